@@ -39,6 +39,7 @@ import (
 	"github.com/org/c8s/pkg/api/handlers"
 	"github.com/org/c8s/pkg/api/middleware"
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/log/broker/redis"
 	"github.com/org/c8s/pkg/storage"
 	"github.com/org/c8s/pkg/storage/s3"
 )
@@ -51,6 +52,15 @@ var (
 	s3Bucket        string
 	s3Region        string
 	s3Endpoint      string
+
+	disableLogCompression bool
+	logRetention          time.Duration
+	archiveBucket         string
+
+	redisAddr string
+
+	auditLogPath string
+	auditPolicy  string
 )
 
 func init() {
@@ -60,6 +70,12 @@ func init() {
 	flag.StringVar(&s3Bucket, "s3-bucket", "", "S3 bucket for logs (env: C8S_S3_BUCKET)")
 	flag.StringVar(&s3Region, "s3-region", "us-west-2", "S3 region (env: C8S_S3_REGION)")
 	flag.StringVar(&s3Endpoint, "s3-endpoint", "", "S3 endpoint for MinIO/compatible storage (env: C8S_S3_ENDPOINT)")
+	flag.BoolVar(&disableLogCompression, "disable-log-compression", false, "Serve stored logs without decompressing them (for debugging)")
+	flag.DurationVar(&logRetention, "log-retention", 0, "Delete the stored logs of PipelineRuns completed longer ago than this (0 keeps logs forever)")
+	flag.StringVar(&archiveBucket, "archive-bucket", "", "S3 bucket logs are copied to before --log-retention deletes them (env: C8S_S3_ARCHIVE_BUCKET)")
+	flag.StringVar(&redisAddr, "redis-addr", "", "Redis address for streaming live logs (env: C8S_REDIS_ADDR)")
+	flag.StringVar(&auditLogPath, "audit-log-path", "", "File to write audit events of mutating requests to, in JSON Lines (disabled if empty)")
+	flag.StringVar(&auditPolicy, "audit-policy", "metadata", "Audit event verbosity: none, metadata, request or requestresponse")
 }
 
 func main() {
@@ -124,6 +140,9 @@ func main() {
 		if s3Endpoint == "" {
 			s3Endpoint = os.Getenv("C8S_S3_ENDPOINT")
 		}
+		if archiveBucket == "" {
+			archiveBucket = os.Getenv("C8S_S3_ARCHIVE_BUCKET")
+		}
 
 		storageConfig := &storage.Config{
 			Bucket:          s3Bucket,
@@ -132,6 +151,7 @@ func main() {
 			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
 			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
 			UsePathStyle:    s3Endpoint != "", // Use path-style for custom endpoints
+			ArchiveBucket:   archiveBucket,
 		}
 
 		storageClient, err = s3.NewClient(storageConfig)
@@ -140,6 +160,11 @@ func main() {
 			os.Exit(1)
 		}
 		logger.Info("S3 storage client initialized", "bucket", s3Bucket)
+
+		if logRetention > 0 {
+			go runLogRetention(ctx, k8sClient, storageClient, storageConfig, logRetention)
+			logger.Info("Log retention enabled", "retention", logRetention, "archiveBucket", archiveBucket)
+		}
 	} else {
 		logger.Info("No S3 bucket configured, log streaming from storage will be disabled")
 	}
@@ -151,6 +176,23 @@ func main() {
 	pipelineConfigHandler := handlers.NewPipelineConfigHandler(k8sClient)
 	pipelineRunHandler := handlers.NewPipelineRunHandler(k8sClient)
 	logsHandler := handlers.NewLogsHandler(clientset, k8sClient, storageClient)
+	logsHandler.SetDisableDecompression(disableLogCompression)
+
+	// Stream live logs through Redis if configured, otherwise from step Pods
+	redisOptions := redis.OptionsFromEnv()
+	if redisAddr != "" {
+		redisOptions.Addr = redisAddr
+	}
+	if redisOptions.Addr != "" {
+		logBroker := redis.NewBroker(redisOptions)
+		defer func() { _ = logBroker.Close() }()
+		if err := logBroker.Ping(ctx); err != nil {
+			logger.Error(err, "Failed to connect to Redis", "address", redisOptions.Addr)
+			os.Exit(1)
+		}
+		logsHandler.SetBroker(logBroker)
+		logger.Info("Redis log broker initialized", "address", redisOptions.Addr)
+	}
 
 	// Register API routes
 	// PipelineConfig endpoints
@@ -160,9 +202,14 @@ func main() {
 	// PipelineRun endpoints
 	mux.HandleFunc("/api/v1/namespaces/{namespace}/pipelineruns", pipelineRunHandler.HandlePipelineRuns)
 	mux.HandleFunc("/api/v1/namespaces/{namespace}/pipelineruns/{name}", pipelineRunHandler.HandlePipelineRun)
+	mux.HandleFunc("/api/v1/namespaces/{namespace}/pipelineruns/{name}/estimate", pipelineRunHandler.HandlePipelineRunEstimate)
 
 	// Logs endpoints
 	mux.HandleFunc("/api/v1/namespaces/{namespace}/pipelineruns/{name}/logs/{step}", logsHandler.HandleStepLogs)
+	mux.HandleFunc("/api/v1/namespaces/{namespace}/pipelineruns/{name}/logs/{step}/stream", logsHandler.HandleStepLogStream)
+
+	// Schema endpoints
+	mux.HandleFunc("/schemas/pipeline.json", handlers.HandlePipelineSchema)
 
 	// Dashboard routes (if enabled)
 	if enableDashboard {
@@ -188,6 +235,21 @@ func main() {
 	if enableCORS {
 		handler = middleware.CORS(handler)
 	}
+	if auditLogPath != "" {
+		policy, err := middleware.ParseAuditPolicy(auditPolicy)
+		if err != nil {
+			logger.Error(err, "Invalid audit policy")
+			os.Exit(1)
+		}
+		auditLogger, err := middleware.NewAuditLogger(auditLogPath)
+		if err != nil {
+			logger.Error(err, "Failed to create audit logger")
+			os.Exit(1)
+		}
+		defer func() { _ = auditLogger.Sync() }()
+		handler = middleware.AuditWithPolicy(handler, auditLogger, policy)
+		logger.Info("Audit logging enabled", "path", auditLogPath, "policy", policy)
+	}
 	handler = middleware.Logging(handler, logger)
 
 	// Create HTTP server
@@ -226,6 +288,43 @@ func main() {
 	logger.Info("API server stopped")
 }
 
+// runLogRetention applies the log retention policy every hour to the logs
+// of the PipelineRuns that completed longer than retention ago
+func runLogRetention(ctx context.Context, k8sClient client.Client, storageClient storage.StorageClient, storageConfig *storage.Config, retention time.Duration) {
+	logger := log.FromContext(ctx).WithName("log-retention")
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		var runs c8sv1alpha1.PipelineRunList
+		if err := k8sClient.List(ctx, &runs); err != nil {
+			logger.Error(err, "Failed to list PipelineRuns")
+		} else {
+			cutoff := time.Now().Add(-retention)
+			var prefixes []string
+			for _, run := range runs.Items {
+				if run.Status.CompletionTime != nil && run.Status.CompletionTime.Time.Before(cutoff) {
+					prefixes = append(prefixes, storage.RunLogPrefix(run.Namespace, run.Name))
+				}
+			}
+
+			result, err := storage.ApplyRetentionPolicy(ctx, storageClient, storageConfig, prefixes)
+			if err != nil {
+				logger.Error(err, "Failed to apply log retention policy")
+			}
+			if result.Deleted > 0 {
+				logger.Info("Applied log retention policy", "archived", result.Archived, "deleted", result.Deleted)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // getKubeConfig creates a Kubernetes client config
 func getKubeConfig(kubeconfigPath string) (*rest.Config, error) {
 	if kubeconfigPath != "" {
@@ -0,0 +1,253 @@
+package dev
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/scheduler"
+	"github.com/spf13/cobra"
+)
+
+// BenchmarkResult contains latency and allocation measurements for one operation
+type BenchmarkResult struct {
+	Operation     string        `json:"operation" yaml:"operation"`
+	Steps         int           `json:"steps" yaml:"steps"`
+	Iterations    int           `json:"iterations" yaml:"iterations"`
+	Median        time.Duration `json:"median" yaml:"median"`
+	P99           time.Duration `json:"p99" yaml:"p99"`
+	Throughput    float64       `json:"throughputPerSecond" yaml:"throughputPerSecond"`
+	BytesPerOp    uint64        `json:"bytesPerOp" yaml:"bytesPerOp"`
+	AllocsPerOp   uint64        `json:"allocsPerOp" yaml:"allocsPerOp"`
+	ErrorMessages []string      `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// newBenchmarkCommand creates the pipeline benchmark subcommand
+func newBenchmarkCommand() *cobra.Command {
+	var (
+		stepCounts []int
+		iterations int
+		maxDeps    int
+		seed       int64
+		output     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Measure scheduler and parser performance with large pipelines",
+		Long: `Benchmark the scheduler and parser against synthetic pipelines.
+
+For each requested size, a pipeline with N steps and a random (acyclic)
+dependency graph is generated and the following operations are measured:
+- scheduler.BuildDAG
+- scheduler.BuildSchedule
+- parser.Parse (YAML rendering of the same pipeline)
+- parser.Validate
+
+Results report median and p99 latency, throughput (configs/second), and
+memory allocated per operation. Use a fixed --seed to compare runs across
+commits when tracking performance regressions.`,
+		Example: `  # Benchmark default sizes
+  c8s dev pipeline benchmark
+
+  # Benchmark specific sizes with more iterations
+  c8s dev pipeline benchmark --steps 100,500,1000 --iterations 50
+
+  # Output as JSON for tracking over time
+  c8s dev pipeline benchmark --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if iterations <= 0 {
+				return fmt.Errorf("--iterations must be greater than 0")
+			}
+
+			var results []BenchmarkResult
+			for _, n := range stepCounts {
+				if n <= 0 {
+					return fmt.Errorf("--steps values must be greater than 0, got %d", n)
+				}
+
+				if IsVerbose() {
+					printInfo("[DEBUG] Benchmarking pipeline with %d steps (%d iterations)", n, iterations)
+				}
+
+				rng := rand.New(rand.NewSource(seed))
+				config := generateBenchmarkConfig(rng, n, maxDeps)
+				pipelineYAML := renderBenchmarkYAML(config)
+
+				results = append(results,
+					runBenchmark("BuildDAG", n, iterations, func() error {
+						_, err := scheduler.BuildDAG(config.Spec.Steps)
+						return err
+					}),
+					runBenchmark("BuildSchedule", n, iterations, func() error {
+						_, err := scheduler.BuildSchedule(config)
+						return err
+					}),
+					runBenchmark("Parse", n, iterations, func() error {
+						_, err := parser.ParseBytes(pipelineYAML)
+						return err
+					}),
+					runBenchmark("Validate", n, iterations, func() error {
+						return parser.Validate(config)
+					}),
+				)
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(map[string]interface{}{"results": results})
+			case "yaml":
+				return formatYAML(map[string]interface{}{"results": results})
+			default:
+				headers := []string{"OPERATION", "STEPS", "MEDIAN", "P99", "CONFIGS/SEC", "BYTES/OP", "ALLOCS/OP"}
+				rows := make([][]string, 0, len(results))
+				for _, r := range results {
+					rows = append(rows, []string{
+						r.Operation,
+						fmt.Sprintf("%d", r.Steps),
+						r.Median.String(),
+						r.P99.String(),
+						fmt.Sprintf("%.1f", r.Throughput),
+						fmt.Sprintf("%d", r.BytesPerOp),
+						fmt.Sprintf("%d", r.AllocsPerOp),
+					})
+				}
+				formatTable(headers, rows)
+
+				for _, r := range results {
+					for _, msg := range r.ErrorMessages {
+						printWarning("%s (%d steps): %s", r.Operation, r.Steps, msg)
+					}
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntSliceVar(&stepCounts, "steps", []int{100, 500, 1000},
+		"Pipeline sizes (number of steps) to benchmark")
+	cmd.Flags().IntVar(&iterations, "iterations", 20,
+		"Number of iterations per operation and size")
+	cmd.Flags().IntVar(&maxDeps, "max-deps", 3,
+		"Maximum number of dependencies per generated step")
+	cmd.Flags().Int64Var(&seed, "seed", 1,
+		"Random seed for dependency graph generation")
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// runBenchmark runs fn the given number of times and collects latency and allocation statistics
+func runBenchmark(operation string, steps, iterations int, fn func() error) BenchmarkResult {
+	result := BenchmarkResult{
+		Operation:  operation,
+		Steps:      steps,
+		Iterations: iterations,
+	}
+
+	durations := make([]time.Duration, 0, iterations)
+	errorSeen := make(map[string]bool)
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		opStart := time.Now()
+		err := fn()
+		durations = append(durations, time.Since(opStart))
+
+		if err != nil && !errorSeen[err.Error()] {
+			errorSeen[err.Error()] = true
+			result.ErrorMessages = append(result.ErrorMessages, err.Error())
+		}
+	}
+	total := time.Since(start)
+
+	runtime.ReadMemStats(&after)
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	result.Median = durations[len(durations)/2]
+	result.P99 = durations[percentileIndex(len(durations), 0.99)]
+	if total > 0 {
+		result.Throughput = float64(iterations) / total.Seconds()
+	}
+	result.BytesPerOp = (after.TotalAlloc - before.TotalAlloc) / uint64(iterations)
+	result.AllocsPerOp = (after.Mallocs - before.Mallocs) / uint64(iterations)
+
+	return result
+}
+
+// percentileIndex returns the index of the given percentile in a sorted slice of length n
+func percentileIndex(n int, percentile float64) int {
+	idx := int(float64(n)*percentile+0.5) - 1
+	if idx < 0 {
+		return 0
+	}
+	if idx >= n {
+		return n - 1
+	}
+	return idx
+}
+
+// generateBenchmarkConfig generates a PipelineConfig with n steps where each
+// step depends on up to maxDeps randomly chosen earlier steps (always acyclic)
+func generateBenchmarkConfig(rng *rand.Rand, n, maxDeps int) *c8sv1alpha1.PipelineConfig {
+	steps := make([]c8sv1alpha1.PipelineStep, n)
+	for i := 0; i < n; i++ {
+		step := c8sv1alpha1.PipelineStep{
+			Name:     fmt.Sprintf("step-%d", i),
+			Image:    "alpine:latest",
+			Commands: []string{fmt.Sprintf("echo step %d", i)},
+		}
+
+		if i > 0 && maxDeps > 0 {
+			deps := make(map[int]bool)
+			for d := rng.Intn(maxDeps + 1); d > 0; d-- {
+				deps[rng.Intn(i)] = true
+			}
+			for dep := range deps {
+				step.DependsOn = append(step.DependsOn, fmt.Sprintf("step-%d", dep))
+			}
+			sort.Strings(step.DependsOn)
+		}
+
+		steps[i] = step
+	}
+
+	config := &c8sv1alpha1.PipelineConfig{
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/example/benchmark.git",
+			Steps:      steps,
+			Timeout:    "1h",
+		},
+	}
+	config.Name = fmt.Sprintf("benchmark-%d", n)
+
+	return config
+}
+
+// renderBenchmarkYAML renders a generated config as pipeline YAML for parser benchmarks
+func renderBenchmarkYAML(config *c8sv1alpha1.PipelineConfig) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "version: v1alpha1\nname: %s\ntimeout: %s\nsteps:\n", config.Name, config.Spec.Timeout)
+	for _, step := range config.Spec.Steps {
+		fmt.Fprintf(&b, "  - name: %s\n    image: %s\n    commands:\n", step.Name, step.Image)
+		for _, c := range step.Commands {
+			fmt.Fprintf(&b, "      - %q\n", c)
+		}
+		if len(step.DependsOn) > 0 {
+			fmt.Fprintf(&b, "    dependsOn: [%s]\n", strings.Join(step.DependsOn, ", "))
+		}
+	}
+	return []byte(b.String())
+}
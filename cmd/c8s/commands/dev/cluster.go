@@ -3,15 +3,26 @@ package dev
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/org/c8s/pkg/localenv"
 	"github.com/org/c8s/pkg/localenv/cluster"
+	"github.com/org/c8s/pkg/localenv/deploy"
+	"github.com/org/c8s/pkg/localenv/health"
+	"github.com/org/c8s/pkg/storage"
+	"github.com/org/c8s/pkg/storage/s3"
+	"github.com/org/c8s/pkg/types"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 // newClusterCommand creates the cluster subcommand
@@ -29,7 +40,19 @@ Create, delete, start, stop, and inspect local k3d clusters.`,
   c8s dev cluster delete my-cluster
 
   # Check cluster status
-  c8s dev cluster status`,
+  c8s dev cluster status
+
+  # Suspend workloads but keep the Kubernetes API running
+  c8s dev cluster pause
+
+  # Reset operator state without recreating the cluster
+  c8s dev cluster reset --force
+
+  # Install an optional addon
+  c8s dev cluster addons install metrics-server
+
+  # Switch the kubeconfig context to another cluster
+  c8s dev cluster context my-cluster`,
 	}
 
 	// Add subcommands
@@ -39,6 +62,30 @@ Create, delete, start, stop, and inspect local k3d clusters.`,
 	cmd.AddCommand(newClusterListCommand())
 	cmd.AddCommand(newClusterStartCommand())
 	cmd.AddCommand(newClusterStopCommand())
+	cmd.AddCommand(newClusterPauseCommand())
+	cmd.AddCommand(newClusterResumeCommand())
+	cmd.AddCommand(newClusterResetCommand())
+	cmd.AddCommand(newClusterSSHCommand())
+	cmd.AddCommand(newClusterInspectCommand())
+	cmd.AddCommand(newClusterLoadImageCommand())
+	cmd.AddCommand(newClusterNetworkCommand())
+	cmd.AddCommand(newClusterExportLogsCommand())
+	cmd.AddCommand(newClusterBackupCommand())
+	cmd.AddCommand(newClusterRestoreCommand())
+	cmd.AddCommand(newClusterBackupImagesCommand())
+	cmd.AddCommand(newClusterRestoreImagesCommand())
+	cmd.AddCommand(newClusterAddonsCommand())
+	cmd.AddCommand(newClusterContextCommand())
+	cmd.AddCommand(newClusterEventsCommand())
+	cmd.AddCommand(newClusterWaitCommand())
+	cmd.AddCommand(newClusterCloneCommand())
+	cmd.AddCommand(newClusterTrustCommand())
+	cmd.AddCommand(newClusterCRDCommand())
+	cmd.AddCommand(newClusterMigrateCommand())
+	cmd.AddCommand(newClusterCopySecretCommand())
+	cmd.AddCommand(newClusterHealthCheckCommand())
+	cmd.AddCommand(newClusterNodeCommand())
+	cmd.AddCommand(newClusterConfigCommand())
 
 	return cmd
 }
@@ -46,14 +93,16 @@ Create, delete, start, stop, and inspect local k3d clusters.`,
 // newClusterCreateCommand creates the cluster create subcommand
 func newClusterCreateCommand() *cobra.Command {
 	var (
-		configPath   string
-		k8sVersion   string
-		servers      int
-		agents       int
-		registry     bool
-		registryPort int
-		timeout      string
-		wait         bool
+		configPath      string
+		k8sVersion      string
+		servers         int
+		agents          int
+		registry        bool
+		registryPort    int
+		timeout         string
+		wait            bool
+		noWait          bool
+		noSwitchContext bool
 	)
 
 	cmd := &cobra.Command{
@@ -106,7 +155,7 @@ and optionally with a local container registry.`,
 			// Create options
 			opts := cluster.CreateOptions{
 				Config:  config,
-				Wait:    wait,
+				Wait:    wait && !noWait,
 				Timeout: timeoutDuration,
 			}
 
@@ -126,12 +175,12 @@ and optionally with a local container registry.`,
 				// Enhance error with suggestions
 				enhancedErr := cluster.EnhanceError(err, "create")
 
-				if cluster.IsClusterAlreadyExistsError(err) {
+				if errors.Is(err, types.ErrClusterAlreadyExists) {
 					printError("Cluster '%s' already exists", config.Name)
 					printInfo("Run 'c8s dev cluster delete %s' to remove it first", config.Name)
 					return exitWithCode(2)
 				}
-				if cluster.IsDockerNotAvailableError(err) {
+				if errors.Is(err, types.ErrDockerNotAvailable) {
 					printError("Docker is not available")
 					printInfo("Please ensure Docker is installed and running")
 					printInfo("Verify with: docker info")
@@ -152,10 +201,25 @@ and optionally with a local container registry.`,
 				printInfo("[DEBUG] Status: %+v", status)
 			}
 
+			// Make the new cluster the current kubeconfig context
+			currentContext := ""
+			if !noSwitchContext {
+				contextName := cluster.KubeContextName(status.Name)
+				if _, err := cluster.UseContext(contextName); err != nil {
+					printWarning("Failed to switch kubeconfig context: %v", err)
+				} else {
+					currentContext = contextName
+				}
+			}
+
 			// Display success message
 			printSuccess("Cluster '%s' created successfully", status.Name)
-			printSuccess("Kubeconfig updated: ~/.kube/config")
-			printSuccess("Current context: k3d-%s", status.Name)
+			printSuccess("Kubeconfig updated: %s", clientcmd.NewDefaultPathOptions().GetDefaultFilename())
+			if currentContext != "" {
+				printSuccess("Current context: %s", currentContext)
+			} else {
+				printInfo("Switch to the cluster with: kubectl config use-context %s", cluster.KubeContextName(status.Name))
+			}
 			if status.RegistryEndpoint != "" {
 				printSuccess("Registry available at: %s", status.RegistryEndpoint)
 			}
@@ -187,6 +251,8 @@ and optionally with a local container registry.`,
 	cmd.Flags().IntVar(&registryPort, "registry-port", 5000, "Registry host port")
 	cmd.Flags().StringVar(&timeout, "timeout", "3m", "Creation timeout")
 	cmd.Flags().BoolVar(&wait, "wait", true, "Wait for cluster to be ready")
+	cmd.Flags().BoolVar(&noWait, "no-wait", false, "Return without waiting for the cluster to be ready (see 'c8s dev cluster wait')")
+	cmd.Flags().BoolVar(&noSwitchContext, "no-switch-context", false, "Keep the current kubeconfig context")
 
 	return cmd
 }
@@ -339,6 +405,14 @@ func padRight(s string, length int) string {
 	return s + strings.Repeat(" ", length-len(s))
 }
 
+// orDash returns s, or "-" for an empty table cell
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 // newClusterDeleteCommand creates the cluster delete subcommand
 func newClusterDeleteCommand() *cobra.Command {
 	var (
@@ -380,10 +454,13 @@ This will remove the cluster, Docker containers, and kubeconfig context.`,
 					return nil
 				}
 
+				deletedContexts := make([]string, 0, len(deleted))
 				for _, name := range deleted {
 					printSuccess("Cluster '%s' deleted successfully", name)
+					deletedContexts = append(deletedContexts, cluster.KubeContextName(name))
 				}
 				printSuccess("Kubeconfig contexts removed")
+				printCurrentContextAfterDelete(deletedContexts...)
 				return nil
 			}
 
@@ -418,7 +495,7 @@ This will remove the cluster, Docker containers, and kubeconfig context.`,
 			if err != nil {
 				enhancedErr := cluster.EnhanceError(err, "delete")
 
-				if cluster.IsClusterNotFoundError(err) {
+				if errors.Is(err, types.ErrClusterNotFound) {
 					printError("Cluster '%s' not found", name)
 					printInfo("Run 'c8s dev cluster list' to see available clusters")
 					return exitWithCode(2)
@@ -433,6 +510,7 @@ This will remove the cluster, Docker containers, and kubeconfig context.`,
 
 			printSuccess("Cluster '%s' deleted successfully", name)
 			printSuccess("Kubeconfig context removed")
+			printCurrentContextAfterDelete(cluster.KubeContextName(name))
 
 			return nil
 		},
@@ -444,6 +522,21 @@ This will remove the cluster, Docker containers, and kubeconfig context.`,
 	return cmd
 }
 
+// printCurrentContextAfterDelete moves the kubeconfig off the contexts of
+// deleted clusters and prints the resulting current context
+func printCurrentContextAfterDelete(deletedContexts ...string) {
+	current, err := cluster.SwitchFromDeletedContexts(deletedContexts...)
+	if err != nil {
+		printWarning("Failed to update kubeconfig context: %v", err)
+		return
+	}
+	if current == "" {
+		printSuccess("Current context cleared (no contexts remain)")
+		return
+	}
+	printSuccess("Current context: %s", current)
+}
+
 // newClusterStatusCommand creates the cluster status subcommand
 func newClusterStatusCommand() *cobra.Command {
 	var (
@@ -487,7 +580,7 @@ Shows cluster state, nodes, API endpoint, and other details.`,
 			if err != nil {
 				enhancedErr := cluster.EnhanceError(err, "status")
 
-				if cluster.IsClusterNotFoundError(err) {
+				if errors.Is(err, types.ErrClusterNotFound) {
 					printError("Cluster '%s' not found", name)
 					printInfo("List available clusters with: c8s dev cluster list")
 					return exitWithCode(2)
@@ -519,6 +612,9 @@ Shows cluster state, nodes, API endpoint, and other details.`,
 				if status.RegistryEndpoint != "" {
 					fmt.Printf("Registry:  %s\n", status.RegistryEndpoint)
 				}
+				if len(status.Addons) > 0 {
+					fmt.Printf("Addons:    %s\n", strings.Join(status.Addons, ", "))
+				}
 
 				if len(status.Nodes) > 0 {
 					fmt.Println("\nNodes:")
@@ -549,8 +645,13 @@ Shows cluster state, nodes, API endpoint, and other details.`,
 // newClusterListCommand creates the cluster list subcommand
 func newClusterListCommand() *cobra.Command {
 	var (
-		output string
-		all    bool
+		output         string
+		all            bool
+		sortBy         string
+		sortDirection  string
+		filterState    string
+		filterVersion  string
+		filterRegistry bool
 	)
 
 	cmd := &cobra.Command{
@@ -558,26 +659,66 @@ func newClusterListCommand() *cobra.Command {
 		Short: "List all local clusters",
 		Long: `List all local Kubernetes clusters.
 
-By default, shows only c8s clusters. Use --all to show all k3d clusters.`,
+By default, shows only c8s clusters. Use --all to show all k3d clusters.
+Clusters are sorted by name; --sort-by orders them by state, uptime or
+node count instead, and --filter-state only shows running or stopped
+clusters (also with --all). --filter-version only shows running clusters
+whose Kubernetes version starts with a prefix such as v1.28, and
+--filter-registry only those with a registry. Filters can be combined.
+
+--output wide adds the registry and API endpoints, the version reported by
+the API server, the kubeconfig context and the disk usage of the cluster's
+docker volumes. JSON and YAML output always include these fields.`,
 		Example: `  # List c8s clusters
   c8s dev cluster list
 
   # List all k3d clusters
   c8s dev cluster list --all
 
+  # Show the longest-running clusters first
+  c8s dev cluster list --sort-by uptime --sort-direction desc
+
+  # Show only stopped clusters
+  c8s dev cluster list --all --filter-state stopped
+
+  # Get the name of the first cluster running Kubernetes 1.28
+  c8s dev cluster list --filter-version v1.28 -o json | jq -r '.clusters[0].name'
+
+  # Show only clusters with a registry
+  c8s dev cluster list --filter-registry
+
+  # Show endpoints, server version, context and disk usage
+  c8s dev cluster list --output wide
+
   # Output as JSON
   c8s dev cluster list --output json`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
+			// Reject invalid flags before the slow k3d and docker calls
+			if !slices.Contains(cluster.ListSortFields, sortBy) {
+				printError("Invalid --sort-by %q (valid: %s)", sortBy, strings.Join(cluster.ListSortFields, ", "))
+				return exitWithCode(1)
+			}
+			if sortDirection != "asc" && sortDirection != "desc" {
+				printError("Invalid --sort-direction %q (valid: asc, desc)", sortDirection)
+				return exitWithCode(1)
+			}
+			if filterState != "" && !slices.Contains(cluster.ListFilterStates, filterState) {
+				printError("Invalid --filter-state %q (valid: %s)", filterState, strings.Join(cluster.ListFilterStates, ", "))
+				return exitWithCode(1)
+			}
+
 			// List clusters
 			if IsVerbose() {
 				printInfo("[DEBUG] Listing clusters (all=%v)", all)
 			}
 
 			clusters, err := cluster.List(ctx, cluster.ListOptions{
-				All: all,
+				All:      all,
+				Details:  output != "text",
+				Registry: filterRegistry,
 			})
 			if err != nil {
 				enhancedErr := cluster.EnhanceError(err, "list")
@@ -589,6 +730,23 @@ By default, shows only c8s clusters. Use --all to show all k3d clusters.`,
 				printInfo("[DEBUG] Found %d clusters", len(clusters))
 			}
 
+			if filterState != "" {
+				if clusters, err = cluster.FilterClustersByState(clusters, filterState); err != nil {
+					printError("%v", err)
+					return exitWithCode(1)
+				}
+			}
+			if filterVersion != "" {
+				clusters = cluster.FilterClustersByVersion(clusters, filterVersion)
+			}
+			if filterRegistry {
+				clusters = cluster.FilterClustersWithRegistry(clusters)
+			}
+			if err := cluster.SortClusters(clusters, sortBy, sortDirection == "desc"); err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+
 			// Format output
 			switch output {
 			case "json":
@@ -609,6 +767,9 @@ By default, shows only c8s clusters. Use --all to show all k3d clusters.`,
 				}
 
 				headers := []string{"NAME", "STATE", "NODES", "VERSION", "UPTIME"}
+				if output == "wide" {
+					headers = append(headers, "REGISTRY-ENDPOINT", "API-ENDPOINT", "K8S-SERVER-VERSION", "CONTEXT-NAME", "DISK-USAGE")
+				}
 				rows := make([][]string, len(clusters))
 				for i, c := range clusters {
 					rows[i] = []string{
@@ -618,6 +779,15 @@ By default, shows only c8s clusters. Use --all to show all k3d clusters.`,
 						c.Version,
 						c.Uptime,
 					}
+					if output == "wide" {
+						rows[i] = append(rows[i],
+							orDash(c.RegistryEndpoint),
+							orDash(c.APIEndpoint),
+							orDash(c.ServerVersion),
+							c.Context,
+							orDash(c.DiskUsage),
+						)
+					}
 				}
 				formatTable(headers, rows)
 			}
@@ -626,8 +796,13 @@ By default, shows only c8s clusters. Use --all to show all k3d clusters.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|wide|json|yaml)")
 	cmd.Flags().BoolVar(&all, "all", false, "Show all k3d clusters (not just c8s clusters)")
+	cmd.Flags().StringVar(&sortBy, "sort-by", "name", "Order clusters by name, state, uptime or nodes")
+	cmd.Flags().StringVar(&sortDirection, "sort-direction", "asc", "Sort direction (asc|desc)")
+	cmd.Flags().StringVar(&filterState, "filter-state", "", "Only show clusters in this state (running|stopped)")
+	cmd.Flags().StringVar(&filterVersion, "filter-version", "", "Only show clusters whose Kubernetes version starts with this prefix (e.g. v1.28)")
+	cmd.Flags().BoolVar(&filterRegistry, "filter-registry", false, "Only show clusters with a registry")
 
 	return cmd
 }
@@ -680,7 +855,7 @@ The cluster state and data will be preserved.`,
 			if err != nil {
 				enhancedErr := cluster.EnhanceError(err, "start")
 
-				if cluster.IsClusterNotFoundError(err) {
+				if errors.Is(err, types.ErrClusterNotFound) {
 					printError("Cluster '%s' not found", name)
 					printInfo("List available clusters with: c8s dev cluster list")
 					return exitWithCode(2)
@@ -745,7 +920,7 @@ The cluster state and data will be preserved and can be restarted later.`,
 			if err != nil {
 				enhancedErr := cluster.EnhanceError(err, "stop")
 
-				if cluster.IsClusterNotFoundError(err) {
+				if errors.Is(err, types.ErrClusterNotFound) {
 					printError("Cluster '%s' not found", name)
 					printInfo("List available clusters with: c8s dev cluster list")
 					return exitWithCode(2)
@@ -766,3 +941,2600 @@ The cluster state and data will be preserved and can be restarted later.`,
 
 	return cmd
 }
+
+// newClusterSSHCommand creates the cluster ssh subcommand
+func newClusterSSHCommand() *cobra.Command {
+	var (
+		clusterName string
+		list        bool
+		command     string
+		shell       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ssh [NODE]",
+		Short: "Open a shell in a cluster node",
+		Long: `Open an interactive shell inside a k3d node container using docker exec.
+
+Useful for inspecting node state, reading kubelet/k3s logs, and debugging
+scheduling issues. Without a node name the first server node is used.
+Nodes can be given by full name (k3d-c8s-dev-agent-0) or short name (agent-0).`,
+		Example: `  # Open a shell in the server node
+  c8s dev cluster ssh
+
+  # Open a shell in an agent node
+  c8s dev cluster ssh agent-0
+
+  # List available nodes
+  c8s dev cluster ssh --list
+
+  # Run a command without an interactive shell
+  c8s dev cluster ssh server-0 --command "crictl ps"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			node := ""
+			if len(args) > 0 {
+				node = args[0]
+			}
+
+			if list {
+				names, err := cluster.ListNodeNames(ctx, clusterName)
+				if err != nil {
+					if errors.Is(err, types.ErrClusterNotFound) {
+						printError("Cluster '%s' not found", clusterName)
+						printInfo("List available clusters with: c8s dev cluster list")
+						return exitWithCode(2)
+					}
+					printError("Failed to list nodes: %v", cluster.EnhanceError(err, "ssh"))
+					return exitWithCode(1)
+				}
+				for _, name := range names {
+					fmt.Println(name)
+				}
+				return nil
+			}
+
+			if IsVerbose() {
+				printInfo("[DEBUG] Opening shell in cluster '%s' node '%s'", clusterName, node)
+			}
+
+			err := cluster.NodeShell(ctx, cluster.NodeShellOptions{
+				Cluster: clusterName,
+				Node:    node,
+				Command: command,
+				Shell:   shell,
+			})
+			if err != nil {
+				// Pass through the exit code of the remote shell or command
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					return exitWithCode(exitErr.ExitCode())
+				}
+
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to open shell: %v", cluster.EnhanceError(err, "ssh"))
+				return exitWithCode(1)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().BoolVar(&list, "list", false, "List available node names")
+	cmd.Flags().StringVar(&command, "command", "", "Run a command non-interactively instead of opening a shell")
+	cmd.Flags().StringVar(&shell, "shell", "/bin/sh", "Shell to run inside the node container")
+
+	return cmd
+}
+
+// newClusterInspectCommand creates the cluster inspect subcommand
+func newClusterInspectCommand() *cobra.Command {
+	var (
+		output       string
+		diff         bool
+		k8sVersion   string
+		servers      int
+		agents       int
+		registry     bool
+		registryPort int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "inspect [NAME]",
+		Short: "Show low-level configuration details of a cluster",
+		Long: `Show low-level configuration details of a local cluster: k3d version,
+node containers and images, the k3d cluster object, docker network and volumes,
+registry configuration, and the kubeconfig context entry.
+
+With --diff, the actual configuration is compared with what
+'c8s dev cluster create' would generate for the same flags. Use this to
+diagnose clusters created with non-default settings.`,
+		Example: `  # Inspect the default cluster
+  c8s dev cluster inspect
+
+  # Output as JSON
+  c8s dev cluster inspect my-test-cluster --output json
+
+  # Compare with the defaults of 'c8s dev cluster create'
+  c8s dev cluster inspect --diff
+
+  # Compare with a cluster created with 3 agents
+  c8s dev cluster inspect --diff --agents 3`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			// Determine cluster name
+			name := "c8s-dev"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			if IsVerbose() {
+				printInfo("[DEBUG] Inspecting cluster: %s", name)
+			}
+
+			result, err := cluster.Inspect(ctx, name)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", name)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to inspect cluster: %v", cluster.EnhanceError(err, "inspect"))
+				return exitWithCode(1)
+			}
+
+			var diffs []cluster.ConfigDifference
+			if diff {
+				expected := buildClusterConfigFromFlags(name, k8sVersion, servers, agents, registry, registryPort)
+				diffs = cluster.DiffClusterConfig(expected, result.Config)
+			}
+
+			switch output {
+			case "json", "yaml":
+				report := struct {
+					*cluster.InspectResult `yaml:",inline"`
+					Differences            []cluster.ConfigDifference `json:"differences,omitempty" yaml:"differences,omitempty"`
+				}{result, diffs}
+				if output == "json" {
+					return formatJSON(report)
+				}
+				return formatYAML(report)
+			}
+
+			fmt.Printf("Cluster:      %s\n", result.Name)
+			fmt.Printf("k3d Version:  %s\n", result.K3dVersion)
+			fmt.Printf("Network:      %s", result.Network.Name)
+			if result.Network.Subnet != "" {
+				fmt.Printf(" (subnet %s, gateway %s)", result.Network.Subnet, result.Network.Gateway)
+			}
+			fmt.Println()
+
+			if len(result.Nodes) > 0 {
+				fmt.Println("\nNodes:")
+				rows := make([][]string, 0, len(result.Nodes))
+				for _, node := range result.Nodes {
+					rows = append(rows, []string{node.Name, node.Role, node.ContainerID, node.Image, node.State})
+				}
+				formatTable([]string{"NAME", "ROLE", "CONTAINER", "IMAGE", "STATE"}, rows)
+			}
+
+			fmt.Println("\nVolumes:")
+			if len(result.Volumes) == 0 {
+				fmt.Println("  <none>")
+			}
+			for _, volume := range result.Volumes {
+				fmt.Printf("  %s\n", volume)
+			}
+
+			fmt.Println("\nRegistry:")
+			if result.Registry != nil {
+				fmt.Printf("  Name:       %s\n", result.Registry.Name)
+				fmt.Printf("  Container:  %s\n", result.Registry.ContainerID)
+				fmt.Printf("  Host Port:  %d\n", result.Registry.HostPort)
+			} else {
+				fmt.Println("  <none>")
+			}
+
+			fmt.Println("\nKubeconfig Context:")
+			if result.Kubeconfig != nil {
+				fmt.Printf("  Name:       %s\n", result.Kubeconfig.Name)
+				fmt.Printf("  Cluster:    %s\n", result.Kubeconfig.Cluster)
+				fmt.Printf("  User:       %s\n", result.Kubeconfig.User)
+				fmt.Printf("  Server:     %s\n", result.Kubeconfig.Server)
+				if result.Kubeconfig.Namespace != "" {
+					fmt.Printf("  Namespace:  %s\n", result.Kubeconfig.Namespace)
+				}
+			} else {
+				fmt.Println("  <not found>")
+			}
+
+			fmt.Println("\nk3d Cluster Config:")
+			k3dConfig, err := yaml.Marshal(result.K3dCluster)
+			if err != nil {
+				return err
+			}
+			for _, line := range strings.Split(strings.TrimRight(string(k3dConfig), "\n"), "\n") {
+				fmt.Printf("  %s\n", line)
+			}
+
+			if diff {
+				fmt.Println()
+				if len(diffs) == 0 {
+					printSuccess("Cluster matches 'c8s dev cluster create' configuration")
+				} else {
+					printWarning("Cluster differs from 'c8s dev cluster create' configuration:")
+					rows := make([][]string, 0, len(diffs))
+					for _, d := range diffs {
+						rows = append(rows, []string{d.Field, d.Expected, d.Actual})
+					}
+					formatTable([]string{"FIELD", "EXPECTED", "ACTUAL"}, rows)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+	cmd.Flags().BoolVar(&diff, "diff", false, "Compare with the configuration 'c8s dev cluster create' would generate")
+	cmd.Flags().StringVar(&k8sVersion, "k8s-version", "v1.28.15", "Kubernetes version to compare against (with --diff)")
+	cmd.Flags().IntVar(&servers, "servers", 1, "Number of server nodes to compare against (with --diff)")
+	cmd.Flags().IntVar(&agents, "agents", 2, "Number of agent nodes to compare against (with --diff)")
+	cmd.Flags().BoolVar(&registry, "registry", true, "Whether a registry is expected (with --diff)")
+	cmd.Flags().IntVar(&registryPort, "registry-port", 5000, "Registry host port to compare against (with --diff)")
+
+	return cmd
+}
+
+// newClusterResetCommand creates the cluster reset subcommand
+func newClusterResetCommand() *cobra.Command {
+	var (
+		force     bool
+		flushLogs bool
+		namespace string
+		timeout   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reset [NAME]",
+		Short: "Reset operator state without recreating the cluster",
+		Long: `Reset the C8S operator to a clean slate without destroying the cluster.
+
+This will:
+- Delete all PipelineRun and PipelineConfig resources
+- Delete all c8s-managed Jobs and Pods (label c8s.dev/managed=true)
+- Delete and recreate the operator Deployment
+- Optionally delete stored logs for the namespace (--flush-logs)
+
+This is much faster than deleting and recreating the cluster. All pipeline
+data is lost, so --force is required to proceed.
+
+Log storage for --flush-logs is configured with C8S_STORAGE_BUCKET,
+C8S_STORAGE_REGION, C8S_STORAGE_ENDPOINT, AWS_ACCESS_KEY_ID and
+AWS_SECRET_ACCESS_KEY.`,
+		Example: `  # Reset the default cluster
+  c8s dev cluster reset --force
+
+  # Reset only the default namespace and empty its stored logs
+  c8s dev cluster reset --force --namespace default --flush-logs`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			// Determine cluster name
+			name := "c8s-dev"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			scope := "all namespaces"
+			if namespace != "" {
+				scope = fmt.Sprintf("namespace '%s'", namespace)
+			}
+
+			if !force {
+				printWarning("This will delete all pipelines, runs, Jobs and Pods in %s of cluster '%s'", scope, name)
+				if flushLogs {
+					printWarning("Stored logs for %s will also be deleted", scope)
+				}
+				printInfo("Re-run with --force to proceed")
+				return exitWithCode(1)
+			}
+
+			timeoutDuration, err := time.ParseDuration(timeout)
+			if err != nil {
+				return fmt.Errorf("invalid timeout: %w", err)
+			}
+
+			opts := cluster.ResetOptions{
+				Name:      name,
+				Namespace: namespace,
+				FlushLogs: flushLogs,
+				Timeout:   timeoutDuration,
+			}
+
+			if flushLogs {
+				storageClient, err := newStorageClientFromEnv()
+				if err != nil {
+					printError("Failed to configure log storage: %v", err)
+					return exitWithCode(1)
+				}
+				opts.Storage = storageClient
+			}
+
+			if IsVerbose() {
+				printInfo("[DEBUG] Resetting cluster: %s (namespace=%q, flush-logs=%v)", name, namespace, flushLogs)
+			}
+
+			printInfo("Resetting cluster '%s'...", name)
+			result, err := cluster.Reset(ctx, opts)
+			if err != nil {
+				enhancedErr := cluster.EnhanceError(err, "reset")
+
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", name)
+					printInfo("Run 'c8s dev cluster list' to see available clusters")
+					return exitWithCode(2)
+				}
+				if cluster.IsTimeoutError(err) {
+					printError("Cluster reset timed out: %v", err)
+					printInfo("Check operator status with: kubectl get pods -n c8s-system")
+					return exitWithCode(3)
+				}
+				printError("Failed to reset cluster: %v", enhancedErr)
+				return exitWithCode(1)
+			}
+
+			printSuccess("Deleted %d PipelineRuns and %d PipelineConfigs", result.PipelineRuns, result.PipelineConfigs)
+			printSuccess("Deleted %d Jobs and %d Pods", result.Jobs, result.Pods)
+			if result.OperatorRecreated {
+				printSuccess("Operator deployment recreated")
+			}
+			if flushLogs {
+				printSuccess("Deleted %d log objects", result.LogObjects)
+			}
+			printSuccess("Cluster '%s' reset successfully", name)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Confirm the reset (required)")
+	cmd.Flags().BoolVar(&flushLogs, "flush-logs", false, "Also delete stored logs for the namespace")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Only reset this namespace (default: all namespaces)")
+	cmd.Flags().StringVar(&timeout, "timeout", "3m", "Timeout for recreating the operator")
+
+	return cmd
+}
+
+// newStorageClientFromEnv creates an S3 storage client from the C8S storage environment variables
+func newStorageClientFromEnv() (storage.StorageClient, error) {
+	endpoint := os.Getenv(types.StorageEndpointEnv)
+	return s3.NewClient(&storage.Config{
+		Bucket:          os.Getenv(types.StorageBucketEnv),
+		Region:          os.Getenv(types.StorageRegionEnv),
+		Endpoint:        endpoint,
+		AccessKeyID:     os.Getenv(types.StorageAccessKeyEnv),
+		SecretAccessKey: os.Getenv(types.StorageSecretKeyEnv),
+		UsePathStyle:    endpoint != "",
+	})
+}
+
+// newClusterLoadImageCommand creates the cluster load-image subcommand
+func newClusterLoadImageCommand() *cobra.Command {
+	var (
+		clusterName string
+		allClusters bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "load-image IMAGE [IMAGE...]",
+		Short: "Import local Docker images into a cluster without a registry",
+		Long: `Copy images from the local Docker daemon into the containerd runtime
+of every node of a k3d cluster using 'k3d image import'.
+
+This is faster than pushing to the local registry and pulling again when
+iterating on large custom images. Pods must use imagePullPolicy IfNotPresent
+or Never to use the imported images.`,
+		Example: `  # Import an image into the default cluster
+  c8s dev cluster load-image my-builder:dev
+
+  # Import several images into a specific cluster
+  c8s dev cluster load-image my-builder:dev my-runner:dev --cluster my-env
+
+  # Import into every running c8s cluster
+  c8s dev cluster load-image my-builder:dev --all-clusters`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			clusters := []string{clusterName}
+			if allClusters {
+				running, err := cluster.RunningClusters(ctx)
+				if err != nil {
+					printError("Failed to list clusters: %v", err)
+					return exitWithCode(1)
+				}
+				if len(running) == 0 {
+					printError("No running c8s clusters found")
+					printInfo("Create one with: c8s dev cluster create")
+					return exitWithCode(2)
+				}
+				clusters = running
+			}
+
+			for _, name := range clusters {
+				printInfo("Importing %s into cluster '%s'...", strings.Join(args, ", "), name)
+
+				result, err := cluster.LoadImage(ctx, cluster.LoadImageOptions{
+					Name:   name,
+					Images: args,
+					OnNode: func(node string) {
+						printInfo("  → %s", node)
+					},
+				})
+				if err != nil {
+					if errors.Is(err, types.ErrClusterNotFound) {
+						printError("Cluster '%s' not found", name)
+						printInfo("List available clusters with: c8s dev cluster list")
+						return exitWithCode(2)
+					}
+					printError("Failed to import images into cluster '%s': %v", name, err)
+					return exitWithCode(1)
+				}
+
+				printSuccess("Imported %d image(s) into %d node(s) of cluster '%s' in %s",
+					len(result.Images), len(result.Nodes), name, result.Duration.Round(time.Second))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().BoolVar(&allClusters, "all-clusters", false, "Import into all running c8s clusters")
+
+	return cmd
+}
+
+// newClusterNetworkCommand creates the cluster network subcommand
+func newClusterNetworkCommand() *cobra.Command {
+	var (
+		clusterName       string
+		image             string
+		testMatrix        bool
+		stepNamespace     string
+		operatorNamespace string
+		gitlabURL         string
+		timeout           time.Duration
+		output            string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Test Pod networking inside a cluster",
+		Long: `Launch temporary test Pods in a cluster and check Pod networking: DNS
+resolution of kube-dns, connectivity to the API server, and connectivity
+between two Pods in different namespaces.
+
+Useful to debug CNI issues after creating a cluster. With --test-matrix the
+c8s-specific paths are also tested: a Pod labeled as a pipeline step to the
+API server, and the webhook namespace to GitLab.
+
+The test Pods and namespaces are deleted afterwards. Exits with code 1 if
+any test fails.`,
+		Example: `  # Test networking of the default cluster
+  c8s dev cluster network
+
+  # Include the c8s-specific tests
+  c8s dev cluster network --cluster my-env --test-matrix`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printInfo("Running network tests in cluster '%s'...", clusterName)
+
+			result, err := cluster.CheckNetwork(context.Background(), cluster.NetworkCheckOptions{
+				ClusterName:       clusterName,
+				Image:             image,
+				TestMatrix:        testMatrix,
+				StepNamespace:     stepNamespace,
+				OperatorNamespace: operatorNamespace,
+				GitLabURL:         gitlabURL,
+				Timeout:           timeout,
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to run network tests: %v", err)
+				return exitWithCode(1)
+			}
+
+			if output == "json" {
+				if err := formatJSON(result); err != nil {
+					return err
+				}
+			} else {
+				for _, test := range result.Tests {
+					if test.Passed {
+						printSuccess("%-22s %s (%s)", test.Name, test.Description, test.Duration.Round(time.Millisecond))
+					} else {
+						printError("%-22s %s: %s", test.Name, test.Description, test.Output)
+					}
+				}
+				printInfo("\n%d passed, %d failed", result.Passed, result.Failed)
+			}
+
+			if result.Failed > 0 {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVar(&image, "image", cluster.DefaultNetworkTestImage, "Image of the test Pods (needs nslookup, curl, nc and socat)")
+	cmd.Flags().BoolVar(&testMatrix, "test-matrix", false, "Also test step Pod to API server and webhook to GitLab connectivity")
+	cmd.Flags().StringVar(&stepNamespace, "step-namespace", "default", "Namespace pipeline steps run in (with --test-matrix)")
+	cmd.Flags().StringVar(&operatorNamespace, "operator-namespace", "c8s-system", "Namespace of the webhook (with --test-matrix)")
+	cmd.Flags().StringVar(&gitlabURL, "gitlab-url", cluster.DefaultGitLabURL, "GitLab URL the webhook must reach (with --test-matrix)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second, "Timeout of each test")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+
+	return cmd
+}
+
+func newClusterExportLogsCommand() *cobra.Command {
+	var (
+		clusterName       string
+		operatorNamespace string
+		outputDir         string
+		since             time.Duration
+		output            string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-logs",
+		Short: "Bundle operator and step logs for a bug report",
+		Long: `Collect everything needed to debug a c8s issue into a tarball:
+
+  - Operator, API server and webhook Pod logs
+  - All PipelineRun and PipelineConfig resources
+  - Pipeline Jobs and the logs of their Pods
+  - Cluster events from the last hour
+
+The values of all Secrets in the cluster are redacted from every file before
+it is written. The bundle is written to ~/.c8s/logs/{timestamp}.tar.gz and
+kept under 10MB by trimming the oldest log lines if needed.`,
+		Example: `  # Export logs of the default cluster
+  c8s dev cluster export-logs
+
+  # Export logs of another cluster, including events of the last 3 hours
+  c8s dev cluster export-logs --cluster my-env --since 3h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printInfo("Exporting logs from cluster '%s'...", clusterName)
+
+			result, err := cluster.ExportLogs(context.Background(), cluster.ExportLogsOptions{
+				ClusterName:       clusterName,
+				OperatorNamespace: operatorNamespace,
+				OutputDir:         outputDir,
+				EventsSince:       since,
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to export logs: %v", err)
+				return exitWithCode(1)
+			}
+
+			if output == "json" {
+				return formatJSON(result)
+			}
+
+			for _, warning := range result.Warnings {
+				printWarning("%s", warning)
+			}
+			if result.Trimmed {
+				printWarning("Logs were trimmed to their most recent lines to keep the bundle under 10MB")
+			}
+			printSuccess("Wrote %d files (%.1f KB) to %s", result.Files, float64(result.Size)/1024, result.Path)
+			printInfo("\nTo report a bug, open an issue at https://github.com/org/c8s/issues/new")
+			printInfo("and drag the bundle into the description to attach it. Secret values")
+			printInfo("have been redacted, but review the bundle before sharing it.")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVar(&operatorNamespace, "operator-namespace", "c8s-system", "Namespace of the operator, API server and webhook")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Directory to write the bundle to (default ~/.c8s/logs)")
+	cmd.Flags().DurationVar(&since, "since", time.Hour, "Export cluster events newer than this")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+
+	return cmd
+}
+
+// newClusterBackupCommand creates the cluster backup-state subcommand
+func newClusterBackupCommand() *cobra.Command {
+	var (
+		clusterName string
+		outputFile  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backup-state",
+		Short: "Save PipelineConfigs and PipelineRuns to a YAML file",
+		Long: `Save all PipelineConfig and PipelineRun resources of a cluster to a
+multi-document YAML file.
+
+Status and server-side metadata (resourceVersion, uid, generation,
+managedFields) are removed so the file can be restored to the same or
+another cluster with 'c8s dev cluster restore-state' or kubectl apply.`,
+		Example: `  # Back up the default cluster to backup.yaml
+  c8s dev cluster backup-state
+
+  # Move pipelines to a recreated cluster
+  c8s dev cluster backup-state --output pipelines.yaml
+  c8s dev cluster delete --force && c8s dev cluster create
+  c8s dev cluster restore-state --input pipelines.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, result, err := cluster.BackupState(context.Background(), clusterName)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to back up cluster state: %v", err)
+				return exitWithCode(1)
+			}
+
+			if err := os.WriteFile(outputFile, data, 0o600); err != nil {
+				printError("Failed to write %s: %v", outputFile, err)
+				return exitWithCode(1)
+			}
+
+			printSuccess("Saved %d PipelineConfigs and %d PipelineRuns to %s",
+				result.PipelineConfigs, result.PipelineRuns, outputFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVar(&outputFile, "output", "backup.yaml", "File to write the backup to")
+
+	return cmd
+}
+
+// newClusterRestoreCommand creates the cluster restore-state subcommand
+func newClusterRestoreCommand() *cobra.Command {
+	var (
+		clusterName string
+		inputFile   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore-state",
+		Short: "Apply PipelineConfigs and PipelineRuns from a backup file",
+		Long: `Apply every resource of a file created by 'c8s dev cluster backup-state'
+to a cluster. PipelineConfigs are applied before PipelineRuns, and existing
+resources are updated to match the backup.
+
+Restored PipelineRuns have no status, so the operator runs them again.`,
+		Example: `  # Restore backup.yaml to the default cluster
+  c8s dev cluster restore-state
+
+  # Restore a backup to another cluster
+  c8s dev cluster restore-state --input pipelines.yaml --cluster my-env`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(inputFile)
+			if err != nil {
+				printError("Failed to read %s: %v", inputFile, err)
+				return exitWithCode(1)
+			}
+
+			result, err := cluster.RestoreState(context.Background(), clusterName, data)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to restore cluster state: %v", err)
+				if result != nil {
+					printInfo("Restored %d PipelineConfigs and %d PipelineRuns before the failure",
+						result.PipelineConfigs, result.PipelineRuns)
+				}
+				return exitWithCode(1)
+			}
+
+			printSuccess("Restored %d PipelineConfigs and %d PipelineRuns to cluster '%s'",
+				result.PipelineConfigs, result.PipelineRuns, clusterName)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVar(&inputFile, "input", "backup.yaml", "Backup file to restore")
+
+	return cmd
+}
+
+// newClusterBackupImagesCommand creates the cluster backup-images subcommand
+func newClusterBackupImagesCommand() *cobra.Command {
+	var (
+		clusterName string
+		outputFile  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backup-images",
+		Short: "Save the images used by a cluster to a tarball",
+		Long: `Save the images of every running Pod of a cluster to a tarball with
+'docker save'. Images missing from the local Docker daemon are pulled first.
+
+Restore the tarball with 'c8s dev cluster restore-images' to run pipelines
+without network access, e.g. after recreating the cluster offline. Run the
+pipelines whose step images should be cached before backing up, since only
+images of running Pods are saved.`,
+		Example: `  # Save the images of the default cluster to backup-images.tar
+  c8s dev cluster backup-images
+
+  # Cache images before going offline and load them into a new cluster
+  c8s dev cluster backup-images --output images.tar
+  c8s dev cluster delete --force && c8s dev cluster create
+  c8s dev cluster restore-images --input images.tar`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printInfo("Saving images of cluster '%s'...", clusterName)
+			result, err := cluster.BackupImages(context.Background(), cluster.BackupImagesOptions{
+				Name:   clusterName,
+				Output: outputFile,
+				OnPull: func(image string) {
+					printInfo("  Pulling %s", image)
+				},
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to back up images: %v", err)
+				return exitWithCode(1)
+			}
+
+			for _, image := range result.Images {
+				printInfo("  %s", image)
+			}
+			printSuccess("Saved %d images (%.1f MB) to %s", len(result.Images), float64(result.Size)/(1024*1024), result.File)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVar(&outputFile, "output", cluster.DefaultImageBackupFile, "File to write the images to")
+
+	return cmd
+}
+
+// newClusterRestoreImagesCommand creates the cluster restore-images subcommand
+func newClusterRestoreImagesCommand() *cobra.Command {
+	var (
+		clusterName string
+		inputFile   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore-images",
+		Short: "Load images saved by backup-images into a cluster",
+		Long: `Load the images of a tarball created by 'c8s dev cluster backup-images'
+into the local Docker daemon with 'docker load', then import them into every
+node of a cluster with 'k3d image import'.
+
+Pods must use imagePullPolicy IfNotPresent or Never to use the imported
+images without network access.`,
+		Example: `  # Load backup-images.tar into the default cluster
+  c8s dev cluster restore-images
+
+  # Load a backup into another cluster
+  c8s dev cluster restore-images --input images.tar --cluster my-env`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printInfo("Loading images from %s into cluster '%s'...", inputFile, clusterName)
+			result, err := cluster.RestoreImages(context.Background(), clusterName, inputFile, func(node string) {
+				printInfo("  → %s", node)
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to restore images: %v", err)
+				return exitWithCode(1)
+			}
+
+			printSuccess("Imported %d images into %d node(s) of cluster '%s'", len(result.Images), len(result.Nodes), clusterName)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVar(&inputFile, "input", cluster.DefaultImageBackupFile, "Tarball to load the images from")
+
+	return cmd
+}
+
+// newClusterTrustCommand creates the cluster trust subcommand
+func newClusterTrustCommand() *cobra.Command {
+	var (
+		untrust    bool
+		namespaces []string
+		output     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "trust [NAME]",
+		Short: "Trust the CA certificate of a cluster on this machine",
+		Long: `Install the CA certificate of a cluster, read from its kubeconfig context,
+as a trusted root in the OS trust store, so HTTPS clients on the host (such
+as the local registry or the webhook calling the API server) verify the
+cluster's certificates without --insecure flags.
+
+On Linux the certificate is copied to
+/usr/local/share/ca-certificates/c8s-<name>.crt and added to /etc/ssl/certs
+with update-ca-certificates. On macOS it is trusted in the System Keychain
+with 'security add-trusted-cert'. Both run through sudo unless c8s runs as
+root.
+
+The Deployments of the c8s namespaces are then restarted so they don't keep
+TLS state from before the change. --untrust removes the certificate again.`,
+		Example: `  # Trust the default cluster
+  c8s dev cluster trust
+
+  # Remove the certificate of a cluster before deleting it
+  c8s dev cluster trust my-env --untrust`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "c8s-dev"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			if IsVerbose() {
+				printInfo("[DEBUG] Trusting CA certificate of cluster: %s (untrust=%v)", name, untrust)
+			}
+
+			result, err := cluster.Trust(context.Background(), cluster.TrustOptions{
+				Name:       name,
+				Untrust:    untrust,
+				Namespaces: namespaces,
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", name)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				if result == nil {
+					printError("Failed to update trust store: %v", err)
+					return exitWithCode(1)
+				}
+				printWarning("Trust store updated, but restarting c8s services failed: %v", err)
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(result)
+			case "yaml":
+				return formatYAML(result)
+			}
+
+			for _, deployment := range result.Restarted {
+				printInfo("Restarted %s", deployment)
+			}
+			if result.Trusted {
+				printSuccess("CA certificate of cluster '%s' trusted in %s (SHA-1 %s)", name, result.Store, result.Fingerprint)
+				printInfo("Remove it with: c8s dev cluster trust %s --untrust", name)
+			} else {
+				printSuccess("CA certificate of cluster '%s' removed from %s", name, result.Store)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&untrust, "untrust", false, "Remove the CA certificate from the trust store")
+	cmd.Flags().StringSliceVar(&namespaces, "namespace", nil, "Namespace whose Deployments are restarted (default c8s-system)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// newClusterNodeCommand creates the cluster node subcommand
+func newClusterNodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Add or remove cluster nodes",
+		Long: `Scale the nodes of a running cluster without recreating it, e.g. to test
+how pipelines are scheduled on more agents or survive the loss of one.
+
+'c8s dev cluster status' reflects the updated node count.`,
+	}
+
+	cmd.AddCommand(newClusterNodeAddCommand())
+	cmd.AddCommand(newClusterNodeRemoveCommand())
+
+	return cmd
+}
+
+// newClusterNodeAddCommand creates the cluster node add subcommand
+func newClusterNodeAddCommand() *cobra.Command {
+	var (
+		nodeType string
+		count    int
+		timeout  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add [NAME]",
+		Short: "Add nodes to a running cluster",
+		Long: `Add agent nodes to a running cluster and wait for them to be ready.
+
+Server nodes can only be added to clusters created with more than one
+server, which run an embedded etcd.`,
+		Example: `  # Add an agent to the default cluster
+  c8s dev cluster node add
+
+  # Add two agents
+  c8s dev cluster node add --type agent --count 2 my-dev`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "c8s-dev"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			if !slices.Contains(cluster.NodeRoles, nodeType) {
+				printError("Invalid --type %q (valid: %s)", nodeType, strings.Join(cluster.NodeRoles, ", "))
+				return exitWithCode(1)
+			}
+
+			printInfo("Adding %d %s node(s) to cluster '%s'...", count, nodeType, name)
+			added, err := cluster.AddNodes(context.Background(), name, cluster.NodeAddOptions{
+				Role:    nodeType,
+				Count:   count,
+				Timeout: timeout,
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", name)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to add nodes: %v", err)
+				return exitWithCode(1)
+			}
+
+			for _, node := range added {
+				printInfo("Added node %s", node)
+			}
+			printSuccess("Cluster '%s' scaled up by %d %s node(s)", name, len(added), nodeType)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&nodeType, "type", cluster.NodeRoleAgent, "Role of the new nodes (agent|server)")
+	cmd.Flags().IntVar(&count, "count", 1, "Number of nodes to add")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Maximum time to wait for the nodes to be ready (default: k3d's)")
+
+	return cmd
+}
+
+// newClusterNodeRemoveCommand creates the cluster node remove subcommand
+func newClusterNodeRemoveCommand() *cobra.Command {
+	var (
+		nodeName     string
+		noDrain      bool
+		drainTimeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "remove [NAME]",
+		Short: "Drain and remove a node from a cluster",
+		Long: `Drain a node with 'kubectl drain', evicting its pods to the other nodes,
+then delete its container and its Node object.
+
+--no-drain skips the drain, to force-remove a node that no longer responds.
+The last server node of a cluster can't be removed.`,
+		Example: `  # Remove an agent of the default cluster
+  c8s dev cluster node remove --name k3d-c8s-dev-agent-0
+
+  # Force-remove an unresponsive node
+  c8s dev cluster node remove my-dev --name k3d-my-dev-agent-1 --no-drain`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "c8s-dev"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			if noDrain {
+				printWarning("Removing node %s without draining it", nodeName)
+			} else {
+				printInfo("Draining node %s...", nodeName)
+			}
+			err := cluster.RemoveNode(context.Background(), name, nodeName, cluster.NodeRemoveOptions{
+				NoDrain:      noDrain,
+				DrainTimeout: drainTimeout,
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", name)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to remove node: %v", err)
+				return exitWithCode(1)
+			}
+
+			printSuccess("Node %s removed from cluster '%s'", nodeName, name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&nodeName, "name", "", "Name of the node to remove (required)")
+	cmd.Flags().BoolVar(&noDrain, "no-drain", false, "Remove the node without draining it first")
+	cmd.Flags().DurationVar(&drainTimeout, "drain-timeout", cluster.DefaultNodeDrainTimeout, "Maximum time to wait for the node's pods to be evicted")
+	_ = cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+// newClusterPauseCommand creates the cluster pause subcommand
+func newClusterPauseCommand() *cobra.Command {
+	var namespaces []string
+
+	cmd := &cobra.Command{
+		Use:   "pause [NAME]",
+		Short: "Suspend c8s workloads while keeping the cluster running",
+		Long: `Scale every Deployment in the c8s namespaces to zero replicas.
+
+Unlike 'c8s dev cluster stop', which stops the cluster containers, pause
+keeps the Kubernetes API available while the operator, API server and
+webhook stop using CPU. The previous replica count of each Deployment is
+saved in the c8s.dev/pre-pause-replicas annotation and restored by
+'c8s dev cluster resume'.`,
+		Example: `  # Pause the default cluster
+  c8s dev cluster pause
+
+  # Also pause the monitoring stack
+  c8s dev cluster pause --namespace c8s-system --namespace monitoring`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "c8s-dev"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			if IsVerbose() {
+				printInfo("[DEBUG] Pausing cluster: %s (namespaces=%v)", name, namespaces)
+			}
+
+			result, err := cluster.Pause(context.Background(), cluster.PauseOptions{
+				Name:       name,
+				Namespaces: namespaces,
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", name)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to pause cluster: %v", err)
+				return exitWithCode(1)
+			}
+
+			if len(result.Deployments) == 0 {
+				printInfo("No running Deployments to pause in cluster '%s'", name)
+				return nil
+			}
+			for _, deployment := range result.Deployments {
+				printInfo("Scaled %s to 0 replicas", deployment)
+			}
+			printSuccess("Cluster '%s' paused", name)
+			printInfo("Resume with: c8s dev cluster resume %s", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&namespaces, "namespace", nil, "Namespace whose Deployments are paused (default c8s-system)")
+
+	return cmd
+}
+
+// newClusterResumeCommand creates the cluster resume subcommand
+func newClusterResumeCommand() *cobra.Command {
+	var namespaces []string
+
+	cmd := &cobra.Command{
+		Use:   "resume [NAME]",
+		Short: "Restore c8s workloads suspended by pause",
+		Long: `Scale the Deployments paused by 'c8s dev cluster pause' back to the
+replica count saved in their c8s.dev/pre-pause-replicas annotation.`,
+		Example: `  # Resume the default cluster
+  c8s dev cluster resume
+
+  # Resume a cluster paused with extra namespaces
+  c8s dev cluster resume --namespace c8s-system --namespace monitoring`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "c8s-dev"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			if IsVerbose() {
+				printInfo("[DEBUG] Resuming cluster: %s (namespaces=%v)", name, namespaces)
+			}
+
+			result, err := cluster.Resume(context.Background(), cluster.PauseOptions{
+				Name:       name,
+				Namespaces: namespaces,
+			})
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", name)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to resume cluster: %v", err)
+				return exitWithCode(1)
+			}
+
+			if len(result.Deployments) == 0 {
+				printInfo("Cluster '%s' is not paused", name)
+				return nil
+			}
+			for _, deployment := range result.Deployments {
+				printInfo("Restored replicas of %s", deployment)
+			}
+			printSuccess("Cluster '%s' resumed", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&namespaces, "namespace", nil, "Namespace whose Deployments are resumed (default c8s-system)")
+
+	return cmd
+}
+
+// newClusterAddonsCommand creates the cluster addons subcommand
+func newClusterAddonsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addons",
+		Short: "List, install and uninstall optional cluster addons",
+		Long: `Manage optional addons of a local cluster: metrics-server, ingress-nginx,
+cert-manager and local-path-provisioner.
+
+Addons are installed from their official release manifests. The installed
+addons and their versions are tracked in the c8s-addons ConfigMap of the
+kube-system namespace.`,
+		Example: `  # Show which addons are installed
+  c8s dev cluster addons list
+
+  # Install metrics-server
+  c8s dev cluster addons install metrics-server
+
+  # Remove ingress-nginx from another cluster
+  c8s dev cluster addons uninstall ingress-nginx --cluster my-env`,
+	}
+
+	cmd.AddCommand(newClusterAddonsListCommand())
+	cmd.AddCommand(newClusterAddonsInstallCommand())
+	cmd.AddCommand(newClusterAddonsUninstallCommand())
+
+	return cmd
+}
+
+// newClusterAddonsListCommand creates the cluster addons list subcommand
+func newClusterAddonsListCommand() *cobra.Command {
+	var (
+		clusterName string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Show available addons and whether they are installed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addons, err := cluster.ListAddons(context.Background(), clusterName)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to list addons: %v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(addons)
+			case "yaml":
+				return formatYAML(addons)
+			default:
+				rows := make([][]string, 0, len(addons))
+				for _, addon := range addons {
+					status := "available"
+					version := addon.Version
+					if addon.Installed {
+						status = "installed"
+						version = addon.InstalledVersion
+					}
+					rows = append(rows, []string{addon.Name, status, version, addon.Description})
+				}
+				formatTable([]string{"NAME", "STATUS", "VERSION", "DESCRIPTION"}, rows)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// newClusterAddonsInstallCommand creates the cluster addons install subcommand
+func newClusterAddonsInstallCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "install NAME",
+		Short: "Install an addon into a cluster",
+		Long: `Apply the official manifest of an addon to a cluster and record it as
+installed. Installing an installed addon applies its manifest again, which
+upgrades it to the version pinned by c8s.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printInfo("Installing addon '%s' into cluster '%s'...", args[0], clusterName)
+
+			addon, err := cluster.InstallAddon(context.Background(), clusterName, args[0])
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to install addon: %v", err)
+				return exitWithCode(1)
+			}
+
+			printSuccess("Installed %s %s", addon.Name, addon.Version)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+
+	return cmd
+}
+
+// newClusterAddonsUninstallCommand creates the cluster addons uninstall subcommand
+func newClusterAddonsUninstallCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "uninstall NAME",
+		Short: "Delete the resources of an installed addon",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printInfo("Uninstalling addon '%s' from cluster '%s'...", args[0], clusterName)
+
+			addon, err := cluster.UninstallAddon(context.Background(), clusterName, args[0])
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to uninstall addon: %v", err)
+				return exitWithCode(1)
+			}
+
+			printSuccess("Uninstalled %s", addon.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+
+	return cmd
+}
+
+// newClusterCRDCommand creates the cluster crd subcommand
+func newClusterCRDCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "crd",
+		Short: "Install, list, validate and upgrade the c8s CRDs",
+		Long: `Manage the c8s CustomResourceDefinitions of a cluster independently of
+the operator deployment.
+
+The CRD manifests are built into the c8s binary, so they can be installed
+or upgraded without a checkout of the repository. 'validate' checks the
+existing PipelineConfigs and PipelineRuns against the installed schemas,
+'diff' shows the schema changes an upgrade would apply, and 'upgrade' runs
+the same check as 'validate' after applying the new schemas.`,
+		Example: `  # Install the CRDs without deploying the operator
+  c8s dev cluster crd install
+
+  # Show the installed CRDs and their schema hashes
+  c8s dev cluster crd list
+
+  # Preview the schema changes of an upgrade
+  c8s dev cluster crd diff
+
+  # Apply the CRDs of this binary and check existing objects against them
+  c8s dev cluster crd upgrade`,
+	}
+
+	cmd.AddCommand(newClusterCRDInstallCommand())
+	cmd.AddCommand(newClusterCRDListCommand())
+	cmd.AddCommand(newClusterCRDValidateCommand())
+	cmd.AddCommand(newClusterCRDDiffCommand())
+	cmd.AddCommand(newClusterCRDUpgradeCommand())
+
+	return cmd
+}
+
+// newClusterCRDInstallCommand creates the cluster crd install subcommand
+func newClusterCRDInstallCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Apply the CRDs built into c8s to a cluster",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printInfo("Installing CRDs into cluster '%s'...", clusterName)
+
+			names, err := cluster.InstallCRDs(context.Background(), clusterName)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to install CRDs: %v", err)
+				return exitWithCode(1)
+			}
+
+			for _, name := range names {
+				printInfo("Applied %s", name)
+			}
+			printSuccess("Installed %d CRDs", len(names))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+
+	return cmd
+}
+
+// newClusterCRDListCommand creates the cluster crd list subcommand
+func newClusterCRDListCommand() *cobra.Command {
+	var (
+		clusterName string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Show the installed c8s CRDs with their versions and schema hashes",
+		Long: `List the CRDs of the c8s.dev group installed in a cluster.
+
+The schema hash changes whenever the OpenAPI schema of a CRD does. A CRD
+whose hash differs from the one built into c8s is marked as outdated.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			crds, err := cluster.ListCRDs(context.Background(), clusterName)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to list CRDs: %v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(crds)
+			case "yaml":
+				return formatYAML(crds)
+			default:
+				if len(crds) == 0 {
+					printInfo("No c8s CRDs installed in cluster '%s'", clusterName)
+					printInfo("Install them with: c8s dev cluster crd install")
+					return nil
+				}
+
+				embedded, err := cluster.EmbeddedCRDs()
+				if err != nil {
+					return err
+				}
+				embeddedHashes := make(map[string]string, len(embedded))
+				for _, crd := range embedded {
+					embeddedHashes[crd.GetName()], _ = cluster.CRDSchemaHash(crd)
+				}
+
+				rows := make([][]string, 0, len(crds))
+				for _, crd := range crds {
+					status := "up to date"
+					if hash, ok := embeddedHashes[crd.Name]; !ok {
+						status = "unknown"
+					} else if hash != crd.SchemaHash {
+						status = "outdated"
+					}
+					rows = append(rows, []string{crd.Name, crd.Kind, strings.Join(crd.Versions, ","), crd.SchemaHash, status})
+				}
+				formatTable([]string{"NAME", "KIND", "VERSIONS", "SCHEMA HASH", "STATUS"}, rows)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// newClusterCRDValidateCommand creates the cluster crd validate subcommand
+func newClusterCRDValidateCommand() *cobra.Command {
+	var (
+		clusterName string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check PipelineConfigs and PipelineRuns against the installed CRD schemas",
+		Long: `Check that every PipelineConfig and PipelineRun of a cluster conforms to
+the schema of the installed CRD for its version.
+
+Objects created before a schema change may no longer conform to it, which
+makes updating them fail. Exits with code 1 if any object is invalid.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := cluster.ValidateCRDs(context.Background(), clusterName)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to validate objects: %v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				if err := formatJSON(result); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(result); err != nil {
+					return err
+				}
+			default:
+				displayCRDValidation(result)
+			}
+
+			if !result.Valid() {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// newClusterCRDUpgradeCommand creates the cluster crd upgrade subcommand
+func newClusterCRDUpgradeCommand() *cobra.Command {
+	var (
+		clusterName string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Apply the CRDs built into c8s and validate existing objects",
+		Long: `Apply the CRD schemas built into c8s to a cluster, then check the
+existing PipelineConfigs and PipelineRuns against them like 'validate'.
+
+The operator is not redeployed. Exits with code 1 if any object does not
+conform to the new schemas.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "text" {
+				printInfo("Upgrading CRDs of cluster '%s'...", clusterName)
+			}
+
+			result, err := cluster.UpgradeCRDs(context.Background(), clusterName)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to upgrade CRDs: %v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				if err := formatJSON(result); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(result); err != nil {
+					return err
+				}
+			default:
+				rows := make([][]string, 0, len(result.CRDs))
+				for _, crd := range result.CRDs {
+					change := "unchanged"
+					if crd.PreviousHash == "" {
+						change = "installed"
+					} else if crd.Changed() {
+						change = "upgraded"
+					}
+					rows = append(rows, []string{crd.Name, orDash(crd.PreviousHash), crd.SchemaHash, change})
+				}
+				formatTable([]string{"NAME", "PREVIOUS HASH", "SCHEMA HASH", "CHANGE"}, rows)
+				fmt.Println()
+				displayCRDValidation(result.Validation)
+			}
+
+			if !result.Validation.Valid() {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// newClusterCRDDiffCommand creates the cluster crd diff subcommand
+func newClusterCRDDiffCommand() *cobra.Command {
+	var (
+		clusterName string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the schema changes between the installed and bundled CRDs",
+		Long: `Compare the OpenAPI schema of each CRD built into c8s with the one
+installed in a cluster, field by field, and show the fields added, removed
+or changed (type, required-ness, enum, format, pattern and bounds).
+
+Breaking changes, which existing objects may no longer conform to, are
+shown in red: removed fields and versions, type changes, newly required
+fields and tighter constraints. Run it before 'c8s dev cluster crd upgrade'
+to see what an upgrade applies; 'validate' after the upgrade reports the
+objects actually affected.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diffs, err := cluster.DiffCRDs(context.Background(), clusterName)
+			if err != nil {
+				if errors.Is(err, types.ErrClusterNotFound) {
+					printError("Cluster '%s' not found", clusterName)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to diff CRDs: %v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(diffs)
+			case "yaml":
+				return formatYAML(diffs)
+			default:
+				displayCRDDiffs(diffs)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// displayCRDDiffs prints the schema changes of each CRD, breaking ones in red
+func displayCRDDiffs(diffs []cluster.CRDDiff) {
+	breaking := 0
+	for _, diff := range diffs {
+		switch {
+		case !diff.Installed:
+			printInfo("%s: not installed, an upgrade installs it", diff.Name)
+			continue
+		case len(diff.Changes) == 0:
+			printSuccess("%s: no schema changes", diff.Name)
+			continue
+		}
+
+		printInfo("%s:", diff.Name)
+		for _, change := range diff.Changes {
+			mark, color := "~", "\033[33m"
+			switch change.Change {
+			case cluster.SchemaFieldAdded:
+				mark, color = "+", "\033[32m"
+			case cluster.SchemaFieldRemoved:
+				mark = "-"
+			}
+			if change.Breaking {
+				color = "\033[31m"
+				breaking++
+			}
+
+			line := fmt.Sprintf("  %s %s %s", mark, change.Version, orDash(change.Path))
+			if change.Detail != "" {
+				line += " (" + change.Detail + ")"
+			}
+			if change.Breaking {
+				line += " [breaking]"
+			}
+			if IsColorDisabled() {
+				fmt.Println(line)
+			} else {
+				fmt.Printf("%s%s\033[0m\n", color, line)
+			}
+		}
+	}
+
+	if breaking > 0 {
+		fmt.Println()
+		printWarning("%d breaking changes: check existing objects with 'c8s dev cluster crd validate' after upgrading", breaking)
+	}
+}
+
+// displayCRDValidation prints the objects that do not conform to their CRD schema
+func displayCRDValidation(result *cluster.CRDValidationResult) {
+	if result.Valid() {
+		printSuccess("All %d objects conform to the installed CRD schemas", result.Checked)
+		return
+	}
+
+	for _, violation := range result.Violations {
+		printError("%s %s/%s:", violation.Kind, violation.Namespace, violation.Name)
+		for _, message := range violation.Errors {
+			fmt.Printf("    %s\n", message)
+		}
+	}
+	printWarning("%d of %d objects do not conform to the installed CRD schemas", len(result.Violations), result.Checked)
+}
+
+// newClusterContextCommand creates the cluster context subcommand
+func newClusterContextCommand() *cobra.Command {
+	var (
+		previous bool
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "context [NAME]",
+		Short: "Switch the kubeconfig context between clusters",
+		Long: `Switch the current context of the kubeconfig ($KUBECONFIG, or
+~/.kube/config) to the context of a local cluster (k3d-NAME).
+
+Without a name, list the contexts of all local clusters with the current
+one marked. With --previous, switch back to the context that was current
+before the last switch, as recorded in ~/.c8s/config.yaml.`,
+		Example: `  # List cluster contexts
+  c8s dev cluster context
+
+  # Switch to a cluster
+  c8s dev cluster context my-cluster
+
+  # Switch back to the previous context
+  c8s dev cluster context --previous`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if previous {
+				if len(args) > 0 {
+					printError("--previous can't be combined with a cluster name")
+					return exitWithCode(1)
+				}
+				current, err := cluster.UsePreviousContext()
+				if err != nil {
+					printError("Failed to switch to the previous context: %v", err)
+					return exitWithCode(1)
+				}
+				printSuccess("Current context: %s", current)
+				return nil
+			}
+
+			if len(args) > 0 {
+				contextName := cluster.KubeContextName(args[0])
+				if _, err := cluster.UseContext(contextName); err != nil {
+					printError("Failed to switch context: %v", err)
+					printInfo("Run 'c8s dev cluster context' to see available cluster contexts")
+					return exitWithCode(2)
+				}
+				printSuccess("Current context: %s", contextName)
+				return nil
+			}
+
+			contexts, err := cluster.ListClusterContexts()
+			if err != nil {
+				printError("Failed to list contexts: %v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(map[string]interface{}{"contexts": contexts})
+			case "yaml":
+				return formatYAML(map[string]interface{}{"contexts": contexts})
+			default:
+				if len(contexts) == 0 {
+					printInfo("No cluster contexts found")
+					return nil
+				}
+
+				headers := []string{"CURRENT", "NAME", "CLUSTER"}
+				rows := make([][]string, len(contexts))
+				for i, c := range contexts {
+					current := ""
+					if c.Current {
+						current = "*"
+					}
+					rows[i] = []string{current, c.Name, c.Cluster}
+				}
+				formatTable(headers, rows)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&previous, "previous", false, "Switch to the previously active context")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// eventColumnWidths are the widths of the streamed events table columns,
+// which can't be sized from rows that haven't arrived yet
+var eventColumnWidths = []int{10, 8, 16, 40, 20}
+
+// newClusterEventsCommand creates the cluster events subcommand
+func newClusterEventsCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+		eventType   string
+		run         string
+		since       time.Duration
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Stream Kubernetes events of c8s objects",
+		Long: `Stream the Kubernetes events of c8s objects (PipelineRuns,
+PipelineConfigs and other c8s.dev resources), like 'kubectl get events -w'.
+
+Events from the last hour are shown first, then new events as they happen.
+With --run, events of a PipelineRun and of its Jobs and Pods are shown.
+Warning events are shown in yellow.`,
+		Example: `  # Stream events of all c8s objects
+  c8s dev cluster events
+
+  # Only show warnings
+  c8s dev cluster events --type Warning
+
+  # Follow a single run, including its Jobs and Pods
+  c8s dev cluster events --run my-pipeline-abc123 --namespace ci`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if eventType != "" && eventType != corev1.EventTypeNormal && eventType != corev1.EventTypeWarning {
+				printError("Invalid --type %q: must be Normal or Warning", eventType)
+				return exitWithCode(1)
+			}
+			if run != "" && namespace == "" {
+				namespace = "default"
+			}
+
+			client, err := deploy.NewClusterClientset(clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", clusterName, err)
+				return exitWithCode(1)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			if output != "json" {
+				printEventRow([]string{"LAST SEEN", "TYPE", "NAMESPACE", "OBJECT", "REASON", "MESSAGE"}, "")
+			}
+
+			err = cluster.WatchEvents(ctx, client, cluster.EventsOptions{
+				Namespace: namespace,
+				Type:      eventType,
+				Run:       run,
+				Since:     since,
+			}, func(event cluster.ClusterEvent) {
+				if output == "json" {
+					if data, err := json.Marshal(event); err == nil {
+						fmt.Println(string(data))
+					}
+					return
+				}
+				printEventRow([]string{
+					event.LastSeen.Local().Format("15:04:05"),
+					event.Type,
+					event.Namespace,
+					event.Kind + "/" + event.Name,
+					event.Reason,
+					event.Message,
+				}, event.Type)
+			})
+			if err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to watch (default: all namespaces, or default with --run)")
+	cmd.Flags().StringVar(&eventType, "type", "", "Only show events of this type (Normal|Warning)")
+	cmd.Flags().StringVar(&run, "run", "", "Only show events of this PipelineRun and its Jobs and Pods")
+	cmd.Flags().DurationVar(&since, "since", cluster.DefaultEventsSince, "Age of the oldest event shown on start")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+
+	return cmd
+}
+
+// printEventRow prints a row of the events table, coloured by event type
+func printEventRow(cells []string, eventType string) {
+	parts := make([]string, len(cells))
+	for i, cell := range cells {
+		if i < len(eventColumnWidths) {
+			parts[i] = padRight(cell, eventColumnWidths[i])
+		} else {
+			parts[i] = cell
+		}
+	}
+	line := strings.Join(parts, "   ")
+
+	switch {
+	case IsColorDisabled() || eventType == "":
+		fmt.Println(line)
+	case eventType == corev1.EventTypeWarning:
+		fmt.Printf("\033[33m%s\033[0m\n", line)
+	default:
+		fmt.Printf("\033[37m%s\033[0m\n", line)
+	}
+}
+
+// newClusterWaitCommand creates the cluster wait subcommand
+func newClusterWaitCommand() *cobra.Command {
+	var (
+		timeout    time.Duration
+		conditions []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait [NAME]",
+		Short: "Wait until a local cluster is ready",
+		Long: `Block until a local cluster reports the running state, checking every
+5 seconds, for use in scripts after 'c8s dev cluster create --no-wait'.
+
+With --condition, the running cluster must also pass each condition:
+  api-server-healthy  the API server answers GET /healthz
+  crds-installed      the c8s CRDs are installed
+
+Exits with code 0 once the cluster is ready, or 1 when the timeout expires.`,
+		Example: `  # Create a cluster in the background of other setup, then wait for it
+  c8s dev cluster create --no-wait && c8s dev cluster wait && c8s dev deploy operator
+
+  # Wait for the API server to be healthy, up to 5 minutes
+  c8s dev cluster wait my-test-cluster --condition api-server-healthy --timeout 5m
+
+  # Wait until the operator CRDs are installed
+  c8s dev cluster wait --condition crds-installed`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "c8s-dev"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			opts := cluster.WaitOptions{Name: name, Timeout: timeout}
+			for _, c := range conditions {
+				condition, err := cluster.ParseWaitCondition(c)
+				if err != nil {
+					printError("%v", err)
+					return exitWithCode(1)
+				}
+				opts.Conditions = append(opts.Conditions, condition)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			printInfo("Waiting for cluster '%s' to be ready (timeout %s)...", name, timeout)
+			if err := cluster.Wait(ctx, opts); err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+
+			printSuccess("Cluster '%s' is ready", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 3*time.Minute, "Maximum time to wait")
+	cmd.Flags().StringSliceVar(&conditions, "condition", nil, "Additional condition to wait for (api-server-healthy|crds-installed), repeatable")
+
+	return cmd
+}
+
+// newClusterCloneCommand creates the cluster clone subcommand
+func newClusterCloneCommand() *cobra.Command {
+	var (
+		withState bool
+		timeout   time.Duration
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "clone SOURCE TARGET",
+		Short: "Create a new cluster with the configuration of an existing one",
+		Long: `Create a new local cluster with the same configuration as an existing one:
+node counts, Kubernetes version, registry, port mappings, volume mounts and
+k3s arguments.
+
+The registry of the clone is named after it, and host ports already in use
+(by the source cluster, for instance) are moved to the next free port.
+
+Without --with-state, the clone is a fresh cluster. With --with-state, the
+c8s CRDs, PipelineConfigs and PipelineRuns of the source are copied to it;
+deploy the operator to the clone to run the copied PipelineRuns.`,
+		Example: `  # Duplicate the default dev cluster infrastructure
+  c8s dev cluster clone c8s-dev c8s-dev-2
+
+  # Also copy the deployed pipelines and runs
+  c8s dev cluster clone c8s-dev experiment --with-state`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			source, target := args[0], args[1]
+
+			printInfo("Cloning cluster '%s' to '%s'...", source, target)
+			result, err := cluster.Clone(ctx, cluster.CloneOptions{
+				Source:    source,
+				Target:    target,
+				Timeout:   timeout,
+				WithState: withState,
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, types.ErrClusterNotFound):
+					printError("Cluster '%s' not found", source)
+					return exitWithCode(2)
+				case errors.Is(err, types.ErrClusterAlreadyExists):
+					printError("Cluster '%s' already exists", target)
+					return exitWithCode(2)
+				case errors.Is(err, types.ErrDockerNotAvailable):
+					printError("Docker is not available")
+					return exitWithCode(4)
+				}
+				printError("Failed to clone cluster: %v", cluster.EnhanceError(err, "create"))
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(result)
+			case "yaml":
+				return formatYAML(result)
+			}
+
+			printSuccess("Cluster '%s' created from '%s'", target, source)
+			fmt.Printf("  Nodes:       %d\n", len(result.Status.Nodes))
+			if result.Config.Registry != nil {
+				fmt.Printf("  Registry:    k3d-%s:%d\n", result.Config.Registry.Name, result.Config.Registry.HostPort)
+			}
+			for _, port := range result.Config.Ports {
+				fmt.Printf("  Port:        %d -> %d@%s\n", port.HostPort, port.ContainerPort, port.NodeFilter)
+			}
+			for _, mount := range result.Config.VolumeMounts {
+				fmt.Printf("  Volume:      %s:%s@%s\n", mount.HostPath, mount.ContainerPath, mount.NodeFilter)
+			}
+			if result.State != nil {
+				printSuccess("Copied %d PipelineConfigs and %d PipelineRuns", result.State.PipelineConfigs, result.State.PipelineRuns)
+			}
+			printInfo("Switch to the cluster with: c8s dev cluster context %s", target)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&withState, "with-state", false, "Copy the c8s CRDs, PipelineConfigs and PipelineRuns to the clone")
+	cmd.Flags().DurationVar(&timeout, "timeout", 3*time.Minute, "Creation timeout")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// newClusterMigrateCommand creates the cluster migrate subcommand
+func newClusterMigrateCommand() *cobra.Command {
+	var (
+		clusterName   string
+		fromNamespace string
+		toNamespace   string
+		toCluster     string
+		includeRuns   bool
+		dryRun        bool
+		output        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy PipelineConfigs to another namespace or cluster",
+		Long: `Copy the PipelineConfigs of a namespace to another namespace of the same
+cluster (--to-namespace) or to another cluster (--to-cluster), whose k3d
+kubeconfig context is used for the target. Status and server-side metadata
+are removed, existing objects in the target are updated, and the target
+namespace is created if needed. The source objects are left in place.
+
+With --include-runs, the finished PipelineRuns of the namespace are copied
+too, keeping their status. They are annotated as imported so the operator
+of the target doesn't run them again; runs still in progress are skipped.
+
+With --dry-run, the objects that would be created are listed and nothing
+is changed.`,
+		Example: `  # Move pipelines to a new namespace
+  c8s dev cluster migrate --from-namespace old --to-namespace new
+
+  # Copy pipelines and their history to another cluster
+  c8s dev cluster migrate --from-namespace ci --to-cluster staging --include-runs
+
+  # Preview the migration
+  c8s dev cluster migrate --from-namespace old --to-namespace new --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if toNamespace == "" && toCluster == "" {
+				printError("Either --to-namespace or --to-cluster is required")
+				return exitWithCode(1)
+			}
+
+			result, err := cluster.Migrate(context.Background(), cluster.MigrateOptions{
+				Cluster:       clusterName,
+				FromNamespace: fromNamespace,
+				ToCluster:     toCluster,
+				ToNamespace:   toNamespace,
+				IncludeRuns:   includeRuns,
+				DryRun:        dryRun,
+			})
+			if err != nil {
+				var notFound *cluster.ClusterNotFoundError
+				if errors.As(err, &notFound) {
+					printError("Cluster '%s' not found", notFound.Name)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to migrate: %v", err)
+				if result != nil {
+					printInfo("Migrated %d objects before the failure", len(result.Objects))
+				}
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(result)
+			case "yaml":
+				return formatYAML(result)
+			}
+
+			displayMigrateResult(result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the source cluster")
+	cmd.Flags().StringVar(&fromNamespace, "from-namespace", "default", "Namespace to copy PipelineConfigs from")
+	cmd.Flags().StringVar(&toNamespace, "to-namespace", "", "Namespace to copy PipelineConfigs to (default: --from-namespace)")
+	cmd.Flags().StringVar(&toCluster, "to-cluster", "", "Cluster to copy PipelineConfigs to (default: --cluster)")
+	cmd.Flags().BoolVar(&includeRuns, "include-runs", false, "Also copy the finished PipelineRuns of the namespace")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the objects that would be created without creating them")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// displayMigrateResult prints the objects of a migration
+func displayMigrateResult(result *cluster.MigrateResult) {
+	target := fmt.Sprintf("%s/%s", result.ToCluster, result.ToNamespace)
+	if len(result.Objects) == 0 {
+		printInfo("No PipelineConfigs found in %s/%s", result.FromCluster, result.FromNamespace)
+		return
+	}
+
+	copied := 0
+	for _, object := range result.Objects {
+		if object.Action == cluster.MigrateSkipped {
+			printWarning("%s %s %s: %s", object.Kind, object.Name, object.Action, object.Reason)
+			continue
+		}
+		copied++
+		fmt.Printf("  %s %s %s\n", object.Kind, object.Name, object.Action)
+	}
+
+	if result.DryRun {
+		printInfo("Dry run: %d objects would be created in %s", copied, target)
+		return
+	}
+	printSuccess("Migrated %d objects from %s/%s to %s", copied, result.FromCluster, result.FromNamespace, target)
+}
+
+// newClusterCopySecretCommand creates the cluster copy-secret subcommand
+func newClusterCopySecretCommand() *cobra.Command {
+	var (
+		clusterName   string
+		fromNamespace string
+		toNamespace   string
+		watch         bool
+		output        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "copy-secret SECRET",
+		Short: "Copy a Secret to the namespace pipelines run in",
+		Long: `Copy a Secret to another namespace of a cluster, for pipelines running in
+per-project namespaces that use credentials kept in a shared namespace.
+Server-side metadata (resourceVersion, uid) and owner references are
+removed, and the copy is annotated with c8s.dev/copied-from. The target
+namespace is created if needed.
+
+A Secret of the same name in the target namespace that was not copied from
+the source is replaced, with a warning.
+
+With --watch, the Secret is copied again every time it changes in the
+source namespace, propagating credential rotations, until interrupted.
+Deleting the source Secret leaves its copy in place.`,
+		Example: `  # Share registry credentials with the ci namespace
+  c8s dev cluster copy-secret registry-creds --to-namespace ci
+
+  # Keep a rotated token in sync
+  c8s dev cluster copy-secret deploy-token --from-namespace shared --to-namespace web --watch`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if toNamespace == "" {
+				printError("--to-namespace is required")
+				return exitWithCode(1)
+			}
+			opts := cluster.CopySecretOptions{
+				Cluster:       clusterName,
+				Name:          args[0],
+				FromNamespace: fromNamespace,
+				ToNamespace:   toNamespace,
+			}
+
+			result, err := cluster.CopySecret(context.Background(), opts)
+			if err != nil {
+				var notFound *cluster.ClusterNotFoundError
+				if errors.As(err, &notFound) {
+					printError("Cluster '%s' not found", notFound.Name)
+					printInfo("List available clusters with: c8s dev cluster list")
+					return exitWithCode(2)
+				}
+				printError("Failed to copy secret: %v", err)
+				return exitWithCode(1)
+			}
+
+			if !watch {
+				switch output {
+				case "json":
+					return formatJSON(result)
+				case "yaml":
+					return formatYAML(result)
+				}
+			}
+			displayCopySecretResult(result)
+			if !watch {
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			printInfo("Watching secret %s/%s for changes (Ctrl+C to stop)...", fromNamespace, args[0])
+			err = cluster.WatchSecret(ctx, opts, func(result *cluster.CopySecretResult, err error) {
+				if err != nil {
+					printError("Failed to copy secret: %v", err)
+					return
+				}
+				if result.Action != cluster.SecretUnchanged {
+					displayCopySecretResult(result)
+				}
+			})
+			if err != nil {
+				printError("Failed to watch secret: %v", err)
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVar(&fromNamespace, "from-namespace", "default", "Namespace of the Secret")
+	cmd.Flags().StringVar(&toNamespace, "to-namespace", "", "Namespace to copy the Secret to")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Copy the Secret again whenever it changes in the source namespace")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml), ignored with --watch")
+
+	return cmd
+}
+
+// displayCopySecretResult prints a Secret copy
+func displayCopySecretResult(result *cluster.CopySecretResult) {
+	source := fmt.Sprintf("%s/%s", result.FromNamespace, result.Name)
+	target := fmt.Sprintf("%s/%s", result.ToNamespace, result.Name)
+	if result.Conflict {
+		printWarning("Secret %s already existed and was not copied from %s; it was replaced", target, source)
+	}
+
+	switch result.Action {
+	case cluster.SecretSourceDeleted:
+		printWarning("Secret %s was deleted; %s is left in place", source, target)
+	case cluster.SecretUnchanged:
+		printInfo("Secret %s is up to date with %s", target, source)
+	default:
+		printSuccess("Secret %s %s from %s", target, result.Action, source)
+	}
+}
+
+// newClusterHealthCheckCommand creates the cluster health-check subcommand
+func newClusterHealthCheckCommand() *cobra.Command {
+	var (
+		clusterName    string
+		operator       operatorFlags
+		continuous     bool
+		interval       time.Duration
+		alertThreshold int
+		onFailure      string
+		selfHeal       bool
+		output         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "health-check",
+		Short: "Check the health of a cluster, once or continuously",
+		Long: `Check the API server responsiveness, the operator Deployment, the
+metrics server and the node conditions of a cluster.
+
+With --continuous, the checks run every --interval and each round is printed
+as a color-coded log line per check until interrupted. After
+--alert-threshold consecutive failed rounds, and again every
+--alert-threshold failed rounds, the --on-failure command is run with
+"sh -c", with the failed checks in C8S_HEALTH_FAILED_CHECKS (comma
+separated), the failed rounds in C8S_HEALTH_CONSECUTIVE_FAILURES and the
+cluster in C8S_CLUSTER.
+
+With --self-heal, the first failed round after a healthy one triggers an
+automatic remediation: a failed operator check restarts the operator, like
+'c8s dev operator restart'. Other checks have no remediation.
+
+Without --continuous, exits with code 1 if a check fails.`,
+		Example: `  # Check a cluster once
+  c8s dev cluster health-check
+
+  # Monitor a cluster, notifying after 3 failed rounds
+  c8s dev cluster health-check --continuous --interval 30s --alert-threshold 3 \
+    --on-failure 'notify-send "c8s: $C8S_HEALTH_FAILED_CHECKS failing"'
+
+  # Restart the operator automatically when it fails
+  c8s dev cluster health-check --continuous --self-heal`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			if _, err := cluster.NewK3dClient().Get(ctx, clusterName); err != nil {
+				printError("Cluster '%s' not found", clusterName)
+				printInfo("List available clusters with: c8s dev cluster list")
+				return exitWithCode(2)
+			}
+
+			checker := health.NewChecker()
+			check := func(ctx context.Context) []health.CheckResult {
+				client, err := deploy.NewClusterClientset(clusterName)
+				if err != nil {
+					return []health.CheckResult{{Name: "API server", Message: fmt.Sprintf("Cannot connect to cluster: %v", err)}}
+				}
+				return []health.CheckResult{
+					checker.CheckAPIServer(ctx, client),
+					operatorHealthCheck(ctx, clusterName, operator.namespace, operator.name),
+					checker.CheckMetricsServer(ctx, client),
+					checker.CheckNodeConditions(ctx, client),
+				}
+			}
+
+			if !continuous {
+				round := &health.MonitorRound{Number: 1, Time: time.Now(), Checks: check(ctx)}
+				round.Healthy = len(round.Failed()) == 0
+				switch output {
+				case "json":
+					if err := formatJSON(round); err != nil {
+						return err
+					}
+				case "yaml":
+					if err := formatYAML(round); err != nil {
+						return err
+					}
+				default:
+					displayHealthRound(round)
+				}
+				if !round.Healthy {
+					return exitWithCode(1)
+				}
+				return nil
+			}
+
+			opts := health.MonitorOptions{
+				Interval:       interval,
+				AlertThreshold: alertThreshold,
+				Check:          check,
+				OnRound:        displayHealthRound,
+			}
+			if onFailure != "" {
+				opts.OnAlert = health.AlertCommand(onFailure, map[string]string{"C8S_CLUSTER": clusterName})
+			}
+			if selfHeal {
+				opts.Remediate = func(ctx context.Context, failed []health.CheckResult) ([]string, error) {
+					return remediateHealthChecks(ctx, clusterName, operator, failed)
+				}
+			}
+
+			printInfo("Checking cluster '%s' every %s (Ctrl+C to stop)...", clusterName, interval)
+			if err := health.Monitor(ctx, opts); err != nil {
+				printError("Failed to monitor cluster: %v", err)
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVar(&operator.namespace, "operator-namespace", deploy.DefaultOperatorNamespace, "Namespace of the operator")
+	cmd.Flags().StringVar(&operator.name, "operator-deployment", deploy.DefaultOperatorName, "Name of the operator Deployment")
+	cmd.Flags().BoolVar(&continuous, "continuous", false, "Run the checks every --interval until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "Time between two rounds of checks with --continuous")
+	cmd.Flags().IntVar(&alertThreshold, "alert-threshold", 3, "Consecutive failed rounds before running --on-failure")
+	cmd.Flags().StringVar(&onFailure, "on-failure", "", "Command to run when --alert-threshold is reached")
+	cmd.Flags().BoolVar(&selfHeal, "self-heal", false, "Attempt automatic remediation of failed checks")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml), ignored with --continuous")
+
+	return cmd
+}
+
+// remediateHealthChecks restarts the operator when its check failed
+func remediateHealthChecks(ctx context.Context, clusterName string, operator operatorFlags, failed []health.CheckResult) ([]string, error) {
+	for _, check := range failed {
+		if check.Name != "Operator" {
+			continue
+		}
+		client, err := deploy.NewClusterClientset(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		if err := deploy.RestartOperator(ctx, client, operator.namespace, operator.name); err != nil {
+			return nil, err
+		}
+		return []string{fmt.Sprintf("restarted operator %s/%s", operator.namespace, operator.name)}, nil
+	}
+	return nil, nil
+}
+
+// displayHealthRound prints the checks of a round as timestamped log lines
+func displayHealthRound(round *health.MonitorRound) {
+	timestamp := round.Time.Format("15:04:05")
+	for _, check := range round.Checks {
+		mark, color := "✓", "\033[32m"
+		if !check.Healthy {
+			mark, color = "✗", "\033[31m"
+		}
+		line := fmt.Sprintf("%s %s %-15s %s", timestamp, mark, check.Name, check.Message)
+		if IsColorDisabled() {
+			fmt.Println(line)
+		} else {
+			fmt.Printf("%s%s\033[0m\n", color, line)
+		}
+	}
+
+	for _, remediation := range round.Remediations {
+		printWarning("%s Self-heal: %s", timestamp, remediation)
+	}
+	if round.RemediationError != "" {
+		printError("%s Self-heal failed: %s", timestamp, round.RemediationError)
+	}
+	if round.Alerted {
+		printWarning("%s %d consecutive failed rounds", timestamp, round.ConsecutiveFailures)
+	}
+	if round.AlertError != "" {
+		printError("%s %s", timestamp, round.AlertError)
+	}
+}
+
+// newClusterConfigCommand creates the cluster config subcommand
+func newClusterConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Work with cluster config files",
+		Long:  `Work with the cluster config files read by 'c8s dev cluster create --config'.`,
+	}
+
+	cmd.AddCommand(newClusterConfigValidateCommand())
+
+	return cmd
+}
+
+// newClusterConfigValidateCommand creates the cluster config validate subcommand
+func newClusterConfigValidateCommand() *cobra.Command {
+	var (
+		file           string
+		fix            bool
+		skipImageCheck bool
+		output         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a cluster config file without creating a cluster",
+		Long: `Check a cluster config file before 'c8s dev cluster create --config' uses
+it: the name, the Kubernetes version format, the node counts, the port
+mappings, the registry name and ports, the volume mounts and the wait
+timeout. Every issue is reported, not only the first.
+
+The k3s image of the Kubernetes version is looked up on Docker Hub;
+--skip-image-check skips the lookup, e.g. when offline. A failed lookup is
+reported as a warning.
+
+--fix corrects common issues in place: a Kubernetes version without the "v"
+prefix or with a "-k3s1" suffix, upper case cluster and registry names, and
+lower case port protocols. The file is rewritten without its comments.
+
+Exits with code 1 if the config has errors; warnings don't fail validation.`,
+		Example: `  # Validate a config file
+  c8s dev cluster config validate --file k3d-config.yaml
+
+  # Fix common issues and validate offline
+  c8s dev cluster config validate --file k3d-config.yaml --fix --skip-image-check`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadClusterConfigFromFile(file)
+			if err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+
+			report := cluster.ValidateConfig(context.Background(), config, cluster.ConfigValidateOptions{
+				Fix:            fix,
+				SkipImageCheck: skipImageCheck,
+			})
+
+			if fix && hasFixedIssues(report) {
+				data, err := yaml.Marshal(config)
+				if err != nil {
+					printError("Failed to encode fixed config: %v", err)
+					return exitWithCode(1)
+				}
+				if err := os.WriteFile(file, data, 0o644); err != nil {
+					printError("Failed to write fixed config: %v", err)
+					return exitWithCode(1)
+				}
+			}
+
+			switch output {
+			case "json":
+				if err := formatJSON(report); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(report); err != nil {
+					return err
+				}
+			default:
+				displayConfigValidationReport(file, report)
+			}
+
+			if !report.Valid {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Cluster config file to validate (required)")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Correct common issues in the file")
+	cmd.Flags().BoolVar(&skipImageCheck, "skip-image-check", false, "Don't check that the k3s image of the version exists")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// hasFixedIssues reports whether --fix changed the config
+func hasFixedIssues(report *cluster.ConfigValidationReport) bool {
+	for _, issue := range report.Issues {
+		if issue.Fixed {
+			return true
+		}
+	}
+	return false
+}
+
+// displayConfigValidationReport prints the issues of a config file, fixed
+// issues first
+func displayConfigValidationReport(file string, report *cluster.ConfigValidationReport) {
+	warnings := 0
+	for _, issue := range report.Issues {
+		switch {
+		case issue.Fixed:
+			printSuccess("%s: fixed, %s", issue.Field, issue.Message)
+		case issue.Severity == cluster.ConfigSeverityWarning:
+			warnings++
+			printWarning("%s: %s", issue.Field, issue.Message)
+		default:
+			printError("%s: %s", issue.Field, issue.Message)
+		}
+	}
+
+	if report.ImageAvailable != nil && *report.ImageAvailable {
+		printInfo("Image %s is available", report.Image)
+	}
+
+	if !report.Valid {
+		printError("%s has %d error(s) and %d warning(s)", file, report.Errors(), warnings)
+		return
+	}
+	printSuccess("%s is valid (%d warning(s))", file, warnings)
+}
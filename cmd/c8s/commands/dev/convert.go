@@ -0,0 +1,81 @@
+package dev
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/org/c8s/pkg/parser"
+)
+
+// newPipelineConvertCommand creates the pipeline convert subcommand
+func newPipelineConvertCommand() *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "convert FILE",
+		Short: "Convert a Tekton or GitHub Actions pipeline to c8s format",
+		Long: `Convert a pipeline from another CI system to a .c8s.yaml definition,
+written to stdout.
+
+  github-actions  A workflow file (.github/workflows/*.yml). Each job becomes
+                  a step running its 'run' commands; 'needs' becomes dependsOn.
+  tekton          A YAML file with a Pipeline and the Tasks it references.
+                  Each pipeline task becomes a step; 'runAfter' becomes
+                  dependsOn.
+
+Features without a c8s equivalent, such as actions or when expressions, are
+reported as warnings on stderr and as comments in the output. Review the
+result before committing it.`,
+		Example: `  # Convert a GitHub Actions workflow
+  c8s dev pipeline convert .github/workflows/ci.yml --from github-actions > .c8s.yaml
+
+  # Convert a Tekton Pipeline and its Tasks
+  c8s dev pipeline convert pipeline.yaml --from tekton > .c8s.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := os.ReadFile(args[0])
+			if err != nil {
+				printError("Failed to read %s: %v", args[0], err)
+				return exitWithCode(1)
+			}
+
+			var result *parser.ConversionResult
+			switch from {
+			case "github-actions":
+				result, err = parser.ConvertGitHubActions(content)
+			case "tekton":
+				result, err = parser.ConvertTekton(content)
+			default:
+				printError("Unsupported --from %q (expected tekton or github-actions)", from)
+				return exitWithCode(1)
+			}
+			if err != nil {
+				printError("%s: %v", args[0], err)
+				return exitWithCode(1)
+			}
+
+			output, err := result.Marshal()
+			if err != nil {
+				return err
+			}
+
+			// Warnings go to stderr so stdout can be redirected to .c8s.yaml
+			for _, warning := range result.Warnings {
+				fmt.Fprintf(os.Stderr, "⚠ %s\n", warning)
+			}
+			if _, err := parser.ParseBytes(output); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ The converted pipeline needs manual fixes: %v\n", err)
+			}
+
+			_, err = os.Stdout.Write(output)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Source format (tekton|github-actions)")
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/org/c8s/pkg/localenv/cluster"
 	"github.com/org/c8s/pkg/localenv/deploy"
@@ -24,12 +25,15 @@ This command manages all aspects of deploying the C8S system:
 - Loading operator images
 - Deploying the operator
 - Deploying sample PipelineConfigs
+- Deploying the Prometheus/Grafana monitoring stack
 
-Use 'c8s dev deploy operator' to deploy the operator and 'c8s dev deploy samples' to deploy samples.`,
+Use 'c8s dev deploy operator' to deploy the operator, 'c8s dev deploy samples' to deploy samples
+and 'c8s dev deploy monitoring' to install Prometheus and Grafana.`,
 	}
 
 	cmd.AddCommand(newDeployOperatorCommand())
 	cmd.AddCommand(newDeploySamplesCommand())
+	cmd.AddCommand(newDeployMonitoringCommand())
 
 	return cmd
 }
@@ -224,3 +228,102 @@ Example:
 
 	return cmd
 }
+
+// newDeployMonitoringCommand creates the deploy monitoring subcommand
+func newDeployMonitoringCommand() *cobra.Command {
+	var (
+		clusterName       string
+		namespace         string
+		operatorNamespace string
+		releaseName       string
+		chartVersion      string
+		grafanaPort       int
+		portForward       bool
+		timeout           int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "monitoring",
+		Short: "Deploy Prometheus and Grafana to a cluster",
+		Long: `Deploy a Prometheus and Grafana monitoring stack to a local Kubernetes cluster.
+
+This command:
+1. Installs the kube-prometheus-stack Helm chart (requires helm)
+2. Applies the C8S PrometheusRule and PodMonitor for the operator metrics
+3. Installs the C8S Grafana dashboard (run rates, step durations,
+   failure rates and log storage usage)
+4. Port-forwards Grafana to a local port (if --port-forward is set)
+
+Example:
+  c8s dev deploy monitoring --cluster c8s-dev
+  c8s dev deploy monitoring --grafana-port 3001 --port-forward=false`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Deploying monitoring stack to cluster %q\n", clusterName)
+			}
+
+			status, err := deploy.DeployMonitoring(ctx, cluster.NewKubectlClient(), deploy.MonitoringOptions{
+				Namespace:         namespace,
+				OperatorNamespace: operatorNamespace,
+				ReleaseName:       releaseName,
+				ChartVersion:      chartVersion,
+				Timeout:           time.Duration(timeout) * time.Second,
+			})
+			if err != nil {
+				if status != nil && status.Message != "" {
+					return fmt.Errorf("failed to deploy monitoring: %s", status.Message)
+				}
+				return fmt.Errorf("failed to deploy monitoring: %w", err)
+			}
+
+			fmt.Printf("✓ Monitoring deployed successfully\n")
+			fmt.Printf("  Namespace: %s\n", status.Namespace)
+			fmt.Printf("  Release: %s\n", status.ReleaseName)
+			fmt.Printf("  Manifests applied:\n")
+			for _, manifest := range status.ManifestsApplied {
+				fmt.Printf("    - %s\n", manifest)
+			}
+
+			if !portForward {
+				fmt.Printf("\nAccess Grafana with:\n")
+				fmt.Printf("  kubectl port-forward -n %s svc/%s %d:80\n", status.Namespace, status.GrafanaService, grafanaPort)
+				return nil
+			}
+
+			forward, err := deploy.PortForwardGrafana(ctx, status.Namespace, status.ReleaseName, grafanaPort)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\nGrafana: http://localhost:%d (dashboard \"C8S Pipelines\")\n", grafanaPort)
+			fmt.Printf("Default credentials: admin / prom-operator\n")
+			fmt.Printf("Press Ctrl+C to stop port-forwarding\n")
+
+			return forward.Wait()
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster to deploy to")
+	cmd.Flags().StringVar(&namespace, "namespace", deploy.DefaultMonitoringNamespace,
+		"Kubernetes namespace to deploy Prometheus and Grafana into")
+	cmd.Flags().StringVar(&operatorNamespace, "operator-namespace", "c8s-system",
+		"Namespace of the C8S operator to scrape")
+	cmd.Flags().StringVar(&releaseName, "release", deploy.DefaultMonitoringRelease,
+		"Helm release name")
+	cmd.Flags().StringVar(&chartVersion, "chart-version", "",
+		"kube-prometheus-stack chart version (default: latest)")
+	cmd.Flags().IntVar(&grafanaPort, "grafana-port", 3000,
+		"Local port to forward Grafana to")
+	cmd.Flags().BoolVar(&portForward, "port-forward", true,
+		"Port-forward Grafana after deployment (blocks until interrupted)")
+	cmd.Flags().IntVar(&timeout, "timeout", 600,
+		"Timeout in seconds for the Helm installation")
+
+	return cmd
+}
@@ -65,7 +65,13 @@ Workflows:
 	// Add subcommands
 	cmd.AddCommand(newClusterCommand())
 	cmd.AddCommand(newDeployCommand())
+	cmd.AddCommand(newOperatorCommand())
 	cmd.AddCommand(newTestCommand())
+	cmd.AddCommand(newPipelineCommand())
+	cmd.AddCommand(newLintCommand())
+	cmd.AddCommand(newDiagnoseCommand())
+	cmd.AddCommand(newWebhookCommand())
+	cmd.AddCommand(newVersionCommand())
 
 	return cmd
 }
@@ -0,0 +1,232 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/org/c8s/pkg/localenv/deploy"
+	"github.com/org/c8s/pkg/localenv/health"
+	"github.com/spf13/cobra"
+)
+
+// DiagnoseReport contains the results of all diagnostic checks
+type DiagnoseReport struct {
+	Cluster  string               `json:"cluster" yaml:"cluster"`
+	Healthy  bool                 `json:"healthy" yaml:"healthy"`
+	Checks   []health.CheckResult `json:"checks" yaml:"checks"`
+	Optional []health.CheckResult `json:"optional" yaml:"optional"`
+}
+
+// newDiagnoseCommand creates the diagnose subcommand
+func newDiagnoseCommand() *cobra.Command {
+	var (
+		clusterName         string
+		operatorNamespace   string
+		monitoringNamespace string
+		output              string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Diagnose the local development environment",
+		Long: `Run diagnostic checks against the local development environment.
+
+Required checks:
+- Docker daemon and kubectl availability
+- Cluster readiness
+- C8S CRD registration
+- Operator pod status
+- Operator Deployment availability (see 'c8s dev operator status')
+
+Optional checks (reported but do not fail the diagnosis):
+- Monitoring stack availability (see 'c8s dev deploy monitoring')
+
+Exits with code 1 if any required check fails.
+
+Use 'c8s dev diagnose network' to check connectivity from pipeline Pods.`,
+		Example: `  # Diagnose the default environment
+  c8s dev diagnose
+
+  # Output as JSON
+  c8s dev diagnose --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			checker := health.NewChecker()
+
+			report := DiagnoseReport{
+				Cluster: clusterName,
+				Checks: []health.CheckResult{
+					checker.CheckDocker(ctx),
+					checker.CheckKubectl(ctx),
+					checker.CheckClusterReady(ctx),
+					checker.CheckCRDRegistered(ctx, "pipelineconfigs.c8s.dev"),
+					checker.CheckCRDRegistered(ctx, "pipelineruns.c8s.dev"),
+					checker.CheckPodStatus(ctx, operatorNamespace, "app=c8s-controller"),
+					operatorHealthCheck(ctx, clusterName, operatorNamespace, deploy.DefaultOperatorName),
+				},
+				Optional: []health.CheckResult{
+					checker.CheckMonitoring(ctx, monitoringNamespace),
+				},
+			}
+
+			report.Healthy = true
+			for _, check := range report.Checks {
+				if !check.Healthy {
+					report.Healthy = false
+					break
+				}
+			}
+
+			switch output {
+			case "json":
+				if err := formatJSON(report); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(report); err != nil {
+					return err
+				}
+			default:
+				for _, check := range report.Checks {
+					if check.Healthy {
+						printSuccess("%s: %s", check.Name, check.Message)
+					} else {
+						printError("%s: %s", check.Name, check.Message)
+					}
+				}
+				for _, check := range report.Optional {
+					if check.Healthy {
+						printSuccess("%s: %s", check.Name, check.Message)
+					} else {
+						printWarning("%s: %s", check.Name, check.Message)
+						if check.Name == "Monitoring" {
+							printInfo("Install it with: c8s dev deploy monitoring --cluster %s", clusterName)
+						}
+					}
+				}
+				fmt.Println()
+				if report.Healthy {
+					printSuccess("Environment is healthy")
+				} else {
+					printError("Environment has problems")
+				}
+			}
+
+			if !report.Healthy {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newDiagnoseNetworkCommand())
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster to diagnose")
+	cmd.Flags().StringVar(&operatorNamespace, "operator-namespace", "c8s-system",
+		"Namespace of the C8S operator")
+	cmd.Flags().StringVar(&monitoringNamespace, "monitoring-namespace", deploy.DefaultMonitoringNamespace,
+		"Namespace of the monitoring stack")
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// newDiagnoseNetworkCommand creates the diagnose network subcommand
+func newDiagnoseNetworkCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+		image       string
+		timeout     time.Duration
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Check connectivity from the perspective of pipeline Pods",
+		Long: `Run a temporary Job built like the Jobs of pipeline steps and check, from
+its Pod:
+- DNS resolution of each target
+- HTTPS to the docker.io and ghcr.io registries and to github.com
+- access to the Kubernetes API server
+
+Failures are classified as DNS, routing (connection refused or timed out)
+or TLS (handshake or certificate verification), to tell why steps fail to
+pull images or clone repositories. The Job is deleted afterwards.
+
+Exits with code 1 if any target is unreachable.`,
+		Example: `  # Check connectivity of pipeline Pods in the default namespace
+  c8s dev diagnose network
+
+  # Check the namespace pipelines run in, with a mirrored check image
+  c8s dev diagnose network --namespace ci --image registry.localhost:5000/curl:8.10.1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := deploy.NewClusterClientset(clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", clusterName, err)
+				return exitWithCode(1)
+			}
+
+			if output == "text" {
+				printInfo("Running network checks in namespace %s...", namespace)
+			}
+			report, err := health.DiagnoseNetwork(context.Background(), client, health.NetworkOptions{
+				Namespace: namespace,
+				Image:     image,
+				Timeout:   timeout,
+			})
+			if err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				if err := formatJSON(report); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(report); err != nil {
+					return err
+				}
+			default:
+				rows := make([][]string, 0, len(report.Results))
+				for _, result := range report.Results {
+					rows = append(rows, []string{
+						result.Target,
+						string(result.DNS),
+						string(result.HTTPS),
+						orDash(string(result.Failure)),
+						result.Diagnosis,
+					})
+				}
+				fmt.Println()
+				formatTable([]string{"TARGET", "DNS", "HTTPS", "FAILURE", "DIAGNOSIS"}, rows)
+				fmt.Println()
+				if report.Healthy {
+					printSuccess("All targets are reachable from pipeline Pods")
+				} else {
+					printError("Some targets are unreachable from pipeline Pods")
+				}
+			}
+
+			if !report.Healthy {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace to run the check Job in")
+	cmd.Flags().StringVar(&image, "image", health.DefaultNetworkCheckImage, "Image providing curl and nslookup")
+	cmd.Flags().DurationVar(&timeout, "timeout", health.DefaultNetworkCheckTimeout, "Maximum time to wait for the check Job")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
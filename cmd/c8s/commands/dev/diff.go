@@ -0,0 +1,174 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/org/c8s/pkg/localenv/deploy"
+	"github.com/org/c8s/pkg/localenv/watch"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// newPipelineDiffCommand creates the pipeline diff subcommand
+func newPipelineDiffCommand() *cobra.Command {
+	var (
+		clusterName   string
+		namespace     string
+		name          string
+		live          bool
+		applyOnChange bool
+		output        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff [FILE]",
+		Short: "Compare a pipeline file with the deployed PipelineConfig",
+		Long: `Compare a pipeline file (default .c8s.yaml) with the PipelineConfig of the
+same name deployed in a cluster, and show the changes applying the file
+would make: added, removed and modified steps, dependency, timeout and
+resource changes.
+
+The PipelineConfig name is the name field of the file unless --name is set.
+
+With --live, the file is watched and the diff is printed again every time
+it is saved, replacing the previous output. With --apply-on-change, the
+file is also applied to the deployed PipelineConfig after each save whose
+diff removes no steps. Removals are never applied automatically.
+
+Exits with code 1 if the file is invalid or the cluster can't be reached.`,
+		Example: `  # Show what applying .c8s.yaml would change
+  c8s dev pipeline diff
+
+  # Review changes while editing
+  c8s dev pipeline diff .c8s.yaml --live
+
+  # Apply every save that doesn't remove steps
+  c8s dev pipeline diff --live --apply-on-change --namespace ci`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ".c8s.yaml"
+			if len(args) > 0 {
+				path = args[0]
+			}
+			if applyOnChange && !live {
+				return fmt.Errorf("--apply-on-change requires --live")
+			}
+
+			client, err := deploy.NewClusterDynamicClient(clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", clusterName, err)
+				return exitWithCode(1)
+			}
+
+			if !live {
+				if err := runPipelineDiff(cmd.Context(), client, path, name, namespace, output, false); err != nil {
+					printError("%v", err)
+					return exitWithCode(1)
+				}
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			refresh := func() {
+				fmt.Print(clearScreen)
+				if err := runPipelineDiff(ctx, client, path, name, namespace, output, applyOnChange); err != nil {
+					printError("%v", err)
+				}
+				printInfo("\nWatching %s for changes (Ctrl+C to stop)...", path)
+			}
+
+			refresh()
+			if err := watch.File(ctx, path, watch.DefaultDebounce, refresh); err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev", "Name of the cluster")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the PipelineConfig")
+	cmd.Flags().StringVar(&name, "name", "", "Name of the PipelineConfig (default: name field of the file)")
+	cmd.Flags().BoolVar(&live, "live", false, "Watch the file and print the diff again on every save")
+	cmd.Flags().BoolVar(&applyOnChange, "apply-on-change", false, "Apply the file on every save that removes no steps (with --live)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// runPipelineDiff prints the diff between a pipeline file and the deployed
+// PipelineConfig, then applies the file when apply is set and the diff is
+// clean
+func runPipelineDiff(ctx context.Context, client dynamic.Interface, path, name, namespace, output string, apply bool) error {
+	local, err := deploy.LoadPipelineFile(path, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	diff, err := deploy.DiffDeployedConfig(ctx, client, local)
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case "json":
+		if err := formatJSON(diff); err != nil {
+			return err
+		}
+	case "yaml":
+		if err := formatYAML(diff); err != nil {
+			return err
+		}
+	default:
+		printPipelineDiff(path, diff)
+	}
+
+	if !apply || len(diff.Changes) == 0 {
+		return nil
+	}
+	if diff.HasRemovals() {
+		printWarning("Not applied: the diff removes steps; apply the file manually to confirm")
+		return nil
+	}
+	if err := deploy.ApplyPipelineFile(ctx, client, local); err != nil {
+		return err
+	}
+	printSuccess("Applied %s to PipelineConfig %s/%s at %s", path, diff.Namespace, diff.Name, time.Now().Format("15:04:05"))
+	return nil
+}
+
+// printPipelineDiff prints the changes of a diff, one per line
+func printPipelineDiff(path string, diff *deploy.PipelineDiff) {
+	fmt.Printf("%s -> PipelineConfig %s/%s\n\n", path, diff.Namespace, diff.Name)
+
+	if !diff.Deployed {
+		printWarning("PipelineConfig %s/%s is not deployed", diff.Namespace, diff.Name)
+	}
+	if len(diff.Changes) == 0 {
+		printSuccess("No changes")
+		return
+	}
+
+	for _, change := range diff.Changes {
+		switch change.Kind {
+		case parser.ChangeStepAdded:
+			fmt.Printf("  + %s\n", change)
+		case parser.ChangeStepRemoved:
+			fmt.Printf("  - %s\n", change)
+		default:
+			fmt.Printf("  ~ %s\n", change)
+		}
+	}
+	fmt.Printf("\n%d changes\n", len(diff.Changes))
+}
@@ -0,0 +1,274 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/deploy"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/scheduler"
+)
+
+// newLintCommand creates the lint subcommand
+func newLintCommand() *cobra.Command {
+	var (
+		strict      bool
+		branches    []string
+		maxParallel int
+		clusterName string
+		output      string
+		remote      bool
+		namespace   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint [FILE]",
+		Short: "Check a pipeline file for configuration issues",
+		Long: `Parse and validate a pipeline file (default .c8s.yaml).
+
+With --strict, the execution schedule is also checked for issues that only
+manifest at runtime:
+- steps whose branch conditional matches none of the trigger branches
+- steps requesting more CPU or memory than any node provides (--cluster)
+- layers with more steps than --max-parallel Jobs (default: maxParallel)
+- layers where every step is conditional and may be skipped
+
+and the lint rules are run: images using :latest, steps without resources,
+steps with more than 10 commands and step timeouts over 2h.
+
+Exits with code 1 if the file is invalid, or with --strict if any issue of
+warning or error severity is found.
+
+With --remote, the PipelineConfigs deployed in --namespace of the cluster are
+validated instead of a file. Configs created before a validation rule was
+added are accepted by the API server but reported here, so deployed
+pipelines can be audited after an upgrade. Exits with code 1 if any config
+is invalid.`,
+		Example: `  # Validate .c8s.yaml
+  c8s dev lint
+
+  # Check the schedule against the trigger branches and a local cluster
+  c8s dev lint .c8s.yaml --strict --branch main --cluster dev-env
+
+  # Output schedule warnings as JSON
+  c8s dev lint --strict --output json
+
+  # Audit the PipelineConfigs deployed in a namespace
+  c8s dev lint --remote --namespace default --cluster dev-env`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if remote {
+				if len(args) > 0 {
+					return fmt.Errorf("a file can't be linted with --remote")
+				}
+				if clusterName == "" {
+					clusterName = "c8s-dev"
+				}
+				return lintRemoteConfigs(cmd.Context(), clusterName, namespace, output)
+			}
+
+			path := ".c8s.yaml"
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				printError("Failed to read %s: %v", path, err)
+				return exitWithCode(1)
+			}
+
+			spec, err := parser.ParseBytes(content)
+			if err != nil {
+				printError("%s: %v", path, err)
+				return exitWithCode(1)
+			}
+			if len(branches) > 0 {
+				spec.Branches = branches
+			}
+
+			config := &c8sv1alpha1.PipelineConfig{Spec: *spec}
+			if err := parser.Validate(config); err != nil {
+				printError("%s: %v", path, err)
+				return exitWithCode(1)
+			}
+
+			configWarnings := parser.Warnings(config)
+
+			if !strict {
+				for _, warning := range configWarnings {
+					printWarning("%s: %v", path, warning)
+				}
+				printSuccess("%s is valid (%d steps)", path, len(spec.Steps))
+				return nil
+			}
+
+			schedule, err := scheduler.BuildSchedule(config)
+			if err != nil {
+				printError("%s: %v", path, err)
+				return exitWithCode(1)
+			}
+
+			opts := scheduler.ValidateOptions{MaxParallel: maxParallel}
+			if opts.MaxParallel == 0 {
+				opts.MaxParallel = config.Spec.MaxParallel
+			}
+			if clusterName != "" {
+				opts.NodeCapacity = clusterNodeCapacity(clusterName)
+			}
+			warnings := scheduler.ValidateScheduleWithOptions(schedule, config, opts)
+			for _, warning := range configWarnings {
+				warnings = append(warnings, scheduler.ScheduleWarning{
+					Severity: scheduler.SeverityWarning,
+					Message:  warning.Error(),
+				})
+			}
+			for _, finding := range parser.Lint(spec) {
+				warnings = append(warnings, scheduler.ScheduleWarning{
+					Severity: scheduler.WarningSeverity(finding.Severity),
+					Step:     finding.Step,
+					Message:  finding.Message,
+				})
+			}
+
+			switch output {
+			case "json":
+				if err := formatJSON(warnings); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(warnings); err != nil {
+					return err
+				}
+			default:
+				printScheduleWarnings(path, warnings)
+			}
+
+			for _, warning := range warnings {
+				if warning.Severity != scheduler.SeverityInfo {
+					return exitWithCode(1)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", false,
+		"Also check the execution schedule and fail on warnings")
+	cmd.Flags().StringSliceVar(&branches, "branch", nil,
+		"Trigger branches to check conditionals against (default: spec branches)")
+	cmd.Flags().IntVar(&maxParallel, "max-parallel", 0,
+		"Maximum number of Jobs run in parallel (default: the pipeline's maxParallel, 0 for no limit)")
+	cmd.Flags().StringVar(&clusterName, "cluster", "",
+		"Check step resource requests against the nodes of this cluster (with --remote: the cluster to read, default c8s-dev)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format (text|json|yaml)")
+	cmd.Flags().BoolVar(&remote, "remote", false,
+		"Validate the PipelineConfigs deployed in the cluster instead of a file")
+	cmd.Flags().StringVar(&namespace, "namespace", "default",
+		"Namespace of the PipelineConfigs to validate with --remote")
+
+	return cmd
+}
+
+// lintRemoteConfigs validates the PipelineConfigs deployed in a namespace of
+// a cluster and exits with code 1 if any is invalid
+func lintRemoteConfigs(ctx context.Context, clusterName, namespace, output string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	client, err := deploy.NewClusterDynamicClient(clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to connect to cluster '%s': %w", clusterName, err)
+	}
+
+	results, err := deploy.ValidateDeployedConfigs(ctx, client, namespace)
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case "json":
+		if err := formatJSON(results); err != nil {
+			return err
+		}
+	case "yaml":
+		if err := formatYAML(results); err != nil {
+			return err
+		}
+	default:
+		if len(results) == 0 {
+			printInfo("No PipelineConfigs found in namespace %s", namespace)
+			return nil
+		}
+		rows := make([][]string, 0, len(results))
+		for _, result := range results {
+			rows = append(rows, []string{result.Name, fmt.Sprintf("%t", result.Valid), strings.Join(result.Errors, "; ")})
+		}
+		formatTable([]string{"CONFIG-NAME", "VALID", "ERRORS"}, rows)
+	}
+
+	for _, result := range results {
+		if !result.Valid {
+			return exitWithCode(1)
+		}
+	}
+	return nil
+}
+
+// printScheduleWarnings prints schedule warnings as text
+func printScheduleWarnings(path string, warnings []scheduler.ScheduleWarning) {
+	if len(warnings) == 0 {
+		printSuccess("%s is valid, no schedule issues found", path)
+		return
+	}
+
+	for _, warning := range warnings {
+		message := warning.Message
+		if warning.Step != "" {
+			message = fmt.Sprintf("step %s: %s", warning.Step, message)
+		}
+
+		switch warning.Severity {
+		case scheduler.SeverityError:
+			printError("%s", message)
+		case scheduler.SeverityWarning:
+			printWarning("%s", message)
+		default:
+			printInfo("ℹ %s", message)
+		}
+	}
+}
+
+// clusterNodeCapacity returns a NodeCapacityFunc listing the allocatable
+// resources of the nodes of a local cluster
+func clusterNodeCapacity(clusterName string) scheduler.NodeCapacityFunc {
+	return func() ([]scheduler.NodeCapacity, error) {
+		client, err := deploy.NewClusterClientset(clusterName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to cluster '%s': %w", clusterName, err)
+		}
+
+		nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+
+		capacities := make([]scheduler.NodeCapacity, 0, len(nodes.Items))
+		for _, node := range nodes.Items {
+			capacities = append(capacities, scheduler.NodeCapacity{
+				Name:   node.Name,
+				CPU:    node.Status.Allocatable.Cpu().DeepCopy(),
+				Memory: node.Status.Allocatable.Memory().DeepCopy(),
+			})
+		}
+		return capacities, nil
+	}
+}
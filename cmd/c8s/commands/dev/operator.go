@@ -0,0 +1,520 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/org/c8s/pkg/localenv/deploy"
+	"github.com/org/c8s/pkg/localenv/health"
+)
+
+// operatorFlags holds the flags shared by the operator subcommands
+type operatorFlags struct {
+	clusterName string
+	namespace   string
+	name        string
+}
+
+// register adds the shared operator flags to cmd
+func (f *operatorFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.clusterName, "cluster", "c8s-dev",
+		"Name of the cluster")
+	cmd.Flags().StringVar(&f.namespace, "namespace", deploy.DefaultOperatorNamespace,
+		"Namespace of the operator")
+	cmd.Flags().StringVar(&f.name, "deployment", deploy.DefaultOperatorName,
+		"Name of the operator Deployment")
+}
+
+// newOperatorCommand creates the operator subcommand
+func newOperatorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "operator",
+		Short: "Inspect and control the deployed C8S operator",
+		Long: `Inspect and control the C8S operator Deployment on a local cluster.
+
+Use 'c8s dev deploy operator' to install the operator first.`,
+	}
+
+	cmd.AddCommand(newOperatorStatusCommand())
+	cmd.AddCommand(newOperatorRestartCommand())
+	cmd.AddCommand(newOperatorScaleCommand())
+	cmd.AddCommand(newOperatorUpgradeCommand())
+	cmd.AddCommand(newOperatorProfileCommand())
+
+	return cmd
+}
+
+// newOperatorStatusCommand creates the operator status subcommand
+func newOperatorStatusCommand() *cobra.Command {
+	var (
+		flags  operatorFlags
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show operator Deployment status",
+		Long: `Show the conditions, replica counts, image and recent events of the
+operator Deployment.
+
+Exits with code 1 if the operator is not fully available.`,
+		Example: `  # Show the operator status
+  c8s dev operator status
+
+  # Output as JSON
+  c8s dev operator status --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := deploy.NewClusterClientset(flags.clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", flags.clusterName, err)
+				return exitWithCode(1)
+			}
+
+			status, err := deploy.GetOperatorStatus(context.Background(), client, flags.namespace, flags.name)
+			if err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				if err := formatJSON(status); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(status); err != nil {
+					return err
+				}
+			default:
+				printOperatorStatus(status)
+			}
+
+			if !status.Healthy {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// newOperatorRestartCommand creates the operator restart subcommand
+func newOperatorRestartCommand() *cobra.Command {
+	var (
+		flags   operatorFlags
+		wait    bool
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Perform a rollout restart of the operator",
+		Long: `Restart the operator Pods with a rolling update, the same way
+'kubectl rollout restart' does.
+
+Useful after changing configuration the operator reads at startup.`,
+		Example: `  # Restart the operator and wait for the rollout
+  c8s dev operator restart
+
+  # Restart without waiting
+  c8s dev operator restart --wait=false`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			client, err := deploy.NewClusterClientset(flags.clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", flags.clusterName, err)
+				return exitWithCode(1)
+			}
+
+			if err := deploy.RestartOperator(ctx, client, flags.namespace, flags.name); err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+			printSuccess("Restarted operator deployment %s/%s", flags.namespace, flags.name)
+
+			if wait {
+				return waitForOperator(ctx, client, flags, timeout)
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&wait, "wait", true,
+		"Wait for the rollout to complete")
+	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute,
+		"Maximum time to wait for the rollout")
+
+	return cmd
+}
+
+// newOperatorScaleCommand creates the operator scale subcommand
+func newOperatorScaleCommand() *cobra.Command {
+	var (
+		flags    operatorFlags
+		replicas int32
+		wait     bool
+		timeout  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scale",
+		Short: "Scale the operator Deployment",
+		Long: `Set the number of operator replicas, for example to test leader election
+and failover with more than one replica.`,
+		Example: `  # Run three operator replicas
+  c8s dev operator scale --replicas 3
+
+  # Stop the operator
+  c8s dev operator scale --replicas 0`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if !cmd.Flags().Changed("replicas") {
+				printError("--replicas is required")
+				return exitWithCode(1)
+			}
+
+			client, err := deploy.NewClusterClientset(flags.clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", flags.clusterName, err)
+				return exitWithCode(1)
+			}
+
+			if err := deploy.ScaleOperator(ctx, client, flags.namespace, flags.name, replicas); err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+			printSuccess("Scaled operator deployment %s/%s to %d replica(s)", flags.namespace, flags.name, replicas)
+
+			if wait {
+				return waitForOperator(ctx, client, flags, timeout)
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().Int32Var(&replicas, "replicas", 1,
+		"Number of operator replicas")
+	cmd.Flags().BoolVar(&wait, "wait", true,
+		"Wait for the replicas to become available")
+	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute,
+		"Maximum time to wait for the replicas")
+
+	return cmd
+}
+
+// newOperatorUpgradeCommand creates the operator upgrade subcommand
+func newOperatorUpgradeCommand() *cobra.Command {
+	var (
+		flags   operatorFlags
+		image   string
+		timeout time.Duration
+		output  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Roll out a new operator version",
+		Long: `Upgrade the operator Deployment to a new image.
+
+The upgrade:
+  1. Checks the image exists locally or can be pulled, and imports it into
+     the cluster
+  2. Sets the image of the operator container
+  3. Waits for the rollout to complete
+  4. Validates every PipelineConfig against the new operator with a
+     server-side dry-run update
+
+If the rollout does not complete or any PipelineConfig is rejected, the
+previous image is restored automatically.`,
+		Example: `  # Upgrade the operator to a locally built image
+  c8s dev operator upgrade --image c8s-controller:dev
+
+  # Upgrade to a release, allowing five minutes per rollout
+  c8s dev operator upgrade --image ghcr.io/org/c8s-controller:v0.2.0 --timeout 5m`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if image == "" {
+				printError("--image is required")
+				return exitWithCode(1)
+			}
+
+			printInfo("Checking image %s...", image)
+			if _, err := deploy.LoadImageToCluster(flags.clusterName, image); err != nil {
+				printError("Image %s is not available: %v", image, err)
+				return exitWithCode(1)
+			}
+
+			client, err := deploy.NewClusterClientset(flags.clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", flags.clusterName, err)
+				return exitWithCode(1)
+			}
+			dynamicClient, err := deploy.NewClusterDynamicClient(flags.clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", flags.clusterName, err)
+				return exitWithCode(1)
+			}
+
+			printInfo("Upgrading operator deployment %s/%s to %s...", flags.namespace, flags.name, image)
+			result, err := deploy.UpgradeOperator(ctx, client, dynamicClient, deploy.UpgradeOptions{
+				Namespace: flags.namespace,
+				Name:      flags.name,
+				Image:     image,
+				Timeout:   timeout,
+			})
+
+			if output == "json" && result != nil {
+				if jsonErr := formatJSON(result); jsonErr != nil {
+					return jsonErr
+				}
+			} else if result != nil {
+				for _, config := range result.IncompatibleConfigs {
+					printWarning("Rejected PipelineConfig %s", config)
+				}
+			}
+
+			if err != nil {
+				printError("Upgrade failed: %v", err)
+				if result != nil && result.RolledBack {
+					return exitWithCode(3)
+				}
+				return exitWithCode(1)
+			}
+
+			if output != "json" {
+				printSuccess("Validated %d PipelineConfigs", result.CheckedConfigs)
+				printSuccess("Upgraded operator from %s to %s", result.PreviousImage, result.Image)
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().StringVar(&image, "image", "",
+		"Operator image to roll out (required)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute,
+		"Maximum time to wait for each rollout")
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format (text|json)")
+
+	return cmd
+}
+
+// newOperatorProfileCommand creates the operator profile subcommand
+func newOperatorProfileCommand() *cobra.Command {
+	var (
+		flags      operatorFlags
+		goroutines bool
+		heap       bool
+		duration   time.Duration
+		outputFile string
+		noOpen     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Capture a performance profile of the operator",
+		Long: `Capture a profile from the pprof endpoint of the operator and open it
+in the 'go tool pprof' web UI.
+
+The operator must run with --enable-pprof, which serves net/http/pprof on
+port 6060. The endpoint is port-forwarded to a free local port for the
+duration of the capture.
+
+By default a CPU profile is sampled for --duration. Use --heap for a heap
+profile, or --goroutines for a text dump of every goroutine stack, which is
+saved but not opened in pprof.`,
+		Example: `  # Capture a 30-second CPU profile and open it in the browser
+  c8s dev operator profile
+
+  # Capture a heap profile without opening it
+  c8s dev operator profile --heap --no-open --file heap.pprof
+
+  # Dump all goroutine stacks to find a stuck reconcile
+  c8s dev operator profile --goroutines`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if goroutines && heap {
+				printError("--goroutines and --heap can't be combined")
+				return exitWithCode(1)
+			}
+
+			kind := deploy.ProfileCPU
+			switch {
+			case heap:
+				kind = deploy.ProfileHeap
+			case goroutines:
+				kind = deploy.ProfileGoroutines
+			}
+
+			if outputFile == "" {
+				extension := "pprof"
+				if kind == deploy.ProfileGoroutines {
+					extension = "txt"
+				}
+				outputFile = fmt.Sprintf("c8s-operator-%s-%s.%s", kind, time.Now().Format("20060102-150405"), extension)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			forward, addr, err := deploy.PortForwardOperatorPprof(ctx, flags.clusterName, flags.namespace, flags.name)
+			if err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+			defer func() {
+				_ = forward.Process.Kill()
+				_ = forward.Wait()
+			}()
+
+			if kind == deploy.ProfileCPU {
+				printInfo("Capturing %s CPU profile of %s/%s...", duration, flags.namespace, flags.name)
+			} else {
+				printInfo("Capturing %s profile of %s/%s...", kind, flags.namespace, flags.name)
+			}
+			if err := deploy.CaptureProfile(ctx, addr, kind, duration, outputFile); err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+			printSuccess("Profile saved to %s", outputFile)
+
+			if noOpen || kind == deploy.ProfileGoroutines {
+				return nil
+			}
+
+			printInfo("Opening profile in the pprof web UI (Ctrl+C to stop)...")
+			pprof := exec.CommandContext(ctx, "go", "tool", "pprof", "-http=localhost:0", outputFile)
+			pprof.Stdout = os.Stdout
+			pprof.Stderr = os.Stderr
+			if err := pprof.Run(); err != nil && ctx.Err() == nil {
+				printError("Failed to run go tool pprof: %v", err)
+				printInfo("Open the profile manually with: go tool pprof -http=: %s", outputFile)
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&goroutines, "goroutines", false,
+		"Capture a goroutine stack dump instead of a CPU profile")
+	cmd.Flags().BoolVar(&heap, "heap", false,
+		"Capture a heap profile instead of a CPU profile")
+	cmd.Flags().DurationVar(&duration, "duration", 30*time.Second,
+		"Sampling duration of the CPU profile")
+	cmd.Flags().StringVar(&outputFile, "file", "",
+		"File to save the profile to (default c8s-operator-<kind>-<time>.pprof)")
+	cmd.Flags().BoolVar(&noOpen, "no-open", false,
+		"Save the profile without opening the pprof web UI")
+
+	return cmd
+}
+
+// waitForOperator waits for the operator rollout and reports the result
+func waitForOperator(ctx context.Context, client kubernetes.Interface, flags operatorFlags, timeout time.Duration) error {
+	printInfo("Waiting for rollout to complete...")
+	status, err := deploy.WaitForOperatorRollout(ctx, client, flags.namespace, flags.name, timeout)
+	if err != nil {
+		printError("%v", err)
+		if status != nil {
+			printInfo("%d/%d replicas available, %d updated", status.AvailableReplicas, status.Replicas, status.UpdatedReplicas)
+		}
+		return exitWithCode(3)
+	}
+	printSuccess("%d/%d replicas available", status.AvailableReplicas, status.Replicas)
+	return nil
+}
+
+// printOperatorStatus prints the operator status in text format
+func printOperatorStatus(status *deploy.OperatorStatus) {
+	fmt.Printf("Deployment: %s/%s\n", status.Namespace, status.Name)
+	fmt.Printf("Image:      %s\n", status.Image)
+	fmt.Printf("Replicas:   %d desired, %d ready, %d updated, %d available\n",
+		status.Replicas, status.ReadyReplicas, status.UpdatedReplicas, status.AvailableReplicas)
+	if status.RestartedAt != "" {
+		fmt.Printf("Restarted:  %s\n", status.RestartedAt)
+	}
+
+	fmt.Println("\nConditions:")
+	if len(status.Conditions) == 0 {
+		fmt.Println("  (none)")
+	} else {
+		rows := make([][]string, 0, len(status.Conditions))
+		for _, condition := range status.Conditions {
+			rows = append(rows, []string{condition.Type, condition.Status, condition.Reason, condition.Message})
+		}
+		formatTable([]string{"TYPE", "STATUS", "REASON", "MESSAGE"}, rows)
+	}
+
+	fmt.Println("\nRecent Events:")
+	if len(status.Events) == 0 {
+		fmt.Println("  (none)")
+	} else {
+		rows := make([][]string, 0, len(status.Events))
+		for _, event := range status.Events {
+			rows = append(rows, []string{
+				formatEventAge(event.LastSeen),
+				event.Type,
+				event.Reason,
+				event.Object,
+				event.Message,
+			})
+		}
+		formatTable([]string{"LAST SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE"}, rows)
+	}
+
+	fmt.Println()
+	if status.Healthy {
+		printSuccess("Operator is available")
+	} else {
+		printError("Operator is not fully available")
+	}
+}
+
+// formatEventAge formats the time since an event was last seen
+func formatEventAge(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return time.Since(t).Round(time.Second).String()
+}
+
+// operatorHealthCheck reports the operator Deployment status as a diagnose check
+func operatorHealthCheck(ctx context.Context, clusterName, namespace, name string) health.CheckResult {
+	result := health.CheckResult{Name: "Operator"}
+
+	client, err := deploy.NewClusterClientset(clusterName)
+	if err != nil {
+		result.Message = fmt.Sprintf("Cannot connect to cluster: %v", err)
+		return result
+	}
+
+	status, err := deploy.GetOperatorStatus(ctx, client, namespace, name)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	result.Healthy = status.Healthy
+	result.Message = fmt.Sprintf("%d/%d replicas available (%s)", status.AvailableReplicas, status.Replicas, status.Image)
+	return result
+}
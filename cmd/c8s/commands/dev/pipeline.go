@@ -0,0 +1,41 @@
+package dev
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// newPipelineCommand creates the pipeline subcommand
+func newPipelineCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pipeline",
+		Short: "Inspect and benchmark pipeline definitions",
+		Long: `Work with pipeline definitions locally.
+
+This command groups tooling that operates on pipeline configurations
+and the scheduler directly, such as performance benchmarks, execution time
+estimates and conversion from other CI systems, and the comparison of a
+pipeline file with the PipelineConfig deployed in a cluster.`,
+		Example: `  # Benchmark the scheduler with large pipelines
+  c8s dev pipeline benchmark --steps 100,500,1000
+
+  # Profile the parser and scheduler against a large pipeline file
+  c8s dev pipeline profile --file large.c8s.yaml --iterations 1000
+
+  # Estimate the wall-clock time of a pipeline
+  c8s dev pipeline simulate .c8s.yaml
+
+  # Convert a GitHub Actions workflow
+  c8s dev pipeline convert .github/workflows/ci.yml --from github-actions
+
+  # Compare .c8s.yaml with the deployed PipelineConfig on every save
+  c8s dev pipeline diff --live`,
+	}
+
+	cmd.AddCommand(newBenchmarkCommand())
+	cmd.AddCommand(newSimulateCommand())
+	cmd.AddCommand(newPipelineConvertCommand())
+	cmd.AddCommand(newPipelineDiffCommand())
+	cmd.AddCommand(newPipelineProfileCommand())
+
+	return cmd
+}
@@ -0,0 +1,244 @@
+package dev
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/scheduler"
+	"github.com/spf13/cobra"
+)
+
+// ProfileLatency summarizes the latencies of one stage of a profiled operation
+type ProfileLatency struct {
+	Stage string        `json:"stage" yaml:"stage"`
+	Mean  time.Duration `json:"mean" yaml:"mean"`
+	P50   time.Duration `json:"p50" yaml:"p50"`
+	P95   time.Duration `json:"p95" yaml:"p95"`
+	P99   time.Duration `json:"p99" yaml:"p99"`
+}
+
+// ProfileResult contains the measurements of parsing and scheduling a pipeline
+type ProfileResult struct {
+	Source      string           `json:"source" yaml:"source"`
+	Steps       int              `json:"steps" yaml:"steps"`
+	Iterations  int              `json:"iterations" yaml:"iterations"`
+	Latencies   []ProfileLatency `json:"latencies" yaml:"latencies"`
+	BytesPerOp  uint64           `json:"bytesPerOp" yaml:"bytesPerOp"`
+	AllocsPerOp uint64           `json:"allocsPerOp" yaml:"allocsPerOp"`
+	CPUProfile  string           `json:"cpuProfile,omitempty" yaml:"cpuProfile,omitempty"`
+}
+
+// newPipelineProfileCommand creates the pipeline profile subcommand
+func newPipelineProfileCommand() *cobra.Command {
+	var (
+		file          string
+		iterations    int
+		generateSteps int
+		maxDeps       int
+		seed          int64
+		cpuProfile    string
+		output        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Profile the parser and scheduler against a pipeline file",
+		Long: `Profile parsing and scheduling a pipeline.
+
+Each iteration runs parser.ParseBytes on the pipeline YAML, then
+scheduler.BuildSchedule on the result. The command reports the mean, p50,
+p95 and p99 latencies of each stage and of the whole operation, the memory
+allocated per operation, and writes a CPU profile of all iterations that
+can be inspected with 'go tool pprof'.
+
+With --generate-steps, a pipeline with N steps and random (acyclic)
+dependencies is synthesized instead of reading --file.`,
+		Example: `  # Profile a large pipeline file
+  c8s dev pipeline profile --file large.c8s.yaml --iterations 1000
+
+  # Profile a synthetic pipeline with 2000 steps
+  c8s dev pipeline profile --generate-steps 2000
+
+  # Inspect the CPU profile
+  go tool pprof -top c8s-profile.pprof`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if iterations <= 0 {
+				return fmt.Errorf("--iterations must be greater than 0")
+			}
+			if generateSteps < 0 {
+				return fmt.Errorf("--generate-steps must not be negative")
+			}
+
+			source := file
+			name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			var pipelineYAML []byte
+			if generateSteps > 0 {
+				config := generateBenchmarkConfig(rand.New(rand.NewSource(seed)), generateSteps, maxDeps)
+				pipelineYAML = renderBenchmarkYAML(config)
+				source = fmt.Sprintf("generated (%d steps)", generateSteps)
+				name = config.Name
+			} else {
+				data, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("failed to read pipeline file: %w", err)
+				}
+				pipelineYAML = data
+			}
+
+			if IsVerbose() {
+				printInfo("[DEBUG] Profiling %s (%d iterations)", source, iterations)
+			}
+
+			result, err := runPipelineProfile(pipelineYAML, name, iterations, cpuProfile)
+			if err != nil {
+				return err
+			}
+			result.Source = source
+
+			switch output {
+			case "json":
+				return formatJSON(result)
+			case "yaml":
+				return formatYAML(result)
+			default:
+				printInfo("Profiled %s: %d steps, %d iterations", result.Source, result.Steps, result.Iterations)
+				fmt.Println()
+
+				headers := []string{"STAGE", "MEAN", "P50", "P95", "P99"}
+				rows := make([][]string, 0, len(result.Latencies))
+				for _, l := range result.Latencies {
+					rows = append(rows, []string{l.Stage, l.Mean.String(), l.P50.String(), l.P95.String(), l.P99.String()})
+				}
+				formatTable(headers, rows)
+
+				fmt.Println()
+				fmt.Printf("Memory: %d B/op, %d allocs/op\n", result.BytesPerOp, result.AllocsPerOp)
+				if result.CPUProfile != "" {
+					fmt.Printf("CPU profile: %s (go tool pprof -top %s)\n", result.CPUProfile, result.CPUProfile)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", ".c8s.yaml",
+		"Pipeline file to profile")
+	cmd.Flags().IntVar(&iterations, "iterations", 1000,
+		"Number of times the pipeline is parsed and scheduled")
+	cmd.Flags().IntVar(&generateSteps, "generate-steps", 0,
+		"Synthesize a pipeline with N steps and random dependencies instead of reading --file")
+	cmd.Flags().IntVar(&maxDeps, "max-deps", 3,
+		"Maximum number of dependencies per generated step")
+	cmd.Flags().Int64Var(&seed, "seed", 1,
+		"Random seed for dependency graph generation")
+	cmd.Flags().StringVar(&cpuProfile, "cpu-profile", "c8s-profile.pprof",
+		"File the CPU profile is written to (empty to disable)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// runPipelineProfile parses and schedules pipelineYAML the given number of
+// times, recording the latency of each stage, the memory allocated per
+// operation and, if cpuProfile is set, a CPU profile of all iterations
+func runPipelineProfile(pipelineYAML []byte, name string, iterations int, cpuProfile string) (*ProfileResult, error) {
+	// Fail on an invalid pipeline before measuring anything
+	spec, err := parser.ParseBytes(pipelineYAML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline: %w", err)
+	}
+	config := &c8sv1alpha1.PipelineConfig{Spec: *spec}
+	config.Name = name
+	if _, err := scheduler.BuildSchedule(config); err != nil {
+		return nil, fmt.Errorf("failed to schedule pipeline: %w", err)
+	}
+
+	result := &ProfileResult{Steps: len(spec.Steps), Iterations: iterations}
+
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		result.CPUProfile = cpuProfile
+	}
+
+	parseDurations := make([]time.Duration, 0, iterations)
+	scheduleDurations := make([]time.Duration, 0, iterations)
+	totalDurations := make([]time.Duration, 0, iterations)
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	for i := 0; i < iterations; i++ {
+		start := time.Now()
+		spec, err := parser.ParseBytes(pipelineYAML)
+		parsed := time.Now()
+		if err == nil {
+			config := &c8sv1alpha1.PipelineConfig{Spec: *spec}
+			config.Name = name
+			_, err = scheduler.BuildSchedule(config)
+		}
+		end := time.Now()
+		if err != nil {
+			if cpuProfile != "" {
+				pprof.StopCPUProfile()
+			}
+			return nil, fmt.Errorf("iteration %d: %w", i+1, err)
+		}
+
+		parseDurations = append(parseDurations, parsed.Sub(start))
+		scheduleDurations = append(scheduleDurations, end.Sub(parsed))
+		totalDurations = append(totalDurations, end.Sub(start))
+	}
+
+	runtime.ReadMemStats(&after)
+	if cpuProfile != "" {
+		pprof.StopCPUProfile()
+	}
+
+	result.Latencies = []ProfileLatency{
+		profileLatency("Parse", parseDurations),
+		profileLatency("BuildSchedule", scheduleDurations),
+		profileLatency("Total", totalDurations),
+	}
+	result.BytesPerOp = (after.TotalAlloc - before.TotalAlloc) / uint64(iterations)
+	result.AllocsPerOp = (after.Mallocs - before.Mallocs) / uint64(iterations)
+
+	return result, nil
+}
+
+// profileLatency summarizes the latencies of a stage
+func profileLatency(stage string, durations []time.Duration) ProfileLatency {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+
+	return ProfileLatency{
+		Stage: stage,
+		Mean:  total / time.Duration(len(durations)),
+		P50:   durations[percentileIndex(len(durations), 0.50)],
+		P95:   durations[percentileIndex(len(durations), 0.95)],
+		P99:   durations[percentileIndex(len(durations), 0.99)],
+	}
+}
@@ -0,0 +1,108 @@
+package dev
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/scheduler"
+)
+
+// newSimulateCommand creates the pipeline simulate subcommand
+func newSimulateCommand() *cobra.Command {
+	var (
+		parallelism int
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "simulate [FILE]",
+		Short: "Estimate the wall-clock time of a pipeline",
+		Long: `Simulate the execution of a pipeline definition (default .c8s.yaml)
+and estimate how long it takes.
+
+Each step is assumed to run for its timeout (or the default step timeout)
+and to start as soon as its dependencies have finished. Use --parallelism
+to limit the number of steps running at once.`,
+		Example: `  # Estimate the wall-clock time of .c8s.yaml
+  c8s dev pipeline simulate
+
+  # Estimate with at most two steps running at once
+  c8s dev pipeline simulate .c8s.yaml --parallelism 2`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ".c8s.yaml"
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				printError("Failed to read %s: %v", path, err)
+				return exitWithCode(1)
+			}
+
+			spec, err := parser.ParseBytes(content)
+			if err != nil {
+				printError("%s: %v", path, err)
+				return exitWithCode(1)
+			}
+
+			schedule, err := scheduler.BuildSchedule(&c8sv1alpha1.PipelineConfig{Spec: *spec})
+			if err != nil {
+				printError("%s: %v", path, err)
+				return exitWithCode(1)
+			}
+
+			result := scheduler.Simulate(schedule, parallelism)
+
+			switch output {
+			case "json":
+				return formatJSON(result)
+			case "yaml":
+				return formatYAML(result)
+			default:
+				printSimulation(result)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&parallelism, "parallelism", 0,
+		"Maximum number of steps running at once (0 for no limit)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// printSimulation prints the simulated timeline of each step in start order
+func printSimulation(result *scheduler.SimulationResult) {
+	names := make([]string, 0, len(result.WallClockByStep))
+	for name := range result.WallClockByStep {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := result.WallClockByStep[names[i]], result.WallClockByStep[names[j]]
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Printf("%-30s %10s %10s\n", "STEP", "START", "END")
+	for _, name := range names {
+		window := result.WallClockByStep[name]
+		fmt.Printf("%-30s %10s %10s\n", name, window[0].Round(time.Second), window[1].Round(time.Second))
+	}
+
+	fmt.Println()
+	fmt.Printf("Critical path: %s\n", strings.Join(result.CriticalPath, " → "))
+	fmt.Printf("Estimated wall-clock time: %s\n", result.EstimatedDuration.Round(time.Second))
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/org/c8s/pkg/localenv/fuzz"
 	"github.com/org/c8s/pkg/localenv/samples"
+	"github.com/org/c8s/pkg/webhook"
 	"github.com/spf13/cobra"
 )
 
@@ -24,11 +27,79 @@ This command manages all aspects of pipeline testing:
 - Monitoring pipeline execution status
 - Collecting metrics and debugging information
 
-Use 'c8s dev test run' to execute tests and 'c8s dev test logs' to view results.`,
+Use 'c8s dev test generate' to create sample pipelines, 'c8s dev test run'
+to execute tests and 'c8s dev test logs' to view results. 'c8s dev test fuzz'
+fuzz-tests the pipeline parser and 'c8s dev test lint-all' validates the
+pipeline files of a repository; neither needs a cluster.`,
 	}
 
+	cmd.AddCommand(newTestGenerateCommand())
 	cmd.AddCommand(newTestRunCommand())
 	cmd.AddCommand(newTestLogsCommand())
+	cmd.AddCommand(newTestCleanCommand())
+	cmd.AddCommand(newTestFuzzCommand())
+	cmd.AddCommand(newTestReplayCommand())
+	cmd.AddCommand(newTestMockWebhookCommand())
+	cmd.AddCommand(newTestParallelCommand())
+	cmd.AddCommand(newTestBaselineCommand())
+	cmd.AddCommand(newTestLintAllCommand())
+
+	return cmd
+}
+
+// newTestGenerateCommand creates the test generate subcommand
+func newTestGenerateCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+		sampleNames []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Create sample PipelineConfigs for testing",
+		Long: `Apply built-in sample PipelineConfigs to a cluster.
+
+The samples cover common test scenarios so that 'c8s dev test run' has
+pipelines to execute:
+  simple-build  single step that succeeds
+  multi-step    steps chained with dependsOn, including a fan-in
+  matrix        build and test across an os/version matrix
+  failing       a step that exits non-zero, blocking its dependents
+
+Example:
+  c8s dev test generate --cluster c8s-dev
+  c8s dev test generate --samples simple-build,matrix
+  c8s dev test generate --namespace pipelines`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Generating test samples on cluster %q\n", clusterName)
+			}
+
+			status, err := samples.GenerateTestSamples(namespace, sampleNames)
+			if err != nil {
+				return fmt.Errorf("failed to generate test samples: %w", err)
+			}
+
+			fmt.Println(status.Message)
+			fmt.Println()
+			fmt.Println("Trigger a sample with:")
+			for _, name := range status.SamplesDeployed {
+				fmt.Printf("  %-14s c8s dev test run --cluster %s --namespace %s --pipeline %s\n",
+					name, clusterName, status.Namespace, name)
+			}
+
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster to create samples on")
+	cmd.Flags().StringVar(&namespace, "namespace", "default",
+		"Kubernetes namespace to create samples in")
+	cmd.Flags().StringSliceVar(&sampleNames, "samples", nil,
+		"Comma-separated list of samples to create (default: all)")
 
 	return cmd
 }
@@ -42,6 +113,8 @@ func newTestRunCommand() *cobra.Command {
 		timeout        int
 		watch          bool
 		outputFormat   string
+		coverage       bool
+		reportFile     string
 	)
 
 	cmd := &cobra.Command{
@@ -57,10 +130,16 @@ This command:
 
 The operator must be deployed before running tests.
 
+With --coverage, coverage.out artifacts produced by steps running
+'go test -coverprofile=coverage.out' are downloaded from artifact storage,
+merged, and rendered as an HTML report. The report is opened in the browser
+unless --report-file is given.
+
 Example:
   c8s dev test run --cluster c8s-dev
   c8s dev test run --pipeline simple-build --watch
-  c8s dev test run --output json`,
+  c8s dev test run --output json
+  c8s dev test run --coverage --report-file coverage.html`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			if ctx == nil {
@@ -79,6 +158,12 @@ Example:
 				return fmt.Errorf("failed to run pipeline tests: %w", err)
 			}
 
+			if coverage {
+				if err := collectTestCoverage(ctx, summary, namespace, reportFile); err != nil {
+					return err
+				}
+			}
+
 			// Format and display results
 			return displayTestResults(summary, outputFormat, watch)
 		},
@@ -97,10 +182,39 @@ Example:
 		"Watch test progress in real-time")
 	cmd.Flags().StringVar(&outputFormat, "output", "text",
 		"Output format: text, json, yaml")
+	cmd.Flags().BoolVar(&coverage, "coverage", false,
+		"Collect and merge Go coverage profiles from step artifacts")
+	cmd.Flags().StringVar(&reportFile, "report-file", "",
+		"Save the HTML coverage report to this file instead of opening it in the browser")
 
 	return cmd
 }
 
+// collectTestCoverage merges coverage profiles from the runs in summary and
+// either opens the HTML report or leaves it at reportFile
+func collectTestCoverage(ctx context.Context, summary *samples.PipelineTestSummary, namespace string, reportFile string) error {
+	var runNames []string
+	for _, result := range summary.Results {
+		if result.RunName != "" {
+			runNames = append(runNames, result.RunName)
+		}
+	}
+
+	report, err := samples.CollectCoverage(ctx, namespace, runNames, reportFile)
+	if err != nil {
+		return fmt.Errorf("failed to collect coverage: %w", err)
+	}
+	summary.Coverage = report
+
+	if report.ReportFile != "" && reportFile == "" {
+		if err := samples.OpenInBrowser(report.ReportFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v (report saved to %s)\n", err, report.ReportFile)
+		}
+	}
+
+	return nil
+}
+
 // newTestLogsCommand creates the test logs subcommand
 func newTestLogsCommand() *cobra.Command {
 	var (
@@ -181,6 +295,815 @@ Example:
 	return cmd
 }
 
+// newTestCleanCommand creates the test clean subcommand
+func newTestCleanCommand() *cobra.Command {
+	var (
+		clusterName string
+		namespace   string
+		olderThan   time.Duration
+		dryRun      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Delete PipelineRuns and Jobs created by pipeline tests",
+		Long: `Delete the PipelineRuns created by 'c8s dev test run' and their Jobs.
+
+Only objects labeled c8s.dev/created-by=c8s-test are deleted; PipelineConfigs
+and runs created by other means are kept. Use 'c8s dev cluster reset' to
+remove all pipeline state instead.
+
+Example:
+  c8s dev test clean
+  c8s dev test clean --older-than 24h
+  c8s dev test clean --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := samples.NewClusterClient(clusterName)
+			if err != nil {
+				return err
+			}
+
+			result, err := samples.CleanTestRuns(ctx, c, samples.CleanOptions{
+				Namespace: namespace,
+				OlderThan: olderThan,
+				DryRun:    dryRun,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to clean test runs: %w", err)
+			}
+
+			verb := "Deleted"
+			if dryRun {
+				verb = "Would delete"
+			}
+			for _, name := range result.PipelineRuns {
+				fmt.Printf("%s pipelinerun/%s\n", verb, name)
+			}
+			for _, name := range result.Jobs {
+				fmt.Printf("%s job/%s\n", verb, name)
+			}
+			fmt.Printf("%s %d PipelineRuns and %d Jobs in namespace %s\n",
+				verb, len(result.PipelineRuns), len(result.Jobs), namespace)
+
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster")
+	cmd.Flags().StringVar(&namespace, "namespace", "default",
+		"Kubernetes namespace to clean")
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0,
+		"Only delete runs completed more than this long ago (e.g. 24h)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Print what would be deleted without deleting it")
+
+	return cmd
+}
+
+// newTestFuzzCommand creates the test fuzz subcommand
+func newTestFuzzCommand() *cobra.Command {
+	var (
+		duration     time.Duration
+		target       string
+		pkg          string
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fuzz",
+		Short: "Fuzz-test the pipeline parser",
+		Long: `Fuzz the pipeline parser with random inputs using 'go test -fuzz'.
+
+Pipeline YAML comes from repositories and webhook payloads, so the parser
+must return an error rather than panic on any input. The fuzzer starts from
+the corpus in tests/fuzz/testdata/fuzz/FuzzParse and mutates it for the
+given duration.
+
+Inputs that crash the parser are written to the corpus directory. Commit
+them with the fix: plain 'go test ./tests/fuzz' replays every corpus file.
+
+Must be run from the root of the c8s source tree, with Go installed.
+
+Example:
+  c8s dev test fuzz
+  c8s dev test fuzz --duration 10m
+  c8s dev test fuzz --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			opts := fuzz.Options{
+				Package:  pkg,
+				Target:   target,
+				Duration: duration,
+			}
+			if outputFormat == "text" {
+				printInfo("Fuzzing %s for %s...", target, duration)
+				if verbose {
+					opts.Output = os.Stderr
+				}
+			}
+
+			result, err := fuzz.Run(ctx, opts)
+			if err != nil {
+				return err
+			}
+
+			switch outputFormat {
+			case "json":
+				if err := formatJSON(result); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(result); err != nil {
+					return err
+				}
+			default:
+				if len(result.Crashes) == 0 {
+					printSuccess("No crashes found in %s", result.Duration)
+				}
+				for _, crash := range result.Crashes {
+					printError("%s: %s", crash.Input, crash.Message)
+				}
+			}
+
+			if len(result.Crashes) > 0 {
+				return fmt.Errorf("fuzzing found %d crashing inputs", len(result.Crashes))
+			}
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().DurationVar(&duration, "duration", time.Minute,
+		"How long to fuzz for (e.g. 30s, 10m)")
+	cmd.Flags().StringVar(&target, "target", fuzz.DefaultTarget,
+		"Fuzz test to run")
+	cmd.Flags().StringVar(&pkg, "package", fuzz.DefaultPackage,
+		"Package containing the fuzz test")
+	cmd.Flags().StringVar(&outputFormat, "output", "text",
+		"Output format: text, json, yaml")
+
+	return cmd
+}
+
+// newTestReplayCommand creates the test replay subcommand
+func newTestReplayCommand() *cobra.Command {
+	var (
+		clusterName  string
+		sessionPath  string
+		host         string
+		port         int
+		timeout      time.Duration
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay a saved test session against the local operator",
+		Long: `Replay the webhook events of a saved test session and check that the
+operator creates the same PipelineRuns, executes the same steps and reaches
+the same terminal phases, without pushing to a git repository.
+
+The session file (JSON) holds the PipelineConfig under test (pipelineConfig),
+optionally the RepositoryConnection routing events to it, the events and the
+expected runs, identified by commit. Each event is either a recorded delivery
+(headers and payload, sent as-is) or a synthetic push (push), built like
+'c8s dev webhook test'.
+
+The PipelineConfig and RepositoryConnection are applied first, and runs of
+the expected commits left by an earlier replay are deleted. The command waits
+for every expected run to finish, then exits with code 1 if any run differs
+or an unexpected run was created.
+
+Example:
+  c8s dev test replay --session session.json
+  c8s dev test replay --session session.json --port 9090 --timeout 5m
+  c8s dev test replay --session session.json --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			session, err := samples.LoadReplaySession(sessionPath)
+			if err != nil {
+				return err
+			}
+
+			c, err := samples.NewClusterClient(clusterName)
+			if err != nil {
+				return err
+			}
+
+			if outputFormat == "text" {
+				printInfo("Replaying %d events from %s...", len(session.Events), sessionPath)
+			}
+			result, replayErr := samples.Replay(ctx, c, session, samples.ReplayOptions{
+				WebhookURL: fmt.Sprintf("http://%s:%d", host, port),
+				Timeout:    timeout,
+			})
+			if result == nil {
+				return fmt.Errorf("failed to replay session: %w", replayErr)
+			}
+
+			switch outputFormat {
+			case "json":
+				if err := formatJSON(result); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(result); err != nil {
+					return err
+				}
+			default:
+				displayReplayResult(result)
+			}
+
+			if replayErr != nil {
+				return replayErr
+			}
+			if !result.Passed {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster")
+	cmd.Flags().StringVar(&sessionPath, "session", "",
+		"Session file to replay")
+	cmd.Flags().StringVar(&host, "host", "localhost",
+		"Host of the webhook service")
+	cmd.Flags().IntVar(&port, "port", 8080,
+		"Port of the webhook service")
+	cmd.Flags().DurationVar(&timeout, "timeout", samples.DefaultReplayTimeout,
+		"How long to wait for the replayed runs to finish")
+	cmd.Flags().StringVar(&outputFormat, "output", "text",
+		"Output format: text, json, yaml")
+	_ = cmd.MarkFlagRequired("session")
+
+	return cmd
+}
+
+// newTestMockWebhookCommand creates the test mock-webhook subcommand
+func newTestMockWebhookCommand() *cobra.Command {
+	var (
+		clusterName    string
+		namespace      string
+		rateValue      string
+		duration       time.Duration
+		provider       string
+		repo           string
+		repoURL        string
+		branch         string
+		secret         string
+		duplicateRatio float64
+		host           string
+		port           int
+		settle         time.Duration
+		outputFormat   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mock-webhook",
+		Short: "Load test the webhook service with synthetic push events",
+		Long: `Send synthetic push events to the webhook service at a fixed rate and
+measure how it keeps up: the events accepted per second, the error rate and
+the latency from each event to the creation of its PipelineRun.
+
+Each event pushes a new random commit to --repo, built like
+'c8s dev webhook test', so a RepositoryConnection in --namespace must match
+the repository. With --duplicate-ratio, that fraction of events resends an
+earlier commit, which must not create another run.
+
+After --duration, the command waits up to --settle for the runs of the
+accepted events. Latency is measured by listing the runs every 250ms. Exits
+with code 1 if an accepted event got no PipelineRun.
+
+Example:
+  c8s dev test mock-webhook --repo example-org/example-repo --rate 10/s --duration 60s
+  c8s dev test mock-webhook --provider gitlab --repo group/project --rate 300/m --secret s3cr3t
+  c8s dev test mock-webhook --repo example-org/example-repo --duplicate-ratio 0.2 --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			limit, err := samples.ParseRate(rateValue)
+			if err != nil {
+				return err
+			}
+			if duplicateRatio < 0 || duplicateRatio >= 1 {
+				return fmt.Errorf("--duplicate-ratio must be at least 0 and below 1")
+			}
+
+			c, err := samples.NewClusterClient(clusterName)
+			if err != nil {
+				return err
+			}
+
+			if outputFormat == "text" {
+				printInfo("Sending %s %s push events to %s:%d for %s...", rateValue, provider, host, port, duration)
+			}
+			result, err := samples.MockWebhook(ctx, c, samples.MockWebhookOptions{
+				WebhookURL:     fmt.Sprintf("http://%s:%d", host, port),
+				Provider:       provider,
+				Repository:     repo,
+				RepositoryURL:  repoURL,
+				Branch:         branch,
+				Secret:         secret,
+				Namespace:      namespace,
+				Rate:           limit,
+				Duration:       duration,
+				DuplicateRatio: duplicateRatio,
+				Settle:         settle,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to load test the webhook service: %w", err)
+			}
+
+			switch outputFormat {
+			case "json":
+				if err := formatJSON(result); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(result); err != nil {
+					return err
+				}
+			default:
+				displayMockWebhookResult(result)
+			}
+
+			if result.MissingRuns > 0 {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default",
+		"Namespace the RepositoryConnection creates PipelineRuns in")
+	cmd.Flags().StringVar(&rateValue, "rate", "10/s",
+		"Events sent per second, minute or hour (e.g., 10/s, 300/m)")
+	cmd.Flags().DurationVar(&duration, "duration", time.Minute,
+		"How long to send events")
+	cmd.Flags().StringVar(&provider, "provider", webhook.ProviderGitHub,
+		"Git provider payload format: github, gitlab, bitbucket")
+	cmd.Flags().StringVar(&repo, "repo", "",
+		"Repository path (owner/repo)")
+	cmd.Flags().StringVar(&repoURL, "repo-url", "",
+		"Repository clone URL in the payload (default: provider HTTPS URL for --repo)")
+	cmd.Flags().StringVar(&branch, "branch", "main",
+		"Branch that was pushed")
+	cmd.Flags().StringVar(&secret, "secret", "",
+		"Webhook secret used to sign the payloads")
+	cmd.Flags().Float64Var(&duplicateRatio, "duplicate-ratio", 0,
+		"Fraction of events resending an earlier commit")
+	cmd.Flags().StringVar(&host, "host", "localhost",
+		"Host of the webhook service")
+	cmd.Flags().IntVar(&port, "port", 8080,
+		"Port of the webhook service")
+	cmd.Flags().DurationVar(&settle, "settle", samples.DefaultMockWebhookSettle,
+		"How long to wait for PipelineRuns after the last event")
+	cmd.Flags().StringVar(&outputFormat, "output", "text",
+		"Output format: text, json, yaml")
+	_ = cmd.MarkFlagRequired("repo")
+
+	return cmd
+}
+
+// newTestParallelCommand creates the test parallel subcommand
+func newTestParallelCommand() *cobra.Command {
+	var (
+		clusterName    string
+		suitePaths     []string
+		parallelism    int
+		timeout        time.Duration
+		keepNamespaces bool
+		outputFormat   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "parallel",
+		Short: "Run several pipeline test suites concurrently",
+		Long: `Run the pipeline test suites of several suite files at the same time, each
+in its own namespace, and report the pass/fail counts of every suite and a
+combined summary.
+
+A suite file (YAML or JSON) has a name (default: the file name) and a list
+of pipelines, each with a name, a PipelineConfig spec and the expected
+outcome of its run: the phase (default Succeeded) and optionally the phase
+of each step. Each suite runs in a namespace named after the suite and the
+start time, where its PipelineConfigs are created and run once. The
+namespace is deleted once the suite finishes unless --keep-namespaces is
+set.
+
+The operator must watch all namespaces. The command exits with code 1 if
+any pipeline differs from its expected outcome or a suite fails to run.
+
+Example:
+  c8s dev test parallel --suites build.yaml,deploy.yaml
+  c8s dev test parallel --suites build.yaml --suites deploy.yaml --parallelism 2
+  c8s dev test parallel --suites build.yaml --keep-namespaces --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			suites := make([]*samples.TestSuite, 0, len(suitePaths))
+			for _, path := range suitePaths {
+				suite, err := samples.LoadTestSuite(path)
+				if err != nil {
+					return err
+				}
+				suites = append(suites, suite)
+			}
+
+			c, err := samples.NewClusterClient(clusterName)
+			if err != nil {
+				return err
+			}
+
+			if outputFormat == "text" {
+				printInfo("Running %d suites on cluster '%s'...", len(suites), clusterName)
+			}
+			summary := samples.RunTestSuites(ctx, c, suites, samples.SuiteOptions{
+				Timeout:        timeout,
+				Parallelism:    parallelism,
+				KeepNamespaces: keepNamespaces,
+			})
+
+			switch outputFormat {
+			case "json":
+				if err := formatJSON(summary); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(summary); err != nil {
+					return err
+				}
+			default:
+				displayParallelTestSummary(summary)
+			}
+
+			if !summary.Succeeded() {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster")
+	cmd.Flags().StringSliceVar(&suitePaths, "suites", nil,
+		"Suite files to run (comma-separated or repeated)")
+	cmd.Flags().IntVar(&parallelism, "parallelism", 0,
+		"Maximum number of suites running at once (0 runs all at once)")
+	cmd.Flags().DurationVar(&timeout, "timeout", samples.DefaultSuiteTimeout,
+		"How long each suite waits for its runs to finish")
+	cmd.Flags().BoolVar(&keepNamespaces, "keep-namespaces", false,
+		"Keep the suite namespaces after the suites finish")
+	cmd.Flags().StringVar(&outputFormat, "output", "text",
+		"Output format: text, json, yaml")
+	_ = cmd.MarkFlagRequired("suites")
+
+	return cmd
+}
+
+// displayParallelTestSummary prints the results of each suite followed by
+// the combined summary
+func displayParallelTestSummary(summary *samples.ParallelTestSummary) {
+	for _, suite := range summary.Suites {
+		fmt.Printf("\n%s (%s): %d passed, %d failed in %s\n", suite.Suite, suite.Namespace,
+			suite.Passed, suite.Failed, suite.Duration.Round(time.Second))
+		if suite.Error != "" {
+			printError("  %s", suite.Error)
+		}
+		for _, pipeline := range suite.Pipelines {
+			status := "✓"
+			if !pipeline.Passed {
+				status = "✗"
+			}
+			fmt.Printf("  %s %s\n", status, pipeline.Name)
+			for _, difference := range pipeline.Differences {
+				fmt.Printf("      %s\n", difference)
+			}
+		}
+	}
+
+	fmt.Printf("\n%s\n", strings.Repeat("=", 70))
+	fmt.Printf("Suites:   %d\n", len(summary.Suites))
+	fmt.Printf("Passed:   %d/%d\n", summary.Passed, summary.Total)
+	fmt.Printf("Failed:   %d\n", summary.Failed)
+	fmt.Printf("Duration: %s (%s sequentially)\n", summary.Duration.Round(time.Second), summary.SequentialDuration.Round(time.Second))
+
+	if summary.Succeeded() {
+		printSuccess("All suites passed")
+	} else {
+		printError("Some suites failed")
+	}
+}
+
+// newTestBaselineCommand creates the test baseline subcommand
+func newTestBaselineCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Record and compare pipeline test baselines",
+		Long: `Record the results of passing pipeline tests as a baseline and compare
+later test runs with it to detect performance regressions.
+
+'c8s dev test baseline save' runs the pipeline tests like 'c8s dev test run'
+and saves the duration, peak memory and CPU usage, and log size of every
+step of the pipelines that passed. 'c8s dev test baseline compare' runs the
+tests again and reports steps that are more than 20% slower, use more than
+50% more memory, or fail where they passed in the baseline.
+
+Resource usage is sampled from metrics-server ('c8s dev cluster addons
+install metrics-server') and left out of the baseline when it is not
+installed.`,
+	}
+
+	cmd.AddCommand(newTestBaselineSaveCommand())
+	cmd.AddCommand(newTestBaselineCompareCommand())
+
+	return cmd
+}
+
+// newTestBaselineSaveCommand creates the test baseline save subcommand
+func newTestBaselineSaveCommand() *cobra.Command {
+	var (
+		clusterName    string
+		namespace      string
+		pipelineFilter string
+		timeout        time.Duration
+		outputFile     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "save",
+		Short: "Run the pipeline tests and save the passing results as a baseline",
+		Long: `Run the pipeline tests of a namespace and save the results of the pipelines
+that passed to a JSON baseline file. Failing pipelines are left out of the
+baseline with a warning.
+
+Example:
+  c8s dev test baseline save --output baseline.json
+  c8s dev test baseline save --pipeline simple-build --output build.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := samples.NewClusterClient(clusterName)
+			if err != nil {
+				return err
+			}
+
+			printInfo("Running pipeline tests on cluster '%s'...", clusterName)
+			recorded, err := samples.RecordBaseline(ctx, c, samples.BaselineOptions{
+				Namespace: namespace,
+				Pipeline:  pipelineFilter,
+				Timeout:   timeout,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to run pipeline tests: %w", err)
+			}
+
+			for _, pipeline := range recorded.Pipelines {
+				if pipeline.Status != "Success" {
+					printWarning("%s: %s, left out of the baseline", pipeline.Name, pipeline.Status)
+				}
+			}
+			baseline := recorded.Passing()
+			if len(baseline.Pipelines) == 0 {
+				return fmt.Errorf("no pipeline tests passed, nothing to save")
+			}
+
+			if err := samples.SaveBaseline(outputFile, baseline); err != nil {
+				return err
+			}
+			printSuccess("Saved baseline of %d pipelines to %s", len(baseline.Pipelines), outputFile)
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster to run tests on")
+	cmd.Flags().StringVar(&namespace, "namespace", "default",
+		"Kubernetes namespace containing pipelines")
+	cmd.Flags().StringVar(&pipelineFilter, "pipeline", "",
+		"Run only pipelines matching this name")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute,
+		"Timeout of each pipeline test")
+	cmd.Flags().StringVar(&outputFile, "output", "baseline.json",
+		"File to save the baseline to")
+
+	return cmd
+}
+
+// newTestBaselineCompareCommand creates the test baseline compare subcommand
+func newTestBaselineCompareCommand() *cobra.Command {
+	var (
+		clusterName       string
+		baselineFile      string
+		pipelineFilter    string
+		timeout           time.Duration
+		durationThreshold float64
+		memoryThreshold   float64
+		outputFormat      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Run the pipeline tests and compare the results with a baseline",
+		Long: `Run the pipeline tests again and compare the results with a baseline saved
+by 'c8s dev test baseline save'. A step regresses when it fails where it
+passed in the baseline, takes more than --duration-threshold longer (and at
+least a second longer) or uses more than --memory-threshold more memory.
+
+The tests run in the namespace of the baseline. The command exits with
+code 1 if any regression is found, so it can gate CI jobs.
+
+Example:
+  c8s dev test baseline compare --baseline baseline.json
+  c8s dev test baseline compare --baseline baseline.json --duration-threshold 0.5
+  c8s dev test baseline compare --baseline baseline.json --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			baseline, err := samples.LoadBaseline(baselineFile)
+			if err != nil {
+				return err
+			}
+
+			c, err := samples.NewClusterClient(clusterName)
+			if err != nil {
+				return err
+			}
+
+			if outputFormat == "text" {
+				printInfo("Running pipeline tests on cluster '%s'...", clusterName)
+			}
+			current, err := samples.RecordBaseline(ctx, c, samples.BaselineOptions{
+				Namespace: baseline.Namespace,
+				Pipeline:  pipelineFilter,
+				Timeout:   timeout,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to run pipeline tests: %w", err)
+			}
+
+			comparison := samples.CompareBaselines(baseline, current, samples.CompareOptions{
+				DurationThreshold: durationThreshold,
+				MemoryThreshold:   memoryThreshold,
+			})
+
+			switch outputFormat {
+			case "json":
+				if err := formatJSON(comparison); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(comparison); err != nil {
+					return err
+				}
+			default:
+				displayBaselineComparison(comparison)
+			}
+
+			if !comparison.Passed() {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster to run tests on")
+	cmd.Flags().StringVar(&baselineFile, "baseline", "baseline.json",
+		"Baseline file saved by 'c8s dev test baseline save'")
+	cmd.Flags().StringVar(&pipelineFilter, "pipeline", "",
+		"Run only pipelines matching this name")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute,
+		"Timeout of each pipeline test")
+	cmd.Flags().Float64Var(&durationThreshold, "duration-threshold", samples.DefaultDurationThreshold,
+		"Relative increase of a step duration reported as a regression")
+	cmd.Flags().Float64Var(&memoryThreshold, "memory-threshold", samples.DefaultMemoryThreshold,
+		"Relative increase of the peak memory of a step reported as a regression")
+	cmd.Flags().StringVar(&outputFormat, "output", "text",
+		"Output format: text, json, yaml")
+
+	return cmd
+}
+
+// displayBaselineComparison prints the regressions found against a baseline
+func displayBaselineComparison(comparison *samples.BaselineComparison) {
+	for _, regression := range comparison.Regressions {
+		name := regression.Pipeline
+		if regression.Step != "" {
+			name = fmt.Sprintf("%s/%s", regression.Pipeline, regression.Step)
+		}
+		printError("%s: %s (%s)", name, regression.Message, regression.Kind)
+	}
+
+	fmt.Printf("\nCompared %d pipelines and %d steps with the baseline\n", comparison.Pipelines, comparison.Steps)
+	if comparison.Passed() {
+		printSuccess("No regressions found")
+	} else {
+		printError("%d regressions found", len(comparison.Regressions))
+	}
+}
+
+// displayReplayResult prints the comparison of each replayed run
+func displayReplayResult(result *samples.ReplayResult) {
+	for _, run := range result.Runs {
+		name := run.RunName
+		if name == "" {
+			name = "-"
+		}
+		if len(run.Differences) == 0 {
+			printSuccess("%s %s: %s", run.Commit[:8], name, run.Phase)
+			continue
+		}
+		printError("%s %s:", run.Commit[:8], name)
+		for _, difference := range run.Differences {
+			fmt.Printf("    %s\n", difference)
+		}
+	}
+	for _, name := range result.Unexpected {
+		printError("unexpected PipelineRun %s", name)
+	}
+
+	if result.Passed {
+		printSuccess("Replay matched the session")
+	} else {
+		printError("Replay did not match the session")
+	}
+}
+
+// displayMockWebhookResult prints the report of a webhook load test
+func displayMockWebhookResult(result *samples.MockWebhookResult) {
+	fmt.Printf("Events:      %d sent (%d duplicates) in %s\n", result.Sent, result.Duplicates, result.Duration.Round(time.Millisecond))
+	fmt.Printf("Accepted:    %d (%.1f/s)\n", result.Accepted, result.Throughput)
+	fmt.Printf("Errors:      %d rejected, %d failed (%.1f%%)\n", result.Rejected, result.Failed, result.ErrorRate*100)
+	codes := make([]int, 0, len(result.StatusCodes))
+	for code := range result.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Printf("  HTTP %d: %d\n", code, result.StatusCodes[code])
+	}
+	fmt.Printf("Runs:        %d created (%.1f/s)\n", result.RunsCreated, result.RunRate)
+	if result.RunsCreated > 0 {
+		latency := result.Latency
+		fmt.Printf("Latency:     min %s, median %s, p95 %s, p99 %s, max %s\n",
+			latency.Min.Round(time.Millisecond), latency.Median.Round(time.Millisecond),
+			latency.P95.Round(time.Millisecond), latency.P99.Round(time.Millisecond), latency.Max.Round(time.Millisecond))
+	}
+	for _, message := range result.Errors {
+		printError("%s", message)
+	}
+
+	if result.MissingRuns > 0 {
+		printError("%d accepted events got no PipelineRun", result.MissingRuns)
+	} else {
+		printSuccess("Every accepted event created a PipelineRun")
+	}
+}
+
 // displayTestResults formats and displays test results
 func displayTestResults(summary *samples.PipelineTestSummary, format string, watch bool) error {
 	switch format {
@@ -205,6 +1128,13 @@ func displayTestResultsText(summary *samples.PipelineTestSummary) error {
 	fmt.Printf("  Failed:         %d ✗\n", summary.FailedTests)
 	fmt.Printf("  Timeout:        %d ⏱\n", summary.TimeoutTests)
 	fmt.Printf("  Total Duration: %v\n", summary.Duration)
+	if summary.Coverage != nil {
+		if summary.Coverage.ReportFile != "" {
+			fmt.Printf("  Coverage:       %.1f%% (%s)\n", summary.Coverage.Percent, summary.Coverage.ReportFile)
+		} else {
+			fmt.Printf("  Coverage:       %s\n", summary.Coverage.Message)
+		}
+	}
 
 	fmt.Printf("\nResults:\n")
 	for i, result := range summary.Results {
@@ -288,3 +1218,94 @@ results:
 
 	return nil
 }
+
+// newTestLintAllCommand creates the test lint-all subcommand
+func newTestLintAllCommand() *cobra.Command {
+	var (
+		dir            string
+		ignorePatterns []string
+		output         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint-all",
+		Short: "Validate every pipeline file under a directory",
+		Long: `Walk a directory tree and parse and validate every pipeline file
+(.c8s.yaml or .c8s.yml, including prefixed names such as api.c8s.yaml), then
+print a summary listing each invalid file with its errors.
+
+Directories and files whose name or path relative to --dir matches an
+--ignore-pattern glob are skipped, as are .git directories. Exits with
+code 1 if any file is invalid, which makes it usable as a pre-commit hook
+or CI gate for monorepos.`,
+		Example: `  # Validate all pipeline files of the repository
+  c8s dev test lint-all
+
+  # Skip vendored and test fixture directories
+  c8s dev test lint-all --dir . --ignore-pattern vendor --ignore-pattern 'testdata/*'
+
+  # Output the report as JSON
+  c8s dev test lint-all --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := samples.LintPipelineFiles(dir, ignorePatterns)
+			if err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+
+			switch output {
+			case "json":
+				if err := formatJSON(result); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(result); err != nil {
+					return err
+				}
+			default:
+				displayLintAllResult(result)
+			}
+
+			if result.Failed > 0 {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", ".", "Root directory to search for pipeline files")
+	cmd.Flags().StringArrayVar(&ignorePatterns, "ignore-pattern", nil,
+		"Glob of directories or files to skip, matched against names and relative paths (repeatable)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// displayLintAllResult prints the invalid pipeline files and a summary
+func displayLintAllResult(result *samples.LintResult) {
+	if len(result.Files) == 0 {
+		printWarning("No pipeline files found under %s", result.Root)
+		return
+	}
+
+	for _, file := range result.Files {
+		if file.Valid {
+			if IsVerbose() {
+				printSuccess("%s (%d steps)", file.Path, file.Steps)
+			}
+			continue
+		}
+		printError("%s", file.Path)
+		for _, message := range file.Errors {
+			fmt.Fprintf(os.Stderr, "    %s\n", message)
+		}
+	}
+
+	summary := fmt.Sprintf("%d pipeline files checked: %d passed, %d failed", len(result.Files), result.Passed, result.Failed)
+	if result.Failed > 0 {
+		printError("%s", summary)
+		return
+	}
+	printSuccess("%s", summary)
+}
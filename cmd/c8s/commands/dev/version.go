@@ -0,0 +1,209 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/deploy"
+	"github.com/org/c8s/pkg/version"
+)
+
+// versionCheckResult is the outcome of comparing the CLI with the cluster
+type versionCheckResult struct {
+	ClientVersion     string   `json:"clientVersion" yaml:"clientVersion"`
+	OperatorImage     string   `json:"operatorImage" yaml:"operatorImage"`
+	OperatorVersion   string   `json:"operatorVersion" yaml:"operatorVersion"`
+	SupportedVersions []string `json:"supportedAPIVersions" yaml:"supportedAPIVersions"`
+	ServedVersions    []string `json:"servedAPIVersions" yaml:"servedAPIVersions"`
+	LatestVersion     string   `json:"latestVersion,omitempty" yaml:"latestVersion,omitempty"`
+	DownloadURL       string   `json:"downloadURL,omitempty" yaml:"downloadURL,omitempty"`
+	Warnings          []string `json:"warnings" yaml:"warnings"`
+}
+
+// newVersionCommand creates the version subcommand
+func newVersionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show and check c8s versions",
+		Long:  `Show the c8s CLI version and check it against the operator deployed to a local cluster.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Println(version.Version)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newVersionCheckCommand())
+
+	return cmd
+}
+
+// newVersionCheckCommand creates the version check subcommand
+func newVersionCheckCommand() *cobra.Command {
+	var (
+		flags       operatorFlags
+		updateCheck bool
+		releasesURL string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Compare the CLI version with the cluster operator",
+		Long: `Compare the version of this c8s binary with the image tag of the operator
+Deployment and with the c8s.dev CRD versions served by the cluster.
+
+A warning is printed when the major or minor versions differ, or when the
+cluster serves no CRD version this binary supports. With --update-check the
+latest release is fetched from GitHub and its download URL is printed when
+it is newer than this binary.`,
+		Example: `  # Check the CLI against the operator
+  c8s dev version check
+
+  # Also check for a newer release
+  c8s dev version check --update-check`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			client, err := deploy.NewClusterClientset(flags.clusterName)
+			if err != nil {
+				printError("Failed to connect to cluster '%s': %v", flags.clusterName, err)
+				return exitWithCode(1)
+			}
+
+			result := &versionCheckResult{
+				ClientVersion:     version.Version,
+				SupportedVersions: version.SupportedAPIVersions,
+				Warnings:          []string{},
+			}
+
+			image, err := deploy.OperatorImage(ctx, client, flags.namespace, flags.name)
+			if err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+			result.OperatorImage = image
+			result.OperatorVersion = deploy.ImageTag(image)
+			result.Warnings = append(result.Warnings, compareVersions(result.ClientVersion, result.OperatorVersion)...)
+
+			served, err := deploy.ServedAPIVersions(client, c8sv1alpha1.GroupVersion.Group)
+			if err != nil {
+				printError("%v", err)
+				return exitWithCode(1)
+			}
+			result.ServedVersions = served
+			result.Warnings = append(result.Warnings, compareAPIVersions(served)...)
+
+			if updateCheck {
+				httpClient := &http.Client{Timeout: 10 * time.Second}
+				release, err := version.LatestRelease(ctx, httpClient, releasesURL)
+				if err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("update check failed: %v", err))
+				} else {
+					result.LatestVersion = release.TagName
+					if isNewerRelease(result.ClientVersion, release.TagName) {
+						result.DownloadURL = release.HTMLURL
+					}
+				}
+			}
+
+			switch output {
+			case "json":
+				return formatJSON(result)
+			case "yaml":
+				return formatYAML(result)
+			default:
+				printVersionCheck(result)
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&updateCheck, "update-check", false,
+		"Check GitHub for a newer c8s release")
+	cmd.Flags().StringVar(&releasesURL, "releases-url", version.DefaultReleasesURL,
+		"GitHub API URL of the latest release")
+	cmd.Flags().StringVarP(&output, "output", "o", "text",
+		"Output format (text|json|yaml)")
+
+	return cmd
+}
+
+// compareVersions warns when the CLI and operator major/minor versions differ
+func compareVersions(clientVersion, operatorVersion string) []string {
+	compatible, err := version.Compatible(clientVersion, operatorVersion)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot compare CLI version %q with operator version %q", clientVersion, operatorVersion)}
+	}
+	if !compatible {
+		return []string{fmt.Sprintf("CLI version %s and operator version %s differ in major/minor version and may be incompatible",
+			clientVersion, operatorVersion)}
+	}
+	return nil
+}
+
+// compareAPIVersions warns when the cluster serves no CRD version the CLI supports
+func compareAPIVersions(served []string) []string {
+	if len(served) == 0 {
+		return []string{fmt.Sprintf("no %s CRDs are installed (run 'c8s dev deploy operator' first)", c8sv1alpha1.GroupVersion.Group)}
+	}
+	for _, v := range served {
+		if version.IsSupportedAPIVersion(v) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("cluster serves %s %v but this CLI supports %v",
+		c8sv1alpha1.GroupVersion.Group, served, version.SupportedAPIVersions)}
+}
+
+// isNewerRelease reports whether a release tag is newer than the CLI version.
+// Development builds are always considered out of date.
+func isNewerRelease(clientVersion, tag string) bool {
+	latest, err := version.Parse(tag)
+	if err != nil {
+		return false
+	}
+	current, err := version.Parse(clientVersion)
+	if err != nil {
+		return true
+	}
+	return current.Less(latest)
+}
+
+// printVersionCheck prints the version check in text format
+func printVersionCheck(result *versionCheckResult) {
+	fmt.Printf("CLI Version:       %s\n", result.ClientVersion)
+	fmt.Printf("Operator Version:  %s (%s)\n", valueOr(result.OperatorVersion, "unknown"), result.OperatorImage)
+	fmt.Printf("API Versions:      served %v, supported %v\n", result.ServedVersions, result.SupportedVersions)
+	if result.LatestVersion != "" {
+		fmt.Printf("Latest Release:    %s\n", result.LatestVersion)
+	}
+
+	for _, warning := range result.Warnings {
+		printWarning("%s", warning)
+	}
+
+	if result.DownloadURL != "" {
+		printInfo("A newer c8s version is available: %s", result.DownloadURL)
+	} else if result.LatestVersion != "" {
+		printSuccess("c8s is up to date")
+	}
+	if len(result.Warnings) == 0 {
+		printSuccess("CLI and cluster versions are compatible")
+	}
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
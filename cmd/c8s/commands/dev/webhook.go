@@ -0,0 +1,342 @@
+package dev
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/org/c8s/pkg/localenv/samples"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+// newWebhookCommand creates the webhook subcommand
+func newWebhookCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Exercise the webhook service locally",
+		Long: `Send synthetic git provider events to a locally reachable webhook service.
+
+Use 'c8s dev webhook test' to trigger pipelines without real git pushes or a
+public tunnel such as ngrok, and 'c8s dev webhook simulate-failure' to check
+how the service handles invalid deliveries.`,
+	}
+
+	cmd.AddCommand(newWebhookTestCommand())
+	cmd.AddCommand(newWebhookSimulateFailureCommand())
+
+	return cmd
+}
+
+// newWebhookTestCommand creates the webhook test subcommand
+func newWebhookTestCommand() *cobra.Command {
+	var (
+		provider string
+		event    string
+		repo     string
+		repoURL  string
+		branch   string
+		commit   string
+		message  string
+		author   string
+		secret   string
+		host     string
+		port     int
+		timeout  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Send a synthetic webhook payload to the webhook service",
+		Long: `Build a push event in the payload format of a git provider and POST it to
+http://<host>:<port>/webhooks/<provider>.
+
+The repository URL in the payload must match the spec.repository of a
+RepositoryConnection in the default namespace for a PipelineRun to be
+created. It defaults to the provider's HTTPS clone URL for --repo; use
+--repo-url to send a different URL.
+
+With --secret the payload is signed the way the provider signs deliveries
+(X-Hub-Signature-256 for GitHub, X-Hub-Signature for Bitbucket and
+X-Gitlab-Token for GitLab). The secret must match the RepositoryConnection's
+webhook secret. Without --commit a random SHA is used.`,
+		Example: `  # Send a GitHub push for main
+  c8s dev webhook test --repo example-org/example-repo
+
+  # Send a signed GitLab push for a feature branch
+  c8s dev webhook test --provider gitlab --repo group/project --branch feature/login --secret s3cr3t
+
+  # Target a port-forwarded webhook service
+  c8s dev webhook test --repo example-org/example-repo --port 9090`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if event != "push" {
+				printError("Unsupported event %q: only push events are supported", event)
+				return exitWithCode(1)
+			}
+
+			if commit == "" {
+				var err error
+				if commit, err = randomCommitSHA(); err != nil {
+					printError("Failed to generate commit SHA: %v", err)
+					return exitWithCode(1)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			url := fmt.Sprintf("http://%s:%d/webhooks/%s", host, port, provider)
+			req, err := webhook.NewTestPushRequest(ctx, url, provider, webhook.TestPushEvent{
+				Repository:    repo,
+				RepositoryURL: repoURL,
+				Branch:        branch,
+				Commit:        commit,
+				Message:       message,
+				Author:        author,
+				AuthorEmail:   author + "@example.com",
+			}, secret)
+			if err != nil {
+				printError("Failed to build webhook request: %v", err)
+				return exitWithCode(1)
+			}
+
+			if IsVerbose() {
+				printInfo("[DEBUG] POST %s (commit %s)", url, commit)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				printError("Failed to send webhook: %v", err)
+				printInfo("Check that the webhook service is reachable at %s:%d", host, port)
+				return exitWithCode(1)
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				printError("Failed to read response: %v", err)
+				return exitWithCode(1)
+			}
+
+			fmt.Printf("HTTP %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
+			if len(body) > 0 {
+				fmt.Println(string(body))
+			}
+
+			if resp.StatusCode >= 300 {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&provider, "provider", webhook.ProviderGitHub,
+		"Git provider payload format: github, gitlab, bitbucket")
+	cmd.Flags().StringVar(&event, "event", "push",
+		"Event type to send")
+	cmd.Flags().StringVar(&repo, "repo", "",
+		"Repository path (owner/repo)")
+	cmd.Flags().StringVar(&repoURL, "repo-url", "",
+		"Repository clone URL in the payload (default: provider HTTPS URL for --repo)")
+	cmd.Flags().StringVar(&branch, "branch", "main",
+		"Branch that was pushed")
+	cmd.Flags().StringVar(&commit, "commit", "",
+		"Commit SHA that was pushed (default: random)")
+	cmd.Flags().StringVar(&message, "message", "Test commit from c8s dev webhook test",
+		"Commit message")
+	cmd.Flags().StringVar(&author, "author", "c8s-dev",
+		"Commit author")
+	cmd.Flags().StringVar(&secret, "secret", "",
+		"Webhook secret used to sign the payload")
+	cmd.Flags().StringVar(&host, "host", "localhost",
+		"Host of the webhook service")
+	cmd.Flags().IntVar(&port, "port", 8080,
+		"Port of the webhook service")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second,
+		"Timeout for the webhook request")
+	_ = cmd.MarkFlagRequired("repo")
+
+	return cmd
+}
+
+// newWebhookSimulateFailureCommand creates the webhook simulate-failure subcommand
+func newWebhookSimulateFailureCommand() *cobra.Command {
+	var (
+		clusterName  string
+		namespace    string
+		errorType    string
+		count        int
+		provider     string
+		repo         string
+		repoURL      string
+		branch       string
+		secret       string
+		host         string
+		port         int
+		timeout      time.Duration
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "simulate-failure",
+		Short: "Send invalid webhook payloads and verify the service rejects them",
+		Long: `Send --count invalid push events of one kind to the webhook service and
+verify that it handles them:
+
+  signature-mismatch  signed with a wrong secret, expects HTTP 401
+  missing-field       push without its commit SHA, expects HTTP 400
+  malformed-json      truncated JSON body, expects HTTP 400
+  timeout             body that stops halfway, expects the service to give
+                      up reading after its 15s read timeout (HTTP 400 or 408,
+                      or a closed connection)
+
+The events are sent concurrently. The command then checks that no
+PipelineRun was created for them in --namespace, that the rejected events
+counter c8s_webhook_events_total on /metrics grew by --count, that /health
+still answers and that a valid push, signed with --secret, still creates a
+PipelineRun. A RepositoryConnection in --namespace must match --repo, and no
+other events should reach the service while the command runs.
+
+Exits with code 1 if any check fails.`,
+		Example: `  # Send 10 payloads signed with a wrong secret
+  c8s dev webhook simulate-failure --repo example-org/example-repo --secret s3cr3t
+
+  # Send 5 truncated GitLab payloads
+  c8s dev webhook simulate-failure --provider gitlab --repo group/project --error-type malformed-json --count 5
+
+  # Stall request bodies until the service times out
+  c8s dev webhook simulate-failure --repo example-org/example-repo --error-type timeout --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if _, err := samples.ExpectedFailureStatus(errorType); err != nil {
+				return err
+			}
+			if count <= 0 {
+				return fmt.Errorf("--count must be positive")
+			}
+
+			c, err := samples.NewClusterClient(clusterName)
+			if err != nil {
+				return err
+			}
+
+			if outputFormat == "text" {
+				printInfo("Sending %d %s %s events to %s:%d...", count, errorType, provider, host, port)
+			}
+			result, err := samples.SimulateWebhookFailures(ctx, c, samples.WebhookFailureOptions{
+				WebhookURL:    fmt.Sprintf("http://%s:%d", host, port),
+				Provider:      provider,
+				Repository:    repo,
+				RepositoryURL: repoURL,
+				Branch:        branch,
+				Secret:        secret,
+				Namespace:     namespace,
+				ErrorType:     errorType,
+				Count:         count,
+				Timeout:       timeout,
+			})
+			if err != nil {
+				printInfo("Check that the webhook service is reachable at %s:%d", host, port)
+				return fmt.Errorf("failed to simulate webhook failures: %w", err)
+			}
+
+			switch outputFormat {
+			case "json":
+				if err := formatJSON(result); err != nil {
+					return err
+				}
+			case "yaml":
+				if err := formatYAML(result); err != nil {
+					return err
+				}
+			default:
+				displayWebhookFailureResult(result)
+			}
+
+			if !result.Passed {
+				return exitWithCode(1)
+			}
+			return nil
+		},
+	}
+
+	// Flags
+	cmd.Flags().StringVar(&clusterName, "cluster", "c8s-dev",
+		"Name of the cluster")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default",
+		"Namespace the RepositoryConnection creates PipelineRuns in")
+	cmd.Flags().StringVar(&errorType, "error-type", samples.FailureSignatureMismatch,
+		"Kind of invalid event: "+strings.Join(samples.WebhookFailureTypes, ", "))
+	cmd.Flags().IntVar(&count, "count", 10,
+		"Number of invalid events to send")
+	cmd.Flags().StringVar(&provider, "provider", webhook.ProviderGitHub,
+		"Git provider payload format: github, gitlab, bitbucket")
+	cmd.Flags().StringVar(&repo, "repo", "",
+		"Repository path (owner/repo)")
+	cmd.Flags().StringVar(&repoURL, "repo-url", "",
+		"Repository clone URL in the payload (default: provider HTTPS URL for --repo)")
+	cmd.Flags().StringVar(&branch, "branch", "main",
+		"Branch that was pushed")
+	cmd.Flags().StringVar(&secret, "secret", "",
+		"Webhook secret used to sign the valid event")
+	cmd.Flags().StringVar(&host, "host", "localhost",
+		"Host of the webhook service")
+	cmd.Flags().IntVar(&port, "port", 8080,
+		"Port of the webhook service")
+	cmd.Flags().DurationVar(&timeout, "timeout", samples.DefaultWebhookFailureTimeout,
+		"Timeout for each request and for the PipelineRun of the valid event")
+	cmd.Flags().StringVar(&outputFormat, "output", "text",
+		"Output format: text, json, yaml")
+	_ = cmd.MarkFlagRequired("repo")
+
+	return cmd
+}
+
+// displayWebhookFailureResult prints the checks of a failure simulation
+func displayWebhookFailureResult(result *samples.WebhookFailureResult) {
+	fmt.Printf("Sent %d %s events (%s)\n", result.Sent, result.ErrorType, result.Provider)
+	responses := make([]string, 0, len(result.Responses))
+	for response := range result.Responses {
+		responses = append(responses, response)
+	}
+	sort.Strings(responses)
+	for _, response := range responses {
+		count := result.Responses[response]
+		if response == "closed" {
+			fmt.Printf("  %d × connection closed\n", count)
+			continue
+		}
+		fmt.Printf("  %d × HTTP %s\n", count, response)
+	}
+	fmt.Println()
+
+	for _, check := range result.Checks {
+		if check.Passed {
+			printSuccess("%s: %s", check.Name, check.Message)
+		} else {
+			printError("%s: %s", check.Name, check.Message)
+		}
+	}
+}
+
+// randomCommitSHA returns a random 40-character hex string
+func randomCommitSHA() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
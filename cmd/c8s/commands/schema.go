@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/org/c8s/pkg/parser"
+)
+
+// AddCommands registers the top-level cobra commands on the root command
+func AddCommands(root *cobra.Command) {
+	root.AddCommand(newSchemaCommand())
+}
+
+// newSchemaCommand creates the schema command
+func newSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the .c8s.yaml format",
+		Long: `Print a JSON Schema (Draft-07) document describing the .c8s.yaml format.
+
+Point a YAML language server at the schema to get autocompletion and
+validation for pipeline files in your editor. The API server publishes the
+same schema at /schemas/pipeline.json.`,
+		Example: `  # Write the schema to a file
+  c8s schema > c8s.schema.json
+
+  # Reference it from .c8s.yaml for the YAML language server
+  # yaml-language-server: $schema=./c8s.schema.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := parser.GenerateJSONSchema()
+			if err != nil {
+				return fmt.Errorf("failed to generate schema: %w", err)
+			}
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+}
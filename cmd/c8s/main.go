@@ -5,9 +5,18 @@ import (
 	"os"
 
 	"github.com/org/c8s/pkg/cli"
+	"github.com/org/c8s/pkg/version"
 )
 
+// Version is the build version, injected with -ldflags "-X main.Version=v0.3.0".
+// It takes precedence over pkg/version.Version when set.
+var Version string
+
 func main() {
+	if Version != "" {
+		version.Version = Version
+	}
+
 	if err := cli.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -17,20 +17,30 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
 	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/log/broker/redis"
+	"github.com/org/c8s/pkg/secrets"
+	"github.com/org/c8s/pkg/storage"
+	"github.com/org/c8s/pkg/storage/s3"
+	"github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/vault"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -50,6 +60,11 @@ func main() {
 	var probeAddr string
 	var enableLeaderElection bool
 	var leaderElectionID string
+	var enablePprof bool
+	var s3Bucket string
+	var s3Region string
+	var s3Endpoint string
+	var redisAddr string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -58,6 +73,12 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&leaderElectionID, "leader-election-id", "c8s-controller-leader",
 		"The name of the leader election ID to use.")
+	flag.BoolVar(&enablePprof, "enable-pprof", false,
+		fmt.Sprintf("Serve net/http/pprof profiles on port %d (used by 'c8s dev operator profile').", types.PprofPort))
+	flag.StringVar(&s3Bucket, "s3-bucket", os.Getenv("C8S_S3_BUCKET"), "S3 bucket step logs are uploaded to (env: C8S_S3_BUCKET)")
+	flag.StringVar(&s3Region, "s3-region", envOrDefault("C8S_S3_REGION", "us-west-2"), "S3 region (env: C8S_S3_REGION)")
+	flag.StringVar(&s3Endpoint, "s3-endpoint", os.Getenv("C8S_S3_ENDPOINT"), "S3 endpoint for MinIO/compatible storage (env: C8S_S3_ENDPOINT)")
+	flag.StringVar(&redisAddr, "redis-addr", "", "Redis address live step logs are published to (env: C8S_REDIS_ADDR)")
 
 	opts := zap.Options{
 		Development: true,
@@ -67,6 +88,13 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// The pprof server is disabled unless requested, as profiles expose internals
+	pprofAddr := ""
+	if enablePprof {
+		pprofAddr = fmt.Sprintf(":%d", types.PprofPort)
+		setupLog.Info("pprof endpoint enabled", "address", pprofAddr)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: server.Options{
@@ -78,21 +106,108 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       leaderElectionID,
+		PprofBindAddress:       pprofAddr,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// Setup Vault client if configured (VAULT_ADDR with VAULT_TOKEN or VAULT_ROLE)
+	var vaultClient *vault.Client
+	if vaultConfig := vault.ConfigFromEnv(); vaultConfig.Address != "" {
+		vaultClient, err = vault.NewClient(vaultConfig)
+		if err != nil {
+			setupLog.Error(err, "unable to create Vault client")
+			os.Exit(1)
+		}
+		setupLog.Info("Vault integration enabled", "address", vaultConfig.Address)
+	}
+
+	// Publish live logs through Redis if configured, with the same settings
+	// as the API server streaming them
+	redisOptions := redis.OptionsFromEnv()
+	if redisAddr != "" {
+		redisOptions.Addr = redisAddr
+	}
+
+	// Setup log collection if storage or Redis is configured. Secrets read
+	// to mask step logs are cached, and invalidated by a watcher when rotated.
+	var logCollector *controller.LogCollector
+	if s3Bucket != "" || redisOptions.Addr != "" {
+		var storageClient storage.StorageClient
+		if s3Bucket != "" {
+			s3Client, err := s3.NewClient(&storage.Config{
+				Bucket:          s3Bucket,
+				Region:          s3Region,
+				Endpoint:        s3Endpoint,
+				AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				UsePathStyle:    s3Endpoint != "", // Use path-style for custom endpoints
+			})
+			if err != nil {
+				setupLog.Error(err, "unable to create S3 storage client")
+				os.Exit(1)
+			}
+			storageClient = s3Client
+		}
+		clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create Kubernetes client")
+			os.Exit(1)
+		}
+
+		secretCache := secrets.NewSecretCache()
+		logCollector = controller.NewLogCollector(clientset, storageClient)
+		logCollector.SetSecretCache(secretCache)
+		if err := mgr.Add(secrets.NewSecretRotationWatcher(clientset, secretCache, "")); err != nil {
+			setupLog.Error(err, "unable to set up secret rotation watcher")
+			os.Exit(1)
+		}
+		// Stops the log streams of running steps with the manager
+		if err := mgr.Add(logCollector); err != nil {
+			setupLog.Error(err, "unable to set up log collector")
+			os.Exit(1)
+		}
+
+		if redisOptions.Addr != "" {
+			logBroker := redis.NewBroker(redisOptions)
+			defer func() { _ = logBroker.Close() }()
+			if err := logBroker.Ping(context.Background()); err != nil {
+				setupLog.Error(err, "unable to connect to Redis", "address", redisOptions.Addr)
+				os.Exit(1)
+			}
+			logCollector.SetBroker(logBroker)
+			setupLog.Info("Redis log broker initialized", "address", redisOptions.Addr)
+		}
+		setupLog.Info("Log collection enabled", "bucket", s3Bucket)
+	}
+
 	// Setup PipelineRun controller
 	if err = (&controller.PipelineRunReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		LogCollector:      logCollector,
+		VaultClient:       vaultClient,
+		ResourceEstimator: controller.NewResourceEstimator(mgr.GetClient()),
+		Recorder:          mgr.GetEventRecorderFor("pipelinerun-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PipelineRun")
 		os.Exit(1)
 	}
 
+	// Create the PriorityClasses used for PipelineRun priorities once the manager starts
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if err := controller.EnsurePriorityClasses(ctx, mgr.GetClient()); err != nil {
+			setupLog.Error(err, "unable to create PriorityClasses")
+			return err
+		}
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to set up PriorityClasses")
+		os.Exit(1)
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -110,3 +225,12 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// envOrDefault returns the value of the environment variable key, or def if
+// it is not set
+func envOrDefault(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
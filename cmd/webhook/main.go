@@ -26,6 +26,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -34,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
 	"github.com/org/c8s/pkg/webhook"
@@ -51,14 +53,16 @@ func init() {
 
 func main() {
 	var (
-		port       int
-		kubeconfig string
-		logLevel   string
+		port         int
+		kubeconfig   string
+		logLevel     string
+		eventHistory int
 	)
 
 	flag.IntVar(&port, "port", 8080, "Port to listen on for webhook requests")
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file (leave empty for in-cluster config)")
 	flag.StringVar(&logLevel, "log-level", "info", "Log level (debug, info, warn, error)")
+	flag.IntVar(&eventHistory, "webhook-event-history", webhook.DefaultEventHistory, "Number of recent webhook events kept for /webhooks/events")
 	flag.Parse()
 
 	// Setup logging
@@ -87,23 +91,31 @@ func main() {
 
 	setupLog.Info("Successfully connected to Kubernetes API")
 
-	// Create webhook handlers
-	githubHandler := webhook.NewGitHubHandler(k8sClient)
-	gitlabHandler := webhook.NewGitLabHandler(k8sClient)
-	bitbucketHandler := webhook.NewBitbucketHandler(k8sClient)
+	// Keep recent events to debug deliveries
+	eventStore := webhook.NewEventStore(eventHistory)
 
 	// Setup HTTP routes
 	mux := http.NewServeMux()
 
-	// Webhook endpoints
-	mux.HandleFunc("/webhooks/github", githubHandler.Handle)
-	mux.HandleFunc("/webhooks/gitlab", gitlabHandler.Handle)
-	mux.HandleFunc("/webhooks/bitbucket", bitbucketHandler.Handle)
+	// Webhook endpoints, one per provider with a registered PayloadValidator
+	for _, provider := range webhook.RegisteredProviders() {
+		validator, _ := webhook.LookupValidator(provider)
+		handler := webhook.NewProviderHandler(k8sClient, provider, validator)
+		handler.SetEventStore(eventStore)
+		mux.HandleFunc("/webhooks/"+provider, handler.Handle)
+	}
+
+	// Event history endpoints
+	mux.HandleFunc("/webhooks/events", eventStore.HandleEvents)
+	mux.HandleFunc("/webhooks/events/{id}", eventStore.HandleEvent)
 
 	// Health check endpoints
 	mux.HandleFunc("/health", handleHealth)
 	mux.HandleFunc("/ready", handleReady)
 
+	// Prometheus metrics, including c8s_webhook_events_total
+	mux.Handle("/metrics", promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{}))
+
 	// Root endpoint
 	mux.HandleFunc("/", handleRoot)
 
@@ -182,8 +194,10 @@ func handleRoot(w http.ResponseWriter, r *http.Request) {
     "github": "/webhooks/github",
     "gitlab": "/webhooks/gitlab",
     "bitbucket": "/webhooks/bitbucket",
+    "events": "/webhooks/events",
     "health": "/health",
-    "ready": "/ready"
+    "ready": "/ready",
+    "metrics": "/metrics"
   }
 }`))
 }
@@ -18,18 +18,26 @@ package handlers
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/log/broker"
+	"github.com/org/c8s/pkg/secrets"
 	"github.com/org/c8s/pkg/storage"
+	ctypes "github.com/org/c8s/pkg/types"
 )
 
 // LogsHandler handles log streaming API requests
@@ -37,19 +45,59 @@ type LogsHandler struct {
 	clientset kubernetes.Interface
 	client    client.Client
 	storage   storage.StorageClient
+
+	// disableDecompression serves stored logs as-is (with their Content-Encoding)
+	disableDecompression bool
+
+	// broker streams live logs published by the controller; when nil, live
+	// logs are streamed from the step's Pod. The controller's in-memory
+	// LogBufferManager is a Broker, but it only reaches handlers running in
+	// the controller process; the API server subscribes through Redis.
+	broker broker.Broker
+
+	// streamPollInterval is how often log streams check whether the step's
+	// Job finished
+	streamPollInterval time.Duration
 }
 
+// DefaultStreamPollInterval is how often a log stream checks whether the
+// step's Job finished
+const DefaultStreamPollInterval = 2 * time.Second
+
 // NewLogsHandler creates a new LogsHandler
 func NewLogsHandler(clientset kubernetes.Interface, client client.Client, storage storage.StorageClient) *LogsHandler {
 	return &LogsHandler{
 		clientset: clientset,
 		client:    client,
 		storage:   storage,
+
+		streamPollInterval: DefaultStreamPollInterval,
 	}
 }
 
+// SetDisableDecompression controls whether compressed logs are served without
+// being decompressed, e.g. to inspect the stored objects when debugging
+func (h *LogsHandler) SetDisableDecompression(disable bool) {
+	h.disableDecompression = disable
+}
+
+// SetBroker streams live logs from b as server-sent events instead of
+// reading them from the step's Pod
+func (h *LogsHandler) SetBroker(b broker.Broker) {
+	h.broker = b
+}
+
+// SetStreamPollInterval sets how often log streams check whether the step's
+// Job finished
+func (h *LogsHandler) SetStreamPollInterval(interval time.Duration) {
+	h.streamPollInterval = interval
+}
+
 // HandleStepLogs handles log retrieval and streaming for a pipeline step
 // GET /api/v1/namespaces/{ns}/pipelineruns/{name}/logs/{step}?follow=true
+//
+// With ?direct=true, the logs of a completed step are streamed from storage
+// as plain text with the step's secret values masked.
 func (h *LogsHandler) HandleStepLogs(w http.ResponseWriter, r *http.Request) {
 	namespace := extractNamespace(r)
 	pipelineRunName := extractResourceName(r)
@@ -60,65 +108,365 @@ func (h *LogsHandler) HandleStepLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get PipelineRun to find the step status
+	run, stepStatus, ok := h.getStepStatus(w, r, namespace, pipelineRunName, stepName)
+	if !ok {
+		return
+	}
+
+	// Responses are truncated to the log size allowed for the step
+	maxSize := h.stepMaxLogSize(r.Context(), run, stepName)
+
+	// Check if we should follow logs (live streaming)
+	follow := r.URL.Query().Get("follow") == "true"
+
+	if r.URL.Query().Get("direct") == "true" && !follow {
+		// Stream stored logs through secret masking
+		h.streamLogsFromStorage(w, r, run, stepName, stepStatus.LogURL, maxSize)
+		return
+	}
+
+	if follow && stepStatus.Phase != "Succeeded" && stepStatus.Phase != "Failed" {
+		if h.broker != nil {
+			// Stream logs published by the controller
+			h.streamLogsFromBroker(w, r, fmt.Sprintf("%s/%s/%s", namespace, pipelineRunName, stepName))
+			return
+		}
+		// Stream logs from running Pod
+		h.streamLogsFromPod(w, r, namespace, stepStatus.JobName, maxSize)
+	} else {
+		// Fetch completed logs from storage
+		h.fetchLogsFromStorage(w, r, stepStatus.LogURL, maxSize)
+	}
+}
+
+// HandleStepLogStream streams the logs of a pipeline step as server-sent
+// events, one "data" event per line, until the step's Job finishes
+// GET /api/v1/namespaces/{ns}/pipelineruns/{name}/logs/{step}/stream
+//
+// Live logs are subscribed to through the broker, e.g. the controller's
+// LogBufferManager, or read from the step's Pod when no broker is set. The
+// logs of a step whose Pod already completed are sent from storage. Logs read
+// from storage or the Pod are masked like the direct logs; the controller
+// masks logs before publishing them. The stream ends with an "end" event
+// whose data is the final phase of the Job: Succeeded, Failed, or Deleted.
+func (h *LogsHandler) HandleStepLogStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	namespace := extractNamespace(r)
+	pipelineRunName := extractResourceName(r)
+	stepName := extractStepName(r)
+
+	if namespace == "" || pipelineRunName == "" || stepName == "" {
+		http.Error(w, "namespace, pipelinerun name, and step name are required", http.StatusBadRequest)
+		return
+	}
+
+	run, stepStatus, ok := h.getStepStatus(w, r, namespace, pipelineRunName, stepName)
+	if !ok {
+		return
+	}
+	maxSize := h.stepMaxLogSize(r.Context(), run, stepName)
+
+	phase, finished := string(stepStatus.Phase), isStepFinished(stepStatus.Phase)
+	if !finished {
+		phase, finished = h.jobPhase(r.Context(), namespace, stepStatus.JobName)
+	}
+
+	switch {
+	case finished && stepStatus.LogURL != "":
+		secretValues := h.stepSecretValues(r.Context(), run, stepName)
+		h.streamEventsFromStorage(w, r, stepStatus.LogURL, phase, maxSize, secretValues)
+	case finished || h.broker == nil:
+		// The Pod stream ends when the step's container exits
+		secretValues := h.stepSecretValues(r.Context(), run, stepName)
+		chunks, err := h.podLogChunks(r.Context(), namespace, stepStatus.JobName, maxSize, secretValues)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.streamEvents(w, r, namespace, stepStatus.JobName, chunks, true)
+	default:
+		key := fmt.Sprintf("%s/%s/%s", namespace, pipelineRunName, stepName)
+		chunks := h.broker.Subscribe(key)
+		defer h.broker.Unsubscribe(key, chunks)
+		h.streamEvents(w, r, namespace, stepStatus.JobName, chunks, false)
+	}
+}
+
+// getStepStatus returns a PipelineRun and the status of one of its steps,
+// writing the error response if either doesn't exist
+func (h *LogsHandler) getStepStatus(w http.ResponseWriter, r *http.Request, namespace, pipelineRunName, stepName string) (*v1alpha1.PipelineRun, *v1alpha1.StepStatus, bool) {
 	var run v1alpha1.PipelineRun
 	key := client.ObjectKey{Namespace: namespace, Name: pipelineRunName}
 	if err := h.client.Get(r.Context(), key, &run); err != nil {
 		if client.IgnoreNotFound(err) == nil {
 			http.Error(w, "pipeline run not found", http.StatusNotFound)
-			return
+			return nil, nil, false
 		}
 		http.Error(w, fmt.Sprintf("failed to get pipeline run: %v", err), http.StatusInternalServerError)
-		return
+		return nil, nil, false
 	}
 
-	// Find the step status
-	var stepStatus *v1alpha1.StepStatus
 	for i, step := range run.Status.Steps {
 		if step.Name == stepName {
-			stepStatus = &run.Status.Steps[i]
-			break
+			return &run, &run.Status.Steps[i], true
+		}
+	}
+
+	http.Error(w, fmt.Sprintf("step %s not found in pipeline run", stepName), http.StatusNotFound)
+	return nil, nil, false
+}
+
+// streamEvents sends the chunks of a log stream as server-sent events until
+// the step's Job finishes or, with untilClosed, until chunks is closed and
+// the Job finished. It returns when the client disconnects.
+func (h *LogsHandler) streamEvents(w http.ResponseWriter, r *http.Request, namespace, jobName string, chunks <-chan []byte, untilClosed bool) {
+	writeEventStreamHeaders(w)
+
+	ticker := time.NewTicker(h.streamPollInterval)
+	defer ticker.Stop()
+
+	var phase string
+	for phase == "" || (untilClosed && chunks != nil) {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-chunks:
+			if !ok {
+				chunks = nil
+				if phase == "" {
+					phase, _ = h.jobPhase(r.Context(), namespace, jobName)
+				}
+				continue
+			}
+			writeEventData(w, data)
+		case <-ticker.C:
+			if finished, done := h.jobPhase(r.Context(), namespace, jobName); done {
+				phase = finished
+			}
+		}
+	}
+
+	// Send the chunks published before the Job finished was noticed
+	for drained := chunks == nil; !drained; {
+		select {
+		case data, ok := <-chunks:
+			if !ok {
+				drained = true
+				break
+			}
+			writeEventData(w, data)
+		default:
+			drained = true
 		}
 	}
 
-	if stepStatus == nil {
-		http.Error(w, fmt.Sprintf("step %s not found in pipeline run", stepName), http.StatusNotFound)
+	writeEndEvent(w, phase)
+}
+
+// streamEventsFromStorage sends the stored logs of a completed step as
+// server-sent events with secretValues masked, followed by the end event
+func (h *LogsHandler) streamEventsFromStorage(w http.ResponseWriter, r *http.Request, logURL, phase string, maxSize int64, secretValues map[string]string) {
+	logsReader, contentEncoding, err := h.storage.DownloadLog(r.Context(), logStorageKey(logURL))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to download logs: %v", err), http.StatusInternalServerError)
 		return
 	}
+	decoded, err := storage.DecompressLog(logsReader, contentEncoding)
+	if err != nil {
+		_ = logsReader.Close()
+		http.Error(w, fmt.Sprintf("failed to read logs: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer func() { _ = decoded.Close() }()
 
-	// Check if we should follow logs (live streaming)
-	follow := r.URL.Query().Get("follow") == "true"
+	masked := secrets.MaskReader(decoded, secretValues)
+	defer func() { _ = masked.Close() }()
 
-	if follow && stepStatus.Phase != "Succeeded" && stepStatus.Phase != "Failed" {
-		// Stream logs from running Pod
-		h.streamLogsFromPod(w, r, namespace, stepStatus.JobName, stepName)
-	} else {
-		// Fetch completed logs from storage
-		h.fetchLogsFromStorage(w, r, stepStatus.LogURL)
+	writeEventStreamHeaders(w)
+
+	reader := bufio.NewReader(io.LimitReader(masked, maxSize))
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			writeEventData(w, line)
+		}
+		if err != nil {
+			break
+		}
 	}
+
+	writeEndEvent(w, phase)
 }
 
-func (h *LogsHandler) streamLogsFromPod(w http.ResponseWriter, r *http.Request, namespace, jobName, stepName string) {
-	// Find the Pod created by the Job
-	pods, err := h.clientset.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+// podLogChunks follows the logs of the Pod of a step's Job with secretValues
+// masked, sending one line per chunk. The channel is closed when the
+// container exits, after maxSize bytes, or when ctx is done.
+func (h *LogsHandler) podLogChunks(ctx context.Context, namespace, jobName string, maxSize int64, secretValues map[string]string) (<-chan []byte, error) {
+	pod, err := h.jobPod(ctx, namespace, jobName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	if pod == nil {
+		return nil, fmt.Errorf("no pods found for job")
+	}
+
+	stream, err := h.clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Follow:    true,
+		Container: ctypes.ContainerNameStep,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream logs: %w", err)
+	}
+
+	chunks := make(chan []byte)
+	go func() {
+		defer close(chunks)
+		defer func() { _ = stream.Close() }()
+
+		masked := secrets.MaskReader(stream, secretValues)
+		defer func() { _ = masked.Close() }()
+
+		reader := bufio.NewReader(io.LimitReader(masked, maxSize))
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case chunks <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return chunks, nil
+}
+
+// jobPod returns the primary Pod of a Job: the most recently started of its
+// Pods, since a Job retrying a failed attempt has one Pod per attempt, like
+// the controller's GetJobPod. Returns nil if the Job has no Pod yet.
+func (h *LogsHandler) jobPod(ctx context.Context, namespace, jobName string) (*corev1.Pod, error) {
+	pods, err := h.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("job-name=%s", jobName),
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *corev1.Pod
+	for i := range pods.Items {
+		if latest == nil || podStartTime(latest).Before(podStartTime(&pods.Items[i])) {
+			latest = &pods.Items[i]
+		}
+	}
+	return latest, nil
+}
+
+// podStartTime returns when a Pod started, or was created if it hasn't
+func podStartTime(pod *corev1.Pod) *metav1.Time {
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime
+	}
+	return &pod.CreationTimestamp
+}
+
+// jobPhase returns the final phase of a step's Job and whether it finished.
+// A deleted Job, e.g. of a cancelled run, has finished.
+func (h *LogsHandler) jobPhase(ctx context.Context, namespace, jobName string) (string, bool) {
+	if jobName == "" {
+		return "", false
+	}
+	job, err := h.clientset.BatchV1().Jobs(namespace).Get(ctx, jobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "Deleted", true
+	}
+	if err != nil {
+		return "", false
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return string(v1alpha1.StepPhaseSucceeded), true
+		case batchv1.JobFailed:
+			return string(v1alpha1.StepPhaseFailed), true
+		}
+	}
+	return "", false
+}
+
+// isStepFinished reports whether a step phase is final
+func isStepFinished(phase v1alpha1.StepPhase) bool {
+	return phase == v1alpha1.StepPhaseSucceeded || phase == v1alpha1.StepPhaseFailed
+}
+
+// writeEventStreamHeaders starts a server-sent events response
+func writeEventStreamHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeEventData sends log data as server-sent events, one per line
+func writeEventData(w http.ResponseWriter, data []byte) {
+	for _, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) {
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", line)
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeEndEvent sends the event ending a log stream, with the final phase of
+// the step's Job
+func writeEndEvent(w http.ResponseWriter, phase string) {
+	_, _ = fmt.Fprintf(w, "event: end\ndata: %s\n\n", phase)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// stepMaxLogSize returns the log size allowed for a step by the run's
+// PipelineConfig, falling back to the default when the config is gone
+func (h *LogsHandler) stepMaxLogSize(ctx context.Context, run *v1alpha1.PipelineRun, stepName string) int64 {
+	var config v1alpha1.PipelineConfig
+	key := client.ObjectKey{Namespace: run.Namespace, Name: run.Spec.PipelineConfigRef}
+	if err := h.client.Get(ctx, key, &config); err != nil {
+		return int64(v1alpha1.DefaultMaxLogSizeMB) * 1024 * 1024
+	}
+	return config.Spec.MaxLogSizeBytes(stepName)
+}
+
+func (h *LogsHandler) streamLogsFromPod(w http.ResponseWriter, r *http.Request, namespace, jobName string, maxSize int64) {
+	// Find the Pod created by the Job
+	pod, err := h.jobPod(context.Background(), namespace, jobName)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to list pods: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	if len(pods.Items) == 0 {
+	if pod == nil {
 		http.Error(w, "no pods found for job", http.StatusNotFound)
 		return
 	}
 
-	pod := pods.Items[0]
-
 	// Stream logs from the Pod's main container
 	req := h.clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
 		Follow:    true,
-		Container: stepName, // Container name matches step name
+		Container: ctypes.ContainerNameStep,
 	})
 
 	stream, err := req.Stream(context.Background())
@@ -139,7 +487,7 @@ func (h *LogsHandler) streamLogsFromPod(w http.ResponseWriter, r *http.Request,
 	}
 
 	// Copy logs to response
-	reader := bufio.NewReader(stream)
+	reader := bufio.NewReader(io.LimitReader(stream, maxSize))
 	for {
 		line, err := reader.ReadBytes('\n')
 		if len(line) > 0 {
@@ -157,44 +505,133 @@ func (h *LogsHandler) streamLogsFromPod(w http.ResponseWriter, r *http.Request,
 	}
 }
 
-func (h *LogsHandler) fetchLogsFromStorage(w http.ResponseWriter, r *http.Request, logURL string) {
+// streamLogsFromBroker sends the logs published to key as server-sent events,
+// one event per line, until the client disconnects
+func (h *LogsHandler) streamLogsFromBroker(w http.ResponseWriter, r *http.Request, key string) {
+	ch := h.broker.Subscribe(key)
+	defer h.broker.Unsubscribe(key, ch)
+
+	writeEventStreamHeaders(w)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeEventData(w, data)
+		}
+	}
+}
+
+func (h *LogsHandler) fetchLogsFromStorage(w http.ResponseWriter, r *http.Request, logURL string, maxSize int64) {
 	if logURL == "" {
 		http.Error(w, "logs not yet available", http.StatusNotFound)
 		return
 	}
 
-	// Extract key from logURL (assumes format: s3://bucket/key or just the key)
-	key := logURL
-	if len(logURL) > 5 && logURL[:5] == "s3://" {
-		// Parse s3:// URL to extract key
-		parts := logURL[5:] // Remove s3://
-		// Find first slash to separate bucket from key
-		slashIdx := 0
-		for i, c := range parts {
-			if c == '/' {
-				slashIdx = i
-				break
-			}
+	logsReader, contentEncoding, err := h.storage.DownloadLog(r.Context(), logStorageKey(logURL))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to download logs: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if h.disableDecompression {
+		if contentEncoding != "" {
+			w.Header().Set("Content-Encoding", contentEncoding)
 		}
-		if slashIdx > 0 && slashIdx < len(parts)-1 {
-			key = parts[slashIdx+1:] // Everything after bucket name
+	} else {
+		// Decompress gzip-encoded logs transparently
+		decoded, err := storage.DecompressLog(logsReader, contentEncoding)
+		if err != nil {
+			_ = logsReader.Close()
+			http.Error(w, fmt.Sprintf("failed to read logs: %v", err), http.StatusInternalServerError)
+			return
 		}
+		logsReader = decoded
 	}
+	defer func() { _ = logsReader.Close() }()
 
-	logsReader, err := h.storage.DownloadLog(r.Context(), key)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	// Stream logs to response; compressed logs served as-is can't be
+	// truncated without corrupting them
+	var body io.Reader = logsReader
+	if !h.disableDecompression {
+		body = io.LimitReader(logsReader, maxSize)
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		// Can't change status code here, already sent headers
+		// Log error but continue
+		_, _ = fmt.Fprintf(w, "\n\nError streaming logs: %v\n", err)
+	}
+}
+
+// streamLogsFromStorage streams the stored logs of a step as plain text,
+// masking the current values of the step's secrets
+func (h *LogsHandler) streamLogsFromStorage(w http.ResponseWriter, r *http.Request, run *v1alpha1.PipelineRun, stepName, logURL string, maxSize int64) {
+	if logURL == "" {
+		http.Error(w, "logs not yet available", http.StatusNotFound)
+		return
+	}
+
+	logsReader, err := h.storage.GetLog(r.Context(), logStorageKey(logURL))
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to download logs: %v", err), http.StatusInternalServerError)
 		return
 	}
 	defer func() { _ = logsReader.Close() }()
 
+	masked := secrets.MaskReader(logsReader, h.stepSecretValues(r.Context(), run, stepName))
+	defer func() { _ = masked.Close() }()
+
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 
-	// Stream logs to response
-	if _, err := io.Copy(w, logsReader); err != nil {
-		// Can't change status code here, already sent headers
-		// Log error but continue
+	if _, err := io.Copy(w, io.LimitReader(masked, maxSize)); err != nil {
 		_, _ = fmt.Fprintf(w, "\n\nError streaming logs: %v\n", err)
 	}
 }
+
+// stepSecretValues returns the values of the Secrets referenced by a step of
+// the run's PipelineConfig. Secrets that can't be read are skipped.
+func (h *LogsHandler) stepSecretValues(ctx context.Context, run *v1alpha1.PipelineRun, stepName string) map[string]string {
+	var config v1alpha1.PipelineConfig
+	key := client.ObjectKey{Namespace: run.Namespace, Name: run.Spec.PipelineConfigRef}
+	if err := h.client.Get(ctx, key, &config); err != nil {
+		return nil
+	}
+
+	values := make(map[string]string)
+	for _, step := range config.Spec.Steps {
+		if step.Name != stepName {
+			continue
+		}
+		for _, ref := range step.Secrets {
+			var secret corev1.Secret
+			if err := h.client.Get(ctx, client.ObjectKey{Namespace: run.Namespace, Name: ref.SecretRef}, &secret); err != nil {
+				continue
+			}
+			if value, ok := secret.Data[ref.Key]; ok {
+				values[fmt.Sprintf("%s:%s", ref.SecretRef, ref.Key)] = string(value)
+			}
+		}
+	}
+	return values
+}
+
+// logStorageKey returns the storage key of a step's LogURL, which is either
+// the key itself or an s3://bucket/key URL
+func logStorageKey(logURL string) string {
+	path, ok := strings.CutPrefix(logURL, "s3://")
+	if !ok {
+		return logURL
+	}
+	if bucket, key, found := strings.Cut(path, "/"); found && bucket != "" && key != "" {
+		return key
+	}
+	return logURL
+}
@@ -17,15 +17,19 @@ limitations under the License.
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/scheduler"
+	ctypes "github.com/org/c8s/pkg/types"
 )
 
 // PipelineRunHandler handles PipelineRun API requests
@@ -149,22 +153,161 @@ func (h *PipelineRunHandler) createPipelineRun(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// deletePipelineRun cancels a PipelineRun in progress: it records the
+// c8s.dev/cancel-requested annotation and moves the run to Cancelling, and
+// the controller deletes its Jobs and marks it Cancelled. A run that already
+// completed is deleted.
 func (h *PipelineRunHandler) deletePipelineRun(w http.ResponseWriter, r *http.Request, namespace, name string) {
-	run := &v1alpha1.PipelineRun{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: namespace,
-			Name:      name,
-		},
+	var run v1alpha1.PipelineRun
+	key := client.ObjectKey{Namespace: namespace, Name: name}
+	if err := h.client.Get(r.Context(), key, &run); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			http.Error(w, "pipeline run not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to get pipeline run: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	switch run.Status.Phase {
+	case v1alpha1.PipelineRunPhaseSucceeded, v1alpha1.PipelineRunPhaseFailed, v1alpha1.PipelineRunPhaseCancelled:
+		if err := h.client.Delete(r.Context(), &run); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				http.Error(w, "pipeline run not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, fmt.Sprintf("failed to delete pipeline run: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := h.cancelPipelineRun(r.Context(), &run); err != nil {
+		http.Error(w, fmt.Sprintf("failed to cancel pipeline run: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(run); err != nil {
+		_, _ = fmt.Fprintf(w, `{"error": "failed to encode response: %v"}`, err)
+	}
+}
+
+// cancelPipelineRun requests cancellation of a run. Repeated requests keep
+// the time of the first one.
+func (h *PipelineRunHandler) cancelPipelineRun(ctx context.Context, run *v1alpha1.PipelineRun) error {
+	if _, requested := run.Annotations[ctypes.AnnotationCancelRequested]; !requested {
+		patch := client.MergeFrom(run.DeepCopy())
+		if run.Annotations == nil {
+			run.Annotations = map[string]string{}
+		}
+		run.Annotations[ctypes.AnnotationCancelRequested] = time.Now().UTC().Format(time.RFC3339)
+		if err := h.client.Patch(ctx, run, patch); err != nil {
+			return err
+		}
+	}
+
+	if run.Status.Phase == v1alpha1.PipelineRunPhaseCancelling {
+		return nil
+	}
+	patch := client.MergeFrom(run.DeepCopy())
+	run.Status.Phase = v1alpha1.PipelineRunPhaseCancelling
+	return h.client.Status().Patch(ctx, run, patch)
+}
+
+// PipelineRunEstimate is the predicted progress of a PipelineRun
+type PipelineRunEstimate struct {
+	EstimatedDuration   string       `json:"estimatedDuration"`
+	CriticalPath        []string     `json:"criticalPath"`
+	StartTime           *metav1.Time `json:"startTime,omitempty"`
+	EstimatedCompletion *metav1.Time `json:"estimatedCompletion,omitempty"`
+	Progress            int          `json:"progress"`
+}
+
+// HandlePipelineRunEstimate returns the estimated duration and progress of a PipelineRun
+func (h *PipelineRunHandler) HandlePipelineRunEstimate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	if err := h.client.Delete(r.Context(), run); err != nil {
+	namespace := extractNamespace(r)
+	name := r.PathValue("name")
+	if namespace == "" || name == "" {
+		http.Error(w, "namespace and name are required", http.StatusBadRequest)
+		return
+	}
+
+	var run v1alpha1.PipelineRun
+	if err := h.client.Get(r.Context(), client.ObjectKey{Namespace: namespace, Name: name}, &run); err != nil {
 		if client.IgnoreNotFound(err) == nil {
 			http.Error(w, "pipeline run not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, fmt.Sprintf("failed to delete pipeline run: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("failed to get pipeline run: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var config v1alpha1.PipelineConfig
+	if err := h.client.Get(r.Context(), client.ObjectKey{Namespace: namespace, Name: run.Spec.PipelineConfigRef}, &config); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			http.Error(w, "pipeline config not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to get pipeline config: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	estimate, err := EstimatePipelineRun(&run, &config, time.Now())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to estimate pipeline run: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(estimate); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// EstimatePipelineRun simulates the run's pipeline and reports how far the
+// run is towards its estimated completion. Progress is capped at 99% while
+// the run is still executing and is 100% once it has completed.
+func EstimatePipelineRun(run *v1alpha1.PipelineRun, config *v1alpha1.PipelineConfig, now time.Time) (*PipelineRunEstimate, error) {
+	schedule, err := scheduler.BuildSchedule(config)
+	if err != nil {
+		return nil, err
+	}
+	simulation := scheduler.Simulate(schedule, 0)
+
+	estimate := &PipelineRunEstimate{
+		EstimatedDuration: simulation.EstimatedDuration.String(),
+		CriticalPath:      simulation.CriticalPath,
+		StartTime:         run.Status.StartTime,
+	}
+
+	if run.Status.CompletionTime != nil {
+		estimate.Progress = 100
+		return estimate, nil
+	}
+	if run.Status.StartTime == nil {
+		return estimate, nil
+	}
+
+	completion := metav1.NewTime(run.Status.StartTime.Add(simulation.EstimatedDuration))
+	estimate.EstimatedCompletion = &completion
+
+	if simulation.EstimatedDuration > 0 {
+		elapsed := now.Sub(run.Status.StartTime.Time)
+		estimate.Progress = int(elapsed * 100 / simulation.EstimatedDuration)
+	}
+	if estimate.Progress > 99 {
+		estimate.Progress = 99
+	}
+	if estimate.Progress < 0 {
+		estimate.Progress = 0
+	}
+	return estimate, nil
 }
@@ -35,8 +35,17 @@ func extractNamespace(r *http.Request) string {
 
 // extractResourceName extracts the resource name from the request URL path
 // Expected pattern: .../pipelineconfigs/{name} or .../pipelineruns/{name}
+// (optionally followed by /logs/{step} or /logs/{step}/stream)
 func extractResourceName(r *http.Request) string {
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) >= 4 && parts[len(parts)-1] == "stream" && parts[len(parts)-3] == "logs" {
+		// Step log stream endpoint: .../{name}/logs/{step}/stream
+		return parts[len(parts)-4]
+	}
+	if len(parts) >= 3 && parts[len(parts)-2] == "logs" {
+		// Step logs endpoint: the resource name precedes /logs/{step}
+		return parts[len(parts)-3]
+	}
 	if len(parts) > 0 {
 		// The last part is the resource name
 		lastPart := parts[len(parts)-1]
@@ -0,0 +1,258 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AuditPolicy controls how much of a request is recorded in audit events
+type AuditPolicy string
+
+const (
+	// AuditPolicyNone records no audit events
+	AuditPolicyNone AuditPolicy = "none"
+
+	// AuditPolicyMetadata records who did what to which resource, without
+	// request or response bodies
+	AuditPolicyMetadata AuditPolicy = "metadata"
+
+	// AuditPolicyRequest also records the request body
+	AuditPolicyRequest AuditPolicy = "request"
+
+	// AuditPolicyRequestResponse also records the request and response bodies
+	AuditPolicyRequestResponse AuditPolicy = "requestresponse"
+)
+
+// RequestIDHeader is the header carrying the ID of a request. Requests
+// without one are assigned a generated ID, returned in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// anonymousUser is the audit user of unauthenticated requests
+const anonymousUser = "system:anonymous"
+
+// ParseAuditPolicy parses an --audit-policy value
+func ParseAuditPolicy(value string) (AuditPolicy, error) {
+	switch policy := AuditPolicy(strings.ToLower(value)); policy {
+	case AuditPolicyNone, AuditPolicyMetadata, AuditPolicyRequest, AuditPolicyRequestResponse:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid audit policy %q: must be one of none, metadata, request, requestresponse", value)
+}
+
+// userKey is the context key of the authenticated user
+type userKey struct{}
+
+// WithUser returns a context carrying the authenticated user of a request.
+// Authentication middleware sets it for Audit to record.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the authenticated user set by WithUser
+func UserFromContext(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(userKey{}).(string)
+	return user, ok && user != ""
+}
+
+// NewAuditLogger returns a logger writing one JSON object per line to the
+// file at path, appending to it if it exists
+func NewAuditLogger(path string) (*zap.Logger, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+
+	// Events carry their own timestamp, so only the fields are encoded
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	})
+	return zap.New(zapcore.NewCore(encoder, zapcore.AddSync(file), zap.InfoLevel)), nil
+}
+
+// Audit records a metadata audit event for every mutating request
+// (POST, PUT, PATCH and DELETE)
+func Audit(next http.Handler, logger *zap.Logger) http.Handler {
+	return AuditWithPolicy(next, logger, AuditPolicyMetadata)
+}
+
+// AuditWithPolicy records an audit event for every mutating request, with
+// the request and response bodies included as the policy requires
+func AuditWithPolicy(next http.Handler, logger *zap.Logger, policy AuditPolicy) http.Handler {
+	if policy == AuditPolicyNone {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verb := auditVerb(r.Method)
+		if verb == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+			r.Header.Set(RequestIDHeader, requestID)
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		// Buffer the body to measure it and to read the name of created
+		// resources, then hand the handler a fresh reader
+		var body []byte
+		if r.Body != nil {
+			body, _ = io.ReadAll(r.Body)
+			_ = r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		rw := &auditResponseWriter{
+			responseWriter: responseWriter{ResponseWriter: w, statusCode: http.StatusOK},
+			capture:        policy == AuditPolicyRequestResponse,
+		}
+		next.ServeHTTP(rw, r)
+
+		resource, namespace, name := auditResource(r.URL.Path)
+		if name == "" && verb == "create" {
+			name = objectName(body)
+		}
+
+		user, ok := UserFromContext(r.Context())
+		if !ok {
+			user = anonymousUser
+		}
+
+		fields := []zap.Field{
+			zap.String("timestamp", start.UTC().Format(time.RFC3339Nano)),
+			zap.String("user", user),
+			zap.String("verb", verb),
+			zap.String("resource", resource),
+			zap.String("name", name),
+			zap.String("namespace", namespace),
+			zap.String("request-id", requestID),
+			zap.String("source-ip", sourceIP(r.RemoteAddr)),
+			zap.Int("request-size-bytes", len(body)),
+			zap.Int("response-code", rw.statusCode),
+		}
+		if policy == AuditPolicyRequest || policy == AuditPolicyRequestResponse {
+			fields = append(fields, auditBody("request-object", body))
+		}
+		if policy == AuditPolicyRequestResponse {
+			fields = append(fields, auditBody("response-object", rw.body.Bytes()))
+		}
+		logger.Info("audit", fields...)
+	})
+}
+
+// auditResponseWriter captures the status code and, when capture is set,
+// the body of a response
+type auditResponseWriter struct {
+	responseWriter
+	capture bool
+	body    bytes.Buffer
+}
+
+func (rw *auditResponseWriter) Write(b []byte) (int, error) {
+	if rw.capture {
+		rw.body.Write(b)
+	}
+	return rw.responseWriter.Write(b)
+}
+
+// auditVerb maps a mutating HTTP method to its audit verb, or returns "" for
+// read-only methods
+func auditVerb(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut, http.MethodPatch:
+		return "update"
+	case http.MethodDelete:
+		return "delete"
+	}
+	return ""
+}
+
+// auditResource extracts the singular resource type, namespace and name from
+// a path of the form /api/v1/namespaces/{namespace}/{resources}[/{name}/...]
+func auditResource(path string) (resource, namespace, name string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, part := range parts {
+		if part != "namespaces" || i+1 >= len(parts) {
+			continue
+		}
+		namespace = parts[i+1]
+		if i+2 < len(parts) {
+			resource = strings.TrimSuffix(parts[i+2], "s")
+		}
+		if i+3 < len(parts) {
+			name = parts[i+3]
+		}
+		break
+	}
+	return resource, namespace, name
+}
+
+// objectName returns metadata.name of a JSON request body
+func objectName(body []byte) string {
+	var object struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return ""
+	}
+	return object.Metadata.Name
+}
+
+// sourceIP strips the port from a remote address
+func sourceIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// auditBody encodes a body as raw JSON when it is valid JSON, and as a
+// string otherwise
+func auditBody(key string, body []byte) zap.Field {
+	if len(body) == 0 {
+		return zap.Skip()
+	}
+	if json.Valid(body) {
+		return zap.Reflect(key, json.RawMessage(body))
+	}
+	return zap.String(key, string(body))
+}
@@ -20,6 +20,56 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Environments a pipeline runs in. Steps can be restricted to some of them
+// with conditional.environment.
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentStaging     = "staging"
+	EnvironmentProduction  = "production"
+)
+
+// ValidEnvironments lists the accepted values for Environment
+var ValidEnvironments = []string{EnvironmentDevelopment, EnvironmentStaging, EnvironmentProduction}
+
+// IsValidEnvironment reports whether e is a valid environment. An empty value
+// is valid and means no environment is set.
+func IsValidEnvironment(e string) bool {
+	if e == "" {
+		return true
+	}
+	for _, valid := range ValidEnvironments {
+		if e == valid {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// DefaultMaxLogSizeMB is the log size kept per step when neither the
+	// step nor the PipelineConfig sets one
+	DefaultMaxLogSizeMB = 10
+
+	// MaxLogSizeMBLimit is the largest accepted log size setting
+	MaxLogSizeMBLimit = 100
+)
+
+// MaxLogSizeBytes returns the log size kept for a step: its maxLogSizeMB,
+// else spec.globalMaxLogSizeMB, else DefaultMaxLogSizeMB
+func (s *PipelineConfigSpec) MaxLogSizeBytes(stepName string) int64 {
+	sizeMB := DefaultMaxLogSizeMB
+	if s.GlobalMaxLogSizeMB > 0 {
+		sizeMB = s.GlobalMaxLogSizeMB
+	}
+	for _, step := range s.Steps {
+		if step.Name == stepName && step.MaxLogSizeMB > 0 {
+			sizeMB = step.MaxLogSizeMB
+			break
+		}
+	}
+	return int64(sizeMB) * 1024 * 1024
+}
+
 // PipelineConfigSpec defines the desired state of PipelineConfig
 type PipelineConfigSpec struct {
 	// Repository is the Git repository URL (https or ssh)
@@ -43,6 +93,12 @@ type PipelineConfigSpec struct {
 	// +optional
 	Timeout string `json:"timeout,omitempty"`
 
+	// DefaultStepTimeout applies to steps without a timeout (e.g., "30m")
+	// +kubebuilder:validation:Pattern=`^[0-9]+(s|m|h)$`
+	// +kubebuilder:default="30m"
+	// +optional
+	DefaultStepTimeout string `json:"defaultStepTimeout,omitempty"`
+
 	// Matrix strategy for parallel execution
 	// +optional
 	Matrix *MatrixStrategy `json:"matrix,omitempty"`
@@ -50,6 +106,75 @@ type PipelineConfigSpec struct {
 	// RetryPolicy defines retry behavior for failed steps
 	// +optional
 	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// NetworkPolicy restricts network egress of step Pods
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// Priority is the default scheduling priority for runs of this pipeline
+	// +kubebuilder:validation:Enum=low;normal;high;critical
+	// +optional
+	Priority string `json:"priority,omitempty"`
+
+	// MaxParallel is the maximum number of step Jobs of a run active at
+	// once (0 for no limit). Ready steps beyond the limit wait, highest
+	// weight first.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxParallel int `json:"maxParallel,omitempty"`
+
+	// DeduplicateSteps runs steps with identical configurations once: steps
+	// with the same image, commands, resources and settings, and without
+	// secrets, artifacts or matrix dimensions, are merged into the first of
+	// them. The merged steps appear in the run status as aliases of it.
+	// +optional
+	DeduplicateSteps bool `json:"deduplicateSteps,omitempty"`
+
+	// ResourceQuota limits the pipeline Jobs active in the namespace before
+	// new Jobs of this pipeline are created
+	// +optional
+	ResourceQuota *ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+
+	// Volumes declares volumes that steps can mount by name
+	// +optional
+	Volumes []VolumeSpec `json:"volumes,omitempty"`
+
+	// Includes are PipelineConfigs whose steps run as part of this pipeline,
+	// named "{config-name}/{step-name}". Steps can depend on included steps
+	// by that name.
+	// +optional
+	Includes []IncludeRef `json:"includes,omitempty"`
+
+	// DefaultSecurityContext applies to step containers without a
+	// securityContext
+	// +optional
+	DefaultSecurityContext *SecurityContextSpec `json:"defaultSecurityContext,omitempty"`
+
+	// Environment is the default environment of runs of this pipeline,
+	// used when the run sets none (default development)
+	// +kubebuilder:validation:Enum=development;staging;production
+	// +optional
+	Environment string `json:"environment,omitempty"`
+
+	// GlobalMaxLogSizeMB is the log size kept per step, in MB, for steps
+	// without maxLogSizeMB (default 10)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	GlobalMaxLogSizeMB int `json:"globalMaxLogSizeMB,omitempty"`
+}
+
+// IncludeRef references a PipelineConfig whose steps are included in another.
+// Included steps run against the repository and commit of the including run.
+type IncludeRef struct {
+	// Name is the name of the included PipelineConfig
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace of the included PipelineConfig (default: the namespace of the
+	// including PipelineConfig)
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // PipelineStep defines a single step in the pipeline
@@ -68,6 +193,11 @@ type PipelineStep struct {
 	// +kubebuilder:validation:MinItems=1
 	Commands []string `json:"commands"`
 
+	// WorkingDir is the directory the commands run in, relative to the
+	// workspace unless absolute (default /workspace)
+	// +optional
+	WorkingDir string `json:"workingDir,omitempty"`
+
 	// DependsOn are step names that must complete before this step
 	// +optional
 	DependsOn []string `json:"dependsOn,omitempty"`
@@ -76,9 +206,9 @@ type PipelineStep struct {
 	// +optional
 	Resources *ResourceRequirements `json:"resources,omitempty"`
 
-	// Timeout is the step timeout (e.g., "30m", "2h")
+	// Timeout is the step timeout (e.g., "30m", "2h"); defaults to
+	// spec.defaultStepTimeout
 	// +kubebuilder:validation:Pattern=`^[0-9]+(s|m|h)$`
-	// +kubebuilder:default="30m"
 	// +optional
 	Timeout string `json:"timeout,omitempty"`
 
@@ -90,9 +220,48 @@ type PipelineStep struct {
 	// +optional
 	Secrets []SecretReference `json:"secrets,omitempty"`
 
+	// VaultSecrets are HashiCorp Vault secrets to inject as env vars
+	// +optional
+	VaultSecrets []VaultSecretRef `json:"vaultSecrets,omitempty"`
+
 	// Conditional defines conditions for step execution
 	// +optional
 	Conditional *ConditionalExecution `json:"conditional,omitempty"`
+
+	// VolumeMounts mount volumes declared in spec.volumes into the step container
+	// +optional
+	VolumeMounts []VolumeMountSpec `json:"volumeMounts,omitempty"`
+
+	// Retry overrides spec.retryPolicy for this step
+	// +optional
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// SecurityContext overrides spec.defaultSecurityContext for this step
+	// +optional
+	SecurityContext *SecurityContextSpec `json:"securityContext,omitempty"`
+
+	// MaxLogSizeMB overrides spec.globalMaxLogSizeMB for this step: logs
+	// beyond this size, in MB, are truncated
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxLogSizeMB int `json:"maxLogSizeMB,omitempty"`
+
+	// Weight orders steps ready at the same time: when spec.maxParallel
+	// limits the Jobs created, steps of higher weight are created first
+	// (default 1)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Weight int `json:"weight,omitempty"`
+
+	// MatrixDimensions are the dimensions of spec.matrix the step varies
+	// over. A step listing dimensions runs once per unique combination of
+	// their values within the same PipelineRun, with the values injected as
+	// MATRIX_<DIMENSION> env vars. When any step lists dimensions, steps
+	// listing none run once, shared by all combinations, instead of a
+	// PipelineRun being created per combination.
+	// +optional
+	MatrixDimensions []string `json:"matrixDimensions,omitempty"`
 }
 
 // ResourceRequirements defines CPU and memory resource constraints
@@ -125,6 +294,22 @@ type SecretReference struct {
 	EnvVar string `json:"envVar,omitempty"`
 }
 
+// VaultSecretRef defines how to inject a HashiCorp Vault secret into a step.
+// Values are fetched when the step's Job is created.
+type VaultSecretRef struct {
+	// Path is the Vault secret path (e.g., "secret/data/ci/deploy")
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+
+	// Key is the key within the Vault secret
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+
+	// EnvVar is the environment variable name (defaults to key)
+	// +optional
+	EnvVar string `json:"envVar,omitempty"`
+}
+
 // ConditionalExecution defines conditions for step execution
 type ConditionalExecution struct {
 	// Branch pattern - execute only on matching branch
@@ -135,6 +320,11 @@ type ConditionalExecution struct {
 	// +kubebuilder:default=true
 	// +optional
 	OnSuccess *bool `json:"onSuccess,omitempty"`
+
+	// Environment lists the environments the step runs in; the step is
+	// skipped in other environments. Empty runs the step in every environment.
+	// +optional
+	Environment []string `json:"environment,omitempty"`
 }
 
 // MatrixStrategy defines matrix strategy for parallel execution
@@ -163,6 +353,158 @@ type RetryPolicy struct {
 	// +kubebuilder:default=60
 	// +optional
 	BackoffSeconds int `json:"backoffSeconds,omitempty"`
+
+	// OnlyOnExitCodes restricts retries to failures with these exit codes of
+	// the step container (e.g., [137] for OOM-killed Pods). Failures with any
+	// other exit code are not retried. Empty retries on any failure.
+	// +optional
+	OnlyOnExitCodes []int `json:"onlyOnExitCodes,omitempty"`
+}
+
+// SecurityContextSpec defines the security settings of a step container
+type SecurityContextSpec struct {
+	// RunAsUser is the UID to run the step commands as (image default if 0)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsUser int64 `json:"runAsUser,omitempty"`
+
+	// RunAsGroup is the GID to run the step commands as (image default if 0)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsGroup int64 `json:"runAsGroup,omitempty"`
+
+	// RunAsNonRoot rejects images that would run as root
+	// +optional
+	RunAsNonRoot bool `json:"runAsNonRoot,omitempty"`
+
+	// ReadOnlyRootFilesystem mounts the container's root filesystem
+	// read-only. Paths the step writes to other than the workspace, such as
+	// /tmp, need an emptyDir volume mount.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// AllowPrivilegeEscalation allows processes to gain more privileges than
+	// their parent, e.g. through setuid binaries
+	// +optional
+	AllowPrivilegeEscalation bool `json:"allowPrivilegeEscalation,omitempty"`
+}
+
+// NetworkPolicySpec defines egress restrictions applied to step Pods.
+// DNS resolution is always allowed so steps can resolve AllowedHosts.
+type NetworkPolicySpec struct {
+	// AllowInternet allows egress to public (non-RFC1918) addresses
+	// +optional
+	AllowInternet bool `json:"allowInternet,omitempty"`
+
+	// AllowCluster allows egress to Pods in any namespace of the cluster
+	// +optional
+	AllowCluster bool `json:"allowCluster,omitempty"`
+
+	// AllowedHosts are IP addresses or CIDR blocks steps may connect to
+	// (e.g., "10.0.0.5", "192.168.0.0/16")
+	// +optional
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+
+	// Egress are additional egress rules
+	// +optional
+	Egress []EgressRule `json:"egress,omitempty"`
+}
+
+// EgressRule allows egress to a CIDR block on specific ports
+type EgressRule struct {
+	// CIDR is the destination IP block (e.g., "10.0.0.0/8")
+	// +kubebuilder:validation:Required
+	CIDR string `json:"cidr"`
+
+	// Ports restricts the rule to these TCP ports (all ports if empty)
+	// +optional
+	Ports []int32 `json:"ports,omitempty"`
+}
+
+// ResourceQuotaSpec limits the resources used by active pipeline Jobs in a
+// namespace. Jobs are not created while a limit would be exceeded.
+type ResourceQuotaSpec struct {
+	// MaxConcurrentRuns is the maximum number of PipelineRuns with active Jobs
+	// (0 for no limit)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentRuns int `json:"maxConcurrentRuns,omitempty"`
+
+	// MaxCPUTotal is the maximum total CPU requested by active Jobs (e.g., "8")
+	// +optional
+	MaxCPUTotal string `json:"maxCPUTotal,omitempty"`
+
+	// MaxMemoryTotal is the maximum total memory requested by active Jobs (e.g., "16Gi")
+	// +optional
+	MaxMemoryTotal string `json:"maxMemoryTotal,omitempty"`
+}
+
+// VolumeSpec declares a volume for step containers. Exactly one source must be set.
+type VolumeSpec struct {
+	// Name is referenced by step volume mounts
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// PersistentVolumeClaim mounts an existing PersistentVolumeClaim
+	// +optional
+	PersistentVolumeClaim *PVCSpec `json:"persistentVolumeClaim,omitempty"`
+
+	// ConfigMap mounts the keys of a ConfigMap as files
+	// +optional
+	ConfigMap *ConfigMapVolumeSpec `json:"configMap,omitempty"`
+
+	// EmptyDir mounts an empty directory that lives as long as the step's Pod
+	// +optional
+	EmptyDir *EmptyDirVolumeSpec `json:"emptyDir,omitempty"`
+}
+
+// PVCSpec references an existing PersistentVolumeClaim
+type PVCSpec struct {
+	// ClaimName is the name of the PersistentVolumeClaim in the run's namespace
+	// +kubebuilder:validation:Required
+	ClaimName string `json:"claimName"`
+
+	// AccessMode is the access mode of the claim. It must be ReadOnlyMany
+	// when more than one step mounts the volume.
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadOnlyMany;ReadWriteMany
+	// +kubebuilder:default=ReadWriteOnce
+	// +optional
+	AccessMode string `json:"accessMode,omitempty"`
+}
+
+// ConfigMapVolumeSpec references a ConfigMap to mount as a volume
+type ConfigMapVolumeSpec struct {
+	// Name is the name of the ConfigMap in the run's namespace
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// EmptyDirVolumeSpec defines a scratch volume for a step
+type EmptyDirVolumeSpec struct {
+	// Medium is the storage medium of the directory; "Memory" uses a tmpfs
+	// +kubebuilder:validation:Enum=Memory
+	// +optional
+	Medium string `json:"medium,omitempty"`
+
+	// SizeLimit is the maximum size of the directory (e.g., "1Gi")
+	// +optional
+	SizeLimit string `json:"sizeLimit,omitempty"`
+}
+
+// VolumeMountSpec mounts a declared volume into a step container
+type VolumeMountSpec struct {
+	// Name of a volume declared in spec.volumes
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// MountPath is the absolute path in the container to mount the volume at
+	// +kubebuilder:validation:Required
+	MountPath string `json:"mountPath"`
+
+	// ReadOnly mounts the volume read-only
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
 }
 
 // PipelineConfigStatus defines the observed state of PipelineConfig
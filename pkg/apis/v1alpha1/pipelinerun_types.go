@@ -21,7 +21,7 @@ import (
 )
 
 // PipelineRunPhase represents the current phase of a PipelineRun
-// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Cancelled
+// +kubebuilder:validation:Enum=Pending;Running;Cancelling;Succeeded;Failed;Cancelled
 type PipelineRunPhase string
 
 const (
@@ -29,6 +29,9 @@ const (
 	PipelineRunPhasePending PipelineRunPhase = "Pending"
 	// PipelineRunPhaseRunning means at least one step is executing
 	PipelineRunPhaseRunning PipelineRunPhase = "Running"
+	// PipelineRunPhaseCancelling means cancellation was requested and the
+	// run's Jobs are being deleted
+	PipelineRunPhaseCancelling PipelineRunPhase = "Cancelling"
 	// PipelineRunPhaseSucceeded means all steps completed successfully
 	PipelineRunPhaseSucceeded PipelineRunPhase = "Succeeded"
 	// PipelineRunPhaseFailed means at least one step failed
@@ -54,6 +57,32 @@ const (
 	StepPhaseSkipped StepPhase = "Skipped"
 )
 
+// Pipeline run priorities. The controller maps each value to a PriorityClass
+// so that higher-priority runs can preempt lower-priority ones.
+const (
+	PriorityLow      = "low"
+	PriorityNormal   = "normal"
+	PriorityHigh     = "high"
+	PriorityCritical = "critical"
+)
+
+// ValidPriorities lists the accepted values for Priority, from lowest to highest
+var ValidPriorities = []string{PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical}
+
+// IsValidPriority reports whether p is a valid priority. An empty value is valid
+// and means no priority is set.
+func IsValidPriority(p string) bool {
+	if p == "" {
+		return true
+	}
+	for _, valid := range ValidPriorities {
+		if p == valid {
+			return true
+		}
+	}
+	return false
+}
+
 // PipelineRunSpec defines the desired state of PipelineRun
 type PipelineRunSpec struct {
 	// PipelineConfigRef is the reference to the PipelineConfig name
@@ -90,6 +119,19 @@ type PipelineRunSpec struct {
 	// Author is the commit author email
 	// +optional
 	Author string `json:"author,omitempty"`
+
+	// Priority sets the scheduling priority of the run's Pods, overriding the
+	// PipelineConfig default
+	// +kubebuilder:validation:Enum=low;normal;high;critical
+	// +optional
+	Priority string `json:"priority,omitempty"`
+
+	// Environment is the environment the run executes in, overriding the
+	// PipelineConfig default. Webhook-triggered runs take it from the
+	// c8s.dev/environment label of their namespace.
+	// +kubebuilder:validation:Enum=development;staging;production
+	// +optional
+	Environment string `json:"environment,omitempty"`
 }
 
 // PipelineRunStatus defines the observed state of PipelineRun
@@ -106,6 +148,11 @@ type PipelineRunStatus struct {
 	// +optional
 	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 
+	// QueueTime is how long the run waited from creation until its first
+	// step started. It is set once, when the first step starts.
+	// +optional
+	QueueTime *metav1.Duration `json:"queueTime,omitempty"`
+
 	// Steps contains the status of each step in the pipeline
 	// +optional
 	Steps []StepStatus `json:"steps,omitempty"`
@@ -160,6 +207,10 @@ type StepStatus struct {
 	// +optional
 	ExitCode *int32 `json:"exitCode,omitempty"`
 
+	// Retries is the number of times the step was retried after a failure
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+
 	// LogURL is the object storage URL for the step's logs
 	// +optional
 	LogURL string `json:"logURL,omitempty"`
@@ -171,6 +222,31 @@ type StepStatus struct {
 	// Message provides additional context about the step status
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// AliasOf is the step this step was merged into by
+	// spec.deduplicateSteps; the status mirrors that step's
+	// +optional
+	AliasOf string `json:"aliasOf,omitempty"`
+
+	// History holds the last phase transitions of the step, oldest first
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	History []StepStatusTransition `json:"history,omitempty"`
+}
+
+// StepStatusTransition records a phase a step entered
+type StepStatusTransition struct {
+	// Phase is the phase the step entered
+	// +kubebuilder:validation:Required
+	Phase StepPhase `json:"phase"`
+
+	// Timestamp is when the transition was observed
+	// +kubebuilder:validation:Required
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Message provides additional context about the transition
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -178,10 +254,14 @@ type StepStatus struct {
 // +kubebuilder:resource:shortName=pr
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="Config",type=string,JSONPath=`.spec.pipelineConfigRef`
+// +kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.conditions[?(@.type=="StepsCompleted")].message`
+// +kubebuilder:printcolumn:name="Jobs",type=string,JSONPath=`.status.conditions[?(@.type=="JobsCreated")].reason`,priority=1
+// +kubebuilder:printcolumn:name="Logs",type=string,JSONPath=`.status.conditions[?(@.type=="LogsCollected")].reason`,priority=1
 // +kubebuilder:printcolumn:name="Commit",type=string,JSONPath=`.spec.commit`,priority=1
 // +kubebuilder:printcolumn:name="Branch",type=string,JSONPath=`.spec.branch`,priority=1
 // +kubebuilder:printcolumn:name="Started",type=date,JSONPath=`.status.startTime`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:selectablefield:JSONPath=`.status.phase`
 
 // PipelineRun is the Schema for the pipelineruns API
 type PipelineRun struct {
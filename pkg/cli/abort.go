@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	ctypes "github.com/org/c8s/pkg/types"
+)
+
+// abortCommand deletes all Jobs of a PipelineRun directly and marks the run
+// Cancelled, without waiting for the controller. It is meant as an escape
+// hatch when the controller is hung or a run is consuming runaway resources.
+//
+// The run is marked Cancelling with the c8s.dev/cancel-requested annotation
+// before its Jobs are deleted, so a running controller treats the missing
+// Jobs as a cancellation rather than Jobs deleted externally.
+func abortCommand(args []string) error {
+	fs := flag.NewFlagSet("abort", flag.ExitOnError)
+	reason := fs.String("reason", "", "reason for aborting, recorded in the c8s.dev/abort-reason annotation")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("pipeline run name required")
+	}
+
+	runName := fs.Arg(0)
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	ctx := context.Background()
+	runs := dynamicClient.Resource(pipelineRunGVR).Namespace(namespace)
+
+	run, err := runs.Get(ctx, runName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get PipelineRun: %w", err)
+	}
+
+	phase, _, _ := unstructured.NestedString(run.Object, "status", "phase")
+	switch c8sv1alpha1.PipelineRunPhase(phase) {
+	case c8sv1alpha1.PipelineRunPhaseSucceeded, c8sv1alpha1.PipelineRunPhaseFailed, c8sv1alpha1.PipelineRunPhaseCancelled:
+		return fmt.Errorf("PipelineRun %s is already %s", runName, phase)
+	}
+
+	annotations := map[string]string{
+		ctypes.AnnotationCancelRequested: time.Now().UTC().Format(time.RFC3339),
+	}
+	if *reason != "" {
+		annotations[ctypes.AnnotationAbortReason] = *reason
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := runs.Patch(ctx, runName, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to annotate PipelineRun: %w", err)
+	}
+
+	cancellingPatch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase": string(c8sv1alpha1.PipelineRunPhaseCancelling),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := runs.Patch(ctx, runName, k8stypes.MergePatchType, cancellingPatch, metav1.PatchOptions{}, "status"); err != nil {
+		return fmt.Errorf("failed to mark PipelineRun as Cancelling: %w", err)
+	}
+
+	// Delete the Jobs directly instead of going through the controller
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", ctypes.LabelPipelineRun, runName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list Jobs: %w", err)
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	for _, job := range jobs.Items {
+		// A running controller may delete the Job first
+		if err := clientset.BatchV1().Jobs(namespace).Delete(ctx, job.Name, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Job %s: %w", job.Name, err)
+		}
+		fmt.Println(job.Name)
+	}
+
+	statusPatch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":          string(c8sv1alpha1.PipelineRunPhaseCancelled),
+			"completionTime": time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := runs.Patch(ctx, runName, k8stypes.MergePatchType, statusPatch, metav1.PatchOptions{}, "status"); err != nil {
+		return fmt.Errorf("failed to mark PipelineRun as Cancelled: %w", err)
+	}
+
+	return nil
+}
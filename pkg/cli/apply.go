@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	v1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// allowedHostsEnv lists the trusted hosts of --file-url when --allowed-hosts is not set
+const allowedHostsEnv = "C8S_ALLOWED_HOSTS"
+
+// applyCommand creates or updates a PipelineConfig from a local or remote pipeline file
+func applyCommand(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	file := fs.String("file", "", "pipeline YAML file")
+	fileURL := fs.String("file-url", "", "https:// or s3:// URL of a pipeline YAML file")
+	name := fs.String("name", "", "PipelineConfig name (default: the file name without .c8s.yaml)")
+	repository := fs.String("repository", "", "Git repository URL (required unless the PipelineConfig exists)")
+	allowedHosts := fs.String("allowed-hosts", os.Getenv(allowedHostsEnv), "comma-separated hosts (or S3 buckets) --file-url may download from")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if (*file == "") == (*fileURL == "") {
+		return fmt.Errorf("exactly one of --file and --file-url is required")
+	}
+
+	var spec *v1alpha1.PipelineConfigSpec
+	source := *file
+	if *fileURL != "" {
+		for _, host := range strings.Split(*allowedHosts, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				parser.AllowedHosts = append(parser.AllowedHosts, host)
+			}
+		}
+
+		var err error
+		if spec, err = parser.ParseFromURL(*fileURL); err != nil {
+			return err
+		}
+		u, _ := url.Parse(*fileURL)
+		source = u.Path
+	} else {
+		yamlContent, err := os.ReadFile(*file)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		if spec, err = parser.ParseBytes(yamlContent); err != nil {
+			return err
+		}
+	}
+
+	configName := *name
+	if configName == "" {
+		configName = pipelineNameFromPath(source)
+	}
+	if configName == "" {
+		return fmt.Errorf("cannot derive a PipelineConfig name from %s, use --name", source)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	ctx := context.Background()
+	configs := dynamicClient.Resource(pipelineConfigGVR).Namespace(namespace)
+
+	// The pipeline file has no repository; an existing PipelineConfig keeps its own
+	existing, err := configs.Get(ctx, configName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get PipelineConfig: %w", err)
+	}
+	spec.Repository = *repository
+	if spec.Repository == "" && existing != nil {
+		spec.Repository, _, _ = unstructured.NestedString(existing.Object, "spec", "repository")
+	}
+
+	if err := parser.Validate(&v1alpha1.PipelineConfig{Spec: *spec}); err != nil {
+		return fmt.Errorf("invalid pipeline configuration: %w", err)
+	}
+
+	specObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return fmt.Errorf("failed to convert pipeline spec: %w", err)
+	}
+
+	if existing == nil {
+		config := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "c8s.io/v1alpha1",
+				"kind":       "PipelineConfig",
+				"metadata": map[string]interface{}{
+					"name":      configName,
+					"namespace": namespace,
+				},
+				"spec": specObject,
+			},
+		}
+		if _, err := configs.Create(ctx, config, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create PipelineConfig: %w", err)
+		}
+		fmt.Printf("PipelineConfig created: %s\n", configName)
+		return nil
+	}
+
+	existing.Object["spec"] = specObject
+	if _, err := configs.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update PipelineConfig: %w", err)
+	}
+	fmt.Printf("PipelineConfig configured: %s\n", configName)
+	return nil
+}
+
+// pipelineNameFromPath returns the PipelineConfig name of a pipeline file:
+// its base name without the .c8s.yaml, .yaml or .yml extension
+func pipelineNameFromPath(p string) string {
+	base := path.Base(strings.ReplaceAll(p, "\\", "/"))
+	for _, ext := range []string{".c8s.yaml", ".c8s.yml", ".yaml", ".yml"} {
+		if trimmed, ok := strings.CutSuffix(base, ext); ok {
+			base = trimmed
+			break
+		}
+	}
+	if base == "." || base == "/" || strings.HasPrefix(base, ".") {
+		return ""
+	}
+	return base
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle converts PipelineRuns to and from the portable bundles used
+// to move them between clusters.
+package bundle
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	ctypes "github.com/org/c8s/pkg/types"
+)
+
+// Kind is the kind of a bundle written by `c8s run export`
+const Kind = "PipelineRunBundle"
+
+// clusterFields are the metadata fields that only make sense in the cluster
+// an object was read from
+var clusterFields = []string{
+	"namespace", "resourceVersion", "uid", "generation", "managedFields",
+	"creationTimestamp", "selfLink", "finalizers", "ownerReferences",
+}
+
+// RunBundle is a portable copy of a PipelineRun and its PipelineConfig that
+// can be imported into another cluster
+type RunBundle struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// ExportedFrom is the kubeconfig context the run was exported from
+	ExportedFrom string `json:"exportedFrom,omitempty"`
+
+	PipelineConfig map[string]interface{} `json:"pipelineConfig"`
+
+	// PipelineRun keeps its status, so the step history survives the import
+	PipelineRun map[string]interface{} `json:"pipelineRun"`
+
+	// LogURLs maps step names to their stored logs
+	LogURLs map[string]string `json:"logURLs,omitempty"`
+}
+
+// New creates a bundle from a PipelineRun and its PipelineConfig,
+// stripping cluster-specific fields. The status of the config is dropped;
+// the status of the run is kept.
+func New(run, config *unstructured.Unstructured, exportedFrom string) *RunBundle {
+	run = run.DeepCopy()
+	config = config.DeepCopy()
+	stripClusterFields(run)
+	stripClusterFields(config)
+	unstructured.RemoveNestedField(config.Object, "status")
+
+	bundle := &RunBundle{
+		APIVersion:     run.GetAPIVersion(),
+		Kind:           Kind,
+		ExportedFrom:   exportedFrom,
+		PipelineConfig: config.Object,
+		PipelineRun:    run.Object,
+	}
+
+	steps, _, _ := unstructured.NestedSlice(run.Object, "status", "steps")
+	for _, raw := range steps {
+		step, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(step, "name")
+		logURL, _, _ := unstructured.NestedString(step, "logURL")
+		if name != "" && logURL != "" {
+			if bundle.LogURLs == nil {
+				bundle.LogURLs = map[string]string{}
+			}
+			bundle.LogURLs[name] = logURL
+		}
+	}
+
+	return bundle
+}
+
+// Parse reads a JSON or YAML bundle written by `c8s run export`
+func Parse(data []byte) (*RunBundle, error) {
+	bundle := &RunBundle{}
+	if err := yaml.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("invalid run bundle: %w", err)
+	}
+	if bundle.Kind != Kind {
+		return nil, fmt.Errorf("invalid run bundle: kind is %q, expected %s", bundle.Kind, Kind)
+	}
+	if bundle.PipelineRun == nil || bundle.PipelineConfig == nil {
+		return nil, fmt.Errorf("invalid run bundle: pipelineRun and pipelineConfig are required")
+	}
+	return bundle, nil
+}
+
+// ImportObjects returns the PipelineConfig and PipelineRun to create in
+// namespace, and the status to set on the created run. The run is annotated
+// with AnnotationImportedFrom so the controller does not execute it, its
+// step log URLs are restored from the bundle, and an Imported condition is
+// added to its status.
+func (b *RunBundle) ImportObjects(namespace string) (config, run *unstructured.Unstructured, status map[string]interface{}) {
+	config = (&unstructured.Unstructured{Object: b.PipelineConfig}).DeepCopy()
+	config.SetNamespace(namespace)
+
+	run = (&unstructured.Unstructured{Object: b.PipelineRun}).DeepCopy()
+	run.SetNamespace(namespace)
+
+	importedFrom := b.ExportedFrom
+	if importedFrom == "" {
+		importedFrom = "unknown"
+	}
+	annotations := run.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ctypes.AnnotationImportedFrom] = importedFrom
+	run.SetAnnotations(annotations)
+
+	status, _, _ = unstructured.NestedMap(run.Object, "status")
+	unstructured.RemoveNestedField(run.Object, "status")
+	if status == nil {
+		status = map[string]interface{}{}
+	}
+
+	// Restore log URLs so the logs of the run stay reachable
+	steps, _, _ := unstructured.NestedSlice(status, "steps")
+	for _, raw := range steps {
+		step, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(step, "name")
+		if logURL, ok := b.LogURLs[name]; ok {
+			step["logURL"] = logURL
+		}
+	}
+	if steps != nil {
+		status["steps"] = steps
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+	status["conditions"] = append(conditions, map[string]interface{}{
+		"type":               ctypes.ConditionTypeImported,
+		"status":             string(metav1.ConditionTrue),
+		"reason":             ctypes.ReasonRunImported,
+		"message":            fmt.Sprintf("Imported from %s", importedFrom),
+		"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+	})
+
+	return config, run, status
+}
+
+// stripClusterFields removes the cluster-specific metadata of an object
+func stripClusterFields(obj *unstructured.Unstructured) {
+	for _, field := range clusterFields {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+}
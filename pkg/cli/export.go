@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"github.com/org/c8s/pkg/cli/bundle"
+)
+
+// exportCommand writes a PipelineRun and its PipelineConfig to a bundle
+func exportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("output", "yaml", "bundle format (yaml|json)")
+	file := fs.String("file", "", "file to write the bundle to (default stdout)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("pipeline run name required")
+	}
+	if *output != "yaml" && *output != "json" {
+		return fmt.Errorf("invalid --output %q: must be yaml or json", *output)
+	}
+
+	runName := fs.Arg(0)
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	ctx := context.Background()
+
+	run, err := dynamicClient.Resource(pipelineRunGVR).Namespace(namespace).Get(ctx, runName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get PipelineRun: %w", err)
+	}
+
+	spec, _, _ := unstructured.NestedMap(run.Object, "spec")
+	configName := pipelineConfigName(spec)
+	config, err := dynamicClient.Resource(pipelineConfigGVR).Namespace(namespace).Get(ctx, configName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get PipelineConfig %s: %w", configName, err)
+	}
+
+	runBundle := bundle.New(run, config, currentContext())
+
+	var data []byte
+	if *output == "json" {
+		data, err = json.MarshalIndent(runBundle, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(runBundle)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+
+	if *file == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*file, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	fmt.Printf("PipelineRun %s exported to %s\n", runName, *file)
+	return nil
+}
+
+// importCommand creates the PipelineConfig and PipelineRun of a bundle in
+// the current cluster, restoring the run's status
+func importCommand(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "bundle written by `c8s run export` (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return fmt.Errorf("--file flag is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+	runBundle, err := bundle.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	ctx := context.Background()
+	config, run, status := runBundle.ImportObjects(namespace)
+
+	// An existing PipelineConfig is kept, since other runs may depend on it
+	_, err = dynamicClient.Resource(pipelineConfigGVR).Namespace(namespace).Create(ctx, config, metav1.CreateOptions{})
+	switch {
+	case apierrors.IsAlreadyExists(err):
+		fmt.Printf("PipelineConfig %s already exists, keeping it\n", config.GetName())
+	case err != nil:
+		return fmt.Errorf("failed to create PipelineConfig: %w", err)
+	default:
+		fmt.Printf("PipelineConfig created: %s\n", config.GetName())
+	}
+
+	runs := dynamicClient.Resource(pipelineRunGVR).Namespace(namespace)
+	created, err := runs.Create(ctx, run, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create PipelineRun: %w", err)
+	}
+
+	created.Object["status"] = status
+	if _, err := runs.UpdateStatus(ctx, created, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to restore PipelineRun status: %w", err)
+	}
+
+	fmt.Printf("PipelineRun imported: %s\n", created.GetName())
+	if len(runBundle.LogURLs) > 0 {
+		fmt.Printf("\nTo view logs:\n  c8s logs %s --step=<step-name>\n", created.GetName())
+	}
+	return nil
+}
+
+// currentContext returns the current kubeconfig context, or "" if unknown
+func currentContext() string {
+	config, err := clientcmd.LoadFromFile(kubeconfig)
+	if err != nil {
+		return ""
+	}
+	return config.CurrentContext
+}
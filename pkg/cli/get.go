@@ -5,6 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -13,6 +15,9 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+
+	v1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
 )
 
 var pipelineConfigGVR = schema.GroupVersionResource{
@@ -21,8 +26,42 @@ var pipelineConfigGVR = schema.GroupVersionResource{
 	Resource: "pipelineconfigs",
 }
 
+// runListOptions filters the PipelineRuns listed by "get runs"
+type runListOptions struct {
+	// since only shows runs started within this duration (0 for no limit)
+	since time.Duration
+
+	// fieldSelector is passed through to the API server
+	fieldSelector string
+
+	// labelSelector is passed through to the API server
+	labelSelector string
+
+	// wide adds the TRIGGERED-BY, COMMIT-MESSAGE, REGISTRY, DURATION and
+	// STEPS-DONE/TOTAL columns to the table
+	wide bool
+
+	// groupBy groups the table by parent ("config" or "" for a flat list)
+	groupBy string
+
+	// perConfig is the number of most recent runs shown per config when
+	// grouping by config
+	perConfig int
+}
+
+// maxCommitMessageWidth is the width of the COMMIT-MESSAGE column in wide output
+const maxCommitMessageWidth = 40
+
+// defaultRunsPerConfig is the default number of runs shown per config with
+// --group-by config
+const defaultRunsPerConfig = 5
+
 func getCommand(args []string) error {
 	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	since := fs.String("since", "", "Only show runs started within this duration (e.g. 24h, 7d)")
+	fieldSelector := fs.String("field-selector", "", "Field selector passed to the API server (e.g. status.phase=Failed)")
+	labelSelector := fs.String("label-selector", "", "Label selector passed to the API server (e.g. c8s.dev/branch=main)")
+	output := fs.String("output", "", "Output format for runs: wide")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -31,15 +70,31 @@ func getCommand(args []string) error {
 		return fmt.Errorf("resource type required (runs, configs)")
 	}
 
+	// Parse again after the resource type so flags may follow it
 	resourceType := fs.Arg(0)
-	resourceName := ""
-	if fs.NArg() > 1 {
-		resourceName = fs.Arg(1)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
 	}
+	resourceName := fs.Arg(0)
 
 	switch resourceType {
 	case "runs", "run", "pipelineruns", "pipelinerun":
-		return getRuns(resourceName)
+		if *output != "" && *output != "wide" {
+			return fmt.Errorf("unsupported --output format %q (supported: wide)", *output)
+		}
+		opts := runListOptions{
+			fieldSelector: *fieldSelector,
+			labelSelector: *labelSelector,
+			wide:          *output == "wide",
+		}
+		if *since != "" {
+			duration, err := parseSinceDuration(*since)
+			if err != nil {
+				return err
+			}
+			opts.since = duration
+		}
+		return getRuns(resourceName, opts)
 	case "configs", "config", "pipelineconfigs", "pipelineconfig":
 		return getConfigs(resourceName)
 	default:
@@ -47,7 +102,7 @@ func getCommand(args []string) error {
 	}
 }
 
-func getRuns(name string) error {
+func getRuns(name string, opts runListOptions) error {
 	dynamicClient, err := dynamic.NewForConfig(restConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create dynamic client: %w", err)
@@ -72,56 +127,338 @@ func getRuns(name string) error {
 	// List all runs
 	list, err := dynamicClient.Resource(pipelineRunGVR).Namespace(namespace).List(
 		ctx,
-		metav1.ListOptions{},
+		metav1.ListOptions{
+			FieldSelector: opts.fieldSelector,
+			LabelSelector: opts.labelSelector,
+		},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to list PipelineRuns: %w", err)
 	}
 
-	if len(list.Items) == 0 {
+	// The API server cannot filter on timestamps, so --since is applied
+	// client-side after the full list has been fetched
+	items := list.Items
+	if opts.since > 0 {
+		items = filterRunsSince(items, time.Now().Add(-opts.since))
+	}
+
+	if len(items) == 0 {
 		fmt.Println("No PipelineRuns found")
 		return nil
 	}
 
+	if len(items) != len(list.Items) {
+		fmt.Printf("Showing %d of %d PipelineRuns.\n\n", len(items), len(list.Items))
+	}
+
+	if opts.groupBy == "config" {
+		printRunsByConfig(items, opts)
+		return nil
+	}
+
 	// Print table
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NAME\tCONFIG\tCOMMIT\tBRANCH\tPHASE\tAGE")
+	fmt.Fprintln(w, runTableHeader(opts.wide))
+	for _, item := range items {
+		fmt.Fprintln(w, runTableRow(item, opts.wide))
+	}
 
-	for _, item := range list.Items {
-		spec, _, _ := unstructured.NestedMap(item.Object, "spec")
-		status, _, _ := unstructured.NestedMap(item.Object, "status")
+	w.Flush()
+	return nil
+}
+
+// runListCommand lists PipelineRuns like "get runs", with --group-by config
+// to organise them under their parent PipelineConfig
+func runListCommand(args []string) error {
+	fs := flag.NewFlagSet("run list", flag.ExitOnError)
+	since := fs.String("since", "", "Only show runs started within this duration (e.g. 24h, 7d)")
+	fieldSelector := fs.String("field-selector", "", "Field selector passed to the API server (e.g. status.phase=Failed)")
+	labelSelector := fs.String("label-selector", "", "Label selector passed to the API server (e.g. c8s.dev/branch=main)")
+	output := fs.String("output", "", "Output format: wide")
+	groupBy := fs.String("group-by", "", "Group runs under their parent: config")
+	perConfig := fs.Int("per-config", defaultRunsPerConfig, "Most recent runs shown per config with --group-by config")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *output != "" && *output != "wide" {
+		return fmt.Errorf("unsupported --output format %q (supported: wide)", *output)
+	}
+	if *groupBy != "" && *groupBy != "config" {
+		return fmt.Errorf("unsupported --group-by %q (supported: config)", *groupBy)
+	}
+	if *perConfig < 1 {
+		return fmt.Errorf("--per-config must be at least 1")
+	}
+
+	opts := runListOptions{
+		fieldSelector: *fieldSelector,
+		labelSelector: *labelSelector,
+		wide:          *output == "wide",
+		groupBy:       *groupBy,
+		perConfig:     *perConfig,
+	}
+	if *since != "" {
+		duration, err := parseSinceDuration(*since)
+		if err != nil {
+			return err
+		}
+		opts.since = duration
+	}
+	return getRuns("", opts)
+}
+
+// runTableHeader returns the tab-separated header of the runs table
+func runTableHeader(wide bool) string {
+	if wide {
+		return "NAME\tCONFIG\tCOMMIT\tBRANCH\tPHASE\tAGE\tTRIGGERED-BY\tCOMMIT-MESSAGE\tREGISTRY\tDURATION\tSTEPS-DONE/TOTAL"
+	}
+	return "NAME\tCONFIG\tCOMMIT\tBRANCH\tPHASE\tAGE"
+}
+
+// runTableRow returns the tab-separated row of a run in the runs table
+func runTableRow(item unstructured.Unstructured, wide bool) string {
+	commit, _, _ := unstructured.NestedString(item.Object, "spec", "commit")
+	branch, _, _ := unstructured.NestedString(item.Object, "spec", "branch")
+
+	// Truncate commit to 7 chars
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+
+	// Calculate age
+	creationTimestamp := item.GetCreationTimestamp()
+	age := time.Since(creationTimestamp.Time).Round(time.Second)
+
+	row := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s",
+		item.GetName(),
+		runConfigName(item),
+		commit,
+		branch,
+		runPhase(item),
+		formatDuration(age),
+	)
+	if wide {
+		row += "\t" + wideRunColumns(item)
+	}
+	return row
+}
+
+// runConfigName returns the name of the PipelineConfig of a run
+func runConfigName(run unstructured.Unstructured) string {
+	name, _, _ := unstructured.NestedString(run.Object, "spec", "pipelineConfigRef", "name")
+	return name
+}
+
+// runPhase returns the phase of a run, Pending until the controller sets one
+func runPhase(run unstructured.Unstructured) string {
+	phase, _, _ := unstructured.NestedString(run.Object, "status", "phase")
+	if phase == "" {
+		return "Pending"
+	}
+	return phase
+}
+
+// configRunStats summarises the runs of a PipelineConfig
+type configRunStats struct {
+	total     int
+	succeeded int
+	finished  int
+
+	// totalDuration sums the durations of the finished runs with a start and
+	// completion time, counted by timed
+	totalDuration time.Duration
+	timed         int
+}
+
+// passRate returns the percentage of finished runs that succeeded, or "-"
+// when none has finished
+func (s configRunStats) passRate() string {
+	if s.finished == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", s.succeeded*100/s.finished)
+}
+
+// averageDuration returns the average duration of the finished runs, or "-"
+func (s configRunStats) averageDuration() string {
+	if s.timed == 0 {
+		return "-"
+	}
+	return formatDuration((s.totalDuration / time.Duration(s.timed)).Round(time.Second))
+}
 
-		configRef, _, _ := unstructured.NestedMap(spec, "pipelineConfigRef")
-		configName, _, _ := unstructured.NestedString(configRef, "name")
-		commit, _, _ := unstructured.NestedString(spec, "commit")
-		branch, _, _ := unstructured.NestedString(spec, "branch")
-		phase, _, _ := unstructured.NestedString(status, "phase")
+// printRunsByConfig prints one section per PipelineConfig, sorted by name,
+// with summary stats over all its runs followed by its most recent runs
+func printRunsByConfig(items []unstructured.Unstructured, opts runListOptions) {
+	groups := make(map[string][]unstructured.Unstructured)
+	for _, item := range items {
+		name := runConfigName(item)
+		groups[name] = append(groups[name], item)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-		if phase == "" {
-			phase = "Pending"
+	for i, name := range names {
+		runs := groups[name]
+		sort.SliceStable(runs, func(a, b int) bool {
+			return runs[a].GetCreationTimestamp().Time.After(runs[b].GetCreationTimestamp().Time)
+		})
+
+		stats := summarizeConfigRuns(runs)
+		if i > 0 {
+			fmt.Println()
 		}
+		fmt.Printf("%s  (runs: %d, pass rate: %s, avg duration: %s)\n",
+			valueOrNone(name), stats.total, stats.passRate(), stats.averageDuration())
 
-		// Truncate commit to 7 chars
-		if len(commit) > 7 {
-			commit = commit[:7]
+		shown := runs
+		if len(shown) > opts.perConfig {
+			shown = shown[:opts.perConfig]
 		}
 
-		// Calculate age
-		creationTimestamp := item.GetCreationTimestamp()
-		age := time.Since(creationTimestamp.Time).Round(time.Second)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "  "+runTableHeader(opts.wide))
+		for _, run := range shown {
+			fmt.Fprintln(w, "  "+runTableRow(run, opts.wide))
+		}
+		w.Flush()
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			item.GetName(),
-			configName,
-			commit,
-			branch,
-			phase,
-			formatDuration(age),
-		)
+		if hidden := len(runs) - len(shown); hidden > 0 {
+			fmt.Printf("  ... %d older runs not shown (--per-config)\n", hidden)
+		}
 	}
+}
 
-	w.Flush()
-	return nil
+// summarizeConfigRuns computes the summary stats of the runs of a config
+func summarizeConfigRuns(runs []unstructured.Unstructured) configRunStats {
+	stats := configRunStats{total: len(runs)}
+	for _, run := range runs {
+		switch v1alpha1.PipelineRunPhase(runPhase(run)) {
+		case v1alpha1.PipelineRunPhaseSucceeded:
+			stats.succeeded++
+			stats.finished++
+		case v1alpha1.PipelineRunPhaseFailed:
+			stats.finished++
+		default:
+			continue
+		}
+
+		startTime, _, _ := unstructured.NestedString(run.Object, "status", "startTime")
+		completionTime, _, _ := unstructured.NestedString(run.Object, "status", "completionTime")
+		start, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, completionTime)
+		if err != nil {
+			continue
+		}
+		stats.totalDuration += end.Sub(start)
+		stats.timed++
+	}
+	return stats
+}
+
+// wideRunColumns returns the tab-separated extra columns of a run in wide output
+func wideRunColumns(run unstructured.Unstructured) string {
+	triggeredBy, _, _ := unstructured.NestedString(run.Object, "spec", "triggeredBy")
+	commitMessage, _, _ := unstructured.NestedString(run.Object, "spec", "commitMessage")
+	steps, _, _ := unstructured.NestedSlice(run.Object, "status", "steps")
+
+	// Only the first line of the commit message fits in a table cell
+	commitMessage, _, _ = strings.Cut(commitMessage, "\n")
+	if len(commitMessage) > maxCommitMessageWidth {
+		commitMessage = commitMessage[:maxCommitMessageWidth-3] + "..."
+	}
+
+	// Runs are triggered through a RepositoryConnection, recorded as a label
+	registry := run.GetLabels()[types.LabelRepository]
+
+	done := 0
+	for _, stepObj := range steps {
+		step, _ := stepObj.(map[string]interface{})
+		phase, _ := step["phase"].(string)
+		switch v1alpha1.StepPhase(phase) {
+		case v1alpha1.StepPhaseSucceeded, v1alpha1.StepPhaseFailed, v1alpha1.StepPhaseSkipped:
+			done++
+		}
+	}
+
+	return strings.Join([]string{
+		valueOrNone(triggeredBy),
+		valueOrNone(commitMessage),
+		valueOrNone(registry),
+		runDuration(run),
+		fmt.Sprintf("%d/%d", done, len(steps)),
+	}, "\t")
+}
+
+// runDuration returns how long a run has been executing, or ran for if it has
+// completed. Runs that have not started yet show "-".
+func runDuration(run unstructured.Unstructured) string {
+	startTime, _, _ := unstructured.NestedString(run.Object, "status", "startTime")
+	start, err := time.Parse(time.RFC3339, startTime)
+	if err != nil {
+		return "-"
+	}
+
+	end := time.Now()
+	if completionTime, _, _ := unstructured.NestedString(run.Object, "status", "completionTime"); completionTime != "" {
+		if parsed, err := time.Parse(time.RFC3339, completionTime); err == nil {
+			end = parsed
+		}
+	}
+	return formatDuration(end.Sub(start).Round(time.Second))
+}
+
+// valueOrNone returns value, or "<none>" when it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+// filterRunsSince returns the runs whose status.startTime is at or after threshold.
+// Runs that have not started yet fall back to their creation timestamp.
+func filterRunsSince(runs []unstructured.Unstructured, threshold time.Time) []unstructured.Unstructured {
+	var filtered []unstructured.Unstructured
+	for _, run := range runs {
+		started := run.GetCreationTimestamp().Time
+		if startTime, found, _ := unstructured.NestedString(run.Object, "status", "startTime"); found {
+			if parsed, err := time.Parse(time.RFC3339, startTime); err == nil {
+				started = parsed
+			}
+		}
+		if !started.Before(threshold) {
+			filtered = append(filtered, run)
+		}
+	}
+	return filtered
+}
+
+// parseSinceDuration parses a --since value. In addition to time.ParseDuration
+// units it accepts whole days, e.g. "7d".
+func parseSinceDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --since duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid --since duration %q (use e.g. 30m, 24h, 7d)", value)
+	}
+	return duration, nil
 }
 
 func getConfigs(name string) error {
@@ -206,6 +543,7 @@ func printRunDetails(run *unstructured.Unstructured) error {
 	phase, _, _ := unstructured.NestedString(status, "phase")
 	startTime, _, _ := unstructured.NestedString(status, "startTime")
 	completionTime, _, _ := unstructured.NestedString(status, "completionTime")
+	queueTime, _, _ := unstructured.NestedString(status, "queueTime")
 	steps, _, _ := unstructured.NestedSlice(status, "steps")
 
 	if phase == "" {
@@ -222,6 +560,9 @@ func printRunDetails(run *unstructured.Unstructured) error {
 	fmt.Printf("Phase:           %s\n", phase)
 	fmt.Printf("Start Time:      %s\n", startTime)
 	fmt.Printf("Completion Time: %s\n", completionTime)
+	if queued, err := time.ParseDuration(queueTime); err == nil {
+		fmt.Printf("Queue Time:      %s\n", formatDuration(queued))
+	}
 
 	if len(steps) > 0 {
 		fmt.Println("\nSteps:")
@@ -248,11 +589,41 @@ func printRunDetails(run *unstructured.Unstructured) error {
 				name, stepPhase, jobName, exitCode, duration)
 		}
 		w.Flush()
+
+		printStepHistory(steps)
 	}
 
 	return nil
 }
 
+// printStepHistory prints the phase transitions recorded for each step
+func printStepHistory(steps []interface{}) {
+	header := false
+	for _, stepObj := range steps {
+		step, _ := stepObj.(map[string]interface{})
+		name, _ := step["name"].(string)
+		history, _ := step["history"].([]interface{})
+		if len(history) == 0 {
+			continue
+		}
+
+		if !header {
+			fmt.Println("\nStep History:")
+			header = true
+		}
+		fmt.Printf("  %s:\n", name)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		for _, transitionObj := range history {
+			transition, _ := transitionObj.(map[string]interface{})
+			phase, _ := transition["phase"].(string)
+			timestamp, _ := transition["timestamp"].(string)
+			message, _ := transition["message"].(string)
+			fmt.Fprintf(w, "    %s\t%s\t%s\n", timestamp, phase, message)
+		}
+		w.Flush()
+	}
+}
+
 func printConfigDetails(config *unstructured.Unstructured) error {
 	spec, _, _ := unstructured.NestedMap(config.Object, "spec")
 
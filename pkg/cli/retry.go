@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/scheduler"
+	ctypes "github.com/org/c8s/pkg/types"
+)
+
+// triggeredByRetry is the triggeredBy value for runs created by `c8s run retry`
+const triggeredByRetry = "manual-retry"
+
+func retryCommand(args []string) error {
+	fs := flag.NewFlagSet("retry", flag.ExitOnError)
+	fromStep := fs.String("from-step", "", "re-run only this step, its dependents, and steps that failed or were not reached")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("pipeline run name required")
+	}
+
+	originalName := fs.Arg(0)
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	ctx := context.Background()
+
+	original, err := dynamicClient.Resource(pipelineRunGVR).Namespace(namespace).Get(
+		ctx,
+		originalName,
+		metav1.GetOptions{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get PipelineRun: %w", err)
+	}
+
+	phase, _, _ := unstructured.NestedString(original.Object, "status", "phase")
+	if phase != string(c8sv1alpha1.PipelineRunPhaseFailed) {
+		return fmt.Errorf("PipelineRun %s is %s; only failed runs can be retried", originalName, phaseOrPending(phase))
+	}
+
+	spec, _, _ := unstructured.NestedMap(original.Object, "spec")
+	configName := pipelineConfigName(spec)
+
+	// Build the new spec from the original run
+	newSpec := map[string]interface{}{
+		"triggeredBy": triggeredByRetry,
+		"triggeredAt": time.Now().Format(time.RFC3339),
+	}
+	for _, field := range []string{"pipelineConfigRef", "commit", "branch", "variables"} {
+		if value, ok := spec[field]; ok {
+			newSpec[field] = value
+		}
+	}
+
+	annotations := map[string]interface{}{
+		ctypes.AnnotationRetryOf: originalName,
+	}
+
+	if *fromStep != "" {
+		preCompleted, err := preCompletedSteps(ctx, dynamicClient, original, configName, *fromStep)
+		if err != nil {
+			return err
+		}
+		if len(preCompleted) > 0 {
+			annotations[ctypes.AnnotationPreCompletedSteps] = strings.Join(preCompleted, ",")
+		}
+	}
+
+	labels := map[string]interface{}{}
+	for key, value := range original.GetLabels() {
+		labels[key] = value
+	}
+
+	runName := fmt.Sprintf("%s-%d", configName, time.Now().Unix())
+
+	pipelineRun := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": original.GetAPIVersion(),
+			"kind":       "PipelineRun",
+			"metadata": map[string]interface{}{
+				"name":        runName,
+				"namespace":   namespace,
+				"labels":      labels,
+				"annotations": annotations,
+			},
+			"spec": newSpec,
+		},
+	}
+
+	result, err := dynamicClient.Resource(pipelineRunGVR).Namespace(namespace).Create(
+		ctx,
+		pipelineRun,
+		metav1.CreateOptions{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create PipelineRun: %w", err)
+	}
+
+	fmt.Println(result.GetName())
+	return nil
+}
+
+// preCompletedSteps returns the steps of the original run that succeeded and
+// do not need to run again when retrying from fromStep. The step itself and
+// everything downstream of it are always re-run.
+func preCompletedSteps(ctx context.Context, dynamicClient dynamic.Interface, original *unstructured.Unstructured, configName, fromStep string) ([]string, error) {
+	config, err := dynamicClient.Resource(pipelineConfigGVR).Namespace(namespace).Get(
+		ctx,
+		configName,
+		metav1.GetOptions{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PipelineConfig: %w", err)
+	}
+
+	rawSteps, _, _ := unstructured.NestedSlice(config.Object, "spec", "steps")
+	steps := make([]c8sv1alpha1.PipelineStep, 0, len(rawSteps))
+	for _, raw := range rawSteps {
+		stepMap, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(stepMap, "name")
+		dependsOn, _, _ := unstructured.NestedStringSlice(stepMap, "dependsOn")
+		steps = append(steps, c8sv1alpha1.PipelineStep{Name: name, DependsOn: dependsOn})
+	}
+
+	dag, err := scheduler.BuildDAG(steps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	if _, exists := dag.GetStep(fromStep); !exists {
+		return nil, fmt.Errorf("step %s not found in PipelineConfig %s", fromStep, configName)
+	}
+
+	// Collect fromStep and all transitive dependents
+	rerun := map[string]bool{fromStep: true}
+	queue := []string{fromStep}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dag.GetDependents(current) {
+			if !rerun[dependent] {
+				rerun[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	stepStatuses, _, _ := unstructured.NestedSlice(original.Object, "status", "steps")
+	var preCompleted []string
+	for _, raw := range stepStatuses {
+		stepStatus, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(stepStatus, "name")
+		stepPhase, _, _ := unstructured.NestedString(stepStatus, "phase")
+		if stepPhase == string(c8sv1alpha1.StepPhaseSucceeded) && !rerun[name] {
+			preCompleted = append(preCompleted, name)
+		}
+	}
+
+	sort.Strings(preCompleted)
+	return preCompleted, nil
+}
+
+// pipelineConfigName reads the PipelineConfig name from a PipelineRun spec,
+// accepting both the string and {name: ...} forms of pipelineConfigRef
+func pipelineConfigName(spec map[string]interface{}) string {
+	if name, ok := spec["pipelineConfigRef"].(string); ok {
+		return name
+	}
+	name, _, _ := unstructured.NestedString(spec, "pipelineConfigRef", "name")
+	return name
+}
+
+func phaseOrPending(phase string) string {
+	if phase == "" {
+		return "Pending"
+	}
+	return phase
+}
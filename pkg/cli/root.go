@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/org/c8s/cmd/c8s/commands"
 	"github.com/org/c8s/cmd/c8s/commands/dev"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/kubernetes"
@@ -39,12 +40,13 @@ func init() {
 
 	// Add dev command
 	rootCmd.AddCommand(dev.NewDevCommand())
+	commands.AddCommands(rootCmd)
 }
 
 // Execute is the entry point for the CLI
 func Execute() error {
-	// Check if this is a cobra command (starts with "dev")
-	if len(os.Args) > 1 && os.Args[1] == "dev" {
+	// Check if this is a cobra command (starts with "dev" or "schema")
+	if len(os.Args) > 1 && (os.Args[1] == "dev" || os.Args[1] == "schema") {
 		return rootCmd.Execute()
 	}
 
@@ -54,7 +56,7 @@ func Execute() error {
 	// Get subcommand
 	args := flag.Args()
 	if len(args) == 0 {
-		return fmt.Errorf("no command specified. Available commands: run, get, validate, logs, dev")
+		return fmt.Errorf("no command specified. Available commands: run, get, validate, apply, logs, schema, dev")
 	}
 
 	command := args[0]
@@ -75,10 +77,12 @@ func Execute() error {
 		return getCommand(commandArgs)
 	case "validate":
 		return validateCommand(commandArgs)
+	case "apply":
+		return applyCommand(commandArgs)
 	case "logs":
 		return logsCommand(commandArgs)
 	default:
-		return fmt.Errorf("unknown command: %s. Available commands: run, get, validate, logs, dev", command)
+		return fmt.Errorf("unknown command: %s. Available commands: run, get, validate, apply, logs, schema, dev", command)
 	}
 }
 
@@ -108,9 +112,20 @@ func usage() {
 
 Usage:
   c8s run <pipeline-config-name> --commit=<sha> --branch=<name>
-  c8s get runs [<name>]
+  c8s run retry <pipelinerun-name> [--from-step=<step-name>]
+  c8s run abort <pipelinerun-name> [--reason=<text>]
+  c8s run export <pipelinerun-name> [--output=yaml|json] [--file=<path>]
+  c8s run import --file=<path>
+  c8s run describe <pipelinerun-name>
+  c8s run list [--group-by=config] [--per-config=<n>] [--since=<duration>]
+  c8s run watch <pipelinerun-name> [--no-tui]
+  c8s get runs [<name>] [--since=<duration>] [--field-selector=<selector>]
+               [--label-selector=<selector>] [--output=wide]
   c8s get configs [<name>]
   c8s validate <pipeline-yaml-file>
+  c8s apply --file=<path> | --file-url=<url> [--name=<name>] [--repository=<url>]
+              [--allowed-hosts=<hosts>]
+  c8s schema
   c8s logs <pipelinerun-name> --step=<step-name> [--follow]
 
 Flags:
@@ -121,15 +136,47 @@ Examples:
   # Run a pipeline manually
   c8s run my-pipeline --commit=abc123 --branch=main
 
+  # Retry a failed run, skipping steps that succeeded before "test"
+  c8s run retry my-run-12345 --from-step=test
+
+  # Abort a run immediately, deleting its Jobs without waiting for the controller
+  c8s run abort my-run-12345 --reason="runaway memory usage"
+
+  # Move a run and its PipelineConfig to another cluster, keeping its history
+  c8s run export my-run-12345 --file=run-bundle.yaml
+  c8s --kubeconfig=$HOME/.kube/other run import --file=run-bundle.yaml
+
   # List all pipeline runs
   c8s get runs
 
+  # List failed runs from the last 7 days
+  c8s get runs --since=7d --field-selector=status.phase=Failed
+
+  # List runs of the main branch with additional columns
+  c8s get runs --label-selector=c8s.dev/branch=main --output=wide
+
+  # Show the 3 most recent runs of each config with pass rates
+  c8s run list --group-by=config --per-config=3
+
   # Get details of a specific run
   c8s get runs my-run-12345
 
+  # Describe a run, including how long it was queued
+  c8s run describe my-run-12345
+
+  # Follow a run as a live tree of its steps (--no-tui for CI logs)
+  c8s run watch my-run-12345
+
   # Validate a pipeline configuration
   c8s validate .c8s.yaml
 
+  # Create or update a PipelineConfig from a pipeline file stored in S3
+  c8s apply --file-url=s3://ci-pipelines/app.c8s.yaml --allowed-hosts=ci-pipelines \
+    --repository=https://github.com/org/app.git
+
+  # Export the JSON Schema of the .c8s.yaml format
+  c8s schema > c8s.schema.json
+
   # Stream logs from a pipeline step
   c8s logs my-run-12345 --step=test --follow
 `)
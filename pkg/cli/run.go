@@ -18,7 +18,35 @@ var pipelineRunGVR = schema.GroupVersionResource{
 	Resource: "pipelineruns",
 }
 
+// describeCommand prints the details of a PipelineRun
+func describeCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("pipelinerun name required")
+	}
+	return getRuns(args[0], runListOptions{})
+}
+
 func runCommand(args []string) error {
+	// Run subcommands operate on existing PipelineRuns
+	if len(args) > 0 {
+		switch args[0] {
+		case "retry":
+			return retryCommand(args[1:])
+		case "abort":
+			return abortCommand(args[1:])
+		case "export":
+			return exportCommand(args[1:])
+		case "import":
+			return importCommand(args[1:])
+		case "describe":
+			return describeCommand(args[1:])
+		case "list":
+			return runListCommand(args[1:])
+		case "watch":
+			return watchCommand(args[1:])
+		}
+	}
+
 	fs := flag.NewFlagSet("run", flag.ExitOnError)
 	commit := fs.String("commit", "", "commit SHA to build (required)")
 	branch := fs.String("branch", "", "branch name (required)")
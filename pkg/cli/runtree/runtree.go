@@ -0,0 +1,236 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runtree renders the steps of a PipelineRun as a dependency tree,
+// for `c8s run watch`.
+package runtree
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// SpinnerFrames are the frames of the spinner shown next to running steps
+var SpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Node is a step of the tree. Steps are placed under the first step they
+// depend on, so a step depending on several others appears once.
+type Node struct {
+	Name     string
+	Status   c8sv1alpha1.StepStatus
+	Children []*Node
+}
+
+// Build returns the root steps of the tree of a run. Steps are ordered as
+// in the PipelineConfig; steps only found in the status (e.g. expanded
+// matrix steps) are added as roots after them.
+func Build(steps []c8sv1alpha1.PipelineStep, statuses []c8sv1alpha1.StepStatus) []*Node {
+	nodes := make(map[string]*Node, len(steps)+len(statuses))
+	var order []*Node
+	add := func(name string) *Node {
+		if node, ok := nodes[name]; ok {
+			return node
+		}
+		node := &Node{Name: name, Status: c8sv1alpha1.StepStatus{Name: name, Phase: c8sv1alpha1.StepPhasePending}}
+		nodes[name] = node
+		order = append(order, node)
+		return node
+	}
+
+	for _, step := range steps {
+		add(step.Name)
+	}
+	for _, status := range statuses {
+		add(status.Name).Status = status
+	}
+
+	var roots []*Node
+	parents := make(map[string]string, len(steps))
+	for _, step := range steps {
+		for _, dependency := range step.DependsOn {
+			if _, ok := nodes[dependency]; ok && dependency != step.Name {
+				parents[step.Name] = dependency
+				break
+			}
+		}
+	}
+	for _, node := range order {
+		parent, ok := parents[node.Name]
+		if !ok || createsCycle(parents, node.Name) {
+			roots = append(roots, node)
+			continue
+		}
+		nodes[parent].Children = append(nodes[parent].Children, node)
+	}
+	return roots
+}
+
+// createsCycle reports whether following the parents of a step leads back to it
+func createsCycle(parents map[string]string, name string) bool {
+	seen := map[string]bool{name: true}
+	for parent, ok := parents[name]; ok; parent, ok = parents[parent] {
+		if seen[parent] {
+			return true
+		}
+		seen[parent] = true
+	}
+	return false
+}
+
+// Icon returns the symbol of a step phase; running steps get the spinner
+// frame for tick
+func Icon(phase c8sv1alpha1.StepPhase, tick int) string {
+	switch phase {
+	case c8sv1alpha1.StepPhaseRunning:
+		return SpinnerFrames[tick%len(SpinnerFrames)]
+	case c8sv1alpha1.StepPhaseSucceeded:
+		return "✓"
+	case c8sv1alpha1.StepPhaseFailed:
+		return "✗"
+	case c8sv1alpha1.StepPhaseSkipped:
+		return "⊘"
+	default:
+		return "○"
+	}
+}
+
+// Elapsed returns how long a step has run: until its completion, or until
+// now while it runs. Steps that have not started return 0.
+func Elapsed(status c8sv1alpha1.StepStatus, now time.Time) time.Duration {
+	if status.StartTime == nil {
+		return 0
+	}
+	end := now
+	if status.CompletionTime != nil {
+		end = status.CompletionTime.Time
+	}
+	if end.Before(status.StartTime.Time) {
+		return 0
+	}
+	return end.Sub(status.StartTime.Time).Round(time.Second)
+}
+
+// Render returns the tree as text, one step per line, with dependent steps
+// indented under their parent
+func Render(roots []*Node, tick int, now time.Time) string {
+	var b strings.Builder
+	var render func(nodes []*Node, depth int)
+	render = func(nodes []*Node, depth int) {
+		for _, node := range nodes {
+			fmt.Fprintf(&b, "%s%s %s", strings.Repeat("  ", depth), Icon(node.Status.Phase, tick), node.Name)
+			if elapsed := Elapsed(node.Status, now); elapsed > 0 {
+				fmt.Fprintf(&b, "  %s", elapsed)
+			}
+			b.WriteString("\n")
+			render(node.Children, depth+1)
+		}
+	}
+	render(roots, 0)
+	return b.String()
+}
+
+// Transition is a phase change of a step, or of the run when Step is empty
+type Transition struct {
+	Step string
+	From string
+	To   string
+}
+
+func (t Transition) String() string {
+	subject := "run"
+	if t.Step != "" {
+		subject = "step " + t.Step
+	}
+	if t.From == "" {
+		return fmt.Sprintf("%s: %s", subject, t.To)
+	}
+	return fmt.Sprintf("%s: %s -> %s", subject, t.From, t.To)
+}
+
+// Transitions returns the phase changes between two statuses of a run.
+// prev may be nil for the first status seen.
+func Transitions(prev *c8sv1alpha1.PipelineRunStatus, curr *c8sv1alpha1.PipelineRunStatus) []Transition {
+	var transitions []Transition
+	if prev == nil {
+		prev = &c8sv1alpha1.PipelineRunStatus{}
+	}
+	if curr.Phase != prev.Phase && curr.Phase != "" {
+		transitions = append(transitions, Transition{From: string(prev.Phase), To: string(curr.Phase)})
+	}
+
+	previous := make(map[string]c8sv1alpha1.StepPhase, len(prev.Steps))
+	for _, step := range prev.Steps {
+		previous[step.Name] = step.Phase
+	}
+	for _, step := range curr.Steps {
+		if phase := previous[step.Name]; phase != step.Phase {
+			transitions = append(transitions, Transition{Step: step.Name, From: string(phase), To: string(step.Phase)})
+		}
+	}
+	return transitions
+}
+
+// Finished reports whether a run reached a terminal phase
+func Finished(phase c8sv1alpha1.PipelineRunPhase) bool {
+	switch phase {
+	case c8sv1alpha1.PipelineRunPhaseSucceeded, c8sv1alpha1.PipelineRunPhaseFailed, c8sv1alpha1.PipelineRunPhaseCancelled:
+		return true
+	}
+	return false
+}
+
+// Summary returns a box summarizing a finished run: its phase, duration and
+// number of steps per phase
+func Summary(runName string, status *c8sv1alpha1.PipelineRunStatus) string {
+	lines := []string{fmt.Sprintf("PipelineRun %s %s", runName, status.Phase)}
+	if status.StartTime != nil && status.CompletionTime != nil {
+		lines = append(lines, fmt.Sprintf("Duration: %s", status.CompletionTime.Sub(status.StartTime.Time).Round(time.Second)))
+	}
+
+	counts := map[c8sv1alpha1.StepPhase]int{}
+	for _, step := range status.Steps {
+		counts[step.Phase]++
+	}
+	var parts []string
+	for _, phase := range []c8sv1alpha1.StepPhase{
+		c8sv1alpha1.StepPhaseSucceeded, c8sv1alpha1.StepPhaseFailed, c8sv1alpha1.StepPhaseSkipped,
+		c8sv1alpha1.StepPhaseRunning, c8sv1alpha1.StepPhasePending,
+	} {
+		if counts[phase] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[phase], strings.ToLower(string(phase))))
+		}
+	}
+	steps := fmt.Sprintf("Steps: %d", len(status.Steps))
+	if len(parts) > 0 {
+		steps += fmt.Sprintf(" (%s)", strings.Join(parts, ", "))
+	}
+	lines = append(lines, steps)
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, len([]rune(line)))
+	}
+	var b strings.Builder
+	b.WriteString("┌" + strings.Repeat("─", width+2) + "┐\n")
+	for _, line := range lines {
+		b.WriteString("│ " + line + strings.Repeat(" ", width-len([]rune(line))) + " │\n")
+	}
+	b.WriteString("└" + strings.Repeat("─", width+2) + "┘\n")
+	return b.String()
+}
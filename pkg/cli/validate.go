@@ -28,7 +28,7 @@ func validateCommand(args []string) error {
 	}
 
 	// Parse YAML
-	spec, err := parser.Parse(yamlContent)
+	spec, err := parser.ParseBytes(yamlContent)
 	if err != nil {
 		fmt.Printf("❌ Invalid pipeline configuration\n\n")
 		fmt.Printf("Parse error: %v\n", err)
@@ -48,6 +48,9 @@ func validateCommand(args []string) error {
 	}
 
 	fmt.Printf("✅ Valid pipeline configuration\n\n")
+	for _, warning := range parser.Warnings(config) {
+		fmt.Printf("⚠️  Warning: %v\n\n", warning)
+	}
 	fmt.Printf("Repository: %s\n", spec.Repository)
 	fmt.Printf("Steps: %d\n", len(spec.Steps))
 
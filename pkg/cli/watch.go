@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/cli/runtree"
+)
+
+// spinnerInterval is the time between two frames of the running step spinner
+const spinnerInterval = 100 * time.Millisecond
+
+// watchCommand shows the steps of a PipelineRun as a live tree until the run
+// finishes. With --no-tui, one line is printed per phase transition instead.
+func watchCommand(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	noTUI := fs.Bool("no-tui", false, "print one line per phase transition instead of a live tree (for CI)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("pipeline run name required")
+	}
+
+	runName := fs.Arg(0)
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	runs := dynamicClient.Resource(pipelineRunGVR).Namespace(namespace)
+	run, err := runs.Get(ctx, runName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get PipelineRun: %w", err)
+	}
+
+	// Dependencies come from the PipelineConfig; without it every step is a root
+	var steps []c8sv1alpha1.PipelineStep
+	spec, _, _ := unstructured.NestedMap(run.Object, "spec")
+	if config, err := dynamicClient.Resource(pipelineConfigGVR).Namespace(namespace).Get(ctx, pipelineConfigName(spec), metav1.GetOptions{}); err == nil {
+		var configSpec c8sv1alpha1.PipelineConfigSpec
+		if object, ok := config.Object["spec"].(map[string]interface{}); ok &&
+			runtime.DefaultUnstructuredConverter.FromUnstructured(object, &configSpec) == nil {
+			steps = configSpec.Steps
+		}
+	}
+
+	updates := make(chan *c8sv1alpha1.PipelineRunStatus)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- watchRunStatus(ctx, runs, run, updates)
+	}()
+
+	if *noTUI {
+		var prev *c8sv1alpha1.PipelineRunStatus
+		for {
+			select {
+			case status := <-updates:
+				for _, transition := range runtree.Transitions(prev, status) {
+					fmt.Printf("%s  %s\n", time.Now().Format("15:04:05"), transition)
+				}
+				prev = status
+				if runtree.Finished(status.Phase) {
+					fmt.Print(runtree.Summary(runName, status))
+					return nil
+				}
+			case err := <-errs:
+				return err
+			}
+		}
+	}
+
+	model := &watchModel{runName: runName, steps: steps, status: &c8sv1alpha1.PipelineRunStatus{}}
+	program := tea.NewProgram(model, tea.WithContext(ctx))
+	go func() {
+		for {
+			select {
+			case status := <-updates:
+				program.Send(statusMsg{status})
+			case err := <-errs:
+				program.Send(watchErrMsg{err})
+				return
+			}
+		}
+	}()
+
+	if _, err := program.Run(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return model.err
+}
+
+// watchRunStatus sends the status of a run to updates, first as read and
+// then on every change seen through the watch API, until the run finishes.
+// The watch is restarted from the last version seen when the server closes it.
+func watchRunStatus(ctx context.Context, runs dynamic.ResourceInterface, run *unstructured.Unstructured, updates chan<- *c8sv1alpha1.PipelineRunStatus) error {
+	resourceVersion := run.GetResourceVersion()
+	send := func(object *unstructured.Unstructured) (bool, error) {
+		status := &c8sv1alpha1.PipelineRunStatus{}
+		if object, ok := object.Object["status"].(map[string]interface{}); ok {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, status); err != nil {
+				return false, fmt.Errorf("failed to read PipelineRun status: %w", err)
+			}
+		}
+		select {
+		case updates <- status:
+		case <-ctx.Done():
+			return true, nil
+		}
+		return runtree.Finished(status.Phase), nil
+	}
+
+	if done, err := send(run); done || err != nil {
+		return err
+	}
+
+	for {
+		watcher, err := runs.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", run.GetName()).String(),
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to watch PipelineRun: %w", err)
+		}
+
+		for event := range watcher.ResultChan() {
+			switch event.Type {
+			case watch.Deleted:
+				watcher.Stop()
+				return fmt.Errorf("PipelineRun %s was deleted", run.GetName())
+			case watch.Added, watch.Modified:
+				object, ok := event.Object.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				resourceVersion = object.GetResourceVersion()
+				if done, err := send(object); done || err != nil {
+					watcher.Stop()
+					return err
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// statusMsg carries a new status of the watched run
+type statusMsg struct {
+	status *c8sv1alpha1.PipelineRunStatus
+}
+
+// watchErrMsg ends the watch with an error
+type watchErrMsg struct{ err error }
+
+// tickMsg advances the spinner and the elapsed times
+type tickMsg time.Time
+
+// watchModel is the bubbletea model of `c8s run watch`
+type watchModel struct {
+	runName string
+	steps   []c8sv1alpha1.PipelineStep
+	status  *c8sv1alpha1.PipelineRunStatus
+	tick    int
+	done    bool
+	err     error
+}
+
+func tickCmd() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// Init implements tea.Model
+func (m *watchModel) Init() tea.Cmd {
+	return tickCmd()
+}
+
+// Update implements tea.Model
+func (m *watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" || msg.String() == "q" {
+			return m, tea.Quit
+		}
+	case tickMsg:
+		m.tick++
+		return m, tickCmd()
+	case statusMsg:
+		m.status = msg.status
+		if runtree.Finished(m.status.Phase) {
+			m.done = true
+			return m, tea.Quit
+		}
+	case watchErrMsg:
+		m.err = msg.err
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// View implements tea.Model
+func (m *watchModel) View() string {
+	phase := string(m.status.Phase)
+	if phase == "" {
+		phase = "Pending"
+	}
+	view := fmt.Sprintf("PipelineRun %s (%s)\n\n", m.runName, phase)
+	view += runtree.Render(runtree.Build(m.steps, m.status.Steps), m.tick, time.Now())
+	if m.done {
+		return view + "\n" + runtree.Summary(m.runName, m.status)
+	}
+	return view + "\nPress q to stop watching\n"
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/metrics"
+	ctypes "github.com/org/c8s/pkg/types"
+)
+
+// MessageStepCancelled is the message of a step failed because its run was
+// cancelled while the step was in progress
+const MessageStepCancelled = "PipelineRun cancelled"
+
+// CancellationRequeueInterval is how often a Cancelling run is checked for
+// Jobs that are still being deleted
+const CancellationRequeueInterval = 2 * time.Second
+
+// IsCancellationRequested reports whether cancellation of a run was
+// requested, by the c8s.dev/cancel-requested annotation or the Cancelling
+// phase
+func IsCancellationRequested(pipelineRun *c8sv1alpha1.PipelineRun) bool {
+	if _, ok := pipelineRun.Annotations[ctypes.AnnotationCancelRequested]; ok {
+		return true
+	}
+	return pipelineRun.Status.Phase == c8sv1alpha1.PipelineRunPhaseCancelling
+}
+
+// reconcileCancellation deletes the Jobs of a run whose cancellation was
+// requested. While Jobs remain, the run is Cancelling and requeued; once
+// they are gone, its steps in progress are failed and the run is Cancelled.
+func (r *PipelineRunReconciler) reconcileCancellation(ctx context.Context, pipelineRun *c8sv1alpha1.PipelineRun) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	jobList := &batchv1.JobList{}
+	if err := r.List(ctx, jobList,
+		client.InNamespace(pipelineRun.Namespace),
+		client.MatchingLabels{
+			ctypes.LabelPipelineRun: pipelineRun.Name,
+		},
+	); err != nil {
+		logger.Error(err, "Failed to list Jobs for cancellation")
+		return ctrl.Result{}, err
+	}
+
+	if len(jobList.Items) > 0 {
+		deleted := 0
+		for i := range jobList.Items {
+			job := &jobList.Items[i]
+			if !job.DeletionTimestamp.IsZero() {
+				continue
+			}
+			logger.Info("Deleting Job of cancelled PipelineRun", "job", job.Name)
+			if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete Job", "job", job.Name)
+				return ctrl.Result{}, err
+			}
+			deleted++
+		}
+
+		if pipelineRun.Status.Phase != c8sv1alpha1.PipelineRunPhaseCancelling {
+			pipelineRun.Status.Phase = c8sv1alpha1.PipelineRunPhaseCancelling
+			NewStatusUpdater(r.Client).setCondition(pipelineRun, metav1.Condition{
+				Type:    ctypes.ConditionTypeStepsCompleted,
+				Status:  metav1.ConditionFalse,
+				Reason:  ctypes.ReasonCancelling,
+				Message: fmt.Sprintf("Deleting %d Jobs", len(jobList.Items)),
+			})
+			if err := r.Status().Update(ctx, pipelineRun); err != nil {
+				logger.Error(err, "Failed to mark PipelineRun as Cancelling")
+				return ctrl.Result{}, err
+			}
+			r.recordEvent(pipelineRun, corev1.EventTypeNormal, ctypes.ReasonCancelling,
+				fmt.Sprintf("Cancellation requested, deleting %d Jobs", deleted))
+		}
+
+		logger.Info("Waiting for Jobs of cancelled PipelineRun to be deleted", "jobs", len(jobList.Items))
+		return ctrl.Result{RequeueAfter: CancellationRequeueInterval}, nil
+	}
+
+	now := metav1.Now()
+	for i := range pipelineRun.Status.Steps {
+		status := &pipelineRun.Status.Steps[i]
+		if !isStepInProgress(status.Phase) {
+			continue
+		}
+		status.Phase = c8sv1alpha1.StepPhaseFailed
+		status.Message = MessageStepCancelled
+		status.CompletionTime = &now
+		StepStatusHistory{}.Record(status, status.Phase, status.Message, now)
+	}
+
+	pipelineRun.Status.Phase = c8sv1alpha1.PipelineRunPhaseCancelled
+	if pipelineRun.Status.CompletionTime == nil {
+		pipelineRun.Status.CompletionTime = &now
+		metrics.RecordPipelineRunCreated(pipelineRun.Namespace, string(pipelineRun.Status.Phase))
+		if pipelineRun.Status.StartTime != nil {
+			metrics.RecordPipelineRunCompleted(pipelineRun.Namespace, pipelineRun.Spec.PipelineConfigRef,
+				now.Sub(pipelineRun.Status.StartTime.Time).Seconds())
+		}
+	}
+	NewStatusUpdater(r.Client).setCondition(pipelineRun, metav1.Condition{
+		Type:    ctypes.ConditionTypeStepsCompleted,
+		Status:  metav1.ConditionFalse,
+		Reason:  ctypes.ReasonCancelled,
+		Message: "PipelineRun cancelled",
+	})
+	if err := r.Status().Update(ctx, pipelineRun); err != nil {
+		logger.Error(err, "Failed to mark PipelineRun as Cancelled")
+		return ctrl.Result{}, err
+	}
+
+	r.recordEvent(pipelineRun, corev1.EventTypeNormal, ctypes.ReasonCancelled, "PipelineRun cancelled")
+	logger.Info("PipelineRun cancelled")
+	return ctrl.Result{}, nil
+}
+
+// recordEvent emits an event on a PipelineRun if the reconciler has a
+// Recorder
+func (r *PipelineRunReconciler) recordEvent(pipelineRun *c8sv1alpha1.PipelineRun, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(pipelineRun, eventType, reason, message)
+	}
+}
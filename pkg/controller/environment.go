@@ -0,0 +1,65 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// ResolveEnvironment returns the environment a run executes in. The run's
+// environment overrides the PipelineConfig default; runs with neither execute
+// in the development environment.
+func ResolveEnvironment(pipelineRun *c8sv1alpha1.PipelineRun, pipelineConfig *c8sv1alpha1.PipelineConfig) string {
+	if pipelineRun != nil && pipelineRun.Spec.Environment != "" {
+		return pipelineRun.Spec.Environment
+	}
+	if pipelineConfig != nil && pipelineConfig.Spec.Environment != "" {
+		return pipelineConfig.Spec.Environment
+	}
+	return c8sv1alpha1.EnvironmentDevelopment
+}
+
+// StepRunsInEnvironment reports whether a step runs in an environment. Steps
+// without conditional.environment run in every environment.
+func StepRunsInEnvironment(step *c8sv1alpha1.PipelineStep, environment string) bool {
+	if step.Conditional == nil || len(step.Conditional.Environment) == 0 {
+		return true
+	}
+	for _, allowed := range step.Conditional.Environment {
+		if allowed == environment {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkStepSkipped records a step as skipped in the run status, unless the
+// step already has a status
+func MarkStepSkipped(pipelineRun *c8sv1alpha1.PipelineRun, stepName, environment string) {
+	if GetStepStatus(pipelineRun, stepName) != nil {
+		return
+	}
+	status := c8sv1alpha1.StepStatus{
+		Name:    stepName,
+		Phase:   c8sv1alpha1.StepPhaseSkipped,
+		Message: "not run in environment " + environment,
+	}
+	StepStatusHistory{}.Record(&status, status.Phase, status.Message, metav1.Now())
+	pipelineRun.Status.Steps = append(pipelineRun.Status.Steps, status)
+}
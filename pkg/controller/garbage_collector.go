@@ -0,0 +1,90 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// MessageJobDeletedExternally is the message of a step failed because its
+// Job was deleted while the step was in progress
+const MessageJobDeletedExternally = "Job deleted externally"
+
+// PipelineRunGarbageCollector detects steps orphaned by a Job deleted outside
+// the controller (e.g. 'kubectl delete job') while their run was in progress
+type PipelineRunGarbageCollector struct{}
+
+// NewPipelineRunGarbageCollector creates a new PipelineRunGarbageCollector
+func NewPipelineRunGarbageCollector() *PipelineRunGarbageCollector {
+	return &PipelineRunGarbageCollector{}
+}
+
+// HasRecordedJob reports whether a step is Pending or Running with a Job
+// recorded in the run status. Such a step must not get a new Job: if its
+// Job is missing, it was deleted externally.
+func (gc *PipelineRunGarbageCollector) HasRecordedJob(pipelineRun *c8sv1alpha1.PipelineRun, stepName string) bool {
+	status := GetStepStatus(pipelineRun, stepName)
+	return status != nil && status.JobName != "" && isStepInProgress(status.Phase)
+}
+
+// OrphanedSteps returns the steps of a run that are Pending or Running with a
+// recorded Job that is not in jobsByStep. Alias steps share the Job of the
+// step they mirror and are never orphaned.
+func (gc *PipelineRunGarbageCollector) OrphanedSteps(pipelineRun *c8sv1alpha1.PipelineRun, jobsByStep map[string]*batchv1.Job) []string {
+	var orphaned []string
+	for _, step := range pipelineRun.Status.Steps {
+		if step.JobName == "" || step.AliasOf != "" || !isStepInProgress(step.Phase) {
+			continue
+		}
+		if _, ok := jobsByStep[step.Name]; !ok {
+			orphaned = append(orphaned, step.Name)
+		}
+	}
+	return orphaned
+}
+
+// Collect fails the orphaned steps of a run with MessageJobDeletedExternally
+// and, if there are any, the run itself. Returns the failed steps.
+func (gc *PipelineRunGarbageCollector) Collect(pipelineRun *c8sv1alpha1.PipelineRun, jobsByStep map[string]*batchv1.Job) []string {
+	orphaned := gc.OrphanedSteps(pipelineRun, jobsByStep)
+	if len(orphaned) == 0 {
+		return nil
+	}
+
+	now := metav1.Now()
+	for _, name := range orphaned {
+		status := GetStepStatus(pipelineRun, name)
+		status.Phase = c8sv1alpha1.StepPhaseFailed
+		status.Message = MessageJobDeletedExternally
+		status.CompletionTime = &now
+		StepStatusHistory{}.Record(status, status.Phase, status.Message, now)
+		recordStepMetrics(pipelineRun.Namespace, status)
+	}
+
+	// A terminal phase is kept by the status update, which then records
+	// the completion time of the run
+	pipelineRun.Status.Phase = c8sv1alpha1.PipelineRunPhaseFailed
+	return orphaned
+}
+
+// isStepInProgress reports whether a step phase is Pending or Running
+func isStepInProgress(phase c8sv1alpha1.StepPhase) bool {
+	return phase == c8sv1alpha1.StepPhasePending || phase == c8sv1alpha1.StepPhaseRunning
+}
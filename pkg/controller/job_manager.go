@@ -17,7 +17,10 @@ limitations under the License.
 package controller
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
@@ -49,14 +52,23 @@ func (jm *JobManager) CreateJobForStep(
 	pipelineRun *c8sv1alpha1.PipelineRun,
 	pipelineConfig *c8sv1alpha1.PipelineConfig,
 ) (*batchv1.Job, error) {
-	jobName := fmt.Sprintf("%s-%s", pipelineRun.Name, step.Name)
+	jobName := GetJobForStep(pipelineRun.Name, step.Name)
 
-	// Parse timeout
-	timeout, err := parseTimeout(step.Timeout)
+	// Steps without a timeout inherit the PipelineConfig default
+	timeoutStr := step.Timeout
+	if timeoutStr == "" && pipelineConfig != nil {
+		timeoutStr = pipelineConfig.Spec.DefaultStepTimeout
+	}
+	timeout, err := parseTimeout(timeoutStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid timeout for step %s: %w", step.Name, err)
 	}
 
+	stepVolumes, stepMounts, err := buildStepVolumes(step, pipelineConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build job spec
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -65,10 +77,11 @@ func (jm *JobManager) CreateJobForStep(
 			Labels: map[string]string{
 				types.LabelPipelineConfig: pipelineRun.Spec.PipelineConfigRef,
 				types.LabelPipelineRun:    pipelineRun.Name,
-				types.LabelStepName:       step.Name,
+				types.LabelStepName:       StepLabelValue(step.Name),
 				types.LabelCommit:         pipelineRun.Spec.Commit,
 				types.LabelBranch:         pipelineRun.Spec.Branch,
 				types.LabelManagedBy:      types.ManagedByC8S,
+				types.LabelManaged:        types.LabelManagedValue,
 			},
 			Annotations: map[string]string{
 				types.AnnotationCommitMessage: pipelineRun.Spec.CommitMessage,
@@ -86,7 +99,8 @@ func (jm *JobManager) CreateJobForStep(
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
 						types.LabelPipelineRun: pipelineRun.Name,
-						types.LabelStepName:    step.Name,
+						types.LabelStepName:    StepLabelValue(step.Name),
+						types.LabelManaged:     types.LabelManagedValue,
 					},
 				},
 				Spec: corev1.PodSpec{
@@ -95,7 +109,7 @@ func (jm *JobManager) CreateJobForStep(
 						jm.buildGitCloneContainer(pipelineRun),
 					},
 					Containers: []corev1.Container{
-						jm.buildStepContainer(step, pipelineRun),
+						jm.buildStepContainer(step, pipelineRun, pipelineConfig, jobName),
 					},
 					Volumes: []corev1.Volume{
 						{
@@ -110,6 +124,26 @@ func (jm *JobManager) CreateJobForStep(
 		},
 	}
 
+	// Add the volumes mounted by this step
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, stepVolumes...)
+	job.Spec.Template.Spec.Containers[0].VolumeMounts = append(job.Spec.Template.Spec.Containers[0].VolumeMounts, stepMounts...)
+
+	// Let the Job controller retry failed attempts under the step's retry policy
+	if policy := ResolveRetryPolicy(step, pipelineConfig); policy != nil && policy.MaxRetries > 0 {
+		job.Spec.BackoffLimit = int32Ptr(int32(policy.MaxRetries))
+		job.Spec.PodFailurePolicy = buildPodFailurePolicy(policy)
+	}
+
+	// Run priority overrides the PipelineConfig default
+	if priorityClassName := ResolvePriorityClassName(pipelineRun, pipelineConfig); priorityClassName != "" {
+		job.Spec.Template.Spec.PriorityClassName = priorityClassName
+	}
+
+	// Jobs of tool-created runs can be cleaned up with the run's label
+	if createdBy := pipelineRun.Labels[types.LabelCreatedBy]; createdBy != "" {
+		job.Labels[types.LabelCreatedBy] = createdBy
+	}
+
 	return job, nil
 }
 
@@ -170,10 +204,25 @@ echo "Repository cloned successfully"`
 	}
 }
 
+// StepWorkingDir returns the directory the commands of a step run in:
+// the workspace, or the step's workingDir resolved against the workspace
+// when relative
+func StepWorkingDir(step *c8sv1alpha1.PipelineStep) string {
+	if step.WorkingDir == "" {
+		return types.MountPathWorkspace
+	}
+	if path.IsAbs(step.WorkingDir) {
+		return path.Clean(step.WorkingDir)
+	}
+	return path.Join(types.MountPathWorkspace, step.WorkingDir)
+}
+
 // buildStepContainer creates the main container for the step
 func (jm *JobManager) buildStepContainer(
 	step *c8sv1alpha1.PipelineStep,
 	pipelineRun *c8sv1alpha1.PipelineRun,
+	pipelineConfig *c8sv1alpha1.PipelineConfig,
+	jobName string,
 ) corev1.Container {
 	// Build command script
 	commandScript := strings.Join(step.Commands, "\n")
@@ -181,7 +230,7 @@ func (jm *JobManager) buildStepContainer(
 	container := corev1.Container{
 		Name:       types.ContainerNameStep,
 		Image:      step.Image,
-		WorkingDir: types.MountPathWorkspace,
+		WorkingDir: StepWorkingDir(step),
 		Command: []string{
 			"/bin/sh",
 			"-c",
@@ -218,6 +267,10 @@ func (jm *JobManager) buildStepContainer(
 				Name:  types.EnvC8SNamespace,
 				Value: pipelineRun.Namespace,
 			},
+			{
+				Name:  types.EnvEnvironment,
+				Value: ResolveEnvironment(pipelineRun, pipelineConfig),
+			},
 		},
 	}
 
@@ -243,8 +296,16 @@ func (jm *JobManager) buildStepContainer(
 		}
 	}
 
+	// Steps without a security context inherit the PipelineConfig default
+	if spec := ResolveSecurityContext(step, pipelineConfig); spec != nil {
+		container.SecurityContext = buildSecurityContext(*spec)
+	}
+
 	// Add secret injection (User Story 3)
-	for _, secret := range step.Secrets {
+	// Vault values are injected from the Job's temporary Secret the same way
+	secretRefs := append([]c8sv1alpha1.SecretReference{}, step.Secrets...)
+	secretRefs = append(secretRefs, VaultSecretReferences(jobName, step)...)
+	for _, secret := range secretRefs {
 		// If EnvVar is not specified, use the key name as the environment variable name
 		envVarName := secret.EnvVar
 		if envVarName == "" {
@@ -269,10 +330,106 @@ func (jm *JobManager) buildStepContainer(
 	return container
 }
 
+// ResolveSecurityContext returns the security context of a step: its own
+// securityContext, or else the PipelineConfig's defaultSecurityContext
+func ResolveSecurityContext(step *c8sv1alpha1.PipelineStep, pipelineConfig *c8sv1alpha1.PipelineConfig) *c8sv1alpha1.SecurityContextSpec {
+	if step != nil && step.SecurityContext != nil {
+		return step.SecurityContext
+	}
+	if pipelineConfig != nil {
+		return pipelineConfig.Spec.DefaultSecurityContext
+	}
+	return nil
+}
+
+// buildSecurityContext converts a step security context to a container
+// security context. A zero user or group keeps the image default.
+func buildSecurityContext(spec c8sv1alpha1.SecurityContextSpec) *corev1.SecurityContext {
+	securityContext := &corev1.SecurityContext{
+		RunAsNonRoot:             &spec.RunAsNonRoot,
+		ReadOnlyRootFilesystem:   &spec.ReadOnlyRootFilesystem,
+		AllowPrivilegeEscalation: &spec.AllowPrivilegeEscalation,
+	}
+	if spec.RunAsUser != 0 {
+		securityContext.RunAsUser = &spec.RunAsUser
+	}
+	if spec.RunAsGroup != 0 {
+		securityContext.RunAsGroup = &spec.RunAsGroup
+	}
+	return securityContext
+}
+
+// buildStepVolumes returns the Pod volumes and container mounts for the volumes
+// a step references. Only referenced volumes are added so that a step does not
+// attach claims it does not use.
+func buildStepVolumes(
+	step *c8sv1alpha1.PipelineStep,
+	pipelineConfig *c8sv1alpha1.PipelineConfig,
+) ([]corev1.Volume, []corev1.VolumeMount, error) {
+	if len(step.VolumeMounts) == 0 {
+		return nil, nil, nil
+	}
+
+	declared := make(map[string]*c8sv1alpha1.VolumeSpec)
+	if pipelineConfig != nil {
+		for i := range pipelineConfig.Spec.Volumes {
+			declared[pipelineConfig.Spec.Volumes[i].Name] = &pipelineConfig.Spec.Volumes[i]
+		}
+	}
+
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	added := make(map[string]bool)
+	for _, mount := range step.VolumeMounts {
+		spec, ok := declared[mount.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("step %s mounts undeclared volume %s", step.Name, mount.Name)
+		}
+
+		if !added[mount.Name] {
+			volume := corev1.Volume{Name: mount.Name}
+			switch {
+			case spec.PersistentVolumeClaim != nil:
+				volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: spec.PersistentVolumeClaim.ClaimName,
+					ReadOnly:  spec.PersistentVolumeClaim.AccessMode == string(corev1.ReadOnlyMany),
+				}
+			case spec.ConfigMap != nil:
+				volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: spec.ConfigMap.Name},
+				}
+			case spec.EmptyDir != nil:
+				volume.EmptyDir = &corev1.EmptyDirVolumeSource{
+					Medium: corev1.StorageMedium(spec.EmptyDir.Medium),
+				}
+				if spec.EmptyDir.SizeLimit != "" {
+					sizeLimit, err := resource.ParseQuantity(spec.EmptyDir.SizeLimit)
+					if err != nil {
+						return nil, nil, fmt.Errorf("invalid sizeLimit for volume %s: %w", spec.Name, err)
+					}
+					volume.EmptyDir.SizeLimit = &sizeLimit
+				}
+			default:
+				return nil, nil, fmt.Errorf("volume %s has no source", spec.Name)
+			}
+			volumes = append(volumes, volume)
+			added[mount.Name] = true
+		}
+
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      mount.Name,
+			MountPath: mount.MountPath,
+			ReadOnly:  mount.ReadOnly,
+		})
+	}
+
+	return volumes, mounts, nil
+}
+
 // parseTimeout converts timeout string (e.g., "30m", "2h") to seconds
 func parseTimeout(timeoutStr string) (int64, error) {
 	if timeoutStr == "" {
-		timeoutStr = "30m"
+		timeoutStr = types.DefaultStepTimeout
 	}
 
 	duration, err := time.ParseDuration(timeoutStr)
@@ -288,9 +445,47 @@ func int32Ptr(i int32) *int32 {
 	return &i
 }
 
-// GetJobForStep constructs the expected Job name for a pipeline step
+// GetJobForStep constructs the expected Job name for a pipeline step. The "/"
+// in the names of included steps is replaced with "-".
 func GetJobForStep(pipelineRunName, stepName string) string {
-	return fmt.Sprintf("%s-%s", pipelineRunName, stepName)
+	return TruncateJobName(pipelineRunName, strings.ReplaceAll(stepName, "/", "-"))
+}
+
+// StepLabelValue returns the step name label value of a step. Included steps
+// are named "{config-name}/{step-name}", and "/" is not allowed in label
+// values, so it is replaced with ".".
+func StepLabelValue(stepName string) string {
+	return strings.ReplaceAll(stepName, "/", ".")
+}
+
+// jobNameHashLength is the number of hash characters added to truncated Job names
+const jobNameHashLength = 8
+
+// TruncateJobName joins runName and stepName with a hyphen, keeping the result
+// within types.JobNameMaxLength. Long run names are truncated and followed by a
+// hash of the full run name so that runs sharing a prefix get distinct Jobs.
+// Step names too long to fit are truncated themselves and followed by a hash
+// of both names.
+func TruncateJobName(runName, stepName string) string {
+	name := fmt.Sprintf("%s-%s", runName, stepName)
+	if len(name) <= types.JobNameMaxLength {
+		return name
+	}
+
+	keep := types.JobNameMaxLength - len(stepName) - jobNameHashLength - 1
+	if keep < 0 {
+		// Keep as much of the step name as fits before the hash
+		stepKeep := types.JobNameMaxLength - jobNameHashLength - 1
+		return fmt.Sprintf("%s-%s", stepName[:stepKeep], shortHash(name))
+	}
+
+	return fmt.Sprintf("%s%s-%s", runName[:keep], shortHash(runName), stepName)
+}
+
+// shortHash returns the first jobNameHashLength hex characters of the SHA-256 of s
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:jobNameHashLength]
 }
 
 // IsJobOwnedByPipelineRun checks if a Job is owned by a PipelineRun
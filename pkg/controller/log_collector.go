@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"bufio"
 	"bytes"
+	"compress/flate"
 	"context"
 	"fmt"
 	"io"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,8 +15,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/log/broker"
+	"github.com/org/c8s/pkg/metrics"
 	"github.com/org/c8s/pkg/secrets"
 	"github.com/org/c8s/pkg/storage"
+	"github.com/org/c8s/pkg/types"
 )
 
 const (
@@ -26,6 +32,23 @@ type LogCollector struct {
 	client        kubernetes.Interface
 	storageClient storage.StorageClient
 	bufferManager *LogBufferManager
+
+	// broker distributes live logs to other processes; nil keeps them in
+	// the in-memory buffers only
+	broker broker.Broker
+
+	// secretCache caches the Secrets read for masking; nil fetches them on
+	// every collection
+	secretCache *secrets.SecretCache
+
+	mu sync.Mutex
+
+	// ctx bounds the log streams followed for running steps; set by Start
+	ctx context.Context
+
+	// published holds the buffer keys of the steps whose logs were published,
+	// line by line while they ran or at once when collected
+	published map[string]bool
 }
 
 // NewLogCollector creates a new LogCollector
@@ -34,11 +57,47 @@ func NewLogCollector(client kubernetes.Interface, storageClient storage.StorageC
 		client:        client,
 		storageClient: storageClient,
 		bufferManager: NewLogBufferManager(),
+		published:     make(map[string]bool),
 	}
 }
 
-// CollectLogs streams logs from a Pod and returns them as bytes
-func (lc *LogCollector) CollectLogs(ctx context.Context, pod *corev1.Pod) ([]byte, error) {
+// Start makes ctx the context log streams of running steps are followed in,
+// and blocks until it is done. It satisfies manager.Runnable so the
+// collector's streams stop with the controller manager.
+func (lc *LogCollector) Start(ctx context.Context) error {
+	lc.mu.Lock()
+	lc.ctx = ctx
+	lc.mu.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+// SetBroker publishes collected logs through b in addition to the in-memory
+// buffers, e.g. to reach API servers running in other Pods
+func (lc *LogCollector) SetBroker(b broker.Broker) {
+	lc.broker = b
+}
+
+// SetSecretCache caches the Secrets read for log masking in c. The cache must
+// be kept current by a secrets.SecretRotationWatcher, otherwise rotated
+// Secret values are never masked.
+func (lc *LogCollector) SetSecretCache(c *secrets.SecretCache) {
+	lc.secretCache = c
+}
+
+// Broker returns the broker live logs are published to, falling back to the
+// in-memory buffers when no broker is configured
+func (lc *LogCollector) Broker() broker.Broker {
+	if lc.broker != nil {
+		return lc.broker
+	}
+	return lc.bufferManager
+}
+
+// CollectLogs streams logs from a Pod and returns them as bytes, keeping at
+// most maxSize bytes (MaxLogBufferSize when maxSize is not positive)
+func (lc *LogCollector) CollectLogs(ctx context.Context, pod *corev1.Pod, maxSize int64) ([]byte, error) {
 	logger := log.FromContext(ctx)
 
 	if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed && pod.Status.Phase != corev1.PodRunning {
@@ -72,7 +131,10 @@ func (lc *LogCollector) CollectLogs(ctx context.Context, pod *corev1.Pod) ([]byt
 
 	// Read logs into buffer with size limit
 	buf := &bytes.Buffer{}
-	limitedReader := io.LimitReader(stream, MaxLogBufferSize)
+	if maxSize <= 0 {
+		maxSize = MaxLogBufferSize
+	}
+	limitedReader := io.LimitReader(stream, maxSize)
 
 	_, err = io.Copy(buf, limitedReader)
 	if err != nil {
@@ -97,7 +159,7 @@ func (lc *LogCollector) UploadLogsToStorage(ctx context.Context, pipelineRun *v1
 	}
 
 	// Fetch secret values for masking
-	secretValues, err := lc.fetchSecretValues(ctx, pipelineRun.Namespace, pipelineConfig, stepName)
+	secretValues, err := lc.fetchSecretValues(ctx, pipelineRun, pipelineConfig, stepName)
 	if err != nil {
 		logger.Error(err, "failed to fetch secret values for masking", "step", stepName)
 		// Continue with upload but log the error
@@ -115,15 +177,20 @@ func (lc *LogCollector) UploadLogsToStorage(ctx context.Context, pipelineRun *v1
 	// Generate storage key: {namespace}/{pipelinerun-name}/{step-name}.log
 	key := fmt.Sprintf("%s/%s/%s.log", pipelineRun.Namespace, pipelineRun.Name, stepName)
 
-	// Convert masked logs to io.Reader
-	reader := bytes.NewReader(maskedLogs)
+	// Compress masked logs to reduce storage costs and upload time
+	compressedLogs, err := storage.CompressLog(maskedLogs)
+	if err != nil {
+		return "", err
+	}
+	reader := bytes.NewReader(compressedLogs)
 
 	// Upload masked logs to storage
-	err = lc.storageClient.UploadLog(ctx, key, reader)
+	err = lc.storageClient.UploadLog(ctx, key, reader, storage.ContentEncodingGzip)
 	if err != nil {
 		logger.Error(err, "failed to upload logs to storage", "key", key)
 		return "", fmt.Errorf("failed to upload logs: %w", err)
 	}
+	metrics.RecordLogUpload(pipelineRun.Namespace, len(compressedLogs))
 
 	// Generate a signed URL for accessing the logs (valid for 7 days)
 	logURL, err := lc.storageClient.GenerateSignedURL(ctx, key, 7*24*3600)
@@ -132,7 +199,8 @@ func (lc *LogCollector) UploadLogsToStorage(ctx context.Context, pipelineRun *v1
 		return "", fmt.Errorf("failed to generate signed URL: %w", err)
 	}
 
-	logger.Info("uploaded logs to storage", "key", key, "url", logURL)
+	logger.Info("uploaded logs to storage", "key", key, "url", logURL,
+		"size", len(maskedLogs), "compressedSize", len(compressedLogs))
 	return logURL, nil
 }
 
@@ -140,14 +208,18 @@ func (lc *LogCollector) UploadLogsToStorage(ctx context.Context, pipelineRun *v1
 func (lc *LogCollector) CollectAndUpload(ctx context.Context, pod *corev1.Pod, pipelineRun *v1alpha1.PipelineRun, stepName string, pipelineConfig *v1alpha1.PipelineConfig) (string, error) {
 	logger := log.FromContext(ctx)
 
-	// Collect logs
-	logs, err := lc.CollectLogs(ctx, pod)
+	// Collect logs, up to the size allowed for the step
+	var maxSize int64
+	if pipelineConfig != nil {
+		maxSize = pipelineConfig.Spec.MaxLogSizeBytes(stepName)
+	}
+	logs, err := lc.CollectLogs(ctx, pod, maxSize)
 	if err != nil {
 		return "", err
 	}
 
 	// Fetch secret values for masking
-	secretValues, err := lc.fetchSecretValues(ctx, pipelineRun.Namespace, pipelineConfig, stepName)
+	secretValues, err := lc.fetchSecretValues(ctx, pipelineRun, pipelineConfig, stepName)
 	if err != nil {
 		logger.Error(err, "failed to fetch secret values for masking", "step", stepName)
 		// Continue with masked logs using empty secret map
@@ -157,9 +229,13 @@ func (lc *LogCollector) CollectAndUpload(ctx context.Context, pod *corev1.Pod, p
 	// Mask secrets in logs before storing in buffer
 	maskedLogs := secrets.MaskSecrets(logs, secretValues)
 
-	// Store masked logs in circular buffer for real-time streaming
-	bufferKey := fmt.Sprintf("%s/%s/%s", pipelineRun.Namespace, pipelineRun.Name, stepName)
-	lc.bufferManager.Write(bufferKey, maskedLogs)
+	// Store masked logs in circular buffer for real-time streaming, unless
+	// they were already published while the step ran or by an earlier
+	// collection
+	bufferKey := LogBufferKey(pipelineRun.Namespace, pipelineRun.Name, stepName)
+	if lc.markPublished(bufferKey) {
+		lc.publish(bufferKey, maskedLogs)
+	}
 
 	// Upload to storage (masking happens again inside for safety)
 	logURL, err := lc.UploadLogsToStorage(ctx, pipelineRun, stepName, maskedLogs, pipelineConfig)
@@ -173,9 +249,10 @@ func (lc *LogCollector) CollectAndUpload(ctx context.Context, pod *corev1.Pod, p
 }
 
 // fetchSecretValues fetches all secret values referenced by a pipeline step for masking purposes
-func (lc *LogCollector) fetchSecretValues(ctx context.Context, namespace string, pipelineConfig *v1alpha1.PipelineConfig, stepName string) (map[string]string, error) {
+func (lc *LogCollector) fetchSecretValues(ctx context.Context, pipelineRun *v1alpha1.PipelineRun, pipelineConfig *v1alpha1.PipelineConfig, stepName string) (map[string]string, error) {
 	logger := log.FromContext(ctx)
 	secretValues := make(map[string]string)
+	namespace := pipelineRun.Namespace
 
 	if pipelineConfig == nil {
 		return secretValues, nil
@@ -194,16 +271,18 @@ func (lc *LogCollector) fetchSecretValues(ctx context.Context, namespace string,
 		return secretValues, fmt.Errorf("step %s not found in pipeline config", stepName)
 	}
 
-	// Fetch all referenced secrets
-	for _, secretRef := range targetStep.Secrets {
-		secret, err := lc.client.CoreV1().Secrets(namespace).Get(ctx, secretRef.SecretRef, metav1.GetOptions{})
+	// Fetch all referenced secrets, including the temporary Vault Secret
+	secretRefs := append([]v1alpha1.SecretReference{}, targetStep.Secrets...)
+	secretRefs = append(secretRefs, VaultSecretReferences(GetJobForStep(pipelineRun.Name, stepName), targetStep)...)
+	for _, secretRef := range secretRefs {
+		data, err := lc.secretData(ctx, namespace, secretRef.SecretRef)
 		if err != nil {
 			logger.Error(err, "failed to fetch secret for masking", "secret", secretRef.SecretRef)
 			continue
 		}
 
 		// Extract the specific key value
-		if value, ok := secret.Data[secretRef.Key]; ok {
+		if value, ok := data[secretRef.Key]; ok {
 			// Use the secret name and key as the identifier
 			identifier := fmt.Sprintf("%s:%s", secretRef.SecretRef, secretRef.Key)
 			secretValues[identifier] = string(value)
@@ -213,16 +292,125 @@ func (lc *LogCollector) fetchSecretValues(ctx context.Context, namespace string,
 	return secretValues, nil
 }
 
+// FollowLogs streams the logs of the step container of a running Pod in the
+// background, publishing them line by line with the step's secrets masked,
+// until the container exits. A step whose logs are already published is
+// ignored.
+func (lc *LogCollector) FollowLogs(ctx context.Context, pod *corev1.Pod, pipelineRun *v1alpha1.PipelineRun, stepName string, pipelineConfig *v1alpha1.PipelineConfig) {
+	logger := log.FromContext(ctx)
+	bufferKey := LogBufferKey(pipelineRun.Namespace, pipelineRun.Name, stepName)
+
+	if !lc.markPublished(bufferKey) {
+		return
+	}
+	lc.mu.Lock()
+	followCtx := lc.ctx
+	lc.mu.Unlock()
+	if followCtx == nil {
+		followCtx = context.Background()
+	}
+
+	secretValues, err := lc.fetchSecretValues(ctx, pipelineRun, pipelineConfig, stepName)
+	if err != nil {
+		logger.Error(err, "failed to fetch secret values for masking", "step", stepName)
+		secretValues = make(map[string]string)
+	}
+	maxSize := int64(MaxLogBufferSize)
+	if pipelineConfig != nil {
+		maxSize = pipelineConfig.Spec.MaxLogSizeBytes(stepName)
+	}
+
+	stream, err := lc.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: types.ContainerNameStep,
+		Follow:    true,
+	}).Stream(followCtx)
+	if err != nil {
+		// Retried on the next reconcile
+		logger.Error(err, "failed to follow logs", "pod", pod.Name, "step", stepName)
+		lc.mu.Lock()
+		delete(lc.published, bufferKey)
+		lc.mu.Unlock()
+		return
+	}
+
+	go func() {
+		defer func() { _ = stream.Close() }()
+		masked := secrets.MaskReader(stream, secretValues)
+		defer func() { _ = masked.Close() }()
+
+		reader := bufio.NewReader(io.LimitReader(masked, maxSize))
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				lc.publish(bufferKey, line)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// markPublished marks the logs of bufferKey as published, and reports
+// whether they weren't already
+func (lc *LogCollector) markPublished(bufferKey string) bool {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if lc.published[bufferKey] {
+		return false
+	}
+	lc.published[bufferKey] = true
+	return true
+}
+
+// publish writes logs to the in-memory buffer of bufferKey and, if set, the
+// broker
+func (lc *LogCollector) publish(bufferKey string, data []byte) {
+	lc.bufferManager.Write(bufferKey, data)
+	if lc.broker != nil {
+		lc.broker.Publish(bufferKey, data)
+	}
+}
+
+// LogBufferKey returns the key the live logs of a step are published under
+func LogBufferKey(namespace, pipelineRunName, stepName string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, pipelineRunName, stepName)
+}
+
+// secretData returns the data of a Secret, from the secret cache when set
+func (lc *LogCollector) secretData(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	if lc.secretCache != nil {
+		if data, ok := lc.secretCache.Get(namespace, name); ok {
+			return data, nil
+		}
+	}
+
+	secret, err := lc.client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if lc.secretCache != nil {
+		lc.secretCache.Set(namespace, name, secret.ResourceVersion, secret.Data)
+	}
+	return secret.Data, nil
+}
+
 // GetLogBuffer returns the log buffer manager for real-time streaming
 func (lc *LogCollector) GetLogBuffer() *LogBufferManager {
 	return lc.bufferManager
 }
 
-// LogBufferManager manages circular buffers for real-time log streaming
+// LogBufferManager manages circular buffers for real-time log streaming. It
+// is the in-memory Broker used when no external broker is configured.
 type LogBufferManager struct {
+	mu      sync.Mutex
 	buffers map[string]*CircularBuffer
 }
 
+var _ broker.Broker = (*LogBufferManager)(nil)
+
 // NewLogBufferManager creates a new LogBufferManager
 func NewLogBufferManager() *LogBufferManager {
 	return &LogBufferManager{
@@ -230,17 +418,34 @@ func NewLogBufferManager() *LogBufferManager {
 	}
 }
 
-// Write writes logs to a circular buffer
-func (lbm *LogBufferManager) Write(key string, data []byte) {
+// buffer returns the circular buffer of key, creating it if needed
+func (lbm *LogBufferManager) buffer(key string) *CircularBuffer {
+	lbm.mu.Lock()
+	defer lbm.mu.Unlock()
+
 	if _, exists := lbm.buffers[key]; !exists {
 		lbm.buffers[key] = NewCircularBuffer(MaxLogBufferSize)
 	}
-	lbm.buffers[key].Write(data)
+	return lbm.buffers[key]
+}
+
+// Write writes logs to a circular buffer
+func (lbm *LogBufferManager) Write(key string, data []byte) {
+	lbm.buffer(key).Write(data)
+}
+
+// Publish writes logs to a circular buffer, notifying its subscribers
+func (lbm *LogBufferManager) Publish(key string, data []byte) {
+	lbm.Write(key, data)
 }
 
 // Read reads logs from a circular buffer
 func (lbm *LogBufferManager) Read(key string) []byte {
-	if buf, exists := lbm.buffers[key]; exists {
+	lbm.mu.Lock()
+	buf, exists := lbm.buffers[key]
+	lbm.mu.Unlock()
+
+	if exists {
 		return buf.Read()
 	}
 	return nil
@@ -248,23 +453,45 @@ func (lbm *LogBufferManager) Read(key string) []byte {
 
 // Subscribe creates a channel that receives log updates
 func (lbm *LogBufferManager) Subscribe(key string) <-chan []byte {
-	if _, exists := lbm.buffers[key]; !exists {
-		lbm.buffers[key] = NewCircularBuffer(MaxLogBufferSize)
+	return lbm.buffer(key).Subscribe()
+}
+
+// Unsubscribe stops log updates to a channel returned by Subscribe
+func (lbm *LogBufferManager) Unsubscribe(key string, ch <-chan []byte) {
+	lbm.mu.Lock()
+	buf, exists := lbm.buffers[key]
+	lbm.mu.Unlock()
+
+	if exists {
+		buf.Unsubscribe(ch)
 	}
-	return lbm.buffers[key].Subscribe()
 }
 
-// CircularBuffer implements a thread-safe circular buffer for logs
+// CircularBuffer implements a thread-safe circular buffer for logs. Once the
+// uncompressed tail grows past half of maxSize it is compressed in the
+// background and moved to the archive, so the buffer holds far more log
+// output than maxSize. The oldest archived chunks are dropped when the
+// archive and tail together exceed maxSize.
 type CircularBuffer struct {
-	data        []byte
+	mu          sync.Mutex
+	archive     []compressedChunk
+	archiveSize int
+	tail        []byte
 	maxSize     int
+	compressing bool
+	compressed  sync.WaitGroup
 	subscribers []chan []byte
 }
 
+// compressedChunk is a flate-compressed region of the log buffer
+type compressedChunk struct {
+	data    []byte
+	rawSize int
+}
+
 // NewCircularBuffer creates a new CircularBuffer
 func NewCircularBuffer(maxSize int) *CircularBuffer {
 	return &CircularBuffer{
-		data:        make([]byte, 0, maxSize),
 		maxSize:     maxSize,
 		subscribers: make([]chan []byte, 0),
 	}
@@ -272,18 +499,16 @@ func NewCircularBuffer(maxSize int) *CircularBuffer {
 
 // Write appends data to the buffer
 func (cb *CircularBuffer) Write(data []byte) {
-	// If adding data would exceed max size, truncate from beginning
-	if len(cb.data)+len(data) > cb.maxSize {
-		// Keep only the most recent data that fits
-		keepSize := cb.maxSize - len(data)
-		if keepSize > 0 {
-			cb.data = cb.data[len(cb.data)-keepSize:]
-		} else {
-			cb.data = cb.data[:0]
-		}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.tail = append(cb.tail, data...)
+	if !cb.compressing && len(cb.tail) > cb.maxSize/2 {
+		cb.compressing = true
+		cb.compressed.Add(1)
+		go cb.compress(cb.tail[:len(cb.tail):len(cb.tail)])
 	}
-
-	cb.data = append(cb.data, data...)
+	cb.evict()
 
 	// Notify all subscribers
 	for _, sub := range cb.subscribers {
@@ -295,16 +520,112 @@ func (cb *CircularBuffer) Write(data []byte) {
 	}
 }
 
+// compress moves the first len(snapshot) bytes of the tail to the archive
+func (cb *CircularBuffer) compress(snapshot []byte) {
+	defer cb.compressed.Done()
+
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestSpeed)
+	_, err := w.Write(snapshot)
+	if err == nil {
+		err = w.Close()
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.compressing = false
+	if err != nil {
+		return
+	}
+
+	cb.archive = append(cb.archive, compressedChunk{data: buf.Bytes(), rawSize: len(snapshot)})
+	cb.archiveSize += buf.Len()
+	cb.tail = append([]byte(nil), cb.tail[len(snapshot):]...)
+	cb.evict()
+
+	metrics.SetLogBufferCompressionRatio(cb.compressionRatio())
+}
+
+// evict drops the oldest data until the buffer fits in maxSize. The tail is
+// only truncated while no compression is in progress, as the running
+// compression holds a snapshot of its head.
+func (cb *CircularBuffer) evict() {
+	for len(cb.archive) > 0 && cb.archiveSize+len(cb.tail) > cb.maxSize {
+		cb.archiveSize -= len(cb.archive[0].data)
+		cb.archive = cb.archive[1:]
+	}
+	if !cb.compressing && len(cb.tail) > cb.maxSize {
+		cb.tail = cb.tail[len(cb.tail)-cb.maxSize:]
+	}
+}
+
 // Read returns all data in the buffer
 func (cb *CircularBuffer) Read() []byte {
-	result := make([]byte, len(cb.data))
-	copy(result, cb.data)
-	return result
+	cb.mu.Lock()
+	archive := cb.archive
+	tail := make([]byte, len(cb.tail))
+	copy(tail, cb.tail)
+	cb.mu.Unlock()
+
+	rawSize := len(tail)
+	for _, chunk := range archive {
+		rawSize += chunk.rawSize
+	}
+
+	result := make([]byte, 0, rawSize)
+	for _, chunk := range archive {
+		r := flate.NewReader(bytes.NewReader(chunk.data))
+		data, _ := io.ReadAll(r)
+		r.Close()
+		result = append(result, data...)
+	}
+	return append(result, tail...)
 }
 
 // Subscribe creates a channel that receives new log data
 func (cb *CircularBuffer) Subscribe() <-chan []byte {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	ch := make(chan []byte, 100)
 	cb.subscribers = append(cb.subscribers, ch)
 	return ch
 }
+
+// Unsubscribe removes and closes a channel returned by Subscribe
+func (cb *CircularBuffer) Unsubscribe(ch <-chan []byte) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	for i, sub := range cb.subscribers {
+		if sub == ch {
+			cb.subscribers = append(cb.subscribers[:i], cb.subscribers[i+1:]...)
+			close(sub)
+			return
+		}
+	}
+}
+
+// CompressionRatio returns the ratio of uncompressed to compressed size of
+// the archive, or 0 while nothing has been compressed
+func (cb *CircularBuffer) CompressionRatio() float64 {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.compressionRatio()
+}
+
+func (cb *CircularBuffer) compressionRatio() float64 {
+	if cb.archiveSize == 0 {
+		return 0
+	}
+	rawSize := 0
+	for _, chunk := range cb.archive {
+		rawSize += chunk.rawSize
+	}
+	return float64(rawSize) / float64(cb.archiveSize)
+}
+
+// WaitForCompression blocks until any background compression has finished
+func (cb *CircularBuffer) WaitForCompression() {
+	cb.compressed.Wait()
+}
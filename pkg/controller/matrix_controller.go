@@ -3,7 +3,10 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
 
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -12,6 +15,7 @@ import (
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
 	"github.com/org/c8s/pkg/scheduler"
+	ctypes "github.com/org/c8s/pkg/types"
 )
 
 // CreateMatrixPipelineRuns creates multiple PipelineRuns for matrix strategy execution
@@ -65,6 +69,7 @@ func CreateMatrixPipelineRuns(
 				MatrixIndex:       matrixVars,
 				CommitMessage:     baseRun.Spec.CommitMessage,
 				Author:            baseRun.Spec.Author,
+				Environment:       baseRun.Spec.Environment,
 			},
 		}
 
@@ -107,6 +112,36 @@ func ApplyMatrixToConfig(config *c8sv1alpha1.PipelineConfig, matrixVars map[stri
 	return newConfig
 }
 
+// InjectMatrixValues adds the matrix values of a step expanded from
+// matrixDimensions to its Job: as MATRIX_<DIMENSION> env vars of the step
+// container and as c8s.dev/matrix-<dimension> labels
+func InjectMatrixValues(job *batchv1.Job, matrixVars map[string]string) {
+	if len(matrixVars) == 0 {
+		return
+	}
+
+	dimensions := make([]string, 0, len(matrixVars))
+	for dimension := range matrixVars {
+		dimensions = append(dimensions, dimension)
+	}
+	sort.Strings(dimensions)
+
+	containers := job.Spec.Template.Spec.Containers
+	for i := range containers {
+		if containers[i].Name != ctypes.ContainerNameStep {
+			continue
+		}
+		for _, dimension := range dimensions {
+			containers[i].Env = append(containers[i].Env, corev1.EnvVar{
+				Name:  scheduler.MatrixEnvName(dimension),
+				Value: matrixVars[dimension],
+			})
+		}
+	}
+
+	job.Labels = mergeLabels(job.Labels, scheduler.MatrixToLabels(matrixVars))
+}
+
 // GetMatrixParentRun fetches the parent PipelineRun for a matrix execution
 func GetMatrixParentRun(ctx context.Context, c client.Client, matrixRun *c8sv1alpha1.PipelineRun) (*c8sv1alpha1.PipelineRun, error) {
 	parentID, ok := matrixRun.Labels["c8s.dev/matrix-parent"]
@@ -164,6 +199,11 @@ func ShouldCreateMatrixRuns(run *c8sv1alpha1.PipelineRun, config *c8sv1alpha1.Pi
 		return false
 	}
 
+	// Steps listing matrix dimensions run as Jobs of the run itself
+	if scheduler.HasStepMatrix(config) {
+		return false
+	}
+
 	// Only create matrix runs if the run is in Pending phase
 	// This ensures we only create them once
 	if run.Status.Phase != "" && run.Status.Phase != c8sv1alpha1.PipelineRunPhasePending {
@@ -0,0 +1,167 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// privateCIDRs are excluded from the internet egress rule so AllowInternet
+// does not implicitly grant access to cluster or LAN addresses
+var privateCIDRs = []string{
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"169.254.0.0/16",
+}
+
+// GetNetworkPolicyName returns the NetworkPolicy name for a PipelineRun
+func GetNetworkPolicyName(pipelineRunName string) string {
+	return fmt.Sprintf("%s-egress", pipelineRunName)
+}
+
+// BuildNetworkPolicy creates a NetworkPolicy restricting egress of all step Pods
+// belonging to the PipelineRun. Returns nil if the config has no network policy.
+func BuildNetworkPolicy(
+	pipelineRun *c8sv1alpha1.PipelineRun,
+	pipelineConfig *c8sv1alpha1.PipelineConfig,
+) (*networkingv1.NetworkPolicy, error) {
+	spec := pipelineConfig.Spec.NetworkPolicy
+	if spec == nil {
+		return nil, nil
+	}
+
+	egress := []networkingv1.NetworkPolicyEgressRule{dnsEgressRule()}
+
+	if spec.AllowInternet {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					IPBlock: &networkingv1.IPBlock{
+						CIDR:   "0.0.0.0/0",
+						Except: privateCIDRs,
+					},
+				},
+			},
+		})
+	}
+
+	if spec.AllowCluster {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{},
+				},
+			},
+		})
+	}
+
+	if len(spec.AllowedHosts) > 0 {
+		peers := make([]networkingv1.NetworkPolicyPeer, 0, len(spec.AllowedHosts))
+		for _, host := range spec.AllowedHosts {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: hostToCIDR(host)},
+			})
+		}
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{To: peers})
+	}
+
+	for _, rule := range spec.Egress {
+		if rule.CIDR == "" {
+			return nil, fmt.Errorf("egress rule is missing cidr")
+		}
+
+		egressRule := networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					IPBlock: &networkingv1.IPBlock{CIDR: hostToCIDR(rule.CIDR)},
+				},
+			},
+		}
+		for _, port := range rule.Ports {
+			egressRule.Ports = append(egressRule.Ports, networkPolicyPort(corev1.ProtocolTCP, port))
+		}
+		egress = append(egress, egressRule)
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetNetworkPolicyName(pipelineRun.Name),
+			Namespace: pipelineRun.Namespace,
+			Labels: map[string]string{
+				types.LabelPipelineConfig: pipelineRun.Spec.PipelineConfigRef,
+				types.LabelPipelineRun:    pipelineRun.Name,
+				types.LabelManagedBy:      types.ManagedByC8S,
+				types.LabelManaged:        types.LabelManagedValue,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pipelineRun, c8sv1alpha1.GroupVersion.WithKind("PipelineRun")),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			// Step Pods carry the pipeline-run label (see JobManager.CreateJobForStep)
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					types.LabelPipelineRun: pipelineRun.Name,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		},
+	}
+
+	return policy, nil
+}
+
+// dnsEgressRule allows DNS lookups to any destination
+func dnsEgressRule() networkingv1.NetworkPolicyEgressRule {
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			networkPolicyPort(corev1.ProtocolUDP, 53),
+			networkPolicyPort(corev1.ProtocolTCP, 53),
+		},
+	}
+}
+
+// networkPolicyPort builds a NetworkPolicyPort for a protocol and port number
+func networkPolicyPort(protocol corev1.Protocol, port int32) networkingv1.NetworkPolicyPort {
+	p := intstr.FromInt32(port)
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &p,
+	}
+}
+
+// hostToCIDR converts a bare IP address into a single-host CIDR block
+func hostToCIDR(host string) string {
+	if strings.Contains(host, "/") {
+		return host
+	}
+	if strings.Contains(host, ":") {
+		return host + "/128"
+	}
+	return host + "/32"
+}
@@ -18,20 +18,26 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
 	"github.com/org/c8s/pkg/scheduler"
 	ctypes "github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/vault"
 )
 
 // PipelineRunReconciler reconciles a PipelineRun object
@@ -39,6 +45,15 @@ type PipelineRunReconciler struct {
 	client.Client
 	Scheme       *runtime.Scheme
 	LogCollector *LogCollector
+	VaultClient  *vault.Client
+
+	// ResourceEstimator, when set, delays Job creation while the cluster
+	// lacks the resources the steps request
+	ResourceEstimator *ResourceEstimator
+
+	// Recorder, when set, receives the events of PipelineRun cancellations
+	// and invalid resource quotas
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=c8s.dev,resources=pipelineruns,verbs=get;list;watch;create;update;patch;delete
@@ -47,21 +62,27 @@ type PipelineRunReconciler struct {
 // +kubebuilder:rbac:groups=c8s.dev,resources=pipelineconfigs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch;create;update
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
+	ctx = WithJobPodCache(ctx)
 
 	// Fetch the PipelineRun instance
+	TracePhase(ctx, PhaseFetchPipelineRun)
 	pipelineRun := &c8sv1alpha1.PipelineRun{}
 	if err := r.Get(ctx, req.NamespacedName, pipelineRun); err != nil {
 		// PipelineRun not found, ignore since object must have been deleted
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	EndPhase(ctx)
 
 	logger.Info("Reconciling PipelineRun",
 		"name", pipelineRun.Name,
@@ -74,6 +95,12 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return r.handleDeletion(ctx, pipelineRun)
 	}
 
+	// Imported runs carry the history of another cluster and are never executed
+	if from, imported := pipelineRun.Annotations[ctypes.AnnotationImportedFrom]; imported {
+		logger.Info("PipelineRun was imported, skipping reconciliation", "importedFrom", from)
+		return ctrl.Result{}, nil
+	}
+
 	// Add finalizer if not present
 	if !containsString(pipelineRun.Finalizers, ctypes.FinalizerPipelineRun) {
 		logger.Info("Adding finalizer to PipelineRun")
@@ -93,7 +120,15 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	// Delete the Jobs of a run cancelled through the API
+	if IsCancellationRequested(pipelineRun) {
+		return r.reconcileCancellation(ctx, pipelineRun)
+	}
+
+	statusUpdater := NewStatusUpdater(r.Client)
+
 	// Step 1: Fetch referenced PipelineConfig
+	TracePhase(ctx, PhaseFetchPipelineConfig)
 	pipelineConfig := &c8sv1alpha1.PipelineConfig{}
 	configKey := types.NamespacedName{
 		Name:      pipelineRun.Spec.PipelineConfigRef,
@@ -101,11 +136,13 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 	if err := r.Get(ctx, configKey, pipelineConfig); err != nil {
 		if apierrors.IsNotFound(err) {
+			err = fmt.Errorf("%w: %s: %w", ctypes.ErrPipelineConfigNotFound, pipelineRun.Spec.PipelineConfigRef, err)
 			logger.Error(err, "PipelineConfig not found",
 				"config", pipelineRun.Spec.PipelineConfigRef,
 			)
 			// Update status to Failed
 			pipelineRun.Status.Phase = c8sv1alpha1.PipelineRunPhaseFailed
+			statusUpdater.SetConfigResolvedCondition(pipelineRun, err)
 			if updateErr := r.Status().Update(ctx, pipelineRun); updateErr != nil {
 				logger.Error(updateErr, "Failed to update PipelineRun status")
 			}
@@ -125,11 +162,19 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// Step 3: Build execution schedule using DAG scheduler
-	schedule, err := scheduler.BuildSchedule(pipelineConfig)
+	// Step 3: Build execution schedule using DAG scheduler, merging the
+	// steps of included PipelineConfigs
+	TracePhase(ctx, PhaseBuildSchedule)
+	schedule, err := r.buildSchedule(ctx, pipelineConfig)
 	if err != nil {
+		var status apierrors.APIStatus
+		if errors.As(err, &status) {
+			logger.Error(err, "Failed to fetch included PipelineConfig")
+			return ctrl.Result{}, err
+		}
 		logger.Error(err, "Failed to build execution schedule")
 		pipelineRun.Status.Phase = c8sv1alpha1.PipelineRunPhaseFailed
+		statusUpdater.SetConfigResolvedCondition(pipelineRun, err)
 		if updateErr := r.Status().Update(ctx, pipelineRun); updateErr != nil {
 			logger.Error(updateErr, "Failed to update PipelineRun status")
 		}
@@ -140,14 +185,80 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		"totalSteps", schedule.TotalSteps(),
 		"layers", schedule.LayerCount(),
 	)
+	statusUpdater.SetConfigResolvedCondition(pipelineRun, nil)
+	statusUpdater.StepAliases = schedule.Aliases
 
 	// Step 4: Get completed steps to determine which steps are ready
+	// Steps pre-completed by a retry count as succeeded without running a Job
 	completedSteps := GetCompletedSteps(pipelineRun)
-	logger.Info("Completed steps", "count", len(completedSteps))
+	var preCompletedSteps []string
+	for _, name := range GetPreCompletedSteps(pipelineRun) {
+		if _, exists := schedule.DAG.GetStep(name); exists {
+			preCompletedSteps = append(preCompletedSteps, name)
+			completedSteps[name] = true
+		}
+	}
+
+	// Steps restricted to other environments are skipped; dependent steps
+	// still run as if they had completed
+	environment := ResolveEnvironment(pipelineRun, pipelineConfig)
+	var skippedSteps []string
+	for _, layer := range schedule.Layers {
+		for _, step := range layer.Steps {
+			if completedSteps[step.Name] || StepRunsInEnvironment(step, environment) {
+				continue
+			}
+			skippedSteps = append(skippedSteps, step.Name)
+			completedSteps[step.Name] = true
+			MarkStepSkipped(pipelineRun, step.Name, environment)
+		}
+	}
+	logger.Info("Completed steps", "count", len(completedSteps), "preCompleted", len(preCompletedSteps),
+		"skipped", len(skippedSteps), "environment", environment)
+
+	// Step 4.5: Restrict step network access before any step Pod starts
+	if err := r.ensureNetworkPolicy(ctx, pipelineRun, pipelineConfig); err != nil {
+		logger.Error(err, "Failed to ensure NetworkPolicy")
+		return ctrl.Result{}, err
+	}
+
+	// Remember step phases to reset the requeue backoff when they change
+	previousPhases := stepPhases(pipelineRun)
 
 	// Step 5: Create Jobs for steps that are ready to execute
+	TracePhase(ctx, PhaseCreateJobs)
+	garbageCollector := NewPipelineRunGarbageCollector()
 	jobManager := NewJobManager(pipelineConfig.Spec.Repository)
-	readySteps := schedule.GetReadySteps(completedSteps)
+	readySteps := schedule.GetReadySteps(completedSteps, scheduler.WithWeightSort())
+	var jobErr error
+
+	// Steps in progress count against spec.maxParallel; ready steps are
+	// sorted by weight so the highest-weight steps get the free slots
+	maxParallel := pipelineConfig.Spec.MaxParallel
+	parallelSlots := maxParallel - activeStepCount(pipelineRun)
+
+	// Aggregate usage of active pipeline Jobs in the namespace for the quota check
+	quota := pipelineConfig.Spec.ResourceQuota
+	var quotaUsage *QuotaUsage
+	var quotaErr error
+	if quota != nil {
+		managedJobs := &batchv1.JobList{}
+		if err := r.List(ctx, managedJobs,
+			client.InNamespace(pipelineRun.Namespace),
+			client.MatchingLabels{
+				ctypes.LabelManaged: ctypes.LabelManagedValue,
+			},
+		); err != nil {
+			logger.Error(err, "Failed to list Jobs for resource quota")
+			return ctrl.Result{}, err
+		}
+		quotaUsage = AggregateQuotaUsage(managedJobs.Items)
+	}
+
+	// Steps whose Jobs are created in this pass count against the cluster
+	// headroom along with the step being checked
+	var headroomSteps []c8sv1alpha1.PipelineStep
+	var headroomErr error
 
 	for _, step := range readySteps {
 		// Check if Job already exists
@@ -171,23 +282,114 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			continue
 		}
 
+		// The Job of a step in progress was deleted externally; its step is
+		// failed below instead of running again
+		if garbageCollector.HasRecordedJob(pipelineRun, step.Name) {
+			continue
+		}
+
+		if maxParallel > 0 && parallelSlots <= 0 {
+			logger.Info("Parallelism limit reached, delaying Job creation", "step", step.Name, "maxParallel", maxParallel)
+			break
+		}
+
 		// Job doesn't exist, create it
 		logger.Info("Creating Job for step", "step", step.Name)
 		job, err := jobManager.CreateJobForStep(step, pipelineRun, pipelineConfig)
 		if err != nil {
 			logger.Error(err, "Failed to create Job spec", "step", step.Name)
+			jobErr = fmt.Errorf("step %s: %w", step.Name, err)
 			continue
 		}
+		InjectMatrixValues(job, schedule.MatrixValues[step.Name])
+
+		// Stop creating Jobs while the namespace quota would be exceeded
+		if quota != nil {
+			quotaErr = CheckResourceQuota(quota, quotaUsage, job)
+			if errors.Is(quotaErr, ctypes.ErrInvalidResourceQuota) {
+				logger.Error(quotaErr, "Invalid resource quota, delaying Job creation", "step", step.Name)
+				r.recordEvent(pipelineRun, corev1.EventTypeWarning, ctypes.ReasonInvalidResourceQuota, quotaErr.Error())
+				break
+			}
+			if quotaErr != nil {
+				logger.Info("Resource quota exceeded, delaying Job creation", "step", step.Name, "reason", quotaErr.Error())
+				break
+			}
+		}
+
+		// Stop creating Jobs while the cluster lacks the resources they request
+		if r.ResourceEstimator != nil {
+			err := r.ResourceEstimator.CheckHeadroom(ctx, append(headroomSteps, *step), pipelineRun.Namespace)
+			if errors.Is(err, ctypes.ErrStepExceedsNodeCapacity) {
+				logger.Info("Step requests more than any node can allocate, failing PipelineRun", "step", step.Name, "reason", err.Error())
+				MarkStepUnschedulable(pipelineRun, step.Name, err.Error())
+				break
+			}
+			if errors.Is(err, ctypes.ErrInsufficientHeadroom) {
+				headroomErr = err
+				logger.Info("Insufficient cluster headroom, delaying Job creation", "step", step.Name, "reason", err.Error())
+				break
+			}
+			if err != nil {
+				logger.Error(err, "Failed to check cluster headroom", "step", step.Name)
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Fetch Vault values before creating the Job so a Vault failure
+		// doesn't leave a Pod waiting on a Secret that never appears
+		var vaultData map[string][]byte
+		if len(step.VaultSecrets) > 0 {
+			vaultData, err = FetchVaultSecretData(ctx, r.VaultClient, step)
+			if err != nil {
+				logger.Error(err, "Failed to fetch Vault secrets", "step", step.Name)
+				jobErr = fmt.Errorf("step %s: %w", step.Name, err)
+				continue
+			}
+		}
+
+		// Create the Vault Secret before the Job so its Pod never starts
+		// without it
+		var vaultSecret *corev1.Secret
+		if vaultData != nil {
+			vaultSecret = BuildVaultSecret(job, pipelineRun, vaultData)
+			if err := r.Create(ctx, vaultSecret); err != nil && !apierrors.IsAlreadyExists(err) {
+				logger.Error(err, "Failed to create Vault Secret", "step", step.Name, "secret", vaultSecret.Name)
+				jobErr = fmt.Errorf("step %s: %w", step.Name, err)
+				continue
+			}
+		}
 
 		if err := r.Create(ctx, job); err != nil {
 			logger.Error(err, "Failed to create Job", "step", step.Name, "job", job.Name)
+			jobErr = fmt.Errorf("step %s: %w", step.Name, err)
 			continue
 		}
 
+		if vaultSecret != nil {
+			patch := client.MergeFrom(vaultSecret.DeepCopy())
+			SetVaultSecretOwner(vaultSecret, job)
+			if err := r.Patch(ctx, vaultSecret, patch); err != nil {
+				// The Secret is still removed with the PipelineRun
+				logger.Error(err, "Failed to hand Vault Secret to its Job", "step", step.Name, "secret", vaultSecret.Name)
+			}
+		}
+
+		if quotaUsage != nil {
+			quotaUsage.Add(job)
+		}
+		headroomSteps = append(headroomSteps, *step)
+		parallelSlots--
+
 		logger.Info("Successfully created Job", "step", step.Name, "job", job.Name)
 	}
 
+	if quota != nil {
+		statusUpdater.SetResourceQuotaCondition(pipelineRun, quotaErr)
+	}
+
 	// Step 6: List all Jobs owned by this PipelineRun
+	TracePhase(ctx, PhaseUpdateStatus)
 	jobList := &batchv1.JobList{}
 	if err := r.List(ctx, jobList,
 		client.InNamespace(pipelineRun.Namespace),
@@ -200,11 +402,20 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	// Build map of jobs by step name
+	stepsByLabel := make(map[string]string, schedule.TotalSteps())
+	for _, layer := range schedule.Layers {
+		for _, name := range layer.StepNames {
+			stepsByLabel[StepLabelValue(name)] = name
+		}
+	}
 	jobsByStep := make(map[string]*batchv1.Job)
 	for i := range jobList.Items {
 		job := &jobList.Items[i]
-		if stepName, ok := job.Labels[ctypes.LabelStepName]; ok {
-			jobsByStep[stepName] = job
+		if label, ok := job.Labels[ctypes.LabelStepName]; ok {
+			if stepName, known := stepsByLabel[label]; known {
+				label = stepName
+			}
+			jobsByStep[label] = job
 		}
 	}
 
@@ -213,25 +424,63 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		"expectedSteps", schedule.TotalSteps(),
 	)
 
+	expectedSteps := schedule.TotalSteps() - len(preCompletedSteps) - len(skippedSteps)
+	statusUpdater.SetJobsCreatedCondition(pipelineRun, len(jobsByStep), expectedSteps, jobErr)
+
+	// Fail steps whose Job was deleted while they were in progress
+	if orphaned := garbageCollector.Collect(pipelineRun, jobsByStep); len(orphaned) > 0 {
+		logger.Info("Jobs deleted externally, failing PipelineRun", "steps", orphaned)
+	}
+
 	// Step 7: Update PipelineRun status based on Job statuses
-	statusUpdater := NewStatusUpdater(r.Client)
-	if err := statusUpdater.UpdatePipelineRunStatus(ctx, pipelineRun, jobsByStep, schedule.TotalSteps()); err != nil {
+	retryPolicies := make(map[string]*c8sv1alpha1.RetryPolicy, len(jobsByStep))
+	for stepName := range jobsByStep {
+		step, _ := schedule.DAG.GetStep(stepName)
+		retryPolicies[stepName] = ResolveRetryPolicy(step, pipelineConfig)
+	}
+	if err := statusUpdater.UpdatePipelineRunStatus(ctx, pipelineRun, jobsByStep, retryPolicies, expectedSteps); err != nil {
 		logger.Error(err, "Failed to update PipelineRun status")
 		return ctrl.Result{}, err
 	}
 
-	// Step 7.5: Collect and upload logs for completed Jobs
+	// Step 7.5: Follow the logs of running steps, and collect and upload
+	// logs for completed Jobs
 	if r.LogCollector != nil {
+		TracePhase(ctx, PhaseCollectLogs)
+		r.followLogsOfRunningSteps(ctx, pipelineRun, pipelineConfig, jobsByStep)
 		if err := r.collectLogsForCompletedJobs(ctx, pipelineRun, pipelineConfig, jobsByStep); err != nil {
 			logger.Error(err, "Failed to collect logs for completed jobs")
 			// Continue even if log collection fails - don't block pipeline progress
 		}
 	}
 
+	EndPhase(ctx)
+
 	// Step 8: Requeue if not in terminal state
+	if quotaErr != nil && !r.isTerminalPhase(pipelineRun.Status.Phase) {
+		logger.Info("PipelineRun throttled by resource quota, requeuing", "reason", quotaErr.Error())
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	if headroomErr != nil && !r.isTerminalPhase(pipelineRun.Status.Phase) {
+		logger.Info("PipelineRun waiting for cluster headroom, requeuing")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
 	if !r.isTerminalPhase(pipelineRun.Status.Phase) {
-		logger.Info("PipelineRun still running, requeuing")
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		// Back off while nothing changes so that many in-flight runs don't
+		// all poll their Jobs at the same interval
+		changed := stepPhasesChanged(previousPhases, stepPhases(pipelineRun))
+		interval := NextRequeueInterval(GetRequeueInterval(pipelineRun), changed, MaxRequeueInterval)
+		if interval != GetRequeueInterval(pipelineRun) {
+			SetRequeueInterval(pipelineRun, interval)
+			if err := r.Update(ctx, pipelineRun); err != nil {
+				logger.Error(err, "Failed to record requeue interval")
+				return ctrl.Result{}, err
+			}
+		}
+		requeueAfter := JitterDuration(interval, MaxRequeueInterval)
+
+		logger.Info("PipelineRun still running, requeuing", "requeueAfter", requeueAfter, "jobPhaseChanged", changed)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	logger.Info("PipelineRun reconciliation complete",
@@ -245,6 +494,7 @@ func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 func (r *PipelineRunReconciler) collectLogsForCompletedJobs(ctx context.Context, pipelineRun *c8sv1alpha1.PipelineRun, pipelineConfig *c8sv1alpha1.PipelineConfig, jobsByStep map[string]*batchv1.Job) error {
 	logger := log.FromContext(ctx)
 	statusUpdated := false
+	var collectErr error
 
 	// Get the current status to check which steps have completed but don't have logs yet
 	for i := range pipelineRun.Status.Steps {
@@ -268,25 +518,17 @@ func (r *PipelineRunReconciler) collectLogsForCompletedJobs(ctx context.Context,
 		}
 
 		// Find the Pod created by this Job
-		podList := &corev1.PodList{}
-		if err := r.List(ctx, podList,
-			client.InNamespace(pipelineRun.Namespace),
-			client.MatchingLabels{
-				"job-name": job.Name,
-			},
-		); err != nil {
+		pod, err := GetJobPod(ctx, r.Client, job)
+		if err != nil {
 			logger.Error(err, "Failed to list Pods for Job", "job", job.Name)
 			continue
 		}
 
-		if len(podList.Items) == 0 {
+		if pod == nil {
 			logger.Info("No Pod found for Job yet", "job", job.Name, "step", step.Name)
 			continue
 		}
 
-		// Get the first Pod (Jobs typically create one Pod)
-		pod := &podList.Items[0]
-
 		// Skip if Pod is not in a state where we can collect logs
 		if pod.Status.Phase != corev1.PodSucceeded &&
 			pod.Status.Phase != corev1.PodFailed &&
@@ -300,6 +542,7 @@ func (r *PipelineRunReconciler) collectLogsForCompletedJobs(ctx context.Context,
 		logURL, err := r.LogCollector.CollectAndUpload(ctx, pod, pipelineRun, step.Name, pipelineConfig)
 		if err != nil {
 			logger.Error(err, "Failed to collect and upload logs", "step", step.Name)
+			collectErr = fmt.Errorf("step %s: %w", step.Name, err)
 			// Continue to next step - don't block on log collection failures
 			continue
 		}
@@ -310,6 +553,10 @@ func (r *PipelineRunReconciler) collectLogsForCompletedJobs(ctx context.Context,
 		logger.Info("Updated step with log URL", "step", step.Name, "url", logURL)
 	}
 
+	if NewStatusUpdater(r.Client).SetLogsCollectedCondition(pipelineRun, collectErr) {
+		statusUpdated = true
+	}
+
 	// Update PipelineRun status if any logs were collected
 	if statusUpdated {
 		if err := r.Status().Update(ctx, pipelineRun); err != nil {
@@ -321,6 +568,103 @@ func (r *PipelineRunReconciler) collectLogsForCompletedJobs(ctx context.Context,
 	return nil
 }
 
+// followLogsOfRunningSteps starts following the logs of the steps whose Pod
+// is running, so they are published while the step runs
+func (r *PipelineRunReconciler) followLogsOfRunningSteps(ctx context.Context, pipelineRun *c8sv1alpha1.PipelineRun, pipelineConfig *c8sv1alpha1.PipelineConfig, jobsByStep map[string]*batchv1.Job) {
+	logger := log.FromContext(ctx)
+
+	for _, step := range pipelineRun.Status.Steps {
+		if step.Phase != c8sv1alpha1.StepPhaseRunning {
+			continue
+		}
+		job, ok := jobsByStep[step.Name]
+		if !ok {
+			continue
+		}
+
+		pod, err := GetJobPod(ctx, r.Client, job)
+		if err != nil {
+			logger.Error(err, "Failed to list Pods for Job", "job", job.Name)
+			continue
+		}
+		if pod == nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		r.LogCollector.FollowLogs(ctx, pod, pipelineRun, step.Name, pipelineConfig)
+	}
+}
+
+// buildSchedule builds the execution schedule of a PipelineConfig and merges
+// the steps of the PipelineConfigs it includes. Errors fetching an included
+// config other than NotFound are returned as API errors so they are retried.
+func (r *PipelineRunReconciler) buildSchedule(ctx context.Context, pipelineConfig *c8sv1alpha1.PipelineConfig) (*scheduler.Schedule, error) {
+	schedule, err := scheduler.BuildSchedule(pipelineConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range pipelineConfig.Spec.Includes {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = pipelineConfig.Namespace
+		}
+
+		included := &c8sv1alpha1.PipelineConfig{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, included); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("included PipelineConfig %s/%s not found", namespace, ref.Name)
+			}
+			return nil, fmt.Errorf("failed to get included PipelineConfig %s/%s: %w", namespace, ref.Name, err)
+		}
+
+		includedSchedule, err := scheduler.BuildSchedule(included)
+		if err != nil {
+			return nil, fmt.Errorf("included PipelineConfig %s/%s: %w", namespace, ref.Name, err)
+		}
+		if schedule, err = scheduler.MergeSchedules(schedule, includedSchedule); err != nil {
+			return nil, err
+		}
+	}
+
+	if unresolved := schedule.DAG.UnresolvedDependencies(); len(unresolved) > 0 {
+		return nil, fmt.Errorf("%w: steps depend on %v, which are not in the included PipelineConfigs",
+			ctypes.ErrStepNotFound, unresolved)
+	}
+	return schedule, nil
+}
+
+// ensureNetworkPolicy creates the egress NetworkPolicy for step Pods if the
+// PipelineConfig requests one. The policy is owned by the PipelineRun and is
+// garbage collected with it.
+func (r *PipelineRunReconciler) ensureNetworkPolicy(ctx context.Context, pipelineRun *c8sv1alpha1.PipelineRun, pipelineConfig *c8sv1alpha1.PipelineConfig) error {
+	logger := log.FromContext(ctx)
+
+	policy, err := BuildNetworkPolicy(pipelineRun, pipelineConfig)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+
+	existing := &networkingv1.NetworkPolicy{}
+	err = r.Get(ctx, types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}, existing)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if err := r.Create(ctx, policy); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	logger.Info("Created NetworkPolicy for step Pods", "networkPolicy", policy.Name)
+	return nil
+}
+
 // isTerminalPhase returns true if the phase is terminal (no further transitions)
 func (r *PipelineRunReconciler) isTerminalPhase(phase c8sv1alpha1.PipelineRunPhase) bool {
 	return phase == c8sv1alpha1.PipelineRunPhaseSucceeded ||
@@ -377,6 +721,18 @@ func (r *PipelineRunReconciler) handleDeletion(ctx context.Context, pipelineRun
 	return ctrl.Result{}, nil
 }
 
+// activeStepCount returns the number of steps of a PipelineRun whose Job is
+// pending or running
+func activeStepCount(pipelineRun *c8sv1alpha1.PipelineRun) int {
+	count := 0
+	for _, step := range pipelineRun.Status.Steps {
+		if step.AliasOf == "" && isStepInProgress(step.Phase) {
+			count++
+		}
+	}
+	return count
+}
+
 // containsString checks if a slice contains a string
 func containsString(slice []string, s string) bool {
 	for _, item := range slice {
@@ -401,7 +757,8 @@ func removeString(slice []string, s string) []string {
 // SetupWithManager sets up the controller with the Manager.
 func (r *PipelineRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&c8sv1alpha1.PipelineRun{}).
+		For(&c8sv1alpha1.PipelineRun{}, builder.WithPredicates(PipelineRunChangedPredicate())).
 		Owns(&batchv1.Job{}).
-		Complete(r)
+		Owns(&networkingv1.NetworkPolicy{}).
+		Complete(NewReconciliationTracer(r))
 }
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// priorityClassValues maps pipeline priorities to PriorityClass values.
+// All values stay well below the system-* classes (2000000000+).
+var priorityClassValues = map[string]int32{
+	c8sv1alpha1.PriorityLow:      1000,
+	c8sv1alpha1.PriorityNormal:   10000,
+	c8sv1alpha1.PriorityHigh:     100000,
+	c8sv1alpha1.PriorityCritical: 1000000,
+}
+
+// GetPriorityClassName returns the PriorityClass name for a pipeline priority
+func GetPriorityClassName(priority string) string {
+	return fmt.Sprintf("c8s-%s", priority)
+}
+
+// ResolvePriorityClassName returns the PriorityClass for a run's Pods. The run's
+// priority overrides the PipelineConfig default; empty means no PriorityClass.
+func ResolvePriorityClassName(pipelineRun *c8sv1alpha1.PipelineRun, pipelineConfig *c8sv1alpha1.PipelineConfig) string {
+	priority := pipelineRun.Spec.Priority
+	if priority == "" && pipelineConfig != nil {
+		priority = pipelineConfig.Spec.Priority
+	}
+	if _, ok := priorityClassValues[priority]; !ok {
+		return ""
+	}
+	return GetPriorityClassName(priority)
+}
+
+// BuildPriorityClasses returns the PriorityClasses used for pipeline priorities
+func BuildPriorityClasses() []*schedulingv1.PriorityClass {
+	preemptLower := corev1.PreemptLowerPriority
+
+	classes := make([]*schedulingv1.PriorityClass, 0, len(c8sv1alpha1.ValidPriorities))
+	for _, priority := range c8sv1alpha1.ValidPriorities {
+		classes = append(classes, &schedulingv1.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: GetPriorityClassName(priority),
+				Labels: map[string]string{
+					types.LabelManagedBy: types.ManagedByC8S,
+				},
+			},
+			Value:            priorityClassValues[priority],
+			PreemptionPolicy: &preemptLower,
+			Description:      fmt.Sprintf("C8S pipeline runs with %s priority", priority),
+		})
+	}
+	return classes
+}
+
+// EnsurePriorityClasses creates or updates the pipeline PriorityClasses
+func EnsurePriorityClasses(ctx context.Context, c client.Client) error {
+	for _, desired := range BuildPriorityClasses() {
+		existing := &schedulingv1.PriorityClass{}
+		err := c.Get(ctx, client.ObjectKey{Name: desired.Name}, existing)
+		if apierrors.IsNotFound(err) {
+			if err := c.Create(ctx, desired); err != nil && !apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("failed to create PriorityClass %s: %w", desired.Name, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get PriorityClass %s: %w", desired.Name, err)
+		}
+
+		// Value and preemption policy are immutable, so only the metadata is updated
+		if existing.Value != desired.Value {
+			return fmt.Errorf("PriorityClass %s exists with value %d, expected %d", desired.Name, existing.Value, desired.Value)
+		}
+		existing.Description = desired.Description
+		if existing.Labels == nil {
+			existing.Labels = map[string]string{}
+		}
+		existing.Labels[types.LabelManagedBy] = types.ManagedByC8S
+		if err := c.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update PriorityClass %s: %w", desired.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math/rand"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+const (
+	// InitialRequeueInterval is the check interval of a PipelineRun after
+	// one of its Jobs changed phase
+	InitialRequeueInterval = 2 * time.Second
+
+	// MaxRequeueInterval caps the check interval of an idle PipelineRun
+	MaxRequeueInterval = 60 * time.Second
+
+	// requeueJitter is the fraction by which requeue intervals are randomized
+	// so that runs started together don't reconcile in lockstep
+	requeueJitter = 0.1
+)
+
+// BackoffDuration returns the next requeue interval of a PipelineRun: the
+// NextRequeueInterval with a random jitter of ±10%, without exceeding
+// maxDuration.
+func BackoffDuration(current time.Duration, changed bool, maxDuration time.Duration) time.Duration {
+	return JitterDuration(NextRequeueInterval(current, changed, maxDuration), maxDuration)
+}
+
+// NextRequeueInterval returns the requeue interval of a PipelineRun before
+// jitter. The interval restarts at InitialRequeueInterval when a Job phase
+// changed and otherwise doubles from current, up to maxDuration.
+func NextRequeueInterval(current time.Duration, changed bool, maxDuration time.Duration) time.Duration {
+	next := current * 2
+	if changed || current <= 0 {
+		next = InitialRequeueInterval
+	}
+	if next > maxDuration {
+		next = maxDuration
+	}
+	return next
+}
+
+// JitterDuration randomizes d by ±10%, without exceeding maxDuration
+func JitterDuration(d, maxDuration time.Duration) time.Duration {
+	jitter := time.Duration((rand.Float64()*2 - 1) * requeueJitter * float64(d))
+	d += jitter
+	if d > maxDuration {
+		d = maxDuration
+	}
+	return d
+}
+
+// GetRequeueInterval returns the requeue interval recorded on a PipelineRun,
+// or 0 if none is recorded
+func GetRequeueInterval(pipelineRun *c8sv1alpha1.PipelineRun) time.Duration {
+	value, ok := pipelineRun.Annotations[types.AnnotationRequeueAfter]
+	if !ok {
+		return 0
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// SetRequeueInterval records the requeue interval of a PipelineRun, before
+// jitter, so it only changes while the run backs off
+func SetRequeueInterval(pipelineRun *c8sv1alpha1.PipelineRun, interval time.Duration) {
+	if pipelineRun.Annotations == nil {
+		pipelineRun.Annotations = make(map[string]string)
+	}
+	pipelineRun.Annotations[types.AnnotationRequeueAfter] = interval.Round(time.Millisecond).String()
+}
+
+// PipelineRunChangedPredicate passes the updates of a PipelineRun that change
+// its spec, its deletion, or an annotation other than AnnotationRequeueAfter.
+// The controller's own status and requeue interval writes then don't trigger
+// a reconcile ahead of the RequeueAfter it returned.
+func PipelineRunChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return true
+			}
+			if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				!e.ObjectOld.GetDeletionTimestamp().Equal(e.ObjectNew.GetDeletionTimestamp()) {
+				return true
+			}
+			return !annotationsEqualExcept(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations(), types.AnnotationRequeueAfter)
+		},
+	}
+}
+
+// annotationsEqualExcept reports whether two annotation maps are equal,
+// ignoring key
+func annotationsEqualExcept(a, b map[string]string, key string) bool {
+	count := func(m map[string]string) int {
+		if _, ok := m[key]; ok {
+			return len(m) - 1
+		}
+		return len(m)
+	}
+	if count(a) != count(b) {
+		return false
+	}
+	for k, v := range a {
+		if k == key {
+			continue
+		}
+		if other, ok := b[k]; !ok || other != v {
+			return false
+		}
+	}
+	return true
+}
+
+// stepPhases returns the phase of each step in the status of a PipelineRun
+func stepPhases(pipelineRun *c8sv1alpha1.PipelineRun) map[string]c8sv1alpha1.StepPhase {
+	phases := make(map[string]c8sv1alpha1.StepPhase, len(pipelineRun.Status.Steps))
+	for _, step := range pipelineRun.Status.Steps {
+		phases[step.Name] = step.Phase
+	}
+	return phases
+}
+
+// stepPhasesChanged returns true if a step was added or changed phase
+func stepPhasesChanged(before, after map[string]c8sv1alpha1.StepPhase) bool {
+	if len(before) != len(after) {
+		return true
+	}
+	for name, phase := range after {
+		if before[name] != phase {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// TightHeadroomRatio is the fraction of allocatable CPU or memory below which
+// the headroom left after creating Jobs is logged as tight
+const TightHeadroomRatio = 0.2
+
+// ResourceEstimator predicts whether the cluster has room for the Jobs of
+// pipeline steps before they are created
+type ResourceEstimator struct {
+	client.Reader
+}
+
+// NewResourceEstimator creates a ResourceEstimator reading Nodes and Pods
+// through reader
+func NewResourceEstimator(reader client.Reader) *ResourceEstimator {
+	return &ResourceEstimator{Reader: reader}
+}
+
+// CheckHeadroom returns an error wrapping types.ErrInsufficientHeadroom if the
+// CPU or memory requested by steps exceeds the allocatable resources of the
+// schedulable Nodes minus the requests of Pods that have not finished. Only
+// the Pods running on those Nodes, or not yet scheduled to any Node, count:
+// Pods on cordoned Nodes use resources that were never counted as
+// allocatable, while pending Pods will compete with the steps for them. A
+// warning is logged when less than TightHeadroomRatio of a resource would be
+// left.
+//
+// A step that requests more than any schedulable Node can allocate would
+// never fit, however many Pods finish; the error then wraps
+// types.ErrStepExceedsNodeCapacity instead.
+func (e *ResourceEstimator) CheckHeadroom(ctx context.Context, steps []c8sv1alpha1.PipelineStep, namespace string) error {
+	logger := log.FromContext(ctx)
+
+	nodes := &corev1.NodeList{}
+	if err := e.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var allocatableCPU, allocatableMemory resource.Quantity
+	var schedulable []*corev1.Node
+	schedulableNames := make(map[string]bool, len(nodes.Items))
+	for i := range nodes.Items {
+		if nodes.Items[i].Spec.Unschedulable {
+			continue
+		}
+		allocatableCPU.Add(*nodes.Items[i].Status.Allocatable.Cpu())
+		allocatableMemory.Add(*nodes.Items[i].Status.Allocatable.Memory())
+		schedulable = append(schedulable, &nodes.Items[i])
+		schedulableNames[nodes.Items[i].Name] = true
+	}
+
+	// Without schedulable Nodes, a step waits for one to join the cluster
+	if len(schedulable) > 0 {
+		for _, step := range steps {
+			cpu, memory := StepResourceRequests([]c8sv1alpha1.PipelineStep{step})
+			if !fitsOnAnyNode(cpu, memory, schedulable) {
+				return fmt.Errorf("%w: step %s requests %s CPU and %s memory, more than any node can allocate",
+					types.ErrStepExceedsNodeCapacity, step.Name, cpu.String(), memory.String())
+			}
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := e.List(ctx, pods); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	availableCPU, availableMemory := allocatableCPU.DeepCopy(), allocatableMemory.DeepCopy()
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodSucceeded || pods.Items[i].Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if nodeName := pods.Items[i].Spec.NodeName; nodeName != "" && !schedulableNames[nodeName] {
+			continue
+		}
+		cpu, memory := podResourceRequests(&pods.Items[i])
+		availableCPU.Sub(cpu)
+		availableMemory.Sub(memory)
+	}
+
+	cpu, memory := StepResourceRequests(steps)
+	if cpu.Cmp(availableCPU) > 0 {
+		return fmt.Errorf("%w: steps in %s request %s CPU, %s of %s available",
+			types.ErrInsufficientHeadroom, namespace, cpu.String(), nonNegative(availableCPU), allocatableCPU.String())
+	}
+	if memory.Cmp(availableMemory) > 0 {
+		return fmt.Errorf("%w: steps in %s request %s memory, %s of %s available",
+			types.ErrInsufficientHeadroom, namespace, memory.String(), nonNegative(availableMemory), allocatableMemory.String())
+	}
+
+	availableCPU.Sub(cpu)
+	availableMemory.Sub(memory)
+	if isTight(availableCPU, allocatableCPU) || isTight(availableMemory, allocatableMemory) {
+		logger.Info("Warning: cluster resource headroom is tight",
+			"namespace", namespace,
+			"cpuRemaining", availableCPU.String(), "cpuAllocatable", allocatableCPU.String(),
+			"memoryRemaining", availableMemory.String(), "memoryAllocatable", allocatableMemory.String())
+	}
+	return nil
+}
+
+// MarkStepUnschedulable fails a step that has no Job because it requests more
+// than any Node can allocate, and with it the run
+func MarkStepUnschedulable(pipelineRun *c8sv1alpha1.PipelineRun, stepName, message string) {
+	now := metav1.Now()
+	status := GetStepStatus(pipelineRun, stepName)
+	if status == nil {
+		pipelineRun.Status.Steps = append(pipelineRun.Status.Steps, c8sv1alpha1.StepStatus{Name: stepName})
+		status = &pipelineRun.Status.Steps[len(pipelineRun.Status.Steps)-1]
+	}
+	status.Phase = c8sv1alpha1.StepPhaseFailed
+	status.Message = message
+	status.CompletionTime = &now
+	StepStatusHistory{}.Record(status, status.Phase, status.Message, now)
+	recordStepMetrics(pipelineRun.Namespace, status)
+
+	// A terminal phase is kept by the status update
+	pipelineRun.Status.Phase = c8sv1alpha1.PipelineRunPhaseFailed
+}
+
+// StepResourceRequests sums the CPU and memory the Jobs of steps request.
+// Steps without resources request nothing, like their Jobs.
+func StepResourceRequests(steps []c8sv1alpha1.PipelineStep) (cpu, memory resource.Quantity) {
+	for _, step := range steps {
+		if step.Resources == nil {
+			continue
+		}
+		if qty, err := resource.ParseQuantity(step.Resources.CPU); err == nil {
+			cpu.Add(qty)
+		}
+		if qty, err := resource.ParseQuantity(step.Resources.Memory); err == nil {
+			memory.Add(qty)
+		}
+	}
+	return cpu, memory
+}
+
+// fitsOnAnyNode reports whether the allocatable resources of one of nodes
+// cover both cpu and memory
+func fitsOnAnyNode(cpu, memory resource.Quantity, nodes []*corev1.Node) bool {
+	for _, node := range nodes {
+		if cpu.Cmp(*node.Status.Allocatable.Cpu()) <= 0 && memory.Cmp(*node.Status.Allocatable.Memory()) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// podResourceRequests returns the effective CPU and memory requests of a Pod:
+// the sum of its containers, or its largest init container if that is higher
+func podResourceRequests(pod *corev1.Pod) (cpu, memory resource.Quantity) {
+	for _, container := range pod.Spec.Containers {
+		cpu.Add(*container.Resources.Requests.Cpu())
+		memory.Add(*container.Resources.Requests.Memory())
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if initCPU := container.Resources.Requests.Cpu(); initCPU.Cmp(cpu) > 0 {
+			cpu = initCPU.DeepCopy()
+		}
+		if initMemory := container.Resources.Requests.Memory(); initMemory.Cmp(memory) > 0 {
+			memory = initMemory.DeepCopy()
+		}
+	}
+	return cpu, memory
+}
+
+// isTight reports whether remaining is less than TightHeadroomRatio of total
+func isTight(remaining, total resource.Quantity) bool {
+	if total.IsZero() {
+		return false
+	}
+	return float64(remaining.MilliValue()) < TightHeadroomRatio*float64(total.MilliValue())
+}
+
+// nonNegative formats q, or zero if q is negative
+func nonNegative(q resource.Quantity) string {
+	if q.Sign() < 0 {
+		return "0"
+	}
+	return q.String()
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// QuotaUsage is the aggregated usage of active pipeline Jobs in a namespace
+type QuotaUsage struct {
+	// Runs is the set of PipelineRuns with at least one active Job
+	Runs map[string]bool
+
+	// CPU is the total CPU requested by active Jobs
+	CPU resource.Quantity
+
+	// Memory is the total memory requested by active Jobs
+	Memory resource.Quantity
+}
+
+// AggregateQuotaUsage sums the resource requests of Jobs that have not finished
+func AggregateQuotaUsage(jobs []batchv1.Job) *QuotaUsage {
+	usage := &QuotaUsage{Runs: make(map[string]bool)}
+	for i := range jobs {
+		if isJobFinished(&jobs[i]) {
+			continue
+		}
+		usage.Add(&jobs[i])
+	}
+	return usage
+}
+
+// Add adds the resource requests of a Job to the usage
+func (u *QuotaUsage) Add(job *batchv1.Job) {
+	if runName := job.Labels[types.LabelPipelineRun]; runName != "" {
+		u.Runs[runName] = true
+	}
+
+	cpu, memory := jobResourceRequests(job)
+	u.CPU.Add(cpu)
+	u.Memory.Add(memory)
+}
+
+// CheckResourceQuota returns an error wrapping types.ErrResourceQuotaExceeded if
+// creating job would exceed the quota, or types.ErrInvalidResourceQuota if a
+// limit can't be parsed. A run that already has active Jobs does not count
+// against MaxConcurrentRuns again.
+func CheckResourceQuota(quota *c8sv1alpha1.ResourceQuotaSpec, usage *QuotaUsage, job *batchv1.Job) error {
+	if quota == nil {
+		return nil
+	}
+
+	runName := job.Labels[types.LabelPipelineRun]
+	if quota.MaxConcurrentRuns > 0 && !usage.Runs[runName] && len(usage.Runs) >= quota.MaxConcurrentRuns {
+		return fmt.Errorf("%w: %d of %d concurrent runs active", types.ErrResourceQuotaExceeded, len(usage.Runs), quota.MaxConcurrentRuns)
+	}
+
+	cpu, memory := jobResourceRequests(job)
+
+	if quota.MaxCPUTotal != "" {
+		limit, err := resource.ParseQuantity(quota.MaxCPUTotal)
+		if err != nil {
+			return fmt.Errorf("%w: maxCPUTotal %q: %w", types.ErrInvalidResourceQuota, quota.MaxCPUTotal, err)
+		}
+		total := usage.CPU.DeepCopy()
+		total.Add(cpu)
+		if total.Cmp(limit) > 0 {
+			return fmt.Errorf("%w: CPU %s would exceed %s", types.ErrResourceQuotaExceeded, total.String(), limit.String())
+		}
+	}
+
+	if quota.MaxMemoryTotal != "" {
+		limit, err := resource.ParseQuantity(quota.MaxMemoryTotal)
+		if err != nil {
+			return fmt.Errorf("%w: maxMemoryTotal %q: %w", types.ErrInvalidResourceQuota, quota.MaxMemoryTotal, err)
+		}
+		total := usage.Memory.DeepCopy()
+		total.Add(memory)
+		if total.Cmp(limit) > 0 {
+			return fmt.Errorf("%w: memory %s would exceed %s", types.ErrResourceQuotaExceeded, total.String(), limit.String())
+		}
+	}
+
+	return nil
+}
+
+// SetResourceQuotaCondition records whether Job creation is throttled by the
+// resource quota. quotaErr is the error returned by CheckResourceQuota; an
+// invalid quota leaves the condition Unknown.
+func (su *StatusUpdater) SetResourceQuotaCondition(pipelineRun *c8sv1alpha1.PipelineRun, quotaErr error) {
+	condition := metav1.Condition{
+		Type:    types.ConditionTypeResourceQuotaExceeded,
+		Status:  metav1.ConditionFalse,
+		Reason:  types.ReasonWithinResourceQuota,
+		Message: "Jobs fit within the resource quota",
+	}
+	switch {
+	case errors.Is(quotaErr, types.ErrInvalidResourceQuota):
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = types.ReasonInvalidResourceQuota
+		condition.Message = quotaErr.Error()
+	case quotaErr != nil:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = types.ReasonResourceQuotaExceeded
+		condition.Message = quotaErr.Error()
+	}
+
+	su.setCondition(pipelineRun, condition)
+}
+
+// jobResourceRequests returns the CPU and memory requested by a Job's containers
+func jobResourceRequests(job *batchv1.Job) (resource.Quantity, resource.Quantity) {
+	var cpu, memory resource.Quantity
+	for _, container := range job.Spec.Template.Spec.Containers {
+		if qty, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			cpu.Add(qty)
+		}
+		if qty, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			memory.Add(qty)
+		}
+	}
+	return cpu, memory
+}
+
+// isJobFinished returns true if the Job has a Complete or Failed condition
+func isJobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// ResolveRetryPolicy returns the retry policy of a step. The step's own
+// retry overrides the PipelineConfig retryPolicy; nil means no retries.
+func ResolveRetryPolicy(step *c8sv1alpha1.PipelineStep, pipelineConfig *c8sv1alpha1.PipelineConfig) *c8sv1alpha1.RetryPolicy {
+	if step != nil && step.Retry != nil {
+		return step.Retry
+	}
+	if pipelineConfig != nil {
+		return pipelineConfig.Spec.RetryPolicy
+	}
+	return nil
+}
+
+// IsRetryableExitCode reports whether a step that exited with exitCode may be
+// retried under policy. An empty onlyOnExitCodes list retries any failure.
+func IsRetryableExitCode(policy *c8sv1alpha1.RetryPolicy, exitCode int32) bool {
+	if policy == nil || policy.MaxRetries <= 0 {
+		return false
+	}
+	if len(policy.OnlyOnExitCodes) == 0 {
+		return true
+	}
+	return slices.Contains(policy.OnlyOnExitCodes, int(exitCode))
+}
+
+// GetStepPhase extracts the step phase from a Job retried under policy.
+// A failed attempt only fails the step once the Job gave up, the retries
+// are exhausted or the exit code is not retryable; otherwise the step stays
+// Running (or Pending) while the Job starts another Pod.
+func GetStepPhase(job *batchv1.Job, exitCode *int32, policy *c8sv1alpha1.RetryPolicy) c8sv1alpha1.StepPhase {
+	phase := GetJobStatus(job)
+	if phase != c8sv1alpha1.StepPhaseFailed || policy == nil {
+		return phase
+	}
+
+	if isJobFailed(job) || int(job.Status.Failed) > policy.MaxRetries {
+		return c8sv1alpha1.StepPhaseFailed
+	}
+	if exitCode != nil && !IsRetryableExitCode(policy, *exitCode) {
+		return c8sv1alpha1.StepPhaseFailed
+	}
+
+	if job.Status.Active > 0 {
+		return c8sv1alpha1.StepPhaseRunning
+	}
+	return c8sv1alpha1.StepPhasePending
+}
+
+// buildPodFailurePolicy makes the Job controller stop retrying a step whose
+// container exits with a code outside the policy's onlyOnExitCodes
+func buildPodFailurePolicy(policy *c8sv1alpha1.RetryPolicy) *batchv1.PodFailurePolicy {
+	if policy == nil || policy.MaxRetries <= 0 || len(policy.OnlyOnExitCodes) == 0 {
+		return nil
+	}
+
+	values := make([]int32, 0, len(policy.OnlyOnExitCodes))
+	for _, code := range policy.OnlyOnExitCodes {
+		values = append(values, int32(code))
+	}
+	slices.Sort(values)
+	values = slices.Compact(values)
+
+	containerName := types.ContainerNameStep
+	return &batchv1.PodFailurePolicy{
+		Rules: []batchv1.PodFailurePolicyRule{
+			{
+				Action: batchv1.PodFailurePolicyActionFailJob,
+				OnExitCodes: &batchv1.PodFailurePolicyOnExitCodesRequirement{
+					ContainerName: &containerName,
+					Operator:      batchv1.PodFailurePolicyOnExitCodesOpNotIn,
+					Values:        values,
+				},
+			},
+		},
+	}
+}
+
+// getStepExitCode reads the exit code of the step container from the primary
+// Pod of a Job, as returned by GetJobPod. Returns nil if that Pod hasn't
+// terminated.
+func (su *StatusUpdater) getStepExitCode(ctx context.Context, job *batchv1.Job) (*int32, error) {
+	pod, err := GetJobPod(ctx, su.client, job)
+	if err != nil {
+		return nil, err
+	}
+	if pod == nil || len(pod.Status.ContainerStatuses) == 0 {
+		return nil, nil
+	}
+
+	status := pod.Status.ContainerStatuses[0]
+	terminated := status.State.Terminated
+	if terminated == nil {
+		terminated = status.LastTerminationState.Terminated
+	}
+	if terminated == nil {
+		return nil, nil
+	}
+	exitCode := terminated.ExitCode
+	return &exitCode, nil
+}
+
+// isJobFailed reports whether the Job controller has given up on a Job
+func isJobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
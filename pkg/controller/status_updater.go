@@ -18,18 +18,30 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/metrics"
 	"github.com/org/c8s/pkg/types"
 )
 
 // StatusUpdater handles updating PipelineRun status based on Job statuses
 type StatusUpdater struct {
-	client client.Client
+	client  client.Client
+	history StepStatusHistory
+
+	// StepAliases maps the steps merged by step deduplication to the step
+	// they were merged into; their statuses mirror that step's
+	StepAliases map[string]string
 }
 
 // NewStatusUpdater creates a new StatusUpdater
@@ -37,11 +49,13 @@ func NewStatusUpdater(c client.Client) *StatusUpdater {
 	return &StatusUpdater{client: c}
 }
 
-// UpdatePipelineRunStatus updates the PipelineRun status based on Job statuses
+// UpdatePipelineRunStatus updates the PipelineRun status based on Job statuses.
+// retryPolicies maps step names to their resolved retry policy.
 func (su *StatusUpdater) UpdatePipelineRunStatus(
 	ctx context.Context,
 	pipelineRun *c8sv1alpha1.PipelineRun,
 	jobs map[string]*batchv1.Job,
+	retryPolicies map[string]*c8sv1alpha1.RetryPolicy,
 	expectedStepCount int,
 ) error {
 	// Initialize status if needed
@@ -85,7 +99,13 @@ func (su *StatusUpdater) UpdatePipelineRunStatus(
 		}
 
 		// Update from job
-		su.updateStepStatusFromJob(status, job)
+		previousPhase := status.Phase
+		if err := su.updateStepStatusFromJob(ctx, status, job, retryPolicies[stepName]); err != nil {
+			return err
+		}
+		if previousPhase != status.Phase {
+			recordStepMetrics(pipelineRun.Namespace, status)
+		}
 
 		// Count by phase
 		totalSteps++
@@ -115,12 +135,9 @@ func (su *StatusUpdater) UpdatePipelineRunStatus(
 		expectedStepCount,
 	)
 
-	// Handle phase transitions
-	if newPhase != pipelineRun.Status.Phase {
-		su.handlePhaseTransition(pipelineRun, pipelineRun.Status.Phase, newPhase)
-	}
-
 	pipelineRun.Status.Phase = newPhase
+	MirrorStepAliases(pipelineRun, su.StepAliases)
+	su.setStepsCompletedCondition(pipelineRun, newPhase, succeededSteps+failedSteps, expectedStepCount)
 
 	// Update timestamps
 	if hasStarted && pipelineRun.Status.StartTime == nil {
@@ -128,20 +145,124 @@ func (su *StatusUpdater) UpdatePipelineRunStatus(
 		pipelineRun.Status.StartTime = &now
 	}
 
+	// Record how long the run was queued once its first step starts
+	if hasStarted && pipelineRun.Status.QueueTime == nil {
+		firstStart := metav1.Now()
+		if start := firstStepStartTime(pipelineRun.Status.Steps); start != nil {
+			firstStart = *start
+		}
+		queueTime := firstStart.Sub(pipelineRun.CreationTimestamp.Time)
+		if queueTime < 0 {
+			queueTime = 0
+		}
+		pipelineRun.Status.QueueTime = &metav1.Duration{Duration: queueTime}
+		metrics.RecordPipelineQueueDuration(pipelineRun.Namespace, pipelineRun.Spec.PipelineConfigRef, queueTime.Seconds())
+	}
+
 	if su.isTerminalPhase(newPhase) && pipelineRun.Status.CompletionTime == nil {
 		now := metav1.Now()
 		pipelineRun.Status.CompletionTime = &now
+
+		metrics.RecordPipelineRunCreated(pipelineRun.Namespace, string(newPhase))
+		if pipelineRun.Status.StartTime != nil {
+			metrics.RecordPipelineRunCompleted(pipelineRun.Namespace, pipelineRun.Spec.PipelineConfigRef,
+				now.Sub(pipelineRun.Status.StartTime.Time).Seconds())
+		}
 	}
 
 	// Update status subresource
 	return su.client.Status().Update(ctx, pipelineRun)
 }
 
+// MirrorStepAliases sets the status of each alias step to a copy of the
+// status of the step it was merged into, once that step has one
+func MirrorStepAliases(pipelineRun *c8sv1alpha1.PipelineRun, aliases map[string]string) {
+	names := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+
+	for _, alias := range names {
+		target := GetStepStatus(pipelineRun, aliases[alias])
+		if target == nil {
+			continue
+		}
+		mirrored := *target.DeepCopy()
+		mirrored.Name = alias
+		mirrored.AliasOf = aliases[alias]
+
+		if existing := GetStepStatus(pipelineRun, alias); existing != nil {
+			*existing = mirrored
+		} else {
+			pipelineRun.Status.Steps = append(pipelineRun.Status.Steps, mirrored)
+		}
+	}
+}
+
+// firstStepStartTime returns the earliest start time of the steps that left
+// the Pending phase, or nil if none recorded one
+func firstStepStartTime(steps []c8sv1alpha1.StepStatus) *metav1.Time {
+	var first *metav1.Time
+	for i := range steps {
+		step := &steps[i]
+		if step.Phase == c8sv1alpha1.StepPhasePending || step.StartTime == nil {
+			continue
+		}
+		if first == nil || step.StartTime.Before(first) {
+			first = step.StartTime
+		}
+	}
+	return first
+}
+
+// recordStepMetrics records duration and failure metrics once a step reaches a terminal phase
+func recordStepMetrics(namespace string, status *c8sv1alpha1.StepStatus) {
+	if status.Phase != c8sv1alpha1.StepPhaseSucceeded && status.Phase != c8sv1alpha1.StepPhaseFailed {
+		return
+	}
+
+	if status.Phase == c8sv1alpha1.StepPhaseFailed {
+		metrics.RecordStepFailed(namespace, status.Name)
+	}
+
+	if status.StartTime != nil {
+		end := metav1.Now()
+		if status.CompletionTime != nil {
+			end = *status.CompletionTime
+		}
+		metrics.RecordStepCompleted(namespace, status.Name, string(status.Phase), end.Sub(status.StartTime.Time).Seconds())
+	}
+}
+
 // updateStepStatusFromJob updates a step status from a Job
-func (su *StatusUpdater) updateStepStatusFromJob(status *c8sv1alpha1.StepStatus, job *batchv1.Job) {
+func (su *StatusUpdater) updateStepStatusFromJob(
+	ctx context.Context,
+	status *c8sv1alpha1.StepStatus,
+	job *batchv1.Job,
+	policy *c8sv1alpha1.RetryPolicy,
+) error {
+	// Failed attempts carry the real exit code on their Pod
+	exitCode := GetJobExitCode(job)
+	if job.Status.Failed > 0 && job.Status.Succeeded == 0 {
+		podExitCode, err := su.getStepExitCode(ctx, job)
+		if err != nil {
+			return err
+		}
+		if podExitCode != nil {
+			exitCode = podExitCode
+		}
+	}
+
 	// Update phase
-	status.Phase = GetJobStatus(job)
+	previousRetries := status.Retries
+	status.Phase = GetStepPhase(job, exitCode, policy)
 	status.JobName = job.Name
+	status.Retries = job.Status.Failed
+	if status.Phase == c8sv1alpha1.StepPhaseFailed && status.Retries > 0 {
+		// The last failed attempt was not retried
+		status.Retries--
+	}
 
 	// Update timestamps
 	if job.Status.StartTime != nil && status.StartTime == nil {
@@ -153,7 +274,7 @@ func (su *StatusUpdater) updateStepStatusFromJob(status *c8sv1alpha1.StepStatus,
 	}
 
 	// Update exit code
-	status.ExitCode = GetJobExitCode(job)
+	status.ExitCode = exitCode
 
 	// Update message based on conditions
 	for _, condition := range job.Status.Conditions {
@@ -167,8 +288,18 @@ func (su *StatusUpdater) updateStepStatusFromJob(status *c8sv1alpha1.StepStatus,
 		}
 	}
 
+	// Record phase changes, including failed attempts that were retried
+	// between two reconciles
+	now := metav1.Now()
+	if status.Retries > previousRetries {
+		su.history.Record(status, c8sv1alpha1.StepPhaseFailed, fmt.Sprintf("Attempt %d failed, retrying", status.Retries), now)
+	}
+	su.history.Record(status, status.Phase, status.Message, now)
+
 	// TODO: Add log URL in Phase 4 (User Story 2 - Observability)
 	// TODO: Add artifact URLs in Phase 4 (User Story 2 - Observability)
+
+	return nil
 }
 
 // calculateOverallPhase determines the overall pipeline phase based on step statuses
@@ -188,8 +319,10 @@ func (su *StatusUpdater) calculateOverallPhase(
 	}
 
 	// If all expected steps succeeded, pipeline succeeds
-	// Use expectedStepCount, not totalSteps (which is only jobs that exist)
-	if succeededSteps == expectedStepCount && expectedStepCount > 0 {
+	// Use expectedStepCount, not totalSteps (which is only jobs that exist).
+	// No steps are expected when they were all skipped or completed by an
+	// earlier run, so there is nothing left to wait for.
+	if succeededSteps == expectedStepCount {
 		return c8sv1alpha1.PipelineRunPhaseSucceeded
 	}
 
@@ -212,59 +345,122 @@ func (su *StatusUpdater) calculateOverallPhase(
 	return c8sv1alpha1.PipelineRunPhaseRunning
 }
 
-// handlePhaseTransition handles actions when phase changes
-func (su *StatusUpdater) handlePhaseTransition(
-	pipelineRun *c8sv1alpha1.PipelineRun,
-	oldPhase, newPhase c8sv1alpha1.PipelineRunPhase,
-) {
-	// Add conditions for phase transitions
-	now := metav1.Now()
+// setStepsCompletedCondition records how many of the expected steps have
+// completed
+func (su *StatusUpdater) setStepsCompletedCondition(pipelineRun *c8sv1alpha1.PipelineRun, phase c8sv1alpha1.PipelineRunPhase, completed, expected int) {
+	condition := metav1.Condition{
+		Type:    types.ConditionTypeStepsCompleted,
+		Status:  metav1.ConditionFalse,
+		Reason:  types.ReasonStepRunning,
+		Message: fmt.Sprintf("%d of %d steps completed", completed, expected),
+	}
 
-	switch newPhase {
-	case c8sv1alpha1.PipelineRunPhaseRunning:
-		if oldPhase == c8sv1alpha1.PipelineRunPhasePending {
-			su.setCondition(pipelineRun, metav1.Condition{
-				Type:               types.ConditionTypeJobsCreated,
-				Status:             metav1.ConditionTrue,
-				Reason:             types.ReasonJobsCreated,
-				Message:            "Pipeline execution started",
-				LastTransitionTime: now,
-			})
+	switch phase {
+	case c8sv1alpha1.PipelineRunPhaseSucceeded:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = types.ReasonStepSucceeded
+		condition.Message = "All steps completed successfully"
+	case c8sv1alpha1.PipelineRunPhaseFailed:
+		condition.Reason = types.ReasonStepFailed
+		condition.Message = "One or more steps failed"
+	}
+
+	su.setCondition(pipelineRun, condition)
+}
+
+// SetConfigResolvedCondition records whether the PipelineConfig of a run was
+// found and a schedule built from it. resolveErr is the error fetching the
+// PipelineConfig or building the schedule.
+func (su *StatusUpdater) SetConfigResolvedCondition(pipelineRun *c8sv1alpha1.PipelineRun, resolveErr error) {
+	condition := metav1.Condition{
+		Type:    types.ConditionTypeConfigResolved,
+		Status:  metav1.ConditionTrue,
+		Reason:  types.ReasonPipelineConfigResolved,
+		Message: fmt.Sprintf("PipelineConfig %s resolved", pipelineRun.Spec.PipelineConfigRef),
+	}
+	if resolveErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = types.ReasonInvalidPipelineConfig
+		condition.Message = resolveErr.Error()
+		if errors.Is(resolveErr, types.ErrPipelineConfigNotFound) || apierrors.IsNotFound(resolveErr) {
+			condition.Reason = types.ReasonPipelineConfigNotFound
+			condition.Message = fmt.Sprintf("PipelineConfig %s not found", pipelineRun.Spec.PipelineConfigRef)
 		}
+	}
 
-	case c8sv1alpha1.PipelineRunPhaseSucceeded:
-		su.setCondition(pipelineRun, metav1.Condition{
-			Type:               types.ConditionTypeStepsCompleted,
-			Status:             metav1.ConditionTrue,
-			Reason:             types.ReasonStepSucceeded,
-			Message:            "All steps completed successfully",
-			LastTransitionTime: now,
-		})
+	su.setCondition(pipelineRun, condition)
+}
 
-	case c8sv1alpha1.PipelineRunPhaseFailed:
-		su.setCondition(pipelineRun, metav1.Condition{
-			Type:               types.ConditionTypeStepsCompleted,
-			Status:             metav1.ConditionFalse,
-			Reason:             types.ReasonStepFailed,
-			Message:            "One or more steps failed",
-			LastTransitionTime: now,
-		})
+// SetJobsCreatedCondition records whether a Job exists for every step of the
+// run. createErr is the last error creating a Job, if any.
+func (su *StatusUpdater) SetJobsCreatedCondition(pipelineRun *c8sv1alpha1.PipelineRun, created, expected int, createErr error) {
+	condition := metav1.Condition{
+		Type:    types.ConditionTypeJobsCreated,
+		Status:  metav1.ConditionFalse,
+		Reason:  types.ReasonJobsPending,
+		Message: fmt.Sprintf("%d of %d Jobs created", created, expected),
 	}
+
+	switch {
+	case createErr != nil:
+		condition.Reason = types.ReasonJobCreationFailed
+		condition.Message = createErr.Error()
+	case created >= expected:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = types.ReasonJobsCreated
+		condition.Message = fmt.Sprintf("All %d Jobs created", expected)
+	}
+
+	su.setCondition(pipelineRun, condition)
 }
 
-// setCondition sets or updates a condition in the PipelineRun status
-func (su *StatusUpdater) setCondition(pipelineRun *c8sv1alpha1.PipelineRun, condition metav1.Condition) {
-	// Find existing condition
-	for i, existingCondition := range pipelineRun.Status.Conditions {
-		if existingCondition.Type == condition.Type {
-			// Update existing condition
-			pipelineRun.Status.Conditions[i] = condition
-			return
+// SetLogsCollectedCondition records whether the logs of all completed steps
+// have been uploaded. collectErr is the last upload error, if any. Returns
+// whether the condition changed.
+func (su *StatusUpdater) SetLogsCollectedCondition(pipelineRun *c8sv1alpha1.PipelineRun, collectErr error) bool {
+	completed, collected := 0, 0
+	for _, step := range pipelineRun.Status.Steps {
+		if step.Phase != c8sv1alpha1.StepPhaseSucceeded && step.Phase != c8sv1alpha1.StepPhaseFailed {
+			continue
+		}
+		completed++
+		if step.LogURL != "" {
+			collected++
 		}
 	}
 
-	// Add new condition
-	pipelineRun.Status.Conditions = append(pipelineRun.Status.Conditions, condition)
+	condition := metav1.Condition{
+		Type:    types.ConditionTypeLogsCollected,
+		Status:  metav1.ConditionFalse,
+		Reason:  types.ReasonLogsPending,
+		Message: fmt.Sprintf("Logs of %d of %d completed steps collected", collected, completed),
+	}
+
+	switch {
+	case collectErr != nil:
+		condition.Reason = types.ReasonStorageError
+		condition.Message = collectErr.Error()
+	case collected == completed && su.isTerminalPhase(pipelineRun.Status.Phase):
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = types.ReasonLogsUploaded
+		condition.Message = fmt.Sprintf("Logs of all %d completed steps collected", completed)
+	}
+
+	return su.setCondition(pipelineRun, condition)
+}
+
+// setCondition sets or updates a condition in the PipelineRun status. The
+// transition time is kept while the condition status is unchanged. Returns
+// whether the condition changed.
+func (su *StatusUpdater) setCondition(pipelineRun *c8sv1alpha1.PipelineRun, condition metav1.Condition) bool {
+	existing := apimeta.FindStatusCondition(pipelineRun.Status.Conditions, condition.Type)
+	changed := existing == nil ||
+		existing.Status != condition.Status ||
+		existing.Reason != condition.Reason ||
+		existing.Message != condition.Message
+
+	apimeta.SetStatusCondition(&pipelineRun.Status.Conditions, condition)
+	return changed
 }
 
 // isTerminalPhase returns true if the phase is terminal (no further transitions)
@@ -295,6 +491,23 @@ func GetCompletedSteps(pipelineRun *c8sv1alpha1.PipelineRun) map[string]bool {
 	return completed
 }
 
+// GetPreCompletedSteps returns the steps marked as already completed by a retry
+// (see types.AnnotationPreCompletedSteps)
+func GetPreCompletedSteps(pipelineRun *c8sv1alpha1.PipelineRun) []string {
+	value := pipelineRun.Annotations[types.AnnotationPreCompletedSteps]
+	if value == "" {
+		return nil
+	}
+
+	var steps []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			steps = append(steps, name)
+		}
+	}
+	return steps
+}
+
 // IsStepReady returns true if a step is ready to execute (dependencies satisfied)
 func IsStepReady(stepName string, dependencies []string, completedSteps map[string]bool) bool {
 	for _, dep := range dependencies {
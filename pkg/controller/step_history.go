@@ -0,0 +1,58 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// DefaultStepStatusHistoryLimit is the number of phase transitions kept per
+// step, which bounds the size of the PipelineRun object
+const DefaultStepStatusHistoryLimit = 10
+
+// StepStatusHistory records the phase transitions of steps in their status,
+// so that a retried step shows e.g. Pending, Running, Failed, Running and
+// Succeeded rather than only its current phase
+type StepStatusHistory struct {
+	// Limit is the number of transitions kept per step, older ones being
+	// dropped (default DefaultStepStatusHistoryLimit)
+	Limit int
+}
+
+// Record appends a transition to phase to the history of a step, unless
+// phase is already the last recorded one
+func (h StepStatusHistory) Record(status *c8sv1alpha1.StepStatus, phase c8sv1alpha1.StepPhase, message string, timestamp metav1.Time) {
+	if n := len(status.History); n > 0 && status.History[n-1].Phase == phase {
+		return
+	}
+
+	status.History = append(status.History, c8sv1alpha1.StepStatusTransition{
+		Phase:     phase,
+		Timestamp: timestamp,
+		Message:   message,
+	})
+
+	limit := h.Limit
+	if limit <= 0 {
+		limit = DefaultStepStatusHistoryLimit
+	}
+	if excess := len(status.History) - limit; excess > 0 {
+		status.History = append(status.History[:0:0], status.History[excess:]...)
+	}
+}
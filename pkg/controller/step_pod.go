@@ -0,0 +1,82 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// jobPodLabel is the label the Job controller sets on the Pods of a Job
+const jobPodLabel = "job-name"
+
+// jobPodCacheKey is the context key of the per-reconcile Job Pod cache
+type jobPodCacheKey struct{}
+
+// WithJobPodCache returns a context in which GetJobPod remembers the Pod it
+// selected for each Job. Reconcile creates one per loop, so a Job's Pod is
+// listed at most once per reconcile and never cached across them.
+func WithJobPodCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, jobPodCacheKey{}, map[k8stypes.NamespacedName]*corev1.Pod{})
+}
+
+// GetJobPod returns the primary Pod of a Job: the most recently started of
+// its Pods, since a Job retrying a failed attempt has one Pod per attempt.
+// Returns nil if the Job has no Pod yet. Results are cached when ctx was
+// created by WithJobPodCache.
+func GetJobPod(ctx context.Context, c client.Client, job *batchv1.Job) (*corev1.Pod, error) {
+	key := k8stypes.NamespacedName{Namespace: job.Namespace, Name: job.Name}
+	cache, _ := ctx.Value(jobPodCacheKey{}).(map[k8stypes.NamespacedName]*corev1.Pod)
+	if pod, ok := cache[key]; ok {
+		return pod, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{jobPodLabel: job.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	var latest *corev1.Pod
+	for i := range pods.Items {
+		if latest == nil || podStartTime(latest).Before(podStartTime(&pods.Items[i])) {
+			latest = &pods.Items[i]
+		}
+	}
+
+	// A Job without Pods is not cached: its Pod may be created later in the
+	// same reconcile
+	if cache != nil && latest != nil {
+		cache[key] = latest
+	}
+	return latest, nil
+}
+
+// podStartTime returns when a Pod started, or was created if it hasn't
+func podStartTime(pod *corev1.Pod) *metav1.Time {
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime
+	}
+	return &pod.CreationTimestamp
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciliation phases timed by ReconciliationTracer
+const (
+	PhaseFetchPipelineRun    = "FetchPipelineRun"
+	PhaseFetchPipelineConfig = "FetchPipelineConfig"
+	PhaseBuildSchedule       = "BuildSchedule"
+	PhaseCreateJobs          = "CreateJobs"
+	PhaseUpdateStatus        = "UpdateStatus"
+	PhaseCollectLogs         = "CollectLogs"
+)
+
+// ReconcileTraceEnv enables OpenTelemetry spans for reconciliations when set
+// to true. Spans are exported by the global TracerProvider.
+const ReconcileTraceEnv = "RECONCILE_TRACE"
+
+// tracerName is the instrumentation name of the reconciliation spans
+const tracerName = "github.com/org/c8s/pkg/controller"
+
+// ReconciliationTracer wraps a reconciler to time the phases it marks with
+// TracePhase. Every phase is logged with its duration, and recorded as a
+// span of the reconciliation when tracing is enabled.
+type ReconciliationTracer struct {
+	reconciler reconcile.Reconciler
+	tracer     trace.Tracer
+}
+
+// NewReconciliationTracer wraps reconciler, emitting spans when
+// RECONCILE_TRACE=true
+func NewReconciliationTracer(reconciler reconcile.Reconciler) *ReconciliationTracer {
+	t := &ReconciliationTracer{reconciler: reconciler}
+	if enabled, _ := strconv.ParseBool(os.Getenv(ReconcileTraceEnv)); enabled {
+		t.tracer = otel.Tracer(tracerName)
+	}
+	return t
+}
+
+// Reconcile runs the wrapped reconciler with phase tracing enabled in ctx
+func (t *ReconciliationTracer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var span trace.Span
+	if t.tracer != nil {
+		ctx, span = t.tracer.Start(ctx, "Reconcile", trace.WithAttributes(
+			attribute.String("namespace", req.Namespace),
+			attribute.String("name", req.Name),
+		))
+		defer span.End()
+	}
+
+	rt := &reconcileTrace{tracer: t.tracer}
+	result, err := t.reconciler.Reconcile(context.WithValue(ctx, reconcileTraceKey{}, rt), req)
+	rt.end(ctx)
+
+	if span != nil && err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+// reconcileTraceKey is the context key of the current reconcileTrace
+type reconcileTraceKey struct{}
+
+// reconcileTrace tracks the open phase of a reconciliation
+type reconcileTrace struct {
+	tracer trace.Tracer
+
+	phase string
+	start time.Time
+	span  trace.Span
+}
+
+// TracePhase ends the current phase of the reconciliation in ctx, if any,
+// and starts the named one. Phases are sequential: the last one ends with
+// EndPhase or when Reconcile returns. Does nothing when ctx isn't traced.
+func TracePhase(ctx context.Context, phase string) {
+	rt, ok := ctx.Value(reconcileTraceKey{}).(*reconcileTrace)
+	if !ok {
+		return
+	}
+
+	rt.end(ctx)
+	rt.phase = phase
+	rt.start = time.Now()
+	if rt.tracer != nil {
+		_, rt.span = rt.tracer.Start(ctx, phase)
+	}
+}
+
+// EndPhase ends the current phase of the reconciliation in ctx
+func EndPhase(ctx context.Context) {
+	if rt, ok := ctx.Value(reconcileTraceKey{}).(*reconcileTrace); ok {
+		rt.end(ctx)
+	}
+}
+
+// end logs the duration of the open phase and ends its span
+func (rt *reconcileTrace) end(ctx context.Context) {
+	if rt.phase == "" {
+		return
+	}
+
+	log.FromContext(ctx).Info("Reconcile phase", "phase", rt.phase, "duration", time.Since(rt.start).String())
+	if rt.span != nil {
+		rt.span.End()
+		rt.span = nil
+	}
+	rt.phase = ""
+}
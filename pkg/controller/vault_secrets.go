@@ -0,0 +1,110 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/vault"
+)
+
+// GetVaultSecretName returns the name of the temporary Secret holding Vault values for a Job
+func GetVaultSecretName(jobName string) string {
+	return fmt.Sprintf("%s-vault", jobName)
+}
+
+// vaultSecretEnvVar returns the environment variable (and Secret key) for a Vault reference
+func vaultSecretEnvVar(ref c8sv1alpha1.VaultSecretRef) string {
+	if ref.EnvVar != "" {
+		return ref.EnvVar
+	}
+	return ref.Key
+}
+
+// VaultSecretReferences converts a step's Vault references into SecretReferences
+// pointing at the temporary Secret, so they are injected like regular secrets
+func VaultSecretReferences(jobName string, step *c8sv1alpha1.PipelineStep) []c8sv1alpha1.SecretReference {
+	if len(step.VaultSecrets) == 0 {
+		return nil
+	}
+
+	refs := make([]c8sv1alpha1.SecretReference, 0, len(step.VaultSecrets))
+	for _, ref := range step.VaultSecrets {
+		envVar := vaultSecretEnvVar(ref)
+		refs = append(refs, c8sv1alpha1.SecretReference{
+			SecretRef: GetVaultSecretName(jobName),
+			Key:       envVar,
+			EnvVar:    envVar,
+		})
+	}
+	return refs
+}
+
+// FetchVaultSecretData reads all Vault references of a step, keyed by environment variable name
+func FetchVaultSecretData(ctx context.Context, vaultClient *vault.Client, step *c8sv1alpha1.PipelineStep) (map[string][]byte, error) {
+	if vaultClient == nil {
+		return nil, fmt.Errorf("step %s uses vaultSecrets but no Vault client is configured", step.Name)
+	}
+
+	data := make(map[string][]byte, len(step.VaultSecrets))
+	for _, ref := range step.VaultSecrets {
+		value, err := vaultClient.ReadSecret(ctx, ref.Path, ref.Key)
+		if err != nil {
+			return nil, fmt.Errorf("step %s: %w", step.Name, err)
+		}
+		data[vaultSecretEnvVar(ref)] = []byte(value)
+	}
+	return data, nil
+}
+
+// BuildVaultSecret creates the temporary Secret for a Job. The Secret is
+// created before the Job, so it is owned by the PipelineRun until
+// SetVaultSecretOwner hands it to the Job.
+func BuildVaultSecret(job *batchv1.Job, pipelineRun *c8sv1alpha1.PipelineRun, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetVaultSecretName(job.Name),
+			Namespace: job.Namespace,
+			Labels: map[string]string{
+				types.LabelPipelineRun: job.Labels[types.LabelPipelineRun],
+				types.LabelStepName:    job.Labels[types.LabelStepName],
+				types.LabelManagedBy:   types.ManagedByC8S,
+				types.LabelManaged:     types.LabelManagedValue,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pipelineRun, c8sv1alpha1.GroupVersion.WithKind("PipelineRun")),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// SetVaultSecretOwner makes the Job the owner of its Vault Secret, so the
+// Secret is garbage collected when the Job is removed after its TTL
+func SetVaultSecretOwner(secret *corev1.Secret, job *batchv1.Job) {
+	secret.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job")),
+	}
+}
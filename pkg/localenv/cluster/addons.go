@@ -0,0 +1,268 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// AddonsConfigMap is the ConfigMap tracking the addons installed in a
+	// cluster, mapping each addon name to its installed version
+	AddonsConfigMap = "c8s-addons"
+
+	// AddonsNamespace is the namespace of the tracking ConfigMap
+	AddonsNamespace = "kube-system"
+)
+
+var configMapResource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// Addon is an optional component that can be installed into a cluster
+type Addon struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+
+	// ManifestURL is the official manifest applied to install the addon and
+	// deleted to uninstall it
+	ManifestURL string `json:"manifestURL"`
+}
+
+// AddonStatus describes an addon and whether it is installed in a cluster
+type AddonStatus struct {
+	Addon
+	Installed        bool   `json:"installed"`
+	InstalledVersion string `json:"installedVersion,omitempty"`
+}
+
+// Addons is the registry of installable addons, sorted by name
+var Addons = []Addon{
+	{
+		Name:        "cert-manager",
+		Description: "X.509 certificate management for webhook TLS",
+		Version:     "v1.16.1",
+		ManifestURL: "https://github.com/cert-manager/cert-manager/releases/download/v1.16.1/cert-manager.yaml",
+	},
+	{
+		Name:        "ingress-nginx",
+		Description: "NGINX Ingress controller to expose the webhook and dashboard",
+		Version:     "v1.11.3",
+		ManifestURL: "https://raw.githubusercontent.com/kubernetes/ingress-nginx/controller-v1.11.3/deploy/static/provider/cloud/deploy.yaml",
+	},
+	{
+		Name:        "local-path-provisioner",
+		Description: "Dynamic provisioning of node-local PersistentVolumes",
+		Version:     "v0.0.30",
+		ManifestURL: "https://raw.githubusercontent.com/rancher/local-path-provisioner/v0.0.30/deploy/local-path-storage.yaml",
+	},
+	{
+		Name:        "metrics-server",
+		Description: "Resource metrics for kubectl top and step resource usage",
+		Version:     "v0.7.2",
+		ManifestURL: "https://github.com/kubernetes-sigs/metrics-server/releases/download/v0.7.2/components.yaml",
+	},
+}
+
+// LookupAddon returns the registered addon with the given name
+func LookupAddon(name string) (Addon, error) {
+	for _, addon := range Addons {
+		if addon.Name == name {
+			return addon, nil
+		}
+	}
+	return Addon{}, fmt.Errorf("unknown addon %q (available: %s)", name, addonNames())
+}
+
+// addonNames returns the names of all registered addons as a comma-separated list
+func addonNames() string {
+	names := make([]string, 0, len(Addons))
+	for _, addon := range Addons {
+		names = append(names, addon.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ListAddons returns every registered addon with its status in a cluster
+func ListAddons(ctx context.Context, clusterName string) ([]AddonStatus, error) {
+	client, err := addonsClient(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return AddonStatuses(ctx, client)
+}
+
+// AddonStatuses returns every registered addon with its status, read from
+// the tracking ConfigMap through client
+func AddonStatuses(ctx context.Context, client dynamic.Interface) ([]AddonStatus, error) {
+	installed, err := InstalledAddons(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]AddonStatus, 0, len(Addons))
+	for _, addon := range Addons {
+		version, ok := installed[addon.Name]
+		statuses = append(statuses, AddonStatus{Addon: addon, Installed: ok, InstalledVersion: version})
+	}
+	return statuses, nil
+}
+
+// InstalledAddons returns the installed addons recorded in the tracking
+// ConfigMap, keyed by name with the installed version as value
+func InstalledAddons(ctx context.Context, client dynamic.Interface) (map[string]string, error) {
+	configMap, err := client.Resource(configMapResource).Namespace(AddonsNamespace).Get(ctx, AddonsConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s: %w", AddonsConfigMap, err)
+	}
+
+	data, _, err := unstructured.NestedStringMap(configMap.Object, "data")
+	if err != nil {
+		return nil, fmt.Errorf("invalid ConfigMap %s: %w", AddonsConfigMap, err)
+	}
+	if data == nil {
+		data = map[string]string{}
+	}
+	return data, nil
+}
+
+// InstalledAddonNames returns the sorted names of the installed addons
+func InstalledAddonNames(ctx context.Context, client dynamic.Interface) ([]string, error) {
+	installed, err := InstalledAddons(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(installed))
+	for name := range installed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// InstallAddon applies the manifest of an addon to a cluster and records it
+// in the tracking ConfigMap. Installing an installed addon applies its
+// manifest again.
+func InstallAddon(ctx context.Context, clusterName, name string) (*Addon, error) {
+	addon, err := LookupAddon(name)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := addonsClient(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := runKubectl(ctx, fmt.Sprintf("k3d-%s", clusterName), "apply", "-f", addon.ManifestURL); err != nil {
+		return nil, fmt.Errorf("failed to apply %s manifest: %w", addon.Name, err)
+	}
+
+	if err := RecordAddon(ctx, client, addon.Name, addon.Version); err != nil {
+		return nil, err
+	}
+	return &addon, nil
+}
+
+// UninstallAddon deletes the resources of an installed addon from a cluster
+// and removes it from the tracking ConfigMap
+func UninstallAddon(ctx context.Context, clusterName, name string) (*Addon, error) {
+	addon, err := LookupAddon(name)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := addonsClient(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := InstalledAddons(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := installed[addon.Name]; !ok {
+		return nil, fmt.Errorf("addon %s is not installed", addon.Name)
+	}
+
+	if _, err := runKubectl(ctx, fmt.Sprintf("k3d-%s", clusterName), "delete", "-f", addon.ManifestURL, "--ignore-not-found"); err != nil {
+		return nil, fmt.Errorf("failed to delete %s resources: %w", addon.Name, err)
+	}
+
+	if err := ForgetAddon(ctx, client, addon.Name); err != nil {
+		return nil, err
+	}
+	return &addon, nil
+}
+
+// RecordAddon marks an addon as installed in the tracking ConfigMap,
+// creating the ConfigMap when needed
+func RecordAddon(ctx context.Context, client dynamic.Interface, name, version string) error {
+	resource := client.Resource(configMapResource).Namespace(AddonsNamespace)
+
+	configMap, err := resource.Get(ctx, AddonsConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      AddonsConfigMap,
+				"namespace": AddonsNamespace,
+			},
+			"data": map[string]interface{}{name: version},
+		}}
+		if _, err := resource.Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create ConfigMap %s: %w", AddonsConfigMap, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s: %w", AddonsConfigMap, err)
+	}
+
+	if err := unstructured.SetNestedField(configMap.Object, version, "data", name); err != nil {
+		return fmt.Errorf("invalid ConfigMap %s: %w", AddonsConfigMap, err)
+	}
+	if _, err := resource.Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s: %w", AddonsConfigMap, err)
+	}
+	return nil
+}
+
+// ForgetAddon removes an addon from the tracking ConfigMap
+func ForgetAddon(ctx context.Context, client dynamic.Interface, name string) error {
+	resource := client.Resource(configMapResource).Namespace(AddonsNamespace)
+
+	configMap, err := resource.Get(ctx, AddonsConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get ConfigMap %s: %w", AddonsConfigMap, err)
+	}
+
+	unstructured.RemoveNestedField(configMap.Object, "data", name)
+	if _, err := resource.Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s: %w", AddonsConfigMap, err)
+	}
+	return nil
+}
+
+// addonsClient returns a dynamic client for a cluster, checking it exists
+func addonsClient(ctx context.Context, clusterName string) (dynamic.Interface, error) {
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, clusterName); err != nil {
+		return nil, &ClusterNotFoundError{Name: clusterName}
+	}
+	return newDynamicClient(clusterName)
+}
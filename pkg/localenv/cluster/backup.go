@@ -0,0 +1,187 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// backupResources are the resources saved by a backup, in the order they
+// are restored: PipelineConfigs before the PipelineRuns referencing them
+var backupResources = []schema.GroupVersionResource{pipelineConfigResource, pipelineRunResource}
+
+// serverFields are the metadata fields set by the API server, which must be
+// removed for a backed up object to be created again
+var serverFields = []string{"resourceVersion", "uid", "generation", "managedFields", "creationTimestamp", "selfLink"}
+
+// StateResult counts the objects saved to or restored from a backup
+type StateResult struct {
+	PipelineConfigs int `json:"pipelineConfigs"`
+	PipelineRuns    int `json:"pipelineRuns"`
+}
+
+// BackupState returns all PipelineConfigs and PipelineRuns of a cluster as
+// a multi-document YAML file that can be re-applied with RestoreState or
+// kubectl apply. Server-side fields and status are stripped.
+func BackupState(ctx context.Context, clusterName string) ([]byte, *StateResult, error) {
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, clusterName); err != nil {
+		return nil, nil, &ClusterNotFoundError{Name: clusterName}
+	}
+
+	client, err := newDynamicClient(clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return BackupResources(ctx, client)
+}
+
+// BackupResources serializes the backed up resources read through client
+func BackupResources(ctx context.Context, client dynamic.Interface) ([]byte, *StateResult, error) {
+	result := &StateResult{}
+	var buf bytes.Buffer
+
+	for _, gvr := range backupResources {
+		list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// CRDs not installed
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			StripServerFields(item)
+
+			data, err := yaml.Marshal(item.Object)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+			}
+			buf.WriteString("---\n")
+			buf.Write(data)
+		}
+
+		if gvr == pipelineRunResource {
+			result.PipelineRuns = len(list.Items)
+		} else {
+			result.PipelineConfigs = len(list.Items)
+		}
+	}
+
+	return buf.Bytes(), result, nil
+}
+
+// StripServerFields removes status and the metadata fields set by the API
+// server from an object
+func StripServerFields(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range serverFields {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+}
+
+// RestoreState applies every resource of a backup created by BackupState to
+// a cluster. Existing objects are updated to match the backup.
+func RestoreState(ctx context.Context, clusterName string, data []byte) (*StateResult, error) {
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, clusterName); err != nil {
+		return nil, &ClusterNotFoundError{Name: clusterName}
+	}
+
+	client, err := newDynamicClient(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return RestoreResources(ctx, client, data)
+}
+
+// RestoreResources applies every resource of a backup through client
+func RestoreResources(ctx context.Context, client dynamic.Interface, data []byte) (*StateResult, error) {
+	objects, err := parseBackup(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StateResult{}
+	for _, gvr := range backupResources {
+		for _, obj := range objects {
+			if resourceForKind(obj.GetKind()) != gvr {
+				continue
+			}
+			if err := applyObject(ctx, client.Resource(gvr).Namespace(obj.GetNamespace()), obj); err != nil {
+				return result, fmt.Errorf("failed to restore %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+			}
+
+			if gvr == pipelineRunResource {
+				result.PipelineRuns++
+			} else {
+				result.PipelineConfigs++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// parseBackup splits a multi-document backup into objects, rejecting any
+// kind that is not part of a backup
+func parseBackup(data []byte) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for i, doc := range strings.Split("\n"+string(data), "\n---") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
+			return nil, fmt.Errorf("invalid backup document %d: %w", i, err)
+		}
+		if obj.Object == nil {
+			continue
+		}
+		if obj.GroupVersionKind().GroupVersion() != c8sv1alpha1.GroupVersion || resourceForKind(obj.GetKind()).Empty() {
+			return nil, fmt.Errorf("invalid backup document %d: unsupported kind %s %s", i, obj.GetAPIVersion(), obj.GetKind())
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// resourceForKind returns the backed up resource of a kind
+func resourceForKind(kind string) schema.GroupVersionResource {
+	switch kind {
+	case "PipelineConfig":
+		return pipelineConfigResource
+	case "PipelineRun":
+		return pipelineRunResource
+	}
+	return schema.GroupVersionResource{}
+}
+
+// applyObject creates an object, or updates it when it already exists
+func applyObject(ctx context.Context, resource dynamic.ResourceInterface, obj *unstructured.Unstructured) error {
+	_, err := resource.Create(ctx, obj, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	_, err = resource.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,182 @@
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultImageBackupFile is the tarball written by BackupImages by default
+const DefaultImageBackupFile = "backup-images.tar"
+
+// BackupImagesOptions holds options for saving the images used by a cluster
+type BackupImagesOptions struct {
+	Name   string
+	Output string
+
+	// OnPull is called before an image missing from the local Docker daemon
+	// is pulled
+	OnPull func(image string)
+}
+
+// BackupImagesResult describes a saved image tarball
+type BackupImagesResult struct {
+	Cluster string   `json:"cluster"`
+	Images  []string `json:"images"`
+	Pulled  []string `json:"pulled,omitempty"`
+	File    string   `json:"file"`
+	Size    int64    `json:"size"`
+}
+
+// RestoreImagesResult describes images loaded from a tarball into a cluster
+type RestoreImagesResult struct {
+	Cluster string   `json:"cluster"`
+	Images  []string `json:"images"`
+	Nodes   []string `json:"nodes"`
+}
+
+// loadedImagePrefix starts the lines of 'docker load' output naming an image
+const loadedImagePrefix = "Loaded image: "
+
+// BackupImages saves the images of the running Pods of a cluster to a
+// tarball with 'docker save', pulling the images missing from the local
+// Docker daemon first, so the cluster can run them without network access
+// after RestoreImages
+func BackupImages(ctx context.Context, opts BackupImagesOptions) (*BackupImagesResult, error) {
+	if opts.Output == "" {
+		opts.Output = DefaultImageBackupFile
+	}
+
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, opts.Name); err != nil {
+		return nil, &ClusterNotFoundError{Name: opts.Name}
+	}
+
+	restConfig, err := clusterRESTConfig(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	images := PodImages(pods.Items)
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no running Pods with named images in cluster '%s'", opts.Name)
+	}
+	result := &BackupImagesResult{Cluster: opts.Name, Images: images, File: opts.Output}
+
+	for _, image := range images {
+		if exec.CommandContext(ctx, "docker", "image", "inspect", image).Run() == nil {
+			continue
+		}
+		if opts.OnPull != nil {
+			opts.OnPull(image)
+		}
+		if output, err := exec.CommandContext(ctx, "docker", "pull", image).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to pull %s: %s", image, strings.TrimSpace(string(output)))
+		}
+		result.Pulled = append(result.Pulled, image)
+	}
+
+	args := append([]string{"save", "-o", opts.Output}, images...)
+	if output, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("docker save failed: %s", strings.TrimSpace(string(output)))
+	}
+
+	if info, err := os.Stat(opts.Output); err == nil {
+		result.Size = info.Size()
+	}
+	return result, nil
+}
+
+// RestoreImages loads the images of a tarball written by BackupImages into
+// the local Docker daemon with 'docker load', then into the nodes of a
+// cluster with 'k3d image import'
+func RestoreImages(ctx context.Context, name, input string, onNode func(node string)) (*RestoreImagesResult, error) {
+	if _, err := os.Stat(input); err != nil {
+		return nil, err
+	}
+
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, name); err != nil {
+		return nil, &ClusterNotFoundError{Name: name}
+	}
+
+	output, err := exec.CommandContext(ctx, "docker", "load", "-i", input).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("docker load failed: %s", strings.TrimSpace(string(output)))
+	}
+	images := ParseLoadedImages(string(output))
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no tagged images found in %s", input)
+	}
+
+	loaded, err := LoadImage(ctx, LoadImageOptions{Name: name, Images: images, OnNode: onNode})
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreImagesResult{Cluster: name, Images: images, Nodes: loaded.Nodes}, nil
+}
+
+// PodImages returns the sorted image references run by the containers of
+// the running Pods. Containers are identified by the ImageID of their
+// status, so an image pulled under several names is listed once, and images
+// known only by their ID can't be saved with a name and are skipped.
+func PodImages(pods []corev1.Pod) []string {
+	byID := map[string]string{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.Image == "" || strings.HasPrefix(status.Image, "sha256:") {
+				continue
+			}
+			id := status.ImageID
+			if id == "" {
+				id = status.Image
+			}
+			if current, ok := byID[id]; !ok || status.Image < current {
+				byID[id] = status.Image
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	images := make([]string, 0, len(byID))
+	for _, image := range byID {
+		if !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+	sort.Strings(images)
+	return images
+}
+
+// ParseLoadedImages returns the images named in 'docker load' output
+func ParseLoadedImages(output string) []string {
+	var images []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if image, found := strings.CutPrefix(strings.TrimSpace(scanner.Text()), loadedImagePrefix); found {
+			images = append(images, image)
+		}
+	}
+	return images
+}
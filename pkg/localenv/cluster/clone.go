@@ -0,0 +1,314 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv"
+	"github.com/org/c8s/pkg/types"
+)
+
+// crdEstablishTimeout is how long copied CRDs may take to be served
+const crdEstablishTimeout = 30 * time.Second
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// CloneOptions holds options for cloning a cluster
+type CloneOptions struct {
+	Source  string
+	Target  string
+	Timeout time.Duration
+
+	// WithState copies the c8s CRDs, PipelineConfigs and PipelineRuns of the
+	// source cluster to the clone
+	WithState bool
+}
+
+// CloneResult describes a cloned cluster
+type CloneResult struct {
+	Status *localenv.ClusterStatus `json:"status"`
+	Config *localenv.ClusterConfig `json:"config"`
+
+	// State counts the objects copied with WithState
+	State *StateResult `json:"state,omitempty"`
+}
+
+// k3dNode is the part of a node of 'k3d cluster list -o json' needed to
+// clone a cluster
+type k3dNode struct {
+	Name         string                      `json:"name"`
+	Role         string                      `json:"role"`
+	Volumes      []string                    `json:"volumes"`
+	Args         []string                    `json:"extraArgs"`
+	PortMappings map[string][]k3dPortBinding `json:"portMappings"`
+}
+
+// k3dPortBinding is a host binding of a node port
+type k3dPortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// Clone creates a cluster with the configuration of an existing one: node
+// counts, Kubernetes version, registry, port mappings, volume mounts and k3s
+// arguments. Host ports used by the source are moved to the next free port.
+func Clone(ctx context.Context, opts CloneOptions) (*CloneResult, error) {
+	source, err := Inspect(ctx, opts.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	config := CloneClusterConfig(SourceClusterConfig(source), opts.Target, isHostPortFree)
+	status, err := Create(ctx, CreateOptions{Config: config, Wait: true, Timeout: opts.Timeout})
+	if err != nil {
+		return nil, err
+	}
+	result := &CloneResult{Status: status, Config: config}
+
+	if opts.WithState {
+		from, err := newDynamicClient(opts.Source)
+		if err != nil {
+			return result, err
+		}
+		to, err := newDynamicClient(opts.Target)
+		if err != nil {
+			return result, err
+		}
+		if result.State, err = CopyState(ctx, from, to); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// SourceClusterConfig returns the configuration of an inspected cluster,
+// completed with the port mappings, volume mounts and k3s arguments recorded
+// by k3d
+func SourceClusterConfig(result *InspectResult) *localenv.ClusterConfig {
+	config := *result.Config
+
+	var cluster struct {
+		Nodes []k3dNode `json:"nodes"`
+	}
+	if data, err := json.Marshal(result.K3dCluster); err == nil {
+		_ = json.Unmarshal(data, &cluster)
+	}
+
+	roleCounts := map[string]int{}
+	for _, node := range cluster.Nodes {
+		roleCounts[node.Role]++
+	}
+
+	// Bind mounts of host paths, with the nodes they are mounted on
+	mountNodes := map[localenv.VolumeMount][]string{}
+	var mounts []localenv.VolumeMount
+	for _, node := range cluster.Nodes {
+		for _, volume := range node.Volumes {
+			parts := strings.Split(volume, ":")
+			if len(parts) < 2 || !strings.HasPrefix(parts[0], "/") {
+				continue
+			}
+			mount := localenv.VolumeMount{HostPath: parts[0], ContainerPath: parts[1]}
+			if _, ok := mountNodes[mount]; !ok {
+				mounts = append(mounts, mount)
+			}
+			mountNodes[mount] = append(mountNodes[mount], node.Name)
+		}
+
+		for containerPort, bindings := range node.PortMappings {
+			port, protocol, _ := strings.Cut(containerPort, "/")
+			containerPortNumber, err := strconv.Atoi(port)
+			if err != nil || containerPortNumber == 6443 {
+				// The API server port is assigned by k3d
+				continue
+			}
+			for _, binding := range bindings {
+				hostPort, err := strconv.Atoi(binding.HostPort)
+				if err != nil {
+					continue
+				}
+				config.Ports = append(config.Ports, localenv.PortMapping{
+					HostPort:      hostPort,
+					ContainerPort: containerPortNumber,
+					Protocol:      strings.ToUpper(protocol),
+					NodeFilter:    nodeFilter(node.Role, node.Name),
+				})
+			}
+		}
+
+		if node.Role == "server" && len(config.Options.K3sArgs) == 0 {
+			config.Options.K3sArgs = node.Args
+		}
+	}
+
+	for _, mount := range mounts {
+		byRole := map[string][]string{}
+		for _, name := range mountNodes[mount] {
+			role := nodeRole(name)
+			byRole[role] = append(byRole[role], name)
+		}
+		roles := make([]string, 0, len(byRole))
+		for role := range byRole {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+		for _, role := range roles {
+			if len(byRole[role]) == roleCounts[role] {
+				mount.NodeFilter = role + ":*"
+				config.VolumeMounts = append(config.VolumeMounts, mount)
+				continue
+			}
+			for _, name := range byRole[role] {
+				mount.NodeFilter = nodeFilter(role, name)
+				config.VolumeMounts = append(config.VolumeMounts, mount)
+			}
+		}
+	}
+	sort.Slice(config.Ports, func(i, j int) bool { return config.Ports[i].HostPort < config.Ports[j].HostPort })
+
+	return &config
+}
+
+// nodeRole returns the role of a k3d node from its name
+// (e.g. "k3d-dev-agent-1" -> "agent")
+func nodeRole(name string) string {
+	for _, role := range []string{"server", "agent"} {
+		if strings.Contains(name, "-"+role+"-") {
+			return role
+		}
+	}
+	return "loadbalancer"
+}
+
+// nodeFilter returns the k3d node filter selecting a single node
+// (e.g. "k3d-dev-agent-1" -> "agent:1")
+func nodeFilter(role, name string) string {
+	if role == "loadbalancer" {
+		return role
+	}
+	return fmt.Sprintf("%s:%s", role, name[strings.LastIndex(name, "-")+1:])
+}
+
+// CloneClusterConfig returns a copy of a cluster configuration for a new
+// cluster. The registry is renamed after the target, and host ports that
+// are not free (according to portFree) are moved to the next free port.
+func CloneClusterConfig(source *localenv.ClusterConfig, target string, portFree func(port int) bool) *localenv.ClusterConfig {
+	clone := *source
+	clone.Name = target
+
+	used := map[int]bool{}
+	nextPort := func(port int) int {
+		for used[port] || !portFree(port) {
+			port++
+		}
+		used[port] = true
+		return port
+	}
+
+	clone.Nodes = append([]localenv.NodeConfig(nil), source.Nodes...)
+	clone.VolumeMounts = append([]localenv.VolumeMount(nil), source.VolumeMounts...)
+	clone.Options.K3sArgs = append([]string(nil), source.Options.K3sArgs...)
+
+	clone.Ports = make([]localenv.PortMapping, len(source.Ports))
+	for i, port := range source.Ports {
+		port.HostPort = nextPort(port.HostPort)
+		clone.Ports[i] = port
+	}
+
+	if source.Registry != nil {
+		registry := *source.Registry
+		registry.Name = fmt.Sprintf("%s-registry.localhost", target)
+		registry.HostPort = nextPort(registry.HostPort)
+		clone.Registry = &registry
+	}
+
+	return &clone
+}
+
+// isHostPortFree reports whether a TCP port can be bound on the host
+func isHostPortFree(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	_ = listener.Close()
+	return true
+}
+
+// CopyState copies the c8s CRDs, then the PipelineConfigs and PipelineRuns,
+// from one cluster to another
+func CopyState(ctx context.Context, from, to dynamic.Interface) (*StateResult, error) {
+	crds, err := from.Resource(crdResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	var copied []string
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if group, _, _ := unstructured.NestedString(crd.Object, "spec", "group"); group != c8sv1alpha1.GroupVersion.Group {
+			continue
+		}
+		StripServerFields(crd)
+		if err := applyObject(ctx, to.Resource(crdResource), crd); err != nil {
+			return nil, fmt.Errorf("failed to copy CRD %s: %w", crd.GetName(), err)
+		}
+		copied = append(copied, crd.GetName())
+	}
+	if err := waitForCRDsEstablished(ctx, to, copied); err != nil {
+		return nil, err
+	}
+
+	data, _, err := BackupResources(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	return RestoreResources(ctx, to, data)
+}
+
+// waitForCRDsEstablished waits until the API server serves the given CRDs
+func waitForCRDsEstablished(ctx context.Context, client dynamic.Interface, names []string) error {
+	deadline := time.Now().Add(crdEstablishTimeout)
+	for _, name := range names {
+		for !crdEstablished(ctx, client, name) {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("%w waiting for CRD %s to be established", types.ErrTimeoutExceeded, name)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	}
+	return nil
+}
+
+// crdEstablished reports whether a CRD has the Established condition
+func crdEstablished(ctx context.Context, client dynamic.Interface, name string) bool {
+	crd, err := client.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == "Established" && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
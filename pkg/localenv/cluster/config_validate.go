@@ -0,0 +1,334 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/org/c8s/pkg/localenv"
+)
+
+// Config issue severities
+const (
+	ConfigSeverityError   = "error"
+	ConfigSeverityWarning = "warning"
+)
+
+const (
+	// DefaultK3sTagsURL is the Docker Hub API listing the tags of the k3s image
+	DefaultK3sTagsURL = "https://hub.docker.com/v2/repositories/rancher/k3s/tags/"
+
+	// MaxServerNodes is the largest number of server nodes of a config
+	MaxServerNodes = 7
+
+	// MaxRecommendedNodes is the node count above which a local cluster is
+	// likely to exhaust the resources of a workstation
+	MaxRecommendedNodes = 10
+
+	// imageCheckTimeout bounds the request checking the k3s image
+	imageCheckTimeout = 10 * time.Second
+)
+
+var (
+	// configVersionPattern matches the Kubernetes versions K3sImage accepts
+	configVersionPattern = regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
+
+	// fixableVersionPattern matches versions ValidateConfig can normalise:
+	// a missing "v" prefix or a k3s suffix K3sImage appends itself
+	fixableVersionPattern = regexp.MustCompile(`^v?(\d+\.\d+\.\d+)([-+]k3s\d+)?$`)
+)
+
+// ConfigIssue is a problem found in a cluster config. Fixed issues were
+// corrected in the config by ValidateConfig.
+type ConfigIssue struct {
+	Field    string `json:"field"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Fixed    bool   `json:"fixed,omitempty"`
+}
+
+// ConfigValidateOptions holds options for validating a cluster config
+type ConfigValidateOptions struct {
+	// Fix corrects the issues that have an unambiguous fix in the config
+	Fix bool
+
+	// SkipImageCheck skips checking that the k3s image of the version exists
+	SkipImageCheck bool
+
+	// TagsURL is the registry API the image tag is looked up in (default DefaultK3sTagsURL)
+	TagsURL    string
+	HTTPClient *http.Client
+}
+
+// ConfigValidationReport is the result of validating a cluster config
+type ConfigValidationReport struct {
+	Valid  bool          `json:"valid"`
+	Issues []ConfigIssue `json:"issues"`
+
+	// Image is the k3s image of the Kubernetes version; ImageAvailable is
+	// unset when the image wasn't checked
+	Image          string `json:"image,omitempty"`
+	ImageAvailable *bool  `json:"imageAvailable,omitempty"`
+}
+
+// Errors returns the number of unfixed issues of error severity
+func (r *ConfigValidationReport) Errors() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == ConfigSeverityError && !issue.Fixed {
+			count++
+		}
+	}
+	return count
+}
+
+// ValidateConfig checks every field of a cluster config and, unless
+// SkipImageCheck is set, that the k3s image of its Kubernetes version is
+// published. With Fix, common issues are corrected in config first.
+func ValidateConfig(ctx context.Context, config *localenv.ClusterConfig, opts ConfigValidateOptions) *ConfigValidationReport {
+	report := &ConfigValidationReport{Issues: []ConfigIssue{}}
+	if opts.Fix {
+		report.Issues = append(report.Issues, FixClusterConfig(config)...)
+	}
+	issues := ValidateClusterConfigFields(config)
+	report.Issues = append(report.Issues, issues...)
+
+	if configVersionPattern.MatchString(config.KubernetesVersion) {
+		report.Image = K3sImage(config.KubernetesVersion)
+		if !opts.SkipImageCheck {
+			available, err := CheckK3sImage(ctx, opts.HTTPClient, opts.TagsURL, config.KubernetesVersion)
+			switch {
+			case err != nil:
+				report.Issues = append(report.Issues, ConfigIssue{
+					Field:    "kubernetesVersion",
+					Severity: ConfigSeverityWarning,
+					Message:  fmt.Sprintf("could not check image %s: %v", report.Image, err),
+				})
+			case !available:
+				report.ImageAvailable = &available
+				report.Issues = append(report.Issues, ConfigIssue{
+					Field:    "kubernetesVersion",
+					Severity: ConfigSeverityError,
+					Message:  fmt.Sprintf("image %s does not exist", report.Image),
+				})
+			default:
+				report.ImageAvailable = &available
+			}
+		}
+	}
+
+	report.Valid = report.Errors() == 0
+	return report
+}
+
+// FixClusterConfig corrects the issues of a config with an unambiguous fix
+// and returns them: Kubernetes versions without the "v" prefix or with a
+// k3s suffix, upper case cluster and registry names, and lower case port
+// protocols
+func FixClusterConfig(config *localenv.ClusterConfig) []ConfigIssue {
+	var fixed []ConfigIssue
+	fix := func(field, from, to string) {
+		fixed = append(fixed, ConfigIssue{
+			Field:    field,
+			Severity: ConfigSeverityError,
+			Message:  fmt.Sprintf("changed %q to %q", from, to),
+			Fixed:    true,
+		})
+	}
+
+	if name := strings.ToLower(strings.TrimSpace(config.Name)); name != config.Name && localenv.IsValidClusterName(name) {
+		fix("name", config.Name, name)
+		config.Name = name
+	}
+
+	if m := fixableVersionPattern.FindStringSubmatch(strings.TrimSpace(config.KubernetesVersion)); m != nil {
+		if version := "v" + m[1]; version != config.KubernetesVersion {
+			fix("kubernetesVersion", config.KubernetesVersion, version)
+			config.KubernetesVersion = version
+		}
+	}
+
+	for i := range config.Ports {
+		port := &config.Ports[i]
+		if protocol := strings.ToUpper(port.Protocol); protocol != port.Protocol && (protocol == "TCP" || protocol == "UDP") {
+			fix(fmt.Sprintf("ports[%d].protocol", i), port.Protocol, protocol)
+			port.Protocol = protocol
+		}
+	}
+
+	if config.Registry != nil {
+		if name := strings.ToLower(strings.TrimSpace(config.Registry.Name)); name != config.Registry.Name && len(validation.IsDNS1123Subdomain(name)) == 0 {
+			fix("registry.name", config.Registry.Name, name)
+			config.Registry.Name = name
+		}
+	}
+
+	return fixed
+}
+
+// ValidateClusterConfigFields returns every issue of the fields of a config,
+// where localenv.ValidateClusterConfig stops at the first failing check
+func ValidateClusterConfigFields(config *localenv.ClusterConfig) []ConfigIssue {
+	var issues []ConfigIssue
+	add := func(field, severity, format string, args ...interface{}) {
+		issues = append(issues, ConfigIssue{Field: field, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Name
+	switch {
+	case config.Name == "":
+		add("name", ConfigSeverityError, "name is required")
+	case !localenv.IsValidClusterName(config.Name):
+		add("name", ConfigSeverityError, "%q must contain only lowercase letters, numbers, and hyphens", config.Name)
+	}
+
+	// Kubernetes version
+	switch {
+	case config.KubernetesVersion == "":
+		add("kubernetesVersion", ConfigSeverityError, "kubernetesVersion is required")
+	case !configVersionPattern.MatchString(config.KubernetesVersion):
+		hint := ""
+		if fixableVersionPattern.MatchString(config.KubernetesVersion) {
+			hint = " (fixable with --fix)"
+		}
+		add("kubernetesVersion", ConfigSeverityError, "%q must be a Kubernetes version like v1.28.15%s", config.KubernetesVersion, hint)
+	}
+
+	// Nodes
+	counts := make(map[string]int)
+	seen := make(map[string]bool)
+	for i, node := range config.Nodes {
+		field := fmt.Sprintf("nodes[%d]", i)
+		switch {
+		case node.Type != NodeRoleServer && node.Type != NodeRoleAgent:
+			add(field+".type", ConfigSeverityError, "type %q must be one of: %s", node.Type, strings.Join(NodeRoles, ", "))
+			continue
+		case seen[node.Type]:
+			add(field+".type", ConfigSeverityError, "duplicate %s entry; only the last one is used", node.Type)
+		}
+		seen[node.Type] = true
+		if node.Count < 0 {
+			add(field+".count", ConfigSeverityError, "count must be at least 0")
+			continue
+		}
+		counts[node.Type] = node.Count
+	}
+	servers, agents := counts[NodeRoleServer], counts[NodeRoleAgent]
+	switch {
+	case servers < 1:
+		add("nodes", ConfigSeverityError, "cluster must have at least 1 server node")
+	case servers > MaxServerNodes:
+		add("nodes", ConfigSeverityError, "cluster can have at most %d server nodes, got %d", MaxServerNodes, servers)
+	case servers > 1 && servers%2 == 0:
+		add("nodes", ConfigSeverityWarning, "an even number of servers (%d) tolerates no more failures than %d", servers, servers-1)
+	}
+	if total := servers + agents; total > MaxRecommendedNodes {
+		add("nodes", ConfigSeverityWarning, "%d nodes may exhaust local resources (recommended at most %d)", total, MaxRecommendedNodes)
+	}
+
+	// Ports
+	hostPorts := make(map[int]string)
+	for i, port := range config.Ports {
+		field := fmt.Sprintf("ports[%d]", i)
+		if port.HostPort < 1024 || port.HostPort > 65535 {
+			add(field+".hostPort", ConfigSeverityError, "host port %d must be between 1024 and 65535", port.HostPort)
+		} else if other, ok := hostPorts[port.HostPort]; ok {
+			add(field+".hostPort", ConfigSeverityError, "host port %d is already used by %s", port.HostPort, other)
+		} else {
+			hostPorts[port.HostPort] = field
+		}
+		if port.ContainerPort < 1 || port.ContainerPort > 65535 {
+			add(field+".containerPort", ConfigSeverityError, "container port %d must be between 1 and 65535", port.ContainerPort)
+		}
+		if port.Protocol != "" && port.Protocol != "TCP" && port.Protocol != "UDP" {
+			add(field+".protocol", ConfigSeverityError, "protocol %q must be TCP or UDP", port.Protocol)
+		}
+		if !localenv.IsValidNodeFilter(port.NodeFilter) {
+			add(field+".nodeFilter", ConfigSeverityError, "node filter %q must match (loadbalancer|server|agent)(:<number>|:*)", port.NodeFilter)
+		}
+	}
+
+	// Registry
+	if registry := config.Registry; registry != nil && registry.Enabled {
+		switch {
+		case registry.Name == "":
+			add("registry.name", ConfigSeverityError, "name is required when the registry is enabled")
+		default:
+			for _, msg := range validation.IsDNS1123Subdomain(registry.Name) {
+				add("registry.name", ConfigSeverityError, "%q is not a valid DNS name: %s", registry.Name, msg)
+			}
+		}
+		if registry.HostPort < 1024 || registry.HostPort > 65535 {
+			add("registry.hostPort", ConfigSeverityError, "host port %d must be between 1024 and 65535", registry.HostPort)
+		} else if other, ok := hostPorts[registry.HostPort]; ok {
+			add("registry.hostPort", ConfigSeverityError, "host port %d is already used by %s", registry.HostPort, other)
+		}
+		if registry.ProxyRemote != "" {
+			if u, err := url.Parse(registry.ProxyRemote); err != nil || u.Scheme == "" || u.Host == "" {
+				add("registry.proxyRemote", ConfigSeverityError, "%q must be a valid URL", registry.ProxyRemote)
+			}
+		}
+	}
+
+	// Volume mounts
+	for i, volume := range config.VolumeMounts {
+		field := fmt.Sprintf("volumeMounts[%d]", i)
+		if !strings.HasPrefix(volume.HostPath, "/") {
+			add(field+".hostPath", ConfigSeverityError, "host path %q must be absolute", volume.HostPath)
+		}
+		if !strings.HasPrefix(volume.ContainerPath, "/") {
+			add(field+".containerPath", ConfigSeverityError, "container path %q must be absolute", volume.ContainerPath)
+		}
+		if !localenv.IsValidNodeFilter(volume.NodeFilter) {
+			add(field+".nodeFilter", ConfigSeverityError, "node filter %q must match (loadbalancer|server|agent)(:<number>|:*)", volume.NodeFilter)
+		}
+	}
+
+	// Options
+	if _, err := localenv.ParseDuration(config.Options.WaitTimeout); err != nil {
+		add("options.waitTimeout", ConfigSeverityError, "%q must be a valid duration (e.g., 60s, 5m)", config.Options.WaitTimeout)
+	}
+
+	return issues
+}
+
+// CheckK3sImage reports whether the k3s image of a Kubernetes version is
+// published, by looking its tag up in the registry API at tagsURL (default
+// DefaultK3sTagsURL)
+func CheckK3sImage(ctx context.Context, client *http.Client, tagsURL, kubernetesVersion string) (bool, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if tagsURL == "" {
+		tagsURL = DefaultK3sTagsURL
+	}
+	_, tag, _ := strings.Cut(K3sImage(kubernetesVersion), ":")
+
+	ctx, cancel := context.WithTimeout(ctx, imageCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(tagsURL, "/")+"/"+url.PathEscape(tag), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("registry returned %s", resp.Status)
+	}
+}
@@ -0,0 +1,212 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// AnnotationCopiedFrom records the "namespace/name" of the Secret a Secret
+// was copied from by CopySecret
+const AnnotationCopiedFrom = "c8s.dev/copied-from"
+
+var secretResource = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// Secret copy actions
+const (
+	SecretCreated       = "created"
+	SecretUpdated       = "updated"
+	SecretUnchanged     = "unchanged"
+	SecretSourceDeleted = "source deleted"
+)
+
+// CopySecretOptions holds options for copying a Secret between namespaces
+type CopySecretOptions struct {
+	Cluster       string
+	Name          string
+	FromNamespace string
+	ToNamespace   string
+}
+
+// CopySecretResult describes a Secret copied to the target namespace
+type CopySecretResult struct {
+	Name          string `json:"name"`
+	FromNamespace string `json:"fromNamespace"`
+	ToNamespace   string `json:"toNamespace"`
+	Action        string `json:"action"`
+
+	// Conflict is set when the target namespace had a Secret of the same
+	// name that was not copied from the source, which the copy replaced
+	Conflict bool `json:"conflict,omitempty"`
+}
+
+// CopySecret copies a Secret of a cluster to another namespace, creating the
+// namespace if needed. Server-side metadata and owner references are
+// removed, and the copy is annotated with AnnotationCopiedFrom.
+func CopySecret(ctx context.Context, opts CopySecretOptions) (*CopySecretResult, error) {
+	if err := validateCopySecretOptions(opts); err != nil {
+		return nil, err
+	}
+	client, err := pauseClient(ctx, opts.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	return CopySecretResource(ctx, client, opts)
+}
+
+// CopySecretResource copies the Secret selected by opts through client
+func CopySecretResource(ctx context.Context, client dynamic.Interface, opts CopySecretOptions) (*CopySecretResult, error) {
+	if err := validateCopySecretOptions(opts); err != nil {
+		return nil, err
+	}
+	source, err := client.Resource(secretResource).Namespace(opts.FromNamespace).Get(ctx, opts.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", opts.FromNamespace, opts.Name, err)
+	}
+	if err := ensureNamespace(ctx, client, opts.ToNamespace); err != nil {
+		return nil, err
+	}
+	return copySecretObject(ctx, client, source, opts.ToNamespace)
+}
+
+// WatchSecret copies a Secret every time it changes in its namespace until
+// ctx is cancelled, calling onCopy after each copy attempt
+func WatchSecret(ctx context.Context, opts CopySecretOptions, onCopy func(*CopySecretResult, error)) error {
+	if err := validateCopySecretOptions(opts); err != nil {
+		return err
+	}
+	client, err := pauseClient(ctx, opts.Cluster)
+	if err != nil {
+		return err
+	}
+	return WatchSecretResource(ctx, client, opts, onCopy)
+}
+
+// WatchSecretResource runs an informer on the Secret selected by opts and
+// copies it on every addition or change. A deleted source Secret is
+// reported with SecretSourceDeleted; its copy is left in place.
+func WatchSecretResource(ctx context.Context, client dynamic.Interface, opts CopySecretOptions, onCopy func(*CopySecretResult, error)) error {
+	if err := validateCopySecretOptions(opts); err != nil {
+		return err
+	}
+	if err := ensureNamespace(ctx, client, opts.ToNamespace); err != nil {
+		return err
+	}
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, secretResource, opts.FromNamespace, 0, cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", opts.Name).String()
+		})
+
+	copySource := func(obj interface{}) {
+		source, ok := obj.(*unstructured.Unstructured)
+		if !ok || source.GetName() != opts.Name {
+			return
+		}
+		onCopy(copySecretObject(ctx, client, source, opts.ToNamespace))
+	}
+	_, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: copySource,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSource, ok := oldObj.(*unstructured.Unstructured)
+			if ok && oldSource.GetResourceVersion() == newObj.(*unstructured.Unstructured).GetResourceVersion() {
+				return
+			}
+			copySource(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			onCopy(&CopySecretResult{
+				Name:          opts.Name,
+				FromNamespace: opts.FromNamespace,
+				ToNamespace:   opts.ToNamespace,
+				Action:        SecretSourceDeleted,
+			}, nil)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch secret %s/%s: %w", opts.FromNamespace, opts.Name, err)
+	}
+
+	informer.Informer().Run(ctx.Done())
+	return nil
+}
+
+// copySecretObject creates or updates the copy of source in namespace.
+// An existing copy of the same source with the same content is left as is.
+func copySecretObject(ctx context.Context, client dynamic.Interface, source *unstructured.Unstructured, namespace string) (*CopySecretResult, error) {
+	copiedFrom := fmt.Sprintf("%s/%s", source.GetNamespace(), source.GetName())
+	result := &CopySecretResult{
+		Name:          source.GetName(),
+		FromNamespace: source.GetNamespace(),
+		ToNamespace:   namespace,
+	}
+
+	secret := MigrationObject(source, namespace)
+	annotations := secret.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationCopiedFrom] = copiedFrom
+	secret.SetAnnotations(annotations)
+
+	resource := client.Resource(secretResource).Namespace(namespace)
+	existing, err := resource.Get(ctx, secret.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err := resource.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create secret %s/%s: %w", namespace, secret.GetName(), err)
+		}
+		result.Action = SecretCreated
+		return result, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, secret.GetName(), err)
+	}
+
+	result.Conflict = existing.GetAnnotations()[AnnotationCopiedFrom] != copiedFrom
+	if !result.Conflict && sameSecretContent(existing, secret) {
+		result.Action = SecretUnchanged
+		return result, nil
+	}
+
+	secret.SetResourceVersion(existing.GetResourceVersion())
+	if _, err := resource.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to update secret %s/%s: %w", namespace, secret.GetName(), err)
+	}
+	result.Action = SecretUpdated
+	return result, nil
+}
+
+// sameSecretContent reports whether two Secrets have the same type, data,
+// labels and annotations
+func sameSecretContent(a, b *unstructured.Unstructured) bool {
+	for _, field := range []string{"type", "data", "stringData"} {
+		if !reflect.DeepEqual(a.Object[field], b.Object[field]) {
+			return false
+		}
+	}
+	return reflect.DeepEqual(a.GetLabels(), b.GetLabels()) && reflect.DeepEqual(a.GetAnnotations(), b.GetAnnotations())
+}
+
+// validateCopySecretOptions checks a Secret is named and copied to another
+// namespace
+func validateCopySecretOptions(opts CopySecretOptions) error {
+	if opts.Name == "" {
+		return fmt.Errorf("secret name is required")
+	}
+	if opts.ToNamespace == "" {
+		return fmt.Errorf("target namespace is required")
+	}
+	if opts.FromNamespace == opts.ToNamespace {
+		return fmt.Errorf("source and target are both namespace %s", opts.FromNamespace)
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/yaml"
+
+	"github.com/org/c8s/config/crd"
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// validatedResources are the resources whose objects ValidateCRDObjects
+// checks against the installed CRD schemas
+var validatedResources = []string{"pipelineconfigs", "pipelineruns"}
+
+// CRDInfo describes a c8s CRD
+type CRDInfo struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Versions   []string `json:"versions"`
+	SchemaHash string   `json:"schemaHash"`
+}
+
+// CRDViolation is an object that does not conform to its CRD schema
+type CRDViolation struct {
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Errors    []string `json:"errors"`
+}
+
+// CRDValidationResult is the result of checking the c8s objects of a
+// cluster against the installed CRD schemas
+type CRDValidationResult struct {
+	Checked    int            `json:"checked"`
+	Violations []CRDViolation `json:"violations,omitempty"`
+}
+
+// Valid reports whether every checked object conforms to its schema
+func (r *CRDValidationResult) Valid() bool {
+	return len(r.Violations) == 0
+}
+
+// CRDUpgrade records the schema change of a CRD applied by UpgradeCRDs
+type CRDUpgrade struct {
+	Name         string `json:"name"`
+	PreviousHash string `json:"previousHash,omitempty"`
+	SchemaHash   string `json:"schemaHash"`
+}
+
+// Changed reports whether the schema of the CRD was installed or modified
+func (u CRDUpgrade) Changed() bool {
+	return u.PreviousHash != u.SchemaHash
+}
+
+// CRDUpgradeResult describes the CRDs applied by UpgradeCRDs and the
+// validation of existing objects against them
+type CRDUpgradeResult struct {
+	CRDs       []CRDUpgrade         `json:"crds"`
+	Validation *CRDValidationResult `json:"validation"`
+}
+
+// EmbeddedCRDs returns the CRD manifests built into the binary
+func EmbeddedCRDs() ([]*unstructured.Unstructured, error) {
+	files, err := fs.Glob(crd.Bases, "bases/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	crds := make([]*unstructured.Unstructured, 0, len(files))
+	for _, file := range files {
+		data, err := crd.Bases.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		crds = append(crds, obj)
+	}
+	return crds, nil
+}
+
+// InstallCRDs applies the embedded CRDs to a cluster and waits until they
+// are served, returning their names
+func InstallCRDs(ctx context.Context, clusterName string) ([]string, error) {
+	client, err := pauseClient(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyCRDs(ctx, client)
+}
+
+// ApplyCRDs creates or updates the embedded CRDs through client and waits
+// until they are established
+func ApplyCRDs(ctx context.Context, client dynamic.Interface) ([]string, error) {
+	crds, err := EmbeddedCRDs()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(crds))
+	for _, obj := range crds {
+		if err := applyObject(ctx, client.Resource(crdResource), obj); err != nil {
+			return names, fmt.Errorf("failed to apply CRD %s: %w", obj.GetName(), err)
+		}
+		names = append(names, obj.GetName())
+	}
+	return names, waitForCRDsEstablished(ctx, client, names)
+}
+
+// ListCRDs returns the c8s CRDs installed in a cluster
+func ListCRDs(ctx context.Context, clusterName string) ([]CRDInfo, error) {
+	client, err := pauseClient(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return InstalledCRDs(ctx, client)
+}
+
+// InstalledCRDs returns the CRDs of the c8s.dev group read through client,
+// sorted by name
+func InstalledCRDs(ctx context.Context, client dynamic.Interface) ([]CRDInfo, error) {
+	list, err := client.Resource(crdResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	var infos []CRDInfo
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if group, _, _ := unstructured.NestedString(obj.Object, "spec", "group"); group != c8sv1alpha1.GroupVersion.Group {
+			continue
+		}
+		info, err := NewCRDInfo(obj)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// NewCRDInfo describes a CRD object
+func NewCRDInfo(obj *unstructured.Unstructured) (CRDInfo, error) {
+	info := CRDInfo{Name: obj.GetName()}
+	info.Kind, _, _ = unstructured.NestedString(obj.Object, "spec", "names", "kind")
+
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+	for _, v := range versions {
+		if version, ok := v.(map[string]interface{}); ok {
+			if name, ok := version["name"].(string); ok {
+				info.Versions = append(info.Versions, name)
+			}
+		}
+	}
+
+	hash, err := CRDSchemaHash(obj)
+	if err != nil {
+		return info, err
+	}
+	info.SchemaHash = hash
+	return info, nil
+}
+
+// CRDSchemaHash returns a short SHA-256 hash of the OpenAPI schemas of all
+// versions of a CRD, which changes whenever the schema does
+func CRDSchemaHash(obj *unstructured.Unstructured) (string, error) {
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+
+	schemas := make(map[string]interface{}, len(versions))
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := version["name"].(string)
+		schemas[name], _, _ = unstructured.NestedFieldNoCopy(version, "schema", "openAPIV3Schema")
+	}
+
+	// Maps are marshaled with sorted keys, so equal schemas hash the same
+	data, err := json.Marshal(schemas)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash schema of %s: %w", obj.GetName(), err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+// ValidateCRDs checks the PipelineConfigs and PipelineRuns of a cluster
+// against the installed CRD schemas
+func ValidateCRDs(ctx context.Context, clusterName string) (*CRDValidationResult, error) {
+	client, err := pauseClient(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateCRDObjects(ctx, client)
+}
+
+// ValidateCRDObjects checks every object of validatedResources read through
+// client against the schema of its version in the installed CRD
+func ValidateCRDObjects(ctx context.Context, client dynamic.Interface) (*CRDValidationResult, error) {
+	result := &CRDValidationResult{}
+	for _, resource := range validatedResources {
+		crdName := resource + "." + c8sv1alpha1.GroupVersion.Group
+		obj, err := client.Resource(crdResource).Get(ctx, crdName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CRD %s: %w", crdName, err)
+		}
+
+		list, err := client.Resource(c8sv1alpha1.GroupVersion.WithResource(resource)).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", resource, err)
+		}
+
+		violations, err := ValidateAgainstCRD(obj, list.Items)
+		if err != nil {
+			return nil, err
+		}
+		result.Checked += len(list.Items)
+		result.Violations = append(result.Violations, violations...)
+	}
+	return result, nil
+}
+
+// ValidateAgainstCRD returns the objects that do not conform to the schema
+// of their version in a CRD
+func ValidateAgainstCRD(obj *unstructured.Unstructured, objects []unstructured.Unstructured) ([]CRDViolation, error) {
+	group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+
+	schemas := make(map[string]*spec.Schema, len(versions))
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := version["name"].(string)
+		openAPISchema, found, _ := unstructured.NestedFieldNoCopy(version, "schema", "openAPIV3Schema")
+		if !found {
+			continue
+		}
+
+		// The CRD schema is an OpenAPI v3 schema with Kubernetes extensions,
+		// which the validator ignores
+		data, err := json.Marshal(openAPISchema)
+		if err != nil {
+			return nil, err
+		}
+		schema := &spec.Schema{}
+		if err := json.Unmarshal(data, schema); err != nil {
+			return nil, fmt.Errorf("invalid schema of %s %s: %w", obj.GetName(), name, err)
+		}
+		schemas[group+"/"+name] = schema
+	}
+
+	var violations []CRDViolation
+	for i := range objects {
+		object := &objects[i]
+		violation := CRDViolation{Kind: object.GetKind(), Namespace: object.GetNamespace(), Name: object.GetName()}
+
+		schema, ok := schemas[object.GetAPIVersion()]
+		if !ok {
+			violation.Errors = []string{fmt.Sprintf("version %s is not served by %s", object.GetAPIVersion(), obj.GetName())}
+			violations = append(violations, violation)
+			continue
+		}
+		result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(object.Object)
+		for _, err := range result.Errors {
+			violation.Errors = append(violation.Errors, err.Error())
+		}
+		if len(violation.Errors) > 0 {
+			sort.Strings(violation.Errors)
+			violations = append(violations, violation)
+		}
+	}
+	return violations, nil
+}
+
+// UpgradeCRDs applies the embedded CRDs to a cluster, then checks the
+// existing PipelineConfigs and PipelineRuns against the new schemas
+func UpgradeCRDs(ctx context.Context, clusterName string) (*CRDUpgradeResult, error) {
+	client, err := pauseClient(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := InstalledCRDs(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[string]string, len(installed))
+	for _, info := range installed {
+		previous[info.Name] = info.SchemaHash
+	}
+
+	if _, err := ApplyCRDs(ctx, client); err != nil {
+		return nil, err
+	}
+	upgraded, err := InstalledCRDs(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CRDUpgradeResult{}
+	for _, info := range upgraded {
+		result.CRDs = append(result.CRDs, CRDUpgrade{Name: info.Name, PreviousHash: previous[info.Name], SchemaHash: info.SchemaHash})
+	}
+	result.Validation, err = ValidateCRDObjects(ctx, client)
+	return result, err
+}
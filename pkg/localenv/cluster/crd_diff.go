@@ -0,0 +1,344 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// Schema change kinds
+const (
+	SchemaFieldAdded   = "added"
+	SchemaFieldRemoved = "removed"
+	SchemaFieldChanged = "changed"
+)
+
+// CRDSchemaChange is a difference between the installed and the bundled
+// schema of a CRD version
+type CRDSchemaChange struct {
+	Version string `json:"version"`
+
+	// Path is the field path in the object, with "[]" for array items and
+	// "{}" for map values; it is empty for a whole version
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	Detail string `json:"detail,omitempty"`
+
+	// Breaking is set for changes that existing objects may no longer
+	// conform to: removed fields, type changes and tighter constraints
+	Breaking bool `json:"breaking"`
+}
+
+// CRDDiff is the schema diff of a bundled CRD against the installed one
+type CRDDiff struct {
+	Name string `json:"name"`
+
+	// Installed is false when the CRD isn't installed in the cluster yet
+	Installed bool              `json:"installed"`
+	Changes   []CRDSchemaChange `json:"changes,omitempty"`
+}
+
+// Breaking reports whether any change of the CRD is breaking
+func (d CRDDiff) Breaking() bool {
+	for _, change := range d.Changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffCRDs compares the CRDs built into the binary with those installed in
+// a cluster
+func DiffCRDs(ctx context.Context, clusterName string) ([]CRDDiff, error) {
+	client, err := pauseClient(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return DiffCRDObjects(ctx, client)
+}
+
+// DiffCRDObjects compares each embedded CRD with the CRD of the same name
+// read through client
+func DiffCRDObjects(ctx context.Context, client dynamic.Interface) ([]CRDDiff, error) {
+	crds, err := EmbeddedCRDs()
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]CRDDiff, 0, len(crds))
+	for _, bundled := range crds {
+		installed, err := client.Resource(crdResource).Get(ctx, bundled.GetName(), metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			diffs = append(diffs, CRDDiff{Name: bundled.GetName()})
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("failed to get CRD %s: %w", bundled.GetName(), err)
+		}
+		diffs = append(diffs, CRDDiff{
+			Name:      bundled.GetName(),
+			Installed: true,
+			Changes:   DiffCRDSchemas(installed, bundled),
+		})
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs, nil
+}
+
+// DiffCRDSchemas returns the changes of the OpenAPI schemas of every version
+// from the installed to the bundled CRD, sorted by version and path
+func DiffCRDSchemas(installed, bundled *unstructured.Unstructured) []CRDSchemaChange {
+	from, to := crdVersionSchemas(installed), crdVersionSchemas(bundled)
+
+	var changes []CRDSchemaChange
+	for version, schema := range from {
+		if _, ok := to[version]; !ok {
+			changes = append(changes, CRDSchemaChange{Version: version, Change: SchemaFieldRemoved, Detail: "version no longer served", Breaking: true})
+			continue
+		}
+		diff := &schemaDiff{version: version}
+		diff.compare("", schema, to[version])
+		changes = append(changes, diff.changes...)
+	}
+	for version := range to {
+		if _, ok := from[version]; !ok {
+			changes = append(changes, CRDSchemaChange{Version: version, Change: SchemaFieldAdded, Detail: "new version"})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Version != changes[j].Version {
+			return changes[i].Version < changes[j].Version
+		}
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// crdVersionSchemas returns the OpenAPI schema of each served version of a CRD
+func crdVersionSchemas(obj *unstructured.Unstructured) map[string]map[string]interface{} {
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+
+	schemas := make(map[string]map[string]interface{}, len(versions))
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if served, ok := version["served"].(bool); ok && !served {
+			continue
+		}
+		name, _ := version["name"].(string)
+		schema, _, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+		schemas[name] = schema
+	}
+	return schemas
+}
+
+// schemaDiff collects the changes between two schemas of a version
+type schemaDiff struct {
+	version string
+	changes []CRDSchemaChange
+}
+
+// add records a change at path
+func (d *schemaDiff) add(path, change, detail string, breaking bool) {
+	d.changes = append(d.changes, CRDSchemaChange{
+		Version:  d.version,
+		Path:     path,
+		Change:   change,
+		Detail:   detail,
+		Breaking: breaking,
+	})
+}
+
+// compare records the changes from the schema from to the schema to of the
+// field at path, then of its properties, items and map values
+func (d *schemaDiff) compare(path string, from, to map[string]interface{}) {
+	fromType, _ := from["type"].(string)
+	toType, _ := to["type"].(string)
+	if fromType != toType {
+		d.add(path, SchemaFieldChanged, fmt.Sprintf("type %s → %s", orAny(fromType), orAny(toType)), true)
+		return
+	}
+	d.compareConstraints(path, from, to)
+
+	fromProperties, _ := from["properties"].(map[string]interface{})
+	toProperties, _ := to["properties"].(map[string]interface{})
+	fromRequired, toRequired := stringSet(from["required"]), stringSet(to["required"])
+
+	for name, fromProperty := range fromProperties {
+		fieldPath := joinFieldPath(path, name)
+		toProperty, ok := toProperties[name].(map[string]interface{})
+		if !ok {
+			d.add(fieldPath, SchemaFieldRemoved, "", true)
+			continue
+		}
+		switch {
+		case toRequired[name] && !fromRequired[name]:
+			d.add(fieldPath, SchemaFieldChanged, "now required", true)
+		case fromRequired[name] && !toRequired[name]:
+			d.add(fieldPath, SchemaFieldChanged, "no longer required", false)
+		}
+		if fromProperty, ok := fromProperty.(map[string]interface{}); ok {
+			d.compare(fieldPath, fromProperty, toProperty)
+		}
+	}
+	for name, toProperty := range toProperties {
+		if _, ok := fromProperties[name]; ok {
+			continue
+		}
+		detail := schemaType(toProperty)
+		if toRequired[name] {
+			detail += ", required"
+		}
+		d.add(joinFieldPath(path, name), SchemaFieldAdded, detail, toRequired[name])
+	}
+
+	if fromItems, ok := from["items"].(map[string]interface{}); ok {
+		if toItems, ok := to["items"].(map[string]interface{}); ok {
+			d.compare(path+"[]", fromItems, toItems)
+		}
+	}
+	if fromValues, ok := from["additionalProperties"].(map[string]interface{}); ok {
+		if toValues, ok := to["additionalProperties"].(map[string]interface{}); ok {
+			d.compare(path+"{}", fromValues, toValues)
+		}
+	}
+}
+
+// schemaBounds are the constraints that reject more values as they
+// increase (lower bounds) or decrease (upper bounds)
+var schemaBounds = []struct {
+	name  string
+	upper bool
+}{
+	{"minimum", false},
+	{"minLength", false},
+	{"minItems", false},
+	{"minProperties", false},
+	{"maximum", true},
+	{"maxLength", true},
+	{"maxItems", true},
+	{"maxProperties", true},
+}
+
+// compareConstraints records changes of the enum, format, pattern and
+// bounds of a field. Tighter constraints are breaking.
+func (d *schemaDiff) compareConstraints(path string, from, to map[string]interface{}) {
+	fromEnum, toEnum := stringSet(from["enum"]), stringSet(to["enum"])
+	if !reflect.DeepEqual(fromEnum, toEnum) {
+		var removed, added []string
+		for value := range fromEnum {
+			if !toEnum[value] {
+				removed = append(removed, value)
+			}
+		}
+		for value := range toEnum {
+			if !fromEnum[value] {
+				added = append(added, value)
+			}
+		}
+		sort.Strings(removed)
+		sort.Strings(added)
+
+		switch {
+		case len(fromEnum) == 0:
+			d.add(path, SchemaFieldChanged, "now restricted to "+strings.Join(added, ", "), true)
+		case len(toEnum) == 0:
+			d.add(path, SchemaFieldChanged, "no longer restricted to an enum", false)
+		default:
+			var details []string
+			if len(added) > 0 {
+				details = append(details, "enum adds "+strings.Join(added, ", "))
+			}
+			if len(removed) > 0 {
+				details = append(details, "enum removes "+strings.Join(removed, ", "))
+			}
+			d.add(path, SchemaFieldChanged, strings.Join(details, "; "), len(removed) > 0)
+		}
+	}
+
+	for _, constraint := range []string{"format", "pattern"} {
+		if !reflect.DeepEqual(from[constraint], to[constraint]) {
+			d.add(path, SchemaFieldChanged, fmt.Sprintf("%s %v → %v", constraint, orNone(from[constraint]), orNone(to[constraint])),
+				to[constraint] != nil)
+		}
+	}
+
+	for _, bound := range schemaBounds {
+		fromValue, fromSet := schemaNumber(from[bound.name])
+		toValue, toSet := schemaNumber(to[bound.name])
+		if fromSet == toSet && fromValue == toValue {
+			continue
+		}
+		// A new bound, or one moved to reject more values, is breaking
+		breaking := toSet && (!fromSet || (bound.upper && toValue < fromValue) || (!bound.upper && toValue > fromValue))
+		d.add(path, SchemaFieldChanged, fmt.Sprintf("%s %v → %v", bound.name, orNone(from[bound.name]), orNone(to[bound.name])), breaking)
+	}
+}
+
+// joinFieldPath appends a property name to a field path
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// schemaType returns the type of a schema, with its item type for arrays
+func schemaType(schema interface{}) string {
+	m, _ := schema.(map[string]interface{})
+	t, _ := m["type"].(string)
+	if items, ok := m["items"].(map[string]interface{}); ok && t == "array" {
+		return "array of " + schemaType(items)
+	}
+	return orAny(t)
+}
+
+// stringSet returns the string values of a schema list as a set
+func stringSet(value interface{}) map[string]bool {
+	list, _ := value.([]interface{})
+	set := make(map[string]bool, len(list))
+	for _, item := range list {
+		set[fmt.Sprint(item)] = true
+	}
+	return set
+}
+
+// schemaNumber returns a numeric schema constraint as a float
+func schemaNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// orAny returns a schema type, or "any" if it is unset
+func orAny(t string) string {
+	if t == "" {
+		return "any"
+	}
+	return t
+}
+
+// orNone returns a constraint value, or "none" if it is unset
+func orNone(value interface{}) interface{} {
+	if value == nil {
+		return "none"
+	}
+	return value
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/org/c8s/pkg/localenv"
+	"github.com/org/c8s/pkg/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -141,6 +142,15 @@ func convertToK3dConfig(config *localenv.ClusterConfig, timeout time.Duration) *
 		})
 	}
 
+	// Convert volume mounts
+	for _, volume := range config.VolumeMounts {
+		k3dConfig.Volumes = append(k3dConfig.Volumes, VolumeMapping{
+			HostPath:      volume.HostPath,
+			ContainerPath: volume.ContainerPath,
+			NodeFilter:    volume.NodeFilter,
+		})
+	}
+
 	return k3dConfig
 }
 
@@ -156,7 +166,7 @@ func waitForClusterReady(ctx context.Context, clusterName string, timeout time.D
 			return ctx.Err()
 		case <-ticker.C:
 			if time.Now().After(deadline) {
-				return fmt.Errorf("timeout waiting for cluster to be ready")
+				return fmt.Errorf("%w waiting for cluster to be ready", types.ErrTimeoutExceeded)
 			}
 
 			// Check cluster status
@@ -183,6 +193,11 @@ func (e *ClusterAlreadyExistsError) Error() string {
 	return fmt.Sprintf("cluster '%s' already exists", e.Name)
 }
 
+// Unwrap makes errors.Is match types.ErrClusterAlreadyExists
+func (e *ClusterAlreadyExistsError) Unwrap() error {
+	return types.ErrClusterAlreadyExists
+}
+
 // DockerNotAvailableError is returned when Docker is not available
 type DockerNotAvailableError struct {
 	Err error
@@ -192,6 +207,11 @@ func (e *DockerNotAvailableError) Error() string {
 	return fmt.Sprintf("Docker is not available: %v", e.Err)
 }
 
+// Unwrap makes errors.Is match types.ErrDockerNotAvailable and the cause
+func (e *DockerNotAvailableError) Unwrap() []error {
+	return []error{types.ErrDockerNotAvailable, e.Err}
+}
+
 // ClusterNotReadyError is returned when cluster is not ready within timeout
 type ClusterNotReadyError struct {
 	Name string
@@ -200,3 +220,8 @@ type ClusterNotReadyError struct {
 func (e *ClusterNotReadyError) Error() string {
 	return fmt.Sprintf("cluster '%s' is not ready", e.Name)
 }
+
+// Unwrap makes errors.Is match types.ErrTimeoutExceeded
+func (e *ClusterNotReadyError) Unwrap() error {
+	return types.ErrTimeoutExceeded
+}
@@ -1,38 +1,42 @@
 package cluster
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os/exec"
 	"strings"
+
+	"github.com/org/c8s/pkg/types"
 )
 
 // Common error types for better error handling
 
-// IsClusterNotFoundError checks if an error is a ClusterNotFoundError
+// IsClusterNotFoundError checks if an error wraps types.ErrClusterNotFound
 func IsClusterNotFoundError(err error) bool {
-	var notFoundErr *ClusterNotFoundError
-	return errors.As(err, &notFoundErr)
+	return errors.Is(err, types.ErrClusterNotFound)
 }
 
-// IsClusterAlreadyExistsError checks if an error is a ClusterAlreadyExistsError
+// IsClusterAlreadyExistsError checks if an error wraps types.ErrClusterAlreadyExists
 func IsClusterAlreadyExistsError(err error) bool {
-	var existsErr *ClusterAlreadyExistsError
-	return errors.As(err, &existsErr)
+	return errors.Is(err, types.ErrClusterAlreadyExists)
 }
 
-// IsDockerNotAvailableError checks if an error is a DockerNotAvailableError
+// IsDockerNotAvailableError checks if an error wraps types.ErrDockerNotAvailable
 func IsDockerNotAvailableError(err error) bool {
-	var dockerErr *DockerNotAvailableError
-	return errors.As(err, &dockerErr)
+	return errors.Is(err, types.ErrDockerNotAvailable)
 }
 
-// IsTimeoutError checks if an error is related to timeout
+// IsTimeoutError checks if an error wraps types.ErrTimeoutExceeded or an
+// expired context deadline
 func IsTimeoutError(err error) bool {
-	if err == nil {
-		return false
-	}
-	return strings.Contains(err.Error(), "timeout") ||
-		strings.Contains(err.Error(), "deadline exceeded")
+	return errors.Is(err, types.ErrTimeoutExceeded) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// isCommandNotFound checks if an error is a missing executable
+func isCommandNotFound(err error, name string) bool {
+	var execErr *exec.Error
+	return errors.As(err, &execErr) && execErr.Name == name && errors.Is(execErr.Err, exec.ErrNotFound)
 }
 
 // ErrorWithSuggestion wraps an error with an actionable suggestion
@@ -87,7 +91,7 @@ func EnhanceError(err error, operation string) error {
 			"Try increasing the timeout with --timeout flag, or check if resources are available")
 	}
 
-	// Port conflicts
+	// Port conflicts, reported by docker in its output
 	if strings.Contains(err.Error(), "address already in use") ||
 		strings.Contains(err.Error(), "port") && strings.Contains(err.Error(), "in use") {
 		return NewErrorWithSuggestion(err,
@@ -95,15 +99,13 @@ func EnhanceError(err error, operation string) error {
 	}
 
 	// Kubectl not found
-	if strings.Contains(err.Error(), "kubectl") &&
-		(strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "executable")) {
+	if isCommandNotFound(err, "kubectl") {
 		return NewErrorWithSuggestion(err,
 			"kubectl is not installed. Install it from: https://kubernetes.io/docs/tasks/tools/")
 	}
 
 	// k3d not found
-	if strings.Contains(err.Error(), "k3d") &&
-		(strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "executable")) {
+	if isCommandNotFound(err, "k3d") {
 		return NewErrorWithSuggestion(err,
 			"k3d is not installed. Install it from: https://k3d.io/")
 	}
@@ -0,0 +1,241 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// DefaultEventsSince is how old events may be to be included in the initial
+// sync of WatchEvents
+const DefaultEventsSince = time.Hour
+
+// EventsOptions configures WatchEvents
+type EventsOptions struct {
+	// Namespace to watch; empty watches all namespaces
+	Namespace string
+
+	// Type only shows events of this type (Normal or Warning) when set
+	Type string
+
+	// Run scopes events to a PipelineRun and its Jobs and Pods. Events of
+	// c8s objects are shown otherwise.
+	Run string
+
+	// Since excludes older events from the initial sync. Events received
+	// while watching are always shown.
+	Since time.Duration
+}
+
+// ClusterEvent is a Kubernetes event of a c8s object
+type ClusterEvent struct {
+	LastSeen  time.Time `json:"lastSeen" yaml:"lastSeen"`
+	Type      string    `json:"type" yaml:"type"`
+	Namespace string    `json:"namespace" yaml:"namespace"`
+	Kind      string    `json:"kind" yaml:"kind"`
+	Name      string    `json:"name" yaml:"name"`
+	Reason    string    `json:"reason" yaml:"reason"`
+	Message   string    `json:"message" yaml:"message"`
+	Count     int32     `json:"count" yaml:"count"`
+}
+
+// WatchEvents calls onEvent with the recent events of c8s objects, oldest
+// first, then with every new or updated event until ctx is done
+func WatchEvents(ctx context.Context, client kubernetes.Interface, opts EventsOptions, onEvent func(ClusterEvent)) error {
+	if opts.Since <= 0 {
+		opts.Since = DefaultEventsSince
+	}
+
+	events := client.CoreV1().Events(opts.Namespace)
+	listOptions := metav1.ListOptions{}
+	if opts.Run == "" {
+		// Events of a run's Jobs and Pods have other API versions, so the
+		// field selector only applies when not scoped to a run
+		listOptions.FieldSelector = fields.OneTermEqualSelector(
+			"involvedObject.apiVersion", c8sv1alpha1.GroupVersion.String()).String()
+	}
+	if opts.Type != "" {
+		selector := fields.OneTermEqualSelector("type", opts.Type)
+		if listOptions.FieldSelector != "" {
+			selector = fields.AndSelectors(fields.ParseSelectorOrDie(listOptions.FieldSelector), selector)
+		}
+		listOptions.FieldSelector = selector.String()
+	}
+
+	matcher := &eventMatcher{client: client, namespace: opts.Namespace, run: opts.Run, eventType: opts.Type}
+
+	list, err := events.List(ctx, listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	threshold := time.Now().Add(-opts.Since)
+	var initial []ClusterEvent
+	for i := range list.Items {
+		event := &list.Items[i]
+		if eventTime(event).Before(threshold) || !matcher.matches(ctx, event) {
+			continue
+		}
+		initial = append(initial, toClusterEvent(event))
+	}
+	sort.SliceStable(initial, func(i, j int) bool { return initial[i].LastSeen.Before(initial[j].LastSeen) })
+	for _, event := range initial {
+		onEvent(event)
+	}
+
+	resourceVersion := list.ResourceVersion
+	for {
+		listOptions.ResourceVersion = resourceVersion
+		watcher, err := events.Watch(ctx, listOptions)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to watch events: %w", err)
+		}
+
+		resourceVersion, err = forwardEvents(ctx, watcher, matcher, resourceVersion, onEvent)
+		watcher.Stop()
+		if err != nil || ctx.Err() != nil {
+			return err
+		}
+		// The API server closed the watch; resume from the last event seen
+	}
+}
+
+// forwardEvents passes the events of a watch to onEvent until the watch is
+// closed, returning the last resource version seen
+func forwardEvents(ctx context.Context, watcher watch.Interface, matcher *eventMatcher, resourceVersion string, onEvent func(ClusterEvent)) (string, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, nil
+		case result, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+			switch result.Type {
+			case watch.Error:
+				return resourceVersion, fmt.Errorf("event watch failed: %v", result.Object)
+			case watch.Added, watch.Modified:
+				event, ok := result.Object.(*corev1.Event)
+				if !ok {
+					continue
+				}
+				resourceVersion = event.ResourceVersion
+				if matcher.matches(ctx, event) {
+					onEvent(toClusterEvent(event))
+				}
+			}
+		}
+	}
+}
+
+// eventMatcher matches the events of c8s objects, or of a PipelineRun and
+// its child Jobs and Pods when run is set. Children are found through the
+// pipeline-run label and looked up again when an event of an unknown Job or
+// Pod arrives, since they are created as the run progresses. The field
+// selectors of WatchEvents are also checked here, so the filters hold with
+// clients that ignore field selectors.
+type eventMatcher struct {
+	client    kubernetes.Interface
+	namespace string
+	run       string
+	eventType string
+
+	children map[string]bool
+	unknown  map[string]bool
+}
+
+// matches reports whether an event should be shown
+func (m *eventMatcher) matches(ctx context.Context, event *corev1.Event) bool {
+	if m.eventType != "" && event.Type != m.eventType {
+		return false
+	}
+	if m.run == "" {
+		return event.InvolvedObject.APIVersion == c8sv1alpha1.GroupVersion.String()
+	}
+
+	object := event.InvolvedObject
+	switch object.Kind {
+	case "PipelineRun":
+		return object.Name == m.run
+	case "Job", "Pod":
+	default:
+		return false
+	}
+
+	key := object.Kind + "/" + object.Namespace + "/" + object.Name
+	if m.children[key] {
+		return true
+	}
+	if m.unknown[key] {
+		return false
+	}
+
+	m.refresh(ctx)
+	if m.children[key] {
+		return true
+	}
+	if m.unknown == nil {
+		m.unknown = map[string]bool{}
+	}
+	m.unknown[key] = true
+	return false
+}
+
+// refresh lists the Jobs and Pods labelled with the run
+func (m *eventMatcher) refresh(ctx context.Context) {
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", types.LabelPipelineRun, m.run)}
+	if m.children == nil {
+		m.children = map[string]bool{}
+	}
+
+	if jobs, err := m.client.BatchV1().Jobs(m.namespace).List(ctx, selector); err == nil {
+		for _, job := range jobs.Items {
+			m.children["Job/"+job.Namespace+"/"+job.Name] = true
+		}
+	}
+	if pods, err := m.client.CoreV1().Pods(m.namespace).List(ctx, selector); err == nil {
+		for _, pod := range pods.Items {
+			m.children["Pod/"+pod.Namespace+"/"+pod.Name] = true
+		}
+	}
+}
+
+// eventTime returns when an event was last seen
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// toClusterEvent converts a Kubernetes event
+func toClusterEvent(event *corev1.Event) ClusterEvent {
+	return ClusterEvent{
+		LastSeen:  eventTime(event),
+		Type:      event.Type,
+		Namespace: event.InvolvedObject.Namespace,
+		Kind:      event.InvolvedObject.Kind,
+		Name:      event.InvolvedObject.Name,
+		Reason:    event.Reason,
+		Message:   event.Message,
+		Count:     event.Count,
+	}
+}
@@ -0,0 +1,389 @@
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/org/c8s/pkg/secrets"
+	"github.com/org/c8s/pkg/types"
+)
+
+const (
+	// DefaultLogBundleMaxSize is the size limit of a log bundle, small enough
+	// to attach to a GitHub issue
+	DefaultLogBundleMaxSize = 10 * 1024 * 1024
+
+	// minMaskedSecretLength is the length below which Secret values are not
+	// masked; short values such as "true" or "80" would redact unrelated text
+	minMaskedSecretLength = 6
+
+	// minTrimmedLogSize is the smallest tail kept of each log when trimming a
+	// bundle to its size limit
+	minTrimmedLogSize = 4 * 1024
+)
+
+// ExportLogsOptions holds options for exporting the logs of a cluster
+type ExportLogsOptions struct {
+	ClusterName       string
+	OperatorNamespace string // Namespace of the operator, API server and webhook (default c8s-system)
+
+	// OutputDir is the directory the bundle is written to (default ~/.c8s/logs)
+	OutputDir string
+
+	// EventsSince bounds the age of exported cluster events (default 1h)
+	EventsSince time.Duration
+
+	// MaxSize is the size limit of the bundle in bytes (default 10MB)
+	MaxSize int64
+}
+
+// ExportLogsResult describes a written log bundle
+type ExportLogsResult struct {
+	Path  string `json:"path"`
+	Files int    `json:"files"`
+	Size  int64  `json:"size"`
+
+	// Trimmed is set when logs were cut to their tail to fit the size limit
+	Trimmed bool `json:"trimmed"`
+
+	// Warnings lists what could not be collected
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// BundleFile is a file of a log bundle
+type BundleFile struct {
+	Name    string
+	Content []byte
+}
+
+// componentLogSources are the c8s components whose Pod logs are exported,
+// by bundle directory and Pod label selector
+var componentLogSources = []struct {
+	Dir      string
+	Selector string
+}{
+	{Dir: "operator", Selector: "app=c8s-controller"},
+	{Dir: "api-server", Selector: "app=c8s-api-server"},
+	{Dir: "webhook", Selector: "app=c8s-webhook"},
+}
+
+// DefaultLogBundleDir returns ~/.c8s/logs
+func DefaultLogBundleDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".c8s", "logs"), nil
+}
+
+// ExportLogs collects the c8s component logs, PipelineRun and PipelineConfig
+// resources, pipeline Jobs with their Pod logs and recent cluster events into
+// a timestamped tarball. Secret values of the cluster are redacted from every
+// file before it is written. Collection failures are reported as warnings so
+// a partially broken cluster still produces a bundle.
+func ExportLogs(ctx context.Context, opts ExportLogsOptions) (*ExportLogsResult, error) {
+	if opts.OperatorNamespace == "" {
+		opts.OperatorNamespace = "c8s-system"
+	}
+	if opts.EventsSince <= 0 {
+		opts.EventsSince = time.Hour
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultLogBundleMaxSize
+	}
+	if opts.OutputDir == "" {
+		dir, err := DefaultLogBundleDir()
+		if err != nil {
+			return nil, err
+		}
+		opts.OutputDir = dir
+	}
+
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, opts.ClusterName); err != nil {
+		return nil, &ClusterNotFoundError{Name: opts.ClusterName}
+	}
+
+	kubeContext := KubeContextName(opts.ClusterName)
+	result := &ExportLogsResult{}
+	var files []BundleFile
+
+	warn := func(format string, args ...interface{}) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	// Secret values are read first so nothing is written unmasked
+	secretValues, err := clusterSecretValues(ctx, kubeContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets for redaction: %w", err)
+	}
+
+	for _, source := range componentLogSources {
+		podFiles, err := podLogs(ctx, kubeContext, opts.OperatorNamespace, source.Selector, source.Dir)
+		if err != nil {
+			warn("%s logs: %v", source.Dir, err)
+			continue
+		}
+		if len(podFiles) == 0 {
+			warn("%s logs: no Pods matching %s in %s", source.Dir, source.Selector, opts.OperatorNamespace)
+		}
+		files = append(files, podFiles...)
+	}
+
+	for _, resource := range []string{"pipelineruns", "pipelineconfigs"} {
+		output, err := runKubectl(ctx, kubeContext, "get", resource, "--all-namespaces", "-o", "yaml")
+		if err != nil {
+			warn("%s: %v", resource, err)
+			continue
+		}
+		files = append(files, BundleFile{Name: "resources/" + resource + ".yaml", Content: []byte(output)})
+	}
+
+	managed := fmt.Sprintf("%s=%s", types.LabelManaged, types.LabelManagedValue)
+	if output, err := runKubectl(ctx, kubeContext, "get", "jobs", "--all-namespaces", "-l", managed, "-o", "yaml"); err != nil {
+		warn("jobs: %v", err)
+	} else {
+		files = append(files, BundleFile{Name: "resources/jobs.yaml", Content: []byte(output)})
+	}
+	if podFiles, err := podLogs(ctx, kubeContext, "", managed, "steps"); err != nil {
+		warn("step logs: %v", err)
+	} else {
+		files = append(files, podFiles...)
+	}
+
+	if output, err := runKubectl(ctx, kubeContext, "get", "events", "--all-namespaces", "-o", "json"); err != nil {
+		warn("events: %v", err)
+	} else {
+		events, err := FormatRecentEvents([]byte(output), time.Now().Add(-opts.EventsSince))
+		if err != nil {
+			warn("events: %v", err)
+		} else {
+			files = append(files, BundleFile{Name: "events.txt", Content: events})
+		}
+	}
+
+	if len(result.Warnings) > 0 {
+		files = append(files, BundleFile{Name: "warnings.txt", Content: []byte(strings.Join(result.Warnings, "\n") + "\n")})
+	}
+
+	bundle, trimmed, err := BuildLogBundle(files, secretValues, opts.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", opts.OutputDir, err)
+	}
+	path := filepath.Join(opts.OutputDir, time.Now().UTC().Format("20060102T150405Z")+".tar.gz")
+	if err := os.WriteFile(path, bundle, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	result.Path = path
+	result.Files = len(files)
+	result.Size = int64(len(bundle))
+	result.Trimmed = trimmed
+	return result, nil
+}
+
+// BuildLogBundle masks secret values in the files and writes them to a
+// gzip-compressed tarball. When the tarball exceeds maxSize, .log files are
+// cut to their tail, halving the kept size until the bundle fits. Returns
+// whether logs were trimmed, or an error if the bundle cannot fit.
+func BuildLogBundle(files []BundleFile, secretValues map[string]string, maxSize int64) ([]byte, bool, error) {
+	masked := make([]BundleFile, len(files))
+	largest := 0
+	for i, file := range files {
+		masked[i] = BundleFile{Name: file.Name, Content: secrets.MaskSecrets(file.Content, secretValues)}
+		if strings.HasSuffix(file.Name, ".log") && len(masked[i].Content) > largest {
+			largest = len(masked[i].Content)
+		}
+	}
+
+	bundle, err := writeLogBundle(masked, 0)
+	if err != nil {
+		return nil, false, err
+	}
+
+	trimmed := false
+	for limit := largest / 2; int64(len(bundle)) > maxSize; limit /= 2 {
+		if limit < minTrimmedLogSize {
+			return nil, true, fmt.Errorf("log bundle is %d bytes, over the %d byte limit even with trimmed logs", len(bundle), maxSize)
+		}
+		if bundle, err = writeLogBundle(masked, limit); err != nil {
+			return nil, true, err
+		}
+		trimmed = true
+	}
+
+	return bundle, trimmed, nil
+}
+
+// writeLogBundle writes the files to a gzip-compressed tarball, keeping only
+// the last logLimit bytes of .log files when logLimit > 0
+func writeLogBundle(files []BundleFile, logLimit int) ([]byte, error) {
+	var buf bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	tw := tar.NewWriter(gz)
+
+	now := time.Now()
+	for _, file := range files {
+		content := file.Content
+		if logLimit > 0 && strings.HasSuffix(file.Name, ".log") && len(content) > logLimit {
+			header := fmt.Sprintf("[trimmed to the last %d bytes to fit the bundle size limit]\n", logLimit)
+			content = append([]byte(header), content[len(content)-logLimit:]...)
+		}
+
+		hdr := &tar.Header{
+			Name:    file.Name,
+			Mode:    0o600,
+			Size:    int64(len(content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("failed to write %s to bundle: %w", file.Name, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, fmt.Errorf("failed to write %s to bundle: %w", file.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// clusterSecretValues returns the values of all Secrets in the cluster that
+// are long enough to be masked
+func clusterSecretValues(ctx context.Context, kubeContext string) (map[string]string, error) {
+	output, err := runKubectl(ctx, kubeContext, "get", "secrets", "--all-namespaces", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Data map[string]string `json:"data"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets: %w", err)
+	}
+
+	values := make(map[string]string)
+	for _, item := range list.Items {
+		for key, encoded := range item.Data {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil || len(decoded) < minMaskedSecretLength {
+				continue
+			}
+			values[item.Metadata.Namespace+"/"+item.Metadata.Name+"/"+key] = string(decoded)
+		}
+	}
+	return values, nil
+}
+
+// podLogs returns the logs of all containers of the Pods matching a label
+// selector, as dir/{namespace}/{pod}.log files. An empty namespace searches
+// all namespaces.
+func podLogs(ctx context.Context, kubeContext, namespace, selector, dir string) ([]BundleFile, error) {
+	args := []string{"get", "pods", "-l", selector, "-o", `jsonpath={range .items[*]}{.metadata.namespace}{" "}{.metadata.name}{"\n"}{end}`}
+	if namespace == "" {
+		args = append(args, "--all-namespaces")
+	} else {
+		args = append(args, "-n", namespace)
+	}
+	output, err := runKubectl(ctx, kubeContext, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []BundleFile
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		podNamespace, pod := fields[0], fields[1]
+
+		logs, err := runKubectl(ctx, kubeContext, "logs", pod, "-n", podNamespace, "--all-containers", "--prefix", "--timestamps")
+		if err != nil {
+			// Pods that never started have no logs; keep the reason instead
+			logs = fmt.Sprintf("failed to get logs: %v\n", err)
+		}
+		files = append(files, BundleFile{Name: fmt.Sprintf("%s/%s/%s.log", dir, podNamespace, pod), Content: []byte(logs)})
+	}
+	return files, nil
+}
+
+// FormatRecentEvents renders the events of a kubectl event list that occurred
+// after since, oldest first, one per line
+func FormatRecentEvents(eventListJSON []byte, since time.Time) ([]byte, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			InvolvedObject struct {
+				Kind string `json:"kind"`
+				Name string `json:"name"`
+			} `json:"involvedObject"`
+			Type          string    `json:"type"`
+			Reason        string    `json:"reason"`
+			Message       string    `json:"message"`
+			LastTimestamp time.Time `json:"lastTimestamp"`
+			EventTime     time.Time `json:"eventTime"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(eventListJSON, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse events: %w", err)
+	}
+
+	type event struct {
+		at   time.Time
+		line string
+	}
+	var events []event
+	for _, item := range list.Items {
+		at := item.LastTimestamp
+		if at.IsZero() {
+			at = item.EventTime
+		}
+		if at.Before(since) {
+			continue
+		}
+		line := fmt.Sprintf("%s %-8s %s %s/%s %s: %s", at.UTC().Format(time.RFC3339), item.Type,
+			item.Metadata.Namespace, item.InvolvedObject.Kind, item.InvolvedObject.Name, item.Reason, item.Message)
+		events = append(events, event{at: at, line: line})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	var buf bytes.Buffer
+	for _, e := range events {
+		buf.WriteString(e.line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
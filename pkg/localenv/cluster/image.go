@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/org/c8s/pkg/localenv"
+)
+
+// LoadImageOptions holds options for importing local Docker images into a cluster
+type LoadImageOptions struct {
+	Name   string
+	Images []string
+
+	// OnNode is called as each node of the cluster starts receiving the images
+	OnNode func(node string)
+}
+
+// LoadImageResult describes a completed image import
+type LoadImageResult struct {
+	Cluster  string        `json:"cluster"`
+	Images   []string      `json:"images"`
+	Nodes    []string      `json:"nodes"`
+	Duration time.Duration `json:"duration"`
+}
+
+// importNodePattern matches the k3d log line emitted as a node receives images, e.g.
+// "Importing images from tarball '/k3d/images/k3d-dev-images.tar' into node 'k3d-dev-server-0'..."
+var importNodePattern = regexp.MustCompile(`into node '([^']+)'`)
+
+// LoadImage copies images from the local Docker daemon into the containerd
+// runtime of every node of a k3d cluster with "k3d image import", without
+// pushing them to a registry
+func LoadImage(ctx context.Context, opts LoadImageOptions) (*LoadImageResult, error) {
+	if len(opts.Images) == 0 {
+		return nil, fmt.Errorf("no images specified")
+	}
+
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, opts.Name); err != nil {
+		return nil, &ClusterNotFoundError{Name: opts.Name}
+	}
+
+	// k3d treats names it cannot find in Docker as tarball paths, so check the
+	// images up front to report a typo clearly
+	for _, image := range opts.Images {
+		if err := exec.CommandContext(ctx, "docker", "image", "inspect", image).Run(); err != nil {
+			return nil, fmt.Errorf("image %s not found in the local Docker daemon", image)
+		}
+	}
+
+	result := &LoadImageResult{
+		Cluster: opts.Name,
+		Images:  opts.Images,
+	}
+	start := time.Now()
+
+	args := append([]string{"image", "import"}, opts.Images...)
+	args = append(args, "-c", opts.Name)
+	cmd := exec.CommandContext(ctx, "k3d", args...)
+
+	// k3d writes its progress log to stderr
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read k3d output: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run k3d: %w", err)
+	}
+
+	var output bytes.Buffer
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		line := scanner.Text()
+		output.WriteString(line + "\n")
+
+		if node, ok := ParseImportedNode(line); ok {
+			result.Nodes = append(result.Nodes, node)
+			if opts.OnNode != nil {
+				opts.OnNode(node)
+			}
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("k3d image import failed: %s", strings.TrimSpace(output.String()))
+	}
+
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// ParseImportedNode returns the node named in a k3d image import log line
+func ParseImportedNode(line string) (string, bool) {
+	match := importNodePattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// RunningClusters returns the names of the running c8s clusters
+func RunningClusters(ctx context.Context) ([]string, error) {
+	clusters, err := List(ctx, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, cluster := range clusters {
+		if cluster.State == localenv.StateRunning {
+			names = append(names, cluster.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,364 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/org/c8s/pkg/localenv"
+)
+
+// InspectResult holds low-level configuration details of a k3d cluster
+type InspectResult struct {
+	Name       string                 `json:"name" yaml:"name"`
+	K3dVersion string                 `json:"k3dVersion" yaml:"k3dVersion"`
+	Nodes      []NodeContainer        `json:"nodes" yaml:"nodes"`
+	Network    NetworkInfo            `json:"network" yaml:"network"`
+	Volumes    []string               `json:"volumes" yaml:"volumes"`
+	Registry   *RegistryInfo          `json:"registry,omitempty" yaml:"registry,omitempty"`
+	Kubeconfig *KubeconfigContext     `json:"kubeconfig,omitempty" yaml:"kubeconfig,omitempty"`
+	K3dCluster map[string]interface{} `json:"k3dCluster" yaml:"k3dCluster"`
+
+	// Config is the cluster configuration reconstructed from the running
+	// containers, in the same format used by 'c8s dev cluster create'
+	Config *localenv.ClusterConfig `json:"config" yaml:"config"`
+}
+
+// NodeContainer describes the docker container backing a cluster node
+type NodeContainer struct {
+	Name        string `json:"name" yaml:"name"`
+	Role        string `json:"role" yaml:"role"`
+	ContainerID string `json:"containerID" yaml:"containerID"`
+	Image       string `json:"image" yaml:"image"`
+	State       string `json:"state" yaml:"state"`
+	Ports       string `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+// NetworkInfo describes the docker network of a cluster
+type NetworkInfo struct {
+	Name    string `json:"name" yaml:"name"`
+	Subnet  string `json:"subnet,omitempty" yaml:"subnet,omitempty"`
+	Gateway string `json:"gateway,omitempty" yaml:"gateway,omitempty"`
+}
+
+// RegistryInfo describes the local registry attached to a cluster
+type RegistryInfo struct {
+	Name        string `json:"name" yaml:"name"`
+	ContainerID string `json:"containerID" yaml:"containerID"`
+	HostPort    int    `json:"hostPort" yaml:"hostPort"`
+}
+
+// KubeconfigContext is the kubeconfig context entry of a cluster (without credentials)
+type KubeconfigContext struct {
+	Name      string `json:"name" yaml:"name"`
+	Cluster   string `json:"cluster" yaml:"cluster"`
+	User      string `json:"user" yaml:"user"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Server    string `json:"server" yaml:"server"`
+}
+
+// ConfigDifference is a setting whose actual value differs from the expected one
+type ConfigDifference struct {
+	Field    string `json:"field" yaml:"field"`
+	Expected string `json:"expected" yaml:"expected"`
+	Actual   string `json:"actual" yaml:"actual"`
+}
+
+// dockerContainer is a line of 'docker ps --format {{json .}}' output
+type dockerContainer struct {
+	ID     string `json:"ID"`
+	Image  string `json:"Image"`
+	Names  string `json:"Names"`
+	Labels string `json:"Labels"`
+	State  string `json:"State"`
+	Ports  string `json:"Ports"`
+}
+
+// Inspect collects low-level configuration details of a k3d cluster from
+// k3d, docker and the kubeconfig
+func Inspect(ctx context.Context, clusterName string) (*InspectResult, error) {
+	k3dCluster, err := getK3dClusterObject(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InspectResult{
+		Name:       clusterName,
+		K3dCluster: k3dCluster,
+	}
+
+	if output, err := exec.CommandContext(ctx, "k3d", "version").Output(); err == nil {
+		result.K3dVersion = parseK3dVersion(string(output))
+	}
+
+	containers, err := listClusterContainers(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster containers: %w", err)
+	}
+	for _, container := range containers {
+		labels := parseDockerLabels(container.Labels)
+		role := labels["k3d.role"]
+
+		if role == "registry" {
+			result.Registry = &RegistryInfo{
+				Name:        container.Names,
+				ContainerID: container.ID,
+				HostPort:    parseHostPort(container.Ports),
+			}
+			continue
+		}
+
+		result.Nodes = append(result.Nodes, NodeContainer{
+			Name:        container.Names,
+			Role:        role,
+			ContainerID: container.ID,
+			Image:       container.Image,
+			State:       container.State,
+			Ports:       container.Ports,
+		})
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool { return result.Nodes[i].Name < result.Nodes[j].Name })
+
+	result.Network, err = inspectNetwork(ctx, fmt.Sprintf("k3d-%s", clusterName))
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := exec.CommandContext(ctx, "docker", "volume", "ls", "-q", "--filter", fmt.Sprintf("label=k3d.cluster=%s", clusterName)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster volumes: %w", err)
+	}
+	result.Volumes = strings.Fields(string(output))
+
+	result.Kubeconfig = getKubeconfigContext(fmt.Sprintf("k3d-%s", clusterName))
+	result.Config = actualClusterConfig(clusterName, result)
+
+	return result, nil
+}
+
+// DiffClusterConfig compares the settings of two cluster configurations that
+// can be observed on a running cluster
+func DiffClusterConfig(expected, actual *localenv.ClusterConfig) []ConfigDifference {
+	var diffs []ConfigDifference
+	compare := func(field, want, got string) {
+		if want != got {
+			diffs = append(diffs, ConfigDifference{Field: field, Expected: want, Actual: got})
+		}
+	}
+
+	compare("kubernetesVersion", expected.KubernetesVersion, actual.KubernetesVersion)
+	compare("nodes.server", strconv.Itoa(nodeCount(expected, "server")), strconv.Itoa(nodeCount(actual, "server")))
+	compare("nodes.agent", strconv.Itoa(nodeCount(expected, "agent")), strconv.Itoa(nodeCount(actual, "agent")))
+
+	expectedRegistry := expected.Registry != nil && expected.Registry.Enabled
+	actualRegistry := actual.Registry != nil && actual.Registry.Enabled
+	compare("registry.enabled", strconv.FormatBool(expectedRegistry), strconv.FormatBool(actualRegistry))
+	if expectedRegistry && actualRegistry {
+		compare("registry.name", strings.TrimPrefix(expected.Registry.Name, "k3d-"), strings.TrimPrefix(actual.Registry.Name, "k3d-"))
+		compare("registry.hostPort", strconv.Itoa(expected.Registry.HostPort), strconv.Itoa(actual.Registry.HostPort))
+	}
+
+	return diffs
+}
+
+// nodeCount returns the total number of nodes of a type
+func nodeCount(config *localenv.ClusterConfig, nodeType string) int {
+	count := 0
+	for _, node := range config.Nodes {
+		if node.Type == nodeType {
+			count += node.Count
+		}
+	}
+	return count
+}
+
+// actualClusterConfig reconstructs the cluster configuration from inspected containers
+func actualClusterConfig(clusterName string, result *InspectResult) *localenv.ClusterConfig {
+	servers, agents := 0, 0
+	version := ""
+	for _, node := range result.Nodes {
+		switch node.Role {
+		case "server":
+			servers++
+			if version == "" {
+				version = k3sImageVersion(node.Image)
+			}
+		case "agent":
+			agents++
+		}
+	}
+
+	config := &localenv.ClusterConfig{
+		Name:              clusterName,
+		KubernetesVersion: version,
+		Nodes: []localenv.NodeConfig{
+			{Type: "server", Count: servers},
+			{Type: "agent", Count: agents},
+		},
+	}
+	if result.Registry != nil {
+		config.Registry = &localenv.RegistryConfig{
+			Enabled:  true,
+			Name:     result.Registry.Name,
+			HostPort: result.Registry.HostPort,
+		}
+	}
+	return config
+}
+
+// getK3dClusterObject returns the k3d cluster object as reported by k3d,
+// without the cluster join token
+func getK3dClusterObject(ctx context.Context, clusterName string) (map[string]interface{}, error) {
+	output, err := exec.CommandContext(ctx, "k3d", "cluster", "list", clusterName, "-o", "json").Output()
+	if err != nil {
+		return nil, &ClusterNotFoundError{Name: clusterName}
+	}
+
+	var clusters []map[string]interface{}
+	if err := json.Unmarshal(output, &clusters); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster info: %w", err)
+	}
+	if len(clusters) == 0 {
+		return nil, &ClusterNotFoundError{Name: clusterName}
+	}
+
+	delete(clusters[0], "token")
+	return clusters[0], nil
+}
+
+// listClusterContainers returns the docker containers labelled for a cluster
+func listClusterContainers(ctx context.Context, clusterName string) ([]dockerContainer, error) {
+	output, err := exec.CommandContext(ctx, "docker", "ps", "-a",
+		"--filter", fmt.Sprintf("label=k3d.cluster=%s", clusterName),
+		"--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []dockerContainer
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var container dockerContainer
+		if err := json.Unmarshal([]byte(line), &container); err != nil {
+			return nil, fmt.Errorf("failed to parse docker output: %w", err)
+		}
+		containers = append(containers, container)
+	}
+	return containers, scanner.Err()
+}
+
+// inspectNetwork returns the subnet and gateway of a docker network
+func inspectNetwork(ctx context.Context, networkName string) (NetworkInfo, error) {
+	info := NetworkInfo{Name: networkName}
+
+	output, err := exec.CommandContext(ctx, "docker", "network", "inspect", networkName).Output()
+	if err != nil {
+		return info, fmt.Errorf("failed to inspect network %s: %w", networkName, err)
+	}
+
+	var networks []struct {
+		IPAM struct {
+			Config []struct {
+				Subnet  string `json:"Subnet"`
+				Gateway string `json:"Gateway"`
+			} `json:"Config"`
+		} `json:"IPAM"`
+	}
+	if err := json.Unmarshal(output, &networks); err != nil {
+		return info, fmt.Errorf("failed to parse network info: %w", err)
+	}
+	if len(networks) > 0 && len(networks[0].IPAM.Config) > 0 {
+		info.Subnet = networks[0].IPAM.Config[0].Subnet
+		info.Gateway = networks[0].IPAM.Config[0].Gateway
+	}
+	return info, nil
+}
+
+// getKubeconfigContext returns a context entry from the default kubeconfig
+func getKubeconfigContext(contextName string) *KubeconfigContext {
+	config, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return nil
+	}
+
+	entry, exists := config.Contexts[contextName]
+	if !exists {
+		return nil
+	}
+
+	kubeContext := &KubeconfigContext{
+		Name:      contextName,
+		Cluster:   entry.Cluster,
+		User:      entry.AuthInfo,
+		Namespace: entry.Namespace,
+	}
+	if cluster, exists := config.Clusters[entry.Cluster]; exists {
+		kubeContext.Server = cluster.Server
+	}
+	return kubeContext
+}
+
+// parseK3dVersion extracts the version from 'k3d version' output
+// (e.g. "k3d version v5.6.0\nk3s version v1.27.4-k3s1 (default)")
+func parseK3dVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if version, found := strings.CutPrefix(strings.TrimSpace(line), "k3d version "); found {
+			return version
+		}
+	}
+	return strings.TrimSpace(output)
+}
+
+// parseDockerLabels parses the comma-separated labels of 'docker ps' output
+func parseDockerLabels(labels string) map[string]string {
+	parsed := make(map[string]string)
+	for _, label := range strings.Split(labels, ",") {
+		if key, value, found := strings.Cut(label, "="); found {
+			parsed[key] = value
+		}
+	}
+	return parsed
+}
+
+// parseHostPort returns the first host port of 'docker ps' port output
+// (e.g. "0.0.0.0:5000->5000/tcp")
+func parseHostPort(ports string) int {
+	for _, mapping := range strings.Split(ports, ",") {
+		host, _, found := strings.Cut(strings.TrimSpace(mapping), "->")
+		if !found {
+			continue
+		}
+		if idx := strings.LastIndex(host, ":"); idx >= 0 {
+			if port, err := strconv.Atoi(host[idx+1:]); err == nil {
+				return port
+			}
+		}
+	}
+	return 0
+}
+
+// k3sImageVersion returns the Kubernetes version of a k3s image
+// (e.g. "rancher/k3s:v1.28.15-k3s1" -> "v1.28.15")
+func k3sImageVersion(image string) string {
+	idx := strings.LastIndex(image, ":")
+	if idx < 0 {
+		return ""
+	}
+	tag := image[idx+1:]
+	if k3s := strings.Index(tag, "-k3s"); k3s >= 0 {
+		tag = tag[:k3s]
+	}
+	return tag
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/org/c8s/pkg/types"
 )
 
 // K3dClient interface defines operations for k3d cluster management
@@ -33,6 +36,13 @@ type K3dClient interface {
 	// LoadImage loads a Docker image into the k3d cluster
 	LoadImage(ctx context.Context, clusterName, imageName string) error
 
+	// CreateNodes adds nodes of a role to a running cluster, named after
+	// name, and waits for them to be ready
+	CreateNodes(ctx context.Context, clusterName string, config *NodeCreateConfig) error
+
+	// DeleteNode deletes the container of a node
+	DeleteNode(ctx context.Context, nodeName string) error
+
 	// IsDockerAvailable checks if Docker daemon is accessible
 	IsDockerAvailable(ctx context.Context) error
 }
@@ -47,6 +57,7 @@ type ClusterCreateConfig struct {
 	RegistryName      string
 	RegistryPort      int
 	Ports             []PortMapping
+	Volumes           []VolumeMapping
 	K3sArgs           []string
 	WaitTimeout       time.Duration
 }
@@ -59,6 +70,21 @@ type PortMapping struct {
 	NodeFilter    string
 }
 
+// VolumeMapping represents a host path mounted into k3d nodes
+type VolumeMapping struct {
+	HostPath      string
+	ContainerPath string
+	NodeFilter    string
+}
+
+// NodeCreateConfig holds configuration for node creation
+type NodeCreateConfig struct {
+	Name        string
+	Role        string
+	Replicas    int
+	WaitTimeout time.Duration
+}
+
 // ClusterInfo holds information about a k3d cluster
 type ClusterInfo struct {
 	Name            string          `json:"name"`
@@ -72,6 +98,11 @@ type ClusterInfo struct {
 	ImageVolume     string          `json:"imageVolume"`
 }
 
+// K3sImage returns the k3s image k3d runs for a Kubernetes version (e.g. v1.28.15)
+func K3sImage(kubernetesVersion string) string {
+	return fmt.Sprintf("rancher/k3s:%s-k3s1", kubernetesVersion)
+}
+
 // k3dClientImpl implements K3dClient using k3d command-line tool
 type k3dClientImpl struct {
 	execTimeout time.Duration
@@ -90,7 +121,7 @@ func (k *k3dClientImpl) Create(ctx context.Context, config *ClusterCreateConfig)
 
 	// Add Kubernetes version if specified
 	if config.KubernetesVersion != "" {
-		args = append(args, "--image", fmt.Sprintf("rancher/k3s:%s-k3s1", config.KubernetesVersion))
+		args = append(args, "--image", K3sImage(config.KubernetesVersion))
 	}
 
 	// Add server and agent counts
@@ -117,6 +148,11 @@ func (k *k3dClientImpl) Create(ctx context.Context, config *ClusterCreateConfig)
 		args = append(args, "-p", portArg)
 	}
 
+	// Add volume mounts
+	for _, volume := range config.Volumes {
+		args = append(args, "-v", fmt.Sprintf("%s:%s@%s", volume.HostPath, volume.ContainerPath, volume.NodeFilter))
+	}
+
 	// Add k3s args
 	for _, k3sArg := range config.K3sArgs {
 		args = append(args, "--k3s-arg", fmt.Sprintf("%s@server:*", k3sArg))
@@ -125,6 +161,9 @@ func (k *k3dClientImpl) Create(ctx context.Context, config *ClusterCreateConfig)
 	// Add wait flag
 	args = append(args, "--wait")
 
+	// The caller switches the kubeconfig context explicitly (see SwitchContext)
+	args = append(args, "--kubeconfig-switch-context=false")
+
 	// Set timeout
 	execCtx, cancel := context.WithTimeout(ctx, config.WaitTimeout)
 	defer cancel()
@@ -171,8 +210,9 @@ func (k *k3dClientImpl) List(ctx context.Context) ([]ClusterInfo, error) {
 func (k *k3dClientImpl) Get(ctx context.Context, name string) (*ClusterInfo, error) {
 	output, err := k.runK3dCommandWithOutput(ctx, "cluster", "list", name, "-o", "json")
 	if err != nil {
+		// k3d only reports missing clusters in its output
 		if strings.Contains(err.Error(), "not found") {
-			return nil, fmt.Errorf("cluster %s not found", name)
+			return nil, &ClusterNotFoundError{Name: name}
 		}
 		return nil, err
 	}
@@ -184,7 +224,7 @@ func (k *k3dClientImpl) Get(ctx context.Context, name string) (*ClusterInfo, err
 	}
 
 	if len(clusters) == 0 {
-		return nil, fmt.Errorf("cluster %s not found", name)
+		return nil, &ClusterNotFoundError{Name: name}
 	}
 
 	return &clusters[0], nil
@@ -195,6 +235,25 @@ func (k *k3dClientImpl) LoadImage(ctx context.Context, clusterName, imageName st
 	return k.runK3dCommand(ctx, "image", "import", imageName, "-c", clusterName)
 }
 
+// CreateNodes adds nodes of a role to a running cluster
+func (k *k3dClientImpl) CreateNodes(ctx context.Context, clusterName string, config *NodeCreateConfig) error {
+	args := []string{"node", "create", config.Name,
+		"--cluster", clusterName,
+		"--role", config.Role,
+		"--replicas", fmt.Sprintf("%d", config.Replicas),
+		"--wait",
+	}
+	if config.WaitTimeout > 0 {
+		args = append(args, "--timeout", config.WaitTimeout.String())
+	}
+	return k.runK3dCommand(ctx, args...)
+}
+
+// DeleteNode deletes the container of a node
+func (k *k3dClientImpl) DeleteNode(ctx context.Context, nodeName string) error {
+	return k.runK3dCommand(ctx, "node", "delete", nodeName)
+}
+
 // IsDockerAvailable checks if Docker daemon is accessible
 func (k *k3dClientImpl) IsDockerAvailable(ctx context.Context) error {
 	cmd := exec.CommandContext(ctx, "docker", "info")
@@ -226,6 +285,9 @@ func (k *k3dClientImpl) runK3dCommandWithOutput(ctx context.Context, args ...str
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("k3d command failed: %w", types.ErrTimeoutExceeded)
+		}
 		if stderr.Len() > 0 {
 			return nil, fmt.Errorf("k3d command failed: %s", stderr.String())
 		}
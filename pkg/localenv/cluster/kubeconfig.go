@@ -0,0 +1,226 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// KubeContextName returns the kubeconfig context k3d creates for a cluster
+func KubeContextName(clusterName string) string {
+	return fmt.Sprintf("k3d-%s", clusterName)
+}
+
+// SwitchContext makes contextName the current context of the kubeconfig
+// (the KUBECONFIG files, or ~/.kube/config)
+func SwitchContext(contextName string) error {
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	config, err := pathOptions.GetStartingConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	if _, exists := config.Contexts[contextName]; !exists {
+		return fmt.Errorf("context %s not found in kubeconfig", contextName)
+	}
+
+	config.CurrentContext = contextName
+	if err := clientcmd.ModifyConfig(pathOptions, *config, true); err != nil {
+		return fmt.Errorf("failed to update kubeconfig: %w", err)
+	}
+	return nil
+}
+
+// SwitchFromDeletedContexts moves the current context off contexts of deleted
+// clusters. The current context is kept if it still exists; otherwise the
+// first remaining context, preferring other k3d clusters, becomes current, or
+// the current context is cleared when none remain. Returns the new current
+// context.
+func SwitchFromDeletedContexts(deleted ...string) (string, error) {
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	config, err := pathOptions.GetStartingConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	next := NextContext(config, deleted)
+	if next == config.CurrentContext {
+		return next, nil
+	}
+
+	config.CurrentContext = next
+	if err := clientcmd.ModifyConfig(pathOptions, *config, true); err != nil {
+		return "", fmt.Errorf("failed to update kubeconfig: %w", err)
+	}
+	return next, nil
+}
+
+// NextContext returns the context that should be current once the deleted
+// contexts are gone
+func NextContext(config *clientcmdapi.Config, deleted []string) string {
+	isDeleted := make(map[string]bool, len(deleted))
+	for _, name := range deleted {
+		isDeleted[name] = true
+	}
+
+	if _, exists := config.Contexts[config.CurrentContext]; exists && !isDeleted[config.CurrentContext] {
+		return config.CurrentContext
+	}
+
+	var remaining []string
+	for name := range config.Contexts {
+		if !isDeleted[name] {
+			remaining = append(remaining, name)
+		}
+	}
+	if len(remaining) == 0 {
+		return ""
+	}
+
+	sort.Slice(remaining, func(i, j int) bool {
+		iK3d, jK3d := strings.HasPrefix(remaining[i], "k3d-"), strings.HasPrefix(remaining[j], "k3d-")
+		if iK3d != jK3d {
+			return iK3d
+		}
+		return remaining[i] < remaining[j]
+	})
+	return remaining[0]
+}
+
+// PreviousContextKey is the key of ~/.c8s/config.yaml recording the context
+// that was current before the last switch
+const PreviousContextKey = "previousContext"
+
+// ClusterContext is the kubeconfig context of a c8s cluster
+type ClusterContext struct {
+	Name    string `json:"name" yaml:"name"`
+	Cluster string `json:"cluster" yaml:"cluster"`
+	Current bool   `json:"current" yaml:"current"`
+}
+
+// ListClusterContexts returns the k3d contexts of the kubeconfig sorted by
+// name, with the current one marked
+func ListClusterContexts() ([]ClusterContext, error) {
+	config, err := clientcmd.NewDefaultPathOptions().GetStartingConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	var contexts []ClusterContext
+	for name := range config.Contexts {
+		clusterName, found := strings.CutPrefix(name, "k3d-")
+		if !found {
+			continue
+		}
+		contexts = append(contexts, ClusterContext{
+			Name:    name,
+			Cluster: clusterName,
+			Current: name == config.CurrentContext,
+		})
+	}
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].Name < contexts[j].Name })
+	return contexts, nil
+}
+
+// UseContext makes contextName the current context like SwitchContext, and
+// records the context it replaces in ~/.c8s/config.yaml so it can be restored
+// with UsePreviousContext. Returns the replaced context.
+func UseContext(contextName string) (string, error) {
+	config, err := clientcmd.NewDefaultPathOptions().GetStartingConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	previous := config.CurrentContext
+
+	if err := SwitchContext(contextName); err != nil {
+		return "", err
+	}
+	if previous != "" && previous != contextName {
+		if err := setUserConfigValue(PreviousContextKey, previous); err != nil {
+			return previous, err
+		}
+	}
+	return previous, nil
+}
+
+// UsePreviousContext switches back to the context recorded by the last
+// UseContext call. Returns the new current context.
+func UsePreviousContext() (string, error) {
+	values, err := readUserConfig()
+	if err != nil {
+		return "", err
+	}
+	previous, _ := values[PreviousContextKey].(string)
+	if previous == "" {
+		return "", fmt.Errorf("no previous context recorded in ~/.c8s/config.yaml")
+	}
+
+	if _, err := UseContext(previous); err != nil {
+		return "", err
+	}
+	return previous, nil
+}
+
+// UserConfigPath returns ~/.c8s/config.yaml
+func UserConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".c8s", "config.yaml"), nil
+}
+
+// readUserConfig reads ~/.c8s/config.yaml, which is empty when missing
+func readUserConfig() (map[string]interface{}, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return values, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values, nil
+}
+
+// setUserConfigValue sets a key of ~/.c8s/config.yaml, keeping the others
+func setUserConfigValue(key string, value interface{}) error {
+	values, err := readUserConfig()
+	if err != nil {
+		return err
+	}
+	values[key] = value
+
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -1,9 +1,16 @@
 package cluster
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/org/c8s/pkg/localenv"
 )
@@ -11,6 +18,14 @@ import (
 // ListOptions holds options for listing clusters
 type ListOptions struct {
 	All bool // Show all k3d clusters, not just c8s clusters
+
+	// Details also collects the endpoints, server version, context and disk
+	// usage of each cluster, which needs extra docker and kubectl calls
+	Details bool
+
+	// Registry only collects the registry endpoint of each cluster, for
+	// FilterClustersWithRegistry without the other details
+	Registry bool
 }
 
 // ClusterListItem represents a cluster in the list
@@ -20,6 +35,13 @@ type ClusterListItem struct {
 	NodeCount int    `json:"nodeCount"`
 	Version   string `json:"version,omitempty"`
 	Uptime    string `json:"uptime,omitempty"`
+
+	// Set with ListOptions.Details
+	RegistryEndpoint string `json:"registryEndpoint,omitempty"`
+	APIEndpoint      string `json:"apiEndpoint,omitempty"`
+	ServerVersion    string `json:"serverVersion,omitempty"`
+	Context          string `json:"context,omitempty"`
+	DiskUsage        string `json:"diskUsage,omitempty"`
 }
 
 // List lists clusters based on the provided options
@@ -34,6 +56,12 @@ func List(ctx context.Context, opts ListOptions) ([]ClusterListItem, error) {
 
 	var result []ClusterListItem
 
+	// Volume sizes are read once for all clusters: 'docker system df' is slow
+	var volumeSizes map[string]int64
+	if opts.Details {
+		volumeSizes, _ = dockerVolumeSizes(ctx)
+	}
+
 	for _, cluster := range clusters {
 		// Filter by c8s prefix unless --all is specified
 		if !opts.All && !isC8sCluster(cluster.Name) {
@@ -62,12 +90,250 @@ func List(ctx context.Context, opts ListOptions) ([]ClusterListItem, error) {
 			}
 		}
 
+		if opts.Details {
+			addListDetails(ctx, &item, volumeSizes)
+		} else if opts.Registry {
+			item.RegistryEndpoint = registryEndpoint(ctx, item.Name)
+		}
+
 		result = append(result, item)
 	}
 
 	return result, nil
 }
 
+// ListSortFields are the fields SortClusters can order clusters by
+var ListSortFields = []string{"name", "state", "uptime", "nodes"}
+
+// ListFilterStates are the states FilterClustersByState accepts
+var ListFilterStates = []string{localenv.StateRunning, localenv.StateStopped}
+
+// SortClusters orders clusters by one of ListSortFields, ascending unless
+// desc is set. Clusters with equal values keep their name order.
+func SortClusters(clusters []ClusterListItem, field string, desc bool) error {
+	var less func(a, b *ClusterListItem) bool
+	switch field {
+	case "name":
+		less = func(a, b *ClusterListItem) bool { return false }
+	case "state":
+		less = func(a, b *ClusterListItem) bool { return a.State < b.State }
+	case "uptime":
+		less = func(a, b *ClusterListItem) bool { return uptimeDuration(a.Uptime) < uptimeDuration(b.Uptime) }
+	case "nodes":
+		less = func(a, b *ClusterListItem) bool { return a.NodeCount < b.NodeCount }
+	default:
+		return fmt.Errorf("invalid sort field %q (valid: %s)", field, strings.Join(ListSortFields, ", "))
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		a, b := &clusters[i], &clusters[j]
+		if desc {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.Name < b.Name
+	})
+	return nil
+}
+
+// FilterClustersByState returns the clusters in one of ListFilterStates
+func FilterClustersByState(clusters []ClusterListItem, state string) ([]ClusterListItem, error) {
+	valid := false
+	for _, s := range ListFilterStates {
+		valid = valid || s == state
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid state %q (valid: %s)", state, strings.Join(ListFilterStates, ", "))
+	}
+
+	var filtered []ClusterListItem
+	for _, c := range clusters {
+		if c.State == state {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// FilterClustersByVersion returns the clusters whose Kubernetes version
+// starts with prefix, e.g. "v1.28" or "1.28". Clusters that aren't running
+// have no version and never match.
+func FilterClustersByVersion(clusters []ClusterListItem, prefix string) []ClusterListItem {
+	if !strings.HasPrefix(prefix, "v") {
+		prefix = "v" + prefix
+	}
+
+	var filtered []ClusterListItem
+	for _, c := range clusters {
+		if c.Version != "" && strings.HasPrefix(c.Version, prefix) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// FilterClustersWithRegistry returns the clusters with a registry, which
+// needs clusters listed with ListOptions.Registry or ListOptions.Details
+func FilterClustersWithRegistry(clusters []ClusterListItem) []ClusterListItem {
+	var filtered []ClusterListItem
+	for _, c := range clusters {
+		if c.RegistryEndpoint != "" {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// uptimeDuration parses an uptime formatted by CalculateUptime. Clusters
+// without an uptime (not running) sort before those up for less than a minute.
+func uptimeDuration(uptime string) time.Duration {
+	switch uptime {
+	case "":
+		return -1
+	case "< 1m":
+		return 0
+	}
+	d, err := time.ParseDuration(uptime)
+	if err != nil {
+		return -1
+	}
+	return d
+}
+
+// addListDetails sets the ListOptions.Details fields of a cluster. Details
+// that can't be read are left empty.
+func addListDetails(ctx context.Context, item *ClusterListItem, volumeSizes map[string]int64) {
+	contextName := KubeContextName(item.Name)
+	item.Context = contextName
+	if kubeContext := getKubeconfigContext(contextName); kubeContext != nil {
+		item.APIEndpoint = kubeContext.Server
+	}
+
+	item.RegistryEndpoint = registryEndpoint(ctx, item.Name)
+
+	if item.State == localenv.StateRunning {
+		item.ServerVersion = serverVersion(ctx, contextName)
+	}
+
+	if volumeSizes != nil {
+		output, err := exec.CommandContext(ctx, "docker", "volume", "ls", "-q", "--filter", fmt.Sprintf("label=k3d.cluster=%s", item.Name)).Output()
+		if err == nil {
+			var total int64
+			for _, volume := range strings.Fields(string(output)) {
+				total += volumeSizes[volume]
+			}
+			item.DiskUsage = FormatDiskSize(total)
+		}
+	}
+}
+
+// registryEndpoint returns the host endpoint of the registry container of a
+// cluster, or "" if it has none
+func registryEndpoint(ctx context.Context, clusterName string) string {
+	containers, err := listClusterContainers(ctx, clusterName)
+	if err != nil {
+		return ""
+	}
+
+	endpoint := ""
+	for _, container := range containers {
+		if parseDockerLabels(container.Labels)["k3d.role"] != "registry" {
+			continue
+		}
+		if port := parseHostPort(container.Ports); port > 0 {
+			endpoint = fmt.Sprintf("localhost:%d", port)
+		}
+	}
+	return endpoint
+}
+
+// serverVersion returns the Kubernetes version reported by the API server
+// of a kubeconfig context, or "" if it can't be reached
+func serverVersion(ctx context.Context, contextName string) string {
+	output, err := exec.CommandContext(ctx, "kubectl", "version", "--context", contextName, "--output=json").Output()
+	if err != nil && len(output) == 0 {
+		return ""
+	}
+	var version struct {
+		ServerVersion *struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"serverVersion"`
+	}
+	if json.Unmarshal(output, &version) != nil || version.ServerVersion == nil {
+		return ""
+	}
+	return version.ServerVersion.GitVersion
+}
+
+// dockerVolumeSizes returns the size in bytes of every docker volume, from
+// 'docker system df -v'
+func dockerVolumeSizes(ctx context.Context) (map[string]int64, error) {
+	output, err := exec.CommandContext(ctx, "docker", "system", "df", "-v", "--format", "{{json .Volumes}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get docker disk usage: %w", err)
+	}
+
+	var volumes []struct {
+		Name string `json:"Name"`
+		Size string `json:"Size"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(output), &volumes); err != nil {
+		return nil, fmt.Errorf("failed to parse docker disk usage: %w", err)
+	}
+
+	sizes := make(map[string]int64, len(volumes))
+	for _, volume := range volumes {
+		if size, err := ParseDiskSize(volume.Size); err == nil {
+			sizes[volume.Name] = size
+		}
+	}
+	return sizes, nil
+}
+
+// diskSizeUnits are the decimal units used by docker to print sizes
+var diskSizeUnits = []string{"B", "kB", "MB", "GB", "TB", "PB"}
+
+// ParseDiskSize parses a size printed by docker (e.g. "1.5GB", "512kB") to bytes
+func ParseDiskSize(size string) (int64, error) {
+	size = strings.TrimSpace(size)
+	for i := len(diskSizeUnits) - 1; i >= 0; i-- {
+		unit := diskSizeUnits[i]
+		number, found := strings.CutSuffix(size, unit)
+		if !found && unit == "kB" {
+			number, found = strings.CutSuffix(size, "KB")
+		}
+		if !found {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil || value < 0 {
+			break
+		}
+		return int64(value * math.Pow(1000, float64(i))), nil
+	}
+	return 0, fmt.Errorf("invalid size %q", size)
+}
+
+// FormatDiskSize formats a number of bytes with the units used by docker
+// (e.g. 1500000000 -> "1.5GB")
+func FormatDiskSize(size int64) string {
+	value := float64(size)
+	unit := 0
+	for value >= 1000 && unit < len(diskSizeUnits)-1 {
+		value /= 1000
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%dB", size)
+	}
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64) + diskSizeUnits[unit]
+}
+
 // isC8sCluster checks if a cluster name follows c8s naming convention
 func isC8sCluster(name string) bool {
 	// c8s clusters typically start with "c8s-"
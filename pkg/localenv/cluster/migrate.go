@@ -0,0 +1,240 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+var namespaceResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// Migration actions
+const (
+	MigrateCreated = "created"
+	MigrateUpdated = "updated"
+	MigrateSkipped = "skipped"
+	MigratePlanned = "would create"
+)
+
+// MigrateOptions holds options for migrating PipelineConfigs between
+// namespaces or clusters
+type MigrateOptions struct {
+	// Cluster is the source cluster
+	Cluster       string
+	FromNamespace string
+
+	// ToCluster defaults to Cluster and ToNamespace to FromNamespace; at
+	// least one of them must differ from the source
+	ToCluster   string
+	ToNamespace string
+
+	// IncludeRuns also copies the finished PipelineRuns of the source
+	// namespace, keeping their status. Copied runs are annotated as imported
+	// so the controller of the target doesn't execute them again.
+	IncludeRuns bool
+
+	// DryRun reports the objects that would be created without creating them
+	DryRun bool
+}
+
+// MigratedObject is an object copied, or planned to be copied, to the target
+type MigratedObject struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+
+	// Reason explains why an object was skipped
+	Reason string `json:"reason,omitempty"`
+}
+
+// MigrateResult describes a migration
+type MigrateResult struct {
+	FromCluster   string           `json:"fromCluster"`
+	FromNamespace string           `json:"fromNamespace"`
+	ToCluster     string           `json:"toCluster"`
+	ToNamespace   string           `json:"toNamespace"`
+	DryRun        bool             `json:"dryRun"`
+	Objects       []MigratedObject `json:"objects"`
+}
+
+// Migrate copies the PipelineConfigs of a namespace, and optionally its
+// finished PipelineRuns, to another namespace of the same cluster or to
+// another cluster. The source objects are left in place.
+func Migrate(ctx context.Context, opts MigrateOptions) (*MigrateResult, error) {
+	if opts.ToCluster == "" {
+		opts.ToCluster = opts.Cluster
+	}
+	if opts.ToNamespace == "" {
+		opts.ToNamespace = opts.FromNamespace
+	}
+	if opts.ToCluster == opts.Cluster && opts.ToNamespace == opts.FromNamespace {
+		return nil, fmt.Errorf("source and target are both namespace %s of cluster %s", opts.FromNamespace, opts.Cluster)
+	}
+
+	from, err := pauseClient(ctx, opts.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	to := from
+	if opts.ToCluster != opts.Cluster {
+		if to, err = pauseClient(ctx, opts.ToCluster); err != nil {
+			return nil, err
+		}
+	}
+
+	return MigrateResources(ctx, from, to, opts)
+}
+
+// MigrateResources copies the objects selected by opts from one client to
+// another, which may be the same client for a migration between namespaces
+func MigrateResources(ctx context.Context, from, to dynamic.Interface, opts MigrateOptions) (*MigrateResult, error) {
+	result := &MigrateResult{
+		FromCluster:   opts.Cluster,
+		FromNamespace: opts.FromNamespace,
+		ToCluster:     opts.ToCluster,
+		ToNamespace:   opts.ToNamespace,
+		DryRun:        opts.DryRun,
+	}
+
+	configs, err := from.Resource(pipelineConfigResource).Namespace(opts.FromNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipelineconfigs in %s: %w", opts.FromNamespace, err)
+	}
+	var runs []unstructured.Unstructured
+	if opts.IncludeRuns {
+		list, err := from.Resource(pipelineRunResource).Namespace(opts.FromNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pipelineruns in %s: %w", opts.FromNamespace, err)
+		}
+		runs = list.Items
+	}
+
+	if !opts.DryRun && len(configs.Items)+len(runs) > 0 {
+		if err := ensureNamespace(ctx, to, opts.ToNamespace); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range configs.Items {
+		config := MigrationObject(&configs.Items[i], opts.ToNamespace)
+		action, err := migrateObject(ctx, to.Resource(pipelineConfigResource).Namespace(opts.ToNamespace), config, nil, opts.DryRun)
+		if err != nil {
+			return result, fmt.Errorf("failed to migrate PipelineConfig %s: %w", config.GetName(), err)
+		}
+		result.Objects = append(result.Objects, MigratedObject{Kind: "PipelineConfig", Name: config.GetName(), Action: action})
+	}
+
+	importedFrom := fmt.Sprintf("%s/%s", KubeContextName(opts.Cluster), opts.FromNamespace)
+	for i := range runs {
+		source := &runs[i]
+		phase, _, _ := unstructured.NestedString(source.Object, "status", "phase")
+		if !isFinishedRunPhase(phase) {
+			result.Objects = append(result.Objects, MigratedObject{
+				Kind:   "PipelineRun",
+				Name:   source.GetName(),
+				Action: MigrateSkipped,
+				Reason: fmt.Sprintf("run is %s", orUnknown(phase)),
+			})
+			continue
+		}
+
+		status, _, _ := unstructured.NestedMap(source.Object, "status")
+		run := MigrationObject(source, opts.ToNamespace)
+		annotations := run.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		if _, ok := annotations[types.AnnotationImportedFrom]; !ok {
+			annotations[types.AnnotationImportedFrom] = importedFrom
+		}
+		run.SetAnnotations(annotations)
+
+		action, err := migrateObject(ctx, to.Resource(pipelineRunResource).Namespace(opts.ToNamespace), run, status, opts.DryRun)
+		if err != nil {
+			return result, fmt.Errorf("failed to migrate PipelineRun %s: %w", run.GetName(), err)
+		}
+		result.Objects = append(result.Objects, MigratedObject{Kind: "PipelineRun", Name: run.GetName(), Action: action})
+	}
+
+	return result, nil
+}
+
+// MigrationObject returns a copy of an object to create in namespace, with
+// status, server-side fields and owner references removed
+func MigrationObject(obj *unstructured.Unstructured, namespace string) *unstructured.Unstructured {
+	migrated := obj.DeepCopy()
+	StripServerFields(migrated)
+	unstructured.RemoveNestedField(migrated.Object, "metadata", "ownerReferences")
+	migrated.SetNamespace(namespace)
+	return migrated
+}
+
+// migrateObject creates or updates an object in the target and sets its
+// status when given, returning the action taken
+func migrateObject(ctx context.Context, resource dynamic.ResourceInterface, obj *unstructured.Unstructured, status map[string]interface{}, dryRun bool) (string, error) {
+	if dryRun {
+		return MigratePlanned, nil
+	}
+
+	action := MigrateCreated
+	created, err := resource.Create(ctx, obj, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		action = MigrateUpdated
+		var existing *unstructured.Unstructured
+		if existing, err = resource.Get(ctx, obj.GetName(), metav1.GetOptions{}); err != nil {
+			return "", err
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		created, err = resource.Update(ctx, obj, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if status != nil {
+		created.Object["status"] = status
+		if _, err := resource.UpdateStatus(ctx, created, metav1.UpdateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to set status: %w", err)
+		}
+	}
+	return action, nil
+}
+
+// ensureNamespace creates a namespace if it doesn't exist
+func ensureNamespace(ctx context.Context, client dynamic.Interface, name string) error {
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName(name)
+
+	_, err := client.Resource(namespaceResource).Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// isFinishedRunPhase reports whether a PipelineRun phase is terminal
+func isFinishedRunPhase(phase string) bool {
+	switch c8sv1alpha1.PipelineRunPhase(phase) {
+	case c8sv1alpha1.PipelineRunPhaseSucceeded, c8sv1alpha1.PipelineRunPhaseFailed, c8sv1alpha1.PipelineRunPhaseCancelled:
+		return true
+	}
+	return false
+}
+
+// orUnknown returns s, or "Unknown" if it is empty
+func orUnknown(s string) string {
+	if s == "" {
+		return "Unknown"
+	}
+	return s
+}
@@ -0,0 +1,293 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/org/c8s/pkg/types"
+)
+
+const (
+	// DefaultNetworkTestImage is the image of the temporary connectivity test Pods
+	DefaultNetworkTestImage = "nicolaka/netshoot:latest"
+
+	// DefaultGitLabURL is the GitLab endpoint the webhook connectivity test reaches
+	DefaultGitLabURL = "https://gitlab.com"
+
+	// networkTestPort is the port the server test Pod listens on
+	networkTestPort = 8080
+)
+
+// NetworkCheckOptions holds options for testing connectivity inside a cluster
+type NetworkCheckOptions struct {
+	ClusterName string
+	Image       string
+
+	// TestMatrix adds the c8s-specific tests: step Pod to API server and
+	// webhook namespace to GitLab
+	TestMatrix        bool
+	StepNamespace     string
+	OperatorNamespace string
+	GitLabURL         string
+
+	// Timeout bounds Pod startup and each test
+	Timeout time.Duration
+}
+
+// NetworkTestResult is the outcome of one connectivity test
+type NetworkTestResult struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Passed      bool          `json:"passed"`
+	Output      string        `json:"output,omitempty"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// NetworkCheckResult holds the results of all connectivity tests
+type NetworkCheckResult struct {
+	Cluster string              `json:"cluster"`
+	Tests   []NetworkTestResult `json:"tests"`
+	Passed  int                 `json:"passed"`
+	Failed  int                 `json:"failed"`
+}
+
+// NetworkTestPod is a temporary Pod the connectivity tests run from or against
+type NetworkTestPod struct {
+	Name      string
+	Namespace string
+	Labels    map[string]string
+
+	// Command is the Pod command; empty keeps the Pod idle
+	Command []string
+}
+
+// NetworkProbe is a connectivity test executed inside a test Pod
+type NetworkProbe struct {
+	Name        string
+	Description string
+	Pod         NetworkTestPod
+	Command     []string
+}
+
+// NetworkTestPods returns the client and server Pods, in different
+// namespaces, plus the c8s-specific Pods when the test matrix is enabled
+func NetworkTestPods(opts NetworkCheckOptions, suffix string) (client, server NetworkTestPod, extra []NetworkTestPod) {
+	client = NetworkTestPod{
+		Name:      "nettest-client",
+		Namespace: "c8s-nettest-a-" + suffix,
+	}
+	server = NetworkTestPod{
+		Name:      "nettest-server",
+		Namespace: "c8s-nettest-b-" + suffix,
+		Command:   []string{"socat", fmt.Sprintf("TCP-LISTEN:%d,fork,reuseaddr", networkTestPort), "SYSTEM:echo ok"},
+	}
+
+	if opts.TestMatrix {
+		extra = []NetworkTestPod{
+			{
+				// Labeled like a step Pod so step NetworkPolicies apply to it
+				Name:      "nettest-step-" + suffix,
+				Namespace: opts.StepNamespace,
+				Labels: map[string]string{
+					types.LabelPipelineRun: "nettest-" + suffix,
+					types.LabelStepName:    "nettest",
+					types.LabelManaged:     types.LabelManagedValue,
+				},
+			},
+			{
+				Name:      "nettest-webhook-" + suffix,
+				Namespace: opts.OperatorNamespace,
+			},
+		}
+	}
+	return client, server, extra
+}
+
+// NetworkProbes returns the connectivity tests to run, given the test Pods and
+// the IP of the server Pod
+func NetworkProbes(opts NetworkCheckOptions, client NetworkTestPod, extra []NetworkTestPod, serverIP string) []NetworkProbe {
+	timeout := strconv.Itoa(int(opts.Timeout.Seconds()))
+
+	probes := []NetworkProbe{
+		{
+			Name:        "dns",
+			Description: "DNS resolution of kube-dns",
+			Pod:         client,
+			Command:     []string{"nslookup", "kube-dns.kube-system.svc.cluster.local"},
+		},
+		{
+			// Any HTTP response, even 401, proves the API server is reachable
+			Name:        "api-server",
+			Description: "Connectivity to the API server",
+			Pod:         client,
+			Command:     []string{"curl", "-sk", "-o", "/dev/null", "--max-time", timeout, "https://kubernetes.default.svc/version"},
+		},
+		{
+			Name:        "pod-to-pod",
+			Description: "Connectivity between Pods in different namespaces",
+			Pod:         client,
+			Command:     []string{"nc", "-z", "-w", timeout, serverIP, strconv.Itoa(networkTestPort)},
+		},
+	}
+
+	if opts.TestMatrix && len(extra) == 2 {
+		probes = append(probes,
+			NetworkProbe{
+				Name:        "step-to-api-server",
+				Description: "Step Pod to API server",
+				Pod:         extra[0],
+				Command:     []string{"curl", "-sk", "-o", "/dev/null", "--max-time", timeout, "https://kubernetes.default.svc/version"},
+			},
+			NetworkProbe{
+				Name:        "webhook-to-gitlab",
+				Description: fmt.Sprintf("Webhook namespace to GitLab (%s)", opts.GitLabURL),
+				Pod:         extra[1],
+				Command:     []string{"curl", "-s", "-o", "/dev/null", "--max-time", timeout, opts.GitLabURL},
+			},
+		)
+	}
+	return probes
+}
+
+// CheckNetwork launches temporary test Pods in the cluster and runs
+// connectivity tests from them. The Pods and test namespaces are removed
+// afterwards. A failing test is reported in the result, not as an error.
+func CheckNetwork(ctx context.Context, opts NetworkCheckOptions) (*NetworkCheckResult, error) {
+	if opts.Image == "" {
+		opts.Image = DefaultNetworkTestImage
+	}
+	if opts.GitLabURL == "" {
+		opts.GitLabURL = DefaultGitLabURL
+	}
+	if opts.StepNamespace == "" {
+		opts.StepNamespace = "default"
+	}
+	if opts.OperatorNamespace == "" {
+		opts.OperatorNamespace = "c8s-system"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, opts.ClusterName); err != nil {
+		return nil, &ClusterNotFoundError{Name: opts.ClusterName}
+	}
+
+	kubeContext := fmt.Sprintf("k3d-%s", opts.ClusterName)
+	suffix := strconv.FormatInt(time.Now().Unix(), 36)
+	client, server, extra := NetworkTestPods(opts, suffix)
+
+	// Clean up with a fresh context so an interrupted check still removes its Pods
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		for _, pod := range extra {
+			_, _ = runKubectl(cleanupCtx, kubeContext, "delete", "pod", pod.Name, "-n", pod.Namespace, "--ignore-not-found", "--wait=false")
+		}
+		for _, namespace := range []string{client.Namespace, server.Namespace} {
+			_, _ = runKubectl(cleanupCtx, kubeContext, "delete", "namespace", namespace, "--ignore-not-found", "--wait=false")
+		}
+	}()
+
+	for _, namespace := range []string{client.Namespace, server.Namespace} {
+		if _, err := runKubectl(ctx, kubeContext, "create", "namespace", namespace); err != nil {
+			return nil, fmt.Errorf("failed to create namespace %s: %w", namespace, err)
+		}
+	}
+
+	pods := append([]NetworkTestPod{client, server}, extra...)
+	for _, pod := range pods {
+		if err := startNetworkTestPod(ctx, kubeContext, opts.Image, pod); err != nil {
+			return nil, err
+		}
+	}
+	podTimeout := fmt.Sprintf("--timeout=%s", 6*opts.Timeout)
+	for _, pod := range pods {
+		if _, err := runKubectl(ctx, kubeContext, "wait", "--for=condition=Ready", "pod/"+pod.Name, "-n", pod.Namespace, podTimeout); err != nil {
+			return nil, fmt.Errorf("test pod %s/%s did not become ready: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+
+	serverIP, err := runKubectl(ctx, kubeContext, "get", "pod", server.Name, "-n", server.Namespace, "-o", "jsonpath={.status.podIP}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server pod IP: %w", err)
+	}
+
+	result := &NetworkCheckResult{Cluster: opts.ClusterName}
+	for _, probe := range NetworkProbes(opts, client, extra, strings.TrimSpace(serverIP)) {
+		test := runNetworkProbe(ctx, kubeContext, probe, opts.Timeout)
+		if test.Passed {
+			result.Passed++
+		} else {
+			result.Failed++
+		}
+		result.Tests = append(result.Tests, test)
+	}
+
+	return result, nil
+}
+
+// startNetworkTestPod creates a test Pod with kubectl run
+func startNetworkTestPod(ctx context.Context, kubeContext, image string, pod NetworkTestPod) error {
+	args := []string{"run", pod.Name, "-n", pod.Namespace, "--image", image, "--restart=Never"}
+	if len(pod.Labels) > 0 {
+		labels := make([]string, 0, len(pod.Labels))
+		for key, value := range pod.Labels {
+			labels = append(labels, key+"="+value)
+		}
+		args = append(args, "--labels", strings.Join(labels, ","))
+	}
+
+	command := pod.Command
+	if len(command) == 0 {
+		command = []string{"sleep", "3600"}
+	}
+	args = append(args, "--command", "--")
+	args = append(args, command...)
+
+	if _, err := runKubectl(ctx, kubeContext, args...); err != nil {
+		return fmt.Errorf("failed to start test pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	return nil
+}
+
+// runNetworkProbe executes a probe in its Pod; a zero exit status passes
+func runNetworkProbe(ctx context.Context, kubeContext string, probe NetworkProbe, timeout time.Duration) NetworkTestResult {
+	probeCtx, cancel := context.WithTimeout(ctx, 2*timeout)
+	defer cancel()
+
+	start := time.Now()
+	args := append([]string{"exec", probe.Pod.Name, "-n", probe.Pod.Namespace, "--"}, probe.Command...)
+	_, err := runKubectl(probeCtx, kubeContext, args...)
+
+	test := NetworkTestResult{
+		Name:        probe.Name,
+		Description: probe.Description,
+		Passed:      err == nil,
+		Duration:    time.Since(start),
+	}
+	if err != nil {
+		test.Output = err.Error()
+	}
+	return test
+}
+
+// runKubectl runs kubectl against a context and returns its stdout
+func runKubectl(ctx context.Context, kubeContext string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "kubectl", append([]string{"--context", kubeContext}, args...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
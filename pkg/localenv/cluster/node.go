@@ -0,0 +1,212 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/org/c8s/pkg/localenv"
+)
+
+// Node roles
+const (
+	NodeRoleServer = "server"
+	NodeRoleAgent  = "agent"
+)
+
+// NodeRoles are the roles of the nodes AddNodes and RemoveNode manage
+var NodeRoles = []string{NodeRoleAgent, NodeRoleServer}
+
+// DefaultNodeDrainTimeout is the time RemoveNode waits for the pods of a
+// node to be evicted
+const DefaultNodeDrainTimeout = 2 * time.Minute
+
+// ClusterNode is a k3d node container of a cluster
+type ClusterNode struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	State string `json:"state"`
+}
+
+// NodeAddOptions holds options for adding nodes to a cluster
+type NodeAddOptions struct {
+	// Role is one of NodeRoles (default agent). Server nodes can only be
+	// added to clusters created with more than one server.
+	Role  string
+	Count int
+
+	// Timeout bounds the wait for the new nodes to be ready (0 for k3d's default)
+	Timeout time.Duration
+}
+
+// NodeRemoveOptions holds options for removing a node from a cluster
+type NodeRemoveOptions struct {
+	// NoDrain removes the node without evicting its pods first, for nodes
+	// that no longer respond
+	NoDrain      bool
+	DrainTimeout time.Duration
+}
+
+// ListNodes returns the server and agent nodes of a cluster, sorted by name
+func ListNodes(ctx context.Context, clusterName string) ([]ClusterNode, error) {
+	containers, err := listClusterContainers(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	return clusterNodes(containers), nil
+}
+
+// clusterNodes returns the server and agent nodes among the containers of a
+// cluster, leaving out its load balancer and registry
+func clusterNodes(containers []dockerContainer) []ClusterNode {
+	var nodes []ClusterNode
+	for _, container := range containers {
+		role := parseDockerLabels(container.Labels)["k3d.role"]
+		if role != NodeRoleServer && role != NodeRoleAgent {
+			continue
+		}
+		nodes = append(nodes, ClusterNode{Name: container.Names, Role: role, State: container.State})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes
+}
+
+// AddNodes adds Count nodes of a role to a running cluster and returns the
+// names of the new nodes
+func AddNodes(ctx context.Context, clusterName string, opts NodeAddOptions) ([]string, error) {
+	if opts.Role == "" {
+		opts.Role = NodeRoleAgent
+	}
+	if opts.Role != NodeRoleAgent && opts.Role != NodeRoleServer {
+		return nil, fmt.Errorf("invalid node type %q (valid: %s)", opts.Role, strings.Join(NodeRoles, ", "))
+	}
+	if opts.Count < 1 {
+		return nil, fmt.Errorf("node count must be at least 1")
+	}
+
+	k3dClient := NewK3dClient()
+	info, err := k3dClient.Get(ctx, clusterName)
+	if err != nil {
+		return nil, &ClusterNotFoundError{Name: clusterName}
+	}
+	if state := determineClusterState(info); state != localenv.StateRunning {
+		return nil, fmt.Errorf("cluster %s is %s, start it with: c8s dev cluster start %s", clusterName, state, clusterName)
+	}
+
+	before, err := ListNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]string, len(before))
+	for i, node := range before {
+		existing[i] = node.Name
+	}
+
+	err = k3dClient.CreateNodes(ctx, clusterName, &NodeCreateConfig{
+		Name:        NewNodeName(clusterName, opts.Role, existing),
+		Role:        opts.Role,
+		Replicas:    opts.Count,
+		WaitTimeout: opts.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s nodes: %w", opts.Role, err)
+	}
+
+	after, err := ListNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var added []string
+	for _, node := range after {
+		if !slices.Contains(existing, node.Name) {
+			added = append(added, node.Name)
+		}
+	}
+	return added, nil
+}
+
+// NewNodeName returns the base name k3d names new nodes of a role after:
+// "<cluster>-<role>-<n>" with the lowest n no existing node name uses. k3d
+// prefixes it with "k3d-" and suffixes each replica with its index.
+func NewNodeName(clusterName, role string, existing []string) string {
+	for n := 0; ; n++ {
+		name := fmt.Sprintf("%s-%s-%d", clusterName, role, n)
+		used := false
+		for _, node := range existing {
+			if node == "k3d-"+name || strings.HasPrefix(node, "k3d-"+name+"-") {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return name
+		}
+	}
+}
+
+// RemoveNode drains a node of a cluster, unless NoDrain is set, then deletes
+// its container and its Node object. The last server node can't be removed.
+func RemoveNode(ctx context.Context, clusterName, nodeName string, opts NodeRemoveOptions) error {
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, clusterName); err != nil {
+		return &ClusterNotFoundError{Name: clusterName}
+	}
+
+	nodes, err := ListNodes(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	node, err := findNode(nodes, nodeName)
+	if err != nil {
+		return fmt.Errorf("%w in cluster %s", err, clusterName)
+	}
+	if node.Role == NodeRoleServer && countNodes(nodes, NodeRoleServer) == 1 {
+		return fmt.Errorf("node %s is the last server of cluster %s", node.Name, clusterName)
+	}
+
+	kubeContext := KubeContextName(clusterName)
+	if !opts.NoDrain {
+		timeout := opts.DrainTimeout
+		if timeout == 0 {
+			timeout = DefaultNodeDrainTimeout
+		}
+		if _, err := runKubectl(ctx, kubeContext, "drain", node.Name,
+			"--ignore-daemonsets", "--delete-emptydir-data", "--timeout", timeout.String()); err != nil {
+			return fmt.Errorf("failed to drain node %s (use --no-drain to remove it anyway): %w", node.Name, err)
+		}
+	}
+
+	if err := k3dClient.DeleteNode(ctx, node.Name); err != nil {
+		return fmt.Errorf("failed to delete node %s: %w", node.Name, err)
+	}
+
+	// k3d leaves the Node object behind, NotReady
+	if _, err := runKubectl(ctx, kubeContext, "delete", "node", node.Name, "--ignore-not-found"); err != nil {
+		return fmt.Errorf("node %s removed, but failed to delete its Node object: %w", node.Name, err)
+	}
+	return nil
+}
+
+// findNode returns the node named name, with or without the "k3d-" prefix
+func findNode(nodes []ClusterNode, name string) (ClusterNode, error) {
+	for _, node := range nodes {
+		if node.Name == name || node.Name == "k3d-"+name {
+			return node, nil
+		}
+	}
+	return ClusterNode{}, fmt.Errorf("node %s not found", name)
+}
+
+// countNodes returns the number of nodes of a role
+func countNodes(nodes []ClusterNode, role string) int {
+	count := 0
+	for _, node := range nodes {
+		if node.Role == role {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,171 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// AnnotationPrePauseReplicas records the replica count of a Deployment
+// scaled to zero by Pause, so Resume can restore it
+const AnnotationPrePauseReplicas = "c8s.dev/pre-pause-replicas"
+
+// DefaultPauseNamespaces are the namespaces of the c8s components suspended by Pause
+var DefaultPauseNamespaces = []string{"c8s-system"}
+
+// PauseOptions holds options for pausing or resuming the workloads of a cluster
+type PauseOptions struct {
+	Name       string
+	Namespaces []string // Namespaces whose Deployments are scaled (default DefaultPauseNamespaces)
+}
+
+// PauseResult lists the Deployments scaled by Pause or Resume as "namespace/name"
+type PauseResult struct {
+	Deployments []string `json:"deployments"`
+}
+
+// Pause scales every Deployment in the c8s namespaces to zero replicas,
+// keeping the cluster and its Kubernetes API running
+func Pause(ctx context.Context, opts PauseOptions) (*PauseResult, error) {
+	client, err := pauseClient(ctx, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := PauseDeployments(ctx, client, pauseNamespaces(opts))
+	return &PauseResult{Deployments: deployments}, err
+}
+
+// Resume restores the replicas of the Deployments scaled down by Pause
+func Resume(ctx context.Context, opts PauseOptions) (*PauseResult, error) {
+	client, err := pauseClient(ctx, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := ResumeDeployments(ctx, client, pauseNamespaces(opts))
+	return &PauseResult{Deployments: deployments}, err
+}
+
+// pauseClient returns a dynamic client for an existing cluster
+func pauseClient(ctx context.Context, clusterName string) (dynamic.Interface, error) {
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, clusterName); err != nil {
+		return nil, &ClusterNotFoundError{Name: clusterName}
+	}
+	return newDynamicClient(clusterName)
+}
+
+// pauseNamespaces returns the namespaces of opts, defaulting to DefaultPauseNamespaces
+func pauseNamespaces(opts PauseOptions) []string {
+	if len(opts.Namespaces) == 0 {
+		return DefaultPauseNamespaces
+	}
+	return opts.Namespaces
+}
+
+// PauseDeployments scales the Deployments of namespaces to zero, recording
+// their replicas in AnnotationPrePauseReplicas. Deployments that are already
+// paused are left unchanged.
+func PauseDeployments(ctx context.Context, client dynamic.Interface, namespaces []string) ([]string, error) {
+	var paused []string
+	for _, namespace := range namespaces {
+		list, err := client.Resource(deploymentResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return paused, fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+		}
+
+		for _, deployment := range list.Items {
+			if _, exists := deployment.GetAnnotations()[AnnotationPrePauseReplicas]; exists {
+				continue
+			}
+
+			// Deployments default to one replica when unset
+			replicas, found, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+			if !found {
+				replicas = 1
+			}
+
+			patch := map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{AnnotationPrePauseReplicas: strconv.FormatInt(replicas, 10)},
+				},
+				"spec": map[string]interface{}{"replicas": 0},
+			}
+			if err := patchDeployment(ctx, client, namespace, deployment.GetName(), patch); err != nil {
+				return paused, err
+			}
+			paused = append(paused, fmt.Sprintf("%s/%s", namespace, deployment.GetName()))
+		}
+	}
+	return paused, nil
+}
+
+// ResumeDeployments restores the replicas recorded by PauseDeployments and
+// removes the annotation
+func ResumeDeployments(ctx context.Context, client dynamic.Interface, namespaces []string) ([]string, error) {
+	var resumed []string
+	for _, namespace := range namespaces {
+		list, err := client.Resource(deploymentResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return resumed, fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+		}
+
+		for _, deployment := range list.Items {
+			value, exists := deployment.GetAnnotations()[AnnotationPrePauseReplicas]
+			if !exists {
+				continue
+			}
+			replicas, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return resumed, fmt.Errorf("invalid %s annotation on deployment %s/%s: %q", AnnotationPrePauseReplicas, namespace, deployment.GetName(), value)
+			}
+
+			patch := map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{AnnotationPrePauseReplicas: nil},
+				},
+				"spec": map[string]interface{}{"replicas": replicas},
+			}
+			if err := patchDeployment(ctx, client, namespace, deployment.GetName(), patch); err != nil {
+				return resumed, err
+			}
+			resumed = append(resumed, fmt.Sprintf("%s/%s", namespace, deployment.GetName()))
+		}
+	}
+	return resumed, nil
+}
+
+// IsPaused reports whether any Deployment of namespaces was paused by PauseDeployments
+func IsPaused(ctx context.Context, client dynamic.Interface, namespaces []string) (bool, error) {
+	for _, namespace := range namespaces {
+		list, err := client.Resource(deploymentResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+		}
+		for _, deployment := range list.Items {
+			if _, exists := deployment.GetAnnotations()[AnnotationPrePauseReplicas]; exists {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// patchDeployment applies a JSON merge patch to a Deployment
+func patchDeployment(ctx context.Context, client dynamic.Interface, namespace, name string, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Resource(deploymentResource).Namespace(namespace).Patch(ctx, name, k8stypes.MergePatchType, data, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to patch deployment %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
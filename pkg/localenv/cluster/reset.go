@@ -0,0 +1,272 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/storage"
+	"github.com/org/c8s/pkg/types"
+)
+
+var (
+	pipelineRunResource    = c8sv1alpha1.GroupVersion.WithResource("pipelineruns")
+	pipelineConfigResource = c8sv1alpha1.GroupVersion.WithResource("pipelineconfigs")
+	jobResource            = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	podResource            = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	deploymentResource     = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+)
+
+// ResetOptions holds options for resetting the operator state of a cluster
+type ResetOptions struct {
+	Name              string
+	Namespace         string // Namespace to reset (empty for all namespaces)
+	OperatorNamespace string // Namespace of the operator Deployment (default c8s-system)
+	OperatorName      string // Name of the operator Deployment (default c8s-controller)
+	FlushLogs         bool   // Delete stored logs under the namespace prefix
+	Storage           storage.StorageClient
+	Timeout           time.Duration
+}
+
+// ResetResult summarizes what was removed during a reset
+type ResetResult struct {
+	PipelineRuns      int
+	PipelineConfigs   int
+	Jobs              int
+	Pods              int
+	LogObjects        int
+	OperatorRecreated bool
+}
+
+// Reset removes all pipeline state from a cluster and recreates the operator
+// Deployment, without deleting the cluster itself
+func Reset(ctx context.Context, opts ResetOptions) (*ResetResult, error) {
+	if opts.OperatorNamespace == "" {
+		opts.OperatorNamespace = "c8s-system"
+	}
+	if opts.OperatorName == "" {
+		opts.OperatorName = "c8s-controller"
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 3 * time.Minute
+	}
+	if opts.FlushLogs && opts.Storage == nil {
+		return nil, fmt.Errorf("log storage is not configured")
+	}
+
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, opts.Name); err != nil {
+		return nil, &ClusterNotFoundError{Name: opts.Name}
+	}
+
+	client, err := newDynamicClient(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ResetResult{}
+	selector := fmt.Sprintf("%s=%s", types.LabelManaged, types.LabelManagedValue)
+
+	// PipelineRun finalizers are removed first so deletion does not depend on
+	// a healthy operator, which is usually why a reset is needed
+	if result.PipelineRuns, err = deleteResources(ctx, client, pipelineRunResource, opts.Namespace, "", true); err != nil {
+		return result, fmt.Errorf("failed to delete PipelineRuns: %w", err)
+	}
+	if result.PipelineConfigs, err = deleteResources(ctx, client, pipelineConfigResource, opts.Namespace, "", false); err != nil {
+		return result, fmt.Errorf("failed to delete PipelineConfigs: %w", err)
+	}
+	if result.Jobs, err = deleteResources(ctx, client, jobResource, opts.Namespace, selector, false); err != nil {
+		return result, fmt.Errorf("failed to delete Jobs: %w", err)
+	}
+	if result.Pods, err = deleteResources(ctx, client, podResource, opts.Namespace, selector, false); err != nil {
+		return result, fmt.Errorf("failed to delete Pods: %w", err)
+	}
+
+	if err := recreateDeployment(ctx, client, opts.OperatorNamespace, opts.OperatorName, opts.Timeout); err != nil {
+		return result, err
+	}
+	result.OperatorRecreated = true
+
+	if opts.FlushLogs {
+		if result.LogObjects, err = flushLogs(ctx, opts.Storage, opts.Namespace); err != nil {
+			return result, fmt.Errorf("failed to flush logs: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// newDynamicClient creates a dynamic client for the k3d context of a cluster
+func newDynamicClient(clusterName string) (dynamic.Interface, error) {
+	restConfig, err := clusterRESTConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return client, nil
+}
+
+// clusterRESTConfig loads the client config of the k3d context of a cluster
+func clusterRESTConfig(clusterName string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: fmt.Sprintf("k3d-%s", clusterName)}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return restConfig, nil
+}
+
+// deleteResources deletes all objects of a resource matching the label selector
+// and returns the number of objects deleted
+func deleteResources(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace, selector string, removeFinalizers bool) (int, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// CRDs not installed
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	deleted := 0
+	for _, item := range list.Items {
+		resource := client.Resource(gvr).Namespace(item.GetNamespace())
+
+		if removeFinalizers && len(item.GetFinalizers()) > 0 {
+			patch := []byte(`{"metadata":{"finalizers":null}}`)
+			if _, err := resource.Patch(ctx, item.GetName(), k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return deleted, fmt.Errorf("failed to remove finalizers from %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+			}
+		}
+
+		err := resource.Delete(ctx, item.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to delete %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// recreateDeployment deletes a Deployment and creates it again from its
+// current spec, then waits for it to become available
+func recreateDeployment(ctx context.Context, client dynamic.Interface, namespace, name string, timeout time.Duration) error {
+	deployments := client.Resource(deploymentResource).Namespace(namespace)
+
+	current, err := deployments.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("operator deployment %s/%s not found (run 'c8s dev deploy operator' first)", namespace, name)
+		}
+		return fmt.Errorf("failed to get operator deployment: %w", err)
+	}
+
+	fresh := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": current.GetAPIVersion(),
+		"kind":       current.GetKind(),
+		"metadata": map[string]interface{}{
+			"name":      current.GetName(),
+			"namespace": current.GetNamespace(),
+		},
+	}}
+	fresh.SetLabels(current.GetLabels())
+	annotations := current.GetAnnotations()
+	delete(annotations, "deployment.kubernetes.io/revision")
+	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	fresh.SetAnnotations(annotations)
+	if spec, found, _ := unstructured.NestedMap(current.Object, "spec"); found {
+		_ = unstructured.SetNestedMap(fresh.Object, spec, "spec")
+	}
+
+	propagation := metav1.DeletePropagationForeground
+	if err := deployments.Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete operator deployment: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Wait for the old Deployment (and its Pods) to be gone
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		if _, err := deployments.Get(ctx, name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w waiting for operator deployment to be deleted after %s", types.ErrTimeoutExceeded, timeout)
+		case <-ticker.C:
+		}
+	}
+
+	if _, err := deployments.Create(ctx, fresh, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to recreate operator deployment: %w", err)
+	}
+
+	// Wait for the new Deployment to report all replicas available
+	for {
+		deployment, err := deployments.Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			replicas, found, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+			if !found {
+				replicas = 1
+			}
+			available, _, _ := unstructured.NestedInt64(deployment.Object, "status", "availableReplicas")
+			if available >= replicas {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w waiting for operator deployment to become available after %s", types.ErrTimeoutExceeded, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// flushLogs deletes all stored logs for a namespace (or all namespaces when empty).
+// The controller stores logs as "{namespace}/{pipeline-run}/{step}.log", so only
+// ".log" objects under the namespace prefix are deleted.
+func flushLogs(ctx context.Context, client storage.StorageClient, namespace string) (int, error) {
+	prefix := ""
+	if namespace != "" {
+		prefix = strings.Trim(namespace, "/") + "/"
+	}
+
+	keys, err := client.ListObjects(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".log") {
+			continue
+		}
+		if err := client.DeleteObject(ctx, key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
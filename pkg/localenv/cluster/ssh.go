@@ -0,0 +1,104 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/org/c8s/pkg/localenv"
+)
+
+// NodeShellOptions holds options for opening a shell in a cluster node
+type NodeShellOptions struct {
+	Cluster string
+	Node    string // Node name (e.g. "server-0" or "k3d-c8s-dev-server-0"); empty for the first server
+	Command string // Command to run non-interactively; empty for an interactive shell
+	Shell   string // Shell inside the node container (default /bin/sh)
+}
+
+// ListNodeNames returns the node names of a running cluster
+func ListNodeNames(ctx context.Context, clusterName string) ([]string, error) {
+	nodes, err := getRunningNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	return names, nil
+}
+
+// NodeShell opens a shell in a k3d node container with docker exec, attached
+// to the current terminal. With a command, the command is run and its exit
+// code is returned as an *exec.ExitError.
+func NodeShell(ctx context.Context, opts NodeShellOptions) error {
+	if opts.Shell == "" {
+		opts.Shell = "/bin/sh"
+	}
+
+	nodes, err := getRunningNodes(ctx, opts.Cluster)
+	if err != nil {
+		return err
+	}
+
+	container, err := ResolveNodeContainer(opts.Cluster, opts.Node, nodes)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"exec"}
+	if opts.Command == "" {
+		args = append(args, "-it", container, opts.Shell)
+	} else {
+		args = append(args, "-i", container, opts.Shell, "-c", opts.Command)
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// ResolveNodeContainer returns the docker container of a cluster node. The node
+// may be given by its full name or by the suffix after "k3d-{cluster}-"; an
+// empty node selects the first server node.
+func ResolveNodeContainer(clusterName, node string, nodes []localenv.NodeStatus) (string, error) {
+	if node == "" {
+		for _, n := range nodes {
+			if n.Role == "server" {
+				return n.Name, nil
+			}
+		}
+		return "", fmt.Errorf("no server node found in cluster '%s'", clusterName)
+	}
+
+	prefix := fmt.Sprintf("k3d-%s-", clusterName)
+	for _, n := range nodes {
+		if n.Name == node || n.Name == prefix+node {
+			return n.Name, nil
+		}
+	}
+
+	names := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		names = append(names, strings.TrimPrefix(n.Name, prefix))
+	}
+	return "", fmt.Errorf("node '%s' not found in cluster '%s' (available: %s)", node, clusterName, strings.Join(names, ", "))
+}
+
+// getRunningNodes returns the nodes of a cluster, failing if it is not running
+func getRunningNodes(ctx context.Context, clusterName string) ([]localenv.NodeStatus, error) {
+	status, err := GetStatusWithUptime(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if !status.IsRunning() {
+		return nil, fmt.Errorf("cluster '%s' is not running (state: %s)", clusterName, status.State)
+	}
+	return status.Nodes, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/org/c8s/pkg/localenv"
+	"github.com/org/c8s/pkg/types"
 )
 
 // GetStatus retrieves the status of a cluster
@@ -48,6 +49,16 @@ func GetStatus(ctx context.Context, clusterName string) (*localenv.ClusterStatus
 
 		// Get kubeconfig context
 		status.Kubeconfig = fmt.Sprintf("k3d-%s", clusterName)
+
+		// Report workloads suspended by Pause and the installed addons
+		if client, err := newDynamicClient(clusterName); err == nil {
+			if paused, err := IsPaused(ctx, client, DefaultPauseNamespaces); err == nil && paused {
+				status.State = localenv.StatePaused
+			}
+			if addons, err := InstalledAddonNames(ctx, client); err == nil {
+				status.Addons = addons
+			}
+		}
 	}
 
 	return status, nil
@@ -110,6 +121,11 @@ func (e *ClusterNotFoundError) Error() string {
 	return fmt.Sprintf("cluster '%s' not found", e.Name)
 }
 
+// Unwrap makes errors.Is match types.ErrClusterNotFound
+func (e *ClusterNotFoundError) Unwrap() error {
+	return types.ErrClusterNotFound
+}
+
 // WaitForReady waits for a cluster to become ready
 func WaitForReady(ctx context.Context, clusterName string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
@@ -122,7 +138,7 @@ func WaitForReady(ctx context.Context, clusterName string, timeout time.Duration
 			return ctx.Err()
 		case <-ticker.C:
 			if time.Now().After(deadline) {
-				return fmt.Errorf("timeout waiting for cluster '%s' to be ready", clusterName)
+				return fmt.Errorf("%w waiting for cluster '%s' to be ready", types.ErrTimeoutExceeded, clusterName)
 			}
 
 			status, err := GetStatus(ctx, clusterName)
@@ -0,0 +1,239 @@
+package cluster
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// linuxTrustDir holds the local CA certificates update-ca-certificates
+	// adds to /etc/ssl/certs
+	linuxTrustDir = "/usr/local/share/ca-certificates"
+
+	// macOSSystemKeychain is the Keychain the CA certificate is trusted in
+	macOSSystemKeychain = "/Library/Keychains/System.keychain"
+
+	// annotationRestartedAt is the Pod template annotation set by
+	// 'kubectl rollout restart'
+	annotationRestartedAt = "kubectl.kubernetes.io/restartedAt"
+)
+
+// TrustOptions holds options for trusting the CA certificate of a cluster
+type TrustOptions struct {
+	Name string
+
+	// Untrust removes the certificate from the trust store instead
+	Untrust bool
+
+	// Namespaces whose Deployments are restarted (default DefaultPauseNamespaces)
+	Namespaces []string
+}
+
+// TrustResult describes a CA certificate added to or removed from the OS
+// trust store
+type TrustResult struct {
+	Cluster     string   `json:"cluster"`
+	Trusted     bool     `json:"trusted"`
+	Store       string   `json:"store"`
+	Fingerprint string   `json:"fingerprint"`
+	Restarted   []string `json:"restarted,omitempty"`
+}
+
+// Trust adds the CA certificate of a cluster's kubeconfig to the trust store
+// of the OS (/etc/ssl/certs on Linux, the System Keychain on macOS), or
+// removes it with opts.Untrust, then restarts the c8s Deployments so they
+// don't keep TLS state from before the change. Modifying the trust store
+// runs through sudo unless the current user is root.
+func Trust(ctx context.Context, opts TrustOptions) (*TrustResult, error) {
+	k3dClient := NewK3dClient()
+	if _, err := k3dClient.Get(ctx, opts.Name); err != nil {
+		return nil, &ClusterNotFoundError{Name: opts.Name}
+	}
+
+	config, err := clientcmd.NewDefaultPathOptions().GetStartingConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	caData, err := ClusterCACertificate(config, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := CertificateFingerprint(caData)
+	if err != nil {
+		return nil, err
+	}
+
+	certPath, err := TrustCertPath(runtime.GOOS, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The certificate is staged in a temporary file the trust store commands
+	// copy or read it from
+	staged, err := os.CreateTemp("", "c8s-ca-*.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(staged.Name())
+	if _, err := staged.Write(caData); err != nil {
+		staged.Close()
+		return nil, fmt.Errorf("failed to write %s: %w", staged.Name(), err)
+	}
+	if err := staged.Close(); err != nil {
+		return nil, err
+	}
+
+	commands, err := TrustCommands(runtime.GOOS, staged.Name(), certPath, fingerprint, opts.Untrust)
+	if err != nil {
+		return nil, err
+	}
+	for _, args := range commands {
+		if os.Geteuid() != 0 {
+			args = append([]string{"sudo"}, args...)
+		}
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdin = os.Stdin
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("%s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	result := &TrustResult{Cluster: opts.Name, Trusted: !opts.Untrust, Store: certPath, Fingerprint: fingerprint}
+
+	client, err := newDynamicClient(opts.Name)
+	if err != nil {
+		return result, err
+	}
+	result.Restarted, err = RestartDeployments(ctx, client, pauseNamespaces(PauseOptions{Namespaces: opts.Namespaces}))
+	return result, err
+}
+
+// ClusterCACertificate returns the PEM-encoded CA certificate of a cluster
+// from its kubeconfig context, embedded or referenced by path
+func ClusterCACertificate(config *clientcmdapi.Config, clusterName string) ([]byte, error) {
+	contextName := KubeContextName(clusterName)
+	kubeContext, ok := config.Contexts[contextName]
+	if !ok {
+		return nil, fmt.Errorf("context %s not found in kubeconfig", contextName)
+	}
+	kubeCluster, ok := config.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s of context %s not found in kubeconfig", kubeContext.Cluster, contextName)
+	}
+
+	data := kubeCluster.CertificateAuthorityData
+	if len(data) == 0 && kubeCluster.CertificateAuthority != "" {
+		var err error
+		if data, err = os.ReadFile(kubeCluster.CertificateAuthority); err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("context %s has no CA certificate", contextName)
+	}
+	if block, _ := pem.Decode(data); block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("CA certificate of context %s is not a PEM certificate", contextName)
+	}
+	return data, nil
+}
+
+// CertificateFingerprint returns the SHA-1 fingerprint of the first
+// certificate of PEM data, in the uppercase hex form the macOS security
+// tool expects
+func CertificateFingerprint(data []byte) (string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", fmt.Errorf("no PEM certificate found")
+	}
+	sum := sha1.Sum(block.Bytes)
+	return strings.ToUpper(hex.EncodeToString(sum[:])), nil
+}
+
+// TrustCertPath returns where the CA certificate of a cluster is installed
+// on an OS: a file of the local CA directory on Linux, the System Keychain
+// on macOS
+func TrustCertPath(goos, clusterName string) (string, error) {
+	switch goos {
+	case "linux":
+		return filepath.Join(linuxTrustDir, fmt.Sprintf("c8s-%s.crt", clusterName)), nil
+	case "darwin":
+		return macOSSystemKeychain, nil
+	default:
+		return "", fmt.Errorf("trusting cluster certificates is not supported on %s", goos)
+	}
+}
+
+// TrustCommands returns the commands adding the certificate staged at
+// certFile to the trust store at storePath, or removing it with untrust
+func TrustCommands(goos, certFile, storePath, fingerprint string, untrust bool) ([][]string, error) {
+	switch goos {
+	case "linux":
+		if untrust {
+			return [][]string{
+				{"rm", "-f", storePath},
+				{"update-ca-certificates", "--fresh"},
+			}, nil
+		}
+		return [][]string{
+			{"install", "-m", "0644", certFile, storePath},
+			{"update-ca-certificates"},
+		}, nil
+	case "darwin":
+		if untrust {
+			return [][]string{
+				{"security", "remove-trusted-cert", "-d", certFile},
+				{"security", "delete-certificate", "-Z", fingerprint, storePath},
+			}, nil
+		}
+		return [][]string{
+			{"security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", storePath, certFile},
+		}, nil
+	default:
+		return nil, fmt.Errorf("trusting cluster certificates is not supported on %s", goos)
+	}
+}
+
+// RestartDeployments triggers a rollout restart of every Deployment of
+// namespaces, the same way 'kubectl rollout restart' does
+func RestartDeployments(ctx context.Context, client dynamic.Interface, namespaces []string) ([]string, error) {
+	restartedAt := time.Now().Format(time.RFC3339)
+
+	var restarted []string
+	for _, namespace := range namespaces {
+		list, err := client.Resource(deploymentResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return restarted, fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+		}
+
+		for _, deployment := range list.Items {
+			patch := map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"annotations": map[string]interface{}{annotationRestartedAt: restartedAt},
+						},
+					},
+				},
+			}
+			if err := patchDeployment(ctx, client, namespace, deployment.GetName(), patch); err != nil {
+				return restarted, err
+			}
+			restarted = append(restarted, fmt.Sprintf("%s/%s", namespace, deployment.GetName()))
+		}
+	}
+	return restarted, nil
+}
@@ -0,0 +1,150 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// DefaultWaitInterval is the time between two checks of a cluster being waited on
+const DefaultWaitInterval = 5 * time.Second
+
+// WaitCondition is an additional check a running cluster must pass to be
+// considered ready
+type WaitCondition string
+
+const (
+	// ConditionAPIServerHealthy requires GET /healthz on the API server to succeed
+	ConditionAPIServerHealthy WaitCondition = "api-server-healthy"
+
+	// ConditionCRDsInstalled requires the c8s CRDs to be served
+	ConditionCRDsInstalled WaitCondition = "crds-installed"
+)
+
+// WaitConditions are the supported wait conditions
+var WaitConditions = []WaitCondition{ConditionAPIServerHealthy, ConditionCRDsInstalled}
+
+// c8sResources are the resources served once the c8s CRDs are installed
+var c8sResources = []string{"pipelineconfigs", "pipelineruns", "repositoryconnections"}
+
+// WaitOptions holds options for waiting until a cluster is ready
+type WaitOptions struct {
+	Name     string
+	Timeout  time.Duration
+	Interval time.Duration
+
+	// Conditions must all pass once the cluster is running
+	Conditions []WaitCondition
+}
+
+// ParseWaitCondition returns the wait condition with the given name
+func ParseWaitCondition(name string) (WaitCondition, error) {
+	for _, condition := range WaitConditions {
+		if string(condition) == name {
+			return condition, nil
+		}
+	}
+
+	names := make([]string, 0, len(WaitConditions))
+	for _, condition := range WaitConditions {
+		names = append(names, string(condition))
+	}
+	return "", fmt.Errorf("unknown condition %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Wait blocks until a cluster is running and passes every condition of opts,
+// checking every opts.Interval. The error on timeout gives the reason the
+// cluster was last found not ready.
+func Wait(ctx context.Context, opts WaitOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWaitInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		reason := checkReady(ctx, opts)
+		if reason == "" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w waiting for cluster '%s' to be ready: %s", types.ErrTimeoutExceeded, opts.Name, reason)
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkReady returns why a cluster is not ready, or "" if it is
+func checkReady(ctx context.Context, opts WaitOptions) string {
+	status, err := GetStatusWithUptime(ctx, opts.Name)
+	if err != nil {
+		return err.Error()
+	}
+	if !status.IsRunning() {
+		return fmt.Sprintf("cluster is %s", status.State)
+	}
+	if len(opts.Conditions) == 0 {
+		return ""
+	}
+
+	restConfig, err := clusterRESTConfig(opts.Name)
+	if err != nil {
+		return err.Error()
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Sprintf("failed to create kubernetes client: %v", err)
+	}
+
+	for _, condition := range opts.Conditions {
+		if err := CheckWaitCondition(ctx, client, condition); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// CheckWaitCondition returns an error if a cluster does not pass a condition
+func CheckWaitCondition(ctx context.Context, client kubernetes.Interface, condition WaitCondition) error {
+	switch condition {
+	case ConditionAPIServerHealthy:
+		body, err := client.Discovery().RESTClient().Get().AbsPath("/healthz").DoRaw(ctx)
+		if err != nil {
+			return fmt.Errorf("API server is not healthy: %w", err)
+		}
+		if strings.TrimSpace(string(body)) != "ok" {
+			return fmt.Errorf("API server is not healthy: %s", body)
+		}
+		return nil
+	case ConditionCRDsInstalled:
+		resources, err := client.Discovery().ServerResourcesForGroupVersion(c8sv1alpha1.GroupVersion.String())
+		if err != nil {
+			return fmt.Errorf("c8s CRDs are not installed: %w", err)
+		}
+		served := make(map[string]bool, len(resources.APIResources))
+		for _, resource := range resources.APIResources {
+			served[resource.Name] = true
+		}
+		for _, name := range c8sResources {
+			if !served[name] {
+				return fmt.Errorf("CRD %s.%s is not installed", name, c8sv1alpha1.GroupVersion.Group)
+			}
+		}
+		return nil
+	default:
+		_, err := ParseWaitCondition(string(condition))
+		return err
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/org/c8s/pkg/types"
 )
 
 // Workload represents an active workload in the cluster
@@ -221,7 +223,7 @@ func WaitForWorkloadsCompletion(namespace string, timeout time.Duration) error {
 		}
 
 		if time.Since(startTime) > timeout {
-			return fmt.Errorf("timeout waiting for workloads to complete after %v", timeout)
+			return fmt.Errorf("%w waiting for workloads to complete after %v", types.ErrTimeoutExceeded, timeout)
 		}
 
 		time.Sleep(checkInterval)
@@ -0,0 +1,143 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// pipelineFileFields are the PipelineConfig spec fields defined by a pipeline
+// file. Other fields, such as the repository, only exist in the cluster and
+// are kept when a pipeline file is applied.
+var pipelineFileFields = []string{"steps", "timeout", "defaultStepTimeout", "matrix", "retryPolicy", "globalMaxLogSizeMB"}
+
+// PipelineDiff is the difference between a local pipeline file and the
+// PipelineConfig deployed with the same name
+type PipelineDiff struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Deployed is false when no PipelineConfig with the name exists
+	Deployed bool            `json:"deployed"`
+	Changes  []parser.Change `json:"changes,omitempty"`
+}
+
+// HasRemovals reports whether applying the local file would remove steps
+func (d *PipelineDiff) HasRemovals() bool {
+	for _, change := range d.Changes {
+		if change.Kind == parser.ChangeStepRemoved {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadPipelineFile parses a pipeline file into a PipelineConfig. The name
+// defaults to the name field of the file.
+func LoadPipelineFile(path, name, namespace string) (*c8sv1alpha1.PipelineConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := parser.ParseBytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if name == "" {
+		var header struct {
+			Name string `yaml:"name"`
+		}
+		if err := yaml.Unmarshal(content, &header); err != nil || header.Name == "" {
+			return nil, fmt.Errorf("%s has no name; set the PipelineConfig name explicitly", path)
+		}
+		name = header.Name
+	}
+
+	return &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       *spec,
+	}, nil
+}
+
+// DiffDeployedConfig compares a local PipelineConfig with the deployed one
+// of the same name. A config that is not deployed is reported with every
+// local step added.
+func DiffDeployedConfig(ctx context.Context, client dynamic.Interface, local *c8sv1alpha1.PipelineConfig) (*PipelineDiff, error) {
+	diff := &PipelineDiff{Name: local.Name, Namespace: local.Namespace}
+
+	deployed, err := getPipelineConfig(ctx, client, local.Namespace, local.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	var deployedSpec *c8sv1alpha1.PipelineConfigSpec
+	if deployed != nil {
+		config := &c8sv1alpha1.PipelineConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(deployed.Object, config); err != nil {
+			return nil, fmt.Errorf("failed to decode PipelineConfig %s/%s: %w", local.Namespace, local.Name, err)
+		}
+		diff.Deployed = true
+		deployedSpec = &config.Spec
+	}
+
+	diff.Changes = parser.Diff(deployedSpec, &local.Spec)
+	return diff, nil
+}
+
+// ApplyPipelineFile updates the deployed PipelineConfig with the fields
+// defined by a local pipeline file. The config must already exist, since a
+// pipeline file does not define the repository it builds.
+func ApplyPipelineFile(ctx context.Context, client dynamic.Interface, local *c8sv1alpha1.PipelineConfig) error {
+	deployed, err := getPipelineConfig(ctx, client, local.Namespace, local.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("PipelineConfig %s/%s does not exist; create it before applying a pipeline file", local.Namespace, local.Name)
+		}
+		return err
+	}
+
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&local.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode PipelineConfig spec: %w", err)
+	}
+	for _, field := range pipelineFileFields {
+		if value, ok := spec[field]; ok {
+			if err := unstructured.SetNestedField(deployed.Object, value, "spec", field); err != nil {
+				return err
+			}
+		} else {
+			unstructured.RemoveNestedField(deployed.Object, "spec", field)
+		}
+	}
+
+	if _, err := client.Resource(c8sv1alpha1.GroupVersion.WithResource("pipelineconfigs")).
+		Namespace(local.Namespace).Update(ctx, deployed, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update PipelineConfig %s/%s: %w", local.Namespace, local.Name, err)
+	}
+	return nil
+}
+
+// getPipelineConfig returns a deployed PipelineConfig
+func getPipelineConfig(ctx context.Context, client dynamic.Interface, namespace, name string) (*unstructured.Unstructured, error) {
+	config, err := client.Resource(c8sv1alpha1.GroupVersion.WithResource("pipelineconfigs")).
+		Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get PipelineConfig %s/%s: %w", namespace, name, err)
+	}
+	return config, nil
+}
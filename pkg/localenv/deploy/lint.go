@@ -0,0 +1,66 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// ConfigValidation is the result of validating a deployed PipelineConfig
+type ConfigValidation struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Valid     bool     `json:"valid"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// ValidateDeployedConfigs runs parser.Validate on every PipelineConfig of a
+// namespace. Configs created before a validation rule was added are reported
+// as invalid even though the API server accepted them.
+func ValidateDeployedConfigs(ctx context.Context, client dynamic.Interface, namespace string) ([]ConfigValidation, error) {
+	list, err := client.Resource(c8sv1alpha1.GroupVersion.WithResource("pipelineconfigs")).
+		Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PipelineConfigs: %w", err)
+	}
+
+	results := make([]ConfigValidation, 0, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		result := ConfigValidation{Name: item.GetName(), Namespace: item.GetNamespace(), Valid: true}
+
+		config := &c8sv1alpha1.PipelineConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, config); err != nil {
+			result.Valid = false
+			result.Errors = []string{fmt.Sprintf("failed to decode: %v", err)}
+		} else if err := parser.Validate(config); err != nil {
+			result.Valid = false
+			result.Errors = validationMessages(err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// validationMessages splits a validation error into one message per field
+func validationMessages(err error) []string {
+	var validationErrors *parser.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, 0, len(validationErrors.Errors))
+	for _, e := range validationErrors.Errors {
+		messages = append(messages, e.Error())
+	}
+	return messages
+}
@@ -0,0 +1,231 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+//go:embed monitoring/*.yaml monitoring/*.json
+var monitoringFS embed.FS
+
+const (
+	// DefaultMonitoringNamespace is the namespace the monitoring stack is installed into
+	DefaultMonitoringNamespace = "monitoring"
+
+	// DefaultMonitoringRelease is the Helm release name of kube-prometheus-stack
+	DefaultMonitoringRelease = "c8s-monitoring"
+
+	prometheusCommunityRepo = "https://prometheus-community.github.io/helm-charts"
+	monitoringChart         = "prometheus-community/kube-prometheus-stack"
+)
+
+// MonitoringOptions holds options for deploying the monitoring stack
+type MonitoringOptions struct {
+	Namespace         string // Namespace for Prometheus and Grafana
+	OperatorNamespace string // Namespace of the C8S operator (for scrape config and rules)
+	ReleaseName       string
+	ChartVersion      string // Empty for latest
+	Timeout           time.Duration
+}
+
+// MonitoringStatus contains information about the monitoring deployment
+type MonitoringStatus struct {
+	Success          bool
+	Namespace        string
+	ReleaseName      string
+	GrafanaService   string
+	ManifestsApplied []string
+	Message          string
+	Timestamp        time.Time
+}
+
+// DeployMonitoring installs kube-prometheus-stack with Helm and applies the
+// C8S PrometheusRule, PodMonitor and Grafana dashboard
+func DeployMonitoring(ctx context.Context, kubectlClient cluster.KubectlClient, opts MonitoringOptions) (*MonitoringStatus, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultMonitoringNamespace
+	}
+	if opts.OperatorNamespace == "" {
+		opts.OperatorNamespace = "c8s-system"
+	}
+	if opts.ReleaseName == "" {
+		opts.ReleaseName = DefaultMonitoringRelease
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Minute
+	}
+
+	status := &MonitoringStatus{
+		Namespace:      opts.Namespace,
+		ReleaseName:    opts.ReleaseName,
+		GrafanaService: GrafanaServiceName(opts.ReleaseName),
+		Timestamp:      time.Now(),
+	}
+
+	if _, err := exec.LookPath("helm"); err != nil {
+		status.Message = "helm is not installed (see https://helm.sh/docs/intro/install/)"
+		return status, fmt.Errorf("helm not found in PATH: %w", err)
+	}
+
+	// Add the chart repository (idempotent) and refresh the index
+	if err := runHelm(ctx, "repo", "add", "prometheus-community", prometheusCommunityRepo, "--force-update"); err != nil {
+		status.Message = fmt.Sprintf("Failed to add Helm repository: %v", err)
+		return status, err
+	}
+	if err := runHelm(ctx, "repo", "update", "prometheus-community"); err != nil {
+		status.Message = fmt.Sprintf("Failed to update Helm repository: %v", err)
+		return status, err
+	}
+
+	args := []string{
+		"upgrade", "--install", opts.ReleaseName, monitoringChart,
+		"--namespace", opts.Namespace,
+		"--create-namespace",
+		"--wait",
+		"--timeout", opts.Timeout.String(),
+		// Select PrometheusRules and PodMonitors from all releases and namespaces
+		"--set", "prometheus.prometheusSpec.ruleSelectorNilUsesHelmValues=false",
+		"--set", "prometheus.prometheusSpec.podMonitorSelectorNilUsesHelmValues=false",
+		"--set", "prometheus.prometheusSpec.serviceMonitorSelectorNilUsesHelmValues=false",
+		// Load dashboards from labelled ConfigMaps
+		"--set", "grafana.sidecar.dashboards.enabled=true",
+		"--set", "grafana.sidecar.dashboards.label=grafana_dashboard",
+		// Keep the dev footprint small
+		"--set", "alertmanager.enabled=false",
+	}
+	if opts.ChartVersion != "" {
+		args = append(args, "--version", opts.ChartVersion)
+	}
+	if err := runHelm(ctx, args...); err != nil {
+		status.Message = fmt.Sprintf("Failed to install %s: %v", monitoringChart, err)
+		return status, err
+	}
+
+	// Apply C8S monitoring manifests
+	manifests, err := MonitoringManifests(opts.Namespace, opts.OperatorNamespace)
+	if err != nil {
+		status.Message = fmt.Sprintf("Failed to render monitoring manifests: %v", err)
+		return status, err
+	}
+	for _, name := range sortedKeys(manifests) {
+		if err := kubectlClient.ApplyManifestFromString(ctx, manifests[name], ""); err != nil {
+			status.Message = fmt.Sprintf("Failed to apply %s: %v", name, err)
+			return status, err
+		}
+		status.ManifestsApplied = append(status.ManifestsApplied, name)
+	}
+
+	status.Success = true
+	status.Message = fmt.Sprintf("Monitoring stack deployed to namespace %s", opts.Namespace)
+	return status, nil
+}
+
+// MonitoringManifests renders the embedded C8S monitoring manifests, keyed by file name.
+// The Grafana dashboard JSON is wrapped in a ConfigMap labelled for the Grafana sidecar.
+func MonitoringManifests(monitoringNamespace, operatorNamespace string) (map[string]string, error) {
+	entries, err := monitoringFS.ReadDir("monitoring")
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		content, err := monitoringFS.ReadFile("monitoring/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasSuffix(entry.Name(), ".json") {
+			configMap, err := dashboardConfigMap(strings.TrimSuffix(entry.Name(), ".json"), monitoringNamespace, content)
+			if err != nil {
+				return nil, err
+			}
+			manifests[entry.Name()] = configMap
+			continue
+		}
+
+		manifests[entry.Name()] = strings.ReplaceAll(string(content), "NAMESPACE_PLACEHOLDER", operatorNamespace)
+	}
+
+	return manifests, nil
+}
+
+// dashboardConfigMap wraps a Grafana dashboard in a ConfigMap picked up by the Grafana sidecar
+func dashboardConfigMap(name, namespace string, dashboard []byte) (string, error) {
+	configMap := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "c8s-" + name,
+			"namespace": namespace,
+			"labels": map[string]string{
+				"grafana_dashboard":      "1",
+				"app.kubernetes.io/name": "c8s",
+			},
+		},
+		"data": map[string]string{
+			name + ".json": string(dashboard),
+		},
+	}
+
+	out, err := yaml.Marshal(configMap)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// GrafanaServiceName returns the Grafana Service created by kube-prometheus-stack
+func GrafanaServiceName(releaseName string) string {
+	return releaseName + "-grafana"
+}
+
+// PortForwardGrafana starts a kubectl port-forward from localPort to the Grafana
+// Service. The returned command runs until it is killed or ctx is cancelled.
+func PortForwardGrafana(ctx context.Context, namespace, releaseName string, localPort int) (*exec.Cmd, error) {
+	cmd := exec.CommandContext(ctx, "kubectl", "port-forward",
+		"-n", namespace,
+		"svc/"+GrafanaServiceName(releaseName),
+		fmt.Sprintf("%d:80", localPort),
+	)
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start port-forward: %w", err)
+	}
+	return cmd, nil
+}
+
+// runHelm runs a helm command and includes stderr in the returned error
+func runHelm(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "helm", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() > 0 {
+			return fmt.Errorf("helm %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("helm %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// sortedKeys returns map keys in sorted order so manifests apply deterministically
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
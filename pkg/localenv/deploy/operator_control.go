@@ -0,0 +1,255 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// DefaultOperatorNamespace is the namespace the operator is deployed into
+	DefaultOperatorNamespace = "c8s-system"
+
+	// DefaultOperatorName is the name of the operator Deployment
+	DefaultOperatorName = "c8s-controller"
+
+	// restartedAtAnnotation is the Pod template annotation kubectl rollout restart sets
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+	// maxOperatorEvents is the number of recent events included in the status
+	maxOperatorEvents = 10
+)
+
+// OperatorStatus describes the state of the operator Deployment
+type OperatorStatus struct {
+	Name              string              `json:"name" yaml:"name"`
+	Namespace         string              `json:"namespace" yaml:"namespace"`
+	Image             string              `json:"image" yaml:"image"`
+	Replicas          int32               `json:"replicas" yaml:"replicas"`
+	ReadyReplicas     int32               `json:"readyReplicas" yaml:"readyReplicas"`
+	UpdatedReplicas   int32               `json:"updatedReplicas" yaml:"updatedReplicas"`
+	AvailableReplicas int32               `json:"availableReplicas" yaml:"availableReplicas"`
+	Conditions        []OperatorCondition `json:"conditions" yaml:"conditions"`
+	Events            []OperatorEvent     `json:"events" yaml:"events"`
+	RestartedAt       string              `json:"restartedAt,omitempty" yaml:"restartedAt,omitempty"`
+	Healthy           bool                `json:"healthy" yaml:"healthy"`
+}
+
+// OperatorCondition is a condition of the operator Deployment
+type OperatorCondition struct {
+	Type    string `json:"type" yaml:"type"`
+	Status  string `json:"status" yaml:"status"`
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// OperatorEvent is a recent event of the operator Deployment or its Pods
+type OperatorEvent struct {
+	Type     string    `json:"type" yaml:"type"`
+	Reason   string    `json:"reason" yaml:"reason"`
+	Object   string    `json:"object" yaml:"object"`
+	Message  string    `json:"message" yaml:"message"`
+	Count    int32     `json:"count" yaml:"count"`
+	LastSeen time.Time `json:"lastSeen" yaml:"lastSeen"`
+}
+
+// NewClusterClientset creates a clientset for the k3d context of a cluster
+func NewClusterClientset(clusterName string) (kubernetes.Interface, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: fmt.Sprintf("k3d-%s", clusterName)}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	return client, nil
+}
+
+// GetOperatorStatus returns the conditions, replica counts, image and recent
+// events of the operator Deployment
+func GetOperatorStatus(ctx context.Context, client kubernetes.Interface, namespace, name string) (*OperatorStatus, error) {
+	deployment, err := getOperatorDeployment(ctx, client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &OperatorStatus{
+		Name:              deployment.Name,
+		Namespace:         deployment.Namespace,
+		Replicas:          1,
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+		RestartedAt:       deployment.Spec.Template.Annotations[restartedAtAnnotation],
+	}
+	if deployment.Spec.Replicas != nil {
+		status.Replicas = *deployment.Spec.Replicas
+	}
+	if containers := deployment.Spec.Template.Spec.Containers; len(containers) > 0 {
+		status.Image = containers[0].Image
+	}
+	for _, condition := range deployment.Status.Conditions {
+		status.Conditions = append(status.Conditions, OperatorCondition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	status.Healthy = status.Replicas > 0 &&
+		deployment.Status.ObservedGeneration >= deployment.Generation &&
+		status.AvailableReplicas >= status.Replicas &&
+		status.UpdatedReplicas >= status.Replicas
+
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	status.Events = operatorEvents(events.Items, name)
+
+	return status, nil
+}
+
+// RestartOperator triggers a rollout restart of the operator Deployment the
+// same way 'kubectl rollout restart' does, by stamping the Pod template
+func RestartOperator(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	if _, err := getOperatorDeployment(ctx, client, namespace, name); err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.AppsV1().Deployments(namespace).Patch(ctx, name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to restart operator deployment: %w", err)
+	}
+	return nil
+}
+
+// ScaleOperator sets the replica count of the operator Deployment
+func ScaleOperator(ctx context.Context, client kubernetes.Interface, namespace, name string, replicas int32) error {
+	if replicas < 0 {
+		return fmt.Errorf("replicas must be non-negative")
+	}
+
+	deployment, err := getOperatorDeployment(ctx, client, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	deployment.Spec.Replicas = &replicas
+	if _, err := client.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to scale operator deployment: %w", err)
+	}
+	return nil
+}
+
+// WaitForOperatorRollout waits until the operator Deployment has rolled out
+// and all desired replicas are available
+func WaitForOperatorRollout(ctx context.Context, client kubernetes.Interface, namespace, name string, timeout time.Duration) (*OperatorStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		status, err := GetOperatorStatus(ctx, client, namespace, name)
+		if err == nil && (status.Healthy || (status.Replicas == 0 && status.AvailableReplicas == 0)) {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("timeout waiting for operator rollout after %s", timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// getOperatorDeployment fetches the operator Deployment with a hint when it is missing
+func getOperatorDeployment(ctx context.Context, client kubernetes.Interface, namespace, name string) (*appsv1.Deployment, error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("operator deployment %s/%s not found (run 'c8s dev deploy operator' first)", namespace, name)
+		}
+		return nil, fmt.Errorf("failed to get operator deployment: %w", err)
+	}
+	return deployment, nil
+}
+
+// operatorEvents returns the most recent events of the Deployment and the
+// ReplicaSets and Pods it owns, identified by the Deployment name prefix
+func operatorEvents(events []corev1.Event, name string) []OperatorEvent {
+	var result []OperatorEvent
+	for _, event := range events {
+		object := event.InvolvedObject
+		switch object.Kind {
+		case "Deployment":
+			if object.Name != name {
+				continue
+			}
+		case "ReplicaSet", "Pod":
+			if !strings.HasPrefix(object.Name, name+"-") {
+				continue
+			}
+		default:
+			continue
+		}
+
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+		if lastSeen.IsZero() {
+			lastSeen = event.CreationTimestamp.Time
+		}
+
+		result = append(result, OperatorEvent{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Object:   fmt.Sprintf("%s/%s", strings.ToLower(object.Kind), object.Name),
+			Message:  event.Message,
+			Count:    event.Count,
+			LastSeen: lastSeen,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+	if len(result) > maxOperatorEvents {
+		result = result[:maxOperatorEvents]
+	}
+	return result
+}
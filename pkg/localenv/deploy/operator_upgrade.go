@@ -0,0 +1,168 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// UpgradeOptions holds options for upgrading the operator to a new image
+type UpgradeOptions struct {
+	Namespace string
+	Name      string
+	Image     string
+
+	// Timeout bounds each wait for a rollout, including a rollback
+	Timeout time.Duration
+}
+
+// UpgradeResult describes the outcome of an operator upgrade
+type UpgradeResult struct {
+	PreviousImage string `json:"previousImage"`
+	Image         string `json:"image"`
+
+	// CheckedConfigs is the number of PipelineConfigs validated against the
+	// new operator
+	CheckedConfigs int `json:"checkedConfigs"`
+
+	// IncompatibleConfigs lists the PipelineConfigs rejected by the new
+	// operator as "namespace/name: reason"
+	IncompatibleConfigs []string `json:"incompatibleConfigs,omitempty"`
+
+	// RolledBack is true when the previous image was restored
+	RolledBack bool `json:"rolledBack"`
+}
+
+// NewClusterDynamicClient creates a dynamic client for the k3d context of a cluster
+func NewClusterDynamicClient(clusterName string) (dynamic.Interface, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: fmt.Sprintf("k3d-%s", clusterName)}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return client, nil
+}
+
+// UpgradeOperator rolls the operator Deployment out with a new image, then
+// validates every PipelineConfig against the new operator with a server-side
+// dry-run update. If the rollout does not complete or any PipelineConfig is
+// rejected, the previous image is restored.
+func UpgradeOperator(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, opts UpgradeOptions) (*UpgradeResult, error) {
+	if opts.Image == "" {
+		return nil, fmt.Errorf("image is required")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+
+	deployment, err := getOperatorDeployment(ctx, client, opts.Namespace, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(deployment.Spec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("operator deployment %s/%s has no containers", opts.Namespace, opts.Name)
+	}
+
+	result := &UpgradeResult{
+		PreviousImage: deployment.Spec.Template.Spec.Containers[0].Image,
+		Image:         opts.Image,
+	}
+
+	if err := SetOperatorImage(ctx, client, opts.Namespace, opts.Name, opts.Image); err != nil {
+		return result, err
+	}
+
+	if _, err := WaitForOperatorRollout(ctx, client, opts.Namespace, opts.Name, opts.Timeout); err != nil {
+		return result, rollbackOperator(ctx, client, opts, result, err)
+	}
+
+	result.CheckedConfigs, result.IncompatibleConfigs, err = CheckPipelineConfigCompatibility(ctx, dynamicClient)
+	if err != nil {
+		return result, rollbackOperator(ctx, client, opts, result, err)
+	}
+	if len(result.IncompatibleConfigs) > 0 {
+		err := fmt.Errorf("%d of %d PipelineConfigs are rejected by %s",
+			len(result.IncompatibleConfigs), result.CheckedConfigs, opts.Image)
+		return result, rollbackOperator(ctx, client, opts, result, err)
+	}
+
+	return result, nil
+}
+
+// rollbackOperator restores the previous operator image after a failed
+// upgrade and returns the error describing the failure
+func rollbackOperator(ctx context.Context, client kubernetes.Interface, opts UpgradeOptions, result *UpgradeResult, cause error) error {
+	if err := SetOperatorImage(ctx, client, opts.Namespace, opts.Name, result.PreviousImage); err != nil {
+		return fmt.Errorf("%w; rollback to %s failed: %v", cause, result.PreviousImage, err)
+	}
+	result.RolledBack = true
+
+	if _, err := WaitForOperatorRollout(ctx, client, opts.Namespace, opts.Name, opts.Timeout); err != nil {
+		return fmt.Errorf("%w; rolled back to %s but the rollback did not complete: %v", cause, result.PreviousImage, err)
+	}
+	return fmt.Errorf("%w; rolled back to %s", cause, result.PreviousImage)
+}
+
+// SetOperatorImage sets the image of the first container of the operator Deployment
+func SetOperatorImage(ctx context.Context, client kubernetes.Interface, namespace, name, image string) error {
+	patch, err := json.Marshal([]map[string]interface{}{{
+		"op":    "replace",
+		"path":  "/spec/template/spec/containers/0/image",
+		"value": image,
+	}})
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.AppsV1().Deployments(namespace).Patch(ctx, name, k8stypes.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to set operator image to %s: %w", image, err)
+	}
+	return nil
+}
+
+// CheckPipelineConfigCompatibility submits every PipelineConfig as a
+// server-side dry-run update, so it passes through the current CRD schema and
+// admission webhooks without being changed. Returns the number of configs
+// checked and the rejected ones as "namespace/name: reason".
+func CheckPipelineConfigCompatibility(ctx context.Context, client dynamic.Interface) (int, []string, error) {
+	resource := client.Resource(c8sv1alpha1.GroupVersion.WithResource("pipelineconfigs"))
+
+	list, err := resource.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to list PipelineConfigs: %w", err)
+	}
+
+	var incompatible []string
+	for i := range list.Items {
+		item := &list.Items[i]
+		_, err := resource.Namespace(item.GetNamespace()).Update(ctx, item, metav1.UpdateOptions{
+			DryRun: []string{metav1.DryRunAll},
+		})
+		switch {
+		case err == nil:
+		case apierrors.IsInvalid(err), apierrors.IsForbidden(err), apierrors.IsBadRequest(err):
+			incompatible = append(incompatible, fmt.Sprintf("%s/%s: %v", item.GetNamespace(), item.GetName(), err))
+		default:
+			return len(list.Items), incompatible, fmt.Errorf("failed to validate PipelineConfig %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+		}
+	}
+
+	return len(list.Items), incompatible, nil
+}
@@ -0,0 +1,135 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/org/c8s/pkg/types"
+)
+
+// ProfileKind is a profile served by the controller's pprof endpoint
+type ProfileKind string
+
+const (
+	// ProfileCPU samples CPU usage over a duration
+	ProfileCPU ProfileKind = "cpu"
+
+	// ProfileHeap is a snapshot of live heap allocations
+	ProfileHeap ProfileKind = "heap"
+
+	// ProfileGoroutines is a text dump of every goroutine stack
+	ProfileGoroutines ProfileKind = "goroutines"
+)
+
+// pprofReadyTimeout bounds the wait for a port-forward to accept connections
+const pprofReadyTimeout = 10 * time.Second
+
+// ProfilePath returns the pprof endpoint path of a profile. duration only
+// applies to CPU profiles.
+func ProfilePath(kind ProfileKind, duration time.Duration) string {
+	switch kind {
+	case ProfileHeap:
+		return "/debug/pprof/heap"
+	case ProfileGoroutines:
+		return "/debug/pprof/goroutine?debug=2"
+	default:
+		return fmt.Sprintf("/debug/pprof/profile?seconds=%d", int(duration.Seconds()))
+	}
+}
+
+// PortForwardOperatorPprof starts a kubectl port-forward from a free local
+// port to the pprof port of the operator Deployment. The returned command
+// runs until it is killed or ctx is cancelled; the returned address is the
+// local host:port.
+func PortForwardOperatorPprof(ctx context.Context, clusterName, namespace, name string) (*exec.Cmd, string, error) {
+	localPort, err := freeLocalPort()
+	if err != nil {
+		return nil, "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "kubectl", "port-forward",
+		"--context", "k3d-"+clusterName,
+		"-n", namespace,
+		"deployment/"+name,
+		fmt.Sprintf("%d:%d", localPort, types.PprofPort),
+	)
+	if err := cmd.Start(); err != nil {
+		return nil, "", fmt.Errorf("failed to start port-forward: %w", err)
+	}
+
+	addr := fmt.Sprintf("127.0.0.1:%d", localPort)
+	if err := waitForPort(ctx, addr, pprofReadyTimeout); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, "", fmt.Errorf("port-forward to %s/%s did not become ready: %w", namespace, name, err)
+	}
+	return cmd, addr, nil
+}
+
+// CaptureProfile downloads a profile from the pprof endpoint at addr into
+// the file at path. A missing endpoint is reported as the operator running
+// without --enable-pprof.
+func CaptureProfile(ctx context.Context, addr string, kind ProfileKind, duration time.Duration, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+ProfilePath(kind, duration), nil)
+	if err != nil {
+		return err
+	}
+
+	// CPU profiles take the whole sampling duration to respond
+	client := &http.Client{Timeout: duration + 30*time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("pprof endpoint not reachable (is the operator running with --enable-pprof?): %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("pprof endpoint returned %s: %s", resp.Status, body)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to download profile: %w", err)
+	}
+	return file.Close()
+}
+
+// freeLocalPort returns a TCP port that is free on the loopback interface
+func freeLocalPort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free local port: %w", err)
+	}
+	defer func() { _ = listener.Close() }()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitForPort waits until addr accepts TCP connections
+func waitForPort(ctx context.Context, addr string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn.Close()
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// OperatorImage returns the container image of the operator Deployment
+func OperatorImage(ctx context.Context, client kubernetes.Interface, namespace, name string) (string, error) {
+	deployment, err := getOperatorDeployment(ctx, client, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	containers := deployment.Spec.Template.Spec.Containers
+	if len(containers) == 0 {
+		return "", fmt.Errorf("operator deployment %s/%s has no containers", namespace, name)
+	}
+	return containers[0].Image, nil
+}
+
+// ImageTag returns the tag of an image reference, "latest" when it has none
+// and "" when the image is pinned by digest only
+func ImageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+		if !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+			return ""
+		}
+	}
+
+	// A colon before the last slash belongs to a registry port
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return "latest"
+}
+
+// ServedAPIVersions returns the versions of an API group the cluster serves,
+// or nil if the group's CRDs are not installed
+func ServedAPIVersions(client kubernetes.Interface, group string) ([]string, error) {
+	groups, err := client.Discovery().ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+		versions := make([]string, 0, len(g.Versions))
+		for _, v := range g.Versions {
+			versions = append(versions, v.Version)
+		}
+		return versions, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,137 @@
+package fuzz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPackage is the package holding the c8s fuzz tests
+	DefaultPackage = "./tests/fuzz"
+
+	// DefaultTarget is the fuzz test run when no target is given
+	DefaultTarget = "FuzzParse"
+)
+
+// sourceLocation matches the "file.go:123: " prefix of test failure messages
+var sourceLocation = regexp.MustCompile(`^\S+\.go:\d+: `)
+
+// Options holds options for a fuzzing session
+type Options struct {
+	// Dir is the module root the package is resolved from (default: current directory)
+	Dir string
+
+	// Package is the Go package containing the fuzz test
+	Package string
+
+	// Target is the name of the fuzz test
+	Target string
+
+	// Duration bounds the fuzzing session
+	Duration time.Duration
+
+	// Output receives the output of go test as it runs (optional)
+	Output io.Writer
+}
+
+// Crash is an input that made a fuzz test fail
+type Crash struct {
+	// Input is the corpus file the failing input was written to. Once
+	// committed, it is run as a regression test by a plain go test.
+	Input string `json:"input"`
+
+	// Message is the panic or failure reported for the input
+	Message string `json:"message"`
+}
+
+// Result describes the outcome of a fuzzing session
+type Result struct {
+	Target   string        `json:"target"`
+	Duration time.Duration `json:"duration"`
+	Crashes  []Crash       `json:"crashes,omitempty"`
+}
+
+// Run fuzzes a target with go test -fuzz for the configured duration. Inputs
+// that crash the target are reported in the result rather than as an error;
+// an error means the session itself could not run.
+func Run(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Package == "" {
+		opts.Package = DefaultPackage
+	}
+	if opts.Target == "" {
+		opts.Target = DefaultTarget
+	}
+	if opts.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be greater than 0")
+	}
+
+	var output bytes.Buffer
+	var w io.Writer = &output
+	if opts.Output != nil {
+		w = io.MultiWriter(&output, opts.Output)
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "test",
+		"-run=^$",
+		fmt.Sprintf("-fuzz=^%s$", opts.Target),
+		fmt.Sprintf("-fuzztime=%s", opts.Duration),
+		opts.Package)
+	cmd.Dir = opts.Dir
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &Result{
+		Target:   opts.Target,
+		Duration: time.Since(start).Round(time.Second),
+		Crashes:  ParseCrashes(output.String()),
+	}
+
+	// go test exits non-zero when a crash is found
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && len(result.Crashes) > 0) {
+		return result, fmt.Errorf("go test -fuzz failed: %w\n%s", err, output.String())
+	}
+
+	for i := range result.Crashes {
+		result.Crashes[i].Input = filepath.Join(opts.Dir, opts.Package, result.Crashes[i].Input)
+	}
+	return result, nil
+}
+
+// ParseCrashes extracts the failing inputs from the output of go test -fuzz.
+// Input paths are relative to the fuzzed package.
+func ParseCrashes(output string) []Crash {
+	var crashes []Crash
+	var message string
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "--- FAIL:"):
+			message = ""
+		case strings.HasPrefix(line, "Failing input written to "):
+			crashes = append(crashes, Crash{
+				Input:   strings.TrimPrefix(line, "Failing input written to "),
+				Message: message,
+			})
+			message = ""
+		case message == "" && line != "" && !strings.HasPrefix(line, "fuzz:"):
+			// The first line after the failing test names the panic or failure
+			message = sourceLocation.ReplaceAllString(line, "")
+		}
+	}
+
+	return crashes
+}
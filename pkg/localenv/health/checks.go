@@ -257,6 +257,31 @@ func (c *Checker) CheckPodStatus(ctx context.Context, namespace, labelSelector s
 	}
 }
 
+// CheckMonitoring checks if the Prometheus and Grafana pods of the monitoring stack are ready
+func (c *Checker) CheckMonitoring(ctx context.Context, namespace string) CheckResult {
+	var problems []string
+	for _, component := range []string{"prometheus", "grafana"} {
+		result := c.CheckPodStatus(ctx, namespace, "app.kubernetes.io/name="+component)
+		if !result.Healthy {
+			problems = append(problems, fmt.Sprintf("%s: %s", component, result.Message))
+		}
+	}
+
+	if len(problems) > 0 {
+		return CheckResult{
+			Name:    "Monitoring",
+			Healthy: false,
+			Message: fmt.Sprintf("Monitoring not available in namespace %s (%s)", namespace, strings.Join(problems, "; ")),
+		}
+	}
+
+	return CheckResult{
+		Name:    "Monitoring",
+		Healthy: true,
+		Message: fmt.Sprintf("Prometheus and Grafana are running in namespace %s", namespace),
+	}
+}
+
 // CheckAll runs all basic health checks
 func (c *Checker) CheckAll(ctx context.Context) *HealthStatus {
 	checks := []CheckResult{
@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SlowAPIServerLatency is the version request latency above which the
+	// API server is reported unhealthy
+	SlowAPIServerLatency = 2 * time.Second
+
+	// metricsGroupVersion is the API served by metrics-server
+	metricsGroupVersion = "metrics.k8s.io/v1beta1"
+)
+
+// CheckAPIServer checks the API server answers a version request within
+// SlowAPIServerLatency
+func (c *Checker) CheckAPIServer(ctx context.Context, client kubernetes.Interface) CheckResult {
+	start := time.Now()
+	version, err := client.Discovery().ServerVersion()
+	latency := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		return CheckResult{
+			Name:    "API server",
+			Healthy: false,
+			Message: fmt.Sprintf("Cannot reach API server: %v", err),
+		}
+	}
+
+	if latency > SlowAPIServerLatency {
+		return CheckResult{
+			Name:    "API server",
+			Healthy: false,
+			Message: fmt.Sprintf("API server %s answered in %s (slower than %s)", version.GitVersion, latency, SlowAPIServerLatency),
+		}
+	}
+
+	return CheckResult{
+		Name:    "API server",
+		Healthy: true,
+		Message: fmt.Sprintf("API server %s answered in %s", version.GitVersion, latency),
+	}
+}
+
+// CheckMetricsServer checks the resource metrics API of metrics-server is
+// served
+func (c *Checker) CheckMetricsServer(ctx context.Context, client kubernetes.Interface) CheckResult {
+	resources, err := client.Discovery().ServerResourcesForGroupVersion(metricsGroupVersion)
+	if err != nil {
+		return CheckResult{
+			Name:    "Metrics server",
+			Healthy: false,
+			Message: fmt.Sprintf("%s not available: %v", metricsGroupVersion, err),
+		}
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Name == "nodes" {
+			return CheckResult{
+				Name:    "Metrics server",
+				Healthy: true,
+				Message: fmt.Sprintf("%s is served", metricsGroupVersion),
+			}
+		}
+	}
+
+	return CheckResult{
+		Name:    "Metrics server",
+		Healthy: false,
+		Message: fmt.Sprintf("%s serves no node metrics", metricsGroupVersion),
+	}
+}
+
+// CheckNodeConditions checks every node is Ready without memory, disk, PID
+// or network pressure
+func (c *Checker) CheckNodeConditions(ctx context.Context, client kubernetes.Interface) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return CheckResult{
+			Name:    "Nodes",
+			Healthy: false,
+			Message: fmt.Sprintf("Cannot list nodes: %v", err),
+		}
+	}
+	if len(nodes.Items) == 0 {
+		return CheckResult{
+			Name:    "Nodes",
+			Healthy: false,
+			Message: "No nodes found in cluster",
+		}
+	}
+
+	var problems []string
+	for _, node := range nodes.Items {
+		if problem := nodeProblem(node); problem != "" {
+			problems = append(problems, fmt.Sprintf("%s (%s)", node.Name, problem))
+		}
+	}
+	if len(problems) > 0 {
+		return CheckResult{
+			Name:    "Nodes",
+			Healthy: false,
+			Message: fmt.Sprintf("Unhealthy nodes: %s", strings.Join(problems, ", ")),
+		}
+	}
+
+	return CheckResult{
+		Name:    "Nodes",
+		Healthy: true,
+		Message: fmt.Sprintf("%d node(s) ready", len(nodes.Items)),
+	}
+}
+
+// nodeProblem describes the failing conditions of a node, or returns ""
+func nodeProblem(node corev1.Node) string {
+	ready := false
+	var problems []string
+	for _, condition := range node.Status.Conditions {
+		switch condition.Type {
+		case corev1.NodeReady:
+			ready = condition.Status == corev1.ConditionTrue
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable:
+			if condition.Status == corev1.ConditionTrue {
+				problems = append(problems, string(condition.Type))
+			}
+		}
+	}
+	if !ready {
+		problems = append([]string{"not ready"}, problems...)
+	}
+	return strings.Join(problems, ", ")
+}
@@ -0,0 +1,150 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// MonitorOptions configures Monitor
+type MonitorOptions struct {
+	// Interval is the time between two rounds of checks
+	Interval time.Duration
+
+	// AlertThreshold is the number of consecutive failed rounds that raise
+	// an alert; the alert is raised again every AlertThreshold failed rounds
+	AlertThreshold int
+
+	// Check runs the checks of a round
+	Check func(ctx context.Context) []CheckResult
+
+	// OnAlert is called when an alert is raised (optional)
+	OnAlert func(ctx context.Context, round *MonitorRound) error
+
+	// Remediate is called with the failed checks of the first failed round
+	// after a healthy one and returns the remediations attempted (optional)
+	Remediate func(ctx context.Context, failed []CheckResult) ([]string, error)
+
+	// OnRound is called after every round
+	OnRound func(round *MonitorRound)
+}
+
+// MonitorRound is the outcome of one round of checks
+type MonitorRound struct {
+	Number  int           `json:"number"`
+	Time    time.Time     `json:"time"`
+	Healthy bool          `json:"healthy"`
+	Checks  []CheckResult `json:"checks"`
+
+	// ConsecutiveFailures counts the failed rounds up to this one
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	Alerted    bool   `json:"alerted,omitempty"`
+	AlertError string `json:"alertError,omitempty"`
+
+	Remediations     []string `json:"remediations,omitempty"`
+	RemediationError string   `json:"remediationError,omitempty"`
+}
+
+// Failed returns the checks of the round that failed
+func (r *MonitorRound) Failed() []CheckResult {
+	var failed []CheckResult
+	for _, check := range r.Checks {
+		if !check.Healthy {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// Monitor runs opts.Check every opts.Interval until ctx is done, starting
+// immediately. Failures are remediated once per streak of failed rounds, and
+// an alert is raised after every opts.AlertThreshold consecutive failures.
+func Monitor(ctx context.Context, opts MonitorOptions) error {
+	if opts.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if opts.AlertThreshold <= 0 {
+		return fmt.Errorf("alert threshold must be positive")
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for number := 1; ; number++ {
+		round := &MonitorRound{
+			Number: number,
+			Time:   time.Now(),
+			Checks: opts.Check(ctx),
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		round.Healthy = len(round.Failed()) == 0
+
+		if round.Healthy {
+			failures = 0
+		} else {
+			failures++
+		}
+		round.ConsecutiveFailures = failures
+
+		if failures == 1 && opts.Remediate != nil {
+			remediations, err := opts.Remediate(ctx, round.Failed())
+			round.Remediations = remediations
+			if err != nil {
+				round.RemediationError = err.Error()
+			}
+		}
+		if failures > 0 && failures%opts.AlertThreshold == 0 {
+			round.Alerted = true
+			if opts.OnAlert != nil {
+				if err := opts.OnAlert(ctx, round); err != nil {
+					round.AlertError = err.Error()
+				}
+			}
+		}
+
+		if opts.OnRound != nil {
+			opts.OnRound(round)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// AlertCommand returns an OnAlert running command with "sh -c". The
+// command gets the failed checks in C8S_HEALTH_FAILED_CHECKS (comma
+// separated) and the failed rounds in C8S_HEALTH_CONSECUTIVE_FAILURES, in
+// addition to env.
+func AlertCommand(command string, env map[string]string) func(ctx context.Context, round *MonitorRound) error {
+	return func(ctx context.Context, round *MonitorRound) error {
+		var names []string
+		for _, check := range round.Failed() {
+			names = append(names, check.Name)
+		}
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = append(os.Environ(),
+			"C8S_HEALTH_FAILED_CHECKS="+strings.Join(names, ","),
+			fmt.Sprintf("C8S_HEALTH_CONSECUTIVE_FAILURES=%d", round.ConsecutiveFailures))
+		for key, value := range env {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failure command failed: %w", err)
+		}
+		return nil
+	}
+}
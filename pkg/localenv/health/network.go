@@ -0,0 +1,334 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/org/c8s/pkg/types"
+)
+
+const (
+	// DefaultNetworkCheckImage provides the curl and nslookup used by the
+	// network checks
+	DefaultNetworkCheckImage = "curlimages/curl:8.10.1"
+
+	// DefaultNetworkCheckTimeout bounds the whole network diagnosis
+	DefaultNetworkCheckTimeout = 3 * time.Minute
+
+	// networkCheckStep is the name of the synthetic step running the checks
+	networkCheckStep = "network-check"
+
+	// networkResultPrefix starts the log lines holding a check result:
+	// "C8S-NET <target> <dns ok|fail> <curl exit code> <http code>"
+	networkResultPrefix = "C8S-NET"
+
+	// serviceAccountCA verifies the API server certificate from a Pod
+	serviceAccountCA = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// NetworkTarget is an endpoint pipeline steps need to reach
+type NetworkTarget struct {
+	// Name identifies the target in the results
+	Name string `json:"name" yaml:"name"`
+
+	// Host is resolved to check DNS
+	Host string `json:"host" yaml:"host"`
+
+	// URL is requested to check routing and TLS
+	URL string `json:"url" yaml:"url"`
+
+	// CACert is the CA bundle verifying the server, in the Pod
+	// (default: the image's system CAs)
+	CACert string `json:"caCert,omitempty" yaml:"caCert,omitempty"`
+}
+
+// DefaultNetworkTargets are the endpoints pulling images, cloning
+// repositories and talking to the Kubernetes API depend on
+var DefaultNetworkTargets = []NetworkTarget{
+	{Name: "docker.io", Host: "registry-1.docker.io", URL: "https://registry-1.docker.io/v2/"},
+	{Name: "ghcr.io", Host: "ghcr.io", URL: "https://ghcr.io/v2/"},
+	{Name: "github.com", Host: "github.com", URL: "https://github.com/"},
+	{
+		Name:   "kubernetes-api",
+		Host:   "kubernetes.default.svc.cluster.local",
+		URL:    "https://kubernetes.default.svc.cluster.local/version",
+		CACert: serviceAccountCA,
+	},
+}
+
+// NetworkCheckStatus is the outcome of a check of a target
+type NetworkCheckStatus string
+
+const (
+	// NetworkCheckOK is a check that passed
+	NetworkCheckOK NetworkCheckStatus = "ok"
+
+	// NetworkCheckFailed is a check that failed
+	NetworkCheckFailed NetworkCheckStatus = "fail"
+
+	// NetworkCheckSkipped is a check with no result in the Job logs
+	NetworkCheckSkipped NetworkCheckStatus = "skipped"
+)
+
+// NetworkFailure classifies why a target is unreachable
+type NetworkFailure string
+
+const (
+	// NetworkFailureDNS is a host that does not resolve
+	NetworkFailureDNS NetworkFailure = "dns"
+
+	// NetworkFailureRouting is a connection refused or timing out
+	NetworkFailureRouting NetworkFailure = "routing"
+
+	// NetworkFailureTLS is a failed TLS handshake or certificate verification
+	NetworkFailureTLS NetworkFailure = "tls"
+
+	// NetworkFailureOther is any other failure
+	NetworkFailureOther NetworkFailure = "other"
+)
+
+// NetworkResult is the connectivity of a target from a pipeline Pod
+type NetworkResult struct {
+	Target string             `json:"target" yaml:"target"`
+	DNS    NetworkCheckStatus `json:"dns" yaml:"dns"`
+	HTTPS  NetworkCheckStatus `json:"https" yaml:"https"`
+
+	// HTTPStatus is the status of the response, when one was received;
+	// any status means the target is reachable
+	HTTPStatus int `json:"httpStatus,omitempty" yaml:"httpStatus,omitempty"`
+
+	// Failure classifies the failure of an unreachable target
+	Failure NetworkFailure `json:"failure,omitempty" yaml:"failure,omitempty"`
+
+	// Diagnosis explains the result
+	Diagnosis string `json:"diagnosis" yaml:"diagnosis"`
+}
+
+// NetworkReport is the result of a network diagnosis
+type NetworkReport struct {
+	Namespace string          `json:"namespace" yaml:"namespace"`
+	Job       string          `json:"job" yaml:"job"`
+	Healthy   bool            `json:"healthy" yaml:"healthy"`
+	Results   []NetworkResult `json:"results" yaml:"results"`
+}
+
+// NetworkOptions holds options for a network diagnosis
+type NetworkOptions struct {
+	Namespace string
+	Image     string
+	Timeout   time.Duration
+	Targets   []NetworkTarget
+}
+
+// tlsCurlExitCodes are the curl exit codes of TLS handshake and certificate
+// verification failures
+var tlsCurlExitCodes = map[int]bool{35: true, 51: true, 53: true, 54: true, 58: true, 59: true, 60: true, 64: true, 66: true, 77: true, 80: true, 82: true, 83: true, 90: true, 91: true}
+
+// NetworkCheckScript returns the shell script checking each target: DNS
+// resolution with nslookup, then an HTTPS request with curl
+func NetworkCheckScript(targets []NetworkTarget) string {
+	var b strings.Builder
+	b.WriteString(`check() {
+  if nslookup "$2" >/dev/null 2>&1; then dns=ok; else dns=fail; fi
+  if [ -n "$4" ]; then
+    code=$(curl -sS -o /dev/null -w '%{http_code}' --max-time 10 --cacert "$4" "$3")
+  else
+    code=$(curl -sS -o /dev/null -w '%{http_code}' --max-time 10 "$3")
+  fi
+  rc=$?
+  echo "` + networkResultPrefix + ` $1 $dns $rc $code"
+}
+`)
+	for _, target := range targets {
+		fmt.Fprintf(&b, "check %s %s %s %s\n", shellQuote(target.Name), shellQuote(target.Host), shellQuote(target.URL), shellQuote(target.CACert))
+	}
+	return b.String()
+}
+
+// shellQuote quotes a value for a POSIX shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// NetworkCheckJob returns a Job running the network checks in a Pod shaped
+// like the Pods of pipeline steps (step container, workspace and labels),
+// without a repository to clone or an owning PipelineRun. It is built here
+// rather than by the controller's JobManager so the CLI doesn't link the
+// operator packages, whose flags clash with its own.
+func NetworkCheckJob(namespace, image string, targets []NetworkTarget) (*batchv1.Job, error) {
+	name := fmt.Sprintf("c8s-diagnose-%d", time.Now().Unix())
+	labels := map[string]string{
+		types.LabelManagedBy: types.ManagedByC8S,
+		types.LabelManaged:   types.LabelManagedValue,
+		types.LabelStepName:  networkCheckStep,
+	}
+	deadline := int64(DefaultNetworkCheckTimeout.Seconds())
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          new(int32),
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:       types.ContainerNameStep,
+						Image:      image,
+						Command:    []string{"/bin/sh", "-c", NetworkCheckScript(targets)},
+						WorkingDir: types.MountPathWorkspace,
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      types.VolumeNameWorkspace,
+							MountPath: types.MountPathWorkspace,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name:         types.VolumeNameWorkspace,
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					}},
+				},
+			},
+		},
+	}, nil
+}
+
+// DiagnoseNetwork runs the network checks in a Job of the cluster, reads
+// the results from its logs and deletes it
+func DiagnoseNetwork(ctx context.Context, client kubernetes.Interface, opts NetworkOptions) (*NetworkReport, error) {
+	if opts.Image == "" {
+		opts.Image = DefaultNetworkCheckImage
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultNetworkCheckTimeout
+	}
+	if len(opts.Targets) == 0 {
+		opts.Targets = DefaultNetworkTargets
+	}
+
+	job, err := NetworkCheckJob(opts.Namespace, opts.Image, opts.Targets)
+	if err != nil {
+		return nil, err
+	}
+	jobs := client.BatchV1().Jobs(opts.Namespace)
+	if job, err = jobs.Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create network check Job: %w", err)
+	}
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		_ = jobs.Delete(context.Background(), job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	for {
+		current, err := jobs.Get(ctx, job.Name, metav1.GetOptions{})
+		if err == nil && (current.Status.Succeeded > 0 || current.Status.Failed > 0) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timeout waiting for network check Job %s; check that image %s can be pulled", job.Name, opts.Image)
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	pods, err := client.CoreV1().Pods(opts.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", types.LabelPipelineRun, job.Labels[types.LabelPipelineRun]),
+	})
+	if err != nil || len(pods.Items) == 0 {
+		return nil, fmt.Errorf("failed to find the Pod of network check Job %s: %v", job.Name, err)
+	}
+	logs, err := client.CoreV1().Pods(opts.Namespace).GetLogs(pods.Items[0].Name, &corev1.PodLogOptions{
+		Container: types.ContainerNameStep,
+	}).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network check logs: %w", err)
+	}
+
+	report := &NetworkReport{
+		Namespace: opts.Namespace,
+		Job:       job.Name,
+		Results:   ParseNetworkResults(string(logs), opts.Targets),
+		Healthy:   true,
+	}
+	for _, result := range report.Results {
+		if result.HTTPS != NetworkCheckOK {
+			report.Healthy = false
+		}
+	}
+	return report, nil
+}
+
+// ParseNetworkResults returns the result of each target from the logs of
+// the network check script. Targets without a result line are reported as
+// skipped.
+func ParseNetworkResults(logs string, targets []NetworkTarget) []NetworkResult {
+	lines := map[string][]string{}
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 4 && fields[0] == networkResultPrefix {
+			lines[fields[1]] = fields[2:]
+		}
+	}
+
+	results := make([]NetworkResult, 0, len(targets))
+	for _, target := range targets {
+		fields, ok := lines[target.Name]
+		if !ok {
+			results = append(results, NetworkResult{
+				Target:    target.Name,
+				DNS:       NetworkCheckSkipped,
+				HTTPS:     NetworkCheckSkipped,
+				Failure:   NetworkFailureOther,
+				Diagnosis: "not checked; see the Job logs",
+			})
+			continue
+		}
+
+		exitCode, _ := strconv.Atoi(fields[1])
+		httpStatus := 0
+		if len(fields) > 2 {
+			httpStatus, _ = strconv.Atoi(fields[2])
+		}
+		results = append(results, classifyNetworkResult(target, NetworkCheckStatus(fields[0]), exitCode, httpStatus))
+	}
+	return results
+}
+
+// classifyNetworkResult explains the result of a target from the nslookup
+// outcome and the curl exit code
+func classifyNetworkResult(target NetworkTarget, dns NetworkCheckStatus, exitCode, httpStatus int) NetworkResult {
+	result := NetworkResult{Target: target.Name, DNS: dns, HTTPS: NetworkCheckFailed}
+
+	switch {
+	case exitCode == 0 && httpStatus > 0:
+		result.HTTPS = NetworkCheckOK
+		result.HTTPStatus = httpStatus
+		result.Diagnosis = fmt.Sprintf("reachable (HTTP %d)", httpStatus)
+	case exitCode == 6 || result.DNS == NetworkCheckFailed:
+		result.DNS = NetworkCheckFailed
+		result.Failure = NetworkFailureDNS
+		result.Diagnosis = fmt.Sprintf("%s does not resolve; check CoreDNS and its upstream resolvers", target.Host)
+	case exitCode == 7 || exitCode == 28:
+		result.Failure = NetworkFailureRouting
+		result.Diagnosis = fmt.Sprintf("no connection to %s; check egress routing, proxies and NetworkPolicies", target.Host)
+	case tlsCurlExitCodes[exitCode]:
+		result.Failure = NetworkFailureTLS
+		result.Diagnosis = "TLS handshake or certificate verification failed; check for an intercepting proxy or missing CA certificates"
+	default:
+		result.Failure = NetworkFailureOther
+		result.Diagnosis = fmt.Sprintf("request failed (curl exit code %d)", exitCode)
+	}
+	return result
+}
@@ -0,0 +1,421 @@
+package samples
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+const (
+	// DefaultDurationThreshold is the relative increase of a step duration
+	// reported as a regression
+	DefaultDurationThreshold = 0.2
+
+	// DefaultMemoryThreshold is the relative increase of the peak memory of
+	// a step reported as a regression
+	DefaultMemoryThreshold = 0.5
+
+	// minDurationIncrease is the smallest increase of a step duration
+	// reported as a regression; step times are recorded to the second
+	minDurationIncrease = time.Second
+
+	// usageSampleInterval is the time between two samples of step pod metrics
+	usageSampleInterval = 2 * time.Second
+)
+
+// Regression kinds
+const (
+	RegressionFailure  = "failure"
+	RegressionDuration = "duration"
+	RegressionMemory   = "memory"
+	RegressionMissing  = "missing"
+)
+
+// podMetricsKind is the list kind of the metrics-server pod metrics
+var podMetricsKind = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+// Baseline is the recorded outcome of passing pipeline tests that later
+// runs are compared with
+type Baseline struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	Namespace string             `json:"namespace"`
+	Pipelines []BaselinePipeline `json:"pipelines"`
+}
+
+// BaselinePipeline is the recorded run of one PipelineConfig
+type BaselinePipeline struct {
+	Name     string         `json:"name"`
+	RunName  string         `json:"runName,omitempty"`
+	Status   string         `json:"status"`
+	Duration time.Duration  `json:"duration"`
+	Steps    []BaselineStep `json:"steps,omitempty"`
+}
+
+// BaselineStep is the recorded execution of a step. Resource usage is the
+// peak sampled from metrics-server and is 0 when it is not installed.
+type BaselineStep struct {
+	Name        string        `json:"name"`
+	Phase       string        `json:"phase"`
+	Duration    time.Duration `json:"duration"`
+	MemoryBytes int64         `json:"memoryBytes,omitempty"`
+	CPUMillis   int64         `json:"cpuMillis,omitempty"`
+	LogBytes    int64         `json:"logBytes"`
+}
+
+// BaselineOptions holds options for recording a baseline
+type BaselineOptions struct {
+	Namespace string
+	Pipeline  string
+	Timeout   time.Duration
+}
+
+// CompareOptions holds the thresholds of a baseline comparison
+type CompareOptions struct {
+	DurationThreshold float64
+	MemoryThreshold   float64
+}
+
+// BaselineRegression is a difference between a baseline and a later run
+type BaselineRegression struct {
+	Pipeline string `json:"pipeline"`
+	Step     string `json:"step,omitempty"`
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+}
+
+// BaselineComparison lists the regressions of a run against a baseline
+type BaselineComparison struct {
+	Pipelines   int                  `json:"pipelines"`
+	Steps       int                  `json:"steps"`
+	Regressions []BaselineRegression `json:"regressions,omitempty"`
+}
+
+// Passed reports whether the run has no regressions
+func (c *BaselineComparison) Passed() bool {
+	return len(c.Regressions) == 0
+}
+
+// Passing returns the baseline restricted to the pipelines that succeeded
+func (b *Baseline) Passing() *Baseline {
+	passing := *b
+	passing.Pipelines = nil
+	for _, pipeline := range b.Pipelines {
+		if pipeline.Status == "Success" {
+			passing.Pipelines = append(passing.Pipelines, pipeline)
+		}
+	}
+	return &passing
+}
+
+// RecordBaseline runs the pipeline tests of a namespace and records the
+// duration, peak resource usage and log size of every step
+func RecordBaseline(ctx context.Context, c client.Client, opts BaselineOptions) (*Baseline, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+
+	usage := newStepUsage()
+	samplerCtx, stopSampler := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		usage.sample(samplerCtx, c, opts.Namespace)
+	}()
+
+	summary, err := RunPipelineTests(ctx, opts.Namespace, opts.Pipeline, opts.Timeout)
+	stopSampler()
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := &Baseline{CreatedAt: time.Now().UTC(), Namespace: opts.Namespace}
+	for _, result := range summary.Results {
+		pipeline := BaselinePipeline{
+			Name:     result.Name,
+			RunName:  result.RunName,
+			Status:   result.Status,
+			Duration: result.Duration,
+		}
+
+		run := &c8sv1alpha1.PipelineRun{}
+		if result.RunName != "" {
+			if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: result.RunName}, run); err == nil {
+				pipeline.Steps = BaselineSteps(run, usage.peaks(result.RunName))
+			}
+		}
+		for i := range pipeline.Steps {
+			pipeline.Steps[i].LogBytes = stepLogBytes(opts.Namespace, result.RunName, pipeline.Steps[i].Name)
+		}
+
+		baseline.Pipelines = append(baseline.Pipelines, pipeline)
+	}
+
+	return baseline, nil
+}
+
+// BaselineSteps returns the steps of a finished run with the peak resource
+// usage recorded for them
+func BaselineSteps(run *c8sv1alpha1.PipelineRun, peaks map[string]StepUsage) []BaselineStep {
+	steps := make([]BaselineStep, 0, len(run.Status.Steps))
+	for _, status := range run.Status.Steps {
+		step := BaselineStep{Name: status.Name, Phase: string(status.Phase)}
+		if status.StartTime != nil && status.CompletionTime != nil {
+			step.Duration = status.CompletionTime.Sub(status.StartTime.Time)
+		}
+		if peak, ok := peaks[stepLabelValue(status.Name)]; ok {
+			step.MemoryBytes = peak.MemoryBytes
+			step.CPUMillis = peak.CPUMillis
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// CompareBaselines reports the pipelines and steps of current that fail
+// where they passed in baseline, take more than opts.DurationThreshold
+// longer or use more than opts.MemoryThreshold more memory
+func CompareBaselines(baseline, current *Baseline, opts CompareOptions) *BaselineComparison {
+	if opts.DurationThreshold <= 0 {
+		opts.DurationThreshold = DefaultDurationThreshold
+	}
+	if opts.MemoryThreshold <= 0 {
+		opts.MemoryThreshold = DefaultMemoryThreshold
+	}
+
+	runs := make(map[string]*BaselinePipeline, len(current.Pipelines))
+	for i := range current.Pipelines {
+		runs[current.Pipelines[i].Name] = &current.Pipelines[i]
+	}
+
+	comparison := &BaselineComparison{}
+	for _, want := range baseline.Pipelines {
+		if want.Status != "Success" {
+			continue
+		}
+		comparison.Pipelines++
+
+		got, ok := runs[want.Name]
+		if !ok {
+			comparison.Regressions = append(comparison.Regressions, BaselineRegression{
+				Pipeline: want.Name,
+				Kind:     RegressionMissing,
+				Message:  "pipeline was not run",
+			})
+			continue
+		}
+		if got.Status != want.Status {
+			comparison.Regressions = append(comparison.Regressions, BaselineRegression{
+				Pipeline: want.Name,
+				Kind:     RegressionFailure,
+				Message:  fmt.Sprintf("status %s, was %s", got.Status, want.Status),
+			})
+		}
+
+		steps := make(map[string]BaselineStep, len(got.Steps))
+		for _, step := range got.Steps {
+			steps[step.Name] = step
+		}
+		for _, wantStep := range want.Steps {
+			if wantStep.Phase != string(c8sv1alpha1.StepPhaseSucceeded) {
+				continue
+			}
+			comparison.Steps++
+			comparison.Regressions = append(comparison.Regressions, compareStep(want.Name, wantStep, steps, opts)...)
+		}
+	}
+
+	return comparison
+}
+
+// compareStep returns the regressions of a step that succeeded in the
+// baseline
+func compareStep(pipeline string, want BaselineStep, steps map[string]BaselineStep, opts CompareOptions) []BaselineRegression {
+	got, ok := steps[want.Name]
+	if !ok {
+		return []BaselineRegression{{
+			Pipeline: pipeline,
+			Step:     want.Name,
+			Kind:     RegressionMissing,
+			Message:  "step did not run",
+		}}
+	}
+	if got.Phase != want.Phase {
+		return []BaselineRegression{{
+			Pipeline: pipeline,
+			Step:     want.Name,
+			Kind:     RegressionFailure,
+			Message:  fmt.Sprintf("phase %s, was %s", got.Phase, want.Phase),
+		}}
+	}
+
+	var regressions []BaselineRegression
+	limit := time.Duration(float64(want.Duration) * (1 + opts.DurationThreshold))
+	if got.Duration > limit && got.Duration-want.Duration >= minDurationIncrease {
+		regressions = append(regressions, BaselineRegression{
+			Pipeline: pipeline,
+			Step:     want.Name,
+			Kind:     RegressionDuration,
+			Message: fmt.Sprintf("took %s, was %s (+%.0f%%)", got.Duration, want.Duration,
+				increase(float64(want.Duration), float64(got.Duration))),
+		})
+	}
+	if want.MemoryBytes > 0 && float64(got.MemoryBytes) > float64(want.MemoryBytes)*(1+opts.MemoryThreshold) {
+		regressions = append(regressions, BaselineRegression{
+			Pipeline: pipeline,
+			Step:     want.Name,
+			Kind:     RegressionMemory,
+			Message: fmt.Sprintf("used %s memory, was %s (+%.0f%%)",
+				resource.NewQuantity(got.MemoryBytes, resource.BinarySI),
+				resource.NewQuantity(want.MemoryBytes, resource.BinarySI),
+				increase(float64(want.MemoryBytes), float64(got.MemoryBytes))),
+		})
+	}
+	return regressions
+}
+
+// increase returns the increase from was to now in percent
+func increase(was, now float64) float64 {
+	return (now - was) / was * 100
+}
+
+// SaveBaseline writes a baseline to a JSON file
+func SaveBaseline(path string, baseline *Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// LoadBaseline reads a baseline saved by SaveBaseline
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	baseline := &Baseline{}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// StepUsage is the peak resource usage of a step pod
+type StepUsage struct {
+	MemoryBytes int64
+	CPUMillis   int64
+}
+
+// stepUsage collects the peak usage of step pods by run and step name
+type stepUsage struct {
+	mu    sync.Mutex
+	usage map[string]map[string]StepUsage
+}
+
+func newStepUsage() *stepUsage {
+	return &stepUsage{usage: map[string]map[string]StepUsage{}}
+}
+
+// sample records the metrics of step pods until ctx is done. Sampling
+// stops silently when metrics-server is not installed.
+func (u *stepUsage) sample(ctx context.Context, c client.Client, namespace string) {
+	ticker := time.NewTicker(usageSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(podMetricsKind)
+		if err := c.List(ctx, list, client.InNamespace(namespace), client.HasLabels{types.LabelPipelineRun}); err == nil {
+			for _, item := range list.Items {
+				u.record(&item)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record keeps the usage of a PodMetrics object if it is above the peak of
+// its step
+func (u *stepUsage) record(metrics *unstructured.Unstructured) {
+	labels := metrics.GetLabels()
+	run, step := labels[types.LabelPipelineRun], labels[types.LabelStepName]
+	if run == "" || step == "" {
+		return
+	}
+
+	var current StepUsage
+	containers, _, _ := unstructured.NestedSlice(metrics.Object, "containers")
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if memory, ok, _ := unstructured.NestedString(container, "usage", "memory"); ok {
+			if quantity, err := resource.ParseQuantity(memory); err == nil {
+				current.MemoryBytes += quantity.Value()
+			}
+		}
+		if cpu, ok, _ := unstructured.NestedString(container, "usage", "cpu"); ok {
+			if quantity, err := resource.ParseQuantity(cpu); err == nil {
+				current.CPUMillis += quantity.MilliValue()
+			}
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.usage[run] == nil {
+		u.usage[run] = map[string]StepUsage{}
+	}
+	peak := u.usage[run][step]
+	peak.MemoryBytes = max(peak.MemoryBytes, current.MemoryBytes)
+	peak.CPUMillis = max(peak.CPUMillis, current.CPUMillis)
+	u.usage[run][step] = peak
+}
+
+// peaks returns the peak usage of the steps of a run by step label value
+func (u *stepUsage) peaks(run string) map[string]StepUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.usage[run]
+}
+
+// stepLogBytes returns the size of the logs of a step's pods, or 0 if they
+// can't be read
+func stepLogBytes(namespace, runName, step string) int64 {
+	selector := fmt.Sprintf("%s=%s,%s=%s", types.LabelPipelineRun, runName, types.LabelStepName, stepLabelValue(step))
+	output, err := exec.Command("kubectl", "-n", namespace, "logs", "-l", selector, "--tail=-1", "--all-containers").Output()
+	if err != nil {
+		return 0
+	}
+	return int64(len(output))
+}
+
+// stepLabelValue returns the c8s.dev/step-name label value of a step, as
+// set by the controller
+func stepLabelValue(step string) string {
+	return strings.ReplaceAll(step, "/", ".")
+}
@@ -0,0 +1,137 @@
+package samples
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// CleanOptions holds options for deleting test-created PipelineRuns and Jobs
+type CleanOptions struct {
+	Namespace string
+
+	// OlderThan only deletes runs that completed at least this long ago;
+	// zero deletes all test runs, including running ones
+	OlderThan time.Duration
+
+	// DryRun lists what would be deleted without deleting it
+	DryRun bool
+}
+
+// CleanResult lists the deleted (or, in dry-run mode, matching) objects
+type CleanResult struct {
+	PipelineRuns []string `json:"pipelineRuns"`
+	Jobs         []string `json:"jobs"`
+}
+
+// NewClusterClient creates a controller-runtime client for the k3d context of
+// a cluster, with the c8s types registered
+func NewClusterClient(clusterName string) (client.Client, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: fmt.Sprintf("k3d-%s", clusterName)}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := c8sv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return c, nil
+}
+
+// CleanTestRuns deletes the PipelineRuns created by `c8s dev test run` and
+// their Jobs, selected by the c8s.dev/created-by=c8s-test label. Without
+// OlderThan everything is removed with DeleteAllOf; otherwise only runs that
+// completed before the cutoff, and their Jobs, are deleted.
+func CleanTestRuns(ctx context.Context, c client.Client, opts CleanOptions) (*CleanResult, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+
+	selector := client.MatchingLabels{types.LabelCreatedBy: types.CreatedByTest}
+
+	var runs c8sv1alpha1.PipelineRunList
+	if err := c.List(ctx, &runs, client.InNamespace(opts.Namespace), selector); err != nil {
+		return nil, fmt.Errorf("failed to list PipelineRuns: %w", err)
+	}
+	var jobs batchv1.JobList
+	if err := c.List(ctx, &jobs, client.InNamespace(opts.Namespace), selector); err != nil {
+		return nil, fmt.Errorf("failed to list Jobs: %w", err)
+	}
+
+	result := &CleanResult{}
+	cutoff := time.Now().Add(-opts.OlderThan)
+	selected := make(map[string]bool)
+	for _, run := range runs.Items {
+		if opts.OlderThan > 0 && !completedBefore(&run, cutoff) {
+			continue
+		}
+		selected[run.Name] = true
+		result.PipelineRuns = append(result.PipelineRuns, run.Name)
+	}
+	for _, job := range jobs.Items {
+		if opts.OlderThan > 0 && !selected[job.Labels[types.LabelPipelineRun]] {
+			continue
+		}
+		result.Jobs = append(result.Jobs, job.Name)
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	propagation := client.PropagationPolicy(metav1.DeletePropagationBackground)
+
+	if opts.OlderThan == 0 {
+		if err := c.DeleteAllOf(ctx, &batchv1.Job{}, client.InNamespace(opts.Namespace), selector, propagation); err != nil {
+			return nil, fmt.Errorf("failed to delete Jobs: %w", err)
+		}
+		if err := c.DeleteAllOf(ctx, &c8sv1alpha1.PipelineRun{}, client.InNamespace(opts.Namespace), selector, propagation); err != nil {
+			return nil, fmt.Errorf("failed to delete PipelineRuns: %w", err)
+		}
+		return result, nil
+	}
+
+	for _, name := range result.PipelineRuns {
+		runSelector := client.MatchingLabels{
+			types.LabelCreatedBy:   types.CreatedByTest,
+			types.LabelPipelineRun: name,
+		}
+		if err := c.DeleteAllOf(ctx, &batchv1.Job{}, client.InNamespace(opts.Namespace), runSelector, propagation); err != nil {
+			return nil, fmt.Errorf("failed to delete Jobs of %s: %w", name, err)
+		}
+
+		run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: opts.Namespace}}
+		if err := c.Delete(ctx, run, propagation); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to delete PipelineRun %s: %w", name, err)
+		}
+	}
+
+	return result, nil
+}
+
+// completedBefore reports whether a run has completed before the cutoff
+func completedBefore(run *c8sv1alpha1.PipelineRun, cutoff time.Time) bool {
+	return run.Status.CompletionTime != nil && run.Status.CompletionTime.Time.Before(cutoff)
+}
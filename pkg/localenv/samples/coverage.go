@@ -0,0 +1,268 @@
+package samples
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoverageProfileName is the artifact name expected from steps running `go test -coverprofile`
+const CoverageProfileName = "coverage.out"
+
+// CoverageReport contains the merged coverage results for a test run
+type CoverageReport struct {
+	Profiles        []string // Artifact URLs the profiles were downloaded from
+	MergedProfile   string   // Path to the merged profile on disk
+	ReportFile      string   // Path to the generated HTML report
+	TotalStatements int
+	CoveredStmts    int
+	Percent         float64
+	Message         string
+}
+
+// CollectCoverage downloads coverage.out artifacts from the given pipeline runs,
+// merges them, and renders an HTML report to reportFile.
+// If reportFile is empty, the report is written to a temporary directory.
+func CollectCoverage(ctx context.Context, namespace string, runNames []string, reportFile string) (*CoverageReport, error) {
+	report := &CoverageReport{}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	workDir, err := os.MkdirTemp("", "c8s-coverage-")
+	if err != nil {
+		return report, fmt.Errorf("failed to create coverage work directory: %w", err)
+	}
+
+	var profilePaths []string
+	for _, runName := range runNames {
+		urls, err := getCoverageArtifactURLs(namespace, runName)
+		if err != nil {
+			return report, err
+		}
+
+		for _, url := range urls {
+			path := filepath.Join(workDir, fmt.Sprintf("%d-%s", len(profilePaths), CoverageProfileName))
+			if err := downloadArtifact(ctx, url, path); err != nil {
+				return report, fmt.Errorf("failed to download coverage profile for %s: %w", runName, err)
+			}
+			report.Profiles = append(report.Profiles, url)
+			profilePaths = append(profilePaths, path)
+		}
+	}
+
+	if len(profilePaths) == 0 {
+		report.Message = "No coverage profiles found (steps must run 'go test -coverprofile=coverage.out' and upload it as an artifact)"
+		return report, nil
+	}
+
+	report.MergedProfile = filepath.Join(workDir, CoverageProfileName)
+	total, covered, err := MergeCoverageProfiles(profilePaths, report.MergedProfile)
+	if err != nil {
+		return report, err
+	}
+
+	report.TotalStatements = total
+	report.CoveredStmts = covered
+	if total > 0 {
+		report.Percent = float64(covered) / float64(total) * 100
+	}
+
+	if reportFile == "" {
+		reportFile = filepath.Join(workDir, "coverage.html")
+	}
+	report.ReportFile = reportFile
+
+	cmd := exec.CommandContext(ctx, "go", "tool", "cover", "-html="+report.MergedProfile, "-o", reportFile)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return report, fmt.Errorf("failed to generate coverage report: %v\nOutput: %s", err, output)
+	}
+
+	report.Message = fmt.Sprintf("Merged %d coverage profile(s): %.1f%% of statements covered", len(profilePaths), report.Percent)
+	return report, nil
+}
+
+// MergeCoverageProfiles merges Go text coverage profiles into a single profile.
+// Blocks reported by several profiles are combined: counts are summed in
+// "count"/"atomic" mode and OR'd in "set" mode.
+// It returns the total and covered statement counts of the merged profile.
+func MergeCoverageProfiles(paths []string, outputPath string) (int, int, error) {
+	type block struct {
+		stmts int
+		count int
+	}
+
+	mode := ""
+	blocks := make(map[string]*block)
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to open coverage profile: %w", err)
+		}
+
+		scanner := bufio.NewScanner(f)
+		lineNum := 0
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			lineNum++
+			if line == "" {
+				continue
+			}
+
+			if strings.HasPrefix(line, "mode:") {
+				fileMode := strings.TrimSpace(strings.TrimPrefix(line, "mode:"))
+				if mode == "" {
+					mode = fileMode
+				} else if mode != fileMode {
+					f.Close()
+					return 0, 0, fmt.Errorf("cannot merge coverage profiles with different modes: %s and %s", mode, fileMode)
+				}
+				continue
+			}
+
+			// Format: name.go:line.column,line.column numberOfStatements count
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				f.Close()
+				return 0, 0, fmt.Errorf("invalid coverage line %d in %s: %q", lineNum, path, line)
+			}
+
+			stmts, err := strconv.Atoi(fields[1])
+			if err != nil {
+				f.Close()
+				return 0, 0, fmt.Errorf("invalid statement count on line %d in %s: %w", lineNum, path, err)
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil {
+				f.Close()
+				return 0, 0, fmt.Errorf("invalid hit count on line %d in %s: %w", lineNum, path, err)
+			}
+
+			existing, ok := blocks[fields[0]]
+			if !ok {
+				blocks[fields[0]] = &block{stmts: stmts, count: count}
+				continue
+			}
+
+			if mode == "set" {
+				if count > 0 {
+					existing.count = 1
+				}
+			} else {
+				existing.count += count
+			}
+		}
+
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read coverage profile %s: %w", path, err)
+		}
+	}
+
+	if mode == "" {
+		mode = "set"
+	}
+
+	keys := make([]string, 0, len(blocks))
+	for key := range blocks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "mode: %s\n", mode)
+
+	total, covered := 0, 0
+	for _, key := range keys {
+		b := blocks[key]
+		fmt.Fprintf(&out, "%s %d %d\n", key, b.stmts, b.count)
+		total += b.stmts
+		if b.count > 0 {
+			covered += b.stmts
+		}
+	}
+
+	if err := os.WriteFile(outputPath, []byte(out.String()), 0644); err != nil {
+		return 0, 0, fmt.Errorf("failed to write merged coverage profile: %w", err)
+	}
+
+	return total, covered, nil
+}
+
+// OpenInBrowser opens a local file with the platform's default browser
+func OpenInBrowser(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	return nil
+}
+
+// getCoverageArtifactURLs returns the artifact URLs of a pipeline run that point at coverage profiles
+func getCoverageArtifactURLs(namespace string, runName string) ([]string, error) {
+	cmd := exec.Command("kubectl", "-n", namespace, "get", "pipelinerun", runName,
+		"-o", `jsonpath={range .status.steps[*]}{range .artifactURLs[*]}{@}{"\n"}{end}{end}`)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artifacts for PipelineRun %s: %v\nOutput: %s", runName, err, output)
+	}
+
+	var urls []string
+	for _, url := range strings.Fields(string(output)) {
+		// Strip any query string (signed URLs) before matching the file name
+		name := strings.SplitN(url, "?", 2)[0]
+		if filepath.Base(name) == CoverageProfileName {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls, nil
+}
+
+// downloadArtifact downloads an artifact from a (signed) URL to a local path
+func downloadArtifact(ctx context.Context, url string, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
@@ -153,16 +153,8 @@ func applySampleManifest(manifestPath string, namespace string) error {
 		return err
 	}
 
-	// Apply using kubectl with namespace flag
 	// kubectl apply -n flag will inject namespace, no need to modify manifest
-	cmd := exec.Command("kubectl", "apply", "-n", namespace, "-f", "-")
-	cmd.Stdin = strings.NewReader(string(content))
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("kubectl apply failed: %v\nOutput: %s", err, output)
-	}
-
-	return nil
+	return applyManifest(content, namespace)
 }
 
 // validateManifest checks if a YAML manifest is valid
@@ -0,0 +1,148 @@
+package samples
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// lintRepository stands in for the repository of pipeline files, which is
+// set by the webhook or 'c8s apply' rather than in the file
+const lintRepository = "https://github.com/example-org/example-repo"
+
+// PipelineFileSuffixes are the name suffixes of the pipeline files
+// LintPipelineFiles validates, e.g. .c8s.yaml or api.c8s.yml
+var PipelineFileSuffixes = []string{".c8s.yaml", ".c8s.yml"}
+
+// LintFileResult is the validation result of a pipeline file
+type LintFileResult struct {
+	// Path is relative to the root directory
+	Path   string   `json:"path"`
+	Valid  bool     `json:"valid"`
+	Steps  int      `json:"steps,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// LintResult is the summary of the validation of all pipeline files under a
+// directory
+type LintResult struct {
+	Root   string           `json:"root"`
+	Files  []LintFileResult `json:"files"`
+	Passed int              `json:"passed"`
+	Failed int              `json:"failed"`
+}
+
+// LintPipelineFiles walks root and parses and validates every pipeline
+// file, in lexical order. Directories and files whose name or path relative
+// to root matches one of ignorePatterns (filepath.Match globs) are skipped,
+// as are .git directories.
+func LintPipelineFiles(root string, ignorePatterns []string) (*LintResult, error) {
+	for _, pattern := range ignorePatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+	}
+
+	result := &LintResult{Root: root, Files: []LintFileResult{}}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if rel != "." && (entry.Name() == ".git" || matchesIgnorePattern(rel, ignorePatterns)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isPipelineFile(entry.Name()) || matchesIgnorePattern(rel, ignorePatterns) {
+			return nil
+		}
+
+		file := lintPipelineFile(path)
+		file.Path = rel
+		if file.Valid {
+			result.Passed++
+		} else {
+			result.Failed++
+		}
+		result.Files = append(result.Files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	return result, nil
+}
+
+// lintPipelineFile parses and validates a pipeline file
+func lintPipelineFile(path string) LintFileResult {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return LintFileResult{Errors: []string{err.Error()}}
+	}
+
+	spec, err := parser.ParseBytes(content)
+	if err != nil {
+		return LintFileResult{Errors: validationMessages(err)}
+	}
+	if spec.Repository == "" {
+		spec.Repository = lintRepository
+	}
+	if err := parser.Validate(&c8sv1alpha1.PipelineConfig{Spec: *spec}); err != nil {
+		return LintFileResult{Errors: validationMessages(err)}
+	}
+
+	return LintFileResult{Valid: true, Steps: len(spec.Steps)}
+}
+
+// validationMessages splits parser validation errors into one message per
+// field
+func validationMessages(err error) []string {
+	var validationErrors *parser.ValidationErrors
+	if !errors.As(err, &validationErrors) || len(validationErrors.Errors) == 0 {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, 0, len(validationErrors.Errors))
+	for _, e := range validationErrors.Errors {
+		messages = append(messages, e.Error())
+	}
+	return messages
+}
+
+// isPipelineFile reports whether a file name has one of
+// PipelineFileSuffixes
+func isPipelineFile(name string) bool {
+	for _, suffix := range PipelineFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesIgnorePattern reports whether the relative path or the base name of
+// an entry matches one of patterns
+func matchesIgnorePattern(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
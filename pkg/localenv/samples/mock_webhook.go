@@ -0,0 +1,367 @@
+package samples
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+const (
+	// DefaultMockWebhookSettle is how long runs are still awaited after the
+	// last event was sent
+	DefaultMockWebhookSettle = 30 * time.Second
+
+	// mockWebhookPollInterval is the time between two lists of the created
+	// runs, which bounds the precision of the measured latency
+	mockWebhookPollInterval = 250 * time.Millisecond
+)
+
+// MockWebhookOptions holds options for load testing the webhook service
+type MockWebhookOptions struct {
+	// WebhookURL is the base URL of the webhook service
+	// (e.g., "http://localhost:8080")
+	WebhookURL string
+
+	Provider      string
+	Repository    string
+	RepositoryURL string
+	Branch        string
+	Secret        string
+
+	// Namespace is where the RepositoryConnection creates the runs
+	Namespace string
+
+	// Rate is the number of events sent per second, for Duration
+	Rate     rate.Limit
+	Duration time.Duration
+
+	// DuplicateRatio is the fraction of events resending the commit of an
+	// earlier event, which must not create another run
+	DuplicateRatio float64
+
+	// Settle is how long runs are still awaited after the last event
+	// (default DefaultMockWebhookSettle)
+	Settle time.Duration
+}
+
+// MockWebhookResult reports the throughput, errors and latency of a load test
+type MockWebhookResult struct {
+	Provider string        `json:"provider"`
+	Rate     float64       `json:"rate"`
+	Duration time.Duration `json:"duration"`
+
+	Sent       int `json:"sent"`
+	Duplicates int `json:"duplicates"`
+	Accepted   int `json:"accepted"`
+
+	// Rejected counts the events answered with an HTTP error, Failed those
+	// that got no answer
+	Rejected    int         `json:"rejected"`
+	Failed      int         `json:"failed"`
+	StatusCodes map[int]int `json:"statusCodes,omitempty"`
+	ErrorRate   float64     `json:"errorRate"`
+
+	// Throughput is the number of events accepted per second
+	Throughput float64 `json:"throughputPerSecond"`
+
+	RunsCreated int     `json:"runsCreated"`
+	RunRate     float64 `json:"runsPerSecond"`
+
+	// MissingRuns counts the accepted commits no run was created for
+	MissingRuns int `json:"missingRuns"`
+
+	// Latency is the time from sending an event to its run being created
+	Latency MockWebhookLatency `json:"latency"`
+
+	Errors []string `json:"errors,omitempty"`
+}
+
+// MockWebhookLatency summarizes the latencies of the created runs
+type MockWebhookLatency struct {
+	Min    time.Duration `json:"min"`
+	Median time.Duration `json:"median"`
+	P95    time.Duration `json:"p95"`
+	P99    time.Duration `json:"p99"`
+	Max    time.Duration `json:"max"`
+}
+
+// ParseRate parses an event rate such as "10/s", "300/m" or "5" (per second)
+func ParseRate(value string) (rate.Limit, error) {
+	count, unit, found := strings.Cut(value, "/")
+	if !found {
+		unit = "s"
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid rate %q: must be a positive number of events per s, m or h", value)
+	}
+
+	switch strings.TrimSpace(unit) {
+	case "s":
+		return rate.Limit(n), nil
+	case "m":
+		return rate.Limit(n / 60), nil
+	case "h":
+		return rate.Limit(n / 3600), nil
+	default:
+		return 0, fmt.Errorf("invalid rate %q: unit must be s, m or h", value)
+	}
+}
+
+// mockWebhookRun records when the event of a commit was first sent and when
+// its run appeared
+type mockWebhookRun struct {
+	sentAt    time.Time
+	accepted  bool
+	createdAt time.Time
+}
+
+// MockWebhook sends synthetic push events for new random commits to the
+// webhook service at opts.Rate for opts.Duration, then waits up to
+// opts.Settle for the runs of the accepted commits, and reports throughput,
+// error rate and the latency from each event to its run
+func MockWebhook(ctx context.Context, c client.Client, opts MockWebhookOptions) (*MockWebhookResult, error) {
+	if opts.Rate <= 0 {
+		return nil, fmt.Errorf("rate must be positive")
+	}
+	if opts.Settle == 0 {
+		opts.Settle = DefaultMockWebhookSettle
+	}
+
+	result := &MockWebhookResult{
+		Provider:    opts.Provider,
+		Rate:        float64(opts.Rate),
+		StatusCodes: map[int]int{},
+	}
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		commits []string
+		runs    = map[string]*mockWebhookRun{}
+		seen    = map[string]bool{}
+	)
+	recordError := func(err error) {
+		if !seen[err.Error()] {
+			seen[err.Error()] = true
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+
+	limiter := rate.NewLimiter(opts.Rate, 1)
+	sendCtx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	start := time.Now()
+	observed := make(chan struct{})
+	observeCtx, stopObserving := context.WithCancel(ctx)
+	defer stopObserving()
+	go func() {
+		defer close(observed)
+		observeMockWebhookRuns(observeCtx, c, opts.Namespace, &mu, runs)
+	}()
+
+	for limiter.Wait(sendCtx) == nil {
+		mu.Lock()
+		var commit string
+		if len(commits) > 0 && mathrand.Float64() < opts.DuplicateRatio {
+			commit = commits[mathrand.Intn(len(commits))]
+			result.Duplicates++
+		} else {
+			commit = mockCommitSHA()
+			commits = append(commits, commit)
+			runs[commit[:8]] = &mockWebhookRun{sentAt: time.Now()}
+		}
+		result.Sent++
+		mu.Unlock()
+
+		wg.Add(1)
+		go func(commit string) {
+			defer wg.Done()
+			status, err := sendMockWebhookEvent(ctx, opts, commit)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				result.Failed++
+				recordError(err)
+			case status >= 300:
+				result.Rejected++
+				result.StatusCodes[status]++
+			default:
+				result.Accepted++
+				result.StatusCodes[status]++
+				runs[commit[:8]].accepted = true
+			}
+		}(commit)
+	}
+	wg.Wait()
+	result.Duration = time.Since(start)
+
+	// Wait for the runs of the accepted commits; those still missing after
+	// opts.Settle are reported as MissingRuns
+	settle := time.NewTimer(opts.Settle)
+	defer settle.Stop()
+waitRuns:
+	for !mockWebhookRunsCreated(&mu, runs) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-settle.C:
+			break waitRuns
+		case <-time.After(mockWebhookPollInterval):
+		}
+	}
+	stopObserving()
+	<-observed
+
+	summarizeMockWebhook(result, runs)
+	return result, nil
+}
+
+// observeMockWebhookRuns lists the runs of namespace until ctx is done,
+// recording when the run of each sent commit first appears
+func observeMockWebhookRuns(ctx context.Context, c client.Client, namespace string, mu *sync.Mutex, runs map[string]*mockWebhookRun) {
+	ticker := time.NewTicker(mockWebhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var list c8sv1alpha1.PipelineRunList
+		if err := c.List(ctx, &list, client.InNamespace(namespace)); err == nil {
+			now := time.Now()
+			mu.Lock()
+			for _, run := range list.Items {
+				if sent, ok := runs[run.Labels[types.LabelCommit]]; ok && sent.createdAt.IsZero() {
+					sent.createdAt = now
+				}
+			}
+			mu.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// mockWebhookRunsCreated reports whether every accepted commit has a run
+func mockWebhookRunsCreated(mu *sync.Mutex, runs map[string]*mockWebhookRun) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, run := range runs {
+		if run.accepted && run.createdAt.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// summarizeMockWebhook computes the rates and latencies of a load test
+func summarizeMockWebhook(result *MockWebhookResult, runs map[string]*mockWebhookRun) {
+	if result.Sent > 0 {
+		result.ErrorRate = float64(result.Rejected+result.Failed) / float64(result.Sent)
+	}
+	if seconds := result.Duration.Seconds(); seconds > 0 {
+		result.Throughput = float64(result.Accepted) / seconds
+	}
+
+	var latencies []time.Duration
+	var lastCreated time.Time
+	for _, run := range runs {
+		if run.createdAt.IsZero() {
+			if run.accepted {
+				result.MissingRuns++
+			}
+			continue
+		}
+		result.RunsCreated++
+		latencies = append(latencies, run.createdAt.Sub(run.sentAt))
+		if run.createdAt.After(lastCreated) {
+			lastCreated = run.createdAt
+		}
+	}
+	if len(latencies) == 0 {
+		return
+	}
+
+	// Runs are counted over the time from the first event to the last run
+	var firstSent time.Time
+	for _, run := range runs {
+		if firstSent.IsZero() || run.sentAt.Before(firstSent) {
+			firstSent = run.sentAt
+		}
+	}
+	if window := lastCreated.Sub(firstSent).Seconds(); window > 0 {
+		result.RunRate = float64(result.RunsCreated) / window
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.Latency = MockWebhookLatency{
+		Min:    latencies[0],
+		Median: latencies[len(latencies)/2],
+		P95:    latencies[latencyIndex(len(latencies), 0.95)],
+		P99:    latencies[latencyIndex(len(latencies), 0.99)],
+		Max:    latencies[len(latencies)-1],
+	}
+}
+
+// latencyIndex returns the index of a percentile in a sorted slice of length n
+func latencyIndex(n int, percentile float64) int {
+	idx := int(float64(n)*percentile+0.5) - 1
+	if idx < 0 {
+		return 0
+	}
+	if idx >= n {
+		return n - 1
+	}
+	return idx
+}
+
+// sendMockWebhookEvent sends a push of commit and returns the HTTP status
+func sendMockWebhookEvent(ctx context.Context, opts MockWebhookOptions, commit string) (int, error) {
+	url := fmt.Sprintf("%s/webhooks/%s", strings.TrimSuffix(opts.WebhookURL, "/"), opts.Provider)
+	req, err := webhook.NewTestPushRequest(ctx, url, opts.Provider, webhook.TestPushEvent{
+		Repository:    opts.Repository,
+		RepositoryURL: opts.RepositoryURL,
+		Branch:        opts.Branch,
+		Commit:        commit,
+		Message:       "Load test commit from c8s dev test mock-webhook",
+		Author:        "c8s-dev",
+		AuthorEmail:   "c8s-dev@example.com",
+	}, opts.Secret)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// mockCommitSHA returns a random 40-character hex commit SHA
+func mockCommitSHA() string {
+	buf := make([]byte, 20)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
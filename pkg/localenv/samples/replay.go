@@ -0,0 +1,445 @@
+package samples
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+const (
+	// DefaultReplayTimeout is how long a replay waits for the runs of a
+	// session to finish
+	DefaultReplayTimeout = 10 * time.Minute
+
+	// replayPollInterval is the time between two checks of the replayed runs
+	replayPollInterval = 2 * time.Second
+)
+
+// ReplaySession is a saved test session: the PipelineConfig under test, the
+// webhook events that triggered runs of it, and the state they ended in
+type ReplaySession struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+
+	PipelineConfig ReplayPipelineConfig `json:"pipelineConfig"`
+
+	// RepositoryConnection routes the events to the PipelineConfig; when
+	// unset, a matching connection must already exist in the namespace
+	RepositoryConnection *ReplayRepositoryConnection `json:"repositoryConnection,omitempty"`
+
+	Events   []ReplayEvent     `json:"events"`
+	Expected ReplayExpectation `json:"expected"`
+}
+
+// ReplayPipelineConfig is the PipelineConfig applied before the events are sent
+type ReplayPipelineConfig struct {
+	Name string                         `json:"name"`
+	Spec c8sv1alpha1.PipelineConfigSpec `json:"spec"`
+}
+
+// ReplayRepositoryConnection is the RepositoryConnection applied before the
+// events are sent
+type ReplayRepositoryConnection struct {
+	Name string                               `json:"name"`
+	Spec c8sv1alpha1.RepositoryConnectionSpec `json:"spec"`
+}
+
+// ReplayEvent is a webhook delivery: either a recorded one, sent as-is with
+// its headers, or a synthetic push built like 'c8s dev webhook test'
+type ReplayEvent struct {
+	Provider string `json:"provider"`
+
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload json.RawMessage   `json:"payload,omitempty"`
+
+	Push *ReplayPush `json:"push,omitempty"`
+
+	// Secret signs a synthetic push
+	Secret string `json:"secret,omitempty"`
+}
+
+// ReplayPush describes a synthetic push event
+type ReplayPush struct {
+	Repository    string `json:"repository"`
+	RepositoryURL string `json:"repositoryURL,omitempty"`
+	Branch        string `json:"branch"`
+	Commit        string `json:"commit"`
+	Message       string `json:"message,omitempty"`
+	Author        string `json:"author,omitempty"`
+}
+
+// ReplayExpectation is the state the runs of a session must end in
+type ReplayExpectation struct {
+	Runs []ExpectedRun `json:"runs"`
+}
+
+// ExpectedRun is a PipelineRun the events must create, identified by the
+// commit that triggered it
+type ExpectedRun struct {
+	Commit string                       `json:"commit"`
+	Branch string                       `json:"branch,omitempty"`
+	Phase  c8sv1alpha1.PipelineRunPhase `json:"phase"`
+	Steps  []ExpectedStep               `json:"steps,omitempty"`
+}
+
+// ExpectedStep is the terminal phase of a step of an expected run
+type ExpectedStep struct {
+	Name  string                `json:"name"`
+	Phase c8sv1alpha1.StepPhase `json:"phase"`
+}
+
+// ReplayOptions holds options for replaying a session
+type ReplayOptions struct {
+	// WebhookURL is the base URL of the webhook service
+	// (e.g., "http://localhost:8080")
+	WebhookURL string
+	Timeout    time.Duration
+}
+
+// ReplayResult compares the runs created by a replay with the expected ones
+type ReplayResult struct {
+	Session string            `json:"session,omitempty"`
+	Passed  bool              `json:"passed"`
+	Runs    []ReplayRunResult `json:"runs"`
+
+	// Unexpected are runs created by the replay that the session does not expect
+	Unexpected []string `json:"unexpected,omitempty"`
+}
+
+// ReplayRunResult is the comparison of an expected run with the actual one
+type ReplayRunResult struct {
+	Commit      string   `json:"commit"`
+	RunName     string   `json:"runName,omitempty"`
+	Phase       string   `json:"phase,omitempty"`
+	Differences []string `json:"differences,omitempty"`
+}
+
+// LoadReplaySession reads and checks a session file
+func LoadReplaySession(path string) (*ReplaySession, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var session ReplaySession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %w", path, err)
+	}
+	if session.Namespace == "" {
+		session.Namespace = "default"
+	}
+
+	if session.PipelineConfig.Name == "" {
+		return nil, fmt.Errorf("invalid session file %s: pipelineConfig.name is required", path)
+	}
+	if len(session.Events) == 0 {
+		return nil, fmt.Errorf("invalid session file %s: no events", path)
+	}
+	for i, event := range session.Events {
+		if event.Provider == "" {
+			return nil, fmt.Errorf("invalid session file %s: events[%d].provider is required", path, i)
+		}
+		if len(event.Payload) == 0 && event.Push == nil {
+			return nil, fmt.Errorf("invalid session file %s: events[%d] needs a payload or a push", path, i)
+		}
+	}
+	for i, run := range session.Expected.Runs {
+		if len(run.Commit) < 8 {
+			return nil, fmt.Errorf("invalid session file %s: expected.runs[%d].commit must have at least 8 characters", path, i)
+		}
+	}
+
+	return &session, nil
+}
+
+// Request builds the webhook delivery of an event for the webhook service at
+// baseURL
+func (e *ReplayEvent) Request(ctx context.Context, baseURL string) (*http.Request, error) {
+	url := fmt.Sprintf("%s/webhooks/%s", strings.TrimSuffix(baseURL, "/"), e.Provider)
+
+	if len(e.Payload) == 0 {
+		return webhook.NewTestPushRequest(ctx, url, e.Provider, webhook.TestPushEvent{
+			Repository:    e.Push.Repository,
+			RepositoryURL: e.Push.RepositoryURL,
+			Branch:        e.Push.Branch,
+			Commit:        e.Push.Commit,
+			Message:       e.Push.Message,
+			Author:        e.Push.Author,
+			AuthorEmail:   e.Push.Author + "@example.com",
+		}, e.Secret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(e.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range e.Headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
+
+// Replay applies the PipelineConfig of a session, deletes earlier runs of
+// its expected commits, sends its events to the webhook service, and waits
+// for the created runs to finish before comparing them with the expected ones
+func Replay(ctx context.Context, c client.Client, session *ReplaySession, opts ReplayOptions) (*ReplayResult, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultReplayTimeout
+	}
+
+	if err := applyReplayObjects(ctx, c, session); err != nil {
+		return nil, err
+	}
+	if err := deleteReplayRuns(ctx, c, session); err != nil {
+		return nil, err
+	}
+
+	start := time.Now().Truncate(time.Second)
+	for i := range session.Events {
+		if err := sendReplayEvent(ctx, &session.Events[i], opts.WebhookURL); err != nil {
+			return nil, fmt.Errorf("events[%d]: %w", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	for {
+		runs, err := listReplayRuns(ctx, c, session, start)
+		if err != nil {
+			return nil, err
+		}
+		if replayFinished(session.Expected.Runs, runs) {
+			result := CompareReplayRuns(session.Expected.Runs, runs)
+			result.Session = session.Name
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			result := CompareReplayRuns(session.Expected.Runs, runs)
+			result.Session = session.Name
+			return result, fmt.Errorf("timeout waiting for the replayed runs to finish")
+		case <-time.After(replayPollInterval):
+		}
+	}
+}
+
+// CompareReplayRuns compares runs with the expected ones, matching them by
+// commit
+func CompareReplayRuns(expected []ExpectedRun, runs []c8sv1alpha1.PipelineRun) *ReplayResult {
+	result := &ReplayResult{Passed: true}
+	matched := make(map[string]bool)
+
+	for _, want := range expected {
+		runResult := ReplayRunResult{Commit: want.Commit}
+
+		run := findReplayRun(runs, want.Commit)
+		if run == nil {
+			runResult.Differences = []string{"no PipelineRun was created"}
+		} else {
+			matched[run.Name] = true
+			runResult.RunName = run.Name
+			runResult.Phase = string(run.Status.Phase)
+			runResult.Differences = runDifferences(&want, run)
+		}
+
+		if len(runResult.Differences) > 0 {
+			result.Passed = false
+		}
+		result.Runs = append(result.Runs, runResult)
+	}
+
+	for _, run := range runs {
+		if !matched[run.Name] {
+			result.Unexpected = append(result.Unexpected, run.Name)
+			result.Passed = false
+		}
+	}
+
+	return result
+}
+
+// runDifferences lists how a run differs from the expected one
+func runDifferences(want *ExpectedRun, run *c8sv1alpha1.PipelineRun) []string {
+	var differences []string
+	if want.Branch != "" && run.Spec.Branch != want.Branch {
+		differences = append(differences, fmt.Sprintf("branch %s, expected %s", run.Spec.Branch, want.Branch))
+	}
+	if run.Status.Phase != want.Phase {
+		differences = append(differences, fmt.Sprintf("phase %s, expected %s", valueOrUnset(string(run.Status.Phase)), want.Phase))
+	}
+	if len(want.Steps) == 0 {
+		return differences
+	}
+
+	actual := make(map[string]c8sv1alpha1.StepPhase, len(run.Status.Steps))
+	for _, step := range run.Status.Steps {
+		actual[step.Name] = step.Phase
+	}
+	expectedSteps := make(map[string]bool, len(want.Steps))
+	for _, step := range want.Steps {
+		expectedSteps[step.Name] = true
+		phase, ok := actual[step.Name]
+		switch {
+		case !ok:
+			differences = append(differences, fmt.Sprintf("step %s did not execute", step.Name))
+		case phase != step.Phase:
+			differences = append(differences, fmt.Sprintf("step %s phase %s, expected %s", step.Name, valueOrUnset(string(phase)), step.Phase))
+		}
+	}
+	for _, step := range run.Status.Steps {
+		if !expectedSteps[step.Name] {
+			differences = append(differences, fmt.Sprintf("unexpected step %s", step.Name))
+		}
+	}
+	return differences
+}
+
+// valueOrUnset returns value, or "<unset>" when it is empty
+func valueOrUnset(value string) string {
+	if value == "" {
+		return "<unset>"
+	}
+	return value
+}
+
+// findReplayRun returns the run triggered by a commit
+func findReplayRun(runs []c8sv1alpha1.PipelineRun, commit string) *c8sv1alpha1.PipelineRun {
+	for i := range runs {
+		if strings.HasPrefix(runs[i].Spec.Commit, commit) {
+			return &runs[i]
+		}
+	}
+	return nil
+}
+
+// replayFinished reports whether every expected run exists and has finished
+func replayFinished(expected []ExpectedRun, runs []c8sv1alpha1.PipelineRun) bool {
+	for _, want := range expected {
+		run := findReplayRun(runs, want.Commit)
+		if run == nil {
+			return false
+		}
+		switch run.Status.Phase {
+		case c8sv1alpha1.PipelineRunPhaseSucceeded, c8sv1alpha1.PipelineRunPhaseFailed, c8sv1alpha1.PipelineRunPhaseCancelled:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// applyReplayObjects creates or updates the PipelineConfig and
+// RepositoryConnection of a session
+func applyReplayObjects(ctx context.Context, c client.Client, session *ReplaySession) error {
+	config := &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: session.PipelineConfig.Name, Namespace: session.Namespace},
+		Spec:       session.PipelineConfig.Spec,
+	}
+	if err := applyReplayObject(ctx, c, config, &c8sv1alpha1.PipelineConfig{}); err != nil {
+		return fmt.Errorf("failed to apply PipelineConfig %s: %w", config.Name, err)
+	}
+
+	if session.RepositoryConnection != nil {
+		conn := &c8sv1alpha1.RepositoryConnection{
+			ObjectMeta: metav1.ObjectMeta{Name: session.RepositoryConnection.Name, Namespace: session.Namespace},
+			Spec:       session.RepositoryConnection.Spec,
+		}
+		if err := applyReplayObject(ctx, c, conn, &c8sv1alpha1.RepositoryConnection{}); err != nil {
+			return fmt.Errorf("failed to apply RepositoryConnection %s: %w", conn.Name, err)
+		}
+	}
+	return nil
+}
+
+// applyReplayObject creates obj, or updates it when it already exists;
+// existing receives the current object
+func applyReplayObject(ctx context.Context, c client.Client, obj, existing client.Object) error {
+	err := c.Create(ctx, obj)
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return c.Update(ctx, obj)
+}
+
+// deleteReplayRuns deletes runs of the expected commits left by an earlier
+// replay, since the webhook doesn't create a run that already exists
+func deleteReplayRuns(ctx context.Context, c client.Client, session *ReplaySession) error {
+	var runs c8sv1alpha1.PipelineRunList
+	if err := c.List(ctx, &runs, client.InNamespace(session.Namespace),
+		client.MatchingLabels{types.LabelPipelineConfig: session.PipelineConfig.Name}); err != nil {
+		return fmt.Errorf("failed to list PipelineRuns: %w", err)
+	}
+
+	propagation := client.PropagationPolicy(metav1.DeletePropagationBackground)
+	for i := range runs.Items {
+		run := &runs.Items[i]
+		for _, want := range session.Expected.Runs {
+			if run.Labels[types.LabelCommit] != want.Commit[:8] {
+				continue
+			}
+			if err := c.Delete(ctx, run, propagation); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete PipelineRun %s: %w", run.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// listReplayRuns returns the runs of the session's PipelineConfig created
+// since the replay started
+func listReplayRuns(ctx context.Context, c client.Client, session *ReplaySession, since time.Time) ([]c8sv1alpha1.PipelineRun, error) {
+	var runs c8sv1alpha1.PipelineRunList
+	if err := c.List(ctx, &runs, client.InNamespace(session.Namespace),
+		client.MatchingLabels{types.LabelPipelineConfig: session.PipelineConfig.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list PipelineRuns: %w", err)
+	}
+
+	var created []c8sv1alpha1.PipelineRun
+	for _, run := range runs.Items {
+		if run.DeletionTimestamp == nil && !run.CreationTimestamp.Time.Before(since) {
+			created = append(created, run)
+		}
+	}
+	return created, nil
+}
+
+// sendReplayEvent delivers an event to the webhook service
+func sendReplayEvent(ctx context.Context, event *ReplayEvent, baseURL string) error {
+	req, err := event.Request(ctx, baseURL)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+package samples
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+const (
+	// DefaultSuiteTimeout is how long a suite waits for its runs to finish
+	DefaultSuiteTimeout = 10 * time.Minute
+
+	// defaultSuiteCommit is the commit of suite runs that set none
+	defaultSuiteCommit = "0000000000000000000000000000000000000000"
+
+	// suitePollInterval is the time between two checks of the runs of a suite
+	suitePollInterval = 2 * time.Second
+)
+
+// TestSuite is a suite definition file: PipelineConfigs, each run once, and
+// the outcome expected of the runs
+type TestSuite struct {
+	Name      string          `json:"name"`
+	Pipelines []SuitePipeline `json:"pipelines"`
+}
+
+// SuitePipeline is a PipelineConfig of a suite and the outcome expected of
+// its run
+type SuitePipeline struct {
+	Name string                         `json:"name"`
+	Spec c8sv1alpha1.PipelineConfigSpec `json:"spec"`
+
+	// Branch and Commit of the run (default main and a zero commit)
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
+
+	Expected SuiteExpectation `json:"expected"`
+}
+
+// SuiteExpectation is the state the run of a suite pipeline must end in
+type SuiteExpectation struct {
+	// Phase defaults to Succeeded
+	Phase c8sv1alpha1.PipelineRunPhase `json:"phase,omitempty"`
+	Steps []ExpectedStep               `json:"steps,omitempty"`
+}
+
+// SuiteOptions holds options for running test suites
+type SuiteOptions struct {
+	Timeout time.Duration
+
+	// Parallelism limits the suites running at once (0 runs all at once)
+	Parallelism int
+
+	// KeepNamespaces leaves the suite namespaces in place for inspection
+	KeepNamespaces bool
+}
+
+// SuiteResult is the outcome of the runs of a suite
+type SuiteResult struct {
+	Suite     string                `json:"suite"`
+	Namespace string                `json:"namespace"`
+	Passed    int                   `json:"passed"`
+	Failed    int                   `json:"failed"`
+	Duration  time.Duration         `json:"duration"`
+	Pipelines []SuitePipelineResult `json:"pipelines"`
+
+	// Error is set when the suite could not be run to completion
+	Error string `json:"error,omitempty"`
+}
+
+// SuitePipelineResult compares the run of a suite pipeline with the
+// expected outcome
+type SuitePipelineResult struct {
+	Name        string   `json:"name"`
+	RunName     string   `json:"runName,omitempty"`
+	Phase       string   `json:"phase,omitempty"`
+	Passed      bool     `json:"passed"`
+	Differences []string `json:"differences,omitempty"`
+}
+
+// ParallelTestSummary aggregates the results of suites run concurrently
+type ParallelTestSummary struct {
+	Suites []SuiteResult `json:"suites"`
+	Total  int           `json:"total"`
+	Passed int           `json:"passed"`
+	Failed int           `json:"failed"`
+
+	// Duration is the wall-clock time of all suites; SequentialDuration is
+	// the time they would have taken one after another
+	Duration           time.Duration `json:"duration"`
+	SequentialDuration time.Duration `json:"sequentialDuration"`
+}
+
+// Succeeded reports whether every suite ran and all its pipelines passed
+func (s *ParallelTestSummary) Succeeded() bool {
+	for _, suite := range s.Suites {
+		if suite.Error != "" || suite.Failed > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadTestSuite reads and checks a suite definition file (YAML or JSON).
+// Suites without a name are named after the file.
+func LoadTestSuite(path string) (*TestSuite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var suite TestSuite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("invalid suite file %s: %w", path, err)
+	}
+	if suite.Name == "" {
+		base := path[strings.LastIndex(path, "/")+1:]
+		suite.Name = strings.TrimSuffix(strings.TrimSuffix(base, ".yaml"), ".yml")
+	}
+
+	if len(suite.Pipelines) == 0 {
+		return nil, fmt.Errorf("invalid suite file %s: no pipelines", path)
+	}
+	names := make(map[string]bool, len(suite.Pipelines))
+	for i := range suite.Pipelines {
+		pipeline := &suite.Pipelines[i]
+		if pipeline.Name == "" {
+			return nil, fmt.Errorf("invalid suite file %s: pipelines[%d].name is required", path, i)
+		}
+		if names[pipeline.Name] {
+			return nil, fmt.Errorf("invalid suite file %s: duplicate pipeline %s", path, pipeline.Name)
+		}
+		names[pipeline.Name] = true
+		if len(pipeline.Spec.Steps) == 0 {
+			return nil, fmt.Errorf("invalid suite file %s: pipeline %s has no steps", path, pipeline.Name)
+		}
+		if pipeline.Branch == "" {
+			pipeline.Branch = "main"
+		}
+		if pipeline.Commit == "" {
+			pipeline.Commit = defaultSuiteCommit
+		}
+		if pipeline.Expected.Phase == "" {
+			pipeline.Expected.Phase = c8sv1alpha1.PipelineRunPhaseSucceeded
+		}
+	}
+
+	return &suite, nil
+}
+
+// SuiteNamespace returns the namespace a suite runs in: the suite name,
+// made DNS-1123 compliant, followed by the start time
+func SuiteNamespace(suite string, start time.Time) string {
+	name := strings.Trim(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(suite)), "-")
+
+	suffix := start.Format("20060102-150405")
+	if maxLen := 63 - len(suffix) - 1; len(name) > maxLen {
+		name = strings.TrimRight(name[:maxLen], "-")
+	}
+	if name == "" {
+		name = "suite"
+	}
+	return name + "-" + suffix
+}
+
+// RunTestSuites runs suites concurrently, each in its own namespace, and
+// aggregates their results. A suite that fails to run is reported in its
+// result and does not stop the others.
+func RunTestSuites(ctx context.Context, c client.Client, suites []*TestSuite, opts SuiteOptions) *ParallelTestSummary {
+	start := time.Now()
+	results := make([]SuiteResult, len(suites))
+
+	var group errgroup.Group
+	if opts.Parallelism > 0 {
+		group.SetLimit(opts.Parallelism)
+	}
+	for i, suite := range suites {
+		group.Go(func() error {
+			results[i] = RunTestSuite(ctx, c, suite, opts)
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	summary := &ParallelTestSummary{Suites: results, Duration: time.Since(start)}
+	for _, result := range results {
+		summary.Total += len(result.Pipelines)
+		summary.Passed += result.Passed
+		summary.Failed += result.Failed
+		summary.SequentialDuration += result.Duration
+	}
+	return summary
+}
+
+// RunTestSuite creates the namespace of a suite and its PipelineConfigs,
+// runs each once, and waits for the runs to finish before comparing them
+// with the expected outcomes. The namespace is deleted afterwards unless
+// opts.KeepNamespaces is set.
+func RunTestSuite(ctx context.Context, c client.Client, suite *TestSuite, opts SuiteOptions) SuiteResult {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultSuiteTimeout
+	}
+	start := time.Now()
+	result := SuiteResult{Suite: suite.Name, Namespace: SuiteNamespace(suite.Name, start)}
+
+	runs, err := startSuiteRuns(ctx, c, suite, result.Namespace)
+	if err == nil {
+		err = waitForSuiteRuns(ctx, c, result.Namespace, runs, opts.Timeout)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	var list c8sv1alpha1.PipelineRunList
+	if listErr := c.List(ctx, &list, client.InNamespace(result.Namespace)); listErr != nil && result.Error == "" {
+		result.Error = fmt.Sprintf("failed to list PipelineRuns: %v", listErr)
+	}
+	result.Pipelines = CompareSuiteRuns(suite, runs, list.Items)
+	for _, pipeline := range result.Pipelines {
+		if pipeline.Passed {
+			result.Passed++
+		} else {
+			result.Failed++
+		}
+	}
+
+	if !opts.KeepNamespaces {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: result.Namespace}}
+		if err := client.IgnoreNotFound(c.Delete(context.Background(), namespace)); err != nil && result.Error == "" {
+			result.Error = fmt.Sprintf("failed to delete namespace %s: %v", result.Namespace, err)
+		}
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// startSuiteRuns creates the namespace, PipelineConfigs and PipelineRuns of
+// a suite, and returns the run names by pipeline name
+func startSuiteRuns(ctx context.Context, c client.Client, suite *TestSuite, namespace string) (map[string]string, error) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   namespace,
+		Labels: map[string]string{types.LabelCreatedBy: types.CreatedByTest},
+	}}
+	if err := c.Create(ctx, ns); err != nil {
+		return nil, fmt.Errorf("failed to create namespace %s: %w", namespace, err)
+	}
+
+	runs := make(map[string]string, len(suite.Pipelines))
+	for _, pipeline := range suite.Pipelines {
+		config := &c8sv1alpha1.PipelineConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: pipeline.Name, Namespace: namespace},
+			Spec:       pipeline.Spec,
+		}
+		if err := c.Create(ctx, config); err != nil {
+			return runs, fmt.Errorf("failed to create PipelineConfig %s: %w", pipeline.Name, err)
+		}
+
+		run := &c8sv1alpha1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pipeline.Name + "-run",
+				Namespace: namespace,
+				Labels:    map[string]string{types.LabelCreatedBy: types.CreatedByTest},
+			},
+			Spec: c8sv1alpha1.PipelineRunSpec{
+				PipelineConfigRef: pipeline.Name,
+				Commit:            pipeline.Commit,
+				Branch:            pipeline.Branch,
+				TriggeredBy:       types.CreatedByTest,
+				Environment:       c8sv1alpha1.EnvironmentDevelopment,
+			},
+		}
+		if err := c.Create(ctx, run); err != nil {
+			return runs, fmt.Errorf("failed to create PipelineRun for %s: %w", pipeline.Name, err)
+		}
+		runs[pipeline.Name] = run.Name
+	}
+	return runs, nil
+}
+
+// waitForSuiteRuns waits until every run of a suite has finished
+func waitForSuiteRuns(ctx context.Context, c client.Client, namespace string, runs map[string]string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		var list c8sv1alpha1.PipelineRunList
+		if err := c.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list PipelineRuns: %w", err)
+		}
+
+		finished := 0
+		for _, run := range list.Items {
+			switch run.Status.Phase {
+			case c8sv1alpha1.PipelineRunPhaseSucceeded, c8sv1alpha1.PipelineRunPhaseFailed, c8sv1alpha1.PipelineRunPhaseCancelled:
+				finished++
+			}
+		}
+		if finished >= len(runs) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for %d of %d runs to finish", len(runs)-finished, len(runs))
+		case <-time.After(suitePollInterval):
+		}
+	}
+}
+
+// CompareSuiteRuns compares the runs of a suite, named by runNames per
+// pipeline, with the expected outcomes
+func CompareSuiteRuns(suite *TestSuite, runNames map[string]string, runs []c8sv1alpha1.PipelineRun) []SuitePipelineResult {
+	byName := make(map[string]*c8sv1alpha1.PipelineRun, len(runs))
+	for i := range runs {
+		byName[runs[i].Name] = &runs[i]
+	}
+
+	results := make([]SuitePipelineResult, 0, len(suite.Pipelines))
+	for _, pipeline := range suite.Pipelines {
+		result := SuitePipelineResult{Name: pipeline.Name, RunName: runNames[pipeline.Name]}
+
+		run := byName[result.RunName]
+		if run == nil {
+			result.Differences = []string{"no PipelineRun was created"}
+		} else {
+			result.Phase = string(run.Status.Phase)
+			result.Differences = runDifferences(&ExpectedRun{
+				Phase: pipeline.Expected.Phase,
+				Steps: pipeline.Expected.Steps,
+			}, run)
+		}
+		result.Passed = len(result.Differences) == 0
+		results = append(results, result)
+	}
+	return results
+}
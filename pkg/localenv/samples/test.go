@@ -6,12 +6,16 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
 )
 
 // PipelineTestResult contains the result of running a single pipeline test
 type PipelineTestResult struct {
 	Name       string
 	Namespace  string
+	RunName    string
 	Status     string // Running, Success, Failed, Timeout
 	Duration   time.Duration
 	StartTime  time.Time
@@ -29,6 +33,7 @@ type PipelineTestSummary struct {
 	Duration     time.Duration
 	Results      []PipelineTestResult
 	Message      string
+	Coverage     *CoverageReport
 }
 
 // RunPipelineTests executes pipeline tests
@@ -71,6 +76,7 @@ func RunPipelineTests(ctx context.Context, namespace string, pipelineFilter stri
 
 		// Create PipelineRun resource
 		runName := fmt.Sprintf("%s-run-%d", config, time.Now().Unix())
+		result.RunName = runName
 		err := createPipelineRun(namespace, config, runName)
 		if err != nil {
 			result.Status = "Failed"
@@ -147,11 +153,14 @@ kind: PipelineRun
 metadata:
   name: %s
   namespace: %s
+  labels:
+    %s: %s
 spec:
   pipelineConfigRef:
     name: %s
+  environment: %s
   timeout: 10m
-`, runName, namespace, configName)
+`, runName, namespace, types.LabelCreatedBy, types.CreatedByTest, configName, c8sv1alpha1.EnvironmentDevelopment)
 
 	cmd := exec.Command("kubectl", "apply", "-f", "-")
 	cmd.Stdin = strings.NewReader(manifest)
@@ -0,0 +1,483 @@
+package samples
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+// Webhook failure types sent by SimulateWebhookFailures
+const (
+	FailureSignatureMismatch = "signature-mismatch"
+	FailureMissingField      = "missing-field"
+	FailureMalformedJSON     = "malformed-json"
+	FailureTimeout           = "timeout"
+)
+
+// WebhookFailureTypes are the supported webhook failure types
+var WebhookFailureTypes = []string{FailureSignatureMismatch, FailureMissingField, FailureMalformedJSON, FailureTimeout}
+
+const (
+	// DefaultWebhookFailureTimeout bounds each request. It exceeds the 15s
+	// read timeout of the webhook service, which answers timeout events.
+	DefaultWebhookFailureTimeout = 30 * time.Second
+
+	// webhookEventsMetric is the counter of handled webhook events
+	webhookEventsMetric = "c8s_webhook_events_total"
+)
+
+// Webhook failure checks
+const (
+	CheckStatusCodes    = "status-codes"
+	CheckNoPipelineRuns = "no-pipelineruns"
+	CheckErrorMetrics   = "error-metrics"
+	CheckHealthy        = "healthy"
+	CheckValidEvent     = "valid-event"
+)
+
+// WebhookFailureOptions holds options for sending invalid webhook events
+type WebhookFailureOptions struct {
+	// WebhookURL is the base URL of the webhook service
+	// (e.g., "http://localhost:8080")
+	WebhookURL string
+
+	Provider      string
+	Repository    string
+	RepositoryURL string
+	Branch        string
+
+	// Secret is the webhook secret of the RepositoryConnection, used to
+	// sign the valid event sent after the invalid ones
+	Secret string
+
+	// Namespace is where the RepositoryConnection creates the runs
+	Namespace string
+
+	ErrorType string
+	Count     int
+
+	// Timeout bounds each request and the wait for the run of the valid
+	// event (default DefaultWebhookFailureTimeout)
+	Timeout time.Duration
+}
+
+// WebhookFailureCheck is the outcome of one verification
+type WebhookFailureCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+// WebhookFailureResult reports how the webhook service handled invalid events
+type WebhookFailureResult struct {
+	ErrorType string `json:"errorType"`
+	Provider  string `json:"provider"`
+	Sent      int    `json:"sent"`
+
+	// Responses counts the responses by HTTP status; "closed" counts the
+	// requests the service closed without answering
+	Responses map[string]int `json:"responses"`
+
+	Checks []WebhookFailureCheck `json:"checks"`
+	Passed bool                  `json:"passed"`
+}
+
+// ExpectedFailureStatus returns the HTTP statuses the webhook service answers
+// an event of errorType with
+func ExpectedFailureStatus(errorType string) ([]int, error) {
+	switch errorType {
+	case FailureSignatureMismatch:
+		return []int{http.StatusUnauthorized}, nil
+	case FailureMissingField, FailureMalformedJSON:
+		return []int{http.StatusBadRequest}, nil
+	case FailureTimeout:
+		return []int{http.StatusBadRequest, http.StatusRequestTimeout}, nil
+	default:
+		return nil, fmt.Errorf("unknown error type %q (valid: %s)", errorType, strings.Join(WebhookFailureTypes, ", "))
+	}
+}
+
+// SimulateWebhookFailures sends opts.Count invalid events of opts.ErrorType
+// to the webhook service and verifies that it answers them with the
+// expected status, creates no PipelineRun for them and counts them as
+// rejected in its metrics, then that it is still healthy and creates the
+// PipelineRun of a valid event
+func SimulateWebhookFailures(ctx context.Context, c client.Client, opts WebhookFailureOptions) (*WebhookFailureResult, error) {
+	expected, err := ExpectedFailureStatus(opts.ErrorType)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Count <= 0 {
+		return nil, fmt.Errorf("count must be positive")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultWebhookFailureTimeout
+	}
+
+	result := &WebhookFailureResult{
+		ErrorType: opts.ErrorType,
+		Provider:  opts.Provider,
+		Responses: map[string]int{},
+	}
+
+	rejectedBefore, metricsErr := webhookEventCount(ctx, opts.WebhookURL, opts.Provider, webhook.OutcomeRejected)
+
+	// Invalid events are sent at once, so timeout events wait for the read
+	// timeout of the service together
+	commits := make([]string, opts.Count)
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		sendErrors []string
+	)
+	for i := range commits {
+		commits[i] = mockCommitSHA()
+		wg.Add(1)
+		go func(commit string) {
+			defer wg.Done()
+			status, err := sendFailureEvent(ctx, opts, commit)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil && opts.ErrorType == FailureTimeout && ctx.Err() == nil:
+				result.Responses["closed"]++
+			case err != nil:
+				sendErrors = append(sendErrors, err.Error())
+			default:
+				result.Responses[fmt.Sprint(status)]++
+			}
+		}(commits[i])
+	}
+	wg.Wait()
+	result.Sent = opts.Count
+	if len(sendErrors) == opts.Count {
+		return result, fmt.Errorf("failed to send webhook events: %s", sendErrors[0])
+	}
+
+	result.Checks = append(result.Checks, checkFailureStatuses(result, expected, len(sendErrors)))
+	result.Checks = append(result.Checks, checkNoFailureRuns(ctx, c, opts.Namespace, commits))
+	result.Checks = append(result.Checks, checkFailureMetrics(ctx, opts, rejectedBefore, metricsErr))
+	result.Checks = append(result.Checks, checkWebhookHealth(ctx, opts))
+	result.Checks = append(result.Checks, checkValidEvent(ctx, c, opts))
+
+	result.Passed = true
+	for _, check := range result.Checks {
+		result.Passed = result.Passed && check.Passed
+	}
+	return result, nil
+}
+
+// FailureEventRequest builds an invalid webhook delivery of errorType for
+// commit. Timeout events are returned with a body that stops after half the
+// payload until ctx is done.
+func FailureEventRequest(ctx context.Context, opts WebhookFailureOptions, commit string) (*http.Request, error) {
+	url := fmt.Sprintf("%s/webhooks/%s", strings.TrimSuffix(opts.WebhookURL, "/"), opts.Provider)
+	event := webhook.TestPushEvent{
+		Repository:    opts.Repository,
+		RepositoryURL: opts.RepositoryURL,
+		Branch:        opts.Branch,
+		Commit:        commit,
+		Message:       "Invalid event from c8s dev webhook simulate-failure",
+		Author:        "c8s-dev",
+		AuthorEmail:   "c8s-dev@example.com",
+	}
+
+	// The signature of a mismatched event is computed with another secret;
+	// other events are unsigned
+	secret := ""
+	if opts.ErrorType == FailureSignatureMismatch {
+		secret = "c8s-invalid-" + commit[:8]
+	}
+	req, err := webhook.NewTestPushRequest(ctx, url, opts.Provider, event, secret)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := webhook.BuildTestPushPayload(opts.Provider, event)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.ErrorType {
+	case FailureMissingField:
+		if payload, err = withoutCommit(opts.Provider, payload); err != nil {
+			return nil, err
+		}
+	case FailureMalformedJSON:
+		payload = payload[:len(payload)/2]
+	case FailureTimeout:
+		body, writer := io.Pipe()
+		go func() {
+			_, _ = writer.Write(payload[:len(payload)/2])
+			<-ctx.Done()
+			writer.CloseWithError(ctx.Err())
+		}()
+		req.Body = body
+		req.GetBody = nil
+		req.ContentLength = int64(len(payload))
+		return req, nil
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(payload))
+	req.GetBody = nil
+	req.ContentLength = int64(len(payload))
+	return req, nil
+}
+
+// withoutCommit removes the pushed commit from a push payload of a provider
+func withoutCommit(provider string, payload []byte) ([]byte, error) {
+	var event map[string]interface{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+
+	switch provider {
+	case webhook.ProviderBitbucket:
+		push, _ := event["push"].(map[string]interface{})
+		changes, _ := push["changes"].([]interface{})
+		for _, change := range changes {
+			if target, ok := nestedMap(change, "new", "target"); ok {
+				delete(target, "hash")
+			}
+		}
+	default:
+		delete(event, "after")
+	}
+	return json.Marshal(event)
+}
+
+// nestedMap returns the map at path in obj
+func nestedMap(obj interface{}, path ...string) (map[string]interface{}, bool) {
+	m, ok := obj.(map[string]interface{})
+	for _, key := range path {
+		if !ok {
+			return nil, false
+		}
+		m, ok = m[key].(map[string]interface{})
+	}
+	return m, ok
+}
+
+// sendFailureEvent sends an invalid event and returns the HTTP status
+func sendFailureEvent(ctx context.Context, opts WebhookFailureOptions, commit string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := FailureEventRequest(ctx, opts, commit)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// checkFailureStatuses checks every invalid event got an expected status
+func checkFailureStatuses(result *WebhookFailureResult, expected []int, sendErrors int) WebhookFailureCheck {
+	check := WebhookFailureCheck{Name: CheckStatusCodes, Passed: sendErrors == 0}
+
+	allowed := map[string]bool{}
+	var names []string
+	for _, status := range expected {
+		allowed[fmt.Sprint(status)] = true
+		names = append(names, fmt.Sprintf("HTTP %d", status))
+	}
+	if result.ErrorType == FailureTimeout {
+		allowed["closed"] = true
+	}
+
+	var unexpected []string
+	for response, count := range result.Responses {
+		if !allowed[response] {
+			unexpected = append(unexpected, fmt.Sprintf("%d × %s", count, response))
+		}
+	}
+	sort.Strings(unexpected)
+	if sendErrors > 0 {
+		unexpected = append(unexpected, fmt.Sprintf("%d not sent", sendErrors))
+	}
+
+	if len(unexpected) > 0 {
+		check.Passed = false
+		check.Message = fmt.Sprintf("expected %s, got %s", strings.Join(names, " or "), strings.Join(unexpected, ", "))
+		return check
+	}
+	check.Message = fmt.Sprintf("%d events answered with %s", result.Sent, strings.Join(names, " or "))
+	return check
+}
+
+// checkNoFailureRuns checks no PipelineRun was created for the commits of
+// the invalid events
+func checkNoFailureRuns(ctx context.Context, c client.Client, namespace string, commits []string) WebhookFailureCheck {
+	check := WebhookFailureCheck{Name: CheckNoPipelineRuns}
+
+	var runs c8sv1alpha1.PipelineRunList
+	if err := c.List(ctx, &runs, client.InNamespace(namespace)); err != nil {
+		check.Message = fmt.Sprintf("failed to list PipelineRuns: %v", err)
+		return check
+	}
+	invalid := make(map[string]bool, len(commits))
+	for _, commit := range commits {
+		invalid[commit[:8]] = true
+	}
+
+	var created []string
+	for _, run := range runs.Items {
+		if invalid[run.Labels[types.LabelCommit]] {
+			created = append(created, run.Name)
+		}
+	}
+	if len(created) > 0 {
+		check.Message = fmt.Sprintf("PipelineRuns created for invalid events: %s", strings.Join(created, ", "))
+		return check
+	}
+	check.Passed = true
+	check.Message = "no PipelineRun created for invalid events"
+	return check
+}
+
+// checkFailureMetrics checks the rejected events counter of the provider
+// grew by the number of invalid events
+func checkFailureMetrics(ctx context.Context, opts WebhookFailureOptions, before float64, beforeErr error) WebhookFailureCheck {
+	check := WebhookFailureCheck{Name: CheckErrorMetrics}
+	if beforeErr != nil {
+		check.Message = beforeErr.Error()
+		return check
+	}
+
+	after, err := webhookEventCount(ctx, opts.WebhookURL, opts.Provider, webhook.OutcomeRejected)
+	if err != nil {
+		check.Message = err.Error()
+		return check
+	}
+
+	increase := int(after - before)
+	check.Passed = increase == opts.Count
+	check.Message = fmt.Sprintf(`%s{provider=%q,outcome="rejected"} increased by %d, expected %d`,
+		webhookEventsMetric, opts.Provider, increase, opts.Count)
+	return check
+}
+
+// checkWebhookHealth checks the health endpoint of the webhook service
+func checkWebhookHealth(ctx context.Context, opts WebhookFailureOptions) WebhookFailureCheck {
+	check := WebhookFailureCheck{Name: CheckHealthy}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(opts.WebhookURL, "/")+"/health", nil)
+	if err != nil {
+		check.Message = err.Error()
+		return check
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		check.Message = fmt.Sprintf("health check failed: %v", err)
+		return check
+	}
+	resp.Body.Close()
+
+	check.Passed = resp.StatusCode == http.StatusOK
+	check.Message = fmt.Sprintf("/health returned HTTP %d", resp.StatusCode)
+	return check
+}
+
+// checkValidEvent checks a valid event sent after the invalid ones is
+// accepted and creates its PipelineRun
+func checkValidEvent(ctx context.Context, c client.Client, opts WebhookFailureOptions) WebhookFailureCheck {
+	check := WebhookFailureCheck{Name: CheckValidEvent}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	commit := mockCommitSHA()
+	status, err := sendMockWebhookEvent(ctx, MockWebhookOptions{
+		WebhookURL:    opts.WebhookURL,
+		Provider:      opts.Provider,
+		Repository:    opts.Repository,
+		RepositoryURL: opts.RepositoryURL,
+		Branch:        opts.Branch,
+		Secret:        opts.Secret,
+	}, commit)
+	if err != nil {
+		check.Message = err.Error()
+		return check
+	}
+	if status >= 300 {
+		check.Message = fmt.Sprintf("valid event returned HTTP %d", status)
+		return check
+	}
+
+	for {
+		var runs c8sv1alpha1.PipelineRunList
+		err := c.List(ctx, &runs, client.InNamespace(opts.Namespace),
+			client.MatchingLabels{types.LabelCommit: commit[:8]})
+		if err == nil && len(runs.Items) > 0 {
+			check.Passed = true
+			check.Message = fmt.Sprintf("valid event created PipelineRun %s", runs.Items[0].Name)
+			return check
+		}
+
+		select {
+		case <-ctx.Done():
+			check.Message = fmt.Sprintf("no PipelineRun created for valid commit %s", commit[:8])
+			return check
+		case <-time.After(mockWebhookPollInterval):
+		}
+	}
+}
+
+// webhookEventCount scrapes the metrics of the webhook service and returns
+// the number of events of a provider handled with outcome
+func webhookEventCount(ctx context.Context, baseURL, provider string, outcome webhook.EventOutcome) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/metrics", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read webhook metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to read webhook metrics: HTTP %d", resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook metrics: %w", err)
+	}
+
+	// The counter only appears once an event of the provider was handled
+	family, ok := families[webhookEventsMetric]
+	if !ok {
+		return 0, nil
+	}
+	for _, metric := range family.GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["provider"] == provider && labels["outcome"] == string(outcome) {
+			return metric.GetCounter().GetValue(), nil
+		}
+	}
+	return 0, nil
+}
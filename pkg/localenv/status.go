@@ -12,6 +12,7 @@ type ClusterStatus struct {
 	RegistryEndpoint string       `json:"registryEndpoint,omitempty"`
 	CreatedAt        *time.Time   `json:"createdAt,omitempty"`
 	Uptime           string       `json:"uptime,omitempty"`
+	Addons           []string     `json:"addons,omitempty"`
 }
 
 // NodeStatus represents runtime status for a single node
@@ -30,6 +31,9 @@ const (
 	StateCreating = "creating"
 	StateStarting = "starting"
 	StateDeleting = "deleting"
+
+	// StatePaused is a running cluster whose c8s workloads are scaled to zero
+	StatePaused = "paused"
 )
 
 // NodeState constants
@@ -39,9 +43,10 @@ const (
 	NodeUnknown  = "Unknown"
 )
 
-// IsRunning returns true if the cluster is in running state
+// IsRunning returns true if the cluster is in running state. A paused
+// cluster is running, only its workloads are suspended.
 func (cs *ClusterStatus) IsRunning() bool {
-	return cs.State == StateRunning
+	return cs.State == StateRunning || cs.State == StatePaused
 }
 
 // IsReady returns true if all nodes are ready
@@ -147,6 +147,18 @@ func formatFieldError(e validator.FieldError) string {
 	}
 }
 
+// IsValidClusterName reports whether name contains only lowercase letters,
+// numbers, and hyphens
+func IsValidClusterName(name string) bool {
+	return clusterNamePattern.MatchString(name)
+}
+
+// IsValidNodeFilter reports whether filter is a k3d node filter such as
+// loadbalancer, server:0, or agent:*
+func IsValidNodeFilter(filter string) bool {
+	return nodeFilterPattern.MatchString(filter)
+}
+
 // Custom validation functions
 
 func validateClusterName(fl validator.FieldLevel) bool {
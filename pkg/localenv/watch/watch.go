@@ -0,0 +1,68 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is the quiet period after the last change event before a
+// watched file is considered saved
+const DefaultDebounce = 200 * time.Millisecond
+
+// File calls onChange every time the file at path is saved, until ctx is
+// done. Change events are debounced: onChange runs once debounce has elapsed
+// since the last event, so editors writing a file in several steps trigger a
+// single call.
+//
+// The parent directory is watched rather than the file itself, so saves that
+// replace the file through a rename are still seen.
+func File(ctx context.Context, path string, debounce time.Duration, onChange func()) error {
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(absPath)); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != absPath || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package broker distributes live step logs between the controller, which
+// collects them, and the API servers streaming them to clients.
+package broker
+
+// Broker publishes log data under a key ("namespace/run/step") and fans it
+// out to every subscriber of that key
+type Broker interface {
+	// Publish sends data to the current subscribers of key
+	Publish(key string, data []byte)
+
+	// Subscribe returns a channel receiving the data published to key from
+	// now on. The channel is closed after Unsubscribe.
+	Subscribe(key string) <-chan []byte
+
+	// Unsubscribe stops delivery to a channel returned by Subscribe
+	Unsubscribe(key string, ch <-chan []byte)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redis implements a log broker on Redis PUBSUB, so logs published by
+// the controller reach subscribers in any API server replica.
+package redis
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	goredis "github.com/redis/go-redis/v9"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/org/c8s/pkg/log/broker"
+)
+
+const (
+	// EnvAddress is the environment variable holding the Redis address
+	EnvAddress = "C8S_REDIS_ADDR"
+
+	// EnvPassword is the environment variable holding the Redis password
+	EnvPassword = "C8S_REDIS_PASSWORD"
+
+	// channelPrefix namespaces the PUBSUB channels used for logs
+	channelPrefix = "c8s:logs:"
+
+	// subscriberBufferSize matches the channel size of in-memory log subscribers
+	subscriberBufferSize = 100
+)
+
+// Broker publishes logs to Redis PUBSUB channels named after the log key
+type Broker struct {
+	client *goredis.Client
+
+	mu            sync.Mutex
+	subscriptions map[<-chan []byte]*subscription
+}
+
+// subscription is a Redis PUBSUB subscription forwarded to a log channel
+type subscription struct {
+	pubsub *goredis.PubSub
+	done   chan struct{}
+}
+
+var _ broker.Broker = (*Broker)(nil)
+
+// OptionsFromEnv returns the Redis options from EnvAddress and EnvPassword.
+// The address is empty when Redis is not configured.
+func OptionsFromEnv() *goredis.Options {
+	return &goredis.Options{
+		Addr:     os.Getenv(EnvAddress),
+		Password: os.Getenv(EnvPassword),
+	}
+}
+
+// NewBroker creates a Broker connected to the Redis server of opts
+func NewBroker(opts *goredis.Options) *Broker {
+	return &Broker{
+		client:        goredis.NewClient(opts),
+		subscriptions: make(map[<-chan []byte]*subscription),
+	}
+}
+
+// Ping checks that the Redis server is reachable
+func (b *Broker) Ping(ctx context.Context) error {
+	return b.client.Ping(ctx).Err()
+}
+
+// Publish sends data to the subscribers of key. Errors are logged, since live
+// logs are best effort and remain available from storage.
+func (b *Broker) Publish(key string, data []byte) {
+	if err := b.client.Publish(context.Background(), channelPrefix+key, data).Err(); err != nil {
+		log.Log.WithName("redis-broker").Error(err, "failed to publish logs", "key", key)
+	}
+}
+
+// Subscribe subscribes to the Redis channel of key
+func (b *Broker) Subscribe(key string) <-chan []byte {
+	sub := &subscription{
+		pubsub: b.client.Subscribe(context.Background(), channelPrefix+key),
+		done:   make(chan struct{}),
+	}
+	ch := make(chan []byte, subscriberBufferSize)
+
+	go func() {
+		defer close(ch)
+		for msg := range sub.pubsub.Channel() {
+			select {
+			case ch <- []byte(msg.Payload):
+			case <-sub.done:
+				return
+			}
+		}
+	}()
+
+	b.mu.Lock()
+	b.subscriptions[ch] = sub
+	b.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe closes the Redis subscription behind ch
+func (b *Broker) Unsubscribe(key string, ch <-chan []byte) {
+	b.mu.Lock()
+	sub, exists := b.subscriptions[ch]
+	delete(b.subscriptions, ch)
+	b.mu.Unlock()
+
+	if !exists {
+		return
+	}
+	close(sub.done)
+	if err := sub.pubsub.Close(); err != nil {
+		log.Log.WithName("redis-broker").Error(err, "failed to close subscription", "key", key)
+	}
+}
+
+// Close closes all subscriptions and the connection to Redis
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	subs := b.subscriptions
+	b.subscriptions = make(map[<-chan []byte]*subscription)
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		close(sub.done)
+		_ = sub.pubsub.Close()
+	}
+	return b.client.Close()
+}
@@ -80,6 +80,54 @@ var (
 		[]string{"namespace"},
 	)
 
+	// StepDuration tracks duration of completed pipeline steps in seconds
+	StepDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "c8s_step_duration_seconds",
+			Help:    "Duration of completed pipeline steps in seconds",
+			Buckets: prometheus.ExponentialBuckets(5, 2, 10), // 5s, 10s, 20s, ..., 2560s (~43min)
+		},
+		[]string{"namespace", "step", "phase"},
+	)
+
+	// PipelineQueueDuration tracks how long PipelineRuns wait between
+	// creation and their first step starting
+	PipelineQueueDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "c8s_pipeline_queue_duration_seconds",
+			Help:    "Time from PipelineRun creation to its first step starting in seconds",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12), // 1s, 2s, 4s, ..., 2048s (~34min)
+		},
+		[]string{"namespace", "config"},
+	)
+
+	// LogStorageBytes tracks bytes of step logs uploaded to object storage
+	LogStorageBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "c8s_log_storage_bytes_total",
+			Help: "Total bytes of step logs uploaded to object storage",
+		},
+		[]string{"namespace"},
+	)
+
+	// LogBufferCompressionRatio tracks how much the compressed region of the
+	// in-memory log buffers shrinks the logs it holds
+	LogBufferCompressionRatio = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "c8s_log_buffer_compression_ratio",
+			Help: "Ratio of uncompressed to compressed size of the most recently compressed log buffer",
+		},
+	)
+
+	// WebhookEvents tracks webhook deliveries by provider and outcome
+	WebhookEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "c8s_webhook_events_total",
+			Help: "Total number of webhook events received, by how they were handled",
+		},
+		[]string{"provider", "outcome"},
+	)
+
 	// ReconcileErrors tracks reconciliation errors
 	ReconcileErrors = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -101,6 +149,11 @@ func init() {
 		PendingSteps,
 		FailedSteps,
 		JobCreationDuration,
+		StepDuration,
+		PipelineQueueDuration,
+		LogStorageBytes,
+		LogBufferCompressionRatio,
+		WebhookEvents,
 		ReconcileErrors,
 	)
 }
@@ -141,6 +194,31 @@ func RecordJobCreationDuration(namespace string, durationSeconds float64) {
 	JobCreationDuration.WithLabelValues(namespace).Observe(durationSeconds)
 }
 
+// RecordStepCompleted records the duration of a step that reached a terminal phase
+func RecordStepCompleted(namespace, step, phase string, durationSeconds float64) {
+	StepDuration.WithLabelValues(namespace, step, phase).Observe(durationSeconds)
+}
+
+// RecordPipelineQueueDuration records the time a PipelineRun waited for its first step to start
+func RecordPipelineQueueDuration(namespace, config string, durationSeconds float64) {
+	PipelineQueueDuration.WithLabelValues(namespace, config).Observe(durationSeconds)
+}
+
+// RecordLogUpload adds the size of an uploaded log to the log storage counter
+func RecordLogUpload(namespace string, bytes int) {
+	LogStorageBytes.WithLabelValues(namespace).Add(float64(bytes))
+}
+
+// SetLogBufferCompressionRatio updates the log buffer compression ratio gauge
+func SetLogBufferCompressionRatio(ratio float64) {
+	LogBufferCompressionRatio.Set(ratio)
+}
+
+// RecordWebhookEvent increments the webhook event counter of an outcome
+func RecordWebhookEvent(provider, outcome string) {
+	WebhookEvents.WithLabelValues(provider, outcome).Inc()
+}
+
 // RecordReconcileError increments reconciliation error counter
 func RecordReconcileError(controller, namespace string) {
 	ReconcileErrors.WithLabelValues(controller, namespace).Inc()
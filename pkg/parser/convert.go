@@ -0,0 +1,425 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConvertDefaultImage is the image of converted steps whose source does
+	// not name one, such as GitHub Actions jobs running directly on a runner
+	ConvertDefaultImage = "ubuntu:22.04"
+
+	// pipelineFormatVersion is the version written to converted pipelines
+	pipelineFormatVersion = "v1alpha1"
+)
+
+// invalidNameChars matches characters not allowed in step names
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// ConversionResult is a pipeline converted from another CI system
+type ConversionResult struct {
+	Pipeline *PipelineYAML
+
+	// Warnings describe source features that were dropped or approximated
+	Warnings []string
+}
+
+// Marshal renders the converted pipeline as .c8s.yaml content, preceded by
+// the conversion warnings as comments
+func (r *ConversionResult) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(&buf, "# WARNING: %s\n", warning)
+	}
+	if len(r.Warnings) > 0 {
+		buf.WriteString("\n")
+	}
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(r.Pipeline); err != nil {
+		return nil, fmt.Errorf("failed to render pipeline: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *ConversionResult) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// githubWorkflow is the subset of a GitHub Actions workflow that is converted
+type githubWorkflow struct {
+	Name string               `yaml:"name"`
+	Env  map[string]string    `yaml:"env"`
+	Jobs map[string]githubJob `yaml:"jobs"`
+}
+
+type githubJob struct {
+	Name           string            `yaml:"name"`
+	RunsOn         interface{}       `yaml:"runs-on"`
+	Container      interface{}       `yaml:"container"`
+	Needs          interface{}       `yaml:"needs"`
+	If             string            `yaml:"if"`
+	Env            map[string]string `yaml:"env"`
+	Strategy       *yaml.Node        `yaml:"strategy"`
+	Services       *yaml.Node        `yaml:"services"`
+	TimeoutMinutes int               `yaml:"timeout-minutes"`
+	Steps          []githubStep      `yaml:"steps"`
+}
+
+type githubStep struct {
+	Name string            `yaml:"name"`
+	Uses string            `yaml:"uses"`
+	Run  string            `yaml:"run"`
+	If   string            `yaml:"if"`
+	Env  map[string]string `yaml:"env"`
+}
+
+// ConvertGitHubActions converts a GitHub Actions workflow to a pipeline. Each
+// job becomes a step running the job's `run` commands in the job container
+// image, with `needs` mapped to dependsOn. Actions (`uses`) cannot run in c8s
+// and are reported as warnings, except actions/checkout since c8s clones the
+// repository itself.
+func ConvertGitHubActions(data []byte) (*ConversionResult, error) {
+	var workflow githubWorkflow
+	if err := yaml.Unmarshal(data, &workflow); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+	if len(workflow.Jobs) == 0 {
+		return nil, fmt.Errorf("workflow has no jobs")
+	}
+
+	result := &ConversionResult{
+		Pipeline: &PipelineYAML{Version: pipelineFormatVersion, Name: sanitizeName(workflow.Name, "workflow")},
+	}
+	if len(workflow.Env) > 0 {
+		result.warn("workflow env is not supported; set the variables in the step commands or use secrets")
+	}
+
+	// Map iteration order is random; convert jobs in a stable order
+	ids := make([]string, 0, len(workflow.Jobs))
+	for id := range workflow.Jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		job := workflow.Jobs[id]
+		step := PipelineStepYAML{
+			Name:  sanitizeName(id, "job"),
+			Image: githubJobImage(id, job, result),
+		}
+
+		needs, err := stringOrList(job.Needs)
+		if err != nil {
+			return nil, fmt.Errorf("jobs.%s.needs: %w", id, err)
+		}
+		for _, need := range needs {
+			step.DependsOn = append(step.DependsOn, sanitizeName(need, "job"))
+		}
+
+		if job.TimeoutMinutes > 0 {
+			step.Timeout = fmt.Sprintf("%dm", job.TimeoutMinutes)
+		}
+		if job.If != "" {
+			result.warn("jobs.%s.if (%s) is not supported; the step always runs", id, job.If)
+		}
+		if len(job.Env) > 0 {
+			result.warn("jobs.%s.env is not supported; set the variables in the step commands or use secrets", id)
+		}
+		if job.Strategy != nil {
+			result.warn("jobs.%s.strategy is not supported; use the pipeline-level matrix instead", id)
+		}
+		if job.Services != nil {
+			result.warn("jobs.%s.services is not supported", id)
+		}
+
+		for i, ghStep := range job.Steps {
+			label := ghStep.Name
+			if label == "" {
+				label = fmt.Sprintf("jobs.%s.steps[%d]", id, i)
+			}
+
+			switch {
+			case ghStep.Run != "":
+				step.Commands = append(step.Commands, strings.TrimRight(ghStep.Run, "\n"))
+			case strings.HasPrefix(ghStep.Uses, "actions/checkout"):
+				// c8s clones the repository into the workspace of every step
+			case strings.HasPrefix(ghStep.Uses, "actions/setup-"):
+				result.warn("%s: %s is not supported; pick a step image with the toolchain instead", label, ghStep.Uses)
+			case ghStep.Uses != "":
+				result.warn("%s: action %s is not supported; replace it with commands", label, ghStep.Uses)
+			}
+			if ghStep.If != "" {
+				result.warn("%s: if (%s) is not supported; the command always runs", label, ghStep.If)
+			}
+			if len(ghStep.Env) > 0 {
+				result.warn("%s: env is not supported; set the variables in the command or use secrets", label)
+			}
+		}
+
+		if len(step.Commands) == 0 {
+			result.warn("jobs.%s has no run steps; added a placeholder command", id)
+			step.Commands = []string{fmt.Sprintf("echo 'TODO: port job %s'", id)}
+		}
+
+		result.Pipeline.Steps = append(result.Pipeline.Steps, step)
+	}
+
+	return result, nil
+}
+
+// githubJobImage returns the container image of a job, or the default image
+// with a warning when the job runs directly on a runner
+func githubJobImage(id string, job githubJob, result *ConversionResult) string {
+	switch container := job.Container.(type) {
+	case string:
+		return container
+	case map[string]interface{}:
+		if image, ok := container["image"].(string); ok && image != "" {
+			return image
+		}
+	}
+
+	runsOn := fmt.Sprint(job.RunsOn)
+	if job.RunsOn == nil {
+		runsOn = "unknown runner"
+	}
+	result.warn("jobs.%s runs on %s without a container; using %s", id, runsOn, ConvertDefaultImage)
+	return ConvertDefaultImage
+}
+
+// tektonResource is the subset of a Tekton Pipeline or Task that is converted
+type tektonResource struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		// Pipeline fields
+		Tasks      []tektonPipelineTask `yaml:"tasks"`
+		Finally    []tektonPipelineTask `yaml:"finally"`
+		Params     []yaml.Node          `yaml:"params"`
+		Workspaces []yaml.Node          `yaml:"workspaces"`
+
+		// Task fields
+		Steps []tektonStep `yaml:"steps"`
+	} `yaml:"spec"`
+}
+
+type tektonPipelineTask struct {
+	Name    string `yaml:"name"`
+	TaskRef *struct {
+		Name string `yaml:"name"`
+		Kind string `yaml:"kind"`
+	} `yaml:"taskRef"`
+	TaskSpec *struct {
+		Steps []tektonStep `yaml:"steps"`
+	} `yaml:"taskSpec"`
+	RunAfter []string    `yaml:"runAfter"`
+	When     []yaml.Node `yaml:"when"`
+	Retries  int         `yaml:"retries"`
+	Timeout  string      `yaml:"timeout"`
+}
+
+type tektonStep struct {
+	Name    string   `yaml:"name"`
+	Image   string   `yaml:"image"`
+	Script  string   `yaml:"script"`
+	Command []string `yaml:"command"`
+	Args    []string `yaml:"args"`
+}
+
+// ConvertTekton converts Tekton resources to a pipeline. The input holds a
+// Pipeline and the Tasks it references, as a multi-document YAML stream.
+// Each pipeline task becomes a step running the Task's steps in the image of
+// its first step, with runAfter mapped to dependsOn.
+func ConvertTekton(data []byte) (*ConversionResult, error) {
+	var pipeline *tektonResource
+	tasks := make(map[string]*tektonResource)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var resource tektonResource
+		if err := decoder.Decode(&resource); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse Tekton resources: %w", err)
+		}
+		if !strings.HasPrefix(resource.APIVersion, "tekton.dev/") {
+			continue
+		}
+
+		switch resource.Kind {
+		case "Pipeline":
+			if pipeline != nil {
+				return nil, fmt.Errorf("multiple Pipelines found (%s, %s); convert them separately", pipeline.Metadata.Name, resource.Metadata.Name)
+			}
+			r := resource
+			pipeline = &r
+		case "Task", "ClusterTask":
+			r := resource
+			tasks[resource.Metadata.Name] = &r
+		}
+	}
+	if pipeline == nil {
+		return nil, fmt.Errorf("no Tekton Pipeline found")
+	}
+
+	result := &ConversionResult{
+		Pipeline: &PipelineYAML{Version: pipelineFormatVersion, Name: sanitizeName(pipeline.Metadata.Name, "pipeline")},
+	}
+	if len(pipeline.Spec.Params) > 0 {
+		result.warn("pipeline params are not supported; $(params.*) references are kept as is")
+	}
+	if len(pipeline.Spec.Workspaces) > 0 {
+		result.warn("pipeline workspaces are not supported; every c8s step gets its own workspace with the repository")
+	}
+	if len(pipeline.Spec.Finally) > 0 {
+		result.warn("finally tasks are converted to regular steps that run after all other steps")
+	}
+
+	var lastTasks []string
+	for _, task := range pipeline.Spec.Tasks {
+		lastTasks = append(lastTasks, sanitizeName(task.Name, "task"))
+	}
+
+	allTasks := append(append([]tektonPipelineTask{}, pipeline.Spec.Tasks...), pipeline.Spec.Finally...)
+	for i, task := range allTasks {
+		var steps []tektonStep
+		switch {
+		case task.TaskSpec != nil:
+			steps = task.TaskSpec.Steps
+		case task.TaskRef != nil:
+			referenced, ok := tasks[task.TaskRef.Name]
+			if !ok {
+				return nil, fmt.Errorf("task %s references Task %s, which is not in the input", task.Name, task.TaskRef.Name)
+			}
+			steps = referenced.Spec.Steps
+		default:
+			return nil, fmt.Errorf("task %s has neither taskRef nor taskSpec", task.Name)
+		}
+		if len(steps) == 0 {
+			return nil, fmt.Errorf("task %s has no steps", task.Name)
+		}
+
+		step := PipelineStepYAML{
+			Name:    sanitizeName(task.Name, "task"),
+			Image:   steps[0].Image,
+			Timeout: tektonTimeout(task.Timeout, task.Name, result),
+		}
+		for _, dep := range task.RunAfter {
+			step.DependsOn = append(step.DependsOn, sanitizeName(dep, "task"))
+		}
+		if i >= len(pipeline.Spec.Tasks) {
+			step.DependsOn = lastTasks
+		}
+
+		for _, tStep := range steps {
+			if tStep.Image != step.Image {
+				result.warn("task %s: step %s uses image %s; all steps of the task run in %s", task.Name, tStep.Name, tStep.Image, step.Image)
+			}
+			if command := tektonCommand(tStep); command != "" {
+				step.Commands = append(step.Commands, command)
+			} else {
+				result.warn("task %s: step %s has no script or command", task.Name, tStep.Name)
+			}
+		}
+		if len(step.Commands) == 0 {
+			step.Commands = []string{fmt.Sprintf("echo 'TODO: port task %s'", task.Name)}
+		}
+
+		if len(task.When) > 0 {
+			result.warn("task %s: when expressions are not supported; the step always runs", task.Name)
+		}
+		if task.Retries > 0 {
+			step.Retry = &RetryPolicyYAML{MaxRetries: task.Retries}
+		}
+
+		result.Pipeline.Steps = append(result.Pipeline.Steps, step)
+	}
+
+	return result, nil
+}
+
+// tektonCommand returns the shell command of a Tekton step
+func tektonCommand(step tektonStep) string {
+	if step.Script != "" {
+		return strings.TrimRight(step.Script, "\n")
+	}
+	return strings.Join(append(append([]string{}, step.Command...), step.Args...), " ")
+}
+
+// tektonTimeout checks a Tekton timeout (a Go duration such as "1h30m0s")
+// can be used as a step timeout
+func tektonTimeout(timeout, task string, result *ConversionResult) string {
+	if timeout == "" {
+		return ""
+	}
+	if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+		result.warn("task %s: timeout %s could not be converted", task, timeout)
+		return ""
+	}
+	return timeout
+}
+
+// sanitizeName turns a source identifier into a valid step or pipeline name
+func sanitizeName(name, fallback string) string {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.TrimSpace(name), "-"), "-")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// stringOrList decodes a YAML value that is either a string or a list of strings
+func stringOrList(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string, got %v", item)
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("expected a string or a list, got %v", value)
+	}
+}
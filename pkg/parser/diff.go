@@ -0,0 +1,271 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// Change kinds reported by Diff
+const (
+	ChangeStepAdded         = "StepAdded"
+	ChangeStepRemoved       = "StepRemoved"
+	ChangeStepModified      = "StepModified"
+	ChangeDependencyChanged = "DependencyChanged"
+	ChangeTimeoutChanged    = "TimeoutChanged"
+	ChangeResourceChanged   = "ResourceChanged"
+)
+
+// Change describes a single semantic difference between two pipeline specs
+type Change struct {
+	// Kind is one of the Change* constants
+	Kind string
+
+	// StepName is the affected step (empty for pipeline-level changes)
+	StepName string
+
+	// Detail is a human-readable description of the change
+	Detail string
+}
+
+// String returns a one-line description of the change
+func (c Change) String() string {
+	if c.StepName == "" {
+		return fmt.Sprintf("%s: %s", c.Kind, c.Detail)
+	}
+	return fmt.Sprintf("%s %s: %s", c.Kind, c.StepName, c.Detail)
+}
+
+// Diff computes the semantic differences between two pipeline specs.
+// Step order in the list is ignored; only changes that affect execution are
+// reported. A step whose execution layer moves (because its own or an upstream
+// dependency changed) is reported as DependencyChanged.
+// Changes are returned in a stable order: pipeline-level changes first, then
+// steps in the order of the new spec, then removed steps.
+func Diff(old, new *c8sv1alpha1.PipelineConfigSpec) []Change {
+	if old == nil {
+		old = &c8sv1alpha1.PipelineConfigSpec{}
+	}
+	if new == nil {
+		new = &c8sv1alpha1.PipelineConfigSpec{}
+	}
+
+	var changes []Change
+
+	if old.Timeout != new.Timeout {
+		changes = append(changes, Change{
+			Kind:   ChangeTimeoutChanged,
+			Detail: fmt.Sprintf("pipeline timeout %s -> %s", valueOrNone(old.Timeout), valueOrNone(new.Timeout)),
+		})
+	}
+
+	oldSteps := stepsByName(old.Steps)
+	newSteps := stepsByName(new.Steps)
+	oldLayers := stepLayers(old.Steps)
+	newLayers := stepLayers(new.Steps)
+
+	for _, step := range new.Steps {
+		before, exists := oldSteps[step.Name]
+		if !exists {
+			changes = append(changes, Change{
+				Kind:     ChangeStepAdded,
+				StepName: step.Name,
+				Detail:   fmt.Sprintf("image %s", step.Image),
+			})
+			continue
+		}
+		changes = append(changes, diffStep(before, &step, oldLayers[step.Name], newLayers[step.Name])...)
+	}
+
+	for _, step := range old.Steps {
+		if _, exists := newSteps[step.Name]; !exists {
+			changes = append(changes, Change{
+				Kind:     ChangeStepRemoved,
+				StepName: step.Name,
+				Detail:   fmt.Sprintf("image %s", step.Image),
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffStep compares two versions of the same step
+func diffStep(old, new *c8sv1alpha1.PipelineStep, oldLayer, newLayer int) []Change {
+	var changes []Change
+
+	// Execution-affecting fields other than dependencies, resources and timeout
+	var modified []string
+	if old.Image != new.Image {
+		modified = append(modified, fmt.Sprintf("image %s -> %s", old.Image, new.Image))
+	}
+	if !reflect.DeepEqual(old.Commands, new.Commands) {
+		modified = append(modified, "commands changed")
+	}
+	if old.WorkingDir != new.WorkingDir {
+		modified = append(modified, fmt.Sprintf("workingDir %s -> %s", valueOrNone(old.WorkingDir), valueOrNone(new.WorkingDir)))
+	}
+	if !equalStringSets(old.Artifacts, new.Artifacts) {
+		modified = append(modified, "artifacts changed")
+	}
+	if !reflect.DeepEqual(old.Secrets, new.Secrets) || !reflect.DeepEqual(old.VaultSecrets, new.VaultSecrets) {
+		modified = append(modified, "secrets changed")
+	}
+	if !reflect.DeepEqual(old.Conditional, new.Conditional) {
+		modified = append(modified, "conditional changed")
+	}
+	if len(modified) > 0 {
+		changes = append(changes, Change{
+			Kind:     ChangeStepModified,
+			StepName: new.Name,
+			Detail:   strings.Join(modified, "; "),
+		})
+	}
+
+	var deps []string
+	if !equalStringSets(old.DependsOn, new.DependsOn) {
+		deps = append(deps, fmt.Sprintf("dependsOn %s -> %s", formatList(old.DependsOn), formatList(new.DependsOn)))
+	}
+	if oldLayer != newLayer && oldLayer >= 0 && newLayer >= 0 {
+		deps = append(deps, fmt.Sprintf("execution layer %d -> %d", oldLayer, newLayer))
+	}
+	if len(deps) > 0 {
+		changes = append(changes, Change{
+			Kind:     ChangeDependencyChanged,
+			StepName: new.Name,
+			Detail:   strings.Join(deps, "; "),
+		})
+	}
+
+	if old.Timeout != new.Timeout {
+		changes = append(changes, Change{
+			Kind:     ChangeTimeoutChanged,
+			StepName: new.Name,
+			Detail:   fmt.Sprintf("timeout %s -> %s", valueOrNone(old.Timeout), valueOrNone(new.Timeout)),
+		})
+	}
+
+	oldCPU, oldMemory := resourceValues(old.Resources)
+	newCPU, newMemory := resourceValues(new.Resources)
+	var resources []string
+	if oldCPU != newCPU {
+		resources = append(resources, fmt.Sprintf("cpu %s -> %s", valueOrNone(oldCPU), valueOrNone(newCPU)))
+	}
+	if oldMemory != newMemory {
+		resources = append(resources, fmt.Sprintf("memory %s -> %s", valueOrNone(oldMemory), valueOrNone(newMemory)))
+	}
+	if len(resources) > 0 {
+		changes = append(changes, Change{
+			Kind:     ChangeResourceChanged,
+			StepName: new.Name,
+			Detail:   strings.Join(resources, "; "),
+		})
+	}
+
+	return changes
+}
+
+// stepsByName indexes steps by name
+func stepsByName(steps []c8sv1alpha1.PipelineStep) map[string]*c8sv1alpha1.PipelineStep {
+	index := make(map[string]*c8sv1alpha1.PipelineStep, len(steps))
+	for i := range steps {
+		index[steps[i].Name] = &steps[i]
+	}
+	return index
+}
+
+// stepLayers returns the topological layer (0-based) of each step, matching
+// the layers produced by the scheduler. Steps that are part of a cycle or
+// depend on unknown steps get layer -1.
+func stepLayers(steps []c8sv1alpha1.PipelineStep) map[string]int {
+	index := stepsByName(steps)
+	layers := make(map[string]int, len(steps))
+	visiting := make(map[string]bool)
+
+	var layerOf func(name string) int
+	layerOf = func(name string) int {
+		if layer, done := layers[name]; done {
+			return layer
+		}
+		step, exists := index[name]
+		if !exists || visiting[name] {
+			return -1
+		}
+
+		visiting[name] = true
+		layer := 0
+		for _, dep := range step.DependsOn {
+			depLayer := layerOf(dep)
+			if depLayer < 0 {
+				layer = -1
+				break
+			}
+			if depLayer+1 > layer {
+				layer = depLayer + 1
+			}
+		}
+		visiting[name] = false
+
+		layers[name] = layer
+		return layer
+	}
+
+	for _, step := range steps {
+		layerOf(step.Name)
+	}
+	return layers
+}
+
+// resourceValues returns the CPU and memory of optional resource requirements
+func resourceValues(resources *c8sv1alpha1.ResourceRequirements) (string, string) {
+	if resources == nil {
+		return "", ""
+	}
+	return resources.CPU, resources.Memory
+}
+
+// equalStringSets compares two string slices ignoring order
+func equalStringSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	return reflect.DeepEqual(sortedA, sortedB)
+}
+
+// formatList formats a string slice as a sorted list, e.g. "[a, b]"
+func formatList(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return "[" + strings.Join(sorted, ", ") + "]"
+}
+
+// valueOrNone returns "<none>" for empty values
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+const (
+	// maxStepCommands is the number of commands above which TooManyCommands
+	// suggests splitting a step
+	maxStepCommands = 10
+
+	// maxStepTimeout is the step timeout above which LongTimeout warns
+	maxStepTimeout = 2 * time.Hour
+)
+
+// LintSeverity classifies a LintFinding
+type LintSeverity string
+
+const (
+	// LintSeverityError marks issues that should block the pipeline
+	LintSeverityError LintSeverity = "error"
+
+	// LintSeverityWarning marks likely mistakes or bad practices
+	LintSeverityWarning LintSeverity = "warning"
+
+	// LintSeverityInfo marks suggestions
+	LintSeverityInfo LintSeverity = "info"
+)
+
+// LintFinding is an issue reported by a LintRule
+type LintFinding struct {
+	// Severity of the issue
+	Severity LintSeverity `json:"severity" yaml:"severity"`
+
+	// Step is the name of the step concerned, empty for the whole pipeline
+	Step string `json:"step,omitempty" yaml:"step,omitempty"`
+
+	// Message describes the issue
+	Message string `json:"message" yaml:"message"`
+}
+
+// LintRule is a check of a valid pipeline spec for practices that make
+// pipelines slow, fragile or hard to maintain. Rules registered with
+// RegisterLintRule are run by Lint, i.e. by 'c8s dev lint --strict'.
+type LintRule interface {
+	// Name identifies the rule
+	Name() string
+
+	// Check returns the issues found in a spec
+	Check(spec *c8sv1alpha1.PipelineConfigSpec) []LintFinding
+}
+
+var (
+	lintRulesMu sync.RWMutex
+	lintRules   = []LintRule{NoLatestTag{}, ResourcesRequired{}, TooManyCommands{}, LongTimeout{}}
+)
+
+// RegisterLintRule adds a rule run by Lint after the built-in rules. A rule
+// replaces a registered rule of the same name.
+func RegisterLintRule(rule LintRule) {
+	lintRulesMu.Lock()
+	defer lintRulesMu.Unlock()
+
+	for i, registered := range lintRules {
+		if registered.Name() == rule.Name() {
+			lintRules[i] = rule
+			return
+		}
+	}
+	lintRules = append(lintRules, rule)
+}
+
+// LintRules returns the registered lint rules
+func LintRules() []LintRule {
+	lintRulesMu.RLock()
+	defer lintRulesMu.RUnlock()
+	return append([]LintRule(nil), lintRules...)
+}
+
+// Lint runs every registered lint rule on a spec, which should already have
+// passed Validate
+func Lint(spec *c8sv1alpha1.PipelineConfigSpec) []LintFinding {
+	var findings []LintFinding
+	for _, rule := range LintRules() {
+		findings = append(findings, rule.Check(spec)...)
+	}
+	return findings
+}
+
+// NoLatestTag warns about step images using the latest tag, explicitly or
+// by having no tag, since runs of the same commit may then use different images
+type NoLatestTag struct{}
+
+// Name implements LintRule
+func (NoLatestTag) Name() string { return "NoLatestTag" }
+
+// Check implements LintRule
+func (NoLatestTag) Check(spec *c8sv1alpha1.PipelineConfigSpec) []LintFinding {
+	var findings []LintFinding
+	for _, step := range spec.Steps {
+		if strings.Contains(step.Image, "@") {
+			continue
+		}
+		name := step.Image[strings.LastIndex(step.Image, "/")+1:]
+		_, tag, tagged := strings.Cut(name, ":")
+		switch {
+		case !tagged:
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityWarning,
+				Step:     step.Name,
+				Message:  fmt.Sprintf("image %s has no tag and uses :latest; pin a version", step.Image),
+			})
+		case tag == "latest":
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityWarning,
+				Step:     step.Name,
+				Message:  fmt.Sprintf("image %s uses :latest; pin a version", step.Image),
+			})
+		}
+	}
+	return findings
+}
+
+// ResourcesRequired warns about steps without CPU or memory resources,
+// which get the cluster defaults whatever they run
+type ResourcesRequired struct{}
+
+// Name implements LintRule
+func (ResourcesRequired) Name() string { return "ResourcesRequired" }
+
+// Check implements LintRule
+func (ResourcesRequired) Check(spec *c8sv1alpha1.PipelineConfigSpec) []LintFinding {
+	var findings []LintFinding
+	for _, step := range spec.Steps {
+		if step.Resources == nil || (step.Resources.CPU == "" && step.Resources.Memory == "") {
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityWarning,
+				Step:     step.Name,
+				Message:  "no resources set; set cpu and memory so the step is scheduled predictably",
+			})
+		}
+	}
+	return findings
+}
+
+// TooManyCommands warns about steps with more than 10 commands, which are
+// easier to retry and cache when split
+type TooManyCommands struct{}
+
+// Name implements LintRule
+func (TooManyCommands) Name() string { return "TooManyCommands" }
+
+// Check implements LintRule
+func (TooManyCommands) Check(spec *c8sv1alpha1.PipelineConfigSpec) []LintFinding {
+	var findings []LintFinding
+	for _, step := range spec.Steps {
+		if len(step.Commands) > maxStepCommands {
+			findings = append(findings, LintFinding{
+				Severity: LintSeverityWarning,
+				Step:     step.Name,
+				Message: fmt.Sprintf("%d commands (more than %d); consider splitting the step",
+					len(step.Commands), maxStepCommands),
+			})
+		}
+	}
+	return findings
+}
+
+// LongTimeout warns about steps whose timeout, or the default step timeout,
+// exceeds 2h, so hung steps hold their Jobs for a long time
+type LongTimeout struct{}
+
+// Name implements LintRule
+func (LongTimeout) Name() string { return "LongTimeout" }
+
+// Check implements LintRule
+func (LongTimeout) Check(spec *c8sv1alpha1.PipelineConfigSpec) []LintFinding {
+	var findings []LintFinding
+	for _, step := range spec.Steps {
+		timeout := step.Timeout
+		if timeout == "" {
+			timeout = spec.DefaultStepTimeout
+		}
+		duration, err := time.ParseDuration(timeout)
+		if err != nil || duration <= maxStepTimeout {
+			continue
+		}
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityWarning,
+			Step:     step.Name,
+			Message:  fmt.Sprintf("timeout %s exceeds %s", timeout, maxStepTimeout),
+		})
+	}
+	return findings
+}
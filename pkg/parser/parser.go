@@ -18,72 +18,240 @@ limitations under the License.
 package parser
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/secrets"
+	"github.com/org/c8s/pkg/types"
 )
 
 // PipelineYAML represents the structure of a .c8s.yaml file
 type PipelineYAML struct {
-	Version string             `yaml:"version"`
-	Name    string             `yaml:"name"`
-	Steps   []PipelineStepYAML `yaml:"steps"`
-	Timeout string             `yaml:"timeout,omitempty"`
-	Matrix  *MatrixYAML        `yaml:"matrix,omitempty"`
-	Retry   *RetryPolicyYAML   `yaml:"retryPolicy,omitempty"`
+	// Version is the pipeline format version
+	Version string `yaml:"version" jsonschema:"enum=v1alpha1"`
+
+	// Name identifies the pipeline
+	Name string `yaml:"name"`
+
+	// Steps are the pipeline steps, run in dependency order
+	Steps []PipelineStepYAML `yaml:"steps" jsonschema:"minItems=1"`
+
+	// Timeout is the pipeline timeout (e.g., "30m", "1h30m")
+	Timeout string `yaml:"timeout,omitempty" jsonschema:"pattern=^(0|(([0-9]+([.][0-9]*)?|[.][0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$"`
+
+	// DefaultStepTimeout applies to steps without a timeout (default "30m")
+	DefaultStepTimeout string `yaml:"defaultStepTimeout,omitempty" jsonschema:"pattern=^(0|(([0-9]+([.][0-9]*)?|[.][0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$"`
+
+	// Matrix runs the pipeline once per combination of dimension values
+	Matrix *MatrixYAML `yaml:"matrix,omitempty"`
+
+	// Retry defines how failed steps are retried
+	Retry *RetryPolicyYAML `yaml:"retryPolicy,omitempty"`
+
+	// GlobalMaxLogSizeMB is the log size kept per step, in MB (default 10)
+	GlobalMaxLogSizeMB int `yaml:"globalMaxLogSizeMB,omitempty" jsonschema:"minimum=0;maximum=100"`
+
+	// MaxParallel is the maximum number of steps running at once (0 for no limit)
+	MaxParallel int `yaml:"maxParallel,omitempty" jsonschema:"minimum=0"`
+
+	// DeduplicateSteps merges steps with identical configurations into one
+	DeduplicateSteps bool `yaml:"deduplicateSteps,omitempty"`
 }
 
 // PipelineStepYAML is the YAML representation of a pipeline step
 type PipelineStepYAML struct {
-	Name        string                    `yaml:"name"`
-	Image       string                    `yaml:"image"`
-	Commands    []string                  `yaml:"commands"`
-	DependsOn   []string                  `yaml:"dependsOn,omitempty"`
-	Resources   *ResourceRequirementsYAML `yaml:"resources,omitempty"`
-	Timeout     string                    `yaml:"timeout,omitempty"`
-	Artifacts   []string                  `yaml:"artifacts,omitempty"`
-	Secrets     []SecretReferenceYAML     `yaml:"secrets,omitempty"`
-	Conditional *ConditionalYAML          `yaml:"conditional,omitempty"`
+	// Name identifies the step within the pipeline
+	Name string `yaml:"name" jsonschema:"pattern=^[a-zA-Z0-9_/-]+$"`
+
+	// Image is the container image the step runs in (e.g., "golang:1.25"),
+	// which may reference matrix values
+	Image string `yaml:"image"`
+
+	// Commands are the shell commands to execute in order
+	Commands []string `yaml:"commands" jsonschema:"minItems=1"`
+
+	// WorkingDir is the directory the commands run in, relative to the
+	// workspace unless absolute (default /workspace)
+	WorkingDir string `yaml:"workingDir,omitempty"`
+
+	// DependsOn lists the steps that must complete before this step
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+
+	// Resources define CPU and memory requests for the step
+	Resources *ResourceRequirementsYAML `yaml:"resources,omitempty"`
+
+	// Timeout is the step timeout (e.g., "30m", "2h")
+	Timeout string `yaml:"timeout,omitempty" jsonschema:"pattern=^(0|(([0-9]+([.][0-9]*)?|[.][0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$"`
+
+	// Artifacts are file patterns to upload to artifact storage
+	Artifacts []string `yaml:"artifacts,omitempty"`
+
+	// Secrets are Kubernetes Secret keys to inject as env vars
+	Secrets []SecretReferenceYAML `yaml:"secrets,omitempty"`
+
+	// VaultSecrets are HashiCorp Vault secrets to inject as env vars
+	VaultSecrets []VaultSecretRefYAML `yaml:"vaultSecrets,omitempty"`
+
+	// Conditional defines conditions for step execution
+	Conditional *ConditionalYAML `yaml:"conditional,omitempty"`
+
+	// Retry overrides the pipeline retryPolicy for this step
+	Retry *RetryPolicyYAML `yaml:"retry,omitempty"`
+
+	// MaxLogSizeMB overrides globalMaxLogSizeMB for this step
+	MaxLogSizeMB int `yaml:"maxLogSizeMB,omitempty" jsonschema:"minimum=0;maximum=100"`
+
+	// Weight gives the step priority over steps of lower weight ready at
+	// the same time when maxParallel is set (default 1)
+	Weight int `yaml:"weight,omitempty" jsonschema:"minimum=0"`
+
+	// MatrixDimensions are the matrix dimensions the step runs once per
+	// value of, within a single run
+	MatrixDimensions []string `yaml:"matrixDimensions,omitempty"`
 }
 
 // ResourceRequirementsYAML is the YAML representation of resource requirements
 type ResourceRequirementsYAML struct {
-	CPU    string `yaml:"cpu,omitempty"`
-	Memory string `yaml:"memory,omitempty"`
+	// CPU resource request (e.g., "500m", "0.5", "2")
+	CPU string `yaml:"cpu,omitempty" jsonschema:"pattern=^[+-]?([0-9]+([.][0-9]*)?|[.][0-9]+)([KMGTPE]i|[numkMGTPE]|[eE][+-]?[0-9]+)?$"`
+
+	// Memory resource request (e.g., "1Gi", "512Mi", "512M")
+	Memory string `yaml:"memory,omitempty" jsonschema:"pattern=^[+-]?([0-9]+([.][0-9]*)?|[.][0-9]+)([KMGTPE]i|[numkMGTPE]|[eE][+-]?[0-9]+)?$"`
 }
 
 // SecretReferenceYAML is the YAML representation of a secret reference
 type SecretReferenceYAML struct {
+	// SecretRef is the name of the Kubernetes Secret
 	SecretRef string `yaml:"secretRef"`
-	Key       string `yaml:"key"`
-	EnvVar    string `yaml:"envVar,omitempty"`
+
+	// Key is the key within the Secret
+	Key string `yaml:"key"`
+
+	// EnvVar is the environment variable name (defaults to the key)
+	EnvVar string `yaml:"envVar,omitempty"`
+}
+
+// VaultSecretRefYAML is the YAML representation of a Vault secret reference
+type VaultSecretRefYAML struct {
+	// Path is the Vault secret path
+	Path string `yaml:"path"`
+
+	// Key is the key within the Vault secret
+	Key string `yaml:"key"`
+
+	// EnvVar is the environment variable name (defaults to the key)
+	EnvVar string `yaml:"envVar,omitempty"`
 }
 
 // ConditionalYAML is the YAML representation of conditional execution
 type ConditionalYAML struct {
-	Branch    string `yaml:"branch,omitempty"`
-	OnSuccess bool   `yaml:"onSuccess,omitempty"`
+	// Branch is a regex the branch name must match for the step to run
+	Branch string `yaml:"branch,omitempty"`
+
+	// OnSuccess runs the step only if its dependencies succeeded
+	OnSuccess bool `yaml:"onSuccess,omitempty"`
+
+	// Environment lists the environments the step runs in (development,
+	// staging, production); empty runs it in every environment
+	Environment []string `yaml:"environment,omitempty"`
 }
 
 // MatrixYAML is the YAML representation of matrix strategy
 type MatrixYAML struct {
+	// Dimensions map each dimension name to its values
 	Dimensions map[string][]string `yaml:"dimensions"`
-	Exclude    []map[string]string `yaml:"exclude,omitempty"`
+
+	// Exclude lists dimension combinations to skip
+	Exclude []map[string]string `yaml:"exclude,omitempty"`
 }
 
 // RetryPolicyYAML is the YAML representation of retry policy
 type RetryPolicyYAML struct {
-	MaxRetries     int `yaml:"maxRetries"`
-	BackoffSeconds int `yaml:"backoffSeconds"`
+	// MaxRetries is the maximum number of retries for a failed step
+	MaxRetries int `yaml:"maxRetries,omitempty" jsonschema:"minimum=0"`
+
+	// BackoffSeconds is the delay between retries
+	BackoffSeconds int `yaml:"backoffSeconds,omitempty" jsonschema:"minimum=0"`
+
+	// OnlyOnExitCodes restricts retries to these exit codes (empty retries any failure)
+	OnlyOnExitCodes []int `yaml:"onlyOnExitCodes,omitempty"`
+}
+
+// Validator is an additional check run on a parsed pipeline spec
+type Validator func(spec *c8sv1alpha1.PipelineConfigSpec) error
+
+// Parser parses pipeline YAML content into PipelineConfig specs
+type Parser struct {
+	// StrictMode rejects fields that are not part of the pipeline format
+	StrictMode bool
+
+	// DetectSecrets rejects step commands containing values that look like
+	// hardcoded secrets
+	DetectSecrets bool
+
+	// CustomValidators run after the built-in validation
+	CustomValidators []Validator
+}
+
+// ParserOption configures a Parser
+type ParserOption func(*Parser)
+
+// WithStrictMode rejects unknown fields
+func WithStrictMode() ParserOption {
+	return func(p *Parser) {
+		p.StrictMode = true
+	}
+}
+
+// WithSecretDetection rejects hardcoded secrets in step commands
+func WithSecretDetection() ParserOption {
+	return func(p *Parser) {
+		p.DetectSecrets = true
+	}
+}
+
+// WithValidators adds custom validators
+func WithValidators(validators ...Validator) ParserOption {
+	return func(p *Parser) {
+		p.CustomValidators = append(p.CustomValidators, validators...)
+	}
+}
+
+// NewParser creates a Parser with the given options
+func NewParser(opts ...ParserOption) *Parser {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// ParseBytes parses pipeline YAML content with the given options. It is the
+// canonical entry point of the package.
+func ParseBytes(data []byte, opts ...ParserOption) (*c8sv1alpha1.PipelineConfigSpec, error) {
+	return NewParser(opts...).Parse(data)
 }
 
 // Parse parses pipeline YAML content into a PipelineConfig spec
+//
+// Deprecated: use ParseBytes or Parser.Parse, which accept options.
 func Parse(yamlContent []byte) (*c8sv1alpha1.PipelineConfigSpec, error) {
+	return NewParser().Parse(yamlContent)
+}
+
+// Parse parses pipeline YAML content into a PipelineConfig spec
+func (p *Parser) Parse(data []byte) (*c8sv1alpha1.PipelineConfigSpec, error) {
 	var pipeline PipelineYAML
 
-	if err := yaml.Unmarshal(yamlContent, &pipeline); err != nil {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(p.StrictMode)
+	if err := decoder.Decode(&pipeline); err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
@@ -91,36 +259,91 @@ func Parse(yamlContent []byte) (*c8sv1alpha1.PipelineConfigSpec, error) {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	if p.DetectSecrets {
+		if err := detectHardcodedSecrets(pipeline.Steps); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	// Set defaults
+	if pipeline.DefaultStepTimeout == "" {
+		pipeline.DefaultStepTimeout = types.DefaultStepTimeout
+	}
+
 	// Convert to CRD types
 	spec := &c8sv1alpha1.PipelineConfigSpec{
-		Steps:       convertSteps(pipeline.Steps),
-		Timeout:     pipeline.Timeout,
-		Matrix:      convertMatrix(pipeline.Matrix),
-		RetryPolicy: convertRetryPolicy(pipeline.Retry),
+		Steps:              convertSteps(pipeline.Steps, pipeline.DefaultStepTimeout),
+		Timeout:            pipeline.Timeout,
+		DefaultStepTimeout: pipeline.DefaultStepTimeout,
+		Matrix:             convertMatrix(pipeline.Matrix),
+		RetryPolicy:        convertRetryPolicy(pipeline.Retry),
+		GlobalMaxLogSizeMB: pipeline.GlobalMaxLogSizeMB,
+		MaxParallel:        pipeline.MaxParallel,
+		DeduplicateSteps:   pipeline.DeduplicateSteps,
 	}
 
-	// Set defaults
 	if spec.Timeout == "" {
 		spec.Timeout = "1h"
 	}
 
+	for _, validator := range p.CustomValidators {
+		if err := validator(spec); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
 	return spec, nil
 }
 
-// convertSteps converts YAML steps to CRD steps
-func convertSteps(yamlSteps []PipelineStepYAML) []c8sv1alpha1.PipelineStep {
+// detectHardcodedSecrets reports step command words, or the values of
+// KEY=value assignments, that look like secrets. The value itself is not
+// included in the error.
+func detectHardcodedSecrets(steps []PipelineStepYAML) error {
+	errs := &ValidationErrors{}
+	for i, step := range steps {
+		for j, command := range step.Commands {
+			for _, word := range strings.Fields(command) {
+				if _, value, ok := strings.Cut(word, "="); ok {
+					word = value
+				}
+				if secrets.IsLikelySecretValue(strings.Trim(word, `"'`)) {
+					errs.Add(fmt.Sprintf("steps[%d].commands[%d]", i, j),
+						"contains what looks like a hardcoded secret; use secrets or vaultSecrets instead")
+					break
+				}
+			}
+		}
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// convertSteps converts YAML steps to CRD steps. Steps without a timeout
+// inherit defaultTimeout.
+func convertSteps(yamlSteps []PipelineStepYAML, defaultTimeout string) []c8sv1alpha1.PipelineStep {
 	steps := make([]c8sv1alpha1.PipelineStep, len(yamlSteps))
 	for i, ys := range yamlSteps {
+		if ys.Timeout == "" {
+			ys.Timeout = defaultTimeout
+		}
 		steps[i] = c8sv1alpha1.PipelineStep{
-			Name:        ys.Name,
-			Image:       ys.Image,
-			Commands:    ys.Commands,
-			DependsOn:   ys.DependsOn,
-			Resources:   convertResources(ys.Resources),
-			Timeout:     ys.Timeout,
-			Artifacts:   ys.Artifacts,
-			Secrets:     convertSecrets(ys.Secrets),
-			Conditional: convertConditional(ys.Conditional),
+			Name:             ys.Name,
+			Image:            ys.Image,
+			Commands:         ys.Commands,
+			WorkingDir:       ys.WorkingDir,
+			DependsOn:        ys.DependsOn,
+			Resources:        convertResources(ys.Resources),
+			Timeout:          ys.Timeout,
+			Artifacts:        ys.Artifacts,
+			Secrets:          convertSecrets(ys.Secrets),
+			VaultSecrets:     convertVaultSecrets(ys.VaultSecrets),
+			Conditional:      convertConditional(ys.Conditional),
+			Retry:            convertRetryPolicy(ys.Retry),
+			MaxLogSizeMB:     ys.MaxLogSizeMB,
+			Weight:           ys.Weight,
+			MatrixDimensions: ys.MatrixDimensions,
 		}
 	}
 	return steps
@@ -153,6 +376,22 @@ func convertSecrets(yaml []SecretReferenceYAML) []c8sv1alpha1.SecretReference {
 	return secrets
 }
 
+// convertVaultSecrets converts YAML Vault secrets to CRD Vault secrets
+func convertVaultSecrets(yaml []VaultSecretRefYAML) []c8sv1alpha1.VaultSecretRef {
+	if yaml == nil {
+		return nil
+	}
+	refs := make([]c8sv1alpha1.VaultSecretRef, len(yaml))
+	for i, yr := range yaml {
+		refs[i] = c8sv1alpha1.VaultSecretRef{
+			Path:   yr.Path,
+			Key:    yr.Key,
+			EnvVar: yr.EnvVar,
+		}
+	}
+	return refs
+}
+
 // convertConditional converts YAML conditional to CRD conditional
 func convertConditional(yaml *ConditionalYAML) *c8sv1alpha1.ConditionalExecution {
 	if yaml == nil {
@@ -160,8 +399,9 @@ func convertConditional(yaml *ConditionalYAML) *c8sv1alpha1.ConditionalExecution
 	}
 	onSuccess := yaml.OnSuccess
 	return &c8sv1alpha1.ConditionalExecution{
-		Branch:    yaml.Branch,
-		OnSuccess: &onSuccess,
+		Branch:      yaml.Branch,
+		OnSuccess:   &onSuccess,
+		Environment: yaml.Environment,
 	}
 }
 
@@ -182,8 +422,9 @@ func convertRetryPolicy(yaml *RetryPolicyYAML) *c8sv1alpha1.RetryPolicy {
 		return nil
 	}
 	return &c8sv1alpha1.RetryPolicy{
-		MaxRetries:     yaml.MaxRetries,
-		BackoffSeconds: yaml.BackoffSeconds,
+		MaxRetries:      yaml.MaxRetries,
+		BackoffSeconds:  yaml.BackoffSeconds,
+		OnlyOnExitCodes: yaml.OnlyOnExitCodes,
 	}
 }
 
@@ -247,6 +488,16 @@ func validate(pipeline *PipelineYAML) error {
 			}
 		}
 	}
+	for _, step := range pipeline.Steps {
+		for _, dim := range step.MatrixDimensions {
+			if pipeline.Matrix == nil {
+				return fmt.Errorf("step %s: matrixDimensions requires a matrix", step.Name)
+			}
+			if _, exists := pipeline.Matrix.Dimensions[dim]; !exists {
+				return fmt.Errorf("step %s: matrix dimension %s not found", step.Name, dim)
+			}
+		}
+	}
 
 	return nil
 }
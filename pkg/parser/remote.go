@@ -0,0 +1,215 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+const (
+	// RemoteFetchTimeout bounds the download of a remote pipeline file
+	RemoteFetchTimeout = 5 * time.Second
+
+	// RemoteCacheTTL is how long a downloaded pipeline file is reused
+	RemoteCacheTTL = 5 * time.Minute
+
+	// MaxRemoteFileSize is the largest remote pipeline file accepted
+	MaxRemoteFileSize = 1 << 20
+
+	// maxRemoteRedirects is how many redirects a download follows
+	maxRemoteRedirects = 10
+)
+
+var (
+	// AllowedHosts are the hosts ParseFromURL downloads pipeline files from.
+	// For s3:// URLs the host is the bucket name. An entry "*.example.com"
+	// allows every subdomain of example.com. No host is allowed by default.
+	AllowedHosts []string
+
+	// HTTPClient downloads https:// pipeline files. Its CheckRedirect is
+	// replaced so redirects are only followed to https:// URLs of allowed
+	// hosts.
+	HTTPClient = &http.Client{}
+
+	// S3Client downloads s3:// pipeline files. When nil, a client is created
+	// from the default AWS configuration (environment, shared config) on
+	// first use.
+	S3Client s3iface.S3API
+
+	// remoteCache maps URLs to their cachedRemoteFile
+	remoteCache sync.Map
+)
+
+// cachedRemoteFile is the content of a remote pipeline file and when it
+// stops being reused
+type cachedRemoteFile struct {
+	data    []byte
+	expires time.Time
+}
+
+// ParseFromURL parses a pipeline file downloaded from an https:// or s3://
+// URL whose host is in AllowedHosts. Downloads are cached for RemoteCacheTTL.
+func ParseFromURL(rawURL string) (*c8sv1alpha1.PipelineConfigSpec, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline URL: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "s3" {
+		return nil, fmt.Errorf("unsupported pipeline URL scheme %q (supported: https, s3)", u.Scheme)
+	}
+	if !IsAllowedHost(u.Hostname()) {
+		return nil, fmt.Errorf("%w: %s is not in the allowed hosts", types.ErrUntrustedHost, u.Hostname())
+	}
+
+	if cached, ok := remoteCache.Load(rawURL); ok {
+		if file := cached.(cachedRemoteFile); time.Now().Before(file.expires) {
+			return ParseBytes(file.data)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RemoteFetchTimeout)
+	defer cancel()
+
+	var data []byte
+	if u.Scheme == "s3" {
+		data, err = fetchS3(ctx, u)
+	} else {
+		data, err = fetchHTTPS(ctx, u)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+
+	remoteCache.Store(rawURL, cachedRemoteFile{data: data, expires: time.Now().Add(RemoteCacheTTL)})
+	return ParseBytes(data)
+}
+
+// IsAllowedHost reports whether host matches an entry of AllowedHosts
+func IsAllowedHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ClearRemoteCache forgets all downloaded pipeline files
+func ClearRemoteCache() {
+	remoteCache.Range(func(key, _ interface{}) bool {
+		remoteCache.Delete(key)
+		return true
+	})
+}
+
+// fetchHTTPS downloads the pipeline file at an https:// URL
+func fetchHTTPS(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := *HTTPClient
+	client.CheckRedirect = checkRedirect
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return readRemoteFile(resp.Body)
+}
+
+// checkRedirect rejects redirects from a pipeline file download to anything
+// but an https:// URL whose host is in AllowedHosts
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRemoteRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRemoteRedirects)
+	}
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("unsupported redirect URL scheme %q (supported: https)", req.URL.Scheme)
+	}
+	if !IsAllowedHost(req.URL.Hostname()) {
+		return fmt.Errorf("%w: redirect to %s, which is not in the allowed hosts", types.ErrUntrustedHost, req.URL.Hostname())
+	}
+	return nil
+}
+
+// fetchS3 downloads the pipeline file at an s3://bucket/key URL
+func fetchS3(ctx context.Context, u *url.URL) ([]byte, error) {
+	key := strings.TrimPrefix(u.Path, "/")
+	if key == "" {
+		return nil, fmt.Errorf("missing object key")
+	}
+
+	client := S3Client
+	if client == nil {
+		sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AWS session: %w", err)
+		}
+		client = s3.New(sess)
+	}
+
+	output, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(u.Host),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+	return readRemoteFile(output.Body)
+}
+
+// readRemoteFile reads a downloaded pipeline file, rejecting files larger
+// than MaxRemoteFileSize
+func readRemoteFile(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxRemoteFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxRemoteFileSize {
+		return nil, fmt.Errorf("pipeline file exceeds %d bytes", MaxRemoteFileSize)
+	}
+	return data, nil
+}
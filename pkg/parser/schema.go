@@ -0,0 +1,241 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDraft07 is the meta-schema URI of the generated schema
+const JSONSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// parserSource is the source of the YAML types, read for their doc comments
+//
+//go:embed parser.go
+var parserSource string
+
+// JSONSchema is a JSON Schema (Draft-07) document or subschema
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Maximum              *int                   `json:"maximum,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+}
+
+// PipelineSchema builds the JSON Schema of the .c8s.yaml format by reflecting
+// over PipelineYAML. Property names and required fields come from the yaml
+// struct tags, descriptions from the doc comments, and enum, pattern, minItems
+// and minimum constraints from the jsonschema struct tags.
+func PipelineSchema() (*JSONSchema, error) {
+	descriptions, err := typeDescriptions(parserSource)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := reflectSchema(reflect.TypeOf(PipelineYAML{}), descriptions)
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = JSONSchemaDraft07
+	schema.Title = "C8S Pipeline"
+	return schema, nil
+}
+
+// GenerateJSONSchema returns the indented JSON encoding of PipelineSchema
+func GenerateJSONSchema() ([]byte, error) {
+	schema, err := PipelineSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// reflectSchema builds the schema of a Go type
+func reflectSchema(t reflect.Type, descriptions map[string]string) (*JSONSchema, error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return reflectSchema(t.Elem(), descriptions)
+	case reflect.Struct:
+		return reflectStructSchema(t, descriptions)
+	case reflect.Slice:
+		items, err := reflectSchema(t.Elem(), descriptions)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := reflectSchema(t.Elem(), descriptions)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.String:
+		return &JSONSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return &JSONSchema{Type: "integer"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// reflectStructSchema builds the object schema of a struct from its yaml tags
+func reflectStructSchema(t reflect.Type, descriptions map[string]string) (*JSONSchema, error) {
+	schema := &JSONSchema{
+		Type:                 "object",
+		Description:          descriptions[t.Name()],
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		property, err := reflectSchema(field.Type, descriptions)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		property.Description = descriptions[t.Name()+"."+field.Name]
+		if err := applySchemaTag(property, field.Tag.Get("jsonschema")); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+
+		schema.Properties[name] = property
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema, nil
+}
+
+// applySchemaTag applies the constraints of a jsonschema struct tag, written as
+// semicolon-separated key=value pairs with enum values separated by "|"
+func applySchemaTag(schema *JSONSchema, tag string) error {
+	if tag == "" {
+		return nil
+	}
+
+	for _, entry := range strings.Split(tag, ";") {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid jsonschema tag entry %q", entry)
+		}
+
+		switch key {
+		case "enum":
+			schema.Enum = strings.Split(value, "|")
+		case "pattern":
+			schema.Pattern = value
+		case "minItems", "minimum", "maximum":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid jsonschema %s %q: %w", key, value, err)
+			}
+			switch key {
+			case "minItems":
+				schema.MinItems = &n
+			case "minimum":
+				schema.Minimum = &n
+			default:
+				schema.Maximum = &n
+			}
+		default:
+			return fmt.Errorf("unknown jsonschema tag key %q", key)
+		}
+	}
+	return nil
+}
+
+// typeDescriptions parses Go source and returns the doc comments of its struct
+// types keyed by "Type" and their fields keyed by "Type.Field"
+func typeDescriptions(source string) (map[string]string, error) {
+	file, err := goparser.ParseFile(token.NewFileSet(), "parser.go", source, goparser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse type source: %w", err)
+	}
+
+	descriptions := make(map[string]string)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil {
+				doc = genDecl.Doc
+			}
+			descriptions[typeSpec.Name.Name] = commentText(doc)
+
+			for _, field := range structType.Fields.List {
+				for _, name := range field.Names {
+					descriptions[typeSpec.Name.Name+"."+name.Name] = commentText(field.Doc)
+				}
+			}
+		}
+	}
+	return descriptions, nil
+}
+
+// commentText joins the lines of a comment group into a single sentence
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
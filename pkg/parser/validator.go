@@ -19,18 +19,32 @@ package parser
 import (
 	"fmt"
 	"net/url"
+	"path"
 	"regexp"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/scheduler"
+	"github.com/org/c8s/pkg/types"
 )
 
 var (
 	// Valid step name pattern: alphanumeric, dashes, underscores
 	stepNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	// Volume names used by the Job's own volumes
+	reservedVolumeNames = map[string]bool{
+		types.VolumeNameWorkspace: true,
+		types.VolumeNameSecrets:   true,
+	}
+
+	// Paths commonly written to by build tools, which need a writable
+	// volume when the root filesystem is read-only
+	writablePaths = []string{"/tmp"}
 )
 
 // ValidationError represents a structured validation error
@@ -46,6 +60,16 @@ func (e *ValidationError) Error() string {
 	return e.Message
 }
 
+// Is reports whether target is a *ValidationError for the same field.
+// A target without a field matches any ValidationError.
+func (e *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+	if !ok {
+		return false
+	}
+	return t.Field == "" || t.Field == e.Field
+}
+
 // Validate performs comprehensive validation on a PipelineConfig
 func Validate(config *c8sv1alpha1.PipelineConfig) error {
 	errors := &ValidationErrors{}
@@ -91,6 +115,27 @@ func Validate(config *c8sv1alpha1.PipelineConfig) error {
 				fmt.Sprintf("invalid duration format: %v", err))
 		}
 	}
+	if config.Spec.DefaultStepTimeout != "" {
+		if _, err := time.ParseDuration(config.Spec.DefaultStepTimeout); err != nil {
+			errors.Add("spec.defaultStepTimeout",
+				fmt.Sprintf("invalid duration format: %v", err))
+		}
+	}
+
+	if !c8sv1alpha1.IsValidEnvironment(config.Spec.Environment) {
+		errors.Add("spec.environment",
+			fmt.Sprintf("invalid environment %q: must be one of %s", config.Spec.Environment, strings.Join(c8sv1alpha1.ValidEnvironments, ", ")))
+	}
+
+	if size := config.Spec.GlobalMaxLogSizeMB; size < 0 || size > c8sv1alpha1.MaxLogSizeMBLimit {
+		errors.Add("spec.globalMaxLogSizeMB",
+			fmt.Sprintf("invalid log size %dMB: must be between 0 and %d", size, c8sv1alpha1.MaxLogSizeMBLimit))
+	}
+
+	if config.Spec.MaxParallel < 0 {
+		errors.Add("spec.maxParallel",
+			fmt.Sprintf("invalid maxParallel %d: must not be negative", config.Spec.MaxParallel))
+	}
 
 	// Validate matrix strategy if present
 	if config.Spec.Matrix != nil {
@@ -99,13 +144,24 @@ func Validate(config *c8sv1alpha1.PipelineConfig) error {
 		}
 	}
 
+	// Validate the matrix dimensions steps vary over
+	errors.Merge(validateStepMatrix(&config.Spec))
+
 	// Validate retry policy if present
 	if config.Spec.RetryPolicy != nil {
-		if err := validateRetryPolicy(config.Spec.RetryPolicy); err != nil {
+		if err := validateRetryPolicy(config.Spec.RetryPolicy, "spec.retryPolicy"); err != nil {
 			errors.Merge(err)
 		}
 	}
 
+	// Validate resource quota if present
+	if config.Spec.ResourceQuota != nil {
+		errors.Merge(validateResourceQuota(config.Spec.ResourceQuota))
+	}
+
+	// Validate volumes and the step mounts referencing them
+	errors.Merge(validateVolumes(&config.Spec))
+
 	if errors.HasErrors() {
 		return errors
 	}
@@ -113,6 +169,87 @@ func Validate(config *c8sv1alpha1.PipelineConfig) error {
 	return nil
 }
 
+// Warnings returns issues of a PipelineConfig that don't make it invalid but
+// are likely mistakes, such as a default step timeout that the pipeline
+// timeout can't accommodate
+func Warnings(config *c8sv1alpha1.PipelineConfig) []*ValidationError {
+	var warnings []*ValidationError
+	if warning := timeoutWarning(config); warning != nil {
+		warnings = append(warnings, warning)
+	}
+	return append(warnings, readOnlyRootFilesystemWarnings(&config.Spec)...)
+}
+
+// timeoutWarning warns when the steps can't all run for the default step
+// timeout within the pipeline timeout
+func timeoutWarning(config *c8sv1alpha1.PipelineConfig) *ValidationError {
+	pipelineTimeout, err := time.ParseDuration(config.Spec.Timeout)
+	if err != nil {
+		return nil
+	}
+	stepTimeout, err := time.ParseDuration(config.Spec.DefaultStepTimeout)
+	if err != nil {
+		return nil
+	}
+
+	if total := stepTimeout * time.Duration(len(config.Spec.Steps)); total > pipelineTimeout {
+		return &ValidationError{
+			Field: "spec.defaultStepTimeout",
+			Message: fmt.Sprintf("%d steps x %s (%s) exceeds the pipeline timeout %s",
+				len(config.Spec.Steps), config.Spec.DefaultStepTimeout, total, config.Spec.Timeout),
+		}
+	}
+	return nil
+}
+
+// readOnlyRootFilesystemWarnings warns about steps with a read-only root
+// filesystem that don't mount a writable emptyDir volume at the paths most
+// tools write to
+func readOnlyRootFilesystemWarnings(spec *c8sv1alpha1.PipelineConfigSpec) []*ValidationError {
+	emptyDirs := make(map[string]bool)
+	for _, volume := range spec.Volumes {
+		if volume.EmptyDir != nil {
+			emptyDirs[volume.Name] = true
+		}
+	}
+
+	var warnings []*ValidationError
+	for i := range spec.Steps {
+		step := &spec.Steps[i]
+		securityContext := step.SecurityContext
+		field := fmt.Sprintf("spec.steps[%d].securityContext.readOnlyRootFilesystem", i)
+		if securityContext == nil {
+			securityContext = spec.DefaultSecurityContext
+			field = "spec.defaultSecurityContext.readOnlyRootFilesystem"
+		}
+		if securityContext == nil || !securityContext.ReadOnlyRootFilesystem {
+			continue
+		}
+
+		writable := make(map[string]bool)
+		for _, mount := range step.VolumeMounts {
+			if emptyDirs[mount.Name] && !mount.ReadOnly {
+				writable[strings.TrimSuffix(mount.MountPath, "/")] = true
+			}
+		}
+
+		var missing []string
+		for _, path := range writablePaths {
+			if !writable[path] {
+				missing = append(missing, path)
+			}
+		}
+		if len(missing) > 0 {
+			warnings = append(warnings, &ValidationError{
+				Field: field,
+				Message: fmt.Sprintf("step %s has a read-only root filesystem but no writable emptyDir volume mounted at %s",
+					step.Name, strings.Join(missing, ", ")),
+			})
+		}
+	}
+	return warnings
+}
+
 // validateRepositoryURL validates the repository URL format
 func validateRepositoryURL(repoURL string) error {
 	if repoURL == "" {
@@ -170,6 +307,21 @@ func validateStep(step *c8sv1alpha1.PipelineStep, existingSteps map[string]bool,
 		}
 	}
 
+	if step.WorkingDir != "" && escapesWorkspace(step.WorkingDir) {
+		errors.Add(fmt.Sprintf("%s.workingDir", prefix),
+			fmt.Sprintf("invalid working directory %q: must not leave %s through '..'", step.WorkingDir, types.MountPathWorkspace))
+	}
+
+	if step.MaxLogSizeMB < 0 || step.MaxLogSizeMB > c8sv1alpha1.MaxLogSizeMBLimit {
+		errors.Add(fmt.Sprintf("%s.maxLogSizeMB", prefix),
+			fmt.Sprintf("invalid log size %dMB: must be between 0 and %d", step.MaxLogSizeMB, c8sv1alpha1.MaxLogSizeMBLimit))
+	}
+
+	if step.Weight < 0 {
+		errors.Add(fmt.Sprintf("%s.weight", prefix),
+			fmt.Sprintf("invalid weight %d: must not be negative", step.Weight))
+	}
+
 	// Validate resource values are valid Kubernetes quantities
 	if step.Resources != nil {
 		if step.Resources.CPU != "" {
@@ -186,6 +338,16 @@ func validateStep(step *c8sv1alpha1.PipelineStep, existingSteps map[string]bool,
 		}
 	}
 
+	// Validate Vault secret references
+	for j, ref := range step.VaultSecrets {
+		if ref.Path == "" {
+			errors.Add(fmt.Sprintf("%s.vaultSecrets[%d].path", prefix, j), "path is required")
+		}
+		if ref.Key == "" {
+			errors.Add(fmt.Sprintf("%s.vaultSecrets[%d].key", prefix, j), "key is required")
+		}
+	}
+
 	// Validate conditional execution branch pattern if present
 	if step.Conditional != nil && step.Conditional.Branch != "" {
 		if _, err := regexp.Compile(step.Conditional.Branch); err != nil {
@@ -194,9 +356,45 @@ func validateStep(step *c8sv1alpha1.PipelineStep, existingSteps map[string]bool,
 		}
 	}
 
+	// Validate conditional execution environments if present
+	if step.Conditional != nil {
+		for j, environment := range step.Conditional.Environment {
+			if environment == "" || !c8sv1alpha1.IsValidEnvironment(environment) {
+				errors.Add(fmt.Sprintf("%s.conditional.environment[%d]", prefix, j),
+					fmt.Sprintf("invalid environment %q: must be one of %s", environment, strings.Join(c8sv1alpha1.ValidEnvironments, ", ")))
+			}
+		}
+	}
+
+	// Validate step retry override if present
+	if step.Retry != nil {
+		errors.Merge(validateRetryPolicy(step.Retry, fmt.Sprintf("%s.retry", prefix)))
+	}
+
 	return errors
 }
 
+// escapesWorkspace reports whether a step working directory uses '..' to
+// reach a directory outside the workspace. Absolute paths without '..' may
+// point anywhere.
+func escapesWorkspace(workingDir string) bool {
+	hasParent := false
+	for _, element := range strings.Split(workingDir, "/") {
+		if element == ".." {
+			hasParent = true
+		}
+	}
+	if !hasParent {
+		return false
+	}
+
+	resolved := path.Clean(workingDir)
+	if !path.IsAbs(workingDir) {
+		resolved = path.Join(types.MountPathWorkspace, workingDir)
+	}
+	return resolved != types.MountPathWorkspace && !strings.HasPrefix(resolved, types.MountPathWorkspace+"/")
+}
+
 // validateNoCycles checks for circular dependencies using DFS
 func validateNoCycles(steps []c8sv1alpha1.PipelineStep) error {
 	// Build adjacency list
@@ -279,11 +477,41 @@ func validateMatrix(matrix *c8sv1alpha1.MatrixStrategy) *ValidationErrors {
 
 	// Validate exclusion patterns reference valid dimensions
 	for i, exclusion := range matrix.Exclude {
-		for key := range exclusion {
+		for key, expression := range exclusion {
 			if _, exists := matrix.Dimensions[key]; !exists {
 				errors.Add(fmt.Sprintf("spec.matrix.exclude[%d]", i),
 					fmt.Sprintf("exclusion references undefined dimension: %s", key))
 			}
+			if _, err := scheduler.CompileMatrixExclusion(expression); err != nil {
+				errors.Add(fmt.Sprintf("spec.matrix.exclude[%d].%s", i, key), err.Error())
+			}
+		}
+	}
+
+	return errors
+}
+
+// validateStepMatrix validates that the matrixDimensions of steps are
+// dimensions of spec.matrix
+func validateStepMatrix(spec *c8sv1alpha1.PipelineConfigSpec) *ValidationErrors {
+	errors := &ValidationErrors{}
+
+	for i, step := range spec.Steps {
+		field := fmt.Sprintf("spec.steps[%d].matrixDimensions", i)
+		seen := make(map[string]bool, len(step.MatrixDimensions))
+		for _, dimension := range step.MatrixDimensions {
+			switch {
+			case spec.Matrix == nil:
+				errors.Add(field, "matrix dimensions require spec.matrix")
+				return errors
+			case seen[dimension]:
+				errors.Add(field, fmt.Sprintf("duplicate dimension: %s", dimension))
+			default:
+				if _, exists := spec.Matrix.Dimensions[dimension]; !exists {
+					errors.Add(field, fmt.Sprintf("undefined dimension: %s", dimension))
+				}
+			}
+			seen[dimension] = true
 		}
 	}
 
@@ -291,19 +519,140 @@ func validateMatrix(matrix *c8sv1alpha1.MatrixStrategy) *ValidationErrors {
 }
 
 // validateRetryPolicy validates retry policy configuration
-func validateRetryPolicy(policy *c8sv1alpha1.RetryPolicy) *ValidationErrors {
+func validateRetryPolicy(policy *c8sv1alpha1.RetryPolicy, prefix string) *ValidationErrors {
 	errors := &ValidationErrors{}
 
 	if policy.MaxRetries < 0 {
-		errors.Add("spec.retryPolicy.maxRetries", "must be non-negative")
+		errors.Add(prefix+".maxRetries", "must be non-negative")
 	}
 
 	if policy.MaxRetries > 10 {
-		errors.Add("spec.retryPolicy.maxRetries", "maximum allowed retries is 10")
+		errors.Add(prefix+".maxRetries", "maximum allowed retries is 10")
 	}
 
 	if policy.BackoffSeconds < 0 {
-		errors.Add("spec.retryPolicy.backoffSeconds", "must be non-negative")
+		errors.Add(prefix+".backoffSeconds", "must be non-negative")
+	}
+
+	// Exit code 0 never fails a step, so it can never trigger a retry
+	for i, code := range policy.OnlyOnExitCodes {
+		if code < 1 || code > 255 {
+			errors.Add(fmt.Sprintf("%s.onlyOnExitCodes[%d]", prefix, i), "must be between 1 and 255")
+		}
+	}
+
+	return errors
+}
+
+// validateResourceQuota validates namespace resource quota configuration
+func validateResourceQuota(quota *c8sv1alpha1.ResourceQuotaSpec) *ValidationErrors {
+	errors := &ValidationErrors{}
+
+	if quota.MaxConcurrentRuns < 0 {
+		errors.Add("spec.resourceQuota.maxConcurrentRuns", "must be non-negative")
+	}
+
+	if quota.MaxCPUTotal != "" {
+		if _, err := resource.ParseQuantity(quota.MaxCPUTotal); err != nil {
+			errors.Add("spec.resourceQuota.maxCPUTotal",
+				fmt.Sprintf("invalid CPU quantity: %v", err))
+		}
+	}
+
+	if quota.MaxMemoryTotal != "" {
+		if _, err := resource.ParseQuantity(quota.MaxMemoryTotal); err != nil {
+			errors.Add("spec.resourceQuota.maxMemoryTotal",
+				fmt.Sprintf("invalid memory quantity: %v", err))
+		}
+	}
+
+	return errors
+}
+
+// validateVolumes validates volume declarations and checks that step volume
+// mounts reference declared volumes
+func validateVolumes(spec *c8sv1alpha1.PipelineConfigSpec) *ValidationErrors {
+	errors := &ValidationErrors{}
+
+	declared := make(map[string]*c8sv1alpha1.VolumeSpec)
+	for i := range spec.Volumes {
+		volume := &spec.Volumes[i]
+		prefix := fmt.Sprintf("spec.volumes[%d]", i)
+
+		if volume.Name == "" {
+			errors.Add(fmt.Sprintf("%s.name", prefix), "name is required")
+		} else if reservedVolumeNames[volume.Name] {
+			errors.Add(fmt.Sprintf("%s.name", prefix),
+				fmt.Sprintf("volume name %s is reserved", volume.Name))
+		} else if declared[volume.Name] != nil {
+			errors.Add(fmt.Sprintf("%s.name", prefix),
+				fmt.Sprintf("duplicate volume name: %s", volume.Name))
+		}
+		declared[volume.Name] = volume
+
+		sources := 0
+		if volume.PersistentVolumeClaim != nil {
+			sources++
+			if volume.PersistentVolumeClaim.ClaimName == "" {
+				errors.Add(fmt.Sprintf("%s.persistentVolumeClaim.claimName", prefix), "claimName is required")
+			}
+		}
+		if volume.ConfigMap != nil {
+			sources++
+			if volume.ConfigMap.Name == "" {
+				errors.Add(fmt.Sprintf("%s.configMap.name", prefix), "name is required")
+			}
+		}
+		if volume.EmptyDir != nil {
+			sources++
+			if volume.EmptyDir.SizeLimit != "" {
+				if _, err := resource.ParseQuantity(volume.EmptyDir.SizeLimit); err != nil {
+					errors.Add(fmt.Sprintf("%s.emptyDir.sizeLimit", prefix),
+						fmt.Sprintf("invalid sizeLimit quantity: %v", err))
+				}
+			}
+		}
+		switch {
+		case sources > 1:
+			errors.Add(prefix, "only one of persistentVolumeClaim, configMap or emptyDir may be set")
+		case sources == 0:
+			errors.Add(prefix, "one of persistentVolumeClaim, configMap or emptyDir is required")
+		}
+	}
+
+	// Count the steps mounting each volume
+	mountedBy := make(map[string]int)
+	for i, step := range spec.Steps {
+		mounted := make(map[string]bool)
+		for j, mount := range step.VolumeMounts {
+			prefix := fmt.Sprintf("spec.steps[%d].volumeMounts[%d]", i, j)
+
+			if declared[mount.Name] == nil {
+				errors.Add(fmt.Sprintf("%s.name", prefix),
+					fmt.Sprintf("references undeclared volume: %s", mount.Name))
+			}
+
+			if !strings.HasPrefix(mount.MountPath, "/") {
+				errors.Add(fmt.Sprintf("%s.mountPath", prefix), "must be an absolute path")
+			}
+
+			if !mounted[mount.Name] {
+				mounted[mount.Name] = true
+				mountedBy[mount.Name]++
+			}
+		}
+	}
+
+	// Steps may run concurrently on different nodes, so a shared claim must be read-only
+	for i := range spec.Volumes {
+		volume := &spec.Volumes[i]
+		if volume.PersistentVolumeClaim == nil || mountedBy[volume.Name] < 2 {
+			continue
+		}
+		if volume.PersistentVolumeClaim.AccessMode != string(corev1.ReadOnlyMany) {
+			errors.Add(fmt.Sprintf("spec.volumes[%d].persistentVolumeClaim.accessMode", i),
+				fmt.Sprintf("must be ReadOnlyMany when mounted by multiple steps (mounted by %d)", mountedBy[volume.Name]))
+		}
 	}
 
 	return errors
@@ -343,3 +692,36 @@ func (ve *ValidationErrors) Error() string {
 
 	return fmt.Sprintf("validation failed:\n  - %s", strings.Join(messages, "\n  - "))
 }
+
+// Is reports whether target is a *ValidationErrors, or a *ValidationError
+// matching any of the collected errors (see ValidationError.Is)
+func (ve *ValidationErrors) Is(target error) bool {
+	switch t := target.(type) {
+	case *ValidationErrors:
+		return true
+	case *ValidationError:
+		for _, err := range ve.Errors {
+			if err.Is(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// As sets target to the whole *ValidationErrors, or to the first collected
+// *ValidationError when target is a **ValidationError
+func (ve *ValidationErrors) As(target interface{}) bool {
+	switch t := target.(type) {
+	case **ValidationErrors:
+		*t = ve
+		return true
+	case **ValidationError:
+		if len(ve.Errors) == 0 {
+			return false
+		}
+		*t = ve.Errors[0]
+		return true
+	}
+	return false
+}
@@ -19,6 +19,8 @@ package scheduler
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
 	"github.com/org/c8s/pkg/types"
@@ -34,14 +36,21 @@ type DAG struct {
 
 	// reverseEdges maps step names to steps that depend on them (outgoing edges)
 	reverseEdges map[string][]string
+
+	// external maps step names to their dependencies on steps of included
+	// configs ("{config-name}/{step-name}") that are not in the graph yet
+	external map[string][]string
 }
 
-// BuildDAG constructs a DAG from pipeline steps
+// BuildDAG constructs a DAG from pipeline steps. Dependencies on steps of
+// included configs ("{config-name}/{step-name}") that are not among the steps
+// are kept as unresolved until the included schedule is merged.
 func BuildDAG(steps []c8sv1alpha1.PipelineStep) (*DAG, error) {
 	dag := &DAG{
 		nodes:        make(map[string]*c8sv1alpha1.PipelineStep),
 		edges:        make(map[string][]string),
 		reverseEdges: make(map[string][]string),
+		external:     make(map[string][]string),
 	}
 
 	// First pass: register all nodes
@@ -60,6 +69,10 @@ func BuildDAG(steps []c8sv1alpha1.PipelineStep) (*DAG, error) {
 		for _, dep := range step.DependsOn {
 			// Verify dependency exists
 			if _, exists := dag.nodes[dep]; !exists {
+				if strings.Contains(dep, "/") {
+					dag.external[step.Name] = append(dag.external[step.Name], dep)
+					continue
+				}
 				return nil, fmt.Errorf("%w: step %s depends on non-existent step %s",
 					types.ErrStepNotFound, step.Name, dep)
 			}
@@ -179,6 +192,23 @@ func (d *DAG) GetDependents(name string) []string {
 	return d.reverseEdges[name]
 }
 
+// UnresolvedDependencies returns the sorted dependencies on steps of
+// included configs that are not in the graph
+func (d *DAG) UnresolvedDependencies() []string {
+	seen := make(map[string]bool)
+	var deps []string
+	for _, stepDeps := range d.external {
+		for _, dep := range stepDeps {
+			if !seen[dep] {
+				seen[dep] = true
+				deps = append(deps, dep)
+			}
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
 // Size returns the number of steps in the DAG
 func (d *DAG) Size() int {
 	return len(d.nodes)
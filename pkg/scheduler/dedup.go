@@ -0,0 +1,158 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// DeduplicateSteps merges steps with identical configurations into the first
+// of them, which then runs once for all. Only steps without external
+// dependencies (secrets, Vault secrets, artifacts) or matrix dimensions are
+// merged, and never a step with one it depends on, directly or not. The
+// merged step waits for the dependencies of all the steps merged into it,
+// and dependents of a merged step depend on the step it was merged into.
+func DeduplicateSteps(steps []c8sv1alpha1.PipelineStep) []c8sv1alpha1.PipelineStep {
+	deduplicated, _ := deduplicateSteps(steps)
+	return deduplicated
+}
+
+// StepAliases returns the steps DeduplicateSteps merges into another, mapped
+// to the name of the step they were merged into
+func StepAliases(steps []c8sv1alpha1.PipelineStep) map[string]string {
+	_, aliases := deduplicateSteps(steps)
+	return aliases
+}
+
+// deduplicateSteps returns the deduplicated steps and their aliases
+func deduplicateSteps(steps []c8sv1alpha1.PipelineStep) ([]c8sv1alpha1.PipelineStep, map[string]string) {
+	result := make([]c8sv1alpha1.PipelineStep, len(steps))
+	for i := range steps {
+		steps[i].DeepCopyInto(&result[i])
+	}
+	aliases := map[string]string{}
+
+	for i := 0; i < len(result); i++ {
+		for j := i + 1; j < len(result); {
+			if !canMergeSteps(result, &result[i], &result[j]) {
+				j++
+				continue
+			}
+
+			target, alias := result[i].Name, result[j].Name
+			result[i].DependsOn = appendMissing(result[i].DependsOn, result[j].DependsOn...)
+			result[i].Weight = max(result[i].Weight, result[j].Weight)
+			result = slices.Delete(result, j, j+1)
+			for k := range result {
+				result[k].DependsOn = resolveAliases(result[k].DependsOn, map[string]string{alias: target})
+			}
+			aliases[alias] = target
+		}
+	}
+
+	return result, aliases
+}
+
+// canMergeSteps reports whether two steps can run as one: both can be
+// deduplicated, their configurations are identical and neither depends on
+// the other
+func canMergeSteps(steps []c8sv1alpha1.PipelineStep, a, b *c8sv1alpha1.PipelineStep) bool {
+	if !isDeduplicable(a) || !isDeduplicable(b) || !identicalSteps(a, b) {
+		return false
+	}
+	return !dependsOn(steps, a.Name, b.Name) && !dependsOn(steps, b.Name, a.Name)
+}
+
+// isDeduplicable reports whether a step has no dependencies outside the
+// pipeline that would make two identical runs of it differ
+func isDeduplicable(step *c8sv1alpha1.PipelineStep) bool {
+	return len(step.Secrets) == 0 && len(step.VaultSecrets) == 0 &&
+		len(step.Artifacts) == 0 && len(step.MatrixDimensions) == 0
+}
+
+// identicalSteps compares two steps except for their name, dependencies and
+// weight
+func identicalSteps(a, b *c8sv1alpha1.PipelineStep) bool {
+	normalize := func(step *c8sv1alpha1.PipelineStep) *c8sv1alpha1.PipelineStep {
+		normalized := step.DeepCopy()
+		normalized.Name = ""
+		normalized.DependsOn = nil
+		normalized.Weight = 0
+		return normalized
+	}
+	return equality.Semantic.DeepEqual(normalize(a), normalize(b))
+}
+
+// dependsOn reports whether step from depends on step to, directly or
+// through other steps
+func dependsOn(steps []c8sv1alpha1.PipelineStep, from, to string) bool {
+	byName := make(map[string]*c8sv1alpha1.PipelineStep, len(steps))
+	for i := range steps {
+		byName[steps[i].Name] = &steps[i]
+	}
+
+	visited := map[string]bool{}
+	pending := []string{from}
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		step, ok := byName[name]
+		if !ok {
+			continue
+		}
+		for _, dep := range step.DependsOn {
+			if dep == to {
+				return true
+			}
+			if !visited[dep] {
+				visited[dep] = true
+				pending = append(pending, dep)
+			}
+		}
+	}
+	return false
+}
+
+// resolveAliases replaces the aliases among step names by the step they
+// were merged into, without duplicates
+func resolveAliases(names []string, aliases map[string]string) []string {
+	if len(names) == 0 {
+		return names
+	}
+	resolved := make([]string, 0, len(names))
+	for _, name := range names {
+		if target, ok := aliases[name]; ok {
+			name = target
+		}
+		resolved = appendMissing(resolved, name)
+	}
+	return resolved
+}
+
+// appendMissing appends the names not in list yet
+func appendMissing(list []string, names ...string) []string {
+	for _, name := range names {
+		if !slices.Contains(list, name) {
+			list = append(list, name)
+		}
+	}
+	return list
+}
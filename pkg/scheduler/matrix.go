@@ -2,12 +2,29 @@ package scheduler
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/org/c8s/pkg/apis/v1alpha1"
 )
 
-// ExpandMatrix generates all combinations of matrix dimensions
+// Matrix exclusion expressions. An exclusion value is matched exactly
+// unless it starts with one of these prefixes.
+const (
+	// MatrixExcludeNot prefixes a value every other value matches
+	// (e.g. "!=ubuntu")
+	MatrixExcludeNot = "!="
+
+	// MatrixExcludeRegexp wraps a regular expression the values match
+	// (e.g. "/^1\\.2[01]$/")
+	MatrixExcludeRegexp = "/"
+)
+
+// ExpandMatrix generates all combinations of matrix dimensions that are not
+// excluded. Combinations are in lexicographic order: dimensions are sorted
+// by name, the first varying slowest, and the values of each dimension are
+// sorted, so the same matrix always yields the same sequence.
 // Returns a slice of maps where each map represents one matrix combination
 func ExpandMatrix(matrix *v1alpha1.MatrixStrategy) ([]map[string]string, error) {
 	if matrix == nil {
@@ -25,11 +42,16 @@ func ExpandMatrix(matrix *v1alpha1.MatrixStrategy) ([]map[string]string, error)
 		}
 	}
 
-	// Generate all combinations recursively
+	exclusions, err := compileExclusions(matrix.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate all combinations in order
 	combinations := generateCombinations(matrix.Dimensions)
 
 	// Filter out excluded combinations
-	filtered := filterExclusions(combinations, matrix.Exclude)
+	filtered := filterExclusions(combinations, exclusions)
 
 	if len(filtered) == 0 {
 		return nil, fmt.Errorf("all matrix combinations are excluded")
@@ -38,59 +60,79 @@ func ExpandMatrix(matrix *v1alpha1.MatrixStrategy) ([]map[string]string, error)
 	return filtered, nil
 }
 
-// generateCombinations recursively generates all combinations of dimension values
+// generateCombinations generates all combinations of dimension values in
+// lexicographic order of the sorted dimension names and values
 func generateCombinations(dimensions map[string][]string) []map[string]string {
-	if len(dimensions) == 0 {
-		return []map[string]string{{}}
+	names := make([]string, 0, len(dimensions))
+	for name := range dimensions {
+		names = append(names, name)
 	}
-
-	// Convert map to slice for consistent ordering
-	type dimPair struct {
-		key    string
-		values []string
-	}
-	var dims []dimPair
-	for k, v := range dimensions {
-		dims = append(dims, dimPair{k, v})
-	}
-
-	// Base case: one dimension
-	if len(dims) == 1 {
-		var result []map[string]string
-		for _, value := range dims[0].values {
-			result = append(result, map[string]string{
-				dims[0].key: value,
-			})
+	sort.Strings(names)
+
+	result := []map[string]string{{}}
+	for _, name := range names {
+		values := append([]string(nil), dimensions[name]...)
+		sort.Strings(values)
+
+		next := make([]map[string]string, 0, len(result)*len(values))
+		for _, prefix := range result {
+			for _, value := range values {
+				combo := make(map[string]string, len(prefix)+1)
+				for k, v := range prefix {
+					combo[k] = v
+				}
+				combo[name] = value
+				next = append(next, combo)
+			}
 		}
-		return result
+		result = next
 	}
 
-	// Recursive case: split into first dimension and rest
-	firstDim := dims[0]
-	restDims := make(map[string][]string)
-	for _, dim := range dims[1:] {
-		restDims[dim.key] = dim.values
-	}
-
-	restCombinations := generateCombinations(restDims)
+	return result
+}
 
-	var result []map[string]string
-	for _, value := range firstDim.values {
-		for _, restCombo := range restCombinations {
-			combo := make(map[string]string)
-			combo[firstDim.key] = value
-			for k, v := range restCombo {
-				combo[k] = v
+// matrixValueMatcher reports whether a dimension value matches an
+// exclusion value
+type matrixValueMatcher func(value string) bool
+
+// compileExclusions compiles the values of exclusion rules into matchers
+func compileExclusions(exclusions []map[string]string) ([]map[string]matrixValueMatcher, error) {
+	compiled := make([]map[string]matrixValueMatcher, 0, len(exclusions))
+	for i, exclusion := range exclusions {
+		matchers := make(map[string]matrixValueMatcher, len(exclusion))
+		for key, expression := range exclusion {
+			matcher, err := CompileMatrixExclusion(expression)
+			if err != nil {
+				return nil, fmt.Errorf("matrix exclude[%d].%s: %w", i, key, err)
 			}
-			result = append(result, combo)
+			matchers[key] = matcher
 		}
+		compiled = append(compiled, matchers)
 	}
+	return compiled, nil
+}
 
-	return result
+// CompileMatrixExclusion returns the matcher of an exclusion value: a
+// regular expression wrapped in MatrixExcludeRegexp, a value prefixed with
+// MatrixExcludeNot, or an exact value
+func CompileMatrixExclusion(expression string) (func(value string) bool, error) {
+	switch {
+	case len(expression) >= 2 && strings.HasPrefix(expression, MatrixExcludeRegexp) && strings.HasSuffix(expression, MatrixExcludeRegexp):
+		pattern, err := regexp.Compile(expression[1 : len(expression)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %s: %w", expression, err)
+		}
+		return pattern.MatchString, nil
+	case strings.HasPrefix(expression, MatrixExcludeNot):
+		excluded := strings.TrimPrefix(expression, MatrixExcludeNot)
+		return func(value string) bool { return value != excluded }, nil
+	default:
+		return func(value string) bool { return value == expression }, nil
+	}
 }
 
 // filterExclusions removes combinations that match exclusion rules
-func filterExclusions(combinations []map[string]string, exclusions []map[string]string) []map[string]string {
+func filterExclusions(combinations []map[string]string, exclusions []map[string]matrixValueMatcher) []map[string]string {
 	if len(exclusions) == 0 {
 		return combinations
 	}
@@ -106,7 +148,7 @@ func filterExclusions(combinations []map[string]string, exclusions []map[string]
 }
 
 // isExcluded checks if a combination matches any exclusion rule
-func isExcluded(combo map[string]string, exclusions []map[string]string) bool {
+func isExcluded(combo map[string]string, exclusions []map[string]matrixValueMatcher) bool {
 	for _, exclusion := range exclusions {
 		if matchesExclusion(combo, exclusion) {
 			return true
@@ -117,10 +159,10 @@ func isExcluded(combo map[string]string, exclusions []map[string]string) bool {
 
 // matchesExclusion checks if a combination matches a specific exclusion rule
 // All keys in the exclusion must match the combination
-func matchesExclusion(combo map[string]string, exclusion map[string]string) bool {
-	for key, value := range exclusion {
+func matchesExclusion(combo map[string]string, exclusion map[string]matrixValueMatcher) bool {
+	for key, matches := range exclusion {
 		comboValue, exists := combo[key]
-		if !exists || comboValue != value {
+		if !exists || !matches(comboValue) {
 			return false
 		}
 	}
@@ -166,9 +208,17 @@ func ApplyMatrixToStep(step v1alpha1.PipelineStep, matrixVars map[string]string)
 
 // GenerateMatrixRunName generates a unique name for a matrix run
 func GenerateMatrixRunName(baseName string, matrixIndex int, matrixVars map[string]string) string {
-	// Create a descriptive suffix from matrix variables
+	// Create a descriptive suffix from matrix variables, sorted by
+	// dimension so the name is stable
+	keys := make([]string, 0, len(matrixVars))
+	for k := range matrixVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	var parts []string
-	for k, v := range matrixVars {
+	for _, k := range keys {
+		v := matrixVars[k]
 		// Sanitize values for use in names (Kubernetes names must be DNS-1123 compliant)
 		sanitized := strings.ToLower(v)
 		sanitized = strings.ReplaceAll(sanitized, ":", "-")
@@ -177,10 +227,6 @@ func GenerateMatrixRunName(baseName string, matrixIndex int, matrixVars map[stri
 		parts = append(parts, fmt.Sprintf("%s-%s", k, sanitized))
 	}
 
-	// Sort for consistency
-	// We don't actually need to sort since map iteration is random,
-	// but the index will keep things unique anyway
-
 	suffix := strings.Join(parts, "-")
 	if len(suffix) > 40 {
 		suffix = suffix[:40]
@@ -209,3 +255,186 @@ func MatrixToLabels(matrixVars map[string]string) map[string]string {
 	}
 	return labels
 }
+
+// MatrixEnvPrefix prefixes the env vars the matrix values of a step are
+// injected as
+const MatrixEnvPrefix = "MATRIX_"
+
+// MatrixEnvName returns the env var a matrix dimension is injected as
+// (e.g. "go-version" -> "MATRIX_GO_VERSION")
+func MatrixEnvName(dimension string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(dimension))
+	return MatrixEnvPrefix + name
+}
+
+// HasStepMatrix reports whether any step of a PipelineConfig lists matrix
+// dimensions, in which case the matrix runs as Jobs of a single PipelineRun
+// rather than one PipelineRun per combination
+func HasStepMatrix(config *v1alpha1.PipelineConfig) bool {
+	for _, step := range config.Spec.Steps {
+		if len(step.MatrixDimensions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matrixExpansion is one of the steps a matrix-aware step is expanded into
+type matrixExpansion struct {
+	name   string
+	values map[string]string
+}
+
+// ExpandStepMatrix replaces every step listing matrix dimensions with one
+// step per unique combination of the values of those dimensions among the
+// combinations that are not excluded, named "{step}-{value}..." with the values
+// substituted like ApplyMatrixToStep. Steps listing no dimensions are kept
+// once. Dependencies on an expanded step become dependencies on each of its
+// expansions whose values agree with the depending step on their shared
+// dimensions, so a step listing no dimensions waits for all of them.
+//
+// Returns the steps and the matrix values of each expanded step by name.
+func ExpandStepMatrix(steps []v1alpha1.PipelineStep, matrix *v1alpha1.MatrixStrategy) ([]v1alpha1.PipelineStep, map[string]map[string]string, error) {
+	combinations, err := ExpandMatrix(matrix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		names[step.Name] = true
+	}
+
+	expansions := make(map[string][]matrixExpansion)
+	for _, step := range steps {
+		if len(step.MatrixDimensions) == 0 {
+			continue
+		}
+		for _, dimension := range step.MatrixDimensions {
+			if matrix == nil {
+				return nil, nil, fmt.Errorf("step %s lists matrix dimensions but the pipeline has no matrix", step.Name)
+			}
+			if _, exists := matrix.Dimensions[dimension]; !exists {
+				return nil, nil, fmt.Errorf("step %s: matrix dimension %s not found", step.Name, dimension)
+			}
+		}
+
+		for _, values := range projectCombinations(combinations, step.MatrixDimensions) {
+			name := expandedStepName(step.Name, step.MatrixDimensions, values)
+			if names[name] {
+				return nil, nil, fmt.Errorf("step %s: expanded step name %s is not unique", step.Name, name)
+			}
+			names[name] = true
+			expansions[step.Name] = append(expansions[step.Name], matrixExpansion{name: name, values: values})
+		}
+	}
+
+	var expanded []v1alpha1.PipelineStep
+	matrixValues := make(map[string]map[string]string)
+	for _, step := range steps {
+		if len(step.MatrixDimensions) == 0 {
+			step.DependsOn = expandDependencies(step.DependsOn, nil, expansions)
+			expanded = append(expanded, step)
+			continue
+		}
+		for _, expansion := range expansions[step.Name] {
+			newStep := ApplyMatrixToStep(step, expansion.values)
+			newStep.Name = expansion.name
+			newStep.DependsOn = expandDependencies(step.DependsOn, expansion.values, expansions)
+			expanded = append(expanded, newStep)
+			matrixValues[expansion.name] = expansion.values
+		}
+	}
+
+	return expanded, matrixValues, nil
+}
+
+// projectCombinations returns the unique values of dimensions among the
+// matrix combinations, in the lexicographic order of ExpandMatrix
+func projectCombinations(combinations []map[string]string, dimensions []string) []map[string]string {
+	seen := make(map[string]bool)
+	var projected []map[string]string
+	for _, combination := range combinations {
+		values := make(map[string]string, len(dimensions))
+		var key []string
+		for _, dimension := range dimensions {
+			values[dimension] = combination[dimension]
+			key = append(key, combination[dimension])
+		}
+		if seen[strings.Join(key, "\x00")] {
+			continue
+		}
+		seen[strings.Join(key, "\x00")] = true
+		projected = append(projected, values)
+	}
+
+	// Exclusions can leave the first occurrences of the values out of order
+	sorted := append([]string(nil), dimensions...)
+	sort.Strings(sorted)
+	sort.SliceStable(projected, func(i, j int) bool {
+		for _, dimension := range sorted {
+			if a, b := projected[i][dimension], projected[j][dimension]; a != b {
+				return a < b
+			}
+		}
+		return false
+	})
+	return projected
+}
+
+// expandedStepName returns the name of the expansion of a step for matrix
+// values, made DNS-1123 compliant (e.g. "test" -> "test-ubuntu-1-22")
+func expandedStepName(name string, dimensions []string, values map[string]string) string {
+	parts := []string{name}
+	for _, dimension := range dimensions {
+		value := strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return '-'
+		}, strings.ToLower(values[dimension]))
+		if value = strings.Trim(value, "-"); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// expandDependencies replaces the dependencies on expanded steps with the
+// expansions agreeing with values on their shared dimensions
+func expandDependencies(dependsOn []string, values map[string]string, expansions map[string][]matrixExpansion) []string {
+	if len(dependsOn) == 0 {
+		return dependsOn
+	}
+
+	var deps []string
+	for _, dep := range dependsOn {
+		depExpansions, expanded := expansions[dep]
+		if !expanded {
+			deps = append(deps, dep)
+			continue
+		}
+		for _, expansion := range depExpansions {
+			if matrixValuesAgree(values, expansion.values) {
+				deps = append(deps, expansion.name)
+			}
+		}
+	}
+	return deps
+}
+
+// matrixValuesAgree reports whether two sets of matrix values have the same
+// value for every dimension they share
+func matrixValuesAgree(a, b map[string]string) bool {
+	for dimension, value := range a {
+		if other, shared := b[dimension]; shared && other != value {
+			return false
+		}
+	}
+	return true
+}
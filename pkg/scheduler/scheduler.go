@@ -17,11 +17,21 @@ limitations under the License.
 package scheduler
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
 )
 
 // Schedule represents an execution plan for pipeline steps
 type Schedule struct {
+	// Name is the name of the PipelineConfig the schedule was built from.
+	// Steps of the schedule are named "{Name}/{step-name}" once it is merged
+	// into another schedule.
+	Name string
+
 	// Layers contains ordered layers of steps
 	// Steps within a layer can execute in parallel
 	// Layers must execute sequentially
@@ -29,6 +39,14 @@ type Schedule struct {
 
 	// DAG is the underlying dependency graph
 	DAG *DAG
+
+	// MatrixValues are the matrix values of the steps expanded from steps
+	// listing matrixDimensions, by step name
+	MatrixValues map[string]map[string]string
+
+	// Aliases maps the steps merged by spec.deduplicateSteps to the step
+	// they were merged into
+	Aliases map[string]string
 }
 
 // Layer represents a set of steps that can execute in parallel
@@ -40,14 +58,137 @@ type Layer struct {
 	StepNames []string
 }
 
-// BuildSchedule creates an execution schedule from a PipelineConfig
+// BuildSchedule creates an execution schedule from a PipelineConfig. Steps may
+// depend on steps of the configs listed in spec.includes as
+// "{config-name}/{step-name}"; those dependencies are resolved by
+// MergeSchedules.
 func BuildSchedule(config *c8sv1alpha1.PipelineConfig) (*Schedule, error) {
+	// Steps listing matrix dimensions run once per combination of their values
+	steps := config.Spec.Steps
+	var matrixValues map[string]map[string]string
+	if HasStepMatrix(config) {
+		var err error
+		if steps, matrixValues, err = ExpandStepMatrix(steps, config.Spec.Matrix); err != nil {
+			return nil, err
+		}
+	}
+
+	// Identical steps run once when spec.deduplicateSteps is set
+	var aliases map[string]string
+	if config.Spec.DeduplicateSteps {
+		steps, aliases = deduplicateSteps(steps)
+	}
+
 	// Build DAG from steps
-	dag, err := BuildDAG(config.Spec.Steps)
+	dag, err := BuildDAG(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	included := make(map[string]bool, len(config.Spec.Includes))
+	for _, ref := range config.Spec.Includes {
+		included[ref.Name] = true
+	}
+	for _, dep := range dag.UnresolvedDependencies() {
+		configName, _, _ := strings.Cut(dep, "/")
+		if !included[configName] {
+			return nil, fmt.Errorf("%w: %s is a step of PipelineConfig %s, which is not included",
+				types.ErrStepNotFound, dep, configName)
+		}
+	}
+
+	schedule, err := newSchedule(config.Name, dag)
 	if err != nil {
 		return nil, err
 	}
+	schedule.MatrixValues = matrixValues
+	if len(aliases) > 0 {
+		schedule.Aliases = aliases
+	}
+	return schedule, nil
+}
+
+// MergeSchedules merges the steps of an included schedule into base. The
+// included steps are renamed "{included.Name}/{step-name}", so steps of base
+// can depend on them by that name, and included steps can depend on steps of
+// base as "{base.Name}/{step-name}". Dependencies on steps of other configs
+// stay unresolved until those are merged too. Cycles are detected across the
+// merged graph.
+func MergeSchedules(base, included *Schedule) (*Schedule, error) {
+	if included.Name == "" {
+		return nil, fmt.Errorf("included schedule has no name")
+	}
+	prefix := included.Name + "/"
+	basePrefix := base.Name + "/"
+
+	// Dependencies on merged steps of either schedule are resolved to the
+	// step they were merged into
+	var aliases map[string]string
+	if len(base.Aliases)+len(included.Aliases) > 0 {
+		aliases = make(map[string]string, len(base.Aliases)+len(included.Aliases))
+		for alias, target := range base.Aliases {
+			aliases[alias] = target
+		}
+		for alias, target := range included.Aliases {
+			aliases[prefix+alias] = prefix + target
+		}
+	}
+
+	steps := make([]c8sv1alpha1.PipelineStep, 0, base.TotalSteps()+included.TotalSteps())
+	for _, layer := range base.Layers {
+		for _, step := range layer.Steps {
+			steps = append(steps, *step)
+		}
+	}
 
+	for _, layer := range included.Layers {
+		for _, step := range layer.Steps {
+			merged := *step
+			merged.Name = prefix + step.Name
+			merged.DependsOn = make([]string, 0, len(step.DependsOn))
+			for _, dep := range step.DependsOn {
+				switch {
+				case base.Name != "" && strings.HasPrefix(dep, basePrefix):
+					merged.DependsOn = append(merged.DependsOn, strings.TrimPrefix(dep, basePrefix))
+				case strings.Contains(dep, "/"):
+					merged.DependsOn = append(merged.DependsOn, dep)
+				default:
+					merged.DependsOn = append(merged.DependsOn, prefix+dep)
+				}
+			}
+			steps = append(steps, merged)
+		}
+	}
+	if aliases != nil {
+		for i := range steps {
+			steps[i].DependsOn = resolveAliases(steps[i].DependsOn, aliases)
+		}
+	}
+
+	dag, err := BuildDAG(steps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge %s into %s: %w", included.Name, base.Name, err)
+	}
+	merged, err := newSchedule(base.Name, dag)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(base.MatrixValues)+len(included.MatrixValues) > 0 {
+		merged.MatrixValues = make(map[string]map[string]string, len(base.MatrixValues)+len(included.MatrixValues))
+		for name, values := range base.MatrixValues {
+			merged.MatrixValues[name] = values
+		}
+		for name, values := range included.MatrixValues {
+			merged.MatrixValues[prefix+name] = values
+		}
+	}
+	merged.Aliases = aliases
+	return merged, nil
+}
+
+// newSchedule groups the steps of a DAG into execution layers
+func newSchedule(name string, dag *DAG) (*Schedule, error) {
 	// Get topological ordering in layers
 	layerNames, err := dag.TopologicalSort()
 	if err != nil {
@@ -72,14 +213,43 @@ func BuildSchedule(config *c8sv1alpha1.PipelineConfig) (*Schedule, error) {
 	}
 
 	return &Schedule{
+		Name:   name,
 		Layers: layers,
 		DAG:    dag,
 	}, nil
 }
 
+// ReadyStepsOption configures GetReadySteps
+type ReadyStepsOption func(*readyStepsOptions)
+
+type readyStepsOptions struct {
+	weightSort bool
+}
+
+// WithWeightSort orders ready steps by descending weight, then by name so
+// that the order doesn't depend on layer order
+func WithWeightSort() ReadyStepsOption {
+	return func(o *readyStepsOptions) {
+		o.weightSort = true
+	}
+}
+
+// StepWeight returns the scheduling weight of a step (default 1)
+func StepWeight(step *c8sv1alpha1.PipelineStep) int {
+	if step.Weight > 0 {
+		return step.Weight
+	}
+	return 1
+}
+
 // GetReadySteps returns steps that are ready to execute given completed steps
 // A step is ready if all its dependencies have completed successfully
-func (s *Schedule) GetReadySteps(completedSteps map[string]bool) []*c8sv1alpha1.PipelineStep {
+func (s *Schedule) GetReadySteps(completedSteps map[string]bool, opts ...ReadyStepsOption) []*c8sv1alpha1.PipelineStep {
+	options := &readyStepsOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	var ready []*c8sv1alpha1.PipelineStep
 
 	for _, layer := range s.Layers {
@@ -104,6 +274,15 @@ func (s *Schedule) GetReadySteps(completedSteps map[string]bool) []*c8sv1alpha1.
 		}
 	}
 
+	if options.weightSort {
+		sort.Slice(ready, func(i, j int) bool {
+			if wi, wj := StepWeight(ready[i]), StepWeight(ready[j]); wi != wj {
+				return wi > wj
+			}
+			return ready[i].Name < ready[j].Name
+		})
+	}
+
 	return ready
 }
 
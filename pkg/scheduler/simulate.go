@@ -0,0 +1,128 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
+)
+
+// SimulationResult is the predicted execution of a schedule
+type SimulationResult struct {
+	// EstimatedDuration is the predicted wall-clock time of the pipeline
+	EstimatedDuration time.Duration `json:"estimatedDuration"`
+
+	// CriticalPath is the chain of dependent steps that determines the
+	// estimated duration, in execution order
+	CriticalPath []string `json:"criticalPath"`
+
+	// WallClockByStep holds the predicted start and end of each step,
+	// relative to the start of the pipeline
+	WallClockByStep map[string][2]time.Duration `json:"wallClockByStep"`
+}
+
+// Simulate predicts the execution of a schedule. Each step is assumed to
+// run for its timeout, or types.DefaultStepTimeout when it has none, and
+// starts as soon as its dependencies have finished. Steps are started layer
+// by layer; with parallelism > 0 at most that many steps run at once.
+func Simulate(schedule *Schedule, parallelism int) *SimulationResult {
+	result := &SimulationResult{
+		CriticalPath:    []string{},
+		WallClockByStep: make(map[string][2]time.Duration),
+	}
+
+	// Times at which each execution slot becomes free
+	var slots []time.Duration
+	if parallelism > 0 {
+		slots = make([]time.Duration, parallelism)
+	}
+
+	var last string
+	for _, layer := range schedule.Layers {
+		for _, step := range layer.Steps {
+			var start time.Duration
+			for _, dep := range step.DependsOn {
+				if window, ok := result.WallClockByStep[dep]; ok && window[1] > start {
+					start = window[1]
+				}
+			}
+
+			if slots != nil {
+				sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+				if slots[0] > start {
+					start = slots[0]
+				}
+			}
+
+			end := start + EstimateStepDuration(step)
+			result.WallClockByStep[step.Name] = [2]time.Duration{start, end}
+			if slots != nil {
+				slots[0] = end
+			}
+
+			if end > result.EstimatedDuration || last == "" {
+				result.EstimatedDuration = end
+				last = step.Name
+			}
+		}
+	}
+
+	result.CriticalPath = criticalPath(schedule, result.WallClockByStep, last)
+	return result
+}
+
+// EstimateStepDuration returns the duration a step is assumed to run for
+func EstimateStepDuration(step *c8sv1alpha1.PipelineStep) time.Duration {
+	if step.Timeout != "" {
+		if d, err := time.ParseDuration(step.Timeout); err == nil {
+			return d
+		}
+	}
+	d, _ := time.ParseDuration(types.DefaultStepTimeout)
+	return d
+}
+
+// criticalPath walks back from the last step to finish, following the
+// dependency that finished last at each step
+func criticalPath(schedule *Schedule, windows map[string][2]time.Duration, last string) []string {
+	path := []string{}
+	for name := last; name != ""; {
+		path = append(path, name)
+
+		step, ok := schedule.DAG.GetStep(name)
+		if !ok {
+			break
+		}
+		next := ""
+		var latest time.Duration
+		for _, dep := range step.DependsOn {
+			if window, ok := windows[dep]; ok && (next == "" || window[1] > latest) {
+				next, latest = dep, window[1]
+			}
+		}
+		name = next
+	}
+
+	// Reverse into execution order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
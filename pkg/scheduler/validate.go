@@ -0,0 +1,233 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// WarningSeverity classifies a ScheduleWarning
+type WarningSeverity string
+
+const (
+	// SeverityError marks issues that will make a run fail or hang
+	SeverityError WarningSeverity = "error"
+
+	// SeverityWarning marks issues that are almost certainly configuration mistakes
+	SeverityWarning WarningSeverity = "warning"
+
+	// SeverityInfo marks issues that may be intended
+	SeverityInfo WarningSeverity = "info"
+)
+
+// ScheduleWarning describes a configuration issue that only manifests at runtime
+type ScheduleWarning struct {
+	// Severity of the issue
+	Severity WarningSeverity `json:"severity" yaml:"severity"`
+
+	// Step is the affected step (empty for issues affecting a whole layer)
+	Step string `json:"step,omitempty" yaml:"step,omitempty"`
+
+	// Message describes the issue
+	Message string `json:"message" yaml:"message"`
+}
+
+// NodeCapacity is the allocatable resources of a cluster node
+type NodeCapacity struct {
+	// Name of the node
+	Name string
+
+	// CPU allocatable on the node
+	CPU resource.Quantity
+
+	// Memory allocatable on the node
+	Memory resource.Quantity
+}
+
+// NodeCapacityFunc returns the allocatable resources of the cluster nodes
+type NodeCapacityFunc func() ([]NodeCapacity, error)
+
+// ValidateOptions configures the checks of ValidateScheduleWithOptions
+type ValidateOptions struct {
+	// MaxParallel is the maximum number of Jobs run at once (0 for no limit)
+	MaxParallel int
+
+	// NodeCapacity returns the cluster nodes to check step resource
+	// requests against. The check is skipped when nil.
+	NodeCapacity NodeCapacityFunc
+}
+
+// ValidateSchedule checks a schedule for issues that only manifest at runtime.
+// Checks that need cluster information are skipped; use
+// ValidateScheduleWithOptions to enable them.
+func ValidateSchedule(schedule *Schedule, config *c8sv1alpha1.PipelineConfig) []ScheduleWarning {
+	return ValidateScheduleWithOptions(schedule, config, ValidateOptions{})
+}
+
+// ValidateScheduleWithOptions checks a schedule for steps that can never run,
+// steps that cannot be scheduled on any node, layers wider than MaxParallel
+// and layers that may be skipped entirely
+func ValidateScheduleWithOptions(schedule *Schedule, config *c8sv1alpha1.PipelineConfig, opts ValidateOptions) []ScheduleWarning {
+	var warnings []ScheduleWarning
+
+	warnings = append(warnings, checkBranchConditionals(schedule, config.Spec.Branches)...)
+
+	if opts.NodeCapacity != nil {
+		nodes, err := opts.NodeCapacity()
+		if err != nil {
+			warnings = append(warnings, ScheduleWarning{
+				Severity: SeverityInfo,
+				Message:  fmt.Sprintf("node capacity check skipped: %v", err),
+			})
+		} else {
+			warnings = append(warnings, checkNodeCapacity(schedule, nodes)...)
+		}
+	}
+
+	for i, layer := range schedule.Layers {
+		if opts.MaxParallel > 0 && len(layer.Steps) > opts.MaxParallel {
+			warnings = append(warnings, ScheduleWarning{
+				Severity: SeverityWarning,
+				Message: fmt.Sprintf("layer %d has %d steps but at most %d Jobs run in parallel; steps %s will be queued",
+					i, len(layer.Steps), opts.MaxParallel, strings.Join(layer.StepNames[opts.MaxParallel:], ", ")),
+			})
+		}
+
+		if isConditionalLayer(layer) {
+			warnings = append(warnings, ScheduleWarning{
+				Severity: SeverityInfo,
+				Message: fmt.Sprintf("all steps in layer %d (%s) are conditional; the layer is empty when no branch condition matches",
+					i, strings.Join(layer.StepNames, ", ")),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// checkBranchConditionals reports steps whose branch conditional matches none of
+// the trigger branches. Trigger branches are glob patterns, so the check only
+// applies when every pattern is a literal branch name.
+func checkBranchConditionals(schedule *Schedule, branches []string) []ScheduleWarning {
+	if len(branches) == 0 {
+		return nil
+	}
+	for _, branch := range branches {
+		if strings.ContainsAny(branch, "*?[") {
+			return nil
+		}
+	}
+
+	var warnings []ScheduleWarning
+	for _, layer := range schedule.Layers {
+		for _, step := range layer.Steps {
+			if step.Conditional == nil || step.Conditional.Branch == "" {
+				continue
+			}
+
+			// Invalid patterns are reported by parser.Validate
+			pattern, err := regexp.Compile(step.Conditional.Branch)
+			if err != nil {
+				continue
+			}
+
+			matched := false
+			for _, branch := range branches {
+				if pattern.MatchString(branch) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				warnings = append(warnings, ScheduleWarning{
+					Severity: SeverityWarning,
+					Step:     step.Name,
+					Message: fmt.Sprintf("branch conditional %q matches none of the trigger branches (%s); the step is always skipped",
+						step.Conditional.Branch, strings.Join(branches, ", ")),
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// checkNodeCapacity reports steps whose resource requests fit on no node
+func checkNodeCapacity(schedule *Schedule, nodes []NodeCapacity) []ScheduleWarning {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	var warnings []ScheduleWarning
+	for _, layer := range schedule.Layers {
+		for _, step := range layer.Steps {
+			if step.Resources == nil {
+				continue
+			}
+
+			// Invalid quantities are reported by parser.Validate
+			cpu, cpuErr := parseOptionalQuantity(step.Resources.CPU)
+			memory, memoryErr := parseOptionalQuantity(step.Resources.Memory)
+			if cpuErr != nil || memoryErr != nil {
+				continue
+			}
+
+			fits := false
+			for _, node := range nodes {
+				if cpu.Cmp(node.CPU) <= 0 && memory.Cmp(node.Memory) <= 0 {
+					fits = true
+					break
+				}
+			}
+			if !fits {
+				warnings = append(warnings, ScheduleWarning{
+					Severity: SeverityError,
+					Step:     step.Name,
+					Message: fmt.Sprintf("requests cpu=%s memory=%s exceed the capacity of every node; the step's Pod cannot be scheduled",
+						cpu.String(), memory.String()),
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// parseOptionalQuantity parses a resource quantity, treating "" as zero
+func parseOptionalQuantity(value string) (resource.Quantity, error) {
+	if value == "" {
+		return resource.Quantity{}, nil
+	}
+	return resource.ParseQuantity(value)
+}
+
+// isConditionalLayer returns true if every step in the layer has a branch conditional
+func isConditionalLayer(layer Layer) bool {
+	if len(layer.Steps) == 0 {
+		return false
+	}
+	for _, step := range layer.Steps {
+		if step.Conditional == nil || step.Conditional.Branch == "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SecretCache caches the data of Secrets read for log masking, so masking
+// does not fetch every referenced Secret on each log collection. Entries are
+// keyed by {namespace}/{secret-name}/{version}, the version being the Secret
+// resourceVersion. It is safe for concurrent use.
+type SecretCache struct {
+	mu sync.RWMutex
+
+	// entries holds the Secret data by cache key
+	entries map[string]map[string][]byte
+
+	// versions holds the cached version of each {namespace}/{secret-name}
+	versions map[string]string
+}
+
+// NewSecretCache creates an empty SecretCache
+func NewSecretCache() *SecretCache {
+	return &SecretCache{
+		entries:  make(map[string]map[string][]byte),
+		versions: make(map[string]string),
+	}
+}
+
+// CacheKey returns the cache key of a version of a Secret
+func CacheKey(namespace, name, version string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, name, version)
+}
+
+// Get returns the cached data of a Secret
+func (c *SecretCache) Get(namespace, name string) (map[string][]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	version, ok := c.versions[namespace+"/"+name]
+	if !ok {
+		return nil, false
+	}
+	data, ok := c.entries[CacheKey(namespace, name, version)]
+	return data, ok
+}
+
+// Set caches the data of a version of a Secret, replacing any other cached
+// version of it
+func (c *SecretCache) Set(namespace, name, version string, data map[string][]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidate(namespace, name)
+	c.versions[namespace+"/"+name] = version
+	c.entries[CacheKey(namespace, name, version)] = data
+}
+
+// Invalidate removes every cached version of a Secret
+func (c *SecretCache) Invalidate(namespace, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidate(namespace, name)
+}
+
+// invalidate removes a Secret from the cache; c.mu must be held
+func (c *SecretCache) invalidate(namespace, name string) {
+	if version, ok := c.versions[namespace+"/"+name]; ok {
+		delete(c.entries, CacheKey(namespace, name, version))
+		delete(c.versions, namespace+"/"+name)
+	}
+}
+
+// Len returns the number of cached Secrets
+func (c *SecretCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.entries)
+}
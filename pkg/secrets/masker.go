@@ -17,7 +17,9 @@ limitations under the License.
 package secrets
 
 import (
+	"bufio"
 	"bytes"
+	"io"
 	"regexp"
 	"strings"
 )
@@ -66,6 +68,37 @@ func MaskSecrets(logs []byte, secrets map[string]string) []byte {
 	return masked
 }
 
+// MaskReader returns a reader of r with secret values masked like
+// MaskSecrets. Content is masked line by line, so secret values spanning
+// several lines are not masked. Close the reader to stop masking before the
+// end of r.
+func MaskReader(r io.Reader, secrets map[string]string) io.ReadCloser {
+	if len(secrets) == 0 {
+		return io.NopCloser(r)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		reader := bufio.NewReader(r)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if _, werr := pw.Write(MaskSecrets(line, secrets)); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr
+}
+
 // MaskSecretsString is a convenience function that works with strings
 func MaskSecretsString(logs string, secrets map[string]string) string {
 	return string(MaskSecrets([]byte(logs), secrets))
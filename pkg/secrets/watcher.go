@@ -0,0 +1,94 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SecretRotationWatcher watches Secrets and invalidates their SecretCache
+// entries when they are modified or deleted, so rotated values are fetched
+// again instead of being served stale to the masker.
+type SecretRotationWatcher struct {
+	cache   *SecretCache
+	factory informers.SharedInformerFactory
+}
+
+// NewSecretRotationWatcher creates a watcher invalidating entries of c on
+// changes to Secrets of namespace, or of all namespaces when it is empty
+func NewSecretRotationWatcher(client kubernetes.Interface, c *SecretCache, namespace string) *SecretRotationWatcher {
+	return &SecretRotationWatcher{
+		cache:   c,
+		factory: informers.NewSharedInformerFactoryWithOptions(client, 10*time.Minute, informers.WithNamespace(namespace)),
+	}
+}
+
+// Start watches Secrets until ctx is done. It satisfies manager.Runnable so
+// the watcher can be added to the controller manager.
+func (w *SecretRotationWatcher) Start(ctx context.Context) error {
+	informer := w.factory.Core().V1().Secrets().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: w.onUpdate,
+		DeleteFunc: w.onDelete,
+	}); err != nil {
+		return fmt.Errorf("failed to register secret event handler: %w", err)
+	}
+
+	w.factory.Start(ctx.Done())
+	defer w.factory.Shutdown()
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync secret informer")
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// onUpdate invalidates a Secret whose content changed. Periodic resyncs
+// deliver updates with an unchanged resourceVersion, which are ignored.
+func (w *SecretRotationWatcher) onUpdate(oldObj, newObj interface{}) {
+	oldSecret, ok := oldObj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	newSecret, ok := newObj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	if oldSecret.ResourceVersion == newSecret.ResourceVersion {
+		return
+	}
+	w.cache.Invalidate(newSecret.Namespace, newSecret.Name)
+}
+
+// onDelete invalidates a deleted Secret
+func (w *SecretRotationWatcher) onDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if secret, ok := obj.(*corev1.Secret); ok {
+		w.cache.Invalidate(secret.Namespace, secret.Name)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+const (
+	// ContentEncodingGzip marks objects stored gzip-compressed
+	ContentEncodingGzip = "gzip"
+)
+
+// CompressLog gzip-compresses log content for upload
+func CompressLog(logs []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+
+	if _, err := io.Copy(writer, bytes.NewReader(logs)); err != nil {
+		return nil, fmt.Errorf("failed to compress logs: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress logs: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecompressLog wraps downloaded log content so it is read as plain text.
+// Content without a gzip encoding is returned unchanged.
+func DecompressLog(content io.ReadCloser, contentEncoding string) (io.ReadCloser, error) {
+	if contentEncoding != ContentEncodingGzip {
+		return content, nil
+	}
+
+	reader, err := gzip.NewReader(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress logs: %w", err)
+	}
+	return &gzipReadCloser{Reader: reader, body: content}, nil
+}
+
+// gzipReadCloser closes both the gzip reader and the underlying body
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+// Close closes the gzip reader and the underlying body
+func (g *gzipReadCloser) Close() error {
+	gzipErr := g.Reader.Close()
+	if err := g.body.Close(); err != nil {
+		return err
+	}
+	return gzipErr
+}
@@ -38,6 +38,9 @@ var (
 	// ErrDownloadFailed indicates the download operation failed
 	ErrDownloadFailed = errors.New("failed to download object from storage")
 
+	// ErrCopyFailed indicates the copy operation failed
+	ErrCopyFailed = errors.New("failed to copy object in storage")
+
 	// ErrDeleteFailed indicates the delete operation failed
 	ErrDeleteFailed = errors.New("failed to delete object from storage")
 
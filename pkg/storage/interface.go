@@ -27,10 +27,21 @@ import (
 type StorageClient interface {
 	// UploadLog uploads log content to object storage
 	// key format: "c8s-logs/{namespace}/{pipeline-run}/{step-name}.log"
-	UploadLog(ctx context.Context, key string, content io.Reader) error
+	// contentEncoding is empty for plain text or ContentEncodingGzip
+	UploadLog(ctx context.Context, key string, content io.Reader, contentEncoding string) error
 
-	// DownloadLog downloads log content from object storage
-	DownloadLog(ctx context.Context, key string) (io.ReadCloser, error)
+	// DownloadLog downloads log content from object storage and returns it
+	// as stored, together with its content encoding (see DecompressLog)
+	DownloadLog(ctx context.Context, key string) (io.ReadCloser, string, error)
+
+	// GetLog streams log content from object storage as plain text,
+	// decompressing it when it was stored compressed
+	GetLog(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// CopyLog copies a log object from srcKey to dstKey server-side, keeping
+	// its content encoding. dstKey may name another bucket with
+	// ArchiveObjectKey, e.g. to archive logs to cold storage.
+	CopyLog(ctx context.Context, srcKey, dstKey string) error
 
 	// UploadArtifact uploads an artifact file to object storage
 	// key format: "c8s-artifacts/{namespace}/{pipeline-run}/{step-name}/{filename}"
@@ -74,6 +85,11 @@ type Config struct {
 
 	// UsePathStyle forces path-style URLs (required for MinIO)
 	UsePathStyle bool
+
+	// ArchiveBucket is the bucket logs are copied to before
+	// ApplyRetentionPolicy deletes them (optional, e.g. a bucket whose
+	// lifecycle moves objects to S3 Glacier)
+	ArchiveBucket string
 }
 
 // Validate validates the storage configuration
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// archiveKeyScheme prefixes object keys that name their own bucket
+const archiveKeyScheme = "s3://"
+
+// ArchiveObjectKey returns the key addressing key in another bucket, for
+// use as the destination of CopyLog
+func ArchiveObjectKey(bucket, key string) string {
+	return archiveKeyScheme + bucket + "/" + key
+}
+
+// ParseArchiveObjectKey splits a key returned by ArchiveObjectKey into its
+// bucket and key. ok is false for keys of the client's own bucket.
+func ParseArchiveObjectKey(objectKey string) (bucket, key string, ok bool) {
+	rest, found := strings.CutPrefix(objectKey, archiveKeyScheme)
+	if !found {
+		return "", objectKey, false
+	}
+	bucket, key, found = strings.Cut(rest, "/")
+	if !found || bucket == "" || key == "" {
+		return "", objectKey, false
+	}
+	return bucket, key, true
+}
+
+// RunLogPrefix returns the prefix of the stored logs of a pipeline run
+func RunLogPrefix(namespace, pipelineRun string) string {
+	return fmt.Sprintf("%s/%s/", namespace, pipelineRun)
+}
+
+// RetentionResult counts the logs handled by ApplyRetentionPolicy
+type RetentionResult struct {
+	Archived int
+	Deleted  int
+}
+
+// ApplyRetentionPolicy deletes the logs stored under each prefix, typically
+// the RunLogPrefix of expired pipeline runs, from the primary bucket. When
+// config.ArchiveBucket is set, each log is first copied to the same key of
+// the archive bucket and is only deleted once the copy succeeded.
+func ApplyRetentionPolicy(ctx context.Context, client StorageClient, config *Config, prefixes []string) (*RetentionResult, error) {
+	result := &RetentionResult{}
+	for _, prefix := range prefixes {
+		keys, err := client.ListObjects(ctx, prefix)
+		if err != nil {
+			return result, err
+		}
+
+		for _, key := range keys {
+			if !strings.HasSuffix(key, ".log") {
+				continue
+			}
+			if config.ArchiveBucket != "" {
+				if err := client.CopyLog(ctx, key, ArchiveObjectKey(config.ArchiveBucket, key)); err != nil {
+					return result, fmt.Errorf("failed to archive log %s: %w", key, err)
+				}
+				result.Archived++
+			}
+			if err := client.DeleteObject(ctx, key); err != nil {
+				return result, err
+			}
+			result.Deleted++
+		}
+	}
+	return result, nil
+}
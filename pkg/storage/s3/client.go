@@ -21,10 +21,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -73,13 +75,18 @@ func NewClient(config *storage.Config) (*Client, error) {
 }
 
 // UploadLog uploads log content to S3
-func (c *Client) UploadLog(ctx context.Context, key string, content io.Reader) error {
-	_, err := c.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+func (c *Client) UploadLog(ctx context.Context, key string, content io.Reader, contentEncoding string) error {
+	input := &s3manager.UploadInput{
 		Bucket:      aws.String(c.bucket),
 		Key:         aws.String(key),
 		Body:        content,
 		ContentType: aws.String("text/plain"),
-	})
+	}
+	if contentEncoding != "" {
+		input.ContentEncoding = aws.String(contentEncoding)
+	}
+
+	_, err := c.uploader.UploadWithContext(ctx, input)
 	if err != nil {
 		return fmt.Errorf("%w: %v", storage.ErrUploadFailed, err)
 	}
@@ -87,15 +94,53 @@ func (c *Client) UploadLog(ctx context.Context, key string, content io.Reader) e
 }
 
 // DownloadLog downloads log content from S3
-func (c *Client) DownloadLog(ctx context.Context, key string) (io.ReadCloser, error) {
+func (c *Client) DownloadLog(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	// An explicit Accept-Encoding stops net/http from transparently
+	// decompressing gzip objects and dropping their Content-Encoding
 	result, err := c.s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(c.bucket),
 		Key:    aws.String(key),
+	}, func(r *request.Request) {
+		r.HTTPRequest.Header.Set("Accept-Encoding", "identity")
 	})
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", storage.ErrDownloadFailed, err)
+		return nil, "", fmt.Errorf("%w: %v", storage.ErrDownloadFailed, err)
 	}
-	return result.Body, nil
+	return result.Body, aws.StringValue(result.ContentEncoding), nil
+}
+
+// GetLog streams log content from S3 with GetObject, decompressing
+// gzip-encoded objects
+func (c *Client) GetLog(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, contentEncoding, err := c.DownloadLog(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := storage.DecompressLog(body, contentEncoding)
+	if err != nil {
+		_ = body.Close()
+		return nil, err
+	}
+	return logs, nil
+}
+
+// CopyLog copies a log object with CopyObject, so its content isn't
+// transferred through the caller. Content type and encoding are kept.
+func (c *Client) CopyLog(ctx context.Context, srcKey, dstKey string) error {
+	bucket := c.bucket
+	if archiveBucket, key, ok := storage.ParseArchiveObjectKey(dstKey); ok {
+		bucket, dstKey = archiveBucket, key
+	}
+
+	_, err := c.s3Client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(bucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(url.PathEscape(c.bucket + "/" + srcKey)),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", storage.ErrCopyFailed, err)
+	}
+	return nil
 }
 
 // UploadArtifact uploads an artifact file to S3
@@ -21,8 +21,9 @@ const (
 	// ConditionTypeReady indicates the PipelineRun is ready to execute
 	ConditionTypeReady = "Ready"
 
-	// ConditionTypePipelineConfigResolved indicates the PipelineConfig was found and loaded
-	ConditionTypePipelineConfigResolved = "PipelineConfigResolved"
+	// ConditionTypeConfigResolved indicates the PipelineConfig was found and
+	// a schedule could be built from it
+	ConditionTypeConfigResolved = "ConfigResolved"
 
 	// ConditionTypeJobsCreated indicates all Jobs have been created
 	ConditionTypeJobsCreated = "JobsCreated"
@@ -30,11 +31,20 @@ const (
 	// ConditionTypeStepsCompleted indicates all steps have completed (success or failure)
 	ConditionTypeStepsCompleted = "StepsCompleted"
 
-	// ConditionTypeLogsUploaded indicates logs have been uploaded to object storage
-	ConditionTypeLogsUploaded = "LogsUploaded"
+	// ConditionTypeLogsCollected indicates the logs of all completed steps have
+	// been uploaded to object storage
+	ConditionTypeLogsCollected = "LogsCollected"
 
 	// ConditionTypeArtifactsUploaded indicates artifacts have been uploaded
 	ConditionTypeArtifactsUploaded = "ArtifactsUploaded"
+
+	// ConditionTypeResourceQuotaExceeded indicates Job creation is throttled by
+	// the PipelineConfig resource quota
+	ConditionTypeResourceQuotaExceeded = "ResourceQuotaExceeded"
+
+	// ConditionTypeImported indicates the PipelineRun was imported from
+	// another cluster with `c8s run import` and is never executed
+	ConditionTypeImported = "Imported"
 )
 
 // Condition reasons for PipelineRun status
@@ -45,12 +55,18 @@ const (
 	// ReasonPipelineConfigResolved indicates the PipelineConfig was found
 	ReasonPipelineConfigResolved = "PipelineConfigResolved"
 
+	// ReasonInvalidPipelineConfig indicates no schedule could be built from the PipelineConfig
+	ReasonInvalidPipelineConfig = "InvalidPipelineConfig"
+
 	// ReasonJobCreationFailed indicates a Job could not be created
 	ReasonJobCreationFailed = "JobCreationFailed"
 
 	// ReasonJobsCreated indicates all Jobs were created successfully
 	ReasonJobsCreated = "JobsCreated"
 
+	// ReasonJobsPending indicates Jobs of steps waiting on dependencies are not created yet
+	ReasonJobsPending = "JobsPending"
+
 	// ReasonStepRunning indicates at least one step is running
 	ReasonStepRunning = "StepRunning"
 
@@ -69,18 +85,33 @@ const (
 	// ReasonCancelled indicates the pipeline was cancelled by user
 	ReasonCancelled = "Cancelled"
 
+	// ReasonCancelling indicates the Jobs of a cancelled pipeline are being deleted
+	ReasonCancelling = "Cancelling"
+
 	// ReasonStorageError indicates an error uploading to object storage
 	ReasonStorageError = "StorageError"
 
 	// ReasonLogsUploaded indicates logs were uploaded successfully
 	ReasonLogsUploaded = "LogsUploaded"
 
+	// ReasonLogsPending indicates completed steps are waiting for log collection
+	ReasonLogsPending = "LogsPending"
+
 	// ReasonArtifactsUploaded indicates artifacts were uploaded successfully
 	ReasonArtifactsUploaded = "ArtifactsUploaded"
 
 	// ReasonResourceQuotaExceeded indicates namespace quota was exceeded
 	ReasonResourceQuotaExceeded = "ResourceQuotaExceeded"
 
+	// ReasonWithinResourceQuota indicates Jobs fit within the namespace quota
+	ReasonWithinResourceQuota = "WithinResourceQuota"
+
+	// ReasonInvalidResourceQuota indicates a resource quota limit can't be parsed
+	ReasonInvalidResourceQuota = "InvalidResourceQuota"
+
+	// ReasonRunImported indicates the run's status was restored from an export bundle
+	ReasonRunImported = "RunImported"
+
 	// ReasonSecretNotFound indicates a referenced Secret doesn't exist
 	ReasonSecretNotFound = "SecretNotFound"
 )
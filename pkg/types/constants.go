@@ -24,8 +24,21 @@ const (
 	LabelStepName       = "c8s.dev/step-name"
 	LabelCommit         = "c8s.dev/commit"
 	LabelBranch         = "c8s.dev/branch"
+	LabelRepository     = "c8s.dev/repository"
 	LabelManagedBy      = "app.kubernetes.io/managed-by"
 
+	// LabelManaged marks every resource created for a pipeline run so that
+	// tooling can select them with "c8s.dev/managed=true"
+	LabelManaged = "c8s.dev/managed"
+
+	// LabelCreatedBy records the tool that created a PipelineRun; it is
+	// copied to the run's Jobs
+	LabelCreatedBy = "c8s.dev/created-by"
+
+	// LabelEnvironment on a namespace sets the environment of the
+	// PipelineRuns created in it by webhooks
+	LabelEnvironment = "c8s.dev/environment"
+
 	// Annotation keys
 	AnnotationCommitMessage = "c8s.dev/commit-message"
 	AnnotationAuthor        = "c8s.dev/author"
@@ -33,6 +46,31 @@ const (
 	AnnotationLogURL        = "c8s.dev/log-url"
 	AnnotationArtifactURLs  = "c8s.dev/artifact-urls"
 
+	// AnnotationRetryOf records the PipelineRun a retry was created from
+	AnnotationRetryOf = "c8s.dev/retry-of"
+
+	// AnnotationPreCompletedSteps lists (comma-separated) steps that already
+	// succeeded in the original run and are treated as completed on retry
+	AnnotationPreCompletedSteps = "c8s.dev/pre-completed-steps"
+
+	// AnnotationAbortReason records why a PipelineRun was aborted with `c8s run abort`
+	AnnotationAbortReason = "c8s.dev/abort-reason"
+
+	// AnnotationCancelRequested records when cancellation of a PipelineRun
+	// was requested through the API or `c8s run abort` (RFC 3339). The
+	// controller deletes the run's Jobs and marks it Cancelled.
+	AnnotationCancelRequested = "c8s.dev/cancel-requested"
+
+	// AnnotationRequeueAfter records the controller's check interval for an
+	// in-flight PipelineRun before jitter (e.g. "8s"). Changes to it don't
+	// trigger a reconcile.
+	AnnotationRequeueAfter = "c8s.dev/requeue-after"
+
+	// AnnotationImportedFrom marks a PipelineRun imported with `c8s run
+	// import` and records the kubeconfig context it was exported from. The
+	// controller does not reconcile imported runs.
+	AnnotationImportedFrom = "c8s.dev/imported-from"
+
 	// Finalizer names
 	FinalizerPipelineRun = "c8s.dev/pipelinerun"
 	FinalizerCleanupJobs = "c8s.dev/cleanup-jobs"
@@ -41,9 +79,25 @@ const (
 	// Managed by value
 	ManagedByC8S = "c8s"
 
+	// LabelManagedValue is the value of LabelManaged on c8s-managed resources
+	LabelManagedValue = "true"
+
+	// CreatedByTest is the value of LabelCreatedBy on runs created by
+	// `c8s dev test run`
+	CreatedByTest = "c8s-test"
+
 	// Job configuration
 	JobTTLSecondsAfterFinished = 3600 // 1 hour
 	JobBackoffLimit            = 0    // No retries at Job level (handled by RetryPolicy)
+	JobNameMaxLength           = 63   // Job names are copied into the job-name Pod label
+
+	// PprofPort is the port the controller serves net/http/pprof profiles on
+	// when started with --enable-pprof
+	PprofPort = 6060
+
+	// DefaultStepTimeout applies to steps when neither the step nor the
+	// PipelineConfig sets a timeout
+	DefaultStepTimeout = "30m"
 
 	// Container names
 	ContainerNameGitClone = "git-clone"
@@ -65,6 +119,7 @@ const (
 	EnvStepName     = "STEP_NAME"
 	EnvWorkspace    = "WORKSPACE"
 	EnvC8SNamespace = "C8S_NAMESPACE"
+	EnvEnvironment  = "C8S_ENVIRONMENT"
 
 	// Storage configuration
 	StorageBucketEnv        = "C8S_STORAGE_BUCKET"
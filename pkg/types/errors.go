@@ -38,9 +38,21 @@ var (
 	// ErrInvalidYAML indicates the pipeline YAML is malformed
 	ErrInvalidYAML = errors.New("invalid pipeline YAML")
 
+	// ErrUntrustedHost indicates a remote pipeline URL's host is not allowed
+	ErrUntrustedHost = errors.New("untrusted host")
+
 	// ErrResourceQuotaExceeded indicates namespace quota was exceeded
 	ErrResourceQuotaExceeded = errors.New("resource quota exceeded")
 
+	// ErrInvalidResourceQuota indicates a resource quota limit can't be parsed
+	ErrInvalidResourceQuota = errors.New("invalid resource quota")
+
+	// ErrInsufficientHeadroom indicates the cluster lacks resources for new Jobs
+	ErrInsufficientHeadroom = errors.New("insufficient cluster headroom")
+
+	// ErrStepExceedsNodeCapacity indicates a step requests more than any node can allocate
+	ErrStepExceedsNodeCapacity = errors.New("step exceeds node capacity")
+
 	// ErrStorageUploadFailed indicates log/artifact upload failed
 	ErrStorageUploadFailed = errors.New("storage upload failed")
 
@@ -56,8 +68,22 @@ var (
 	// ErrAuthenticationFailed indicates Git authentication failed
 	ErrAuthenticationFailed = errors.New("authentication failed")
 
+	// ErrTimeoutExceeded indicates an operation didn't complete within its timeout
+	ErrTimeoutExceeded = errors.New("timeout exceeded")
+
 	// ErrTimeout indicates an operation timed out
-	ErrTimeout = errors.New("operation timed out")
+	//
+	// Deprecated: use ErrTimeoutExceeded, which it is an alias of.
+	ErrTimeout = ErrTimeoutExceeded
+
+	// ErrClusterNotFound indicates a local cluster doesn't exist
+	ErrClusterNotFound = errors.New("cluster not found")
+
+	// ErrClusterAlreadyExists indicates a local cluster to create already exists
+	ErrClusterAlreadyExists = errors.New("cluster already exists")
+
+	// ErrDockerNotAvailable indicates the Docker daemon can't be reached
+	ErrDockerNotAvailable = errors.New("docker not available")
 )
 
 // PipelineError wraps errors with pipeline run context
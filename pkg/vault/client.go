@@ -0,0 +1,275 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vault provides a minimal HashiCorp Vault client for reading secrets
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Environment variables read by ConfigFromEnv
+	EnvAddress  = "VAULT_ADDR"
+	EnvToken    = "VAULT_TOKEN"
+	EnvRole     = "VAULT_ROLE"
+	EnvAuthPath = "VAULT_AUTH_PATH"
+
+	// DefaultAuthPath is the mount path of the Kubernetes auth method
+	DefaultAuthPath = "kubernetes"
+
+	// DefaultServiceAccountTokenPath is where Kubernetes mounts the pod's service account JWT
+	DefaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// Config holds configuration for the Vault client
+type Config struct {
+	// Address is the Vault server URL (e.g., "https://vault.example.com:8200")
+	Address string
+
+	// Token is a static Vault token. If empty, Kubernetes auth is used.
+	Token string
+
+	// Role is the Vault role used for Kubernetes auth
+	Role string
+
+	// AuthPath is the mount path of the Kubernetes auth method (default "kubernetes")
+	AuthPath string
+
+	// ServiceAccountTokenPath is the path to the service account JWT used for Kubernetes auth
+	ServiceAccountTokenPath string
+
+	// Timeout is the HTTP request timeout (default 10s)
+	Timeout time.Duration
+}
+
+// ConfigFromEnv builds a Config from VAULT_ADDR, VAULT_TOKEN, VAULT_ROLE and VAULT_AUTH_PATH
+func ConfigFromEnv() *Config {
+	return &Config{
+		Address:  os.Getenv(EnvAddress),
+		Token:    os.Getenv(EnvToken),
+		Role:     os.Getenv(EnvRole),
+		AuthPath: os.Getenv(EnvAuthPath),
+	}
+}
+
+// Validate validates the Vault configuration
+func (c *Config) Validate() error {
+	if c.Address == "" {
+		return ErrMissingAddress
+	}
+	if c.Token == "" && c.Role == "" {
+		return ErrMissingCredentials
+	}
+	return nil
+}
+
+// Client reads secrets from Vault over its HTTP API
+type Client struct {
+	config     Config
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewClient creates a new Vault client
+func NewClient(config *Config) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	cfg := *config
+	cfg.Address = strings.TrimSuffix(cfg.Address, "/")
+	if cfg.AuthPath == "" {
+		cfg.AuthPath = DefaultAuthPath
+	}
+	if cfg.ServiceAccountTokenPath == "" {
+		cfg.ServiceAccountTokenPath = DefaultServiceAccountTokenPath
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &Client{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		token:      cfg.Token,
+	}, nil
+}
+
+// ReadSecret reads a single key from the secret at path.
+// Both KV v1 ({"data": {...}}) and KV v2 ({"data": {"data": {...}}}) responses are supported.
+func (c *Client) ReadSecret(ctx context.Context, path string, key string) (string, error) {
+	data, err := c.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s in %s", ErrKeyNotFound, key, path)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrReadFailed, err)
+		}
+		return string(encoded), nil
+	}
+}
+
+// read fetches the secret data at path, logging in again once if the token was rejected
+func (c *Client) read(ctx context.Context, path string) (map[string]interface{}, error) {
+	token, err := c.getToken(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRead(ctx, path, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusForbidden && c.config.Token == "" {
+		resp.Body.Close()
+		if token, err = c.getToken(ctx, true); err != nil {
+			return nil, err
+		}
+		if resp, err = c.doRead(ctx, path, token); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, path)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("%w: %s: status %d: %s", ErrReadFailed, path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("%w: invalid response: %v", ErrReadFailed, err)
+	}
+
+	// KV v2 nests the values under data.data alongside data.metadata
+	if nested, ok := secret.Data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := secret.Data["metadata"]; hasMetadata {
+			return nested, nil
+		}
+	}
+
+	return secret.Data, nil
+}
+
+// doRead issues the GET request for a secret path
+func (c *Client) doRead(ctx context.Context, path string, token string) (*http.Response, error) {
+	url := fmt.Sprintf("%s/v1/%s", c.config.Address, strings.TrimPrefix(path, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadFailed, err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadFailed, err)
+	}
+	return resp, nil
+}
+
+// getToken returns the cached token, logging in with Kubernetes auth when needed
+func (c *Client) getToken(ctx context.Context, refresh bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && !refresh {
+		return c.token, nil
+	}
+
+	token, err := c.kubernetesLogin(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+	return token, nil
+}
+
+// kubernetesLogin exchanges the pod's service account JWT for a Vault token
+func (c *Client) kubernetesLogin(ctx context.Context) (string, error) {
+	jwt, err := os.ReadFile(c.config.ServiceAccountTokenPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read service account token: %v", ErrAuthenticationFailed, err)
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"role": c.config.Role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrAuthenticationFailed, err)
+	}
+
+	url := fmt.Sprintf("%s/v1/auth/%s/login", c.config.Address, strings.Trim(c.config.AuthPath, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrAuthenticationFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrAuthenticationFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: status %d", ErrAuthenticationFailed, resp.StatusCode)
+	}
+
+	var login struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&login); err != nil {
+		return "", fmt.Errorf("%w: invalid response: %v", ErrAuthenticationFailed, err)
+	}
+	if login.Auth.ClientToken == "" {
+		return "", fmt.Errorf("%w: empty client token", ErrAuthenticationFailed)
+	}
+
+	return login.Auth.ClientToken, nil
+}
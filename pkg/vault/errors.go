@@ -0,0 +1,40 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import "errors"
+
+// Vault client errors
+var (
+	// ErrMissingAddress indicates VAULT_ADDR is not configured
+	ErrMissingAddress = errors.New("vault address is required")
+
+	// ErrMissingCredentials indicates neither a token nor a Kubernetes auth role is configured
+	ErrMissingCredentials = errors.New("vault token or kubernetes auth role is required")
+
+	// ErrAuthenticationFailed indicates login to Vault failed
+	ErrAuthenticationFailed = errors.New("vault authentication failed")
+
+	// ErrSecretNotFound indicates the secret path doesn't exist
+	ErrSecretNotFound = errors.New("vault secret not found")
+
+	// ErrKeyNotFound indicates the key doesn't exist in the secret
+	ErrKeyNotFound = errors.New("key not found in vault secret")
+
+	// ErrReadFailed indicates reading a secret failed
+	ErrReadFailed = errors.New("failed to read vault secret")
+)
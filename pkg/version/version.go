@@ -0,0 +1,148 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version holds the build version of the c8s binaries and helpers
+// to compare it with the versions deployed to a cluster
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+)
+
+// DevVersion is the version of binaries built without -ldflags
+const DevVersion = "dev"
+
+// DefaultReleasesURL is the GitHub API endpoint of the latest c8s release
+const DefaultReleasesURL = "https://api.github.com/repos/org/c8s/releases/latest"
+
+// Version is the build version, injected with
+// -ldflags "-X github.com/org/c8s/pkg/version.Version=v0.3.0"
+var Version = DevVersion
+
+// SupportedAPIVersions are the c8s.dev CRD versions this build understands
+var SupportedAPIVersions = []string{c8sv1alpha1.GroupVersion.Version}
+
+// Semver is a parsed major.minor.patch version
+type Semver struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// String returns the version in vMAJOR.MINOR.PATCH form
+func (s Semver) String() string {
+	return fmt.Sprintf("v%d.%d.%d", s.Major, s.Minor, s.Patch)
+}
+
+// Less reports whether s is an older version than other
+func (s Semver) Less(other Semver) bool {
+	if s.Major != other.Major {
+		return s.Major < other.Major
+	}
+	if s.Minor != other.Minor {
+		return s.Minor < other.Minor
+	}
+	return s.Patch < other.Patch
+}
+
+// Parse parses a version such as "v0.3.1", "0.3" or the git describe output
+// "v0.3.1-4-gabc1234-dirty". Pre-release and build suffixes are ignored.
+func Parse(v string) (Semver, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Semver{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR[.PATCH]", v)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Semver{}, fmt.Errorf("invalid version %q: %q is not a number", v, part)
+		}
+		numbers[i] = n
+	}
+
+	return Semver{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// Compatible reports whether two versions share the same major and minor version
+func Compatible(a, b string) (bool, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return false, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return false, err
+	}
+	return va.Major == vb.Major && va.Minor == vb.Minor, nil
+}
+
+// IsSupportedAPIVersion reports whether this build understands a CRD version
+func IsSupportedAPIVersion(apiVersion string) bool {
+	for _, supported := range SupportedAPIVersions {
+		if supported == apiVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// Release is a published c8s release
+type Release struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+}
+
+// LatestRelease fetches the latest release from a GitHub releases API URL
+func LatestRelease(ctx context.Context, client *http.Client, url string) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query releases: unexpected status %s", resp.Status)
+	}
+
+	release := &Release{}
+	if err := json.NewDecoder(resp.Body).Decode(release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("release has no tag")
+	}
+	return release, nil
+}
@@ -130,6 +130,28 @@ func (aw *AdmissionWebhook) validatePipelineConfig(ctx context.Context, req *adm
 
 	logger.Info("validating PipelineConfig", "name", pipelineConfig.Name, "namespace", pipelineConfig.Namespace)
 
+	if err := validatePriority(pipelineConfig.Spec.Priority); err != nil {
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  "Failure",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			},
+		}
+	}
+
+	if err := validateEnvironment(pipelineConfig.Spec.Environment); err != nil {
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  "Failure",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			},
+		}
+	}
+
 	// Validate secret references
 	if err := aw.validator.ValidatePipelineConfig(ctx, pipelineConfig); err != nil {
 		logger.Info("PipelineConfig validation failed", "name", pipelineConfig.Name, "error", err.Error())
@@ -21,18 +21,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/types"
 )
 
 // WebhookEvent represents a normalized webhook event from any provider
 type WebhookEvent struct {
 	Repository    string
 	RepositoryURL string
+
+	// RepositorySSHURL is matched against RepositoryConnections when
+	// RepositoryURL has no match
+	RepositorySSHURL string
+
 	Commit        string
 	Branch        string
 	Author        string
@@ -46,13 +54,13 @@ type Handler interface {
 	Handle(w http.ResponseWriter, r *http.Request)
 }
 
-// createPipelineRun creates a PipelineRun CRD from a webhook event
+// createPipelineRun creates a PipelineRun CRD from a webhook event and returns its name
 func createPipelineRun(
 	ctx context.Context,
 	k8sClient client.Client,
 	event *WebhookEvent,
 	repoConn *c8sv1alpha1.RepositoryConnection,
-) error {
+) (string, error) {
 	logger := log.FromContext(ctx)
 
 	// Generate PipelineRun name
@@ -83,6 +91,7 @@ func createPipelineRun(
 			TriggeredAt:       &event.Timestamp,
 			CommitMessage:     event.CommitMessage,
 			Author:            event.Author,
+			Environment:       namespaceEnvironment(ctx, k8sClient, repoConn.Namespace),
 		},
 	}
 
@@ -98,12 +107,12 @@ func createPipelineRun(
 			"name", runName,
 			"namespace", repoConn.Namespace,
 		)
-		return nil
+		return runName, nil
 	}
 
 	// Create new PipelineRun
 	if err := k8sClient.Create(ctx, pipelineRun); err != nil {
-		return fmt.Errorf("failed to create PipelineRun: %w", err)
+		return "", fmt.Errorf("failed to create PipelineRun: %w", err)
 	}
 
 	logger.Info("Created PipelineRun",
@@ -113,7 +122,25 @@ func createPipelineRun(
 		"branch", event.Branch,
 	)
 
-	return nil
+	return runName, nil
+}
+
+// namespaceEnvironment returns the environment set by the c8s.dev/environment
+// label of a namespace. Unlabeled namespaces and invalid values return an
+// empty environment, so the PipelineConfig default applies.
+func namespaceEnvironment(ctx context.Context, k8sClient client.Client, namespace string) string {
+	ns := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		log.FromContext(ctx).Info("Could not read namespace environment", "namespace", namespace, "error", err.Error())
+		return ""
+	}
+
+	environment := ns.Labels[types.LabelEnvironment]
+	if !c8sv1alpha1.IsValidEnvironment(environment) {
+		log.FromContext(ctx).Info("Ignoring invalid namespace environment", "namespace", namespace, "environment", environment)
+		return ""
+	}
+	return environment
 }
 
 // findRepositoryConnection finds a RepositoryConnection by repository URL
@@ -161,3 +188,20 @@ func writeSuccessResponse(w http.ResponseWriter, message string) {
 		"message": message,
 	})
 }
+
+// validatePriority checks that a priority is one of low, normal, high or critical
+func validatePriority(priority string) error {
+	if !c8sv1alpha1.IsValidPriority(priority) {
+		return fmt.Errorf("invalid priority %q: must be one of %s", priority, strings.Join(c8sv1alpha1.ValidPriorities, ", "))
+	}
+	return nil
+}
+
+// validateEnvironment checks that an environment is one of development,
+// staging or production
+func validateEnvironment(environment string) error {
+	if !c8sv1alpha1.IsValidEnvironment(environment) {
+		return fmt.Errorf("invalid environment %q: must be one of %s", environment, strings.Join(c8sv1alpha1.ValidEnvironments, ", "))
+	}
+	return nil
+}
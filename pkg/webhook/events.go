@@ -0,0 +1,195 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultEventHistory is the default number of webhook events kept by an EventStore
+const DefaultEventHistory = 100
+
+// EventOutcome describes how a webhook event was handled
+type EventOutcome string
+
+const (
+	// OutcomeAccepted means a PipelineRun was created (or already existed)
+	OutcomeAccepted EventOutcome = "accepted"
+
+	// OutcomeIgnored means the event type or payload does not trigger runs
+	OutcomeIgnored EventOutcome = "ignored"
+
+	// OutcomeRejected means the request was invalid, unauthorized or did not
+	// match a RepositoryConnection
+	OutcomeRejected EventOutcome = "rejected"
+
+	// OutcomeFailed means creating the PipelineRun failed
+	OutcomeFailed EventOutcome = "failed"
+)
+
+// RecordedEvent is a webhook event kept by an EventStore
+type RecordedEvent struct {
+	ID         int64        `json:"id"`
+	ReceivedAt time.Time    `json:"receivedAt"`
+	Provider   string       `json:"provider"`
+	EventType  string       `json:"eventType"`
+	Outcome    EventOutcome `json:"outcome"`
+	RunName    string       `json:"runName,omitempty"`
+
+	// Payload is the raw request body, only included in event details
+	Payload string `json:"payload,omitempty"`
+}
+
+// EventFilter selects events listed by an EventStore
+type EventFilter struct {
+	Provider string
+	Outcome  EventOutcome
+
+	// Limit is the maximum number of events returned (0 for no limit)
+	Limit int
+}
+
+// EventStore keeps the most recent webhook events in a fixed-size circular
+// buffer so that delivery issues can be debugged from the service itself.
+// A nil EventStore records nothing.
+type EventStore struct {
+	mu     sync.RWMutex
+	events []RecordedEvent
+	next   int
+	count  int
+	lastID int64
+}
+
+// NewEventStore creates an EventStore keeping up to capacity events
+// (DefaultEventHistory if capacity is not positive)
+func NewEventStore(capacity int) *EventStore {
+	if capacity <= 0 {
+		capacity = DefaultEventHistory
+	}
+	return &EventStore{events: make([]RecordedEvent, capacity)}
+}
+
+// Record stores a handled webhook event, evicting the oldest event when full
+func (s *EventStore) Record(provider, eventType string, payload []byte, outcome EventOutcome, runName string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	s.events[s.next] = RecordedEvent{
+		ID:         s.lastID,
+		ReceivedAt: time.Now(),
+		Provider:   provider,
+		EventType:  eventType,
+		Outcome:    outcome,
+		RunName:    runName,
+		Payload:    string(payload),
+	}
+	s.next = (s.next + 1) % len(s.events)
+	if s.count < len(s.events) {
+		s.count++
+	}
+}
+
+// List returns the events matching filter, newest first, without payloads
+func (s *EventStore) List(filter EventFilter) []RecordedEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := []RecordedEvent{}
+	for i := 1; i <= s.count; i++ {
+		event := s.events[(s.next-i+len(s.events))%len(s.events)]
+		if filter.Provider != "" && event.Provider != filter.Provider {
+			continue
+		}
+		if filter.Outcome != "" && event.Outcome != filter.Outcome {
+			continue
+		}
+
+		event.Payload = ""
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) == filter.Limit {
+			break
+		}
+	}
+	return events
+}
+
+// Get returns the event with the given ID if it is still stored
+func (s *EventStore) Get(id int64) (RecordedEvent, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := 0; i < s.count; i++ {
+		if event := s.events[i]; event.ID == id {
+			return event, true
+		}
+	}
+	return RecordedEvent{}, false
+}
+
+// HandleEvents lists stored events, filtered by the provider, outcome and
+// limit query parameters
+func (s *EventStore) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := EventFilter{
+		Provider: query.Get("provider"),
+		Outcome:  EventOutcome(query.Get("outcome")),
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeErrorResponse(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		filter.Limit = n
+	}
+
+	writeJSONResponse(w, http.StatusOK, s.List(filter))
+}
+
+// HandleEvent returns a stored event including its raw payload
+func (s *EventStore) HandleEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "event id must be an integer")
+		return
+	}
+
+	event, ok := s.Get(id)
+	if !ok {
+		writeErrorResponse(w, http.StatusNotFound, "event not found")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, event)
+}
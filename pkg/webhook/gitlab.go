@@ -17,28 +17,24 @@ limitations under the License.
 package webhook
 
 import (
-	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
-
-	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
 )
 
 // GitLabHandler handles GitLab webhook events
 type GitLabHandler struct {
-	client client.Client
+	*ProviderHandler
 }
 
 // NewGitLabHandler creates a new GitLab webhook handler
 func NewGitLabHandler(c client.Client) *GitLabHandler {
-	return &GitLabHandler{client: c}
+	return &GitLabHandler{NewProviderHandler(c, ProviderGitLab, &GitLabValidator{})}
 }
 
 // GitLabPushEvent represents a GitLab push webhook event
@@ -65,150 +61,68 @@ type GitLabPushEvent struct {
 	UserEmail string `json:"user_email"`
 }
 
-// Handle processes GitLab webhook requests
-func (h *GitLabHandler) Handle(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	logger := log.FromContext(ctx).WithValues("provider", "gitlab")
-
-	// Only accept POST requests
-	if r.Method != http.MethodPost {
-		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
-		return
-	}
+// GitLabValidator validates GitLab push webhooks authenticated with a secret token
+type GitLabValidator struct{}
 
-	// Check GitLab event type
+// EventType returns the X-Gitlab-Event header
+func (v *GitLabValidator) EventType(r *http.Request) (string, bool) {
 	eventType := r.Header.Get("X-Gitlab-Event")
-	if eventType != "Push Hook" {
-		logger.Info("Ignoring non-push event", "eventType", eventType)
-		writeSuccessResponse(w, fmt.Sprintf("Event type '%s' ignored", eventType))
-		return
+	return eventType, eventType == "Push Hook"
+}
+
+// ValidateSignature verifies the X-Gitlab-Token header. Requests without a
+// token are accepted.
+func (v *GitLabValidator) ValidateSignature(r *http.Request, body []byte) error {
+	token := r.Header.Get("X-Gitlab-Token")
+	if token == "" {
+		return nil
 	}
 
-	// Read request body
-	body, err := io.ReadAll(r.Body)
+	webhookSecret, err := WebhookSecret(r)
 	if err != nil {
-		logger.Error(err, "Failed to read request body")
-		writeErrorResponse(w, http.StatusBadRequest, "Failed to read request body")
-		return
+		return err
 	}
-	defer r.Body.Close()
 
-	// Parse push event
-	var pushEvent GitLabPushEvent
-	if err := json.Unmarshal(body, &pushEvent); err != nil {
-		logger.Error(err, "Failed to parse push event JSON")
-		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
-		return
+	// Compare tokens
+	if subtle.ConstantTimeCompare([]byte(token), webhookSecret) != 1 {
+		return fmt.Errorf("token mismatch")
 	}
 
-	// Extract branch name from ref (refs/heads/main -> main)
-	branch := pushEvent.Ref[len("refs/heads/"):]
-
-	logger.Info("Received GitLab push event",
-		"project", pushEvent.Project.PathWithNamespace,
-		"branch", branch,
-		"commit", pushEvent.After[:8],
-	)
+	return nil
+}
 
-	// Find RepositoryConnection for this repository
-	namespace := "default"
-	repoConn, err := findRepositoryConnection(ctx, h.client, pushEvent.Project.GitHTTPURL, namespace)
-	if err != nil {
-		repoConn, err = findRepositoryConnection(ctx, h.client, pushEvent.Project.GitSSHURL, namespace)
-		if err != nil {
-			logger.Info("No RepositoryConnection found for project",
-				"project", pushEvent.Project.PathWithNamespace,
-			)
-			writeErrorResponse(w, http.StatusNotFound,
-				fmt.Sprintf("No configuration found for project: %s", pushEvent.Project.PathWithNamespace))
-			return
-		}
+// ParseEvent parses a GitLab push event, taking the commit details from the
+// most recent commit
+func (v *GitLabValidator) ParseEvent(body []byte) (*WebhookEvent, error) {
+	var pushEvent GitLabPushEvent
+	if err := json.Unmarshal(body, &pushEvent); err != nil {
+		return nil, fmt.Errorf("invalid JSON payload: %w", err)
 	}
-
-	// Verify webhook token
-	token := r.Header.Get("X-Gitlab-Token")
-	if token != "" {
-		if err := h.verifyToken(ctx, token, repoConn); err != nil {
-			logger.Error(err, "Webhook token verification failed")
-			writeErrorResponse(w, http.StatusUnauthorized, "Invalid webhook token")
-			return
-		}
-		logger.Info("Webhook token verified successfully")
+	if pushEvent.After == "" {
+		return nil, fmt.Errorf("push event has no commit")
 	}
 
-	// Get most recent commit
-	commitMsg := ""
-	commitAuthor := pushEvent.UserName
-	commitEmail := pushEvent.UserEmail
-	commitTimestamp := metav1.Now()
+	event := &WebhookEvent{
+		Repository:       pushEvent.Project.PathWithNamespace,
+		RepositoryURL:    pushEvent.Project.GitHTTPURL,
+		RepositorySSHURL: pushEvent.Project.GitSSHURL,
+		Commit:           pushEvent.After,
+		// Extract branch name from ref (refs/heads/main -> main)
+		Branch:      strings.TrimPrefix(pushEvent.Ref, "refs/heads/"),
+		Author:      pushEvent.UserName,
+		AuthorEmail: pushEvent.UserEmail,
+		Timestamp:   metav1.Now(),
+	}
 
 	if len(pushEvent.Commits) > 0 {
 		lastCommit := pushEvent.Commits[len(pushEvent.Commits)-1]
-		commitMsg = lastCommit.Message
-		commitAuthor = lastCommit.Author.Name
-		commitEmail = lastCommit.Author.Email
+		event.CommitMessage = lastCommit.Message
+		event.Author = lastCommit.Author.Name
+		event.AuthorEmail = lastCommit.Author.Email
 		if t, err := parseTimestamp(lastCommit.Timestamp); err == nil {
-			commitTimestamp = t
+			event.Timestamp = t
 		}
 	}
 
-	// Create normalized webhook event
-	event := &WebhookEvent{
-		Repository:    pushEvent.Project.PathWithNamespace,
-		RepositoryURL: pushEvent.Project.GitHTTPURL,
-		Commit:        pushEvent.After,
-		Branch:        branch,
-		Author:        commitAuthor,
-		AuthorEmail:   commitEmail,
-		CommitMessage: commitMsg,
-		Timestamp:     commitTimestamp,
-	}
-
-	// Create PipelineRun
-	if err := createPipelineRun(ctx, h.client, event, repoConn); err != nil {
-		logger.Error(err, "Failed to create PipelineRun")
-		writeErrorResponse(w, http.StatusInternalServerError, "Failed to create pipeline run")
-		return
-	}
-
-	// Return success
-	writeSuccessResponse(w, "Pipeline run created successfully")
-}
-
-// verifyToken verifies the GitLab webhook token
-func (h *GitLabHandler) verifyToken(
-	ctx context.Context,
-	token string,
-	repoConn *c8sv1alpha1.RepositoryConnection,
-) error {
-	logger := log.FromContext(ctx)
-
-	// Get webhook secret from Kubernetes Secret
-	if repoConn.Spec.WebhookSecretRef == "" {
-		return fmt.Errorf("no webhook secret configured for repository connection")
-	}
-
-	secret := &corev1.Secret{}
-	secretKey := client.ObjectKey{
-		Name:      repoConn.Spec.WebhookSecretRef,
-		Namespace: repoConn.Namespace,
-	}
-
-	if err := h.client.Get(ctx, secretKey, secret); err != nil {
-		logger.Error(err, "Failed to get webhook secret", "secret", repoConn.Spec.WebhookSecretRef)
-		return fmt.Errorf("failed to get webhook secret: %w", err)
-	}
-
-	// Get secret value (default key is "webhook-secret")
-	webhookSecret, ok := secret.Data["webhook-secret"]
-	if !ok {
-		return fmt.Errorf("webhook secret key 'webhook-secret' not found")
-	}
-
-	// Compare tokens
-	if token != string(webhookSecret) {
-		return fmt.Errorf("token mismatch")
-	}
-
-	return nil
+	return event, nil
 }
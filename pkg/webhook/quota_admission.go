@@ -50,6 +50,26 @@ func (w *QuotaAdmissionWebhook) Handle(ctx context.Context, req admissionv1.Admi
 		}
 	}
 
+	if err := validatePriority(pipelineRun.Spec.Priority); err != nil {
+		return admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	if err := validateEnvironment(pipelineRun.Spec.Environment); err != nil {
+		return admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			},
+		}
+	}
+
 	// Fetch PipelineConfig to get step details
 	var pipelineConfig v1alpha1.PipelineConfig
 	configKey := client.ObjectKey{
@@ -0,0 +1,218 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+)
+
+// Supported webhook providers
+const (
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+	ProviderBitbucket = "bitbucket"
+)
+
+// TestPushEvent describes a synthetic push used to exercise the webhook
+// handlers without a real git provider
+type TestPushEvent struct {
+	// Repository is the repository path (e.g., "owner/repo")
+	Repository string
+
+	// RepositoryURL is the clone URL matched against RepositoryConnections.
+	// Defaults to the provider's HTTPS clone URL for Repository.
+	RepositoryURL string
+
+	Branch      string
+	Commit      string
+	Message     string
+	Author      string
+	AuthorEmail string
+	Timestamp   time.Time
+}
+
+// DefaultTestRepositoryURL returns the HTTPS clone URL a provider uses for repository
+func DefaultTestRepositoryURL(provider, repository string) string {
+	switch provider {
+	case ProviderGitLab:
+		return fmt.Sprintf("https://gitlab.com/%s.git", repository)
+	case ProviderBitbucket:
+		return fmt.Sprintf("https://bitbucket.org/%s.git", repository)
+	default:
+		return fmt.Sprintf("https://github.com/%s.git", repository)
+	}
+}
+
+// BuildTestPushPayload marshals event into the push payload format of provider
+func BuildTestPushPayload(provider string, event TestPushEvent) ([]byte, error) {
+	if len(event.Commit) < 8 {
+		return nil, fmt.Errorf("commit SHA must be at least 8 characters")
+	}
+	if event.RepositoryURL == "" {
+		event.RepositoryURL = DefaultTestRepositoryURL(provider, event.Repository)
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	timestamp := event.Timestamp.UTC().Format(time.RFC3339)
+	name := path.Base(event.Repository)
+
+	switch provider {
+	case ProviderGitHub:
+		var payload GitHubPushEvent
+		payload.Ref = "refs/heads/" + event.Branch
+		payload.After = event.Commit
+		payload.Repository.Name = name
+		payload.Repository.FullName = event.Repository
+		payload.Repository.CloneURL = event.RepositoryURL
+		payload.Repository.SSHURL = fmt.Sprintf("git@github.com:%s.git", event.Repository)
+		payload.Repository.HTMLURL = fmt.Sprintf("https://github.com/%s", event.Repository)
+		payload.HeadCommit.ID = event.Commit
+		payload.HeadCommit.Message = event.Message
+		payload.HeadCommit.Timestamp = timestamp
+		payload.HeadCommit.Author.Name = event.Author
+		payload.HeadCommit.Author.Email = event.AuthorEmail
+		payload.HeadCommit.Author.Username = event.Author
+		payload.Pusher.Name = event.Author
+		payload.Pusher.Email = event.AuthorEmail
+		return json.Marshal(payload)
+
+	case ProviderGitLab:
+		var payload GitLabPushEvent
+		payload.ObjectKind = "push"
+		payload.Ref = "refs/heads/" + event.Branch
+		payload.After = event.Commit
+		payload.Project.Name = name
+		payload.Project.PathWithNamespace = event.Repository
+		payload.Project.GitHTTPURL = event.RepositoryURL
+		payload.Project.GitSSHURL = fmt.Sprintf("git@gitlab.com:%s.git", event.Repository)
+		payload.UserName = event.Author
+		payload.UserEmail = event.AuthorEmail
+		payload.Commits = make([]struct {
+			ID        string `json:"id"`
+			Message   string `json:"message"`
+			Timestamp string `json:"timestamp"`
+			Author    struct {
+				Name  string `json:"name"`
+				Email string `json:"email"`
+			} `json:"author"`
+		}, 1)
+		payload.Commits[0].ID = event.Commit
+		payload.Commits[0].Message = event.Message
+		payload.Commits[0].Timestamp = timestamp
+		payload.Commits[0].Author.Name = event.Author
+		payload.Commits[0].Author.Email = event.AuthorEmail
+		return json.Marshal(payload)
+
+	case ProviderBitbucket:
+		var payload BitbucketPushEvent
+		payload.Push.Changes = make([]struct {
+			New struct {
+				Type   string `json:"type"`
+				Name   string `json:"name"`
+				Target struct {
+					Hash    string `json:"hash"`
+					Message string `json:"message"`
+					Date    string `json:"date"`
+					Author  struct {
+						User struct {
+							DisplayName string `json:"display_name"`
+							Email       string `json:"email_address"`
+						} `json:"user"`
+					} `json:"author"`
+				} `json:"target"`
+			} `json:"new"`
+		}, 1)
+		change := &payload.Push.Changes[0].New
+		change.Type = "branch"
+		change.Name = event.Branch
+		change.Target.Hash = event.Commit
+		change.Target.Message = event.Message
+		change.Target.Date = timestamp
+		change.Target.Author.User.DisplayName = event.Author
+		change.Target.Author.User.Email = event.AuthorEmail
+		payload.Repository.Name = name
+		payload.Repository.FullName = event.Repository
+		payload.Repository.Links.HTML.Href = fmt.Sprintf("https://bitbucket.org/%s", event.Repository)
+		payload.Repository.Links.Clone = []struct {
+			Name string `json:"name"`
+			Href string `json:"href"`
+		}{
+			{Name: "https", Href: event.RepositoryURL},
+			{Name: "ssh", Href: fmt.Sprintf("git@bitbucket.org:%s.git", event.Repository)},
+		}
+		payload.Actor.DisplayName = event.Author
+		payload.Actor.Email = event.AuthorEmail
+		return json.Marshal(payload)
+
+	default:
+		return nil, fmt.Errorf("unsupported provider %q (must be github, gitlab, or bitbucket)", provider)
+	}
+}
+
+// NewTestPushRequest builds a POST request delivering event to a provider's
+// webhook endpoint at url. If secret is set the request is signed the way the
+// provider signs deliveries: an HMAC-SHA256 signature for GitHub and Bitbucket
+// and a token header for GitLab.
+func NewTestPushRequest(ctx context.Context, url, provider string, event TestPushEvent, secret string) (*http.Request, error) {
+	payload, err := BuildTestPushPayload(provider, event)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	switch provider {
+	case ProviderGitHub:
+		req.Header.Set("X-GitHub-Event", "push")
+		if secret != "" {
+			req.Header.Set("X-Hub-Signature-256", "sha256="+signPayload(payload, secret))
+		}
+	case ProviderGitLab:
+		req.Header.Set("X-Gitlab-Event", "Push Hook")
+		if secret != "" {
+			req.Header.Set("X-Gitlab-Token", secret)
+		}
+	case ProviderBitbucket:
+		req.Header.Set("X-Event-Key", "repo:push")
+		if secret != "" {
+			req.Header.Set("X-Hub-Signature", "sha256="+signPayload(payload, secret))
+		}
+	}
+
+	return req, nil
+}
+
+// signPayload returns the hex-encoded HMAC-SHA256 of payload
+func signPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/metrics"
+)
+
+// ErrEventIgnored is returned (wrapped) by ParseEvent for payloads that do
+// not trigger a pipeline, such as a push without changes
+var ErrEventIgnored = errors.New("event ignored")
+
+// PayloadValidator authenticates and parses the webhook payloads of a Git provider
+type PayloadValidator interface {
+	// ValidateSignature verifies the request was sent by the provider. The
+	// webhook secret of the matched RepositoryConnection is available through
+	// WebhookSecret(r).
+	ValidateSignature(r *http.Request, body []byte) error
+
+	// ParseEvent converts a push payload into a normalized WebhookEvent
+	ParseEvent(body []byte) (*WebhookEvent, error)
+}
+
+// EventTypeReader is implemented by validators whose provider sends the event
+// type in a header. Events other than pushes are ignored without being parsed.
+type EventTypeReader interface {
+	EventType(r *http.Request) (eventType string, push bool)
+}
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[string]PayloadValidator{
+		ProviderGitHub:    &GitHubValidator{},
+		ProviderGitLab:    &GitLabValidator{},
+		ProviderBitbucket: &BitbucketValidator{},
+	}
+)
+
+// RegisterValidator registers the validator of a provider, replacing any
+// previous one. The webhook service serves every registered provider at
+// /webhooks/{provider}.
+func RegisterValidator(provider string, validator PayloadValidator) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[provider] = validator
+}
+
+// LookupValidator returns the validator registered for a provider
+func LookupValidator(provider string) (PayloadValidator, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	validator, ok := validators[provider]
+	return validator, ok
+}
+
+// RegisteredProviders returns the sorted names of the providers with a validator
+func RegisteredProviders() []string {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+
+	providers := make([]string, 0, len(validators))
+	for provider := range validators {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// secretLookupKey is the context key of the webhook secret lookup
+type secretLookupKey struct{}
+
+// WithSecretLookup returns a context whose WebhookSecret is read by lookup.
+// The secret is only fetched if a validator asks for it.
+func WithSecretLookup(ctx context.Context, lookup func() ([]byte, error)) context.Context {
+	return context.WithValue(ctx, secretLookupKey{}, lookup)
+}
+
+// WebhookSecret returns the webhook secret of the RepositoryConnection a
+// request was matched to
+func WebhookSecret(r *http.Request) ([]byte, error) {
+	lookup, ok := r.Context().Value(secretLookupKey{}).(func() ([]byte, error))
+	if !ok {
+		return nil, fmt.Errorf("no webhook secret available for request")
+	}
+	return lookup()
+}
+
+// ProviderHandler creates PipelineRuns from the push events of a provider,
+// using its PayloadValidator to authenticate and parse requests
+type ProviderHandler struct {
+	client    client.Client
+	events    *EventStore
+	provider  string
+	validator PayloadValidator
+}
+
+// NewProviderHandler creates a webhook handler for a provider
+func NewProviderHandler(c client.Client, provider string, validator PayloadValidator) *ProviderHandler {
+	return &ProviderHandler{client: c, provider: provider, validator: validator}
+}
+
+// SetEventStore records handled events in store
+func (h *ProviderHandler) SetEventStore(store *EventStore) {
+	h.events = store
+}
+
+// Handle processes webhook requests
+func (h *ProviderHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	logger := log.FromContext(ctx).WithValues("provider", h.provider)
+
+	// Only accept POST requests
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	eventType, push := "", true
+	if reader, ok := h.validator.(EventTypeReader); ok {
+		eventType, push = reader.EventType(r)
+	}
+
+	// Record the event once it has been handled
+	var body []byte
+	outcome, runName := OutcomeRejected, ""
+	defer func() {
+		h.events.Record(h.provider, eventType, body, outcome, runName)
+		metrics.RecordWebhookEvent(h.provider, string(outcome))
+	}()
+
+	if !push {
+		logger.Info("Ignoring non-push event", "eventType", eventType)
+		outcome = OutcomeIgnored
+		writeSuccessResponse(w, fmt.Sprintf("Event type '%s' ignored", eventType))
+		return
+	}
+
+	// Read request body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Error(err, "Failed to read request body")
+		writeErrorResponse(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	// Parse push event
+	event, err := h.validator.ParseEvent(body)
+	if errors.Is(err, ErrEventIgnored) {
+		logger.Info("Ignoring push event", "reason", err.Error())
+		outcome = OutcomeIgnored
+		writeSuccessResponse(w, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error(err, "Failed to parse push event")
+		writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid payload: %v", err))
+		return
+	}
+
+	logger.Info("Received push event",
+		"repository", event.Repository,
+		"branch", event.Branch,
+		"commit", event.Commit,
+	)
+
+	// Find RepositoryConnection for this repository
+	// Note: Using default namespace for now. In production, this would be configurable
+	namespace := "default"
+	repoConn, err := findRepositoryConnection(ctx, h.client, event.RepositoryURL, namespace)
+	if err != nil && event.RepositorySSHURL != "" {
+		// Try SSH URL as well
+		repoConn, err = findRepositoryConnection(ctx, h.client, event.RepositorySSHURL, namespace)
+	}
+	if err != nil {
+		logger.Info("No RepositoryConnection found for repository",
+			"repository", event.Repository,
+			"url", event.RepositoryURL,
+			"sshURL", event.RepositorySSHURL,
+		)
+		writeErrorResponse(w, http.StatusNotFound,
+			fmt.Sprintf("No configuration found for repository: %s", event.Repository))
+		return
+	}
+
+	// Verify the request with the secret of the matched RepositoryConnection
+	r = r.WithContext(WithSecretLookup(r.Context(), func() ([]byte, error) {
+		return getWebhookSecret(ctx, h.client, repoConn)
+	}))
+	if err := h.validator.ValidateSignature(r, body); err != nil {
+		logger.Error(err, "Webhook signature verification failed")
+		writeErrorResponse(w, http.StatusUnauthorized, "Invalid webhook signature")
+		return
+	}
+
+	// Create PipelineRun
+	runName, err = createPipelineRun(ctx, h.client, event, repoConn)
+	if err != nil {
+		logger.Error(err, "Failed to create PipelineRun")
+		outcome = OutcomeFailed
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to create pipeline run")
+		return
+	}
+	outcome = OutcomeAccepted
+
+	// Return success
+	writeSuccessResponse(w, "Pipeline run created successfully")
+}
+
+// getWebhookSecret reads the webhook secret referenced by a RepositoryConnection
+func getWebhookSecret(
+	ctx context.Context,
+	k8sClient client.Client,
+	repoConn *c8sv1alpha1.RepositoryConnection,
+) ([]byte, error) {
+	logger := log.FromContext(ctx)
+
+	// Get webhook secret from Kubernetes Secret
+	if repoConn.Spec.WebhookSecretRef == "" {
+		return nil, fmt.Errorf("no webhook secret configured for repository connection")
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := client.ObjectKey{
+		Name:      repoConn.Spec.WebhookSecretRef,
+		Namespace: repoConn.Namespace,
+	}
+
+	if err := k8sClient.Get(ctx, secretKey, secret); err != nil {
+		logger.Error(err, "Failed to get webhook secret", "secret", repoConn.Spec.WebhookSecretRef)
+		return nil, fmt.Errorf("failed to get webhook secret: %w", err)
+	}
+
+	// Get secret value (default key is "webhook-secret")
+	webhookSecret, ok := secret.Data["webhook-secret"]
+	if !ok {
+		return nil, fmt.Errorf("webhook secret key 'webhook-secret' not found")
+	}
+	return webhookSecret, nil
+}
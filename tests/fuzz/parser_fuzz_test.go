@@ -0,0 +1,78 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fuzz contains fuzz tests for code that handles untrusted input.
+// Run them with 'c8s dev test fuzz' or 'go test -fuzz=FuzzParse ./tests/fuzz'.
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/org/c8s/pkg/parser"
+)
+
+// parseSeeds are valid and nearly valid pipelines the fuzzer mutates, in
+// addition to the corpus in testdata/fuzz/FuzzParse
+var parseSeeds = []string{
+	`version: v1alpha1
+name: build
+steps:
+  - name: build
+    image: golang:1.25
+    commands: ["go build ./..."]
+`,
+	`version: v1alpha1
+name: deps
+steps:
+  - name: a
+    image: alpine
+    commands: [echo a]
+  - name: b
+    image: alpine
+    commands: [echo b]
+    dependsOn: [a]
+`,
+	`version: v1alpha1
+name: cycle
+steps:
+  - {name: a, image: alpine, commands: [x], dependsOn: [b]}
+  - {name: b, image: alpine, commands: [x], dependsOn: [a]}
+`,
+	"",
+	"---\n",
+	"version: [",
+}
+
+// FuzzParse verifies the parser returns either a spec or an error for any
+// input, without panicking. Pipeline YAML comes from repositories and webhook
+// payloads, so it must be treated as untrusted.
+func FuzzParse(f *testing.F) {
+	for _, seed := range parseSeeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, p := range []*parser.Parser{
+			parser.NewParser(),
+			parser.NewParser(parser.WithStrictMode(), parser.WithSecretDetection()),
+		} {
+			spec, err := p.Parse(data)
+			if (spec == nil) == (err == nil) {
+				t.Fatalf("Parse returned spec %v and error %v; want exactly one", spec, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+var addonsConfigMapResource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func TestAddonRegistry(t *testing.T) {
+	for _, name := range []string{"metrics-server", "ingress-nginx", "cert-manager", "local-path-provisioner"} {
+		addon, err := cluster.LookupAddon(name)
+		require.NoError(t, err, name)
+		assert.NotEmpty(t, addon.Version, name)
+		assert.Contains(t, addon.ManifestURL, addon.Version, "manifest should be pinned to the addon version")
+	}
+
+	_, err := cluster.LookupAddon("traefik")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: cert-manager, ingress-nginx")
+}
+
+func TestAddonTracking(t *testing.T) {
+	ctx := context.Background()
+	client := newBackupClient()
+
+	t.Run("no ConfigMap means nothing installed", func(t *testing.T) {
+		statuses, err := cluster.AddonStatuses(ctx, client)
+		require.NoError(t, err)
+		require.Len(t, statuses, len(cluster.Addons))
+		for _, status := range statuses {
+			assert.False(t, status.Installed, status.Name)
+		}
+	})
+
+	t.Run("record creates then updates the ConfigMap", func(t *testing.T) {
+		require.NoError(t, cluster.RecordAddon(ctx, client, "metrics-server", "v0.7.2"))
+		require.NoError(t, cluster.RecordAddon(ctx, client, "cert-manager", "v1.16.1"))
+
+		configMap, err := client.Resource(addonsConfigMapResource).Namespace(cluster.AddonsNamespace).
+			Get(ctx, cluster.AddonsConfigMap, metav1.GetOptions{})
+		require.NoError(t, err)
+		data, _, _ := unstructured.NestedStringMap(configMap.Object, "data")
+		assert.Equal(t, map[string]string{"metrics-server": "v0.7.2", "cert-manager": "v1.16.1"}, data)
+
+		names, err := cluster.InstalledAddonNames(ctx, client)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cert-manager", "metrics-server"}, names)
+
+		statuses, err := cluster.AddonStatuses(ctx, client)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			installed := status.Name == "metrics-server" || status.Name == "cert-manager"
+			assert.Equal(t, installed, status.Installed, status.Name)
+		}
+	})
+
+	t.Run("forget removes the addon", func(t *testing.T) {
+		require.NoError(t, cluster.ForgetAddon(ctx, client, "metrics-server"))
+
+		names, err := cluster.InstalledAddonNames(ctx, client)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cert-manager"}, names)
+	})
+
+	t.Run("forget without ConfigMap is a no-op", func(t *testing.T) {
+		assert.NoError(t, cluster.ForgetAddon(ctx, newBackupClient(), "metrics-server"))
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/api/middleware"
+)
+
+// readAuditEvents decodes the JSON Lines events of an audit log
+func readAuditEvents(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var events []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		event := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+// auditTestHandler echoes the request body with a 201 for POST and 200 otherwise
+func auditTestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	})
+}
+
+// TestAuditMetadata verifies mutating requests are audited and reads are not
+func TestAuditMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := middleware.NewAuditLogger(path)
+	require.NoError(t, err)
+	handler := middleware.Audit(auditTestHandler(), logger)
+
+	body := `{"metadata":{"name":"build-1"},"spec":{"pipelineConfigRef":"app"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/ci/pipelineruns", strings.NewReader(body))
+	req.RemoteAddr = "10.0.0.7:51234"
+	req = req.WithContext(middleware.WithUser(req.Context(), "alice"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.NotEmpty(t, rec.Header().Get(middleware.RequestIDHeader))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/ci/pipelineruns", nil))
+
+	del := httptest.NewRequest(http.MethodDelete, "/api/v1/namespaces/ci/pipelineconfigs/app", nil)
+	del.Header.Set(middleware.RequestIDHeader, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), del)
+	require.NoError(t, logger.Sync())
+
+	events := readAuditEvents(t, path)
+	require.Len(t, events, 2)
+
+	create := events[0]
+	assert.NotEmpty(t, create["timestamp"])
+	assert.Equal(t, "alice", create["user"])
+	assert.Equal(t, "create", create["verb"])
+	assert.Equal(t, "pipelinerun", create["resource"])
+	assert.Equal(t, "build-1", create["name"])
+	assert.Equal(t, "ci", create["namespace"])
+	assert.Equal(t, rec.Header().Get(middleware.RequestIDHeader), create["request-id"])
+	assert.Equal(t, "10.0.0.7", create["source-ip"])
+	assert.Equal(t, float64(len(body)), create["request-size-bytes"])
+	assert.Equal(t, float64(http.StatusCreated), create["response-code"])
+	assert.NotContains(t, create, "request-object")
+
+	remove := events[1]
+	assert.Equal(t, "system:anonymous", remove["user"])
+	assert.Equal(t, "delete", remove["verb"])
+	assert.Equal(t, "pipelineconfig", remove["resource"])
+	assert.Equal(t, "app", remove["name"])
+	assert.Equal(t, "req-42", remove["request-id"])
+}
+
+// TestAuditPolicyRequestResponse verifies bodies are recorded per policy
+func TestAuditPolicyRequestResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := middleware.NewAuditLogger(path)
+	require.NoError(t, err)
+
+	policy, err := middleware.ParseAuditPolicy("RequestResponse")
+	require.NoError(t, err)
+	handler := middleware.AuditWithPolicy(auditTestHandler(), logger, policy)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/namespaces/ci/pipelineconfigs/app", strings.NewReader(`{"spec":{}}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.NoError(t, logger.Sync())
+
+	events := readAuditEvents(t, path)
+	require.Len(t, events, 1)
+	assert.Equal(t, "update", events[0]["verb"])
+	assert.Equal(t, map[string]interface{}{"spec": map[string]interface{}{}}, events[0]["request-object"])
+	assert.Equal(t, map[string]interface{}{"status": "ok"}, events[0]["response-object"])
+}
+
+// TestAuditPolicyNone verifies the none policy disables auditing
+func TestAuditPolicyNone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := middleware.NewAuditLogger(path)
+	require.NoError(t, err)
+
+	handler := middleware.AuditWithPolicy(auditTestHandler(), logger, middleware.AuditPolicyNone)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/v1/namespaces/ci/pipelineruns/run-1", nil))
+	require.NoError(t, logger.Sync())
+
+	assert.Empty(t, readAuditEvents(t, path))
+
+	_, err = middleware.ParseAuditPolicy("verbose")
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// imagePod returns a pod in a phase running containers with the given
+// image names and IDs
+func imagePod(phase corev1.PodPhase, images ...[2]string) corev1.Pod {
+	pod := corev1.Pod{Status: corev1.PodStatus{Phase: phase}}
+	for _, image := range images {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Image:   image[0],
+			ImageID: image[1],
+		})
+	}
+	return pod
+}
+
+// TestPodImages verifies the images of running pods are listed once each
+func TestPodImages(t *testing.T) {
+	pods := []corev1.Pod{
+		imagePod(corev1.PodRunning,
+			[2]string{"docker.io/library/golang:1.25", "docker.io/library/golang@sha256:aaa"},
+			[2]string{"docker.io/library/alpine:3.20", "docker.io/library/alpine@sha256:bbb"}),
+		// Same image pulled under another tag
+		imagePod(corev1.PodRunning, [2]string{"docker.io/library/alpine:latest", "docker.io/library/alpine@sha256:bbb"}),
+		// Imported image only known by its ID
+		imagePod(corev1.PodRunning, [2]string{"sha256:ccc", "sha256:ccc"}),
+		imagePod(corev1.PodSucceeded, [2]string{"docker.io/library/node:22", "docker.io/library/node@sha256:ddd"}),
+	}
+	init := imagePod(corev1.PodRunning)
+	init.Status.InitContainerStatuses = []corev1.ContainerStatus{{Image: "alpine/git:2.45.2"}}
+	pods = append(pods, init)
+
+	assert.Equal(t, []string{
+		"alpine/git:2.45.2",
+		"docker.io/library/alpine:3.20",
+		"docker.io/library/golang:1.25",
+	}, cluster.PodImages(pods))
+}
+
+// TestParseLoadedImages verifies image names are read from docker load output
+func TestParseLoadedImages(t *testing.T) {
+	output := "Loaded image: golang:1.25\nLoaded image ID: sha256:ccc\nLoaded image: alpine/git:2.45.2\n"
+	assert.Equal(t, []string{"golang:1.25", "alpine/git:2.45.2"}, cluster.ParseLoadedImages(output))
+	assert.Empty(t, cluster.ParseLoadedImages(""))
+}
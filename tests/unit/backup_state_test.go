@@ -0,0 +1,125 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+var (
+	backupConfigResource = c8sv1alpha1.GroupVersion.WithResource("pipelineconfigs")
+	backupRunResource    = c8sv1alpha1.GroupVersion.WithResource("pipelineruns")
+)
+
+// newBackupClient returns a fake dynamic client holding the given objects
+func newBackupClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			backupConfigResource: "PipelineConfigList",
+			backupRunResource:    "PipelineRunList",
+		}, objects...)
+}
+
+// backupObject returns a c8s object as the API server would return it
+func backupObject(kind, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": c8sv1alpha1.GroupVersion.String(),
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         "default",
+			"resourceVersion":   "42",
+			"uid":               "0b6f7c0e-1d2a-4c3b-9f4e-5a6b7c8d9e0f",
+			"generation":        int64(3),
+			"creationTimestamp": "2025-01-15T10:00:00Z",
+			"managedFields":     []interface{}{map[string]interface{}{"manager": "kubectl"}},
+			"labels":            map[string]interface{}{"team": "platform"},
+		},
+		"spec":   map[string]interface{}{"pipelineConfigRef": "ci"},
+		"status": map[string]interface{}{"phase": "Succeeded"},
+	}}
+}
+
+// TestBackupResources_StripsServerFields verifies the backup keeps spec and labels
+// but not status or server-set metadata
+func TestBackupResources_StripsServerFields(t *testing.T) {
+	client := newBackupClient(backupObject("PipelineConfig", "ci"), backupObject("PipelineRun", "ci-1"))
+
+	data, result, err := cluster.BackupResources(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.PipelineConfigs)
+	assert.Equal(t, 1, result.PipelineRuns)
+
+	backup := string(data)
+	assert.Contains(t, backup, "kind: PipelineConfig")
+	assert.Contains(t, backup, "kind: PipelineRun")
+	assert.Contains(t, backup, "team: platform")
+	assert.Contains(t, backup, "pipelineConfigRef: ci")
+	for _, field := range []string{"status:", "resourceVersion:", "uid:", "generation:", "managedFields:"} {
+		assert.NotContains(t, backup, field)
+	}
+	assert.Less(t, strings.Index(backup, "kind: PipelineConfig"), strings.Index(backup, "kind: PipelineRun"),
+		"PipelineConfigs should be written before PipelineRuns")
+}
+
+// TestRestoreResources_RoundTrip verifies a backup recreates its objects in an empty
+// cluster and updates objects that already exist
+func TestRestoreResources_RoundTrip(t *testing.T) {
+	data, _, err := cluster.BackupResources(context.Background(),
+		newBackupClient(backupObject("PipelineConfig", "ci"), backupObject("PipelineRun", "ci-1")))
+	require.NoError(t, err)
+
+	existing := backupObject("PipelineConfig", "ci")
+	existing.SetLabels(map[string]string{"team": "old"})
+	target := newBackupClient(existing)
+
+	result, err := cluster.RestoreResources(context.Background(), target, data)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.PipelineConfigs)
+	assert.Equal(t, 1, result.PipelineRuns)
+
+	config, err := target.Resource(backupConfigResource).Namespace("default").Get(context.Background(), "ci", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "platform", config.GetLabels()["team"])
+
+	run, err := target.Resource(backupRunResource).Namespace("default").Get(context.Background(), "ci-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, hasStatus := run.Object["status"]
+	assert.False(t, hasStatus)
+}
+
+// TestRestoreResources_RejectsOtherKinds verifies a file with other resources is not applied
+func TestRestoreResources_RejectsOtherKinds(t *testing.T) {
+	data := []byte("---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: token\n")
+
+	_, err := cluster.RestoreResources(context.Background(), newBackupClient(), data)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported kind")
+}
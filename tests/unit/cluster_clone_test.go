@@ -0,0 +1,110 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv"
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// TestSourceClusterConfig verifies port mappings, bind mounts and k3s
+// arguments are read from the k3d cluster object
+func TestSourceClusterConfig(t *testing.T) {
+	result := &cluster.InspectResult{
+		Name: "dev",
+		Config: &localenv.ClusterConfig{
+			Name:              "dev",
+			KubernetesVersion: "v1.28.15",
+			Nodes:             []localenv.NodeConfig{{Type: "server", Count: 1}, {Type: "agent", Count: 2}},
+		},
+		K3dCluster: map[string]interface{}{
+			"nodes": []interface{}{
+				map[string]interface{}{
+					"name":      "k3d-dev-server-0",
+					"role":      "server",
+					"volumes":   []interface{}{"k3d-dev-images:/k3d/images", "/src:/workspace"},
+					"extraArgs": []interface{}{"--disable=traefik"},
+				},
+				map[string]interface{}{
+					"name":    "k3d-dev-agent-0",
+					"role":    "agent",
+					"volumes": []interface{}{"/src:/workspace", "/cache:/cache:ro"},
+				},
+				map[string]interface{}{
+					"name":    "k3d-dev-agent-1",
+					"role":    "agent",
+					"volumes": []interface{}{"/src:/workspace"},
+				},
+				map[string]interface{}{
+					"name": "k3d-dev-serverlb",
+					"role": "loadbalancer",
+					"portMappings": map[string]interface{}{
+						"80/tcp":   []interface{}{map[string]interface{}{"HostIp": "0.0.0.0", "HostPort": "8080"}},
+						"6443/tcp": []interface{}{map[string]interface{}{"HostIp": "0.0.0.0", "HostPort": "41234"}},
+					},
+				},
+			},
+		},
+	}
+
+	config := cluster.SourceClusterConfig(result)
+
+	assert.Equal(t, []string{"--disable=traefik"}, config.Options.K3sArgs)
+	assert.Equal(t, []localenv.PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: "TCP", NodeFilter: "loadbalancer"},
+	}, config.Ports)
+	assert.Equal(t, []localenv.VolumeMount{
+		{HostPath: "/src", ContainerPath: "/workspace", NodeFilter: "agent:*"},
+		{HostPath: "/src", ContainerPath: "/workspace", NodeFilter: "server:*"},
+		{HostPath: "/cache", ContainerPath: "/cache", NodeFilter: "agent:0"},
+	}, config.VolumeMounts)
+	assert.Nil(t, result.Config.VolumeMounts, "the inspected config is not modified")
+}
+
+// TestCloneClusterConfig verifies the clone is renamed, gets its own registry
+// and moves host ports in use to free ones
+func TestCloneClusterConfig(t *testing.T) {
+	source := &localenv.ClusterConfig{
+		Name:              "dev",
+		KubernetesVersion: "v1.28.15",
+		Nodes:             []localenv.NodeConfig{{Type: "server", Count: 1}, {Type: "agent", Count: 2}},
+		Ports:             []localenv.PortMapping{{HostPort: 8080, ContainerPort: 80, NodeFilter: "loadbalancer"}},
+		Registry:          &localenv.RegistryConfig{Enabled: true, Name: "registry.localhost", HostPort: 5000},
+		VolumeMounts:      []localenv.VolumeMount{{HostPath: "/src", ContainerPath: "/workspace", NodeFilter: "agent:*"}},
+	}
+	inUse := map[int]bool{8080: true, 8081: true, 5000: true}
+
+	clone := cluster.CloneClusterConfig(source, "dev-2", func(port int) bool { return !inUse[port] })
+
+	assert.Equal(t, "dev-2", clone.Name)
+	assert.Equal(t, source.KubernetesVersion, clone.KubernetesVersion)
+	assert.Equal(t, source.Nodes, clone.Nodes)
+	assert.Equal(t, source.VolumeMounts, clone.VolumeMounts)
+	assert.Equal(t, 8082, clone.Ports[0].HostPort)
+	require.NotNil(t, clone.Registry)
+	assert.Equal(t, "dev-2-registry.localhost", clone.Registry.Name)
+	assert.Equal(t, 5001, clone.Registry.HostPort)
+	assert.NoError(t, localenv.ValidateClusterConfig(clone))
+
+	assert.Equal(t, 8080, source.Ports[0].HostPort, "the source config is not modified")
+	assert.Equal(t, "registry.localhost", source.Registry.Name)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv"
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// issueFields returns the distinct fields of the unfixed issues of a
+// severity, sorted
+func issueFields(issues []cluster.ConfigIssue, severity string) []string {
+	var fields []string
+	for _, issue := range issues {
+		if issue.Severity == severity && !issue.Fixed && !slices.Contains(fields, issue.Field) {
+			fields = append(fields, issue.Field)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// TestValidateClusterConfigFields verifies every invalid field of a config
+// is reported, not only the first
+func TestValidateClusterConfigFields(t *testing.T) {
+	config := localenv.DefaultClusterConfig()
+	assert.Empty(t, cluster.ValidateClusterConfigFields(&config), "default config should be valid")
+
+	config.KubernetesVersion = "1.28"
+	config.Nodes = []localenv.NodeConfig{{Type: "server", Count: 0}, {Type: "agent", Count: -1}}
+	config.Ports = []localenv.PortMapping{
+		{HostPort: 80, ContainerPort: 80, Protocol: "TCP", NodeFilter: "loadbalancer"},
+		{HostPort: 8080, ContainerPort: 70000, Protocol: "SCTP", NodeFilter: "worker"},
+		{HostPort: 8080, ContainerPort: 443, NodeFilter: "loadbalancer"},
+	}
+	config.Registry.Name = "Registry_Local"
+	config.Registry.HostPort = 8080
+	config.Options.WaitTimeout = "soon"
+
+	issues := cluster.ValidateClusterConfigFields(&config)
+	assert.Equal(t, []string{
+		"kubernetesVersion",
+		"nodes",
+		"nodes[1].count",
+		"options.waitTimeout",
+		"ports[0].hostPort",
+		"ports[1].containerPort",
+		"ports[1].nodeFilter",
+		"ports[1].protocol",
+		"ports[2].hostPort",
+		"registry.hostPort",
+		"registry.name",
+	}, issueFields(issues, cluster.ConfigSeverityError))
+	assert.Empty(t, issueFields(issues, cluster.ConfigSeverityWarning))
+}
+
+// TestValidateClusterConfigFieldsNodes verifies node count checks
+func TestValidateClusterConfigFieldsNodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodes    []localenv.NodeConfig
+		errors   []string
+		warnings []string
+	}{
+		{
+			name:  "single server",
+			nodes: []localenv.NodeConfig{{Type: "server", Count: 1}},
+		},
+		{
+			name:     "even servers",
+			nodes:    []localenv.NodeConfig{{Type: "server", Count: 2}},
+			warnings: []string{"nodes"},
+		},
+		{
+			name:     "many agents",
+			nodes:    []localenv.NodeConfig{{Type: "server", Count: 1}, {Type: "agent", Count: 12}},
+			warnings: []string{"nodes"},
+		},
+		{
+			name:   "too many servers",
+			nodes:  []localenv.NodeConfig{{Type: "server", Count: 9}},
+			errors: []string{"nodes"},
+		},
+		{
+			name:   "duplicate type",
+			nodes:  []localenv.NodeConfig{{Type: "server", Count: 1}, {Type: "server", Count: 1}},
+			errors: []string{"nodes[1].type"},
+		},
+		{
+			name:   "unknown type",
+			nodes:  []localenv.NodeConfig{{Type: "server", Count: 1}, {Type: "worker", Count: 1}},
+			errors: []string{"nodes[1].type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := localenv.DefaultClusterConfig()
+			config.Nodes = tt.nodes
+
+			issues := cluster.ValidateClusterConfigFields(&config)
+			assert.Equal(t, tt.errors, issueFields(issues, cluster.ConfigSeverityError))
+			assert.Equal(t, tt.warnings, issueFields(issues, cluster.ConfigSeverityWarning))
+		})
+	}
+}
+
+// TestFixClusterConfig verifies common issues are corrected and reported as
+// fixed
+func TestFixClusterConfig(t *testing.T) {
+	config := localenv.DefaultClusterConfig()
+	config.Name = "My-Dev"
+	config.KubernetesVersion = "1.29.4-k3s1"
+	config.Ports[0].Protocol = "tcp"
+	config.Registry.Name = "Registry.Localhost"
+
+	fixed := cluster.FixClusterConfig(&config)
+	require.Len(t, fixed, 4)
+	for _, issue := range fixed {
+		assert.True(t, issue.Fixed, issue.Field)
+	}
+	assert.Equal(t, "my-dev", config.Name)
+	assert.Equal(t, "v1.29.4", config.KubernetesVersion)
+	assert.Equal(t, "TCP", config.Ports[0].Protocol)
+	assert.Equal(t, "registry.localhost", config.Registry.Name)
+	assert.Empty(t, cluster.ValidateClusterConfigFields(&config))
+
+	assert.Empty(t, cluster.FixClusterConfig(&config), "fixed config should need no fix")
+
+	// Versions without an unambiguous fix are left as is
+	config.KubernetesVersion = "latest"
+	assert.Empty(t, cluster.FixClusterConfig(&config))
+	assert.Equal(t, "latest", config.KubernetesVersion)
+}
+
+// TestValidateConfigImageCheck verifies the k3s image tag of the version is
+// looked up, and a failed lookup only warns
+func TestValidateConfigImageCheck(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/tags/v1.28.15-k3s1":
+			w.WriteHeader(http.StatusOK)
+		case "/tags/v1.99.0-k3s1":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	opts := cluster.ConfigValidateOptions{TagsURL: server.URL + "/tags/", HTTPClient: server.Client()}
+
+	config := localenv.DefaultClusterConfig()
+	report := cluster.ValidateConfig(context.Background(), &config, opts)
+	assert.True(t, report.Valid)
+	assert.Equal(t, "rancher/k3s:v1.28.15-k3s1", report.Image)
+	require.NotNil(t, report.ImageAvailable)
+	assert.True(t, *report.ImageAvailable)
+
+	config.KubernetesVersion = "v1.99.0"
+	report = cluster.ValidateConfig(context.Background(), &config, opts)
+	assert.False(t, report.Valid)
+	require.NotNil(t, report.ImageAvailable)
+	assert.False(t, *report.ImageAvailable)
+	assert.Equal(t, []string{"kubernetesVersion"}, issueFields(report.Issues, cluster.ConfigSeverityError))
+
+	config.KubernetesVersion = "v1.30.0"
+	report = cluster.ValidateConfig(context.Background(), &config, opts)
+	assert.True(t, report.Valid)
+	assert.Nil(t, report.ImageAvailable)
+	assert.Equal(t, []string{"kubernetesVersion"}, issueFields(report.Issues, cluster.ConfigSeverityWarning))
+
+	requested = nil
+	opts.SkipImageCheck = true
+	report = cluster.ValidateConfig(context.Background(), &config, opts)
+	assert.True(t, report.Valid)
+	assert.Empty(t, requested)
+}
+
+// TestValidateConfigFix verifies fixed issues don't fail validation
+func TestValidateConfigFix(t *testing.T) {
+	config := localenv.DefaultClusterConfig()
+	config.KubernetesVersion = "1.28.15"
+
+	report := cluster.ValidateConfig(context.Background(), &config, cluster.ConfigValidateOptions{SkipImageCheck: true})
+	assert.False(t, report.Valid)
+	assert.Equal(t, 1, report.Errors())
+
+	report = cluster.ValidateConfig(context.Background(), &config, cluster.ConfigValidateOptions{Fix: true, SkipImageCheck: true})
+	assert.True(t, report.Valid)
+	require.Len(t, report.Issues, 1)
+	assert.True(t, report.Issues[0].Fixed)
+	assert.Equal(t, "v1.28.15", config.KubernetesVersion)
+}
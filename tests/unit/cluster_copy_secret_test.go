@@ -0,0 +1,160 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+var copySecretResource = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// newSecretClient returns a fake dynamic client holding the given Secrets
+func newSecretClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			copySecretResource:                      "SecretList",
+			{Version: "v1", Resource: "namespaces"}: "NamespaceList",
+		}, objects...)
+}
+
+// secretObject returns a Secret as the API server would return it
+func secretObject(namespace, name, token string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"namespace":       namespace,
+			"resourceVersion": "7",
+			"uid":             "5d1c2b3a-4e5f-6a7b-8c9d-0e1f2a3b4c5d",
+			"ownerReferences": []interface{}{map[string]interface{}{"kind": "ExternalSecret", "name": name}},
+		},
+		"type": "Opaque",
+		"data": map[string]interface{}{"token": token},
+	}}
+}
+
+// TestCopySecretResource verifies a Secret is copied without server-side
+// fields, and that copying it again leaves the copy unchanged
+func TestCopySecretResource(t *testing.T) {
+	client := newSecretClient(secretObject("default", "registry-creds", "c2VjcmV0"))
+	opts := cluster.CopySecretOptions{Name: "registry-creds", FromNamespace: "default", ToNamespace: "ci"}
+
+	result, err := cluster.CopySecretResource(context.Background(), client, opts)
+	require.NoError(t, err)
+	assert.Equal(t, cluster.SecretCreated, result.Action)
+	assert.False(t, result.Conflict)
+
+	copied, err := client.Resource(copySecretResource).Namespace("ci").Get(context.Background(), "registry-creds", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "c2VjcmV0", copied.Object["data"].(map[string]interface{})["token"])
+	assert.Equal(t, "default/registry-creds", copied.GetAnnotations()[cluster.AnnotationCopiedFrom])
+	assert.Empty(t, copied.GetUID())
+	assert.Empty(t, copied.GetOwnerReferences())
+
+	result, err = cluster.CopySecretResource(context.Background(), client, opts)
+	require.NoError(t, err)
+	assert.Equal(t, cluster.SecretUnchanged, result.Action)
+}
+
+// TestCopySecretResource_Conflict verifies a Secret of the same name in the
+// target that wasn't copied from the source is reported and replaced
+func TestCopySecretResource_Conflict(t *testing.T) {
+	client := newSecretClient(
+		secretObject("default", "registry-creds", "bmV3"),
+		secretObject("ci", "registry-creds", "b2xk"),
+	)
+
+	result, err := cluster.CopySecretResource(context.Background(), client,
+		cluster.CopySecretOptions{Name: "registry-creds", FromNamespace: "default", ToNamespace: "ci"})
+	require.NoError(t, err)
+	assert.Equal(t, cluster.SecretUpdated, result.Action)
+	assert.True(t, result.Conflict)
+
+	copied, err := client.Resource(copySecretResource).Namespace("ci").Get(context.Background(), "registry-creds", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "bmV3", copied.Object["data"].(map[string]interface{})["token"])
+}
+
+// TestCopySecretResource_Errors verifies missing Secrets and copies to the
+// source namespace are rejected
+func TestCopySecretResource_Errors(t *testing.T) {
+	client := newSecretClient()
+
+	_, err := cluster.CopySecretResource(context.Background(), client,
+		cluster.CopySecretOptions{Name: "missing", FromNamespace: "default", ToNamespace: "ci"})
+	assert.ErrorContains(t, err, "default/missing")
+
+	_, err = cluster.CopySecretResource(context.Background(), client,
+		cluster.CopySecretOptions{Name: "missing", FromNamespace: "ci", ToNamespace: "ci"})
+	assert.ErrorContains(t, err, "source and target")
+}
+
+// TestWatchSecretResource verifies a change of the source Secret is copied
+// to the target namespace
+func TestWatchSecretResource(t *testing.T) {
+	client := newSecretClient(secretObject("default", "deploy-token", "djE="))
+	opts := cluster.CopySecretOptions{Name: "deploy-token", FromNamespace: "default", ToNamespace: "web"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := make(chan *cluster.CopySecretResult, 10)
+	done := make(chan error)
+	go func() {
+		done <- cluster.WatchSecretResource(ctx, client, opts, func(result *cluster.CopySecretResult, err error) {
+			assert.NoError(t, err)
+			results <- result
+		})
+	}()
+
+	waitResult := func(action string) {
+		t.Helper()
+		select {
+		case result := <-results:
+			require.NotNil(t, result)
+			assert.Equal(t, action, result.Action)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("secret not %s", action)
+		}
+	}
+	waitResult(cluster.SecretCreated)
+
+	rotated := secretObject("default", "deploy-token", "djI=")
+	rotated.SetResourceVersion("8")
+	_, err := client.Resource(copySecretResource).Namespace("default").Update(ctx, rotated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	waitResult(cluster.SecretUpdated)
+
+	copied, err := client.Resource(copySecretResource).Namespace("web").Get(ctx, "deploy-token", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "djI=", copied.Object["data"].(map[string]interface{})["token"])
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// editCRDSchema returns a copy of crd whose schema of the first version was
+// changed by edit
+func editCRDSchema(t *testing.T, crd *unstructured.Unstructured, edit func(schema map[string]interface{})) *unstructured.Unstructured {
+	edited := crd.DeepCopy()
+	versions, _, _ := unstructured.NestedSlice(edited.Object, "spec", "versions")
+	openAPISchema, found, err := unstructured.NestedMap(versions[0].(map[string]interface{}), "schema", "openAPIV3Schema")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	edit(openAPISchema)
+	require.NoError(t, unstructured.SetNestedMap(versions[0].(map[string]interface{}), openAPISchema, "schema", "openAPIV3Schema"))
+	require.NoError(t, unstructured.SetNestedSlice(edited.Object, versions, "spec", "versions"))
+	return edited
+}
+
+// stepSchema returns the schema of a step in a PipelineConfig schema
+func stepSchema(t *testing.T, openAPISchema map[string]interface{}) map[string]interface{} {
+	step, found, err := unstructured.NestedMap(openAPISchema, "properties", "spec", "properties", "steps", "items")
+	require.NoError(t, err)
+	require.True(t, found)
+	return step
+}
+
+// setStepSchema replaces the schema of a step in a PipelineConfig schema
+func setStepSchema(t *testing.T, openAPISchema, step map[string]interface{}) {
+	require.NoError(t, unstructured.SetNestedMap(openAPISchema, step, "properties", "spec", "properties", "steps", "items"))
+}
+
+// TestDiffCRDSchemas_Identical verifies identical schemas have no changes
+func TestDiffCRDSchemas_Identical(t *testing.T) {
+	crd := embeddedCRD(t, "pipelineconfigs.c8s.dev")
+	assert.Empty(t, cluster.DiffCRDSchemas(crd, crd.DeepCopy()))
+}
+
+// TestDiffCRDSchemas verifies added, removed and changed fields are reported
+// from the installed to the bundled schema, with breaking changes flagged
+func TestDiffCRDSchemas(t *testing.T) {
+	bundled := embeddedCRD(t, "pipelineconfigs.c8s.dev")
+	installed := editCRDSchema(t, bundled, func(openAPISchema map[string]interface{}) {
+		step := stepSchema(t, openAPISchema)
+		properties := step["properties"].(map[string]interface{})
+
+		// The bundled schema adds workingDir and removes legacyImage
+		delete(properties, "workingDir")
+		properties["legacyImage"] = map[string]interface{}{"type": "string"}
+		// The bundled schema narrows timeout from any type to string
+		properties["timeout"] = map[string]interface{}{}
+		// The bundled schema lowers the maximum of maxLogSizeMB from 500 to 100
+		properties["maxLogSizeMB"].(map[string]interface{})["maximum"] = int64(500)
+		// The bundled schema requires image, which was optional
+		step["required"] = []interface{}{"commands", "name"}
+		setStepSchema(t, openAPISchema, step)
+	})
+
+	changes := cluster.DiffCRDSchemas(installed, bundled)
+	byPath := map[string]cluster.CRDSchemaChange{}
+	for _, change := range changes {
+		assert.Equal(t, "v1alpha1", change.Version)
+		byPath[change.Path] = change
+	}
+	require.Len(t, changes, 5, "%+v", changes)
+
+	assert.Equal(t, cluster.CRDSchemaChange{Version: "v1alpha1", Path: "spec.steps[].workingDir", Change: cluster.SchemaFieldAdded, Detail: "string"},
+		byPath["spec.steps[].workingDir"])
+
+	removed := byPath["spec.steps[].legacyImage"]
+	assert.Equal(t, cluster.SchemaFieldRemoved, removed.Change)
+	assert.True(t, removed.Breaking)
+
+	typeChange := byPath["spec.steps[].timeout"]
+	assert.Equal(t, "type any → string", typeChange.Detail)
+	assert.True(t, typeChange.Breaking)
+
+	maximum := byPath["spec.steps[].maxLogSizeMB"]
+	assert.Equal(t, "maximum 500 → 100", maximum.Detail)
+	assert.True(t, maximum.Breaking)
+
+	required := byPath["spec.steps[].image"]
+	assert.Equal(t, "now required", required.Detail)
+	assert.True(t, required.Breaking)
+}
+
+// TestDiffCRDSchemas_NonBreaking verifies loosened constraints and new
+// enum values are not breaking
+func TestDiffCRDSchemas_NonBreaking(t *testing.T) {
+	withEnum := func(values ...interface{}) func(map[string]interface{}) {
+		return func(openAPISchema map[string]interface{}) {
+			step := stepSchema(t, openAPISchema)
+			step["properties"].(map[string]interface{})["image"] = map[string]interface{}{"type": "string", "enum": values}
+			step["required"] = []interface{}{"commands", "name"}
+			setStepSchema(t, openAPISchema, step)
+		}
+	}
+	crd := embeddedCRD(t, "pipelineconfigs.c8s.dev")
+	installed := editCRDSchema(t, crd, withEnum("alpine"))
+	bundled := editCRDSchema(t, crd, withEnum("alpine", "golang"))
+
+	changes := cluster.DiffCRDSchemas(installed, bundled)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "enum adds golang", changes[0].Detail)
+	assert.False(t, changes[0].Breaking)
+
+	changes = cluster.DiffCRDSchemas(bundled, installed)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "enum removes golang", changes[0].Detail)
+	assert.True(t, changes[0].Breaking)
+}
+
+// TestDiffCRDObjects verifies each embedded CRD is compared with the
+// installed one and missing CRDs are reported as not installed
+func TestDiffCRDObjects(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{crdResource: "CustomResourceDefinitionList"},
+		embeddedCRD(t, "pipelineruns.c8s.dev"), embeddedCRD(t, "pipelineconfigs.c8s.dev"))
+
+	diffs, err := cluster.DiffCRDObjects(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, diffs, 3)
+
+	assert.Equal(t, "pipelineconfigs.c8s.dev", diffs[0].Name)
+	assert.True(t, diffs[0].Installed)
+	assert.Empty(t, diffs[0].Changes)
+	assert.Equal(t, "repositoryconnections.c8s.dev", diffs[2].Name)
+	assert.False(t, diffs[2].Installed)
+	assert.False(t, diffs[2].Breaking())
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// embeddedCRD returns the embedded CRD with the given name
+func embeddedCRD(t *testing.T, name string) *unstructured.Unstructured {
+	crds, err := cluster.EmbeddedCRDs()
+	require.NoError(t, err)
+	for _, crd := range crds {
+		if crd.GetName() == name {
+			return crd
+		}
+	}
+	t.Fatalf("CRD %s is not embedded", name)
+	return nil
+}
+
+// crdTestConfig returns a PipelineConfig object with the given spec
+func crdTestConfig(name string, spec map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": c8sv1alpha1.GroupVersion.String(),
+		"kind":       "PipelineConfig",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       spec,
+	}}
+}
+
+// TestEmbeddedCRDs verifies the generated CRDs are built into the binary
+func TestEmbeddedCRDs(t *testing.T) {
+	crds, err := cluster.EmbeddedCRDs()
+	require.NoError(t, err)
+
+	var names []string
+	for _, crd := range crds {
+		names = append(names, crd.GetName())
+	}
+	assert.ElementsMatch(t, []string{"pipelineconfigs.c8s.dev", "pipelineruns.c8s.dev", "repositoryconnections.c8s.dev"}, names)
+}
+
+// TestCRDSchemaHash verifies the hash only depends on the version schemas
+func TestCRDSchemaHash(t *testing.T) {
+	crd := embeddedCRD(t, "pipelineconfigs.c8s.dev")
+	hash, err := cluster.CRDSchemaHash(crd)
+	require.NoError(t, err)
+	assert.Len(t, hash, 12)
+
+	relabeled := crd.DeepCopy()
+	relabeled.SetLabels(map[string]string{"team": "platform"})
+	relabeledHash, err := cluster.CRDSchemaHash(relabeled)
+	require.NoError(t, err)
+	assert.Equal(t, hash, relabeledHash)
+
+	changed := crd.DeepCopy()
+	versions, _, _ := unstructured.NestedSlice(changed.Object, "spec", "versions")
+	require.NoError(t, unstructured.SetNestedField(versions[0].(map[string]interface{}), "changed", "schema", "openAPIV3Schema", "description"))
+	require.NoError(t, unstructured.SetNestedSlice(changed.Object, versions, "spec", "versions"))
+	changedHash, err := cluster.CRDSchemaHash(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+// TestInstalledCRDs verifies only CRDs of the c8s.dev group are listed
+func TestInstalledCRDs(t *testing.T) {
+	other := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "certificates.cert-manager.io"},
+		"spec":       map[string]interface{}{"group": "cert-manager.io"},
+	}}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{crdResource: "CustomResourceDefinitionList"},
+		embeddedCRD(t, "pipelineruns.c8s.dev"), embeddedCRD(t, "pipelineconfigs.c8s.dev"), other)
+
+	infos, err := cluster.InstalledCRDs(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "pipelineconfigs.c8s.dev", infos[0].Name)
+	assert.Equal(t, "PipelineConfig", infos[0].Kind)
+	assert.Equal(t, []string{"v1alpha1"}, infos[0].Versions)
+	assert.NotEmpty(t, infos[0].SchemaHash)
+	assert.Equal(t, "pipelineruns.c8s.dev", infos[1].Name)
+}
+
+// TestValidateAgainstCRD verifies objects violating the schema are reported
+// with the failing fields
+func TestValidateAgainstCRD(t *testing.T) {
+	crd := embeddedCRD(t, "pipelineconfigs.c8s.dev")
+	step := map[string]interface{}{"name": "build", "image": "golang:1.25", "commands": []interface{}{"go build ./..."}}
+
+	objects := []unstructured.Unstructured{
+		crdTestConfig("valid", map[string]interface{}{
+			"repository": "https://github.com/org/app.git",
+			"steps":      []interface{}{step},
+		}),
+		crdTestConfig("missing-repository", map[string]interface{}{
+			"steps": []interface{}{step},
+		}),
+		crdTestConfig("bad-timeout", map[string]interface{}{
+			"repository": "https://github.com/org/app.git",
+			"steps":      []interface{}{step},
+			"timeout":    "one hour",
+		}),
+	}
+	unserved := crdTestConfig("unserved", map[string]interface{}{})
+	unserved.SetAPIVersion("c8s.dev/v2")
+	objects = append(objects, unserved)
+
+	violations, err := cluster.ValidateAgainstCRD(crd, objects)
+	require.NoError(t, err)
+	require.Len(t, violations, 3)
+
+	assert.Equal(t, "missing-repository", violations[0].Name)
+	assert.Contains(t, strings.Join(violations[0].Errors, "\n"), "repository")
+	assert.Equal(t, "bad-timeout", violations[1].Name)
+	assert.Contains(t, strings.Join(violations[1].Errors, "\n"), "timeout")
+	assert.Equal(t, "unserved", violations[2].Name)
+	assert.Contains(t, violations[2].Errors[0], "version c8s.dev/v2 is not served")
+}
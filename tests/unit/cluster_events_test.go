@@ -0,0 +1,154 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+	"github.com/org/c8s/pkg/types"
+)
+
+// testEvent returns an event of an object last seen at lastSeen
+func testEvent(name, apiVersion, kind, object, eventType string, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Name:       object,
+			Namespace:  "default",
+		},
+		Type:          eventType,
+		Reason:        "Test",
+		Message:       name,
+		LastTimestamp: metav1.NewTime(lastSeen),
+	}
+}
+
+// collectEvents runs WatchEvents until the initial sync and one live event
+// created by live have been received, returning the messages in order
+func collectEvents(t *testing.T, client *fake.Clientset, opts cluster.EventsOptions, live *corev1.Event) []string {
+	t.Helper()
+
+	watching := make(chan struct{})
+	client.PrependWatchReactor("events", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		// Register the watch before signalling, so the live event isn't missed
+		watcher, err := client.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		close(watching)
+		return true, watcher, err
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- cluster.WatchEvents(ctx, client, opts, func(event cluster.ClusterEvent) {
+			received <- event.Message
+		})
+	}()
+
+	select {
+	case <-watching:
+	case <-ctx.Done():
+		t.Fatal("watch not started")
+	}
+	_, err := client.CoreV1().Events("default").Create(ctx, live, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	var messages []string
+	for {
+		select {
+		case message := <-received:
+			messages = append(messages, message)
+			if message == live.Message {
+				cancel()
+				require.NoError(t, <-done)
+				return messages
+			}
+		case <-ctx.Done():
+			t.Fatalf("live event not received, got %v", messages)
+		}
+	}
+}
+
+// TestWatchEvents verifies recent events of c8s objects are synced oldest
+// first, old and non-c8s events are skipped, and live events are streamed
+func TestWatchEvents(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		testEvent("recent", "c8s.dev/v1alpha1", "PipelineRun", "run-1", corev1.EventTypeNormal, now.Add(-time.Minute)),
+		testEvent("older", "c8s.dev/v1alpha1", "PipelineConfig", "app", corev1.EventTypeWarning, now.Add(-30*time.Minute)),
+		testEvent("stale", "c8s.dev/v1alpha1", "PipelineRun", "run-0", corev1.EventTypeNormal, now.Add(-2*time.Hour)),
+		testEvent("pod", "v1", "Pod", "other", corev1.EventTypeNormal, now),
+	)
+
+	live := testEvent("live", "c8s.dev/v1alpha1", "PipelineRun", "run-2", corev1.EventTypeNormal, now)
+	messages := collectEvents(t, client, cluster.EventsOptions{}, live)
+	assert.Equal(t, []string{"older", "recent", "live"}, messages)
+}
+
+// TestWatchEventsType verifies --type filters events
+func TestWatchEventsType(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		testEvent("normal", "c8s.dev/v1alpha1", "PipelineRun", "run-1", corev1.EventTypeNormal, now),
+		testEvent("warning", "c8s.dev/v1alpha1", "PipelineRun", "run-1", corev1.EventTypeWarning, now),
+	)
+
+	live := testEvent("live", "c8s.dev/v1alpha1", "PipelineRun", "run-1", corev1.EventTypeWarning, now)
+	messages := collectEvents(t, client, cluster.EventsOptions{Type: corev1.EventTypeWarning}, live)
+	assert.Equal(t, []string{"warning", "live"}, messages)
+}
+
+// TestWatchEventsRun verifies --run includes the run's Jobs and Pods,
+// including Pods created after the watch started
+func TestWatchEventsRun(t *testing.T) {
+	now := time.Now()
+	runLabels := map[string]string{types.LabelPipelineRun: "run-1"}
+	objects := []runtime.Object{
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "run-1-build", Namespace: "default", Labels: runLabels}},
+		testEvent("run", "c8s.dev/v1alpha1", "PipelineRun", "run-1", corev1.EventTypeNormal, now.Add(-3*time.Minute)),
+		testEvent("job", "batch/v1", "Job", "run-1-build", corev1.EventTypeNormal, now.Add(-2*time.Minute)),
+		testEvent("other-run", "c8s.dev/v1alpha1", "PipelineRun", "run-2", corev1.EventTypeNormal, now),
+		testEvent("other-job", "batch/v1", "Job", "run-2-build", corev1.EventTypeNormal, now),
+	}
+	client := fake.NewSimpleClientset(objects...)
+
+	_, err := client.CoreV1().Pods("default").Create(context.Background(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1-build-x7k2p", Namespace: "default", Labels: runLabels},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	live := testEvent("pod", "v1", "Pod", "run-1-build-x7k2p", corev1.EventTypeWarning, now)
+	messages := collectEvents(t, client, cluster.EventsOptions{Namespace: "default", Run: "run-1"}, live)
+	assert.Equal(t, []string{"run", "job", "pod"}, messages)
+}
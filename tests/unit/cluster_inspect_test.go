@@ -0,0 +1,79 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/org/c8s/pkg/localenv"
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// testClusterConfig returns a cluster configuration with a registry
+func testClusterConfig(version string, servers, agents, registryPort int) *localenv.ClusterConfig {
+	return &localenv.ClusterConfig{
+		Name:              "c8s-dev",
+		KubernetesVersion: version,
+		Nodes: []localenv.NodeConfig{
+			{Type: "server", Count: servers},
+			{Type: "agent", Count: agents},
+		},
+		Registry: &localenv.RegistryConfig{
+			Enabled:  true,
+			Name:     "registry.localhost",
+			HostPort: registryPort,
+		},
+	}
+}
+
+// TestDiffClusterConfig_Identical verifies matching configurations report no differences
+func TestDiffClusterConfig_Identical(t *testing.T) {
+	expected := testClusterConfig("v1.28.15", 1, 2, 5000)
+	actual := testClusterConfig("v1.28.15", 1, 2, 5000)
+	actual.Registry.Name = "k3d-registry.localhost"
+
+	assert.Empty(t, cluster.DiffClusterConfig(expected, actual))
+}
+
+// TestDiffClusterConfig_Divergences verifies each differing setting is reported
+func TestDiffClusterConfig_Divergences(t *testing.T) {
+	expected := testClusterConfig("v1.28.15", 1, 2, 5000)
+	actual := testClusterConfig("v1.27.4", 1, 3, 5001)
+
+	diffs := cluster.DiffClusterConfig(expected, actual)
+
+	assert.Equal(t, []cluster.ConfigDifference{
+		{Field: "kubernetesVersion", Expected: "v1.28.15", Actual: "v1.27.4"},
+		{Field: "nodes.agent", Expected: "2", Actual: "3"},
+		{Field: "registry.hostPort", Expected: "5000", Actual: "5001"},
+	}, diffs)
+}
+
+// TestDiffClusterConfig_MissingRegistry verifies a missing registry is reported once
+func TestDiffClusterConfig_MissingRegistry(t *testing.T) {
+	expected := testClusterConfig("v1.28.15", 1, 2, 5000)
+	actual := testClusterConfig("v1.28.15", 1, 2, 5000)
+	actual.Registry = nil
+
+	diffs := cluster.DiffClusterConfig(expected, actual)
+
+	assert.Equal(t, []cluster.ConfigDifference{
+		{Field: "registry.enabled", Expected: "true", Actual: "false"},
+	}, diffs)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// TestParseDiskSize verifies docker sizes are converted to bytes
+func TestParseDiskSize(t *testing.T) {
+	tests := map[string]int64{
+		"0B":      0,
+		"512B":    512,
+		"12.5kB":  12500,
+		"12.5KB":  12500,
+		"1.5GB":   1500000000,
+		"245.3MB": 245300000,
+	}
+	for input, want := range tests {
+		got, err := cluster.ParseDiskSize(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "N/A", "GB", "-1MB"} {
+		_, err := cluster.ParseDiskSize(input)
+		assert.Error(t, err, input)
+	}
+}
+
+// TestFormatDiskSize verifies byte counts are printed like docker does
+func TestFormatDiskSize(t *testing.T) {
+	assert.Equal(t, "0B", cluster.FormatDiskSize(0))
+	assert.Equal(t, "999B", cluster.FormatDiskSize(999))
+	assert.Equal(t, "12.5kB", cluster.FormatDiskSize(12500))
+	assert.Equal(t, "1.5GB", cluster.FormatDiskSize(1500000000))
+	assert.Equal(t, "2GB", cluster.FormatDiskSize(2000000000))
+}
+
+// listTestClusters returns clusters in k3d's order
+func listTestClusters() []cluster.ClusterListItem {
+	return []cluster.ClusterListItem{
+		{Name: "c8s-dev", State: "running", NodeCount: 3, Uptime: "2h30m0s"},
+		{Name: "c8s-b", State: "stopped", NodeCount: 1},
+		{Name: "c8s-a", State: "running", NodeCount: 1, Uptime: "< 1m"},
+		{Name: "c8s-c", State: "running", NodeCount: 2, Uptime: "45m0s"},
+	}
+}
+
+// clusterNames returns the names of clusters in order
+func clusterNames(clusters []cluster.ClusterListItem) []string {
+	names := make([]string, len(clusters))
+	for i, c := range clusters {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// TestSortClusters verifies each sort field and direction, with ties
+// ordered by name
+func TestSortClusters(t *testing.T) {
+	tests := []struct {
+		field string
+		desc  bool
+		want  []string
+	}{
+		{field: "name", want: []string{"c8s-a", "c8s-b", "c8s-c", "c8s-dev"}},
+		{field: "name", desc: true, want: []string{"c8s-dev", "c8s-c", "c8s-b", "c8s-a"}},
+		{field: "state", want: []string{"c8s-a", "c8s-c", "c8s-dev", "c8s-b"}},
+		{field: "uptime", want: []string{"c8s-b", "c8s-a", "c8s-c", "c8s-dev"}},
+		{field: "uptime", desc: true, want: []string{"c8s-dev", "c8s-c", "c8s-a", "c8s-b"}},
+		{field: "nodes", want: []string{"c8s-a", "c8s-b", "c8s-c", "c8s-dev"}},
+		{field: "nodes", desc: true, want: []string{"c8s-dev", "c8s-c", "c8s-b", "c8s-a"}},
+	}
+
+	for _, tt := range tests {
+		clusters := listTestClusters()
+		require.NoError(t, cluster.SortClusters(clusters, tt.field, tt.desc))
+		assert.Equal(t, tt.want, clusterNames(clusters), "%s desc=%v", tt.field, tt.desc)
+	}
+
+	assert.ErrorContains(t, cluster.SortClusters(listTestClusters(), "age", false), `invalid sort field "age"`)
+}
+
+// TestFilterClustersByState verifies only clusters in the state are kept
+func TestFilterClustersByState(t *testing.T) {
+	running, err := cluster.FilterClustersByState(listTestClusters(), "running")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c8s-dev", "c8s-a", "c8s-c"}, clusterNames(running))
+
+	stopped, err := cluster.FilterClustersByState(listTestClusters(), "stopped")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c8s-b"}, clusterNames(stopped))
+
+	_, err = cluster.FilterClustersByState(listTestClusters(), "paused")
+	assert.ErrorContains(t, err, `invalid state "paused"`)
+}
+
+// versionTestClusters returns clusters at different versions, with a
+// registry on some
+func versionTestClusters() []cluster.ClusterListItem {
+	return []cluster.ClusterListItem{
+		{Name: "c8s-old", State: "running", Version: "v1.27.16+k3s1"},
+		{Name: "c8s-dev", State: "running", Version: "v1.28.15+k3s1", RegistryEndpoint: "localhost:5000"},
+		{Name: "c8s-ci", State: "running", Version: "v1.28.3+k3s2"},
+		{Name: "c8s-new", State: "running", Version: "v1.31.5+k3s1", RegistryEndpoint: "localhost:5001"},
+		{Name: "c8s-off", State: "stopped"},
+	}
+}
+
+// TestFilterClustersByVersion verifies clusters are matched by version
+// prefix, with or without the leading v
+func TestFilterClustersByVersion(t *testing.T) {
+	clusters := versionTestClusters()
+
+	assert.Equal(t, []string{"c8s-dev", "c8s-ci"}, clusterNames(cluster.FilterClustersByVersion(clusters, "v1.28")))
+	assert.Equal(t, []string{"c8s-dev", "c8s-ci"}, clusterNames(cluster.FilterClustersByVersion(clusters, "1.28")))
+	assert.Equal(t, []string{"c8s-ci"}, clusterNames(cluster.FilterClustersByVersion(clusters, "v1.28.3")))
+	assert.Equal(t, []string{"c8s-old", "c8s-dev", "c8s-ci", "c8s-new"}, clusterNames(cluster.FilterClustersByVersion(clusters, "v1")))
+	assert.Empty(t, cluster.FilterClustersByVersion(clusters, "v1.29"))
+}
+
+// TestFilterClustersWithRegistry verifies only clusters with a registry
+// endpoint are kept, and that filters combine
+func TestFilterClustersWithRegistry(t *testing.T) {
+	withRegistry := cluster.FilterClustersWithRegistry(versionTestClusters())
+	assert.Equal(t, []string{"c8s-dev", "c8s-new"}, clusterNames(withRegistry))
+
+	assert.Equal(t, []string{"c8s-dev"}, clusterNames(cluster.FilterClustersByVersion(withRegistry, "v1.28")))
+	assert.Empty(t, cluster.FilterClustersWithRegistry(listTestClusters()))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+	"github.com/org/c8s/pkg/types"
+)
+
+// migrateRun returns a PipelineRun in a given phase
+func migrateRun(name, phase string) *unstructured.Unstructured {
+	run := backupObject("PipelineRun", name)
+	run.Object["status"] = map[string]interface{}{"phase": phase}
+	return run
+}
+
+// TestMigrateResources_Namespace verifies PipelineConfigs are copied to the
+// target namespace without server-side fields, and runs only on request
+func TestMigrateResources_Namespace(t *testing.T) {
+	client := newBackupClient(backupObject("PipelineConfig", "ci"), migrateRun("ci-1", "Succeeded"))
+
+	result, err := cluster.MigrateResources(context.Background(), client, client, cluster.MigrateOptions{
+		Cluster: "dev", FromNamespace: "default", ToCluster: "dev", ToNamespace: "ci",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.MigratedObject{{Kind: "PipelineConfig", Name: "ci", Action: cluster.MigrateCreated}}, result.Objects)
+
+	config, err := client.Resource(backupConfigResource).Namespace("ci").Get(context.Background(), "ci", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "platform", config.GetLabels()["team"])
+	assert.Empty(t, config.GetUID())
+	_, hasStatus := config.Object["status"]
+	assert.False(t, hasStatus)
+
+	_, err = client.Resource(backupRunResource).Namespace("ci").Get(context.Background(), "ci-1", metav1.GetOptions{})
+	assert.Error(t, err)
+
+	// The source is left in place
+	_, err = client.Resource(backupConfigResource).Namespace("default").Get(context.Background(), "ci", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+// TestMigrateResources_IncludeRuns verifies finished runs keep their status
+// and are marked imported, while runs in progress are skipped
+func TestMigrateResources_IncludeRuns(t *testing.T) {
+	from := newBackupClient(backupObject("PipelineConfig", "ci"), migrateRun("ci-1", "Failed"), migrateRun("ci-2", "Running"))
+	existing := backupObject("PipelineConfig", "ci")
+	existing.SetLabels(map[string]string{"team": "old"})
+	to := newBackupClient(existing)
+
+	result, err := cluster.MigrateResources(context.Background(), from, to, cluster.MigrateOptions{
+		Cluster: "dev", FromNamespace: "default", ToCluster: "staging", ToNamespace: "default", IncludeRuns: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.MigratedObject{
+		{Kind: "PipelineConfig", Name: "ci", Action: cluster.MigrateUpdated},
+		{Kind: "PipelineRun", Name: "ci-1", Action: cluster.MigrateCreated},
+		{Kind: "PipelineRun", Name: "ci-2", Action: cluster.MigrateSkipped, Reason: "run is Running"},
+	}, result.Objects)
+
+	config, err := to.Resource(backupConfigResource).Namespace("default").Get(context.Background(), "ci", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "platform", config.GetLabels()["team"])
+
+	run, err := to.Resource(backupRunResource).Namespace("default").Get(context.Background(), "ci-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	phase, _, _ := unstructured.NestedString(run.Object, "status", "phase")
+	assert.Equal(t, "Failed", phase)
+	assert.Equal(t, "k3d-dev/default", run.GetAnnotations()[types.AnnotationImportedFrom])
+
+	_, err = to.Resource(backupRunResource).Namespace("default").Get(context.Background(), "ci-2", metav1.GetOptions{})
+	assert.Error(t, err)
+}
+
+// TestMigrateResources_DryRun verifies a dry run lists the objects without
+// creating them
+func TestMigrateResources_DryRun(t *testing.T) {
+	client := newBackupClient(backupObject("PipelineConfig", "ci"), migrateRun("ci-1", "Succeeded"))
+
+	result, err := cluster.MigrateResources(context.Background(), client, client, cluster.MigrateOptions{
+		Cluster: "dev", FromNamespace: "default", ToCluster: "dev", ToNamespace: "ci", IncludeRuns: true, DryRun: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []cluster.MigratedObject{
+		{Kind: "PipelineConfig", Name: "ci", Action: cluster.MigratePlanned},
+		{Kind: "PipelineRun", Name: "ci-1", Action: cluster.MigratePlanned},
+	}, result.Objects)
+
+	list, err := client.Resource(backupConfigResource).Namespace("ci").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
+
+// TestMigrate_SameTarget verifies migrating a namespace onto itself is rejected
+func TestMigrate_SameTarget(t *testing.T) {
+	_, err := cluster.Migrate(context.Background(), cluster.MigrateOptions{Cluster: "dev", FromNamespace: "default"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "source and target")
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// TestNewNodeName verifies new nodes are named after the lowest index no
+// existing node of the cluster uses
+func TestNewNodeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		existing []string
+		want     string
+	}{
+		{
+			name:     "cluster agents only",
+			role:     cluster.NodeRoleAgent,
+			existing: []string{"k3d-c8s-dev-server-0", "k3d-c8s-dev-agent-0", "k3d-c8s-dev-agent-1"},
+			want:     "c8s-dev-agent-2",
+		},
+		{
+			name:     "added agents",
+			role:     cluster.NodeRoleAgent,
+			existing: []string{"k3d-c8s-dev-server-0", "k3d-c8s-dev-agent-0", "k3d-c8s-dev-agent-1", "k3d-c8s-dev-agent-2-0", "k3d-c8s-dev-agent-2-1"},
+			want:     "c8s-dev-agent-3",
+		},
+		{
+			name:     "removed agent",
+			role:     cluster.NodeRoleAgent,
+			existing: []string{"k3d-c8s-dev-server-0", "k3d-c8s-dev-agent-1"},
+			want:     "c8s-dev-agent-0",
+		},
+		{
+			name:     "server",
+			role:     cluster.NodeRoleServer,
+			existing: []string{"k3d-c8s-dev-server-0", "k3d-c8s-dev-server-1", "k3d-c8s-dev-server-2"},
+			want:     "c8s-dev-server-3",
+		},
+		{
+			name:     "other index prefix",
+			role:     cluster.NodeRoleAgent,
+			existing: []string{"k3d-c8s-dev-agent-10"},
+			want:     "c8s-dev-agent-0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cluster.NewNodeName("c8s-dev", tt.role, tt.existing))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+var pauseDeploymentResource = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+// pauseDeployment returns a Deployment with the given replicas, or none when negative
+func pauseDeployment(namespace, name string, replicas int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{},
+	}}
+	if replicas >= 0 {
+		_ = unstructured.SetNestedField(obj.Object, replicas, "spec", "replicas")
+	}
+	return obj
+}
+
+// getDeployment returns a Deployment from the fake client
+func getDeployment(t *testing.T, client *dynamicfake.FakeDynamicClient, namespace, name string) *unstructured.Unstructured {
+	t.Helper()
+	obj, err := client.Resource(pauseDeploymentResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return obj
+}
+
+// TestPauseResumeDeployments verifies pause scales c8s Deployments to zero and resume
+// restores their replicas, leaving other namespaces alone
+func TestPauseResumeDeployments(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{pauseDeploymentResource: "DeploymentList"},
+		pauseDeployment("c8s-system", "c8s-controller", 2),
+		pauseDeployment("c8s-system", "c8s-api-server", -1),
+		pauseDeployment("default", "app", 3))
+	ctx := context.Background()
+	namespaces := []string{"c8s-system"}
+
+	paused, err := cluster.PauseDeployments(ctx, client, namespaces)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"c8s-system/c8s-controller", "c8s-system/c8s-api-server"}, paused)
+
+	controller := getDeployment(t, client, "c8s-system", "c8s-controller")
+	replicas, _, _ := unstructured.NestedInt64(controller.Object, "spec", "replicas")
+	assert.Zero(t, replicas)
+	assert.Equal(t, "2", controller.GetAnnotations()[cluster.AnnotationPrePauseReplicas])
+	assert.Equal(t, "1", getDeployment(t, client, "c8s-system", "c8s-api-server").GetAnnotations()[cluster.AnnotationPrePauseReplicas])
+
+	isPaused, err := cluster.IsPaused(ctx, client, namespaces)
+	require.NoError(t, err)
+	assert.True(t, isPaused)
+
+	// Pausing again must not overwrite the saved replica count with zero
+	paused, err = cluster.PauseDeployments(ctx, client, namespaces)
+	require.NoError(t, err)
+	assert.Empty(t, paused)
+
+	resumed, err := cluster.ResumeDeployments(ctx, client, namespaces)
+	require.NoError(t, err)
+	assert.Len(t, resumed, 2)
+
+	controller = getDeployment(t, client, "c8s-system", "c8s-controller")
+	replicas, _, _ = unstructured.NestedInt64(controller.Object, "spec", "replicas")
+	assert.Equal(t, int64(2), replicas)
+	assert.NotContains(t, controller.GetAnnotations(), cluster.AnnotationPrePauseReplicas)
+
+	app := getDeployment(t, client, "default", "app")
+	replicas, _, _ = unstructured.NestedInt64(app.Object, "spec", "replicas")
+	assert.Equal(t, int64(3), replicas)
+
+	isPaused, err = cluster.IsPaused(ctx, client, namespaces)
+	require.NoError(t, err)
+	assert.False(t, isPaused)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// selfSignedCA returns a PEM-encoded self-signed CA certificate and its DER bytes
+func selfSignedCA(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "k3s-server-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), der
+}
+
+// TestClusterCACertificate verifies the CA certificate is read from the k3d
+// context of a cluster
+func TestClusterCACertificate(t *testing.T) {
+	caPEM, der := selfSignedCA(t)
+	config := clientcmdapi.NewConfig()
+	config.Clusters["k3d-dev"] = &clientcmdapi.Cluster{Server: "https://0.0.0.0:6443", CertificateAuthorityData: caPEM}
+	config.Clusters["k3d-bad"] = &clientcmdapi.Cluster{Server: "https://0.0.0.0:6444", CertificateAuthorityData: []byte("not a certificate")}
+	config.Contexts["k3d-dev"] = &clientcmdapi.Context{Cluster: "k3d-dev"}
+	config.Contexts["k3d-bad"] = &clientcmdapi.Context{Cluster: "k3d-bad"}
+
+	data, err := cluster.ClusterCACertificate(config, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, caPEM, data)
+
+	fingerprint, err := cluster.CertificateFingerprint(data)
+	require.NoError(t, err)
+	sum := sha1.Sum(der)
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(sum[:])), fingerprint)
+
+	_, err = cluster.ClusterCACertificate(config, "bad")
+	assert.ErrorContains(t, err, "not a PEM certificate")
+
+	_, err = cluster.ClusterCACertificate(config, "missing")
+	assert.ErrorContains(t, err, "context k3d-missing not found")
+}
+
+// TestTrustCommands verifies the trust store location and commands of each OS
+func TestTrustCommands(t *testing.T) {
+	path, err := cluster.TrustCertPath("linux", "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "/usr/local/share/ca-certificates/c8s-dev.crt", path)
+
+	commands, err := cluster.TrustCommands("linux", "/tmp/ca.crt", path, "AB", false)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"install", "-m", "0644", "/tmp/ca.crt", path},
+		{"update-ca-certificates"},
+	}, commands)
+
+	commands, err = cluster.TrustCommands("linux", "/tmp/ca.crt", path, "AB", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rm", "-f", path}, commands[0])
+
+	keychain, err := cluster.TrustCertPath("darwin", "dev")
+	require.NoError(t, err)
+	commands, err = cluster.TrustCommands("darwin", "/tmp/ca.crt", keychain, "AB", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"security", "delete-certificate", "-Z", "AB", keychain}, commands[1])
+
+	_, err = cluster.TrustCertPath("windows", "dev")
+	assert.ErrorContains(t, err, "not supported on windows")
+}
+
+// TestRestartDeployments verifies every Deployment of the namespaces gets the
+// rollout restart annotation
+func TestRestartDeployments(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{pauseDeploymentResource: "DeploymentList"},
+		pauseDeployment("c8s-system", "c8s-controller", 1),
+		pauseDeployment("default", "app", 1))
+
+	restarted, err := cluster.RestartDeployments(context.Background(), client, []string{"c8s-system"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c8s-system/c8s-controller"}, restarted)
+
+	annotations, _, _ := unstructured.NestedStringMap(getDeployment(t, client, "c8s-system", "c8s-controller").Object, "spec", "template", "metadata", "annotations")
+	assert.NotEmpty(t, annotations["kubectl.kubernetes.io/restartedAt"])
+	_, found, _ := unstructured.NestedMap(getDeployment(t, client, "default", "app").Object, "spec", "template")
+	assert.False(t, found)
+}
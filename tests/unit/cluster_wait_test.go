@@ -0,0 +1,62 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// TestParseWaitCondition verifies only the supported conditions are accepted
+func TestParseWaitCondition(t *testing.T) {
+	condition, err := cluster.ParseWaitCondition("crds-installed")
+	require.NoError(t, err)
+	assert.Equal(t, cluster.ConditionCRDsInstalled, condition)
+
+	_, err = cluster.ParseWaitCondition("operator-ready")
+	assert.ErrorContains(t, err, `unknown condition "operator-ready"`)
+}
+
+// TestCheckWaitConditionCRDsInstalled verifies the condition passes once
+// every c8s resource is served
+func TestCheckWaitConditionCRDsInstalled(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+
+	err := cluster.CheckWaitCondition(ctx, client, cluster.ConditionCRDsInstalled)
+	assert.ErrorContains(t, err, "c8s CRDs are not installed")
+
+	discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "c8s.dev/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "pipelineconfigs"}, {Name: "pipelineruns"}},
+	}}
+	err = cluster.CheckWaitCondition(ctx, client, cluster.ConditionCRDsInstalled)
+	assert.ErrorContains(t, err, "CRD repositoryconnections.c8s.dev is not installed")
+
+	discovery.Resources[0].APIResources = append(discovery.Resources[0].APIResources,
+		metav1.APIResource{Name: "repositoryconnections"})
+	assert.NoError(t, cluster.CheckWaitCondition(ctx, client, cluster.ConditionCRDsInstalled))
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/storage"
+)
+
+// typicalBuildLog returns log content resembling a Go build and test run
+func typicalBuildLog() []byte {
+	buf := &bytes.Buffer{}
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(buf, "2025-01-15T10:%02d:%02dZ === RUN   TestPipeline/case_%d\n", i/60%60, i%60, i)
+		fmt.Fprintf(buf, "2025-01-15T10:%02d:%02dZ --- PASS: TestPipeline/case_%d (0.%02ds)\n", i/60%60, i%60, i, i%100)
+		fmt.Fprintf(buf, "go: downloading github.com/example/module%d v1.%d.0\n", i%20, i%7)
+	}
+	buf.WriteString("PASS\nok  \tgithub.com/example/app\t12.345s\n")
+	return buf.Bytes()
+}
+
+// TestCompressLog_ReducesTypicalLogsByHalf verifies gzip at least halves typical log content
+func TestCompressLog_ReducesTypicalLogsByHalf(t *testing.T) {
+	logs := typicalBuildLog()
+
+	compressed, err := storage.CompressLog(logs)
+	require.NoError(t, err)
+
+	assert.Less(t, len(compressed), len(logs)/2,
+		"compressed %d bytes to %d bytes", len(logs), len(compressed))
+}
+
+// TestDecompressLog_RoundTrip verifies gzip-encoded logs decompress to the original content
+func TestDecompressLog_RoundTrip(t *testing.T) {
+	logs := typicalBuildLog()
+
+	compressed, err := storage.CompressLog(logs)
+	require.NoError(t, err)
+
+	reader, err := storage.DecompressLog(io.NopCloser(bytes.NewReader(compressed)), storage.ContentEncodingGzip)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, logs, decompressed)
+}
+
+// TestDecompressLog_PlainText verifies logs without a content encoding are returned unchanged
+func TestDecompressLog_PlainText(t *testing.T) {
+	logs := []byte("plain text logs uploaded before compression\n")
+
+	reader, err := storage.DecompressLog(io.NopCloser(bytes.NewReader(logs)), "")
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, logs, content)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/types"
+)
+
+// stepJob returns a Job for a step of run-1 with the given status
+func stepJob(step string, status batchv1.JobStatus) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "run-1-" + step,
+			Namespace: "default",
+			Labels:    map[string]string{types.LabelPipelineRun: "run-1", types.LabelStepName: step},
+		},
+		Status: status,
+	}
+}
+
+// TestStepsCompletedCondition verifies StepsCompleted follows the run through to completion
+func TestStepsCompletedCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		lintStatus batchv1.JobStatus
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{name: "succeeded", lintStatus: batchv1.JobStatus{Succeeded: 1}, wantStatus: metav1.ConditionTrue, wantReason: types.ReasonStepSucceeded},
+		{name: "failed", lintStatus: batchv1.JobStatus{Failed: 1}, wantStatus: metav1.ConditionFalse, wantReason: types.ReasonStepFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+			build := stepJob("build", batchv1.JobStatus{Active: 1})
+			lint := stepJob("lint", batchv1.JobStatus{Active: 1})
+
+			s := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(s))
+			require.NoError(t, c8sv1alpha1.AddToScheme(s))
+			c := fake.NewClientBuilder().WithScheme(s).
+				WithObjects(run, build, lint).
+				WithStatusSubresource(run).
+				Build()
+			su := controller.NewStatusUpdater(c)
+			jobs := map[string]*batchv1.Job{"build": build, "lint": lint}
+
+			require.NoError(t, su.UpdatePipelineRunStatus(context.Background(), run, jobs, nil, 2))
+			cond := apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeStepsCompleted)
+			require.NotNil(t, cond)
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, types.ReasonStepRunning, cond.Reason)
+			assert.Equal(t, "0 of 2 steps completed", cond.Message)
+
+			build.Status = batchv1.JobStatus{Succeeded: 1}
+			require.NoError(t, su.UpdatePipelineRunStatus(context.Background(), run, jobs, nil, 2))
+			cond = apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeStepsCompleted)
+			require.NotNil(t, cond)
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, "1 of 2 steps completed", cond.Message)
+
+			lint.Status = tt.lintStatus
+			require.NoError(t, su.UpdatePipelineRunStatus(context.Background(), run, jobs, nil, 2))
+			cond = apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeStepsCompleted)
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.wantStatus, cond.Status)
+			assert.Equal(t, tt.wantReason, cond.Reason)
+		})
+	}
+}
+
+// TestConfigResolvedCondition verifies ConfigResolved distinguishes a missing config from an invalid one
+func TestConfigResolvedCondition(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{Spec: c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "ci"}}
+	su := controller.NewStatusUpdater(nil)
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "c8s.dev", Resource: "pipelineconfigs"}, "ci")
+	su.SetConfigResolvedCondition(run, notFound)
+	cond := apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeConfigResolved)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, types.ReasonPipelineConfigNotFound, cond.Reason)
+
+	su.SetConfigResolvedCondition(run, errors.New("circular dependency: a -> b -> a"))
+	cond = apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeConfigResolved)
+	require.NotNil(t, cond)
+	assert.Equal(t, types.ReasonInvalidPipelineConfig, cond.Reason)
+	assert.Contains(t, cond.Message, "circular dependency")
+
+	su.SetConfigResolvedCondition(run, nil)
+	cond = apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeConfigResolved)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, types.ReasonPipelineConfigResolved, cond.Reason)
+	assert.Len(t, run.Status.Conditions, 1)
+}
+
+// TestJobsCreatedCondition verifies JobsCreated reports pending, complete and failed Job creation
+func TestJobsCreatedCondition(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{}
+	su := controller.NewStatusUpdater(nil)
+
+	su.SetJobsCreatedCondition(run, 1, 3, nil)
+	cond := apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeJobsCreated)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, types.ReasonJobsPending, cond.Reason)
+	assert.Equal(t, "1 of 3 Jobs created", cond.Message)
+
+	su.SetJobsCreatedCondition(run, 1, 3, errors.New("admission webhook denied the request"))
+	cond = apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeJobsCreated)
+	require.NotNil(t, cond)
+	assert.Equal(t, types.ReasonJobCreationFailed, cond.Reason)
+
+	su.SetJobsCreatedCondition(run, 3, 3, nil)
+	cond = apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeJobsCreated)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, types.ReasonJobsCreated, cond.Reason)
+}
+
+// TestLogsCollectedCondition verifies LogsCollected only becomes true once a finished run has all its logs
+func TestLogsCollectedCondition(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{
+		Status: c8sv1alpha1.PipelineRunStatus{
+			Phase: c8sv1alpha1.PipelineRunPhaseRunning,
+			Steps: []c8sv1alpha1.StepStatus{
+				{Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded, LogURL: "s3://logs/build.log"},
+				{Name: "test", Phase: c8sv1alpha1.StepPhaseRunning},
+			},
+		},
+	}
+	su := controller.NewStatusUpdater(nil)
+
+	assert.True(t, su.SetLogsCollectedCondition(run, nil))
+	assert.False(t, su.SetLogsCollectedCondition(run, nil), "unchanged condition should not report a change")
+	cond := apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeLogsCollected)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, types.ReasonLogsPending, cond.Reason)
+
+	run.Status.Phase = c8sv1alpha1.PipelineRunPhaseSucceeded
+	run.Status.Steps[1].Phase = c8sv1alpha1.StepPhaseSucceeded
+	assert.True(t, su.SetLogsCollectedCondition(run, errors.New("bucket not found")))
+	cond = apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeLogsCollected)
+	require.NotNil(t, cond)
+	assert.Equal(t, types.ReasonStorageError, cond.Reason)
+
+	run.Status.Steps[1].LogURL = "s3://logs/test.log"
+	assert.True(t, su.SetLogsCollectedCondition(run, nil))
+	cond = apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeLogsCollected)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, types.ReasonLogsUploaded, cond.Reason)
+}
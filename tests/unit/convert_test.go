@@ -0,0 +1,178 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/parser"
+)
+
+// TestConvertGitHubActions verifies jobs become steps with needs as dependsOn
+func TestConvertGitHubActions(t *testing.T) {
+	workflow := []byte(`
+name: CI
+on: [push]
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    timeout-minutes: 20
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+      - run: go test ./...
+  build:
+    needs: test
+    container:
+      image: golang:1.25
+    steps:
+      - uses: actions/checkout@v4
+      - run: make build
+      - uses: docker/build-push-action@v6
+`)
+
+	result, err := parser.ConvertGitHubActions(workflow)
+	require.NoError(t, err)
+
+	pipeline := result.Pipeline
+	assert.Equal(t, "CI", pipeline.Name)
+	require.Len(t, pipeline.Steps, 2)
+
+	build, test := pipeline.Steps[0], pipeline.Steps[1]
+	assert.Equal(t, "build", build.Name)
+	assert.Equal(t, "golang:1.25", build.Image)
+	assert.Equal(t, []string{"make build"}, build.Commands)
+	assert.Equal(t, []string{"test"}, build.DependsOn)
+
+	assert.Equal(t, "test", test.Name)
+	assert.Equal(t, parser.ConvertDefaultImage, test.Image)
+	assert.Equal(t, "20m", test.Timeout)
+
+	warnings := strings.Join(result.Warnings, "\n")
+	assert.Contains(t, warnings, "actions/setup-go@v5")
+	assert.Contains(t, warnings, "docker/build-push-action@v6")
+	assert.Contains(t, warnings, "jobs.test runs on ubuntu-latest")
+	assert.NotContains(t, warnings, "actions/checkout")
+
+	// The output is a valid pipeline
+	output, err := result.Marshal()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(output), "# WARNING: "))
+	spec, err := parser.ParseBytes(output)
+	require.NoError(t, err)
+	assert.Len(t, spec.Steps, 2)
+
+	_, err = parser.ConvertGitHubActions([]byte("name: empty\n"))
+	assert.Error(t, err)
+}
+
+// TestConvertTekton verifies pipeline tasks become steps with runAfter as dependsOn
+func TestConvertTekton(t *testing.T) {
+	resources := []byte(`
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: go-test
+spec:
+  steps:
+    - name: test
+      image: golang:1.25
+      script: |
+        go test ./...
+    - name: vet
+      image: golang:1.25
+      command: ["go"]
+      args: ["vet", "./..."]
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: ci
+spec:
+  workspaces:
+    - name: source
+  tasks:
+    - name: unit-tests
+      taskRef:
+        name: go-test
+      timeout: 1h0m0s
+      retries: 2
+    - name: package
+      runAfter: [unit-tests]
+      taskSpec:
+        steps:
+          - name: build
+            image: golang:1.25
+            script: make build
+          - name: push
+            image: gcr.io/kaniko-project/executor:latest
+            args: ["--destination", "example/app"]
+  finally:
+    - name: notify
+      taskSpec:
+        steps:
+          - name: notify
+            image: curlimages/curl:8.10.1
+            script: curl -X POST https://example.com/hook
+`)
+
+	result, err := parser.ConvertTekton(resources)
+	require.NoError(t, err)
+
+	pipeline := result.Pipeline
+	assert.Equal(t, "ci", pipeline.Name)
+	require.Len(t, pipeline.Steps, 3)
+
+	tests := pipeline.Steps[0]
+	assert.Equal(t, "unit-tests", tests.Name)
+	assert.Equal(t, "golang:1.25", tests.Image)
+	assert.Equal(t, []string{"go test ./...", "go vet ./..."}, tests.Commands)
+	assert.Equal(t, "1h0m0s", tests.Timeout)
+	require.NotNil(t, tests.Retry)
+	assert.Equal(t, 2, tests.Retry.MaxRetries)
+
+	assert.Equal(t, []string{"unit-tests"}, pipeline.Steps[1].DependsOn)
+	assert.Equal(t, []string{"unit-tests", "package"}, pipeline.Steps[2].DependsOn)
+
+	warnings := strings.Join(result.Warnings, "\n")
+	assert.Contains(t, warnings, "workspaces")
+	assert.Contains(t, warnings, "kaniko-project/executor")
+	assert.Contains(t, warnings, "finally")
+
+	output, err := result.Marshal()
+	require.NoError(t, err)
+	_, err = parser.ParseBytes(output)
+	require.NoError(t, err)
+
+	// Referenced Tasks must be part of the input
+	_, err = parser.ConvertTekton([]byte(`
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: ci
+spec:
+  tasks:
+    - name: build
+      taskRef:
+        name: missing
+`))
+	assert.ErrorContains(t, err, "missing")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv/samples"
+)
+
+// TestMergeCoverageProfiles verifies overlapping blocks are combined across profiles
+func TestMergeCoverageProfiles(t *testing.T) {
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "a.out")
+	second := filepath.Join(dir, "b.out")
+	merged := filepath.Join(dir, "merged.out")
+
+	require.NoError(t, os.WriteFile(first, []byte("mode: set\n"+
+		"pkg/a.go:1.1,3.2 2 1\n"+
+		"pkg/a.go:4.1,6.2 3 0\n"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("mode: set\n"+
+		"pkg/a.go:4.1,6.2 3 1\n"+
+		"pkg/b.go:1.1,2.2 5 0\n"), 0644))
+
+	total, covered, err := samples.MergeCoverageProfiles([]string{first, second}, merged)
+	require.NoError(t, err)
+	assert.Equal(t, 10, total)
+	assert.Equal(t, 5, covered)
+
+	content, err := os.ReadFile(merged)
+	require.NoError(t, err)
+	assert.Equal(t, "mode: set\n"+
+		"pkg/a.go:1.1,3.2 2 1\n"+
+		"pkg/a.go:4.1,6.2 3 1\n"+
+		"pkg/b.go:1.1,2.2 5 0\n", string(content))
+}
+
+// TestMergeCoverageProfilesModeMismatch verifies profiles with different modes are rejected
+func TestMergeCoverageProfilesModeMismatch(t *testing.T) {
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "a.out")
+	second := filepath.Join(dir, "b.out")
+
+	require.NoError(t, os.WriteFile(first, []byte("mode: set\npkg/a.go:1.1,3.2 2 1\n"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("mode: count\npkg/a.go:1.1,3.2 2 4\n"), 0644))
+
+	_, _, err := samples.MergeCoverageProfiles([]string{first, second}, filepath.Join(dir, "merged.out"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv/health"
+	"github.com/org/c8s/pkg/types"
+)
+
+// TestNetworkCheckJob verifies the check Job is a standalone step Job
+func TestNetworkCheckJob(t *testing.T) {
+	job, err := health.NetworkCheckJob("ci", health.DefaultNetworkCheckImage, health.DefaultNetworkTargets)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ci", job.Namespace)
+	assert.Empty(t, job.OwnerReferences)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, types.ManagedByC8S, job.Labels[types.LabelManagedBy])
+
+	pod := job.Spec.Template.Spec
+	assert.Empty(t, pod.InitContainers)
+	require.Len(t, pod.Containers, 1)
+	assert.Equal(t, health.DefaultNetworkCheckImage, pod.Containers[0].Image)
+	script := pod.Containers[0].Command[2]
+	assert.Contains(t, script, "check 'ghcr.io' 'ghcr.io' 'https://ghcr.io/v2/' ''")
+	assert.Contains(t, script, "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt")
+}
+
+// TestParseNetworkResults verifies failures are classified from the
+// nslookup result and the curl exit code
+func TestParseNetworkResults(t *testing.T) {
+	targets := []health.NetworkTarget{
+		{Name: "docker.io", Host: "registry-1.docker.io"},
+		{Name: "ghcr.io", Host: "ghcr.io"},
+		{Name: "github.com", Host: "github.com"},
+		{Name: "kubernetes-api", Host: "kubernetes.default.svc.cluster.local"},
+		{Name: "quay.io", Host: "quay.io"},
+		{Name: "missing", Host: "missing.example.com"},
+	}
+	logs := `curl: (60) SSL certificate problem: self-signed certificate in certificate chain
+C8S-NET docker.io ok 0 401
+C8S-NET ghcr.io ok 60 000
+C8S-NET github.com fail 6 000
+C8S-NET kubernetes-api ok 28 000
+C8S-NET quay.io ok 56 000
+`
+
+	results := health.ParseNetworkResults(logs, targets)
+	require.Len(t, results, len(targets))
+
+	assert.Equal(t, health.NetworkCheckOK, results[0].HTTPS)
+	assert.Equal(t, 401, results[0].HTTPStatus)
+	assert.Empty(t, results[0].Failure)
+
+	var failures []health.NetworkFailure
+	for _, result := range results[1:5] {
+		assert.Equal(t, health.NetworkCheckFailed, result.HTTPS, result.Target)
+		failures = append(failures, result.Failure)
+	}
+	assert.Equal(t, []health.NetworkFailure{
+		health.NetworkFailureTLS,
+		health.NetworkFailureDNS,
+		health.NetworkFailureRouting,
+		health.NetworkFailureOther,
+	}, failures)
+	assert.Equal(t, health.NetworkCheckFailed, results[2].DNS)
+	assert.Equal(t, health.NetworkCheckSkipped, results[5].HTTPS)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+)
+
+func diffBaseSpec() *c8sv1alpha1.PipelineConfigSpec {
+	return &c8sv1alpha1.PipelineConfigSpec{
+		Repository: "https://github.com/org/repo.git",
+		Timeout:    "1h",
+		Steps: []c8sv1alpha1.PipelineStep{
+			{Name: "lint", Image: "golangci/golangci-lint", Commands: []string{"golangci-lint run"}},
+			{Name: "test", Image: "golang:1.25", Commands: []string{"go test ./..."}, DependsOn: []string{"lint"}},
+			{Name: "build", Image: "golang:1.25", Commands: []string{"go build ./..."}, DependsOn: []string{"test"},
+				Resources: &c8sv1alpha1.ResourceRequirements{CPU: "500m", Memory: "1Gi"}},
+		},
+	}
+}
+
+// TestDiffIdentical verifies identical specs produce no changes
+func TestDiffIdentical(t *testing.T) {
+	assert.Empty(t, parser.Diff(diffBaseSpec(), diffBaseSpec()))
+}
+
+// TestDiffStepReorderOnly verifies reordering steps in the list is not a semantic change
+func TestDiffStepReorderOnly(t *testing.T) {
+	newSpec := diffBaseSpec()
+	newSpec.Steps[0], newSpec.Steps[2] = newSpec.Steps[2], newSpec.Steps[0]
+
+	assert.Empty(t, parser.Diff(diffBaseSpec(), newSpec))
+}
+
+// TestDiffStepAddedRemoved verifies added and removed steps are reported
+func TestDiffStepAddedRemoved(t *testing.T) {
+	newSpec := diffBaseSpec()
+	newSpec.Steps = append(newSpec.Steps[1:], c8sv1alpha1.PipelineStep{Name: "deploy", Image: "alpine", Commands: []string{"true"}})
+	newSpec.Steps[0].DependsOn = nil
+
+	changes := parser.Diff(diffBaseSpec(), newSpec)
+	require.Len(t, changes, 4)
+	assert.Equal(t, parser.Change{Kind: parser.ChangeDependencyChanged, StepName: "test", Detail: "dependsOn [lint] -> []; execution layer 1 -> 0"}, changes[0])
+	assert.Equal(t, parser.ChangeDependencyChanged, changes[1].Kind)
+	assert.Equal(t, "build", changes[1].StepName)
+	assert.Equal(t, "execution layer 2 -> 1", changes[1].Detail)
+	assert.Equal(t, parser.ChangeStepAdded, changes[2].Kind)
+	assert.Equal(t, "deploy", changes[2].StepName)
+	assert.Equal(t, parser.Change{Kind: parser.ChangeStepRemoved, StepName: "lint", Detail: "image golangci/golangci-lint"}, changes[3])
+
+	changes = parser.Diff(newSpec, diffBaseSpec())
+	assert.Contains(t, changes, parser.Change{Kind: parser.ChangeStepRemoved, StepName: "deploy", Detail: "image alpine"})
+	assert.Contains(t, changes, parser.Change{Kind: parser.ChangeStepAdded, StepName: "lint", Detail: "image golangci/golangci-lint"})
+}
+
+// TestDiffLayerShift verifies a reordered dependency chain reports the steps whose layer moved
+func TestDiffLayerShift(t *testing.T) {
+	newSpec := diffBaseSpec()
+	// Swap the order of lint and test: test now runs first
+	newSpec.Steps[0].DependsOn = []string{"test"}
+	newSpec.Steps[1].DependsOn = nil
+	newSpec.Steps[2].DependsOn = []string{"lint"}
+
+	changes := parser.Diff(diffBaseSpec(), newSpec)
+	require.Len(t, changes, 3)
+	for _, change := range changes {
+		assert.Equal(t, parser.ChangeDependencyChanged, change.Kind)
+	}
+	assert.Equal(t, "dependsOn [] -> [test]; execution layer 0 -> 1", changes[0].Detail)
+	assert.Equal(t, "dependsOn [lint] -> []; execution layer 1 -> 0", changes[1].Detail)
+	assert.Equal(t, "dependsOn [test] -> [lint]", changes[2].Detail)
+}
+
+// TestDiffModifiedTimeoutResources verifies field-level step and pipeline changes
+func TestDiffModifiedTimeoutResources(t *testing.T) {
+	newSpec := diffBaseSpec()
+	newSpec.Timeout = "2h"
+	newSpec.Steps[1].Image = "golang:1.26"
+	newSpec.Steps[1].Timeout = "10m"
+	newSpec.Steps[2].Resources = &c8sv1alpha1.ResourceRequirements{CPU: "2", Memory: "1Gi"}
+
+	changes := parser.Diff(diffBaseSpec(), newSpec)
+	assert.Equal(t, []parser.Change{
+		{Kind: parser.ChangeTimeoutChanged, Detail: "pipeline timeout 1h -> 2h"},
+		{Kind: parser.ChangeStepModified, StepName: "test", Detail: "image golang:1.25 -> golang:1.26"},
+		{Kind: parser.ChangeTimeoutChanged, StepName: "test", Detail: "timeout <none> -> 10m"},
+		{Kind: parser.ChangeResourceChanged, StepName: "build", Detail: "cpu 500m -> 2"},
+	}, changes)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/org/c8s/pkg/api/handlers"
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/secrets"
+	"github.com/org/c8s/pkg/storage"
+)
+
+// memoryLogStorage is a StorageClient serving logs from memory
+type memoryLogStorage struct {
+	logs      map[string][]byte
+	requested []string
+
+	// copyErr fails CopyLog when set
+	copyErr error
+}
+
+func (m *memoryLogStorage) UploadLog(ctx context.Context, key string, content io.Reader, contentEncoding string) error {
+	return nil
+}
+
+func (m *memoryLogStorage) DownloadLog(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	return io.NopCloser(bytes.NewReader(m.logs[key])), "", nil
+}
+
+func (m *memoryLogStorage) GetLog(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.requested = append(m.requested, key)
+	logs, ok := m.logs[key]
+	if !ok {
+		return nil, storage.ErrDownloadFailed
+	}
+	return io.NopCloser(bytes.NewReader(logs)), nil
+}
+
+func (m *memoryLogStorage) CopyLog(ctx context.Context, srcKey, dstKey string) error {
+	if m.copyErr != nil {
+		return m.copyErr
+	}
+	logs, ok := m.logs[srcKey]
+	if !ok {
+		return storage.ErrCopyFailed
+	}
+	m.logs[dstKey] = logs
+	return nil
+}
+
+func (m *memoryLogStorage) UploadArtifact(ctx context.Context, key string, content io.Reader) error {
+	return nil
+}
+
+func (m *memoryLogStorage) DownloadArtifact(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, storage.ErrDownloadFailed
+}
+
+func (m *memoryLogStorage) GenerateSignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "", nil
+}
+
+func (m *memoryLogStorage) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for key := range m.logs {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (m *memoryLogStorage) DeleteObject(ctx context.Context, key string) error {
+	delete(m.logs, key)
+	return nil
+}
+
+func (m *memoryLogStorage) ObjectExists(ctx context.Context, key string) (bool, error) {
+	_, ok := m.logs[key]
+	return ok, nil
+}
+
+// TestMaskReader verifies secrets are masked in streamed content
+func TestMaskReader(t *testing.T) {
+	values := map[string]string{"token": "s3cr3t"}
+
+	masked := secrets.MaskReader(strings.NewReader("login s3cr3t\nno secret\nS3CR3T"), values)
+	data, err := io.ReadAll(masked)
+	require.NoError(t, err)
+	require.NoError(t, masked.Close())
+	assert.Equal(t, "login ***REDACTED***\nno secret\n***REDACTED***", string(data))
+
+	// Closing early stops masking without reading the rest
+	masked = secrets.MaskReader(strings.NewReader(strings.Repeat("s3cr3t\n", 10000)), values)
+	_, err = io.ReadFull(masked, make([]byte, 10))
+	require.NoError(t, err)
+	require.NoError(t, masked.Close())
+}
+
+// TestHandleStepLogs_Direct verifies ?direct=true streams stored logs with
+// the step's secrets masked
+func TestHandleStepLogs_Direct(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"},
+		Spec:       c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "app"},
+		Status: c8sv1alpha1.PipelineRunStatus{
+			Steps: []c8sv1alpha1.StepStatus{{
+				Name:   "deploy",
+				Phase:  c8sv1alpha1.StepPhaseSucceeded,
+				LogURL: "s3://c8s-logs/default/run-1/deploy.log",
+			}},
+		},
+	}
+	config := &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Steps: []c8sv1alpha1.PipelineStep{{
+				Name:    "deploy",
+				Secrets: []c8sv1alpha1.SecretReference{{SecretRef: "deploy-token", Key: "token"}},
+			}},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("rotated-token")},
+	}
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(run, config, secret).Build()
+
+	logStorage := &memoryLogStorage{logs: map[string][]byte{
+		"default/run-1/deploy.log": []byte("using rotated-token\ndeployed\n"),
+	}}
+	h := handlers.NewLogsHandler(nil, c, logStorage)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/default/pipelineruns/run-1/logs/deploy?direct=true", nil)
+	rec := httptest.NewRecorder()
+	h.HandleStepLogs(rec, req)
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "using ***REDACTED***\ndeployed\n", rec.Body.String())
+	assert.Equal(t, []string{"default/run-1/deploy.log"}, logStorage.requested)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+// TestResolveEnvironment verifies the run environment overrides the PipelineConfig
+// default and development applies when neither is set
+func TestResolveEnvironment(t *testing.T) {
+	config := &c8sv1alpha1.PipelineConfig{Spec: c8sv1alpha1.PipelineConfigSpec{Environment: c8sv1alpha1.EnvironmentStaging}}
+
+	run := &c8sv1alpha1.PipelineRun{}
+	assert.Equal(t, c8sv1alpha1.EnvironmentStaging, controller.ResolveEnvironment(run, config))
+
+	run.Spec.Environment = c8sv1alpha1.EnvironmentProduction
+	assert.Equal(t, c8sv1alpha1.EnvironmentProduction, controller.ResolveEnvironment(run, config))
+
+	assert.Equal(t, c8sv1alpha1.EnvironmentDevelopment,
+		controller.ResolveEnvironment(&c8sv1alpha1.PipelineRun{}, &c8sv1alpha1.PipelineConfig{}))
+}
+
+// TestStepRunsInEnvironment verifies steps only run in their listed environments
+func TestStepRunsInEnvironment(t *testing.T) {
+	unrestricted := &c8sv1alpha1.PipelineStep{Name: "build"}
+	assert.True(t, controller.StepRunsInEnvironment(unrestricted, c8sv1alpha1.EnvironmentProduction))
+
+	deploy := &c8sv1alpha1.PipelineStep{
+		Name:        "deploy",
+		Conditional: &c8sv1alpha1.ConditionalExecution{Environment: []string{"staging", "production"}},
+	}
+	assert.True(t, controller.StepRunsInEnvironment(deploy, c8sv1alpha1.EnvironmentStaging))
+	assert.False(t, controller.StepRunsInEnvironment(deploy, c8sv1alpha1.EnvironmentDevelopment))
+}
+
+// TestMarkStepSkipped verifies skipped steps are recorded once and never
+// overwrite an existing status
+func TestMarkStepSkipped(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{Status: c8sv1alpha1.PipelineRunStatus{Steps: []c8sv1alpha1.StepStatus{
+		{Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded},
+	}}}
+
+	controller.MarkStepSkipped(run, "deploy", c8sv1alpha1.EnvironmentDevelopment)
+	controller.MarkStepSkipped(run, "deploy", c8sv1alpha1.EnvironmentDevelopment)
+	controller.MarkStepSkipped(run, "build", c8sv1alpha1.EnvironmentDevelopment)
+
+	require.Len(t, run.Status.Steps, 2)
+	assert.Equal(t, c8sv1alpha1.StepPhaseSucceeded, run.Status.Steps[0].Phase)
+	assert.Equal(t, c8sv1alpha1.StepPhaseSkipped, run.Status.Steps[1].Phase)
+	assert.Contains(t, run.Status.Steps[1].Message, "development")
+}
+
+// TestReconcileAllStepsSkipped verifies a run whose steps are all restricted to
+// other environments succeeds without creating Jobs
+func TestReconcileAllStepsSkipped(t *testing.T) {
+	config := &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/example-org/example-repo",
+			Steps: []c8sv1alpha1.PipelineStep{{
+				Name:        "deploy",
+				Image:       "alpine:3.20",
+				Commands:    []string{"./deploy.sh"},
+				Conditional: &c8sv1alpha1.ConditionalExecution{Environment: []string{"production"}},
+			}},
+		},
+	}
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default", Finalizers: []string{types.FinalizerPipelineRun}},
+		Spec:       c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "config", Commit: "abc1234"},
+	}
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(config, run).WithStatusSubresource(run).Build()
+	reconciler := &controller.PipelineRunReconciler{Client: c, Scheme: s}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(run)}
+	ctx := context.Background()
+
+	// The first pass initializes the run's phase
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, run))
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseSucceeded, run.Status.Phase)
+	assert.NotNil(t, run.Status.CompletionTime)
+	status := controller.GetStepStatus(run, "deploy")
+	require.NotNil(t, status)
+	assert.Equal(t, c8sv1alpha1.StepPhaseSkipped, status.Phase)
+
+	jobs := &batchv1.JobList{}
+	require.NoError(t, c.List(ctx, jobs))
+	assert.Empty(t, jobs.Items)
+}
+
+// TestCreateJobForStepEnvironment verifies step containers get C8S_ENVIRONMENT
+func TestCreateJobForStepEnvironment(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"},
+		Spec:       c8sv1alpha1.PipelineRunSpec{Commit: "abc1234", Branch: "main"},
+	}
+	config := &c8sv1alpha1.PipelineConfig{Spec: c8sv1alpha1.PipelineConfigSpec{Environment: c8sv1alpha1.EnvironmentStaging}}
+	step := &c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.25", Commands: []string{"go build ./..."}}
+
+	job, err := controller.NewJobManager("https://github.com/org/repo.git").CreateJobForStep(step, run, config)
+	require.NoError(t, err)
+
+	env := map[string]string{}
+	for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	assert.Equal(t, c8sv1alpha1.EnvironmentStaging, env[types.EnvEnvironment])
+}
+
+// TestParseConditionalEnvironment verifies conditional.environment is parsed and validated
+func TestParseConditionalEnvironment(t *testing.T) {
+	spec, err := parser.ParseBytes([]byte(`version: v1alpha1
+name: app
+steps:
+  - name: deploy
+    image: alpine:3.20
+    commands: ["./deploy.sh"]
+    conditional:
+      environment: [staging, production]
+`))
+	require.NoError(t, err)
+	require.NotNil(t, spec.Steps[0].Conditional)
+	assert.Equal(t, []string{"staging", "production"}, spec.Steps[0].Conditional.Environment)
+
+	config := &c8sv1alpha1.PipelineConfig{Spec: *spec}
+	config.Spec.Repository = "https://github.com/org/repo.git"
+	require.NoError(t, parser.Validate(config))
+
+	config.Spec.Steps[0].Conditional.Environment = []string{"qa"}
+	config.Spec.Environment = "prod"
+	err = parser.Validate(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid environment "qa"`)
+	assert.Contains(t, err.Error(), `invalid environment "prod"`)
+}
+
+// TestWebhookRunEnvironmentFromNamespace verifies webhook-created runs take the
+// environment of their namespace label
+func TestWebhookRunEnvironmentFromNamespace(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := webhookTestClient(t, webhook.DefaultTestRepositoryURL(webhook.ProviderGitHub, "example-org/example-repo"), "s3cr3t")
+	require.NoError(t, k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "default",
+		Labels: map[string]string{types.LabelEnvironment: c8sv1alpha1.EnvironmentProduction},
+	}}))
+
+	req, err := webhook.NewTestPushRequest(ctx, "http://localhost/webhooks/github", webhook.ProviderGitHub,
+		webhook.TestPushEvent{Repository: "example-org/example-repo", Branch: "main", Commit: testWebhookCommit, Author: "dev"},
+		"s3cr3t")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	webhook.NewGitHubHandler(k8sClient).Handle(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	run := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-" + testWebhookCommit[:8]}, run))
+	assert.Equal(t, c8sv1alpha1.EnvironmentProduction, run.Spec.Environment)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// readLogBundle extracts the files of a log bundle
+func readLogBundle(t *testing.T, bundle []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(content)
+	}
+	return files
+}
+
+// TestBuildLogBundleMasksSecrets verifies secret values never reach the bundle
+func TestBuildLogBundleMasksSecrets(t *testing.T) {
+	files := []cluster.BundleFile{
+		{Name: "operator/c8s-system/c8s-controller.log", Content: []byte("cloning with token ghp_supersecret\n")},
+		{Name: "resources/pipelineruns.yaml", Content: []byte("password: hunter22\n")},
+	}
+	secretValues := map[string]string{
+		"default/git/token":   "ghp_supersecret",
+		"default/db/password": "hunter22",
+	}
+
+	bundle, trimmed, err := cluster.BuildLogBundle(files, secretValues, cluster.DefaultLogBundleMaxSize)
+	require.NoError(t, err)
+	assert.False(t, trimmed)
+
+	contents := readLogBundle(t, bundle)
+	require.Len(t, contents, 2)
+	assert.Equal(t, "cloning with token ***REDACTED***\n", contents["operator/c8s-system/c8s-controller.log"])
+	assert.Equal(t, "password: ***REDACTED***\n", contents["resources/pipelineruns.yaml"])
+}
+
+// TestBuildLogBundleTrimsLogs verifies logs are cut to their tail to fit the size limit
+func TestBuildLogBundleTrimsLogs(t *testing.T) {
+	// Random data does not compress, so the bundle is about the size of the log
+	noise := make([]byte, 256*1024)
+	_, err := rand.Read(noise)
+	require.NoError(t, err)
+	log := hex.EncodeToString(noise) + "\nlast line\n"
+
+	files := []cluster.BundleFile{
+		{Name: "steps/default/build.log", Content: []byte(log)},
+		{Name: "events.txt", Content: []byte("event\n")},
+	}
+
+	bundle, trimmed, err := cluster.BuildLogBundle(files, nil, 200*1024)
+	require.NoError(t, err)
+	assert.True(t, trimmed)
+	assert.LessOrEqual(t, len(bundle), 200*1024)
+
+	contents := readLogBundle(t, bundle)
+	assert.True(t, strings.HasPrefix(contents["steps/default/build.log"], "[trimmed to the last"))
+	assert.True(t, strings.HasSuffix(contents["steps/default/build.log"], "last line\n"))
+	assert.Equal(t, "event\n", contents["events.txt"])
+
+	_, _, err = cluster.BuildLogBundle(files, nil, 1024)
+	assert.Error(t, err)
+}
+
+// TestFormatRecentEvents verifies only recent events are kept, oldest first
+func TestFormatRecentEvents(t *testing.T) {
+	events := []byte(`{"items": [
+		{"metadata": {"namespace": "default"}, "involvedObject": {"kind": "Pod", "name": "build"},
+		 "type": "Warning", "reason": "BackOff", "message": "Back-off restarting", "lastTimestamp": "2025-01-01T12:50:00Z"},
+		{"metadata": {"namespace": "default"}, "involvedObject": {"kind": "Job", "name": "build"},
+		 "type": "Normal", "reason": "SuccessfulCreate", "message": "Created pod", "lastTimestamp": null, "eventTime": "2025-01-01T12:30:00Z"},
+		{"metadata": {"namespace": "default"}, "involvedObject": {"kind": "Pod", "name": "old"},
+		 "type": "Normal", "reason": "Scheduled", "message": "Assigned", "lastTimestamp": "2025-01-01T10:00:00Z"}
+	]}`)
+
+	output, err := cluster.FormatRecentEvents(events, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "2025-01-01T12:30:00Z Normal   default Job/build SuccessfulCreate: Created pod", lines[0])
+	assert.Equal(t, "2025-01-01T12:50:00Z Warning  default Pod/build BackOff: Back-off restarting", lines[1])
+}
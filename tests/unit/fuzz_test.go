@@ -0,0 +1,55 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv/fuzz"
+)
+
+// fuzzCrashOutput is the output of go test -fuzz for a fuzz test that panicked
+const fuzzCrashOutput = `fuzz: elapsed: 0s, gathering baseline coverage: 0/14 completed
+fuzz: elapsed: 0s, gathering baseline coverage: 14/14 completed, now fuzzing with 8 workers
+fuzz: minimizing 35-byte failing input file
+fuzz: elapsed: 0s, minimizing
+--- FAIL: FuzzParse (0.06s)
+    --- FAIL: FuzzParse (0.00s)
+        testing.go:2076: panic: runtime error: index out of range [0] with length 0
+            goroutine 1207 [running]:
+            runtime/debug.Stack()
+
+    Failing input written to testdata/fuzz/FuzzParse/1a4feb8ad1fe53b6
+    To re-run:
+    go test -run=FuzzParse/1a4feb8ad1fe53b6
+FAIL
+exit status 1
+FAIL	github.com/org/c8s/tests/fuzz	0.060s
+`
+
+// TestParseCrashes verifies the failing input and its panic are read from go test output
+func TestParseCrashes(t *testing.T) {
+	crashes := fuzz.ParseCrashes(fuzzCrashOutput)
+	require.Len(t, crashes, 1)
+	assert.Equal(t, "testdata/fuzz/FuzzParse/1a4feb8ad1fe53b6", crashes[0].Input)
+	assert.Equal(t, "panic: runtime error: index out of range [0] with length 0", crashes[0].Message)
+
+	assert.Empty(t, fuzz.ParseCrashes("fuzz: elapsed: 20s, execs: 50894 (1213/sec)\nPASS\nok  \tgithub.com/org/c8s/tests/fuzz\t20.028s\n"))
+}
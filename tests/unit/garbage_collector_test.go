@@ -0,0 +1,98 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+)
+
+// gcTestRun returns a running PipelineRun with a step in each phase
+func gcTestRun() *c8sv1alpha1.PipelineRun {
+	return &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-1", Namespace: "default"},
+		Status: c8sv1alpha1.PipelineRunStatus{
+			Phase: c8sv1alpha1.PipelineRunPhaseRunning,
+			Steps: []c8sv1alpha1.StepStatus{
+				{Name: "checkout", Phase: c8sv1alpha1.StepPhaseSucceeded, JobName: "ci-1-checkout"},
+				{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "ci-1-build"},
+				{Name: "lint", Phase: c8sv1alpha1.StepPhasePending, JobName: "ci-1-lint"},
+				{Name: "deploy", Phase: c8sv1alpha1.StepPhaseSkipped, Message: "not run in environment development"},
+			},
+		},
+	}
+}
+
+// gcTestJobs returns Jobs for the given steps
+func gcTestJobs(steps ...string) map[string]*batchv1.Job {
+	jobs := make(map[string]*batchv1.Job, len(steps))
+	for _, step := range steps {
+		jobs[step] = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "ci-1-" + step}}
+	}
+	return jobs
+}
+
+// TestGarbageCollector_NoOrphans verifies a run whose Jobs all exist is unchanged
+func TestGarbageCollector_NoOrphans(t *testing.T) {
+	run := gcTestRun()
+	gc := controller.NewPipelineRunGarbageCollector()
+
+	// Finished steps don't need their Job anymore
+	assert.Empty(t, gc.Collect(run, gcTestJobs("build", "lint")))
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseRunning, run.Status.Phase)
+	assert.Equal(t, c8sv1alpha1.StepPhaseRunning, run.Status.Steps[1].Phase)
+}
+
+// TestGarbageCollector_DeletedJob verifies steps in progress whose Job was
+// deleted are failed along with the run
+func TestGarbageCollector_DeletedJob(t *testing.T) {
+	run := gcTestRun()
+	gc := controller.NewPipelineRunGarbageCollector()
+
+	assert.Equal(t, []string{"build"}, gc.OrphanedSteps(run, gcTestJobs("lint")))
+
+	orphaned := gc.Collect(run, gcTestJobs())
+	assert.Equal(t, []string{"build", "lint"}, orphaned)
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseFailed, run.Status.Phase)
+
+	for _, step := range run.Status.Steps[1:3] {
+		assert.Equal(t, c8sv1alpha1.StepPhaseFailed, step.Phase, step.Name)
+		assert.Equal(t, controller.MessageJobDeletedExternally, step.Message, step.Name)
+		assert.NotNil(t, step.CompletionTime, step.Name)
+	}
+	assert.Equal(t, c8sv1alpha1.StepPhaseSucceeded, run.Status.Steps[0].Phase)
+	assert.Equal(t, c8sv1alpha1.StepPhaseSkipped, run.Status.Steps[3].Phase)
+}
+
+// TestGarbageCollector_HasRecordedJob verifies only steps in progress with a
+// recorded Job are protected from getting a new Job
+func TestGarbageCollector_HasRecordedJob(t *testing.T) {
+	run := gcTestRun()
+	gc := controller.NewPipelineRunGarbageCollector()
+
+	assert.True(t, gc.HasRecordedJob(run, "build"))
+	assert.True(t, gc.HasRecordedJob(run, "lint"))
+	assert.False(t, gc.HasRecordedJob(run, "checkout"))
+	assert.False(t, gc.HasRecordedJob(run, "deploy"))
+	assert.False(t, gc.HasRecordedJob(run, "test"))
+}
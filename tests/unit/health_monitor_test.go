@@ -0,0 +1,172 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/org/c8s/pkg/localenv/health"
+)
+
+// healthNode returns a node with the given condition statuses
+func healthNode(name string, conditions map[corev1.NodeConditionType]corev1.ConditionStatus) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for conditionType, status := range conditions {
+		node.Status.Conditions = append(node.Status.Conditions, corev1.NodeCondition{Type: conditionType, Status: status})
+	}
+	return node
+}
+
+// TestCheckNodeConditions verifies nodes that are not ready or under
+// pressure are reported
+func TestCheckNodeConditions(t *testing.T) {
+	checker := health.NewChecker()
+
+	client := fake.NewSimpleClientset(
+		healthNode("server-0", map[corev1.NodeConditionType]corev1.ConditionStatus{corev1.NodeReady: corev1.ConditionTrue}),
+		healthNode("agent-0", map[corev1.NodeConditionType]corev1.ConditionStatus{corev1.NodeReady: corev1.ConditionTrue}),
+	)
+	result := checker.CheckNodeConditions(context.Background(), client)
+	assert.True(t, result.Healthy, result.Message)
+	assert.Equal(t, "2 node(s) ready", result.Message)
+
+	client = fake.NewSimpleClientset(
+		healthNode("server-0", map[corev1.NodeConditionType]corev1.ConditionStatus{
+			corev1.NodeReady:        corev1.ConditionTrue,
+			corev1.NodeDiskPressure: corev1.ConditionTrue,
+		}),
+		healthNode("agent-0", map[corev1.NodeConditionType]corev1.ConditionStatus{corev1.NodeReady: corev1.ConditionUnknown}),
+	)
+	result = checker.CheckNodeConditions(context.Background(), client)
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Message, "server-0 (DiskPressure)")
+	assert.Contains(t, result.Message, "agent-0 (not ready)")
+
+	result = checker.CheckNodeConditions(context.Background(), fake.NewSimpleClientset())
+	assert.False(t, result.Healthy)
+}
+
+// TestCheckAPIServerAndMetricsServer verifies the API server version and
+// the metrics API are checked through discovery
+func TestCheckAPIServerAndMetricsServer(t *testing.T) {
+	checker := health.NewChecker()
+	client := fake.NewSimpleClientset()
+
+	result := checker.CheckAPIServer(context.Background(), client)
+	assert.True(t, result.Healthy, result.Message)
+
+	result = checker.CheckMetricsServer(context.Background(), client)
+	assert.False(t, result.Healthy)
+
+	client.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "metrics.k8s.io/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "nodes"}, {Name: "pods"}},
+	}}
+	result = checker.CheckMetricsServer(context.Background(), client)
+	assert.True(t, result.Healthy, result.Message)
+}
+
+// TestMonitor verifies failures are remediated once per streak and alerted
+// every threshold failed rounds
+func TestMonitor(t *testing.T) {
+	// Rounds 2-6 fail, round 7 recovers, round 8 fails again
+	failing := map[int]bool{2: true, 3: true, 4: true, 5: true, 6: true, 8: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var rounds []*health.MonitorRound
+	calls, remediations, alerts := 0, []int{}, []int{}
+	err := health.Monitor(ctx, health.MonitorOptions{
+		Interval:       time.Millisecond,
+		AlertThreshold: 2,
+		Check: func(ctx context.Context) []health.CheckResult {
+			calls++
+			return []health.CheckResult{
+				{Name: "API server", Healthy: true},
+				{Name: "Operator", Healthy: !failing[calls]},
+			}
+		},
+		Remediate: func(ctx context.Context, failed []health.CheckResult) ([]string, error) {
+			require.Len(t, failed, 1)
+			assert.Equal(t, "Operator", failed[0].Name)
+			remediations = append(remediations, calls)
+			return []string{"restarted operator"}, nil
+		},
+		OnAlert: func(ctx context.Context, round *health.MonitorRound) error {
+			alerts = append(alerts, round.Number)
+			return nil
+		},
+		OnRound: func(round *health.MonitorRound) {
+			rounds = append(rounds, round)
+			if round.Number == 8 {
+				cancel()
+			}
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, rounds, 8)
+	assert.Equal(t, []int{2, 8}, remediations)
+	assert.Equal(t, []int{3, 5}, alerts)
+	assert.True(t, rounds[0].Healthy)
+	assert.Equal(t, 5, rounds[5].ConsecutiveFailures)
+	assert.True(t, rounds[6].Healthy)
+	assert.Zero(t, rounds[6].ConsecutiveFailures)
+	assert.Equal(t, []string{"restarted operator"}, rounds[1].Remediations)
+	assert.True(t, rounds[2].Alerted)
+}
+
+// TestMonitor_InvalidOptions verifies the interval and threshold must be positive
+func TestMonitor_InvalidOptions(t *testing.T) {
+	check := func(ctx context.Context) []health.CheckResult { return nil }
+	assert.Error(t, health.Monitor(context.Background(), health.MonitorOptions{AlertThreshold: 1, Check: check}))
+	assert.Error(t, health.Monitor(context.Background(), health.MonitorOptions{Interval: time.Second, Check: check}))
+}
+
+// TestAlertCommand verifies the failure command gets the failed checks in
+// its environment and its failure is reported
+func TestAlertCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "alert")
+	round := &health.MonitorRound{
+		ConsecutiveFailures: 3,
+		Checks: []health.CheckResult{
+			{Name: "API server", Healthy: true},
+			{Name: "Operator"},
+			{Name: "Nodes"},
+		},
+	}
+
+	alert := health.AlertCommand(`echo "$C8S_CLUSTER $C8S_HEALTH_FAILED_CHECKS $C8S_HEALTH_CONSECUTIVE_FAILURES" > `+out,
+		map[string]string{"C8S_CLUSTER": "c8s-dev"})
+	require.NoError(t, alert(context.Background(), round))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "c8s-dev Operator,Nodes 3\n", string(data))
+
+	assert.Error(t, health.AlertCommand("exit 3", nil)(context.Background(), round))
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/types"
+)
+
+// TestTruncateJobName_ShortNamesUnchanged verifies names within the limit are joined as-is
+func TestTruncateJobName_ShortNamesUnchanged(t *testing.T) {
+	assert.Equal(t, "run-1-build", controller.TruncateJobName("run-1", "build"))
+
+	// Exactly at the limit
+	runName := strings.Repeat("r", 57)
+	assert.Equal(t, runName+"-build", controller.TruncateJobName(runName, "build"))
+	assert.Equal(t, controller.GetJobForStep(runName, "build"), controller.TruncateJobName(runName, "build"))
+}
+
+// TestTruncateJobName_LongNames verifies long names are truncated to the limit
+func TestTruncateJobName_LongNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		runName  string
+		stepName string
+	}{
+		{name: "long run name", runName: strings.Repeat("r", 100), stepName: "build"},
+		{name: "both names at maximum", runName: strings.Repeat("r", 63), stepName: strings.Repeat("s", 63)},
+		{name: "step name of 54 characters", runName: "run-1", stepName: strings.Repeat("s", 54)},
+		{name: "step name longer than 55 characters", runName: "run-1", stepName: strings.Repeat("s", 56)},
+		{name: "extremely long step name", runName: "run-1", stepName: strings.Repeat("s", 300)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobName := controller.TruncateJobName(tt.runName, tt.stepName)
+			assert.LessOrEqual(t, len(jobName), types.JobNameMaxLength)
+			assert.Equal(t, jobName, controller.TruncateJobName(tt.runName, tt.stepName), "truncation must be deterministic")
+		})
+	}
+}
+
+// TestTruncateJobName_KeepsStepName verifies the full step name is kept when it fits
+func TestTruncateJobName_KeepsStepName(t *testing.T) {
+	jobName := controller.TruncateJobName(strings.Repeat("r", 100), "integration-test")
+
+	assert.Len(t, jobName, types.JobNameMaxLength)
+	assert.True(t, strings.HasSuffix(jobName, "-integration-test"))
+	assert.True(t, strings.HasPrefix(jobName, strings.Repeat("r", 38)))
+}
+
+// TestTruncateJobName_AvoidsCollisions verifies long names sharing a prefix produce distinct Jobs
+func TestTruncateJobName_AvoidsCollisions(t *testing.T) {
+	prefix := strings.Repeat("nightly-build-", 5)
+
+	first := controller.TruncateJobName(prefix+"run-1", "test")
+	second := controller.TruncateJobName(prefix+"run-2", "test")
+	assert.NotEqual(t, first, second)
+
+	longStep := strings.Repeat("s", 60)
+	assert.NotEqual(t,
+		controller.TruncateJobName("run-1", longStep+"-unit"),
+		controller.TruncateJobName("run-1", longStep+"-e2e"))
+	assert.NotEqual(t,
+		controller.TruncateJobName("run-1", longStep),
+		controller.TruncateJobName("run-2", longStep))
+}
+
+// TestJobNameForIncludedStep verifies included step names produce valid Job names and labels
+func TestJobNameForIncludedStep(t *testing.T) {
+	assert.Equal(t, "run-1-shared-scan", controller.GetJobForStep("run-1", "shared/scan"))
+	assert.Equal(t, "shared.scan", controller.StepLabelValue("shared/scan"))
+	assert.Equal(t, "build", controller.StepLabelValue("build"))
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+)
+
+// TestCreateJobForStepTimeout verifies the Job deadline follows the step, then the PipelineConfig default timeout
+func TestCreateJobForStepTimeout(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+	config := &c8sv1alpha1.PipelineConfig{Spec: c8sv1alpha1.PipelineConfigSpec{DefaultStepTimeout: "15m"}}
+	step := &c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.25", Commands: []string{"go build ./..."}}
+	jm := controller.NewJobManager("https://github.com/org/repo.git")
+
+	job, err := jm.CreateJobForStep(step, run, config)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15*60), *job.Spec.ActiveDeadlineSeconds)
+
+	step.Timeout = "5m"
+	job, err = jm.CreateJobForStep(step, run, config)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5*60), *job.Spec.ActiveDeadlineSeconds)
+
+	step.Timeout = ""
+	job, err = jm.CreateJobForStep(step, run, &c8sv1alpha1.PipelineConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(30*60), *job.Spec.ActiveDeadlineSeconds)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// writeKubeconfig writes a kubeconfig with the given contexts and points KUBECONFIG at it
+func writeKubeconfig(t *testing.T, current string, contexts ...string) string {
+	t.Helper()
+
+	config := clientcmdapi.NewConfig()
+	for _, name := range contexts {
+		config.Clusters[name] = &clientcmdapi.Cluster{Server: "https://127.0.0.1:6443"}
+		config.AuthInfos[name] = &clientcmdapi.AuthInfo{}
+		config.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name}
+	}
+	config.CurrentContext = current
+
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, clientcmd.WriteToFile(*config, path))
+	t.Setenv("KUBECONFIG", path)
+	return path
+}
+
+// TestSwitchContext verifies the current context is written to the KUBECONFIG file
+func TestSwitchContext(t *testing.T) {
+	path := writeKubeconfig(t, "kind-other", "kind-other", "k3d-c8s-dev")
+
+	require.NoError(t, cluster.SwitchContext(cluster.KubeContextName("c8s-dev")))
+
+	config, err := clientcmd.LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "k3d-c8s-dev", config.CurrentContext)
+
+	assert.Error(t, cluster.SwitchContext("k3d-missing"))
+}
+
+// TestSwitchFromDeletedContexts verifies the current context moves off deleted clusters
+func TestSwitchFromDeletedContexts(t *testing.T) {
+	path := writeKubeconfig(t, "k3d-old", "k3d-old", "kind-other", "k3d-next")
+
+	current, err := cluster.SwitchFromDeletedContexts("k3d-old")
+	require.NoError(t, err)
+	assert.Equal(t, "k3d-next", current)
+
+	config, err := clientcmd.LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "k3d-next", config.CurrentContext)
+
+	// The current context is kept when another cluster is deleted
+	current, err = cluster.SwitchFromDeletedContexts("k3d-gone")
+	require.NoError(t, err)
+	assert.Equal(t, "k3d-next", current)
+}
+
+// TestNextContextClears verifies the current context is cleared when no contexts remain
+func TestNextContextClears(t *testing.T) {
+	config := clientcmdapi.NewConfig()
+	config.Contexts["k3d-c8s-dev"] = &clientcmdapi.Context{}
+	config.CurrentContext = "k3d-c8s-dev"
+
+	assert.Equal(t, "", cluster.NextContext(config, []string{"k3d-c8s-dev"}))
+}
+
+// TestListClusterContexts verifies only k3d contexts are listed, with the current one marked
+func TestListClusterContexts(t *testing.T) {
+	writeKubeconfig(t, "k3d-c8s-dev", "kind-other", "k3d-c8s-dev", "k3d-ci")
+
+	contexts, err := cluster.ListClusterContexts()
+	require.NoError(t, err)
+	assert.Equal(t, []cluster.ClusterContext{
+		{Name: "k3d-c8s-dev", Cluster: "c8s-dev", Current: true},
+		{Name: "k3d-ci", Cluster: "ci"},
+	}, contexts)
+}
+
+// TestUsePreviousContext verifies switching records the replaced context in
+// ~/.c8s/config.yaml and --previous switches back to it
+func TestUsePreviousContext(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := writeKubeconfig(t, "kind-other", "kind-other", "k3d-c8s-dev")
+
+	_, err := cluster.UsePreviousContext()
+	assert.Error(t, err)
+
+	previous, err := cluster.UseContext("k3d-c8s-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "kind-other", previous)
+
+	content, err := os.ReadFile(filepath.Join(home, ".c8s", "config.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "previousContext: kind-other")
+
+	current, err := cluster.UsePreviousContext()
+	require.NoError(t, err)
+	assert.Equal(t, "kind-other", current)
+
+	config, err := clientcmd.LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "kind-other", config.CurrentContext)
+
+	// Switching back records the context it left, so --previous toggles
+	current, err = cluster.UsePreviousContext()
+	require.NoError(t, err)
+	assert.Equal(t, "k3d-c8s-dev", current)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv/samples"
+)
+
+const lintAllValidPipeline = `
+version: v1alpha1
+name: test-pipeline
+steps:
+  - name: test
+    image: golang:1.25
+    commands:
+      - go test ./...
+`
+
+// lintAllInvalidPipeline depends on an undefined step
+const lintAllInvalidPipeline = `
+version: v1alpha1
+name: broken
+steps:
+  - name: build
+    image: golang:1.25
+    dependsOn: [test]
+    commands:
+      - go build ./...
+`
+
+// writeLintAllTree writes pipeline files in a monorepo layout under a
+// temporary directory and returns it
+func writeLintAllTree(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for path, content := range files {
+		path = filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return root
+}
+
+// TestLintPipelineFiles verifies every pipeline file is found and invalid
+// files are reported with their errors
+func TestLintPipelineFiles(t *testing.T) {
+	root := writeLintAllTree(t, map[string]string{
+		".c8s.yaml":                 lintAllValidPipeline,
+		"services/api/.c8s.yml":     lintAllValidPipeline,
+		"services/web/.c8s.yaml":    lintAllInvalidPipeline,
+		"services/web/ci.c8s.yaml":  lintAllValidPipeline,
+		"services/web/values.yaml":  lintAllInvalidPipeline,
+		".git/hooks/.c8s.yaml":      lintAllInvalidPipeline,
+		"tools/gen/broken.c8s.yaml": "steps: [",
+	})
+
+	result, err := samples.LintPipelineFiles(root, nil)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, file := range result.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{
+		".c8s.yaml",
+		filepath.Join("services", "api", ".c8s.yml"),
+		filepath.Join("services", "web", ".c8s.yaml"),
+		filepath.Join("services", "web", "ci.c8s.yaml"),
+		filepath.Join("tools", "gen", "broken.c8s.yaml"),
+	}, paths)
+	assert.Equal(t, 3, result.Passed)
+	assert.Equal(t, 2, result.Failed)
+
+	assert.True(t, result.Files[0].Valid)
+	assert.Equal(t, 1, result.Files[0].Steps)
+
+	invalid := result.Files[2]
+	assert.False(t, invalid.Valid)
+	require.Len(t, invalid.Errors, 1)
+	assert.Contains(t, invalid.Errors[0], "dependency test not found")
+	assert.False(t, result.Files[4].Valid)
+	assert.NotEmpty(t, result.Files[4].Errors)
+}
+
+// TestLintPipelineFiles_IgnorePatterns verifies directories are skipped by
+// name or relative path
+func TestLintPipelineFiles_IgnorePatterns(t *testing.T) {
+	root := writeLintAllTree(t, map[string]string{
+		".c8s.yaml":                       lintAllValidPipeline,
+		"vendor/lib/.c8s.yaml":            lintAllInvalidPipeline,
+		"services/testdata/.c8s.yaml":     lintAllInvalidPipeline,
+		"services/api/.c8s.yaml":          lintAllValidPipeline,
+		"services/api/fixtures/.c8s.yaml": lintAllInvalidPipeline,
+	})
+
+	result, err := samples.LintPipelineFiles(root, []string{"vendor", "testdata", "services/*/fixtures"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Passed)
+	assert.Zero(t, result.Failed)
+
+	_, err = samples.LintPipelineFiles(root, []string{"["})
+	assert.ErrorContains(t, err, "invalid ignore pattern")
+}
+
+// TestLintPipelineFiles_Empty verifies a tree without pipeline files passes
+func TestLintPipelineFiles_Empty(t *testing.T) {
+	result, err := samples.LintPipelineFiles(t.TempDir(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Files)
+	assert.Zero(t, result.Failed)
+
+	_, err = samples.LintPipelineFiles(filepath.Join(t.TempDir(), "missing"), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/deploy"
+)
+
+// deployedConfig returns a PipelineConfig as read from the cluster with the given steps
+func deployedConfig(name, namespace string, steps ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": c8sv1alpha1.GroupVersion.String(),
+		"kind":       "PipelineConfig",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"repository": "https://github.com/org/repo",
+			"steps":      steps,
+		},
+	}}
+}
+
+// deployedStep returns a step of a deployed PipelineConfig
+func deployedStep(name string, dependsOn ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      name,
+		"image":     "golang:1.25",
+		"commands":  []interface{}{"go build ./..."},
+		"dependsOn": dependsOn,
+	}
+}
+
+// TestValidateDeployedConfigs verifies each config of the namespace is validated and
+// every error of an invalid config is reported
+func TestValidateDeployedConfigs(t *testing.T) {
+	client := newBackupClient(
+		deployedConfig("valid", "default", deployedStep("build"), deployedStep("test", "build")),
+		deployedConfig("legacy", "default", deployedStep("build", "missing"), deployedStep("build")),
+		deployedConfig("other", "team-a", deployedStep("build", "missing")),
+	)
+
+	results, err := deploy.ValidateDeployedConfigs(context.Background(), client, "default")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	byName := map[string]deploy.ConfigValidation{}
+	for _, result := range results {
+		byName[result.Name] = result
+	}
+
+	assert.True(t, byName["valid"].Valid)
+	assert.Empty(t, byName["valid"].Errors)
+
+	legacy := byName["legacy"]
+	assert.False(t, legacy.Valid)
+	assert.Equal(t, "default", legacy.Namespace)
+	require.Len(t, legacy.Errors, 2)
+	assert.Contains(t, legacy.Errors[0], "duplicate step name: build")
+	assert.Contains(t, legacy.Errors[1], "non-existent step: missing")
+}
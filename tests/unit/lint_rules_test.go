@@ -0,0 +1,103 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// lintedStep returns a step passing every built-in lint rule
+func lintedStep(name string) c8sv1alpha1.PipelineStep {
+	return c8sv1alpha1.PipelineStep{
+		Name:      name,
+		Image:     "golang:1.25",
+		Commands:  []string{"go build ./..."},
+		Resources: &c8sv1alpha1.ResourceRequirements{CPU: "500m", Memory: "1Gi"},
+	}
+}
+
+// TestBuiltinLintRules verifies each built-in rule reports the steps it targets
+func TestBuiltinLintRules(t *testing.T) {
+	untagged := lintedStep("untagged")
+	untagged.Image = "registry.localhost:5000/tools/builder"
+	latest := lintedStep("latest")
+	latest.Image = "alpine:latest"
+	digest := lintedStep("digest")
+	digest.Image = "alpine@sha256:0123"
+	noResources := lintedStep("no-resources")
+	noResources.Resources = nil
+	manyCommands := lintedStep("many-commands")
+	manyCommands.Commands = make([]string, 11)
+	longTimeout := lintedStep("long-timeout")
+	longTimeout.Timeout = "3h"
+
+	spec := &c8sv1alpha1.PipelineConfigSpec{
+		DefaultStepTimeout: "30m",
+		Steps:              []c8sv1alpha1.PipelineStep{lintedStep("ok"), untagged, latest, digest, noResources, manyCommands, longTimeout},
+	}
+
+	stepsOf := func(rule parser.LintRule) []string {
+		var steps []string
+		for _, finding := range rule.Check(spec) {
+			assert.Equal(t, parser.LintSeverityWarning, finding.Severity)
+			steps = append(steps, finding.Step)
+		}
+		return steps
+	}
+
+	assert.Equal(t, []string{"untagged", "latest"}, stepsOf(parser.NoLatestTag{}))
+	assert.Equal(t, []string{"no-resources"}, stepsOf(parser.ResourcesRequired{}))
+	assert.Equal(t, []string{"many-commands"}, stepsOf(parser.TooManyCommands{}))
+	assert.Equal(t, []string{"long-timeout"}, stepsOf(parser.LongTimeout{}))
+
+	spec.DefaultStepTimeout = "4h"
+	assert.Len(t, parser.LongTimeout{}.Check(spec), len(spec.Steps))
+}
+
+// stepCountRule reports pipelines with more steps than a limit
+type stepCountRule struct{ max int }
+
+func (r stepCountRule) Name() string { return "StepCount" }
+
+func (r stepCountRule) Check(spec *c8sv1alpha1.PipelineConfigSpec) []parser.LintFinding {
+	if len(spec.Steps) <= r.max {
+		return nil
+	}
+	return []parser.LintFinding{{Severity: parser.LintSeverityError, Message: "too many steps"}}
+}
+
+// TestRegisterLintRule verifies custom rules run after the built-in ones and
+// replace rules of the same name
+func TestRegisterLintRule(t *testing.T) {
+	spec := &c8sv1alpha1.PipelineConfigSpec{Steps: []c8sv1alpha1.PipelineStep{lintedStep("a"), lintedStep("b")}}
+	assert.Empty(t, parser.Lint(spec))
+
+	count := len(parser.LintRules())
+	parser.RegisterLintRule(stepCountRule{max: 1})
+	parser.RegisterLintRule(stepCountRule{max: 2})
+	assert.Len(t, parser.LintRules(), count+1)
+	assert.Empty(t, parser.Lint(spec))
+
+	parser.RegisterLintRule(stepCountRule{max: 1})
+	assert.Equal(t, []parser.LintFinding{{Severity: parser.LintSeverityError, Message: "too many steps"}}, parser.Lint(spec))
+	parser.RegisterLintRule(stepCountRule{max: 100})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+)
+
+// TestParseImportedNode verifies node names are extracted from k3d image import output
+func TestParseImportedNode(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected string
+		ok       bool
+	}{
+		{
+			line:     "INFO[0002] Importing images from tarball '/k3d/images/k3d-c8s-dev-images.tar' into node 'k3d-c8s-dev-server-0'...",
+			expected: "k3d-c8s-dev-server-0",
+			ok:       true,
+		},
+		{
+			line:     "INFO[0002] Importing images from tarball '/k3d/images/k3d-c8s-dev-images.tar' into node 'k3d-c8s-dev-agent-1'...",
+			expected: "k3d-c8s-dev-agent-1",
+			ok:       true,
+		},
+		{
+			line: "INFO[0000] Importing image(s) into cluster 'c8s-dev'",
+		},
+		{
+			line: "INFO[0003] Successfully imported 1 image(s) into 1 cluster(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		node, ok := cluster.ParseImportedNode(tt.line)
+		assert.Equal(t, tt.ok, ok, tt.line)
+		assert.Equal(t, tt.expected, node, tt.line)
+	}
+}
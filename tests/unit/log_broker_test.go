@@ -0,0 +1,144 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/org/c8s/pkg/api/handlers"
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/log/broker"
+	"github.com/org/c8s/pkg/types"
+)
+
+// recordingBroker is a Broker that replays queued messages to the next subscriber
+type recordingBroker struct {
+	queued       [][]byte
+	subscribed   []string
+	unsubscribed []string
+}
+
+func (b *recordingBroker) Publish(key string, data []byte) {
+	b.queued = append(b.queued, data)
+}
+
+func (b *recordingBroker) Subscribe(key string) <-chan []byte {
+	b.subscribed = append(b.subscribed, key)
+	ch := make(chan []byte, len(b.queued))
+	for _, data := range b.queued {
+		ch <- data
+	}
+	close(ch)
+	return ch
+}
+
+func (b *recordingBroker) Unsubscribe(key string, ch <-chan []byte) {
+	b.unsubscribed = append(b.unsubscribed, key)
+}
+
+// TestLogBufferManager_Broker verifies the in-memory fallback delivers published logs
+// until a subscriber unsubscribes
+func TestLogBufferManager_Broker(t *testing.T) {
+	var b broker.Broker = controller.NewLogBufferManager()
+
+	ch := b.Subscribe("default/run-1/build")
+	b.Publish("default/run-1/build", []byte("compiling\n"))
+	assert.Equal(t, []byte("compiling\n"), <-ch)
+
+	b.Unsubscribe("default/run-1/build", ch)
+	_, open := <-ch
+	assert.False(t, open, "channel should be closed after Unsubscribe")
+
+	// Publishing after the last subscriber left must not block or panic
+	b.Publish("default/run-1/build", []byte("linking\n"))
+}
+
+// TestLogCollector_BrokerFallback verifies the collector uses its in-memory buffers
+// unless a broker is configured
+func TestLogCollector_BrokerFallback(t *testing.T) {
+	lc := controller.NewLogCollector(nil, nil)
+	assert.Same(t, lc.GetLogBuffer(), lc.Broker())
+
+	b := &recordingBroker{}
+	lc.SetBroker(b)
+	assert.Same(t, b, lc.Broker())
+}
+
+// TestHandleStepLogs_StreamsFromBroker verifies following a running step streams the
+// logs published to its key as server-sent events
+func TestHandleStepLogs_StreamsFromBroker(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"},
+		Status: c8sv1alpha1.PipelineRunStatus{
+			Steps: []c8sv1alpha1.StepStatus{{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-build"}},
+		},
+	}
+	s := runtime.NewScheme()
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(run).Build()
+
+	b := &recordingBroker{}
+	b.Publish("default/run-1/build", []byte("compiling\nlinking\n"))
+
+	h := handlers.NewLogsHandler(nil, c, nil)
+	h.SetBroker(b)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/default/pipelineruns/run-1/logs/build?follow=true", nil)
+	rec := httptest.NewRecorder()
+	h.HandleStepLogs(rec, req)
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "data: compiling\n\ndata: linking\n\n", rec.Body.String())
+	assert.Equal(t, []string{"default/run-1/build"}, b.subscribed)
+	assert.Equal(t, []string{"default/run-1/build"}, b.unsubscribed)
+}
+
+// TestLogCollector_FollowLogs verifies the logs of a running step are published
+// while it runs, and not published again when they are collected
+func TestLogCollector_FollowLogs(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1-build-abcde", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: types.ContainerNameStep}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+	lc := controller.NewLogCollector(k8sfake.NewSimpleClientset(pod), nil)
+	key := controller.LogBufferKey("default", "run-1", "build")
+
+	lc.FollowLogs(context.Background(), pod, run, "build", nil)
+	lc.FollowLogs(context.Background(), pod, run, "build", nil)
+	assert.Eventually(t, func() bool {
+		return string(lc.GetLogBuffer().Read(key)) == "fake logs"
+	}, time.Second, 10*time.Millisecond)
+
+	_, err := lc.CollectAndUpload(context.Background(), pod, run, "build", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "fake logs", string(lc.GetLogBuffer().Read(key)))
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/org/c8s/pkg/controller"
+)
+
+// TestCircularBuffer_CompressesPastHalfCapacity verifies the buffer compresses its
+// contents once past half capacity and still reads them back in order
+func TestCircularBuffer_CompressesPastHalfCapacity(t *testing.T) {
+	logs := typicalBuildLog()
+	cb := controller.NewCircularBuffer(len(logs))
+
+	cb.Write(logs[:len(logs)/4])
+	cb.WaitForCompression()
+	assert.Zero(t, cb.CompressionRatio(), "below half capacity nothing is compressed")
+
+	cb.Write(logs[len(logs)/4:])
+	cb.WaitForCompression()
+	assert.Greater(t, cb.CompressionRatio(), 2.0)
+	assert.Equal(t, logs, cb.Read())
+}
+
+// TestCircularBuffer_HoldsMoreThanMaxSize verifies compression lets the buffer hold
+// more log output than its size, dropping only the oldest data
+func TestCircularBuffer_HoldsMoreThanMaxSize(t *testing.T) {
+	logs := typicalBuildLog()
+	cb := controller.NewCircularBuffer(len(logs) / 2)
+
+	var written []byte
+	for i := 0; i < 4; i++ {
+		cb.Write(logs)
+		cb.WaitForCompression()
+		written = append(written, logs...)
+	}
+
+	got := cb.Read()
+	assert.Greater(t, len(got), len(logs)/2)
+	assert.True(t, bytes.HasSuffix(written, got), "buffer should hold the most recent output")
+}
+
+// buildLogLine is a single line of typical build output
+var buildLogLine = []byte("2025-01-15T10:00:00Z --- PASS: TestPipeline/case_42 (0.42s)\n")
+
+// BenchmarkCircularBufferWrite_Uncompressed measures Write while the buffer stays below half capacity
+func BenchmarkCircularBufferWrite_Uncompressed(b *testing.B) {
+	// A fresh buffer every linesPerBuffer writes never reaches half capacity
+	linesPerBuffer := controller.MaxLogBufferSize / 2 / len(buildLogLine)
+	cb := controller.NewCircularBuffer(controller.MaxLogBufferSize)
+
+	b.SetBytes(int64(len(buildLogLine)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i > 0 && i%linesPerBuffer == 0 {
+			cb = controller.NewCircularBuffer(controller.MaxLogBufferSize)
+		}
+		cb.Write(buildLogLine)
+	}
+}
+
+// BenchmarkCircularBufferWrite_Compressed measures Write once compression has been triggered
+func BenchmarkCircularBufferWrite_Compressed(b *testing.B) {
+	cb := controller.NewCircularBuffer(controller.MaxLogBufferSize)
+	for cb.CompressionRatio() == 0 {
+		cb.Write(typicalBuildLog())
+		cb.WaitForCompression()
+	}
+
+	b.SetBytes(int64(len(buildLogLine)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cb.Write(buildLogLine)
+	}
+	b.StopTimer()
+	cb.WaitForCompression()
+}
+
+// benchmarkCircularBufferRead measures Read throughput of a buffer holding logs
+func benchmarkCircularBufferRead(b *testing.B, size int) {
+	logs := typicalBuildLog()
+	cb := controller.NewCircularBuffer(size)
+	cb.Write(logs)
+	cb.WaitForCompression()
+
+	b.SetBytes(int64(len(logs)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cb.Read()
+	}
+}
+
+// BenchmarkCircularBufferRead_Uncompressed measures Read of a buffer without a compressed archive
+func BenchmarkCircularBufferRead_Uncompressed(b *testing.B) {
+	benchmarkCircularBufferRead(b, controller.MaxLogBufferSize)
+}
+
+// BenchmarkCircularBufferRead_Compressed measures Read of a buffer whose contents were compressed
+func BenchmarkCircularBufferRead_Compressed(b *testing.B) {
+	benchmarkCircularBufferRead(b, len(typicalBuildLog()))
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/storage"
+)
+
+// TestArchiveObjectKey verifies archive keys name their bucket
+func TestArchiveObjectKey(t *testing.T) {
+	key := storage.ArchiveObjectKey("c8s-archive", "default/run-1/build.log")
+	assert.Equal(t, "s3://c8s-archive/default/run-1/build.log", key)
+
+	bucket, objectKey, ok := storage.ParseArchiveObjectKey(key)
+	require.True(t, ok)
+	assert.Equal(t, "c8s-archive", bucket)
+	assert.Equal(t, "default/run-1/build.log", objectKey)
+
+	for _, key := range []string{"default/run-1/build.log", "s3://c8s-archive", "s3:///build.log"} {
+		_, objectKey, ok := storage.ParseArchiveObjectKey(key)
+		assert.False(t, ok, key)
+		assert.Equal(t, key, objectKey)
+	}
+}
+
+// TestApplyRetentionPolicy_Delete verifies the logs of expired runs are
+// deleted without archiving when no archive bucket is configured
+func TestApplyRetentionPolicy_Delete(t *testing.T) {
+	logStorage := &memoryLogStorage{logs: map[string][]byte{
+		"default/run-1/build.log": []byte("build"),
+		"default/run-1/test.log":  []byte("test"),
+		"default/run-2/build.log": []byte("build"),
+	}}
+
+	result, err := storage.ApplyRetentionPolicy(context.Background(), logStorage, &storage.Config{Bucket: "c8s-logs"},
+		[]string{storage.RunLogPrefix("default", "run-1")})
+	require.NoError(t, err)
+
+	assert.Equal(t, &storage.RetentionResult{Deleted: 2}, result)
+	assert.Equal(t, []string{"default/run-2/build.log"}, keysOf(logStorage.logs))
+}
+
+// TestApplyRetentionPolicy_Archive verifies logs are copied to the archive
+// bucket before being deleted
+func TestApplyRetentionPolicy_Archive(t *testing.T) {
+	logStorage := &memoryLogStorage{logs: map[string][]byte{
+		"default/run-1/build.log": []byte("build"),
+		"default/run-2/build.log": []byte("build"),
+	}}
+	config := &storage.Config{Bucket: "c8s-logs", ArchiveBucket: "c8s-archive"}
+
+	result, err := storage.ApplyRetentionPolicy(context.Background(), logStorage, config,
+		[]string{storage.RunLogPrefix("default", "run-1")})
+	require.NoError(t, err)
+
+	assert.Equal(t, &storage.RetentionResult{Archived: 1, Deleted: 1}, result)
+	assert.Equal(t, []string{"default/run-2/build.log", "s3://c8s-archive/default/run-1/build.log"}, keysOf(logStorage.logs))
+	assert.Equal(t, []byte("build"), logStorage.logs["s3://c8s-archive/default/run-1/build.log"])
+}
+
+// TestApplyRetentionPolicy_ArchiveFailure verifies a log that could not be
+// archived is kept in the primary bucket
+func TestApplyRetentionPolicy_ArchiveFailure(t *testing.T) {
+	logStorage := &memoryLogStorage{
+		logs:    map[string][]byte{"default/run-1/build.log": []byte("build")},
+		copyErr: errors.New("access denied"),
+	}
+	config := &storage.Config{Bucket: "c8s-logs", ArchiveBucket: "c8s-archive"}
+
+	result, err := storage.ApplyRetentionPolicy(context.Background(), logStorage, config,
+		[]string{storage.RunLogPrefix("default", "run-1")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "default/run-1/build.log")
+
+	assert.Zero(t, result.Deleted)
+	assert.Contains(t, logStorage.logs, "default/run-1/build.log")
+}
+
+// keysOf returns the sorted keys of stored logs
+func keysOf(logs map[string][]byte) []string {
+	keys := make([]string, 0, len(logs))
+	for key := range logs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/org/c8s/pkg/api/handlers"
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/types"
+)
+
+const streamPath = "/api/v1/namespaces/default/pipelineruns/run-1/logs/build/stream"
+
+// streamTestHandler returns a LogsHandler for run-1 with its build step in
+// a phase, and the Kubernetes objects in the clientset
+func streamTestHandler(t *testing.T, step c8sv1alpha1.StepStatus, objects ...runtime.Object) (*handlers.LogsHandler, *k8sfake.Clientset) {
+	return maskedStreamTestHandler(t, step, "", objects...)
+}
+
+// maskedStreamTestHandler returns a streamTestHandler whose build step
+// references a Secret of secretValue, if set
+func maskedStreamTestHandler(t *testing.T, step c8sv1alpha1.StepStatus, secretValue string, objects ...runtime.Object) (*handlers.LogsHandler, *k8sfake.Clientset) {
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"},
+		Spec:       c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "config"},
+		Status:     c8sv1alpha1.PipelineRunStatus{Steps: []c8sv1alpha1.StepStatus{step}},
+	}
+	s := runtime.NewScheme()
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	require.NoError(t, corev1.AddToScheme(s))
+	builder := fake.NewClientBuilder().WithScheme(s).WithObjects(run)
+	if secretValue != "" {
+		builder.WithObjects(&c8sv1alpha1.PipelineConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Spec: c8sv1alpha1.PipelineConfigSpec{Steps: []c8sv1alpha1.PipelineStep{{
+				Name:    "build",
+				Secrets: []c8sv1alpha1.SecretReference{{SecretRef: "token", Key: "value"}},
+			}}},
+		}, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "default"},
+			Data:       map[string][]byte{"value": []byte(secretValue)},
+		})
+	}
+	c := builder.Build()
+
+	clientset := k8sfake.NewSimpleClientset(objects...)
+	h := handlers.NewLogsHandler(clientset, c, &memoryLogStorage{logs: map[string][]byte{
+		"logs/run-1/build.log": []byte("compiling\nlinking\n"),
+	}})
+	h.SetStreamPollInterval(5 * time.Millisecond)
+	return h, clientset
+}
+
+// streamTestJob returns the Job of the build step, finished if condition is
+// set
+func streamTestJob(condition batchv1.JobConditionType) *batchv1.Job {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "run-1-build", Namespace: "default"}}
+	if condition != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue}}
+	}
+	return job
+}
+
+// TestHandleStepLogStream_Broker verifies the logs published for a running
+// step are streamed until its Job finishes
+func TestHandleStepLogStream_Broker(t *testing.T) {
+	h, clientset := streamTestHandler(t,
+		c8sv1alpha1.StepStatus{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-build"},
+		streamTestJob(""))
+	b := &recordingBroker{}
+	b.Publish("default/run-1/build", []byte("compiling\nlinking\n"))
+	h.SetBroker(b)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = clientset.BatchV1().Jobs("default").UpdateStatus(context.Background(), streamTestJob(batchv1.JobFailed), metav1.UpdateOptions{})
+	}()
+
+	rec := httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "data: compiling\n\ndata: linking\n\nevent: end\ndata: Failed\n\n", rec.Body.String())
+	assert.Equal(t, []string{"default/run-1/build"}, b.unsubscribed)
+}
+
+// TestHandleStepLogStream_Storage verifies the logs of a completed step are
+// sent from storage
+func TestHandleStepLogStream_Storage(t *testing.T) {
+	h, _ := streamTestHandler(t, c8sv1alpha1.StepStatus{
+		Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded, JobName: "run-1-build", LogURL: "s3://logs-bucket/logs/run-1/build.log",
+	})
+	b := &recordingBroker{}
+	h.SetBroker(b)
+
+	rec := httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "data: compiling\n\ndata: linking\n\nevent: end\ndata: Succeeded\n\n", rec.Body.String())
+	assert.Empty(t, b.subscribed, "completed step should not subscribe to live logs")
+}
+
+// TestHandleStepLogStream_Pod verifies the logs of a step whose Job finished
+// before its logs were stored are read from its Pod
+func TestHandleStepLogStream_Pod(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "run-1-build-abcde", Namespace: "default", Labels: map[string]string{"job-name": "run-1-build"},
+	}}
+	h, _ := streamTestHandler(t,
+		c8sv1alpha1.StepStatus{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-build"},
+		streamTestJob(batchv1.JobComplete), pod)
+
+	rec := httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "data: fake logs\n\nevent: end\ndata: Succeeded\n\n", rec.Body.String())
+}
+
+// TestHandleStepLogStream_LogBufferManager verifies live logs are streamed
+// through the controller's in-memory LogBufferManager when it is the broker
+func TestHandleStepLogStream_LogBufferManager(t *testing.T) {
+	h, clientset := streamTestHandler(t,
+		c8sv1alpha1.StepStatus{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-build"},
+		streamTestJob(""))
+	buffers := controller.NewLogBufferManager()
+	h.SetBroker(buffers)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		buffers.Publish("default/run-1/build", []byte("compiling\n"))
+		time.Sleep(50 * time.Millisecond)
+		_, _ = clientset.BatchV1().Jobs("default").UpdateStatus(context.Background(), streamTestJob(batchv1.JobComplete), metav1.UpdateOptions{})
+	}()
+
+	rec := httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil))
+
+	assert.Equal(t, "data: compiling\n\nevent: end\ndata: Succeeded\n\n", rec.Body.String())
+}
+
+// TestHandleStepLogStream_MasksSecrets verifies secret values are masked in
+// logs streamed from storage and from the step's Pod
+func TestHandleStepLogStream_MasksSecrets(t *testing.T) {
+	h, _ := maskedStreamTestHandler(t, c8sv1alpha1.StepStatus{
+		Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded, JobName: "run-1-build", LogURL: "s3://logs-bucket/logs/run-1/build.log",
+	}, "linking")
+
+	rec := httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil))
+	assert.Equal(t, "data: compiling\n\ndata: ***REDACTED***\n\nevent: end\ndata: Succeeded\n\n", rec.Body.String())
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "run-1-build-abcde", Namespace: "default", Labels: map[string]string{"job-name": "run-1-build"},
+	}}
+	h, _ = maskedStreamTestHandler(t,
+		c8sv1alpha1.StepStatus{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-build"},
+		"fake", streamTestJob(batchv1.JobComplete), pod)
+
+	rec = httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil))
+	assert.Equal(t, "data: ***REDACTED*** logs\n\nevent: end\ndata: Succeeded\n\n", rec.Body.String())
+}
+
+// podLogRecorder records the Pods and containers whose logs are requested
+type podLogRecorder struct {
+	kubernetes.Interface
+	requests *podLogRequests
+}
+
+// podLogRequests are the Pods and containers of the log requests seen by a
+// podLogRecorder
+type podLogRequests struct {
+	pods       []string
+	containers []string
+}
+
+func (r podLogRecorder) CoreV1() corev1client.CoreV1Interface {
+	return podLogCoreV1{CoreV1Interface: r.Interface.CoreV1(), requests: r.requests}
+}
+
+type podLogCoreV1 struct {
+	corev1client.CoreV1Interface
+	requests *podLogRequests
+}
+
+func (c podLogCoreV1) Pods(namespace string) corev1client.PodInterface {
+	return podLogPods{PodInterface: c.CoreV1Interface.Pods(namespace), requests: c.requests}
+}
+
+type podLogPods struct {
+	corev1client.PodInterface
+	requests *podLogRequests
+}
+
+func (p podLogPods) GetLogs(name string, opts *corev1.PodLogOptions) *rest.Request {
+	p.requests.pods = append(p.requests.pods, name)
+	p.requests.containers = append(p.requests.containers, opts.Container)
+	return p.PodInterface.GetLogs(name, opts)
+}
+
+// latestPodTestHandler returns a LogsHandler for run-1 whose build step's
+// finished Job has three Pods, run-1-build-bbbbb being the latest, and the
+// log requests it makes
+func latestPodTestHandler(t *testing.T) (*handlers.LogsHandler, *podLogRequests) {
+	now := time.Now()
+	jobPod := func(name string, started time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"job-name": "run-1-build"}},
+			Status:     corev1.PodStatus{StartTime: &metav1.Time{Time: started}},
+		}
+	}
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"},
+		Status: c8sv1alpha1.PipelineRunStatus{Steps: []c8sv1alpha1.StepStatus{
+			{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-build"},
+		}},
+	}
+	s := runtime.NewScheme()
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(run).Build()
+
+	requests := &podLogRequests{}
+	clientset := podLogRecorder{
+		Interface: k8sfake.NewSimpleClientset(
+			streamTestJob(batchv1.JobComplete),
+			jobPod("run-1-build-aaaaa", now.Add(-time.Minute)),
+			jobPod("run-1-build-bbbbb", now),
+			jobPod("run-1-build-ccccc", now.Add(-2*time.Minute))),
+		requests: requests,
+	}
+	h := handlers.NewLogsHandler(clientset, c, nil)
+	h.SetStreamPollInterval(5 * time.Millisecond)
+	return h, requests
+}
+
+// TestHandleStepLogStream_LatestPod verifies the logs of a retried Job are
+// read from the step container of its most recently started Pod
+func TestHandleStepLogStream_LatestPod(t *testing.T) {
+	h, requests := latestPodTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, []string{"run-1-build-bbbbb"}, requests.pods)
+	assert.Equal(t, []string{types.ContainerNameStep}, requests.containers)
+}
+
+// TestHandleStepLogs_FollowLatestPod verifies followed logs are read from
+// the step container of the most recently started Pod of a retried Job
+func TestHandleStepLogs_FollowLatestPod(t *testing.T) {
+	h, requests := latestPodTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.HandleStepLogs(rec, httptest.NewRequest("GET", "/api/v1/namespaces/default/pipelineruns/run-1/logs/build?follow=true", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "fake logs", rec.Body.String())
+	assert.Equal(t, []string{"run-1-build-bbbbb"}, requests.pods)
+	assert.Equal(t, []string{types.ContainerNameStep}, requests.containers)
+}
+
+// TestHandleStepLogStream_Disconnect verifies the stream stops when the
+// client disconnects while the step runs
+func TestHandleStepLogStream_Disconnect(t *testing.T) {
+	h, _ := streamTestHandler(t,
+		c8sv1alpha1.StepStatus{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-build"},
+		streamTestJob(""))
+	h.SetBroker(&recordingBroker{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	rec := httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil).WithContext(ctx))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "event: end")
+}
+
+// TestHandleStepLogStream_StepNotFound verifies unknown steps are rejected
+// before the stream starts
+func TestHandleStepLogStream_StepNotFound(t *testing.T) {
+	h, _ := streamTestHandler(t, c8sv1alpha1.StepStatus{Name: "test", Phase: c8sv1alpha1.StepPhaseRunning})
+
+	rec := httptest.NewRecorder()
+	h.HandleStepLogStream(rec, httptest.NewRequest("GET", streamPath, nil))
+	assert.Equal(t, 404, rec.Code)
+	assert.Contains(t, rec.Body.String(), "step build not found")
+}
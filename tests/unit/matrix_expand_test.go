@@ -0,0 +1,215 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/scheduler"
+)
+
+// TestExpandMatrix_LexicographicOrder verifies combinations are ordered by
+// dimension name, then value, regardless of declaration order
+func TestExpandMatrix_LexicographicOrder(t *testing.T) {
+	matrix := &c8sv1alpha1.MatrixStrategy{
+		Dimensions: map[string][]string{
+			"os":         {"ubuntu", "alpine"},
+			"go_version": {"1.22", "1.21"},
+		},
+	}
+
+	want := []map[string]string{
+		{"go_version": "1.21", "os": "alpine"},
+		{"go_version": "1.21", "os": "ubuntu"},
+		{"go_version": "1.22", "os": "alpine"},
+		{"go_version": "1.22", "os": "ubuntu"},
+	}
+	for i := 0; i < 20; i++ {
+		combinations, err := scheduler.ExpandMatrix(matrix)
+		require.NoError(t, err)
+		require.Equal(t, want, combinations)
+	}
+
+	// The dimension values are left as declared
+	assert.Equal(t, []string{"ubuntu", "alpine"}, matrix.Dimensions["os"])
+}
+
+// TestExpandMatrix_SingleDimension verifies a single dimension yields one
+// combination per value
+func TestExpandMatrix_SingleDimension(t *testing.T) {
+	combinations, err := scheduler.ExpandMatrix(&c8sv1alpha1.MatrixStrategy{
+		Dimensions: map[string][]string{"os": {"windows", "linux", "darwin"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"os": "darwin"},
+		{"os": "linux"},
+		{"os": "windows"},
+	}, combinations)
+}
+
+// TestExpandMatrix_ThreeDimensions verifies the first dimension name varies
+// slowest and the last fastest
+func TestExpandMatrix_ThreeDimensions(t *testing.T) {
+	combinations, err := scheduler.ExpandMatrix(&c8sv1alpha1.MatrixStrategy{
+		Dimensions: map[string][]string{
+			"os":   {"linux", "darwin"},
+			"arch": {"arm64", "amd64"},
+			"db":   {"postgres", "mysql"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"arch": "amd64", "db": "mysql", "os": "darwin"},
+		{"arch": "amd64", "db": "mysql", "os": "linux"},
+		{"arch": "amd64", "db": "postgres", "os": "darwin"},
+		{"arch": "amd64", "db": "postgres", "os": "linux"},
+		{"arch": "arm64", "db": "mysql", "os": "darwin"},
+		{"arch": "arm64", "db": "mysql", "os": "linux"},
+		{"arch": "arm64", "db": "postgres", "os": "darwin"},
+		{"arch": "arm64", "db": "postgres", "os": "linux"},
+	}, combinations)
+}
+
+// TestExpandMatrix_Excludes verifies exact, negated and regular expression
+// exclusions
+func TestExpandMatrix_Excludes(t *testing.T) {
+	dimensions := map[string][]string{
+		"os":         {"ubuntu", "alpine", "windows"},
+		"go_version": {"1.20", "1.21", "1.22"},
+	}
+
+	tests := []struct {
+		name    string
+		exclude []map[string]string
+		want    []map[string]string
+	}{
+		{
+			name:    "exact",
+			exclude: []map[string]string{{"os": "windows", "go_version": "1.20"}, {"os": "alpine"}},
+			want: []map[string]string{
+				{"go_version": "1.20", "os": "ubuntu"},
+				{"go_version": "1.21", "os": "ubuntu"},
+				{"go_version": "1.21", "os": "windows"},
+				{"go_version": "1.22", "os": "ubuntu"},
+				{"go_version": "1.22", "os": "windows"},
+			},
+		},
+		{
+			name:    "not equal",
+			exclude: []map[string]string{{"os": "!=ubuntu"}},
+			want: []map[string]string{
+				{"go_version": "1.20", "os": "ubuntu"},
+				{"go_version": "1.21", "os": "ubuntu"},
+				{"go_version": "1.22", "os": "ubuntu"},
+			},
+		},
+		{
+			name:    "regular expression",
+			exclude: []map[string]string{{"os": "/^(alpine|windows)$/", "go_version": `/^1\.2[01]$/`}},
+			want: []map[string]string{
+				{"go_version": "1.20", "os": "ubuntu"},
+				{"go_version": "1.21", "os": "ubuntu"},
+				{"go_version": "1.22", "os": "alpine"},
+				{"go_version": "1.22", "os": "ubuntu"},
+				{"go_version": "1.22", "os": "windows"},
+			},
+		},
+		{
+			name:    "undefined dimension",
+			exclude: []map[string]string{{"arch": "arm64"}},
+			want: []map[string]string{
+				{"go_version": "1.20", "os": "alpine"},
+				{"go_version": "1.20", "os": "ubuntu"},
+				{"go_version": "1.20", "os": "windows"},
+				{"go_version": "1.21", "os": "alpine"},
+				{"go_version": "1.21", "os": "ubuntu"},
+				{"go_version": "1.21", "os": "windows"},
+				{"go_version": "1.22", "os": "alpine"},
+				{"go_version": "1.22", "os": "ubuntu"},
+				{"go_version": "1.22", "os": "windows"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combinations, err := scheduler.ExpandMatrix(&c8sv1alpha1.MatrixStrategy{
+				Dimensions: dimensions,
+				Exclude:    tt.exclude,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, combinations)
+		})
+	}
+}
+
+// TestExpandMatrix_AllExcluded verifies an error when no combination is left
+func TestExpandMatrix_AllExcluded(t *testing.T) {
+	_, err := scheduler.ExpandMatrix(&c8sv1alpha1.MatrixStrategy{
+		Dimensions: map[string][]string{
+			"os":   {"ubuntu", "alpine"},
+			"arch": {"amd64"},
+		},
+		Exclude: []map[string]string{{"os": "ubuntu"}, {"os": "/^alp/"}},
+	})
+	assert.ErrorContains(t, err, "all matrix combinations are excluded")
+}
+
+// TestExpandMatrix_Errors verifies empty dimensions and invalid regular
+// expressions are rejected
+func TestExpandMatrix_Errors(t *testing.T) {
+	_, err := scheduler.ExpandMatrix(&c8sv1alpha1.MatrixStrategy{
+		Dimensions: map[string][]string{"os": {}},
+	})
+	assert.ErrorContains(t, err, "matrix dimension os has no values")
+
+	_, err = scheduler.ExpandMatrix(&c8sv1alpha1.MatrixStrategy{
+		Dimensions: map[string][]string{"os": {"ubuntu"}},
+		Exclude:    []map[string]string{{"os": "/[/"}},
+	})
+	assert.ErrorContains(t, err, "matrix exclude[0].os")
+
+	combinations, err := scheduler.ExpandMatrix(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{{}}, combinations)
+}
+
+// TestValidateMatrix_InvalidExcludeExpression verifies the parser rejects
+// exclusions with invalid regular expressions
+func TestValidateMatrix_InvalidExcludeExpression(t *testing.T) {
+	config := stepMatrixConfig()
+	config.Spec.Matrix.Exclude = []map[string]string{{"os": "/(ubuntu/"}}
+	assert.ErrorContains(t, parser.Validate(config), "spec.matrix.exclude[0].os")
+
+	config.Spec.Matrix.Exclude = []map[string]string{{"os": "/ubuntu/"}}
+	assert.NoError(t, parser.Validate(config))
+}
+
+// TestGenerateMatrixRunName verifies run names list variables in dimension
+// order
+func TestGenerateMatrixRunName(t *testing.T) {
+	vars := map[string]string{"os": "ubuntu", "go_version": "1.21"}
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, "build-matrix-3-go_version-1-21-os-ubuntu",
+			scheduler.GenerateMatrixRunName("build", 3, vars))
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// TestMaxLogSizeBytes verifies the step override takes precedence over the
+// config-level size, which takes precedence over the default
+func TestMaxLogSizeBytes(t *testing.T) {
+	const mb = 1024 * 1024
+	spec := &c8sv1alpha1.PipelineConfigSpec{
+		Steps: []c8sv1alpha1.PipelineStep{
+			{Name: "build"},
+			{Name: "test", MaxLogSizeMB: 50},
+		},
+	}
+
+	assert.Equal(t, int64(c8sv1alpha1.DefaultMaxLogSizeMB*mb), spec.MaxLogSizeBytes("build"))
+	assert.Equal(t, int64(50*mb), spec.MaxLogSizeBytes("test"))
+
+	spec.GlobalMaxLogSizeMB = 20
+	assert.Equal(t, int64(20*mb), spec.MaxLogSizeBytes("build"))
+	assert.Equal(t, int64(50*mb), spec.MaxLogSizeBytes("test"))
+	assert.Equal(t, int64(20*mb), spec.MaxLogSizeBytes("unknown"))
+}
+
+// TestParseMaxLogSize verifies log sizes are parsed and values over 100MB are
+// rejected
+func TestParseMaxLogSize(t *testing.T) {
+	spec, err := parser.ParseBytes([]byte(`version: v1alpha1
+name: app
+globalMaxLogSizeMB: 20
+steps:
+  - name: test
+    image: golang:1.25
+    commands: ["go test ./..."]
+    maxLogSizeMB: 50
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 20, spec.GlobalMaxLogSizeMB)
+	assert.Equal(t, 50, spec.Steps[0].MaxLogSizeMB)
+
+	config := &c8sv1alpha1.PipelineConfig{Spec: *spec}
+	config.Spec.Repository = "https://github.com/org/repo.git"
+	require.NoError(t, parser.Validate(config))
+
+	config.Spec.GlobalMaxLogSizeMB = 101
+	config.Spec.Steps[0].MaxLogSizeMB = 200
+	err = parser.Validate(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.globalMaxLogSizeMB")
+	assert.Contains(t, err.Error(), "invalid log size 200MB")
+}
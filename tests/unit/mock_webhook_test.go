@@ -0,0 +1,160 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/samples"
+	"github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+// TestParseRate verifies event rates per second, minute and hour
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  rate.Limit
+	}{
+		{"10/s", 10},
+		{"120/m", 2},
+		{"7200/h", 2},
+		{"5", 5},
+		{"0.5/s", 0.5},
+	}
+	for _, tt := range tests {
+		limit, err := samples.ParseRate(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.InDelta(t, float64(tt.want), float64(limit), 1e-9, tt.value)
+	}
+
+	for _, value := range []string{"", "fast", "0/s", "-1/s", "10/d"} {
+		_, err := samples.ParseRate(value)
+		assert.Error(t, err, value)
+	}
+}
+
+// mockWebhookServer returns a webhook service creating a PipelineRun per new
+// commit in c, like the real service does, and rejecting every reject-th event
+func mockWebhookServer(c client.Client, reject int32) *httptest.Server {
+	var received int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := atomic.AddInt32(&received, 1); reject > 0 && n%reject == 0 {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+
+		var payload webhook.GitHubPushEvent
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+			Name:      "repo-" + payload.After[:8],
+			Namespace: "default",
+			Labels:    map[string]string{types.LabelCommit: payload.After[:8]},
+		}}
+		if err := c.Create(r.Context(), run); err != nil && !apierrors.IsAlreadyExists(err) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+}
+
+// newMockWebhookClient returns a fake client for the runs of a load test
+func newMockWebhookClient(t *testing.T) client.Client {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	return fake.NewClientBuilder().WithScheme(s).Build()
+}
+
+// TestMockWebhook verifies events are sent at the configured rate and each
+// accepted commit is matched with its run
+func TestMockWebhook(t *testing.T) {
+	c := newMockWebhookClient(t)
+	server := mockWebhookServer(c, 0)
+	defer server.Close()
+
+	result, err := samples.MockWebhook(t.Context(), c, samples.MockWebhookOptions{
+		WebhookURL: server.URL,
+		Provider:   webhook.ProviderGitHub,
+		Repository: "example-org/example-repo",
+		Branch:     "main",
+		Namespace:  "default",
+		Rate:       50,
+		Duration:   400 * time.Millisecond,
+		Settle:     2 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, result.Sent, 10)
+	assert.LessOrEqual(t, result.Sent, 30)
+	assert.Equal(t, result.Sent, result.Accepted)
+	assert.Zero(t, result.ErrorRate)
+	assert.Equal(t, result.Sent, result.RunsCreated)
+	assert.Zero(t, result.MissingRuns)
+	assert.Equal(t, map[int]int{http.StatusAccepted: result.Sent}, result.StatusCodes)
+	assert.Positive(t, result.Throughput)
+	assert.LessOrEqual(t, result.Latency.Min, result.Latency.Median)
+	assert.LessOrEqual(t, result.Latency.P99, result.Latency.Max)
+}
+
+// TestMockWebhook_RejectionsAndDuplicates verifies rejected events count as
+// errors and duplicate commits don't count as missing runs
+func TestMockWebhook_RejectionsAndDuplicates(t *testing.T) {
+	c := newMockWebhookClient(t)
+	server := mockWebhookServer(c, 4)
+	defer server.Close()
+
+	result, err := samples.MockWebhook(t.Context(), c, samples.MockWebhookOptions{
+		WebhookURL:     server.URL,
+		Provider:       webhook.ProviderGitHub,
+		Repository:     "example-org/example-repo",
+		Branch:         "main",
+		Namespace:      "default",
+		Rate:           50,
+		Duration:       400 * time.Millisecond,
+		DuplicateRatio: 0.5,
+		Settle:         2 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Positive(t, result.Rejected)
+	assert.Equal(t, result.Sent, result.Accepted+result.Rejected)
+	assert.InDelta(t, float64(result.Rejected)/float64(result.Sent), result.ErrorRate, 1e-9)
+	assert.Equal(t, result.Rejected, result.StatusCodes[http.StatusTooManyRequests])
+	assert.Zero(t, result.MissingRuns)
+	assert.LessOrEqual(t, result.RunsCreated, result.Sent-result.Duplicates)
+}
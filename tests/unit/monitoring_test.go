@@ -0,0 +1,60 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/org/c8s/pkg/localenv/deploy"
+)
+
+// TestMonitoringManifests verifies the embedded manifests render with namespaces and a valid dashboard
+func TestMonitoringManifests(t *testing.T) {
+	manifests, err := deploy.MonitoringManifests("monitoring", "c8s-system")
+	require.NoError(t, err)
+	require.Contains(t, manifests, "prometheus-rules.yaml")
+	require.Contains(t, manifests, "pod-monitor.yaml")
+	require.Contains(t, manifests, "grafana-dashboard.json")
+
+	assert.Contains(t, manifests["prometheus-rules.yaml"], "namespace: c8s-system")
+	assert.NotContains(t, manifests["pod-monitor.yaml"], "NAMESPACE_PLACEHOLDER")
+
+	var configMap struct {
+		Kind     string `yaml:"kind"`
+		Metadata struct {
+			Namespace string            `yaml:"namespace"`
+			Labels    map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Data map[string]string `yaml:"data"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(manifests["grafana-dashboard.json"]), &configMap))
+	assert.Equal(t, "ConfigMap", configMap.Kind)
+	assert.Equal(t, "monitoring", configMap.Metadata.Namespace)
+	assert.Equal(t, "1", configMap.Metadata.Labels["grafana_dashboard"])
+
+	dashboard := configMap.Data["grafana-dashboard.json"]
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(dashboard), &parsed))
+	for _, metric := range []string{"c8s_pipelineruns_total", "c8s_step_duration_seconds_bucket", "c8s_log_storage_bytes_total"} {
+		assert.Contains(t, dashboard, metric)
+	}
+}
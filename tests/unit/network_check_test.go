@@ -0,0 +1,69 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv/cluster"
+	"github.com/org/c8s/pkg/types"
+)
+
+// TestNetworkTestPods verifies the client and server Pods run in different namespaces
+func TestNetworkTestPods(t *testing.T) {
+	client, server, extra := cluster.NetworkTestPods(cluster.NetworkCheckOptions{}, "abc")
+	assert.NotEqual(t, client.Namespace, server.Namespace)
+	assert.NotEmpty(t, server.Command)
+	assert.Empty(t, extra)
+
+	_, _, extra = cluster.NetworkTestPods(cluster.NetworkCheckOptions{
+		TestMatrix:        true,
+		StepNamespace:     "pipelines",
+		OperatorNamespace: "c8s-system",
+	}, "abc")
+	require.Len(t, extra, 2)
+	assert.Equal(t, "pipelines", extra[0].Namespace)
+	assert.Equal(t, types.LabelManagedValue, extra[0].Labels[types.LabelManaged])
+	assert.Equal(t, "c8s-system", extra[1].Namespace)
+}
+
+// TestNetworkProbes verifies the base tests and the c8s test matrix
+func TestNetworkProbes(t *testing.T) {
+	opts := cluster.NetworkCheckOptions{Timeout: 5 * time.Second, GitLabURL: "https://gitlab.example.com"}
+	client, _, _ := cluster.NetworkTestPods(opts, "abc")
+
+	probes := cluster.NetworkProbes(opts, client, nil, "10.42.0.7")
+	names := make([]string, len(probes))
+	for i, probe := range probes {
+		names[i] = probe.Name
+	}
+	assert.Equal(t, []string{"dns", "api-server", "pod-to-pod"}, names)
+	assert.Contains(t, probes[2].Command, "10.42.0.7")
+
+	opts.TestMatrix = true
+	client, _, extra := cluster.NetworkTestPods(opts, "abc")
+	probes = cluster.NetworkProbes(opts, client, extra, "10.42.0.7")
+	require.Len(t, probes, 5)
+	assert.Equal(t, "step-to-api-server", probes[3].Name)
+	assert.Equal(t, extra[0].Name, probes[3].Pod.Name)
+	assert.Equal(t, "webhook-to-gitlab", probes[4].Name)
+	assert.Contains(t, probes[4].Command, "https://gitlab.example.com")
+}
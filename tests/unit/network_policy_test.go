@@ -0,0 +1,71 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/types"
+)
+
+// TestBuildNetworkPolicyDisabled verifies no policy is built without a NetworkPolicySpec
+func TestBuildNetworkPolicyDisabled(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+	config := &c8sv1alpha1.PipelineConfig{}
+
+	policy, err := controller.BuildNetworkPolicy(run, config)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
+
+// TestBuildNetworkPolicy verifies egress rules are generated for each allowance
+func TestBuildNetworkPolicy(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+	config := &c8sv1alpha1.PipelineConfig{
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			NetworkPolicy: &c8sv1alpha1.NetworkPolicySpec{
+				AllowCluster: true,
+				AllowedHosts: []string{"10.1.2.3"},
+				Egress: []c8sv1alpha1.EgressRule{
+					{CIDR: "192.168.0.0/16", Ports: []int32{443}},
+				},
+			},
+		},
+	}
+
+	policy, err := controller.BuildNetworkPolicy(run, config)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+
+	assert.Equal(t, "run-1-egress", policy.Name)
+	assert.Equal(t, "run-1", policy.Spec.PodSelector.MatchLabels[types.LabelPipelineRun])
+	require.Len(t, policy.OwnerReferences, 1)
+	assert.Equal(t, "PipelineRun", policy.OwnerReferences[0].Kind)
+
+	// DNS, cluster, allowed hosts, and one custom egress rule
+	require.Len(t, policy.Spec.Egress, 4)
+	assert.NotNil(t, policy.Spec.Egress[1].To[0].NamespaceSelector)
+	assert.Equal(t, "10.1.2.3/32", policy.Spec.Egress[2].To[0].IPBlock.CIDR)
+	assert.Equal(t, "192.168.0.0/16", policy.Spec.Egress[3].To[0].IPBlock.CIDR)
+	assert.Equal(t, int32(443), policy.Spec.Egress[3].Ports[0].Port.IntVal)
+}
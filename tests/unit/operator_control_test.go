@@ -0,0 +1,189 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/org/c8s/pkg/localenv/deploy"
+)
+
+// operatorTestClient returns a fake clientset with an operator Deployment and events
+func operatorTestClient(available int32) *fake.Clientset {
+	replicas := int32(2)
+	now := time.Now()
+	return fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "c8s-controller", Namespace: "c8s-system", Generation: 3},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "manager", Image: "c8s-controller:v0.2.0"}}},
+				},
+			},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 3,
+				ReadyReplicas:      available,
+				UpdatedReplicas:    2,
+				AvailableReplicas:  available,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+				},
+			},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "pod-event", Namespace: "c8s-system"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "c8s-controller-7d9f-abcde"},
+			Reason:         "BackOff",
+			Type:           corev1.EventTypeWarning,
+			LastTimestamp:  metav1.NewTime(now),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "scaled", Namespace: "c8s-system"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Deployment", Name: "c8s-controller"},
+			Reason:         "ScalingReplicaSet",
+			Type:           corev1.EventTypeNormal,
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "other", Namespace: "c8s-system"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "webhook-5c4b-xyz"},
+			Reason:         "Pulled",
+		},
+	)
+}
+
+// TestGetOperatorStatus verifies replica counts, image, conditions and operator events are reported
+func TestGetOperatorStatus(t *testing.T) {
+	status, err := deploy.GetOperatorStatus(context.Background(), operatorTestClient(2), "c8s-system", "c8s-controller")
+	require.NoError(t, err)
+
+	assert.True(t, status.Healthy)
+	assert.Equal(t, "c8s-controller:v0.2.0", status.Image)
+	assert.Equal(t, int32(2), status.Replicas)
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, "Available", status.Conditions[0].Type)
+
+	// Events of other workloads are excluded, newest first
+	require.Len(t, status.Events, 2)
+	assert.Equal(t, "BackOff", status.Events[0].Reason)
+	assert.Equal(t, "deployment/c8s-controller", status.Events[1].Object)
+
+	degraded, err := deploy.GetOperatorStatus(context.Background(), operatorTestClient(1), "c8s-system", "c8s-controller")
+	require.NoError(t, err)
+	assert.False(t, degraded.Healthy)
+
+	_, err = deploy.GetOperatorStatus(context.Background(), operatorTestClient(2), "c8s-system", "missing")
+	assert.ErrorContains(t, err, "not found")
+}
+
+// TestRestartAndScaleOperator verifies restart stamps the Pod template and scale sets replicas
+func TestRestartAndScaleOperator(t *testing.T) {
+	ctx := context.Background()
+	client := operatorTestClient(2)
+
+	require.NoError(t, deploy.RestartOperator(ctx, client, "c8s-system", "c8s-controller"))
+	deployment, err := client.AppsV1().Deployments("c8s-system").Get(ctx, "c8s-controller", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+
+	require.NoError(t, deploy.ScaleOperator(ctx, client, "c8s-system", "c8s-controller", 3))
+	deployment, err = client.AppsV1().Deployments("c8s-system").Get(ctx, "c8s-controller", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+
+	assert.Error(t, deploy.ScaleOperator(ctx, client, "c8s-system", "c8s-controller", -1))
+}
+
+// upgradeTestDynamicClient returns a fake dynamic client holding one PipelineConfig
+func upgradeTestDynamicClient() *dynamicfake.FakeDynamicClient {
+	config := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "c8s.dev/v1alpha1",
+		"kind":       "PipelineConfig",
+		"metadata":   map[string]interface{}{"name": "ci", "namespace": "default"},
+		"spec":       map[string]interface{}{"repository": "https://github.com/org/repo.git"},
+	}}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: "c8s.dev", Version: "v1alpha1", Resource: "pipelineconfigs"}: "PipelineConfigList",
+		}, config)
+}
+
+// TestUpgradeOperator verifies the new image is rolled out once all PipelineConfigs are accepted
+func TestUpgradeOperator(t *testing.T) {
+	ctx := context.Background()
+	client := operatorTestClient(2)
+
+	result, err := deploy.UpgradeOperator(ctx, client, upgradeTestDynamicClient(), deploy.UpgradeOptions{
+		Namespace: "c8s-system",
+		Name:      "c8s-controller",
+		Image:     "c8s-controller:v0.3.0",
+		Timeout:   time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "c8s-controller:v0.2.0", result.PreviousImage)
+	assert.Equal(t, 1, result.CheckedConfigs)
+	assert.False(t, result.RolledBack)
+
+	deployment, err := client.AppsV1().Deployments("c8s-system").Get(ctx, "c8s-controller", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "c8s-controller:v0.3.0", deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+// TestUpgradeOperator_RollsBackIncompatible verifies the previous image is restored
+// when the new operator rejects a PipelineConfig
+func TestUpgradeOperator_RollsBackIncompatible(t *testing.T) {
+	ctx := context.Background()
+	client := operatorTestClient(2)
+	dynamicClient := upgradeTestDynamicClient()
+	dynamicClient.PrependReactor("update", "pipelineconfigs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "c8s.dev", Resource: "pipelineconfigs"},
+			"ci", errors.New("spec.steps: field is required"))
+	})
+
+	result, err := deploy.UpgradeOperator(ctx, client, dynamicClient, deploy.UpgradeOptions{
+		Namespace: "c8s-system",
+		Name:      "c8s-controller",
+		Image:     "c8s-controller:v0.3.0",
+		Timeout:   time.Second,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rolled back to c8s-controller:v0.2.0")
+	assert.True(t, result.RolledBack)
+	require.Len(t, result.IncompatibleConfigs, 1)
+	assert.Contains(t, result.IncompatibleConfigs[0], "default/ci")
+
+	deployment, err := client.AppsV1().Deployments("c8s-system").Get(ctx, "c8s-controller", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "c8s-controller:v0.2.0", deployment.Spec.Template.Spec.Containers[0].Image)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/localenv/deploy"
+)
+
+// TestProfilePath verifies each profile kind maps to its pprof endpoint
+func TestProfilePath(t *testing.T) {
+	assert.Equal(t, "/debug/pprof/profile?seconds=30", deploy.ProfilePath(deploy.ProfileCPU, 30*time.Second))
+	assert.Equal(t, "/debug/pprof/heap", deploy.ProfilePath(deploy.ProfileHeap, 30*time.Second))
+	assert.Equal(t, "/debug/pprof/goroutine?debug=2", deploy.ProfilePath(deploy.ProfileGoroutines, 0))
+}
+
+// TestCaptureProfile verifies profiles are downloaded to the output file
+func TestCaptureProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/debug/pprof/heap" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("heap-profile"))
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	path := filepath.Join(t.TempDir(), "heap.pprof")
+	require.NoError(t, deploy.CaptureProfile(context.Background(), addr, deploy.ProfileHeap, 0, path))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "heap-profile", string(content))
+
+	err = deploy.CaptureProfile(context.Background(), addr, deploy.ProfileCPU, time.Second, path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/types"
+)
+
+const remotePipelineYAML = `version: v1alpha1
+name: remote
+timeout: 45m
+steps:
+  - name: build
+    image: golang:1.25
+    commands:
+      - go build ./...
+`
+
+// mockS3 serves objects of a single bucket from memory
+type mockS3 struct {
+	s3iface.S3API
+	bucket  string
+	objects map[string]string
+	gets    int32
+}
+
+func (m *mockS3) GetObjectWithContext(_ aws.Context, input *s3.GetObjectInput, _ ...request.Option) (*s3.GetObjectOutput, error) {
+	atomic.AddInt32(&m.gets, 1)
+	content, ok := m.objects[aws.StringValue(input.Key)]
+	if aws.StringValue(input.Bucket) != m.bucket || !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil)
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(content))}, nil
+}
+
+// withRemoteParser sets the allowed hosts and clients of ParseFromURL for a test
+func withRemoteParser(t *testing.T, hosts []string, httpClient *http.Client, s3Client s3iface.S3API) {
+	prevHosts, prevHTTP, prevS3 := parser.AllowedHosts, parser.HTTPClient, parser.S3Client
+	parser.AllowedHosts, parser.S3Client = hosts, s3Client
+	if httpClient != nil {
+		parser.HTTPClient = httpClient
+	}
+	parser.ClearRemoteCache()
+	t.Cleanup(func() {
+		parser.AllowedHosts, parser.HTTPClient, parser.S3Client = prevHosts, prevHTTP, prevS3
+		parser.ClearRemoteCache()
+	})
+}
+
+// TestParseFromURL_HTTPS verifies a pipeline is downloaded once and then
+// served from the cache
+func TestParseFromURL_HTTPS(t *testing.T) {
+	var requests int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/pipelines/app.c8s.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(remotePipelineYAML))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	withRemoteParser(t, []string{serverURL.Hostname()}, server.Client(), nil)
+
+	for i := 0; i < 2; i++ {
+		spec, err := parser.ParseFromURL(server.URL + "/pipelines/app.c8s.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "45m", spec.Timeout)
+		require.Len(t, spec.Steps, 1)
+		assert.Equal(t, "build", spec.Steps[0].Name)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	_, err = parser.ParseFromURL(server.URL + "/pipelines/missing.c8s.yaml")
+	assert.ErrorContains(t, err, "404")
+}
+
+// TestParseFromURL_Redirects verifies redirects are only followed to https://
+// URLs of allowed hosts
+func TestParseFromURL_Redirects(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		port := server.Listener.Addr().(*net.TCPAddr).Port
+		switch r.URL.Path {
+		case "/pipelines/app.c8s.yaml":
+			_, _ = w.Write([]byte(remotePipelineYAML))
+		case "/moved.c8s.yaml":
+			http.Redirect(w, r, server.URL+"/pipelines/app.c8s.yaml", http.StatusFound)
+		case "/untrusted.c8s.yaml":
+			http.Redirect(w, r, fmt.Sprintf("https://localhost:%d/pipelines/app.c8s.yaml", port), http.StatusFound)
+		case "/plain.c8s.yaml":
+			http.Redirect(w, r, fmt.Sprintf("http://127.0.0.1:%d/pipelines/app.c8s.yaml", port), http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	withRemoteParser(t, []string{serverURL.Hostname()}, server.Client(), nil)
+
+	spec, err := parser.ParseFromURL(server.URL + "/moved.c8s.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "45m", spec.Timeout)
+
+	_, err = parser.ParseFromURL(server.URL + "/untrusted.c8s.yaml")
+	assert.ErrorIs(t, err, types.ErrUntrustedHost)
+	assert.ErrorContains(t, err, "redirect to localhost")
+
+	_, err = parser.ParseFromURL(server.URL + "/plain.c8s.yaml")
+	assert.ErrorContains(t, err, `unsupported redirect URL scheme "http"`)
+}
+
+// TestParseFromURL_S3 verifies pipelines are read from the bucket and key of
+// an s3:// URL
+func TestParseFromURL_S3(t *testing.T) {
+	mock := &mockS3{bucket: "ci-pipelines", objects: map[string]string{"teams/app.c8s.yaml": remotePipelineYAML}}
+	withRemoteParser(t, []string{"ci-pipelines"}, nil, mock)
+
+	spec, err := parser.ParseFromURL("s3://ci-pipelines/teams/app.c8s.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "45m", spec.Timeout)
+
+	_, err = parser.ParseFromURL("s3://ci-pipelines/teams/app.c8s.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&mock.gets))
+
+	_, err = parser.ParseFromURL("s3://ci-pipelines/teams/missing.c8s.yaml")
+	assert.ErrorContains(t, err, s3.ErrCodeNoSuchKey)
+}
+
+// TestParseFromURL_Rejected verifies untrusted hosts and unsupported schemes
+// are rejected before anything is downloaded
+func TestParseFromURL_Rejected(t *testing.T) {
+	mock := &mockS3{bucket: "ci-pipelines"}
+	withRemoteParser(t, []string{"pipelines.example.com", "*.trusted.dev"}, nil, mock)
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "untrusted host", url: "https://evil.example.com/app.c8s.yaml", wantErr: "evil.example.com is not in the allowed hosts"},
+		{name: "untrusted bucket", url: "s3://other-bucket/app.c8s.yaml", wantErr: "other-bucket is not in the allowed hosts"},
+		{name: "wildcard apex", url: "https://trusted.dev/app.c8s.yaml", wantErr: "trusted.dev is not in the allowed hosts"},
+		{name: "plain http", url: "http://pipelines.example.com/app.c8s.yaml", wantErr: `unsupported pipeline URL scheme "http"`},
+		{name: "file", url: "file:///etc/passwd", wantErr: `unsupported pipeline URL scheme "file"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.ParseFromURL(tt.url)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&mock.gets))
+
+	_, err := parser.ParseFromURL("https://evil.example.com/app.c8s.yaml")
+	assert.True(t, errors.Is(err, types.ErrUntrustedHost))
+}
+
+func TestIsAllowedHost(t *testing.T) {
+	withRemoteParser(t, []string{"Pipelines.Example.com", "*.trusted.dev"}, nil, nil)
+
+	assert.True(t, parser.IsAllowedHost("pipelines.example.com"))
+	assert.True(t, parser.IsAllowedHost("ci.trusted.dev"))
+	assert.True(t, parser.IsAllowedHost("a.b.trusted.dev"))
+	assert.False(t, parser.IsAllowedHost("trusted.dev"))
+	assert.False(t, parser.IsAllowedHost("eviltrusted.dev"))
+	assert.False(t, parser.IsAllowedHost("example.com"))
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/deploy"
+	"github.com/org/c8s/pkg/localenv/watch"
+	"github.com/org/c8s/pkg/parser"
+)
+
+const diffPipelineFile = `version: v1alpha1
+name: ci
+steps:
+  - name: build
+    image: golang:1.25
+    commands:
+      - go build ./...
+  - name: lint
+    image: golangci/golangci-lint:latest
+    commands:
+      - golangci-lint run
+`
+
+// writePipelineFile writes a pipeline file to a temporary directory
+func writePipelineFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".c8s.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadPipelineFile(t *testing.T) {
+	path := writePipelineFile(t, diffPipelineFile)
+
+	config, err := deploy.LoadPipelineFile(path, "", "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "ci", config.Name)
+	assert.Equal(t, "team-a", config.Namespace)
+	assert.Len(t, config.Spec.Steps, 2)
+
+	config, err = deploy.LoadPipelineFile(path, "override", "default")
+	require.NoError(t, err)
+	assert.Equal(t, "override", config.Name)
+}
+
+func TestDiffDeployedConfig(t *testing.T) {
+	ctx := context.Background()
+	local, err := deploy.LoadPipelineFile(writePipelineFile(t, diffPipelineFile), "", "default")
+	require.NoError(t, err)
+
+	t.Run("not deployed", func(t *testing.T) {
+		diff, err := deploy.DiffDeployedConfig(ctx, newBackupClient(), local)
+		require.NoError(t, err)
+		assert.False(t, diff.Deployed)
+		assert.False(t, diff.HasRemovals())
+	})
+
+	t.Run("removed step", func(t *testing.T) {
+		client := newBackupClient(deployedConfig("ci", "default", deployedStep("build"), deployedStep("test", "build")))
+
+		diff, err := deploy.DiffDeployedConfig(ctx, client, local)
+		require.NoError(t, err)
+		assert.True(t, diff.Deployed)
+		assert.True(t, diff.HasRemovals())
+
+		kinds := map[string]string{}
+		for _, change := range diff.Changes {
+			kinds[change.StepName] = change.Kind
+		}
+		assert.Equal(t, parser.ChangeStepAdded, kinds["lint"])
+		assert.Equal(t, parser.ChangeStepRemoved, kinds["test"])
+	})
+}
+
+func TestApplyPipelineFile(t *testing.T) {
+	ctx := context.Background()
+	local, err := deploy.LoadPipelineFile(writePipelineFile(t, diffPipelineFile), "", "default")
+	require.NoError(t, err)
+
+	t.Run("requires a deployed config", func(t *testing.T) {
+		err := deploy.ApplyPipelineFile(ctx, newBackupClient(), local)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist")
+	})
+
+	t.Run("updates the file fields only", func(t *testing.T) {
+		client := newBackupClient(deployedConfig("ci", "default", deployedStep("build")))
+
+		require.NoError(t, deploy.ApplyPipelineFile(ctx, client, local))
+
+		diff, err := deploy.DiffDeployedConfig(ctx, client, local)
+		require.NoError(t, err)
+		assert.Empty(t, diff.Changes, "applied config should match the file")
+
+		updated, err := client.Resource(c8sv1alpha1.GroupVersion.WithResource("pipelineconfigs")).
+			Namespace("default").Get(ctx, "ci", metav1.GetOptions{})
+		require.NoError(t, err)
+		repository, _, _ := unstructured.NestedString(updated.Object, "spec", "repository")
+		assert.Equal(t, "https://github.com/org/repo", repository, "repository is not defined by the file and must be kept")
+	})
+}
+
+func TestWatchFile_Debounces(t *testing.T) {
+	path := writePipelineFile(t, diffPipelineFile)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		done <- watch.File(ctx, path, 100*time.Millisecond, func() { calls.Add(1) })
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// A burst of saves triggers a single call
+	for i := 0; i < 5; i++ {
+		require.NoError(t, os.WriteFile(path, []byte(diffPipelineFile), 0o600))
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	// Changes to other files of the directory are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), "other.yaml"), []byte("x"), 0o600))
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/org/c8s/pkg/api/handlers"
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	ctypes "github.com/org/c8s/pkg/types"
+)
+
+// cancelTestClient returns a fake client holding objs, with the status
+// subresource of run
+func cancelTestClient(t *testing.T, run *c8sv1alpha1.PipelineRun, objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	return fake.NewClientBuilder().WithScheme(s).
+		WithObjects(append(objs, run)...).
+		WithStatusSubresource(run).
+		Build()
+}
+
+// cancelTestRun returns a PipelineRun in a phase
+func cancelTestRun(phase c8sv1alpha1.PipelineRunPhase) *c8sv1alpha1.PipelineRun {
+	return &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "run-1",
+			Namespace:  "default",
+			Finalizers: []string{ctypes.FinalizerPipelineRun},
+		},
+		Spec:   c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "config", Commit: "abc1234"},
+		Status: c8sv1alpha1.PipelineRunStatus{Phase: phase},
+	}
+}
+
+// TestDeletePipelineRunCancels verifies DELETE on a run in progress requests
+// its cancellation instead of deleting it
+func TestDeletePipelineRunCancels(t *testing.T) {
+	c := cancelTestClient(t, cancelTestRun(c8sv1alpha1.PipelineRunPhaseRunning))
+	handler := handlers.NewPipelineRunHandler(c)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.HandlePipelineRun(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/namespaces/default/pipelineruns/run-1", nil))
+		require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+		var body c8sv1alpha1.PipelineRun
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, c8sv1alpha1.PipelineRunPhaseCancelling, body.Status.Phase)
+	}
+
+	run := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "run-1"}, run))
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseCancelling, run.Status.Phase)
+	assert.Contains(t, run.Annotations, ctypes.AnnotationCancelRequested)
+	assert.True(t, controller.IsCancellationRequested(run))
+}
+
+// TestDeletePipelineRunCompleted verifies DELETE on a completed run deletes
+// it
+func TestDeletePipelineRunCompleted(t *testing.T) {
+	c := cancelTestClient(t, cancelTestRun(c8sv1alpha1.PipelineRunPhaseSucceeded))
+	handler := handlers.NewPipelineRunHandler(c)
+
+	rec := httptest.NewRecorder()
+	handler.HandlePipelineRun(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/namespaces/default/pipelineruns/run-1", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.HandlePipelineRun(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/namespaces/default/pipelineruns/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestReconcileCancellation verifies the controller deletes the Jobs of a
+// cancelled run, then marks it and its steps in progress as cancelled
+func TestReconcileCancellation(t *testing.T) {
+	run := cancelTestRun(c8sv1alpha1.PipelineRunPhaseRunning)
+	run.Annotations = map[string]string{ctypes.AnnotationCancelRequested: "2026-01-01T00:00:00Z"}
+	run.Status.Steps = []c8sv1alpha1.StepStatus{
+		{Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded},
+		{Name: "test", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-test"},
+	}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:      "run-1-test",
+		Namespace: "default",
+		Labels:    map[string]string{ctypes.LabelPipelineRun: "run-1"},
+	}}
+	c := cancelTestClient(t, run, job)
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &controller.PipelineRunReconciler{Client: c, Scheme: c.Scheme(), Recorder: recorder}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(run)}
+	ctx := context.Background()
+
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, controller.CancellationRequeueInterval, result.RequeueAfter)
+	err = c.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{})
+	assert.True(t, apierrors.IsNotFound(err), "Job should be deleted, got %v", err)
+
+	updated := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseCancelling, updated.Status.Phase)
+	assert.Nil(t, updated.Status.CompletionTime)
+
+	result, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseCancelled, updated.Status.Phase)
+	assert.NotNil(t, updated.Status.CompletionTime)
+	assert.Equal(t, c8sv1alpha1.StepPhaseSucceeded, controller.GetStepStatus(updated, "build").Phase)
+	test := controller.GetStepStatus(updated, "test")
+	assert.Equal(t, c8sv1alpha1.StepPhaseFailed, test.Phase)
+	assert.Equal(t, controller.MessageStepCancelled, test.Message)
+
+	require.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, ctypes.ReasonCancelling)
+	assert.Contains(t, <-recorder.Events, ctypes.ReasonCancelled)
+
+	// The Cancelled phase is terminal
+	result, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+}
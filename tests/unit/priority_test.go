@@ -0,0 +1,87 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+// TestResolvePriorityClassName verifies the run priority overrides the PipelineConfig default
+func TestResolvePriorityClassName(t *testing.T) {
+	config := &c8sv1alpha1.PipelineConfig{Spec: c8sv1alpha1.PipelineConfigSpec{Priority: c8sv1alpha1.PriorityLow}}
+
+	run := &c8sv1alpha1.PipelineRun{}
+	assert.Equal(t, "c8s-low", controller.ResolvePriorityClassName(run, config))
+
+	run.Spec.Priority = c8sv1alpha1.PriorityCritical
+	assert.Equal(t, "c8s-critical", controller.ResolvePriorityClassName(run, config))
+
+	assert.Empty(t, controller.ResolvePriorityClassName(&c8sv1alpha1.PipelineRun{}, &c8sv1alpha1.PipelineConfig{}))
+}
+
+// TestCreateJobForStepPriority verifies the Job's Pod template uses the run's PriorityClass
+func TestCreateJobForStepPriority(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"},
+		Spec:       c8sv1alpha1.PipelineRunSpec{Commit: "abc1234", Branch: "main", Priority: c8sv1alpha1.PriorityHigh},
+	}
+	step := &c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.25", Commands: []string{"go build ./..."}}
+
+	job, err := controller.NewJobManager("https://github.com/org/repo.git").CreateJobForStep(step, run, &c8sv1alpha1.PipelineConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "c8s-high", job.Spec.Template.Spec.PriorityClassName)
+}
+
+// TestBuildPriorityClasses verifies higher priorities map to higher PriorityClass values
+func TestBuildPriorityClasses(t *testing.T) {
+	classes := controller.BuildPriorityClasses()
+	require.Len(t, classes, len(c8sv1alpha1.ValidPriorities))
+	for i := 1; i < len(classes); i++ {
+		assert.Greater(t, classes[i].Value, classes[i-1].Value)
+	}
+}
+
+// TestQuotaAdmissionRejectsInvalidPriority verifies unknown priorities are rejected with HTTP 400
+func TestQuotaAdmissionRejectsInvalidPriority(t *testing.T) {
+	run := c8sv1alpha1.PipelineRun{Spec: c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "app", Priority: "urgent"}}
+	raw, err := json.Marshal(run)
+	require.NoError(t, err)
+
+	resp := webhook.NewQuotaAdmissionWebhook(nil, nil).Handle(context.Background(), admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Namespace: "default",
+		Object:    runtime.RawExtension{Raw: raw},
+	})
+
+	assert.False(t, resp.Allowed)
+	require.NotNil(t, resp.Result)
+	assert.Equal(t, int32(http.StatusBadRequest), resp.Result.Code)
+	assert.Contains(t, resp.Result.Message, "urgent")
+}
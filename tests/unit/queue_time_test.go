@@ -0,0 +1,70 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+)
+
+// TestQueueTime verifies the queue time is the time from creation to the first step
+// starting and is not changed by later steps
+func TestQueueTime(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-10 * time.Minute).Truncate(time.Second))
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name: "run-1", Namespace: "default", CreationTimestamp: created,
+	}}
+	build := stepJob("build", batchv1.JobStatus{})
+	test := stepJob("test", batchv1.JobStatus{})
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(run, build, test).
+		WithStatusSubresource(run).
+		Build()
+	su := controller.NewStatusUpdater(c)
+	jobs := map[string]*batchv1.Job{"build": build, "test": test}
+
+	require.NoError(t, su.UpdatePipelineRunStatus(context.Background(), run, jobs, nil, 2))
+	assert.Nil(t, run.Status.QueueTime, "no step has started yet")
+
+	buildStart := metav1.NewTime(created.Add(90 * time.Second))
+	build.Status = batchv1.JobStatus{Active: 1, StartTime: &buildStart}
+	require.NoError(t, su.UpdatePipelineRunStatus(context.Background(), run, jobs, nil, 2))
+	require.NotNil(t, run.Status.QueueTime)
+	assert.Equal(t, 90*time.Second, run.Status.QueueTime.Duration)
+
+	testStart := metav1.NewTime(created.Add(5 * time.Minute))
+	build.Status = batchv1.JobStatus{Succeeded: 1, StartTime: &buildStart}
+	test.Status = batchv1.JobStatus{Active: 1, StartTime: &testStart}
+	require.NoError(t, su.UpdatePipelineRunStatus(context.Background(), run, jobs, nil, 2))
+	assert.Equal(t, 90*time.Second, run.Status.QueueTime.Duration, "the queue time is written once")
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/org/c8s/pkg/controller"
+)
+
+// phaseReconciler marks the given phases, then returns err
+func phaseReconciler(err error, phases ...string) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		for _, phase := range phases {
+			controller.TracePhase(ctx, phase)
+		}
+		return ctrl.Result{}, err
+	})
+}
+
+// tracedContext returns a context whose logger records phase log lines
+func tracedContext(lines *[]string) context.Context {
+	logger := funcr.New(func(prefix, args string) {
+		if strings.Contains(args, `"Reconcile phase"`) {
+			*lines = append(*lines, args)
+		}
+	}, funcr.Options{})
+	return logr.NewContext(context.Background(), logger)
+}
+
+// TestReconciliationTracerLogsPhases verifies every phase is logged once with
+// its duration, including the last one left open by the reconciler
+func TestReconciliationTracerLogsPhases(t *testing.T) {
+	var lines []string
+	tracer := controller.NewReconciliationTracer(phaseReconciler(nil,
+		controller.PhaseFetchPipelineRun, controller.PhaseBuildSchedule, controller.PhaseCreateJobs))
+
+	_, err := tracer.Reconcile(tracedContext(&lines), ctrl.Request{})
+	require.NoError(t, err)
+
+	require.Len(t, lines, 3)
+	for i, phase := range []string{controller.PhaseFetchPipelineRun, controller.PhaseBuildSchedule, controller.PhaseCreateJobs} {
+		assert.Contains(t, lines[i], `"phase"="`+phase+`"`)
+		assert.Contains(t, lines[i], `"duration"=`)
+	}
+}
+
+// TestReconciliationTracerSpans verifies tracing with RECONCILE_TRACE keeps
+// the reconciler's result and error
+func TestReconciliationTracerSpans(t *testing.T) {
+	t.Setenv(controller.ReconcileTraceEnv, "true")
+
+	var lines []string
+	reconcileErr := errors.New("boom")
+	tracer := controller.NewReconciliationTracer(phaseReconciler(reconcileErr, controller.PhaseUpdateStatus))
+
+	_, err := tracer.Reconcile(tracedContext(&lines), ctrl.Request{})
+	assert.ErrorIs(t, err, reconcileErr)
+	assert.Len(t, lines, 1)
+}
+
+// TestTracePhaseUntraced verifies phases are ignored outside a tracer
+func TestTracePhaseUntraced(t *testing.T) {
+	var lines []string
+	ctx := tracedContext(&lines)
+	controller.TracePhase(ctx, controller.PhaseCreateJobs)
+	controller.EndPhase(ctx)
+	assert.Empty(t, lines)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/types"
+)
+
+// assertWithinJitter verifies d is within ±10% of expected
+func assertWithinJitter(t *testing.T, expected, d time.Duration) {
+	t.Helper()
+	assert.GreaterOrEqual(t, d, expected*9/10)
+	assert.LessOrEqual(t, d, expected*11/10)
+}
+
+// TestBackoffDurationStartsAtInitial verifies the first interval is the initial interval
+func TestBackoffDurationStartsAtInitial(t *testing.T) {
+	assertWithinJitter(t, 2*time.Second, controller.BackoffDuration(0, false, 60*time.Second))
+}
+
+// TestBackoffDurationDoubles verifies the interval doubles while nothing changes
+func TestBackoffDurationDoubles(t *testing.T) {
+	assertWithinJitter(t, 8*time.Second, controller.BackoffDuration(4*time.Second, false, 60*time.Second))
+}
+
+// TestBackoffDurationResetsOnChange verifies a Job phase change resets the interval
+func TestBackoffDurationResetsOnChange(t *testing.T) {
+	assertWithinJitter(t, 2*time.Second, controller.BackoffDuration(32*time.Second, true, 60*time.Second))
+}
+
+// TestBackoffDurationCapped verifies the interval never exceeds the maximum
+func TestBackoffDurationCapped(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := controller.BackoffDuration(50*time.Second, false, 60*time.Second)
+		assert.LessOrEqual(t, d, 60*time.Second)
+		assert.GreaterOrEqual(t, d, 54*time.Second)
+	}
+}
+
+// TestBackoffDurationJitter verifies intervals are randomized
+func TestBackoffDurationJitter(t *testing.T) {
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		seen[controller.BackoffDuration(4*time.Second, false, 60*time.Second)] = true
+	}
+	assert.Greater(t, len(seen), 1)
+}
+
+// TestRequeueIntervalAnnotation verifies the interval round-trips through the annotation
+func TestRequeueIntervalAnnotation(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{}
+	assert.Equal(t, time.Duration(0), controller.GetRequeueInterval(run))
+
+	controller.SetRequeueInterval(run, 8*time.Second)
+	assert.Equal(t, "8s", run.Annotations[types.AnnotationRequeueAfter])
+	assert.Equal(t, 8*time.Second, controller.GetRequeueInterval(run))
+
+	run = &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{types.AnnotationRequeueAfter: "soon"},
+	}}
+	assert.Equal(t, time.Duration(0), controller.GetRequeueInterval(run))
+}
+
+// TestNextRequeueInterval verifies the interval before jitter doubles up to
+// the maximum and then stays unchanged
+func TestNextRequeueInterval(t *testing.T) {
+	assert.Equal(t, 2*time.Second, controller.NextRequeueInterval(0, false, 60*time.Second))
+	assert.Equal(t, 8*time.Second, controller.NextRequeueInterval(4*time.Second, false, 60*time.Second))
+	assert.Equal(t, 2*time.Second, controller.NextRequeueInterval(32*time.Second, true, 60*time.Second))
+	assert.Equal(t, 60*time.Second, controller.NextRequeueInterval(60*time.Second, false, 60*time.Second))
+}
+
+// TestPipelineRunChangedPredicate verifies only spec, deletion and
+// annotation changes other than the requeue interval trigger a reconcile
+func TestPipelineRunChangedPredicate(t *testing.T) {
+	old := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Generation: 1}}
+	update := func(mutate func(run *c8sv1alpha1.PipelineRun)) bool {
+		updated := old.DeepCopy()
+		mutate(updated)
+		return controller.PipelineRunChangedPredicate().Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})
+	}
+
+	assert.False(t, update(func(run *c8sv1alpha1.PipelineRun) { run.Status.Phase = c8sv1alpha1.PipelineRunPhaseRunning }))
+	assert.False(t, update(func(run *c8sv1alpha1.PipelineRun) { controller.SetRequeueInterval(run, 8*time.Second) }))
+	assert.True(t, update(func(run *c8sv1alpha1.PipelineRun) { run.Generation = 2 }))
+	assert.True(t, update(func(run *c8sv1alpha1.PipelineRun) {
+		run.Annotations = map[string]string{types.AnnotationCancelRequested: "2026-01-01T00:00:00Z"}
+	}))
+	assert.True(t, update(func(run *c8sv1alpha1.PipelineRun) {
+		now := metav1.Now()
+		run.DeletionTimestamp = &now
+	}))
+}
+
+// TestReconcileUnchangedRunNotRequeuedEarly verifies a pass over a run
+// whose steps didn't change keeps its requeue interval and only writes
+// changes that the watch predicate ignores, so the run is next reconciled
+// after RequeueAfter
+func TestReconcileUnchangedRunNotRequeuedEarly(t *testing.T) {
+	config := &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/example-org/example-repo",
+			Steps:      []c8sv1alpha1.PipelineStep{{Name: "build", Image: "golang:1.22", Commands: []string{"go build ./..."}}},
+		},
+	}
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "run-1",
+			Namespace:   "default",
+			Finalizers:  []string{types.FinalizerPipelineRun},
+			Annotations: map[string]string{types.AnnotationRequeueAfter: "1m0s"},
+		},
+		Spec: c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "config", Commit: "abc1234"},
+		Status: c8sv1alpha1.PipelineRunStatus{
+			Phase: c8sv1alpha1.PipelineRunPhaseRunning,
+			Steps: []c8sv1alpha1.StepStatus{{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-1-build"}},
+		},
+	}
+	start := metav1.Now()
+	job := stepJob("build", batchv1.JobStatus{Active: 1, StartTime: &start})
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(config, run, job).
+		WithStatusSubresource(run).
+		Build()
+	reconciler := &controller.PipelineRunReconciler{Client: c, Scheme: s}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(run)}
+	ctx := context.Background()
+
+	// The first pass records the step's status
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	before := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, before))
+
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, result.RequeueAfter, 54*time.Second)
+	assert.LessOrEqual(t, result.RequeueAfter, controller.MaxRequeueInterval)
+
+	after := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, after))
+	assert.Equal(t, "1m0s", after.Annotations[types.AnnotationRequeueAfter])
+	assert.False(t, controller.PipelineRunChangedPredicate().Update(event.UpdateEvent{ObjectOld: before, ObjectNew: after}),
+		"the pass should not trigger another reconcile")
+}
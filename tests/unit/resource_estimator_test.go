@@ -0,0 +1,272 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/types"
+)
+
+// headroomTestNode returns a Node with the given allocatable resources
+func headroomTestNode(name, cpu, memory string, unschedulable bool) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+// headroomTestPod returns a Pod in a phase requesting the given resources,
+// scheduled to node unless it is empty
+func headroomTestPod(name, node, cpu, memory string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+// headroomTestStep returns a step requesting the given resources
+func headroomTestStep(name, cpu, memory string) c8sv1alpha1.PipelineStep {
+	return c8sv1alpha1.PipelineStep{
+		Name:      name,
+		Image:     "alpine",
+		Commands:  []string{"true"},
+		Resources: &c8sv1alpha1.ResourceRequirements{CPU: cpu, Memory: memory},
+	}
+}
+
+func newHeadroomEstimator(objects ...client.Object) *controller.ResourceEstimator {
+	return controller.NewResourceEstimator(fake.NewClientBuilder().WithObjects(objects...).Build())
+}
+
+func TestCheckHeadroom(t *testing.T) {
+	ctx := context.Background()
+	objects := []client.Object{
+		headroomTestNode("node-1", "4", "8Gi", false),
+		headroomTestNode("node-2", "4", "8Gi", false),
+		headroomTestNode("cordoned", "16", "64Gi", true),
+		headroomTestPod("running", "node-1", "2", "4Gi", corev1.PodRunning),
+		headroomTestPod("pending", "", "1", "2Gi", corev1.PodPending),
+		headroomTestPod("done", "node-2", "8", "16Gi", corev1.PodSucceeded),
+		// Pods on Nodes whose resources aren't counted don't use any of them
+		headroomTestPod("on-cordoned", "cordoned", "8", "16Gi", corev1.PodRunning),
+		headroomTestPod("on-removed", "removed", "8", "16Gi", corev1.PodRunning),
+	}
+
+	tests := []struct {
+		name    string
+		steps   []c8sv1alpha1.PipelineStep
+		wantErr string
+	}{
+		{
+			name:  "fits",
+			steps: []c8sv1alpha1.PipelineStep{headroomTestStep("build", "2", "4Gi"), headroomTestStep("test", "1", "2Gi")},
+		},
+		{
+			name:  "fits exactly",
+			steps: []c8sv1alpha1.PipelineStep{headroomTestStep("build", "3", "6Gi"), headroomTestStep("test", "2", "4Gi")},
+		},
+		{
+			name:    "insufficient cpu",
+			steps:   []c8sv1alpha1.PipelineStep{headroomTestStep("build", "4", "1Gi"), headroomTestStep("test", "1500m", "1Gi")},
+			wantErr: "request 5500m CPU, 5 of 8 available",
+		},
+		{
+			name:    "insufficient memory",
+			steps:   []c8sv1alpha1.PipelineStep{headroomTestStep("build", "1", "6Gi"), headroomTestStep("test", "1", "5Gi")},
+			wantErr: "request 11Gi memory, 10Gi of 16Gi available",
+		},
+		{
+			name:  "steps without resources",
+			steps: []c8sv1alpha1.PipelineStep{{Name: "lint", Image: "alpine", Commands: []string{"true"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newHeadroomEstimator(objects...).CheckHeadroom(ctx, tt.steps, "default")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, types.ErrInsufficientHeadroom))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckHeadroomStepExceedsNodeCapacity(t *testing.T) {
+	ctx := context.Background()
+	estimator := newHeadroomEstimator(
+		headroomTestNode("small-cpu", "2", "16Gi", false),
+		headroomTestNode("small-memory", "8", "2Gi", false),
+		headroomTestNode("cordoned", "16", "64Gi", true),
+	)
+
+	// No single node has both 4 CPU and 4Gi, though the cluster has more
+	err := estimator.CheckHeadroom(ctx, []c8sv1alpha1.PipelineStep{headroomTestStep("build", "4", "4Gi")}, "default")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, types.ErrStepExceedsNodeCapacity))
+	assert.False(t, errors.Is(err, types.ErrInsufficientHeadroom))
+	assert.Contains(t, err.Error(), "step build requests 4 CPU and 4Gi memory")
+
+	assert.NoError(t, estimator.CheckHeadroom(ctx, []c8sv1alpha1.PipelineStep{headroomTestStep("build", "6", "2Gi")}, "default"))
+}
+
+func TestCheckHeadroomWithoutNodes(t *testing.T) {
+	err := newHeadroomEstimator().CheckHeadroom(context.Background(), []c8sv1alpha1.PipelineStep{headroomTestStep("build", "100m", "64Mi")}, "default")
+	assert.True(t, errors.Is(err, types.ErrInsufficientHeadroom))
+}
+
+func TestCheckHeadroomInitContainers(t *testing.T) {
+	pod := headroomTestPod("init", "node-1", "500m", "1Gi", corev1.PodRunning)
+	pod.Spec.InitContainers = []corev1.Container{{
+		Name: "clone",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+		},
+	}}
+	estimator := newHeadroomEstimator(headroomTestNode("node-1", "4", "8Gi", false), pod)
+
+	// The init container's 3 CPU exceed the 500m of the main container
+	assert.NoError(t, estimator.CheckHeadroom(context.Background(), []c8sv1alpha1.PipelineStep{headroomTestStep("build", "1", "1Gi")}, "default"))
+	err := estimator.CheckHeadroom(context.Background(), []c8sv1alpha1.PipelineStep{headroomTestStep("build", "1100m", "1Gi")}, "default")
+	assert.True(t, errors.Is(err, types.ErrInsufficientHeadroom))
+}
+
+func TestStepResourceRequests(t *testing.T) {
+	cpu, memory := controller.StepResourceRequests([]c8sv1alpha1.PipelineStep{
+		headroomTestStep("build", "500m", "512Mi"),
+		headroomTestStep("test", "1", ""),
+		{Name: "lint"},
+	})
+	assert.Equal(t, "1500m", cpu.String())
+	assert.Equal(t, "512Mi", memory.String())
+}
+
+// headroomTestReconciler returns a reconciler estimating headroom through c,
+// with a PipelineConfig of one step requesting cpu and memory and a new run
+func headroomTestReconciler(t *testing.T, cpu, memory string, opts func(*fake.ClientBuilder)) (*controller.PipelineRunReconciler, client.Client, ctrl.Request) {
+	t.Helper()
+	config := &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/example-org/example-repo",
+			Steps:      []c8sv1alpha1.PipelineStep{headroomTestStep("build", cpu, memory)},
+		},
+	}
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default", Finalizers: []string{types.FinalizerPipelineRun}},
+		Spec:       c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "config", Commit: "abc1234"},
+	}
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	builder := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(config, run, headroomTestNode("node-1", "4", "8Gi", false)).
+		WithStatusSubresource(run)
+	if opts != nil {
+		opts(builder)
+	}
+	c := builder.Build()
+	reconciler := &controller.PipelineRunReconciler{Client: c, Scheme: s, ResourceEstimator: controller.NewResourceEstimator(c)}
+	return reconciler, c, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(run)}
+}
+
+func TestReconcileFailsStepExceedingNodeCapacity(t *testing.T) {
+	reconciler, c, req := headroomTestReconciler(t, "8", "1Gi", nil)
+	ctx := context.Background()
+
+	// The first pass initializes the run's phase
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	run := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, run))
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseFailed, run.Status.Phase)
+	status := controller.GetStepStatus(run, "build")
+	require.NotNil(t, status)
+	assert.Equal(t, c8sv1alpha1.StepPhaseFailed, status.Phase)
+	assert.Contains(t, status.Message, "more than any node can allocate")
+
+	jobs := &batchv1.JobList{}
+	require.NoError(t, c.List(ctx, jobs))
+	assert.Empty(t, jobs.Items)
+}
+
+func TestReconcileReturnsHeadroomCheckErrors(t *testing.T) {
+	listErr := errors.New("nodes is forbidden")
+	reconciler, c, req := headroomTestReconciler(t, "1", "1Gi", func(b *fake.ClientBuilder) {
+		b.WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*corev1.NodeList); ok {
+					return listErr
+				}
+				return c.List(ctx, list, opts...)
+			},
+		})
+	})
+	ctx := context.Background()
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = reconciler.Reconcile(ctx, req)
+	assert.ErrorIs(t, err, listErr)
+
+	run := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, run))
+	assert.NotEqual(t, c8sv1alpha1.PipelineRunPhaseFailed, run.Status.Phase)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/types"
+)
+
+// quotaTestJob returns a step Job of a run requesting the given resources
+func quotaTestJob(runName, cpu, memory string, finished bool) batchv1.Job {
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   runName + "-step",
+			Labels: map[string]string{types.LabelPipelineRun: runName},
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "step",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse(cpu),
+								corev1.ResourceMemory: resource.MustParse(memory),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+	if finished {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	}
+	return job
+}
+
+// TestAggregateQuotaUsage_SkipsFinishedJobs verifies only active Jobs count toward usage
+func TestAggregateQuotaUsage_SkipsFinishedJobs(t *testing.T) {
+	usage := controller.AggregateQuotaUsage([]batchv1.Job{
+		quotaTestJob("run-a", "500m", "1Gi", false),
+		quotaTestJob("run-b", "1", "2Gi", false),
+		quotaTestJob("run-c", "4", "8Gi", true),
+	})
+
+	assert.Len(t, usage.Runs, 2)
+	assert.Equal(t, "1500m", usage.CPU.String())
+	assert.Equal(t, "3Gi", usage.Memory.String())
+}
+
+// TestCheckResourceQuota verifies each quota limit throttles Job creation
+func TestCheckResourceQuota(t *testing.T) {
+	active := []batchv1.Job{
+		quotaTestJob("run-a", "1", "2Gi", false),
+		quotaTestJob("run-b", "1", "2Gi", false),
+	}
+
+	tests := []struct {
+		name     string
+		quota    *c8sv1alpha1.ResourceQuotaSpec
+		job      batchv1.Job
+		exceeded bool
+	}{
+		{
+			name:  "no quota",
+			quota: nil,
+			job:   quotaTestJob("run-c", "1", "1Gi", false),
+		},
+		{
+			name:     "concurrent runs reached",
+			quota:    &c8sv1alpha1.ResourceQuotaSpec{MaxConcurrentRuns: 2},
+			job:      quotaTestJob("run-c", "1", "1Gi", false),
+			exceeded: true,
+		},
+		{
+			name:  "already active run does not count twice",
+			quota: &c8sv1alpha1.ResourceQuotaSpec{MaxConcurrentRuns: 2},
+			job:   quotaTestJob("run-a", "1", "1Gi", false),
+		},
+		{
+			name:  "CPU within limit",
+			quota: &c8sv1alpha1.ResourceQuotaSpec{MaxCPUTotal: "3"},
+			job:   quotaTestJob("run-c", "1", "1Gi", false),
+		},
+		{
+			name:     "CPU exceeded",
+			quota:    &c8sv1alpha1.ResourceQuotaSpec{MaxCPUTotal: "2500m"},
+			job:      quotaTestJob("run-c", "1", "1Gi", false),
+			exceeded: true,
+		},
+		{
+			name:     "memory exceeded",
+			quota:    &c8sv1alpha1.ResourceQuotaSpec{MaxMemoryTotal: "4Gi"},
+			job:      quotaTestJob("run-c", "1", "1Gi", false),
+			exceeded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := controller.AggregateQuotaUsage(active)
+			job := tt.job
+
+			err := controller.CheckResourceQuota(tt.quota, usage, &job)
+			if tt.exceeded {
+				assert.ErrorIs(t, err, types.ErrResourceQuotaExceeded)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCheckResourceQuota_Invalid verifies unparsable limits are reported as an
+// invalid quota rather than as exceeded
+func TestCheckResourceQuota_Invalid(t *testing.T) {
+	usage := controller.AggregateQuotaUsage(nil)
+	job := quotaTestJob("run-a", "1", "1Gi", false)
+
+	for _, quota := range []*c8sv1alpha1.ResourceQuotaSpec{{MaxCPUTotal: "two"}, {MaxMemoryTotal: "4 GB"}} {
+		err := controller.CheckResourceQuota(quota, usage, &job)
+		assert.ErrorIs(t, err, types.ErrInvalidResourceQuota)
+		assert.NotErrorIs(t, err, types.ErrResourceQuotaExceeded)
+	}
+}
+
+// TestReconcileInvalidResourceQuota verifies a run whose quota can't be parsed
+// creates no Job and reports why with a condition and a warning event
+func TestReconcileInvalidResourceQuota(t *testing.T) {
+	config := &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository:    "https://github.com/example-org/example-repo",
+			Steps:         []c8sv1alpha1.PipelineStep{{Name: "build", Image: "golang:1.25", Commands: []string{"go build ./..."}}},
+			ResourceQuota: &c8sv1alpha1.ResourceQuotaSpec{MaxCPUTotal: "two"},
+		},
+	}
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default", Finalizers: []string{types.FinalizerPipelineRun}},
+		Spec:       c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "config", Commit: "abc1234"},
+	}
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(config, run).WithStatusSubresource(run).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &controller.PipelineRunReconciler{Client: c, Scheme: s, Recorder: recorder}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(run)}
+	reconcileTwice(t, reconciler, req)
+
+	jobs := &batchv1.JobList{}
+	require.NoError(t, c.List(context.Background(), jobs))
+	assert.Empty(t, jobs.Items)
+
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, run))
+	condition := meta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeResourceQuotaExceeded)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, types.ReasonInvalidResourceQuota, condition.Reason)
+	assert.Contains(t, condition.Message, `maxCPUTotal "two"`)
+
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning "+types.ReasonInvalidResourceQuota)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/types"
+)
+
+// TestResolveRetryPolicy verifies a step's retry overrides the pipeline retryPolicy
+func TestResolveRetryPolicy(t *testing.T) {
+	pipelinePolicy := &c8sv1alpha1.RetryPolicy{MaxRetries: 3}
+	config := &c8sv1alpha1.PipelineConfig{Spec: c8sv1alpha1.PipelineConfigSpec{RetryPolicy: pipelinePolicy}}
+
+	step := &c8sv1alpha1.PipelineStep{Name: "test"}
+	assert.Same(t, pipelinePolicy, controller.ResolveRetryPolicy(step, config))
+
+	step.Retry = &c8sv1alpha1.RetryPolicy{MaxRetries: 1, OnlyOnExitCodes: []int{137}}
+	assert.Same(t, step.Retry, controller.ResolveRetryPolicy(step, config))
+
+	assert.Nil(t, controller.ResolveRetryPolicy(&c8sv1alpha1.PipelineStep{}, &c8sv1alpha1.PipelineConfig{}))
+}
+
+// TestIsRetryableExitCode verifies onlyOnExitCodes restricts which failures are retried
+func TestIsRetryableExitCode(t *testing.T) {
+	anyCode := &c8sv1alpha1.RetryPolicy{MaxRetries: 2}
+	assert.True(t, controller.IsRetryableExitCode(anyCode, 1))
+
+	restricted := &c8sv1alpha1.RetryPolicy{MaxRetries: 2, OnlyOnExitCodes: []int{137, 143}}
+	assert.True(t, controller.IsRetryableExitCode(restricted, 137))
+	assert.False(t, controller.IsRetryableExitCode(restricted, 1))
+
+	assert.False(t, controller.IsRetryableExitCode(&c8sv1alpha1.RetryPolicy{}, 1))
+	assert.False(t, controller.IsRetryableExitCode(nil, 1))
+}
+
+// TestCreateJobForStepRetry verifies the Job backoff limit and pod failure policy follow the retry policy
+func TestCreateJobForStepRetry(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+	step := &c8sv1alpha1.PipelineStep{
+		Name:     "test",
+		Image:    "golang:1.25",
+		Commands: []string{"go test ./..."},
+		Retry:    &c8sv1alpha1.RetryPolicy{MaxRetries: 2, OnlyOnExitCodes: []int{143, 137}},
+	}
+	jm := controller.NewJobManager("https://github.com/org/repo.git")
+
+	job, err := jm.CreateJobForStep(step, run, &c8sv1alpha1.PipelineConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *job.Spec.BackoffLimit)
+	require.NotNil(t, job.Spec.PodFailurePolicy)
+	rule := job.Spec.PodFailurePolicy.Rules[0]
+	assert.Equal(t, batchv1.PodFailurePolicyActionFailJob, rule.Action)
+	assert.Equal(t, batchv1.PodFailurePolicyOnExitCodesOpNotIn, rule.OnExitCodes.Operator)
+	assert.Equal(t, []int32{137, 143}, rule.OnExitCodes.Values)
+
+	step.Retry = nil
+	job, err = jm.CreateJobForStep(step, run, &c8sv1alpha1.PipelineConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(types.JobBackoffLimit), *job.Spec.BackoffLimit)
+	assert.Nil(t, job.Spec.PodFailurePolicy)
+}
+
+// TestUpdatePipelineRunStatusRetryExitCodes verifies a step fails without retrying on an unlisted exit code
+func TestUpdatePipelineRunStatusRetryExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		exitCode int32
+		want     c8sv1alpha1.StepPhase
+	}{
+		{name: "retryable exit code", exitCode: 137, want: c8sv1alpha1.StepPhaseRunning},
+		{name: "unlisted exit code", exitCode: 1, want: c8sv1alpha1.StepPhaseFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "run-1-test",
+					Namespace: "default",
+					Labels:    map[string]string{types.LabelPipelineRun: "run-1", types.LabelStepName: "test"},
+				},
+				Status: batchv1.JobStatus{Failed: 1, Active: 1},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "run-1-test-abcde",
+					Namespace: "default",
+					Labels:    map[string]string{"job-name": "run-1-test", types.LabelPipelineRun: "run-1", types.LabelStepName: "test"},
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name: types.ContainerNameStep,
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: tt.exitCode},
+						},
+					}},
+				},
+			}
+
+			s := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(s))
+			require.NoError(t, c8sv1alpha1.AddToScheme(s))
+			c := fake.NewClientBuilder().WithScheme(s).
+				WithObjects(run, job, pod).
+				WithStatusSubresource(run).
+				Build()
+
+			policies := map[string]*c8sv1alpha1.RetryPolicy{
+				"test": {MaxRetries: 2, OnlyOnExitCodes: []int{137}},
+			}
+			err := controller.NewStatusUpdater(c).UpdatePipelineRunStatus(context.Background(), run,
+				map[string]*batchv1.Job{"test": job}, policies, 1)
+			require.NoError(t, err)
+
+			status := controller.GetStepStatus(run, "test")
+			require.NotNil(t, status)
+			assert.Equal(t, tt.want, status.Phase)
+			require.NotNil(t, status.ExitCode)
+			assert.Equal(t, tt.exitCode, *status.ExitCode)
+		})
+	}
+}
+
+// TestUpdatePipelineRunStatusLatestPodExitCode verifies the exit code of a
+// retried step is read from the most recently started Pod of its Job
+func TestUpdatePipelineRunStatusLatestPodExitCode(t *testing.T) {
+	now := time.Now()
+	attempt := func(name string, started time.Time, exitCode int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"job-name": "run-1-test", types.LabelPipelineRun: "run-1", types.LabelStepName: "test"},
+			},
+			Status: corev1.PodStatus{
+				StartTime: &metav1.Time{Time: started},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: types.ContainerNameStep,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode},
+					},
+				}},
+			},
+		}
+	}
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "run-1-test",
+			Namespace: "default",
+			Labels:    map[string]string{types.LabelPipelineRun: "run-1", types.LabelStepName: "test"},
+		},
+		Status: batchv1.JobStatus{Failed: 2},
+	}
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(run, job,
+			attempt("run-1-test-aaaaa", now.Add(-time.Minute), 137),
+			attempt("run-1-test-bbbbb", now, 1)).
+		WithStatusSubresource(run).
+		Build()
+
+	policies := map[string]*c8sv1alpha1.RetryPolicy{
+		"test": {MaxRetries: 2, OnlyOnExitCodes: []int{137}},
+	}
+	err := controller.NewStatusUpdater(c).UpdatePipelineRunStatus(context.Background(), run,
+		map[string]*batchv1.Job{"test": job}, policies, 1)
+	require.NoError(t, err)
+
+	status := controller.GetStepStatus(run, "test")
+	require.NotNil(t, status)
+	require.NotNil(t, status.ExitCode)
+	assert.Equal(t, int32(1), *status.ExitCode)
+	assert.Equal(t, c8sv1alpha1.StepPhaseFailed, status.Phase)
+}
+
+// TestParseStepRetry verifies step retry overrides are parsed and validated
+func TestParseStepRetry(t *testing.T) {
+	content := []byte(`version: v1alpha1
+name: retry
+steps:
+  - name: test
+    image: golang:1.25
+    commands: ["go test ./..."]
+    retry:
+      maxRetries: 2
+      onlyOnExitCodes: [137]
+`)
+
+	spec, err := parseBoth(t, content)
+	require.NoError(t, err)
+	require.NotNil(t, spec.Steps[0].Retry)
+	assert.Equal(t, []int{137}, spec.Steps[0].Retry.OnlyOnExitCodes)
+
+	spec.Steps[0].Retry.OnlyOnExitCodes = []int{0}
+	err = parser.Validate(&c8sv1alpha1.PipelineConfig{Spec: *spec})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.steps[0].retry.onlyOnExitCodes[0]")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/org/c8s/pkg/cli/bundle"
+	"github.com/org/c8s/pkg/types"
+)
+
+// exportedRun returns a finished PipelineRun as read from its source cluster
+func exportedRun() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "c8s.dev/v1alpha1",
+		"kind":       "PipelineRun",
+		"metadata": map[string]interface{}{
+			"name":            "ci-1",
+			"namespace":       "team-a",
+			"uid":             "0b6f7c0e-1d2a-4c3b-9f4e-5a6b7c8d9e0f",
+			"resourceVersion": "42",
+			"finalizers":      []interface{}{"c8s.dev/pipelinerun"},
+			"labels":          map[string]interface{}{"c8s.dev/branch": "main"},
+		},
+		"spec": map[string]interface{}{"pipelineConfigRef": "ci", "commit": "abc123"},
+		"status": map[string]interface{}{
+			"phase": "Succeeded",
+			"steps": []interface{}{
+				map[string]interface{}{"name": "build", "phase": "Succeeded", "logURL": "s3://logs/team-a/ci-1/build.log"},
+				map[string]interface{}{"name": "lint", "phase": "Skipped"},
+			},
+		},
+	}}
+}
+
+// exportedConfig returns the PipelineConfig of exportedRun
+func exportedConfig() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "c8s.dev/v1alpha1",
+		"kind":       "PipelineConfig",
+		"metadata":   map[string]interface{}{"name": "ci", "namespace": "team-a", "uid": "1c7a8d1f", "generation": int64(4)},
+		"spec":       map[string]interface{}{"repository": "https://github.com/org/repo.git"},
+		"status":     map[string]interface{}{"lastRun": "ci-1"},
+	}}
+}
+
+// TestNewBundle_StripsClusterFields verifies the bundle is portable but keeps the run history
+func TestNewBundle_StripsClusterFields(t *testing.T) {
+	runBundle := bundle.New(exportedRun(), exportedConfig(), "k3d-source")
+
+	assert.Equal(t, bundle.Kind, runBundle.Kind)
+	assert.Equal(t, "k3d-source", runBundle.ExportedFrom)
+	assert.Equal(t, map[string]string{"build": "s3://logs/team-a/ci-1/build.log"}, runBundle.LogURLs)
+
+	run := &unstructured.Unstructured{Object: runBundle.PipelineRun}
+	assert.Empty(t, run.GetUID())
+	assert.Empty(t, run.GetResourceVersion())
+	assert.Empty(t, run.GetNamespace())
+	assert.Empty(t, run.GetFinalizers())
+	assert.Equal(t, "main", run.GetLabels()["c8s.dev/branch"])
+	phase, _, _ := unstructured.NestedString(run.Object, "status", "phase")
+	assert.Equal(t, "Succeeded", phase)
+
+	config := &unstructured.Unstructured{Object: runBundle.PipelineConfig}
+	assert.Empty(t, config.GetUID())
+	assert.Zero(t, config.GetGeneration())
+	_, hasStatus := config.Object["status"]
+	assert.False(t, hasStatus)
+}
+
+// TestRunBundle_ImportObjects verifies an imported run is marked as imported, keeps its
+// step statuses and points at the exported logs
+func TestRunBundle_ImportObjects(t *testing.T) {
+	data, err := yaml.Marshal(bundle.New(exportedRun(), exportedConfig(), "k3d-source"))
+	require.NoError(t, err)
+
+	runBundle, err := bundle.Parse(data)
+	require.NoError(t, err)
+
+	// Log URLs come from the bundle even if the status lost them
+	steps, _, _ := unstructured.NestedSlice(runBundle.PipelineRun, "status", "steps")
+	delete(steps[0].(map[string]interface{}), "logURL")
+	require.NoError(t, unstructured.SetNestedSlice(runBundle.PipelineRun, steps, "status", "steps"))
+
+	config, run, status := runBundle.ImportObjects("team-b")
+	assert.Equal(t, "team-b", config.GetNamespace())
+	assert.Equal(t, "team-b", run.GetNamespace())
+	assert.Equal(t, "k3d-source", run.GetAnnotations()[types.AnnotationImportedFrom])
+	_, hasStatus := run.Object["status"]
+	assert.False(t, hasStatus, "status is set with UpdateStatus after the run is created")
+
+	assert.Equal(t, "Succeeded", status["phase"])
+	steps, _, _ = unstructured.NestedSlice(status, "steps")
+	require.Len(t, steps, 2)
+	logURL, _, _ := unstructured.NestedString(steps[0].(map[string]interface{}), "logURL")
+	assert.Equal(t, "s3://logs/team-a/ci-1/build.log", logURL)
+
+	conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+	require.Len(t, conditions, 1)
+	condition := conditions[0].(map[string]interface{})
+	assert.Equal(t, types.ConditionTypeImported, condition["type"])
+	assert.Equal(t, "True", condition["status"])
+	assert.Equal(t, types.ReasonRunImported, condition["reason"])
+}
+
+// TestParseBundle_Invalid verifies other documents are not imported
+func TestParseBundle_Invalid(t *testing.T) {
+	_, err := bundle.Parse([]byte("apiVersion: v1\nkind: Secret\n"))
+	assert.ErrorContains(t, err, "expected PipelineRunBundle")
+
+	_, err = bundle.Parse([]byte("kind: PipelineRunBundle\n"))
+	assert.ErrorContains(t, err, "required")
+}
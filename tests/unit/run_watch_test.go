@@ -0,0 +1,113 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/cli/runtree"
+)
+
+// TestRunTreeRender verifies dependent steps are indented under their first
+// dependency with their phase icon and elapsed time
+func TestRunTreeRender(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(start.Add(d))
+		return &t
+	}
+
+	steps := []c8sv1alpha1.PipelineStep{
+		{Name: "checkout"},
+		{Name: "build", DependsOn: []string{"checkout"}},
+		{Name: "lint", DependsOn: []string{"checkout"}},
+		{Name: "test", DependsOn: []string{"build", "lint"}},
+	}
+	statuses := []c8sv1alpha1.StepStatus{
+		{Name: "checkout", Phase: c8sv1alpha1.StepPhaseSucceeded, StartTime: at(0), CompletionTime: at(5 * time.Second)},
+		{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning, StartTime: at(5 * time.Second)},
+		{Name: "lint", Phase: c8sv1alpha1.StepPhaseFailed, StartTime: at(5 * time.Second), CompletionTime: at(7 * time.Second)},
+		{Name: "build-linux-amd64", Phase: c8sv1alpha1.StepPhaseSkipped},
+	}
+
+	rendered := runtree.Render(runtree.Build(steps, statuses), 1, start.Add(65*time.Second))
+
+	assert.Equal(t, "✓ checkout  5s\n"+
+		"  ⠙ build  1m0s\n"+
+		"    ○ test\n"+
+		"  ✗ lint  2s\n"+
+		"⊘ build-linux-amd64\n", rendered)
+}
+
+// TestRunTreeTransitions verifies run and step phase changes are reported
+func TestRunTreeTransitions(t *testing.T) {
+	first := &c8sv1alpha1.PipelineRunStatus{
+		Phase: c8sv1alpha1.PipelineRunPhaseRunning,
+		Steps: []c8sv1alpha1.StepStatus{{Name: "build", Phase: c8sv1alpha1.StepPhaseRunning}},
+	}
+	second := &c8sv1alpha1.PipelineRunStatus{
+		Phase: c8sv1alpha1.PipelineRunPhaseRunning,
+		Steps: []c8sv1alpha1.StepStatus{
+			{Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded},
+			{Name: "test", Phase: c8sv1alpha1.StepPhaseRunning},
+		},
+	}
+
+	var lines []string
+	for _, transition := range runtree.Transitions(nil, first) {
+		lines = append(lines, transition.String())
+	}
+	for _, transition := range runtree.Transitions(first, second) {
+		lines = append(lines, transition.String())
+	}
+
+	assert.Equal(t, []string{
+		"run: Running",
+		"step build: Running",
+		"step build: Running -> Succeeded",
+		"step test: Running",
+	}, lines)
+	assert.False(t, runtree.Finished(second.Phase))
+	assert.True(t, runtree.Finished(c8sv1alpha1.PipelineRunPhaseCancelled))
+}
+
+// TestRunTreeSummary verifies the summary box of a finished run
+func TestRunTreeSummary(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(90 * time.Second))
+	status := &c8sv1alpha1.PipelineRunStatus{
+		Phase:          c8sv1alpha1.PipelineRunPhaseFailed,
+		StartTime:      &start,
+		CompletionTime: &end,
+		Steps: []c8sv1alpha1.StepStatus{
+			{Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded},
+			{Name: "test", Phase: c8sv1alpha1.StepPhaseFailed},
+		},
+	}
+
+	assert.Equal(t, ""+
+		"┌──────────────────────────────────┐\n"+
+		"│ PipelineRun app-1 Failed         │\n"+
+		"│ Duration: 1m30s                  │\n"+
+		"│ Steps: 2 (1 succeeded, 1 failed) │\n"+
+		"└──────────────────────────────────┘\n", runtree.Summary("app-1", status))
+}
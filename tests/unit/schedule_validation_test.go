@@ -0,0 +1,167 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/scheduler"
+)
+
+// buildValidationSchedule builds the schedule of a config with the given steps and branches
+func buildValidationSchedule(t *testing.T, branches []string, steps ...c8sv1alpha1.PipelineStep) (*scheduler.Schedule, *c8sv1alpha1.PipelineConfig) {
+	t.Helper()
+	config := &c8sv1alpha1.PipelineConfig{
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/org/repo",
+			Branches:   branches,
+			Steps:      steps,
+		},
+	}
+	schedule, err := scheduler.BuildSchedule(config)
+	require.NoError(t, err)
+	return schedule, config
+}
+
+// TestValidateScheduleClean verifies a plain pipeline has no warnings
+func TestValidateScheduleClean(t *testing.T) {
+	schedule, config := buildValidationSchedule(t, []string{"main"},
+		c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.21", Commands: []string{"go build"}},
+		c8sv1alpha1.PipelineStep{Name: "test", Image: "golang:1.21", Commands: []string{"go test"}, DependsOn: []string{"build"}},
+	)
+
+	assert.Empty(t, scheduler.ValidateSchedule(schedule, config))
+}
+
+// TestValidateScheduleUnmatchedBranch verifies steps whose conditional never matches are reported
+func TestValidateScheduleUnmatchedBranch(t *testing.T) {
+	schedule, config := buildValidationSchedule(t, []string{"main", "develop"},
+		c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.21", Commands: []string{"go build"}},
+		c8sv1alpha1.PipelineStep{
+			Name: "release", Image: "golang:1.21", Commands: []string{"make release"}, DependsOn: []string{"build"},
+			Conditional: &c8sv1alpha1.ConditionalExecution{Branch: "^release/.*$"},
+		},
+		c8sv1alpha1.PipelineStep{
+			Name: "deploy", Image: "golang:1.21", Commands: []string{"make deploy"}, DependsOn: []string{"build"},
+			Conditional: &c8sv1alpha1.ConditionalExecution{Branch: "^main$"},
+		},
+	)
+
+	warnings := scheduler.ValidateSchedule(schedule, config)
+	var skipped []string
+	for _, w := range warnings {
+		if w.Severity == scheduler.SeverityWarning {
+			skipped = append(skipped, w.Step)
+		}
+	}
+	assert.Equal(t, []string{"release"}, skipped)
+}
+
+// TestValidateScheduleGlobBranches verifies the branch check is skipped for glob trigger branches
+func TestValidateScheduleGlobBranches(t *testing.T) {
+	schedule, config := buildValidationSchedule(t, []string{"release/*"},
+		c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.21", Commands: []string{"go build"}},
+		c8sv1alpha1.PipelineStep{
+			Name: "release", Image: "golang:1.21", Commands: []string{"make release"}, DependsOn: []string{"build"},
+			Conditional: &c8sv1alpha1.ConditionalExecution{Branch: "^main$"},
+		},
+	)
+
+	for _, w := range scheduler.ValidateSchedule(schedule, config) {
+		assert.NotEqual(t, "release", w.Step)
+	}
+}
+
+// TestValidateScheduleConditionalLayer verifies layers of only conditional steps are reported
+func TestValidateScheduleConditionalLayer(t *testing.T) {
+	schedule, config := buildValidationSchedule(t, nil,
+		c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.21", Commands: []string{"go build"}},
+		c8sv1alpha1.PipelineStep{
+			Name: "deploy", Image: "golang:1.21", Commands: []string{"make deploy"}, DependsOn: []string{"build"},
+			Conditional: &c8sv1alpha1.ConditionalExecution{Branch: "^main$"},
+		},
+	)
+
+	warnings := scheduler.ValidateSchedule(schedule, config)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, scheduler.SeverityInfo, warnings[0].Severity)
+	assert.Contains(t, warnings[0].Message, "layer 1")
+}
+
+// TestValidateScheduleMaxParallel verifies layers wider than MaxParallel are reported
+func TestValidateScheduleMaxParallel(t *testing.T) {
+	schedule, config := buildValidationSchedule(t, nil,
+		c8sv1alpha1.PipelineStep{Name: "lint", Image: "golang:1.21", Commands: []string{"make lint"}},
+		c8sv1alpha1.PipelineStep{Name: "unit", Image: "golang:1.21", Commands: []string{"make unit"}},
+		c8sv1alpha1.PipelineStep{Name: "vet", Image: "golang:1.21", Commands: []string{"make vet"}},
+	)
+
+	warnings := scheduler.ValidateScheduleWithOptions(schedule, config, scheduler.ValidateOptions{MaxParallel: 2})
+	require.Len(t, warnings, 1)
+	assert.Equal(t, scheduler.SeverityWarning, warnings[0].Severity)
+	assert.Contains(t, warnings[0].Message, "3 steps")
+
+	assert.Empty(t, scheduler.ValidateScheduleWithOptions(schedule, config, scheduler.ValidateOptions{MaxParallel: 3}))
+}
+
+// TestValidateScheduleNodeCapacity verifies steps that fit on no node are reported
+func TestValidateScheduleNodeCapacity(t *testing.T) {
+	schedule, config := buildValidationSchedule(t, nil,
+		c8sv1alpha1.PipelineStep{
+			Name: "small", Image: "golang:1.21", Commands: []string{"go build"},
+			Resources: &c8sv1alpha1.ResourceRequirements{CPU: "500m", Memory: "1Gi"},
+		},
+		c8sv1alpha1.PipelineStep{
+			Name: "large", Image: "golang:1.21", Commands: []string{"go test"}, DependsOn: []string{"small"},
+			Resources: &c8sv1alpha1.ResourceRequirements{CPU: "8", Memory: "1Gi"},
+		},
+	)
+
+	nodes := func() ([]scheduler.NodeCapacity, error) {
+		return []scheduler.NodeCapacity{
+			{Name: "node-1", CPU: resource.MustParse("4"), Memory: resource.MustParse("16Gi")},
+			{Name: "node-2", CPU: resource.MustParse("2"), Memory: resource.MustParse("8Gi")},
+		}, nil
+	}
+
+	warnings := scheduler.ValidateScheduleWithOptions(schedule, config, scheduler.ValidateOptions{NodeCapacity: nodes})
+	require.Len(t, warnings, 1)
+	assert.Equal(t, scheduler.SeverityError, warnings[0].Severity)
+	assert.Equal(t, "large", warnings[0].Step)
+}
+
+// TestValidateScheduleNodeCapacityError verifies a failing callback skips the capacity check
+func TestValidateScheduleNodeCapacityError(t *testing.T) {
+	schedule, config := buildValidationSchedule(t, nil,
+		c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.21", Commands: []string{"go build"}},
+	)
+
+	nodes := func() ([]scheduler.NodeCapacity, error) {
+		return nil, errors.New("cluster unreachable")
+	}
+
+	warnings := scheduler.ValidateScheduleWithOptions(schedule, config, scheduler.ValidateOptions{NodeCapacity: nodes})
+	require.Len(t, warnings, 1)
+	assert.Equal(t, scheduler.SeverityInfo, warnings[0].Severity)
+	assert.Contains(t, warnings[0].Message, "cluster unreachable")
+}
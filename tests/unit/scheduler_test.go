@@ -591,3 +591,71 @@ func TestScheduleGetStepDependents(t *testing.T) {
 	dependents = schedule.GetStepDependents("build")
 	assert.Empty(t, dependents)
 }
+
+// includeTestConfig returns a PipelineConfig with the given steps and includes
+func includeTestConfig(name string, steps []c8sv1alpha1.PipelineStep, includes ...string) *c8sv1alpha1.PipelineConfig {
+	config := &c8sv1alpha1.PipelineConfig{Spec: c8sv1alpha1.PipelineConfigSpec{Steps: steps}}
+	config.Name = name
+	for _, include := range includes {
+		config.Spec.Includes = append(config.Spec.Includes, c8sv1alpha1.IncludeRef{Name: include})
+	}
+	return config
+}
+
+// TestMergeSchedules verifies included steps are namespaced and cross-config dependencies resolved
+func TestMergeSchedules(t *testing.T) {
+	app := includeTestConfig("app", []c8sv1alpha1.PipelineStep{
+		{Name: "build", Image: "golang:1.25"},
+		{Name: "deploy", Image: "alpine:3.20", DependsOn: []string{"build", "shared/scan"}},
+	}, "shared")
+	shared := includeTestConfig("shared", []c8sv1alpha1.PipelineStep{
+		{Name: "lint", Image: "golangci/golangci-lint:v1.60"},
+		{Name: "scan", Image: "aquasec/trivy:0.55", DependsOn: []string{"lint", "app/build"}},
+	})
+
+	base, err := scheduler.BuildSchedule(app)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shared/scan"}, base.DAG.UnresolvedDependencies())
+
+	_, err = scheduler.BuildSchedule(shared)
+	require.Error(t, err, "app/build needs app to be included by shared")
+	shared.Spec.Includes = []c8sv1alpha1.IncludeRef{{Name: "app"}}
+	included, err := scheduler.BuildSchedule(shared)
+	require.NoError(t, err)
+
+	merged, err := scheduler.MergeSchedules(base, included)
+	require.NoError(t, err)
+	assert.Equal(t, "app", merged.Name)
+	assert.Equal(t, 4, merged.TotalSteps())
+	assert.Empty(t, merged.DAG.UnresolvedDependencies())
+
+	assert.ElementsMatch(t, []string{"shared/lint", "build"}, merged.GetStepDependencies("shared/scan"))
+	assert.Equal(t, 0, merged.GetLayer("build"))
+	assert.Equal(t, 1, merged.GetLayer("shared/scan"))
+	assert.Equal(t, 2, merged.GetLayer("deploy"))
+}
+
+// TestMergeSchedulesDetectsCrossConfigCycle verifies a cycle spanning both configs is rejected
+func TestMergeSchedulesDetectsCrossConfigCycle(t *testing.T) {
+	base, err := scheduler.BuildSchedule(includeTestConfig("app", []c8sv1alpha1.PipelineStep{
+		{Name: "build", Image: "golang:1.25", DependsOn: []string{"shared/scan"}},
+	}, "shared"))
+	require.NoError(t, err)
+	included, err := scheduler.BuildSchedule(includeTestConfig("shared", []c8sv1alpha1.PipelineStep{
+		{Name: "scan", Image: "aquasec/trivy:0.55", DependsOn: []string{"app/build"}},
+	}, "app"))
+	require.NoError(t, err)
+
+	_, err = scheduler.MergeSchedules(base, included)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, types.ErrInvalidDependencyGraph)
+}
+
+// TestBuildScheduleRejectsUnincludedConfig verifies dependencies on configs that are not included fail
+func TestBuildScheduleRejectsUnincludedConfig(t *testing.T) {
+	_, err := scheduler.BuildSchedule(includeTestConfig("app", []c8sv1alpha1.PipelineStep{
+		{Name: "deploy", Image: "alpine:3.20", DependsOn: []string{"other/build"}},
+	}))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, types.ErrStepNotFound)
+}
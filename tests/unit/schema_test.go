@@ -0,0 +1,248 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/org/c8s/pkg/parser"
+)
+
+// TestPipelineSchemaTopLevel verifies the root schema reflects PipelineYAML
+func TestPipelineSchemaTopLevel(t *testing.T) {
+	schema, err := parser.PipelineSchema()
+	require.NoError(t, err)
+
+	assert.Equal(t, parser.JSONSchemaDraft07, schema.Schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.NotEmpty(t, schema.Description)
+	assert.ElementsMatch(t, []string{"version", "name", "steps"}, schema.Required)
+	assert.Equal(t, []string{"v1alpha1"}, schema.Properties["version"].Enum)
+	assert.Equal(t, "Name identifies the pipeline", schema.Properties["name"].Description)
+	assert.Equal(t, false, schema.AdditionalProperties)
+}
+
+// TestPipelineSchemaSteps verifies nested step properties carry their constraints
+func TestPipelineSchemaSteps(t *testing.T) {
+	schema, err := parser.PipelineSchema()
+	require.NoError(t, err)
+
+	steps := schema.Properties["steps"]
+	require.NotNil(t, steps)
+	assert.Equal(t, "array", steps.Type)
+	require.NotNil(t, steps.MinItems)
+	assert.Equal(t, 1, *steps.MinItems)
+
+	step := steps.Items
+	require.NotNil(t, step)
+	assert.ElementsMatch(t, []string{"name", "image", "commands"}, step.Required)
+	assert.NotEmpty(t, step.Properties["name"].Pattern)
+	assert.Equal(t, "object", step.Properties["resources"].Type)
+	assert.Equal(t, "array", step.Properties["secrets"].Type)
+}
+
+// TestGenerateJSONSchema verifies the encoded schema is valid JSON
+func TestGenerateJSONSchema(t *testing.T) {
+	data, err := parser.GenerateJSONSchema()
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "C8S Pipeline", decoded["title"])
+	assert.Contains(t, decoded, "properties")
+}
+
+// TestPipelineSchemaPatterns verifies the patterns accept the values the
+// parser and Kubernetes accept
+func TestPipelineSchemaPatterns(t *testing.T) {
+	schema, err := parser.PipelineSchema()
+	require.NoError(t, err)
+	step := schema.Properties["steps"].Items
+	resources := step.Properties["resources"]
+
+	tests := []struct {
+		name    string
+		pattern string
+		valid   []string
+		invalid []string
+	}{
+		{
+			name:    "timeout",
+			pattern: step.Properties["timeout"].Pattern,
+			valid:   []string{"30m", "2h", "1h30m", "90s500ms", "1.5h", "0"},
+			invalid: []string{"", "30", "30 minutes", "-5m", "1d"},
+		},
+		{
+			name:    "pipeline timeout",
+			pattern: schema.Properties["timeout"].Pattern,
+			valid:   []string{"1h30m"},
+			invalid: []string{"1h 30m"},
+		},
+		{
+			name:    "cpu",
+			pattern: resources.Properties["cpu"].Pattern,
+			valid:   []string{"500m", "2", "0.5", ".5", "1e3"},
+			invalid: []string{"", "half", "500 m", "2cores"},
+		},
+		{
+			name:    "memory",
+			pattern: resources.Properties["memory"].Pattern,
+			valid:   []string{"512Mi", "1Gi", "512M", "1G", "1Ki", "1073741824"},
+			invalid: []string{"", "1GB", "512 Mi", "lots"},
+		},
+		{
+			name:    "step name",
+			pattern: step.Properties["name"].Pattern,
+			valid:   []string{"build", "unit_tests", "shared/lint"},
+			invalid: []string{"", "build step", "build.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := regexp.Compile(tt.pattern)
+			require.NoError(t, err)
+			for _, value := range tt.valid {
+				assert.True(t, re.MatchString(value), "%q should match %s", value, tt.pattern)
+			}
+			for _, value := range tt.invalid {
+				assert.False(t, re.MatchString(value), "%q should not match %s", value, tt.pattern)
+			}
+		})
+	}
+}
+
+// TestPipelineSchemaExamples verifies the example pipelines of the docs are
+// valid against the schema
+func TestPipelineSchemaExamples(t *testing.T) {
+	schema, err := parser.PipelineSchema()
+	require.NoError(t, err)
+
+	docs, err := filepath.Glob("../../docs/*.md")
+	require.NoError(t, err)
+	docs = append(docs, "../../README.md")
+
+	examples := 0
+	for _, doc := range docs {
+		content, err := os.ReadFile(doc)
+		require.NoError(t, err)
+
+		for i, example := range examplePipelines(string(content)) {
+			var value interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(example), &value), "%s example %d", doc, i+1)
+			assert.Empty(t, schemaViolations(schema, value, ""), "%s example %d", doc, i+1)
+			examples++
+		}
+	}
+	assert.NotZero(t, examples)
+}
+
+// examplePipelines returns the YAML code blocks of a Markdown document that
+// are complete pipelines, skipping those with parts elided by "# ..."
+func examplePipelines(markdown string) []string {
+	var examples []string
+	for _, block := range regexp.MustCompile("(?s)```yaml\n(.*?)```").FindAllStringSubmatch(markdown, -1) {
+		if strings.HasPrefix(block[1], "version: v1alpha1") && !strings.Contains(block[1], "# ...") {
+			examples = append(examples, block[1])
+		}
+	}
+	return examples
+}
+
+// schemaViolations returns where value doesn't match schema. It supports the
+// keywords PipelineSchema generates.
+func schemaViolations(schema *parser.JSONSchema, value interface{}, path string) []string {
+	var violations []string
+	add := func(format string, args ...interface{}) {
+		violations = append(violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			add("expected object, got %T", value)
+			return violations
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				add("missing required property %q", name)
+			}
+		}
+		for name, property := range object {
+			if propertySchema, ok := schema.Properties[name]; ok {
+				violations = append(violations, schemaViolations(propertySchema, property, path+"."+name)...)
+			} else if additional, ok := schema.AdditionalProperties.(*parser.JSONSchema); ok {
+				violations = append(violations, schemaViolations(additional, property, path+"."+name)...)
+			} else if schema.AdditionalProperties == false {
+				add("unknown property %q", name)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			add("expected array, got %T", value)
+			return violations
+		}
+		if schema.MinItems != nil && len(items) < *schema.MinItems {
+			add("expected at least %d items", *schema.MinItems)
+		}
+		for i, item := range items {
+			violations = append(violations, schemaViolations(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			add("expected string, got %T", value)
+			return violations
+		}
+		if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, s) {
+			add("%q is not one of %v", s, schema.Enum)
+		}
+		if schema.Pattern != "" && !regexp.MustCompile(schema.Pattern).MatchString(s) {
+			add("%q does not match %s", s, schema.Pattern)
+		}
+	case "integer":
+		n, ok := value.(int)
+		if !ok {
+			add("expected integer, got %T", value)
+			return violations
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			add("%d is less than %d", n, *schema.Minimum)
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			add("%d is greater than %d", n, *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			add("expected boolean, got %T", value)
+		}
+	}
+	return violations
+}
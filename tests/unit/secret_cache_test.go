@@ -0,0 +1,186 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/secrets"
+)
+
+func TestSecretCache(t *testing.T) {
+	c := secrets.NewSecretCache()
+
+	_, ok := c.Get("default", "token")
+	assert.False(t, ok)
+
+	c.Set("default", "token", "1", map[string][]byte{"value": []byte("old")})
+	data, ok := c.Get("default", "token")
+	require.True(t, ok)
+	assert.Equal(t, "old", string(data["value"]))
+
+	// A new version replaces the previous one
+	c.Set("default", "token", "2", map[string][]byte{"value": []byte("new")})
+	data, ok = c.Get("default", "token")
+	require.True(t, ok)
+	assert.Equal(t, "new", string(data["value"]))
+	assert.Equal(t, 1, c.Len())
+
+	// Secrets with the same name in other namespaces are distinct
+	c.Set("other", "token", "1", map[string][]byte{"value": []byte("other")})
+	assert.Equal(t, 2, c.Len())
+
+	c.Invalidate("default", "token")
+	_, ok = c.Get("default", "token")
+	assert.False(t, ok)
+	_, ok = c.Get("other", "token")
+	assert.True(t, ok)
+
+	assert.Equal(t, "default/token/2", secrets.CacheKey("default", "token", "2"))
+}
+
+func TestSecretCache_Concurrent(t *testing.T) {
+	c := secrets.NewSecretCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Set("default", "token", "1", map[string][]byte{"value": []byte("x")})
+				c.Invalidate("default", "token")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Get("default", "token")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSecretRotationWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "default", ResourceVersion: "1"},
+		Data:       map[string][]byte{"value": []byte("old")},
+	}
+	client := fake.NewSimpleClientset(secret)
+
+	c := secrets.NewSecretCache()
+	c.Set("default", "token", "1", secret.Data)
+	c.Set("default", "unrelated", "1", map[string][]byte{"value": []byte("kept")})
+
+	watcher := secrets.NewSecretRotationWatcher(client, c, "")
+	done := make(chan error, 1)
+	go func() { done <- watcher.Start(ctx) }()
+
+	// Wait until the informer has listed the Secret before rotating it
+	time.Sleep(200 * time.Millisecond)
+	_, ok := c.Get("default", "token")
+	require.True(t, ok, "initial add events must not invalidate the cache")
+
+	rotated := secret.DeepCopy()
+	rotated.ResourceVersion = "2"
+	rotated.Data["value"] = []byte("new")
+	_, err := client.CoreV1().Secrets("default").Update(ctx, rotated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, ok := c.Get("default", "token")
+		return !ok
+	}, 5*time.Second, 20*time.Millisecond, "rotated secret should be invalidated")
+
+	_, ok = c.Get("default", "unrelated")
+	assert.True(t, ok)
+
+	// Deleting a Secret also invalidates it
+	c.Set("default", "token", "2", rotated.Data)
+	require.NoError(t, client.CoreV1().Secrets("default").Delete(ctx, "token", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		_, ok := c.Get("default", "token")
+		return !ok
+	}, 5*time.Second, 20*time.Millisecond, "deleted secret should be invalidated")
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not stop")
+	}
+}
+
+func TestLogCollectorSecretCache(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "default", ResourceVersion: "1"},
+		Data:       map[string][]byte{"value": []byte("s3cr3t")},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1-build-abcde", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "step"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+	}
+	client := fake.NewSimpleClientset(secret, pod)
+	config := &c8sv1alpha1.PipelineConfig{
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Steps: []c8sv1alpha1.PipelineStep{{
+				Name:    "build",
+				Secrets: []c8sv1alpha1.SecretReference{{SecretRef: "token", Key: "value"}},
+			}},
+		},
+	}
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+
+	c := secrets.NewSecretCache()
+	collector := controller.NewLogCollector(client, nil)
+	collector.SetSecretCache(c)
+
+	for i := 0; i < 2; i++ {
+		_, err := collector.CollectAndUpload(ctx, pod, run, "build", config)
+		require.NoError(t, err)
+	}
+
+	// The Secret is read once, then served from the cache
+	gets := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "get" && action.GetResource().Resource == "secrets" {
+			gets++
+		}
+	}
+	assert.Equal(t, 1, gets)
+	data, ok := c.Get("default", "token")
+	require.True(t, ok)
+	assert.Equal(t, "s3cr3t", string(data["value"]))
+}
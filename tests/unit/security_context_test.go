@@ -0,0 +1,133 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// securityContextTestConfig returns a PipelineConfig with a default security context
+// and a /tmp scratch volume
+func securityContextTestConfig(steps ...c8sv1alpha1.PipelineStep) *c8sv1alpha1.PipelineConfig {
+	return &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-config", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/org/repo",
+			Steps:      steps,
+			DefaultSecurityContext: &c8sv1alpha1.SecurityContextSpec{
+				RunAsUser:    1000,
+				RunAsNonRoot: true,
+			},
+			Volumes: []c8sv1alpha1.VolumeSpec{
+				{Name: "tmp", EmptyDir: &c8sv1alpha1.EmptyDirVolumeSpec{Medium: "Memory", SizeLimit: "256Mi"}},
+			},
+		},
+	}
+}
+
+// TestCreateJobForStepSecurityContext verifies step containers get the step's security
+// context, or else the PipelineConfig default
+func TestCreateJobForStepSecurityContext(t *testing.T) {
+	hardened := volumeTestStep("build", "tmp")
+	hardened.VolumeMounts[0].MountPath = "/tmp"
+	hardened.SecurityContext = &c8sv1alpha1.SecurityContextSpec{
+		RunAsUser:              2000,
+		RunAsGroup:             3000,
+		RunAsNonRoot:           true,
+		ReadOnlyRootFilesystem: true,
+	}
+	plain := volumeTestStep("test")
+	config := securityContextTestConfig(hardened, plain)
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"},
+		Spec:       c8sv1alpha1.PipelineRunSpec{Commit: "abc1234", Branch: "main"},
+	}
+	jm := controller.NewJobManager(config.Spec.Repository)
+
+	job, err := jm.CreateJobForStep(&hardened, run, config)
+	require.NoError(t, err)
+	securityContext := job.Spec.Template.Spec.Containers[0].SecurityContext
+	require.NotNil(t, securityContext)
+	assert.Equal(t, int64(2000), *securityContext.RunAsUser)
+	assert.Equal(t, int64(3000), *securityContext.RunAsGroup)
+	assert.True(t, *securityContext.RunAsNonRoot)
+	assert.True(t, *securityContext.ReadOnlyRootFilesystem)
+	assert.False(t, *securityContext.AllowPrivilegeEscalation)
+
+	volumes := job.Spec.Template.Spec.Volumes
+	require.Len(t, volumes, 2)
+	require.NotNil(t, volumes[1].EmptyDir)
+	assert.Equal(t, "256Mi", volumes[1].EmptyDir.SizeLimit.String())
+
+	job, err = jm.CreateJobForStep(&plain, run, config)
+	require.NoError(t, err)
+	securityContext = job.Spec.Template.Spec.Containers[0].SecurityContext
+	require.NotNil(t, securityContext)
+	assert.Equal(t, int64(1000), *securityContext.RunAsUser)
+	assert.Nil(t, securityContext.RunAsGroup, "a zero group keeps the image default")
+	assert.False(t, *securityContext.ReadOnlyRootFilesystem)
+
+	config.Spec.DefaultSecurityContext = nil
+	job, err = jm.CreateJobForStep(&plain, run, config)
+	require.NoError(t, err)
+	assert.Nil(t, job.Spec.Template.Spec.Containers[0].SecurityContext)
+}
+
+// TestWarnings_ReadOnlyRootFilesystem verifies a read-only root filesystem without a
+// writable /tmp volume is reported
+func TestWarnings_ReadOnlyRootFilesystem(t *testing.T) {
+	step := volumeTestStep("build", "tmp")
+	step.VolumeMounts[0].MountPath = "/tmp"
+	config := securityContextTestConfig(step, volumeTestStep("test"))
+	require.NoError(t, parser.Validate(config))
+	assert.Empty(t, parser.Warnings(config))
+
+	config.Spec.DefaultSecurityContext.ReadOnlyRootFilesystem = true
+	warnings := parser.Warnings(config)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "spec.defaultSecurityContext.readOnlyRootFilesystem", warnings[0].Field)
+	assert.Contains(t, warnings[0].Message, "step test")
+
+	// A read-only /tmp mount doesn't help
+	config.Spec.Steps[0].VolumeMounts[0].ReadOnly = true
+	config.Spec.Steps[1].SecurityContext = &c8sv1alpha1.SecurityContextSpec{}
+	warnings = parser.Warnings(config)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0].Message, "step build")
+	assert.Contains(t, warnings[0].Message, "/tmp")
+}
+
+// TestVolumeValidation_EmptyDir verifies emptyDir volumes are validated like other sources
+func TestVolumeValidation_EmptyDir(t *testing.T) {
+	config := securityContextTestConfig(volumeTestStep("build", "tmp"))
+	require.NoError(t, parser.Validate(config))
+
+	config.Spec.Volumes[0].EmptyDir.SizeLimit = "lots"
+	assert.ErrorContains(t, parser.Validate(config), "invalid sizeLimit quantity")
+
+	config.Spec.Volumes[0].EmptyDir.SizeLimit = ""
+	config.Spec.Volumes[0].ConfigMap = &c8sv1alpha1.ConfigMapVolumeSpec{Name: "settings"}
+	assert.ErrorContains(t, parser.Validate(config), "only one of persistentVolumeClaim, configMap or emptyDir")
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/localenv/cluster"
+	"github.com/org/c8s/pkg/types"
+)
+
+// TestClusterErrors_MatchSentinels verifies the typed cluster errors match
+// their sentinel through any number of wrappings
+func TestClusterErrors_MatchSentinels(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+		is       func(error) bool
+	}{
+		{"not found", &cluster.ClusterNotFoundError{Name: "dev"}, types.ErrClusterNotFound, cluster.IsClusterNotFoundError},
+		{"already exists", &cluster.ClusterAlreadyExistsError{Name: "dev"}, types.ErrClusterAlreadyExists, cluster.IsClusterAlreadyExistsError},
+		{"docker", &cluster.DockerNotAvailableError{Err: errors.New("connection refused")}, types.ErrDockerNotAvailable, cluster.IsDockerNotAvailableError},
+		{"not ready", &cluster.ClusterNotReadyError{Name: "dev"}, types.ErrTimeoutExceeded, cluster.IsTimeoutError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", tt.err))
+			enhanced := cluster.EnhanceError(wrapped, "create")
+
+			for _, err := range []error{tt.err, wrapped, enhanced} {
+				assert.ErrorIs(t, err, tt.sentinel)
+				assert.True(t, tt.is(err))
+			}
+
+			assert.Contains(t, enhanced.Error(), tt.err.Error())
+
+			var suggestion *cluster.ErrorWithSuggestion
+			assert.True(t, errors.As(enhanced, &suggestion), "EnhanceError should add a suggestion")
+		})
+	}
+}
+
+// TestClusterNotFoundError_FieldsReachable verifies the name of a missing
+// cluster can still be read from a wrapped error
+func TestClusterNotFoundError_FieldsReachable(t *testing.T) {
+	err := cluster.EnhanceError(fmt.Errorf("migrate: %w", &cluster.ClusterNotFoundError{Name: "dev"}), "migrate")
+
+	var notFound *cluster.ClusterNotFoundError
+	require.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "dev", notFound.Name)
+}
+
+// TestClusterErrors_WrappedSentinels verifies errors wrapping a sentinel
+// with fmt.Errorf are detected without a typed error
+func TestClusterErrors_WrappedSentinels(t *testing.T) {
+	err := fmt.Errorf("failed to pause cluster dev: %w", types.ErrClusterNotFound)
+	assert.True(t, cluster.IsClusterNotFoundError(err))
+	assert.False(t, cluster.IsClusterAlreadyExistsError(err))
+	assert.False(t, cluster.IsTimeoutError(err))
+
+	assert.True(t, cluster.IsTimeoutError(fmt.Errorf("%w waiting for cluster 'dev' to be ready", types.ErrTimeoutExceeded)))
+	assert.True(t, cluster.IsTimeoutError(fmt.Errorf("wait: %w", context.DeadlineExceeded)))
+	assert.False(t, cluster.IsTimeoutError(errors.New("failed to create cluster")))
+	assert.False(t, cluster.IsTimeoutError(nil))
+
+	// ErrTimeout is kept as an alias
+	assert.ErrorIs(t, fmt.Errorf("x: %w", types.ErrTimeout), types.ErrTimeoutExceeded)
+}
+
+// TestDockerNotAvailableError_KeepsCause verifies the cause of a Docker error
+// stays in the chain next to the sentinel
+func TestDockerNotAvailableError_KeepsCause(t *testing.T) {
+	cause := errors.New("Cannot connect to the Docker daemon")
+	err := fmt.Errorf("create: %w", &cluster.DockerNotAvailableError{Err: cause})
+
+	assert.ErrorIs(t, err, types.ErrDockerNotAvailable)
+	assert.ErrorIs(t, err, cause)
+}
+
+// TestEnhanceError_MissingCommand verifies missing executables are detected
+// from the exec error rather than its message
+func TestEnhanceError_MissingCommand(t *testing.T) {
+	err := fmt.Errorf("k3d command failed: %w", &exec.Error{Name: "k3d", Err: exec.ErrNotFound})
+	var suggestion *cluster.ErrorWithSuggestion
+	require.True(t, errors.As(cluster.EnhanceError(err, "create"), &suggestion))
+	assert.Contains(t, suggestion.Suggestion, "k3d is not installed")
+
+	// A message mentioning k3d and "not found" isn't a missing executable
+	err = errors.New("k3d command failed: image not found")
+	assert.Equal(t, err, cluster.EnhanceError(err, "create"))
+}
+
+// TestConfigResolvedCondition_WrappedNotFound verifies the controller's
+// wrapped missing PipelineConfig error keeps both the sentinel and the API
+// error in its chain
+func TestConfigResolvedCondition_WrappedNotFound(t *testing.T) {
+	apiErr := apierrors.NewNotFound(schema.GroupResource{Group: "c8s.dev", Resource: "pipelineconfigs"}, "ci")
+	err := fmt.Errorf("%w: %s: %w", types.ErrPipelineConfigNotFound, "ci", apiErr)
+	assert.ErrorIs(t, err, types.ErrPipelineConfigNotFound)
+	assert.True(t, apierrors.IsNotFound(err))
+
+	run := &c8sv1alpha1.PipelineRun{Spec: c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "ci"}}
+	controller.NewStatusUpdater(nil).SetConfigResolvedCondition(run, fmt.Errorf("%w: ci", types.ErrPipelineConfigNotFound))
+	cond := apimeta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeConfigResolved)
+	require.NotNil(t, cond)
+	assert.Equal(t, types.ReasonPipelineConfigNotFound, cond.Reason)
+}
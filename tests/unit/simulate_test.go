@@ -0,0 +1,102 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/org/c8s/pkg/api/handlers"
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/scheduler"
+)
+
+// simulationConfig returns a pipeline where lint and test run in parallel
+// and build waits for both
+func simulationConfig() *c8sv1alpha1.PipelineConfig {
+	return &c8sv1alpha1.PipelineConfig{
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Steps: []c8sv1alpha1.PipelineStep{
+				{Name: "lint", Image: "golang:1.21", Timeout: "5m"},
+				{Name: "test", Image: "golang:1.21", Timeout: "10m"},
+				{Name: "build", Image: "golang:1.21", Timeout: "2m30s", DependsOn: []string{"lint", "test"}},
+			},
+		},
+	}
+}
+
+// TestSimulateDiamond verifies steps start when their slowest dependency finishes
+func TestSimulateDiamond(t *testing.T) {
+	schedule, err := scheduler.BuildSchedule(simulationConfig())
+	require.NoError(t, err)
+
+	result := scheduler.Simulate(schedule, 0)
+	assert.Equal(t, 12*time.Minute+30*time.Second, result.EstimatedDuration)
+	assert.Equal(t, []string{"test", "build"}, result.CriticalPath)
+	assert.Equal(t, [2]time.Duration{0, 5 * time.Minute}, result.WallClockByStep["lint"])
+	assert.Equal(t, [2]time.Duration{10 * time.Minute, 12*time.Minute + 30*time.Second}, result.WallClockByStep["build"])
+}
+
+// TestSimulateParallelism verifies a parallelism limit serializes independent steps
+func TestSimulateParallelism(t *testing.T) {
+	schedule, err := scheduler.BuildSchedule(simulationConfig())
+	require.NoError(t, err)
+
+	result := scheduler.Simulate(schedule, 1)
+	assert.Equal(t, 17*time.Minute+30*time.Second, result.EstimatedDuration)
+	assert.Len(t, result.WallClockByStep, 3)
+}
+
+// TestEstimateStepDuration verifies steps without a valid timeout use the default
+func TestEstimateStepDuration(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, scheduler.EstimateStepDuration(&c8sv1alpha1.PipelineStep{Timeout: "5m"}))
+	assert.Equal(t, 30*time.Minute, scheduler.EstimateStepDuration(&c8sv1alpha1.PipelineStep{}))
+	assert.Equal(t, 30*time.Minute, scheduler.EstimateStepDuration(&c8sv1alpha1.PipelineStep{Timeout: "soon"}))
+}
+
+// TestEstimatePipelineRun verifies run progress is derived from the simulated duration
+func TestEstimatePipelineRun(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	run := &c8sv1alpha1.PipelineRun{}
+
+	estimate, err := handlers.EstimatePipelineRun(run, simulationConfig(), start)
+	require.NoError(t, err)
+	assert.Equal(t, "12m30s", estimate.EstimatedDuration)
+	assert.Equal(t, 0, estimate.Progress)
+	assert.Nil(t, estimate.EstimatedCompletion)
+
+	run.Status.StartTime = &metav1.Time{Time: start}
+	estimate, err = handlers.EstimatePipelineRun(run, simulationConfig(), start.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 40, estimate.Progress)
+	require.NotNil(t, estimate.EstimatedCompletion)
+	assert.Equal(t, start.Add(12*time.Minute+30*time.Second), estimate.EstimatedCompletion.Time)
+
+	// Overrunning the estimate never reports a running pipeline as done
+	estimate, err = handlers.EstimatePipelineRun(run, simulationConfig(), start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 99, estimate.Progress)
+
+	run.Status.CompletionTime = &metav1.Time{Time: start.Add(time.Hour)}
+	estimate, err = handlers.EstimatePipelineRun(run, simulationConfig(), start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 100, estimate.Progress)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/scheduler"
+)
+
+// dedupSteps returns a pipeline where unit and unit-again are identical
+func dedupSteps() []c8sv1alpha1.PipelineStep {
+	return []c8sv1alpha1.PipelineStep{
+		{Name: "checkout", Image: "alpine/git", Commands: []string{"git clone ."}},
+		{Name: "generate", Image: "golang:1.21", Commands: []string{"go generate ./..."}},
+		{Name: "unit", Image: "golang:1.21", Commands: []string{"go test ./..."}, DependsOn: []string{"checkout"}},
+		{Name: "unit-again", Image: "golang:1.21", Commands: []string{"go test ./..."}, DependsOn: []string{"generate"}, Weight: 3},
+		{Name: "build", Image: "golang:1.21", Commands: []string{"go build ./..."}, DependsOn: []string{"unit-again"}},
+	}
+}
+
+// TestDeduplicateSteps verifies identical steps are merged into the first,
+// which waits for the dependencies of both, and that dependents of the
+// merged step are rewritten
+func TestDeduplicateSteps(t *testing.T) {
+	steps := dedupSteps()
+	deduplicated := scheduler.DeduplicateSteps(steps)
+
+	require.Len(t, deduplicated, 4)
+	byName := map[string]c8sv1alpha1.PipelineStep{}
+	for _, step := range deduplicated {
+		byName[step.Name] = step
+	}
+	require.NotContains(t, byName, "unit-again")
+	assert.Equal(t, []string{"checkout", "generate"}, byName["unit"].DependsOn)
+	assert.Equal(t, 3, byName["unit"].Weight)
+	assert.Equal(t, []string{"unit"}, byName["build"].DependsOn)
+
+	// The input steps are left unchanged
+	assert.Len(t, steps, 5)
+	assert.Equal(t, []string{"unit-again"}, steps[4].DependsOn)
+
+	assert.Equal(t, map[string]string{"unit-again": "unit"}, scheduler.StepAliases(steps))
+}
+
+// TestDeduplicateStepsKeepsDistinctSteps verifies steps differing in
+// configuration, using secrets or artifacts, or depending on each other are
+// not merged
+func TestDeduplicateStepsKeepsDistinctSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []c8sv1alpha1.PipelineStep
+	}{
+		{
+			name: "different commands",
+			steps: []c8sv1alpha1.PipelineStep{
+				{Name: "a", Image: "golang:1.21", Commands: []string{"go test ./..."}},
+				{Name: "b", Image: "golang:1.21", Commands: []string{"go vet ./..."}},
+			},
+		},
+		{
+			name: "secrets",
+			steps: []c8sv1alpha1.PipelineStep{
+				{Name: "a", Image: "alpine", Commands: []string{"deploy"}, Secrets: []c8sv1alpha1.SecretReference{{SecretRef: "creds", Key: "token", EnvVar: "TOKEN"}}},
+				{Name: "b", Image: "alpine", Commands: []string{"deploy"}, Secrets: []c8sv1alpha1.SecretReference{{SecretRef: "creds", Key: "token", EnvVar: "TOKEN"}}},
+			},
+		},
+		{
+			name: "artifacts",
+			steps: []c8sv1alpha1.PipelineStep{
+				{Name: "a", Image: "alpine", Commands: []string{"make"}, Artifacts: []string{"bin/"}},
+				{Name: "b", Image: "alpine", Commands: []string{"make"}, Artifacts: []string{"bin/"}},
+			},
+		},
+		{
+			name: "transitive dependency",
+			steps: []c8sv1alpha1.PipelineStep{
+				{Name: "a", Image: "alpine", Commands: []string{"make"}},
+				{Name: "middle", Image: "alpine", Commands: []string{"true"}, DependsOn: []string{"a"}},
+				{Name: "b", Image: "alpine", Commands: []string{"make"}, DependsOn: []string{"middle"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, scheduler.DeduplicateSteps(tt.steps), len(tt.steps))
+			assert.Empty(t, scheduler.StepAliases(tt.steps))
+		})
+	}
+}
+
+// TestBuildScheduleDeduplicateSteps verifies steps are only merged when
+// spec.deduplicateSteps is set
+func TestBuildScheduleDeduplicateSteps(t *testing.T) {
+	config := &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci"},
+		Spec:       c8sv1alpha1.PipelineConfigSpec{Steps: dedupSteps()},
+	}
+
+	schedule, err := scheduler.BuildSchedule(config)
+	require.NoError(t, err)
+	assert.Equal(t, 5, schedule.TotalSteps())
+	assert.Nil(t, schedule.Aliases)
+
+	config.Spec.DeduplicateSteps = true
+	schedule, err = scheduler.BuildSchedule(config)
+	require.NoError(t, err)
+	assert.Equal(t, 4, schedule.TotalSteps())
+	assert.Equal(t, map[string]string{"unit-again": "unit"}, schedule.Aliases)
+
+	_, exists := schedule.DAG.GetStep("unit-again")
+	assert.False(t, exists)
+}
+
+// TestMirrorStepAliases verifies alias steps get a copy of the status of
+// the step they were merged into once it has one
+func TestMirrorStepAliases(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{}
+	aliases := map[string]string{"unit-again": "unit"}
+
+	controller.MirrorStepAliases(run, aliases)
+	assert.Empty(t, run.Status.Steps)
+
+	run.Status.Steps = []c8sv1alpha1.StepStatus{
+		{Name: "unit", Phase: c8sv1alpha1.StepPhaseRunning, JobName: "run-unit"},
+	}
+	controller.MirrorStepAliases(run, aliases)
+	alias := controller.GetStepStatus(run, "unit-again")
+	require.NotNil(t, alias)
+	assert.Equal(t, c8sv1alpha1.StepPhaseRunning, alias.Phase)
+	assert.Equal(t, "run-unit", alias.JobName)
+	assert.Equal(t, "unit", alias.AliasOf)
+
+	// Alias steps share the Job of their step and are never orphaned
+	orphaned := controller.NewPipelineRunGarbageCollector().OrphanedSteps(run, map[string]*batchv1.Job{})
+	assert.Equal(t, []string{"unit"}, orphaned)
+
+	run.Status.Steps[0].Phase = c8sv1alpha1.StepPhaseSucceeded
+	controller.MirrorStepAliases(run, aliases)
+	assert.Len(t, run.Status.Steps, 2)
+	assert.Equal(t, c8sv1alpha1.StepPhaseSucceeded, controller.GetStepStatus(run, "unit-again").Phase)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+)
+
+// historyPhases returns the phases recorded in a step's history
+func historyPhases(status *c8sv1alpha1.StepStatus) []c8sv1alpha1.StepPhase {
+	var phases []c8sv1alpha1.StepPhase
+	for _, transition := range status.History {
+		phases = append(phases, transition.Phase)
+	}
+	return phases
+}
+
+// TestStepStatusHistoryRecord verifies repeated phases are recorded once
+// and the history keeps the latest transitions up to its limit
+func TestStepStatusHistoryRecord(t *testing.T) {
+	now := metav1.Now()
+	status := &c8sv1alpha1.StepStatus{Name: "build"}
+
+	history := controller.StepStatusHistory{}
+	history.Record(status, c8sv1alpha1.StepPhasePending, "", now)
+	history.Record(status, c8sv1alpha1.StepPhasePending, "", now)
+	history.Record(status, c8sv1alpha1.StepPhaseRunning, "", now)
+	assert.Equal(t, []c8sv1alpha1.StepPhase{c8sv1alpha1.StepPhasePending, c8sv1alpha1.StepPhaseRunning}, historyPhases(status))
+
+	for i := 0; i < 2*controller.DefaultStepStatusHistoryLimit; i++ {
+		phase := c8sv1alpha1.StepPhaseFailed
+		if i%2 == 1 {
+			phase = c8sv1alpha1.StepPhaseRunning
+		}
+		history.Record(status, phase, fmt.Sprintf("transition %d", i), now)
+	}
+	require.Len(t, status.History, controller.DefaultStepStatusHistoryLimit)
+	assert.Equal(t, "transition 10", status.History[0].Message)
+	assert.Equal(t, "transition 19", status.History[len(status.History)-1].Message)
+
+	limited := &c8sv1alpha1.StepStatus{Name: "build"}
+	for _, phase := range []c8sv1alpha1.StepPhase{c8sv1alpha1.StepPhasePending, c8sv1alpha1.StepPhaseRunning, c8sv1alpha1.StepPhaseSucceeded} {
+		controller.StepStatusHistory{Limit: 2}.Record(limited, phase, "", now)
+	}
+	assert.Equal(t, []c8sv1alpha1.StepPhase{c8sv1alpha1.StepPhaseRunning, c8sv1alpha1.StepPhaseSucceeded}, historyPhases(limited))
+}
+
+// TestStepStatusHistoryRetriedStep verifies the status updater records the
+// transitions of a step through a failed attempt that was retried
+func TestStepStatusHistoryRetriedStep(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name: "run-1", Namespace: "default", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+	}}
+	job := stepJob("test", batchv1.JobStatus{})
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(run, job).
+		WithStatusSubresource(run).
+		Build()
+	su := controller.NewStatusUpdater(c)
+	jobs := map[string]*batchv1.Job{"test": job}
+	policies := map[string]*c8sv1alpha1.RetryPolicy{"test": {MaxRetries: 2}}
+
+	start := metav1.Now()
+	for _, status := range []batchv1.JobStatus{
+		{},
+		{Active: 1, StartTime: &start},
+		{Active: 1, Failed: 1, StartTime: &start},
+		{Succeeded: 1, Failed: 1, StartTime: &start},
+	} {
+		job.Status = status
+		require.NoError(t, su.UpdatePipelineRunStatus(context.Background(), run, jobs, policies, 1))
+	}
+
+	status := controller.GetStepStatus(run, "test")
+	require.NotNil(t, status)
+	assert.Equal(t, []c8sv1alpha1.StepPhase{
+		c8sv1alpha1.StepPhasePending,
+		c8sv1alpha1.StepPhaseRunning,
+		c8sv1alpha1.StepPhaseFailed,
+		c8sv1alpha1.StepPhaseRunning,
+		c8sv1alpha1.StepPhaseSucceeded,
+	}, historyPhases(status))
+	assert.Equal(t, "Attempt 1 failed, retrying", status.History[2].Message)
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/scheduler"
+	"github.com/org/c8s/pkg/types"
+)
+
+// stepMatrixConfig returns a pipeline where lint runs once, test runs per
+// os and go version, and package runs per os
+func stepMatrixConfig() *c8sv1alpha1.PipelineConfig {
+	return &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "platforms", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/org/repo",
+			Matrix: &c8sv1alpha1.MatrixStrategy{
+				Dimensions: map[string][]string{
+					"os": {"ubuntu", "alpine"},
+					"go": {"1.21", "1.22"},
+				},
+				Exclude: []map[string]string{{"os": "alpine", "go": "1.21"}},
+			},
+			Steps: []c8sv1alpha1.PipelineStep{
+				{Name: "lint", Image: "golangci/golangci-lint", Commands: []string{"golangci-lint run"}},
+				{
+					Name:             "test",
+					Image:            "golang:${{matrix.go}}",
+					Commands:         []string{"go test ./... # ${{matrix.os}}"},
+					DependsOn:        []string{"lint"},
+					MatrixDimensions: []string{"os", "go"},
+				},
+				{
+					Name:             "package",
+					Image:            "ubuntu:22.04",
+					Commands:         []string{"./package.sh"},
+					DependsOn:        []string{"test"},
+					MatrixDimensions: []string{"os"},
+				},
+				{Name: "publish", Image: "ubuntu:22.04", Commands: []string{"./publish.sh"}, DependsOn: []string{"package"}},
+			},
+		},
+	}
+}
+
+// TestExpandStepMatrix verifies matrix-aware steps are expanded per unique
+// combination of their dimensions and dependencies follow shared values
+func TestExpandStepMatrix(t *testing.T) {
+	config := stepMatrixConfig()
+
+	steps, values, err := scheduler.ExpandStepMatrix(config.Spec.Steps, config.Spec.Matrix)
+	require.NoError(t, err)
+
+	byName := make(map[string]c8sv1alpha1.PipelineStep, len(steps))
+	var names []string
+	for _, step := range steps {
+		byName[step.Name] = step
+		names = append(names, step.Name)
+	}
+	assert.Equal(t, []string{
+		"lint",
+		"test-ubuntu-1-21", "test-alpine-1-22", "test-ubuntu-1-22",
+		"package-alpine", "package-ubuntu",
+		"publish",
+	}, names)
+
+	assert.Equal(t, map[string]string{"os": "alpine", "go": "1.22"}, values["test-alpine-1-22"])
+	assert.Equal(t, map[string]string{"os": "ubuntu"}, values["package-ubuntu"])
+	assert.NotContains(t, values, "lint")
+
+	assert.Equal(t, "golang:1.22", byName["test-alpine-1-22"].Image)
+	assert.Equal(t, []string{"go test ./... # alpine"}, byName["test-alpine-1-22"].Commands)
+	assert.Equal(t, []string{"lint"}, byName["test-ubuntu-1-21"].DependsOn)
+	assert.Equal(t, []string{"test-ubuntu-1-21", "test-ubuntu-1-22"}, byName["package-ubuntu"].DependsOn)
+	assert.Equal(t, []string{"test-alpine-1-22"}, byName["package-alpine"].DependsOn)
+	assert.Equal(t, []string{"package-alpine", "package-ubuntu"}, byName["publish"].DependsOn)
+
+	// The original steps are left unchanged
+	assert.Equal(t, "test", config.Spec.Steps[1].Name)
+	assert.Equal(t, []string{"test"}, config.Spec.Steps[2].DependsOn)
+}
+
+// TestExpandStepMatrixOrder verifies a step listing every dimension expands
+// in the order of the combinations ExpandMatrix yields, whatever the order
+// of its dimensions
+func TestExpandStepMatrixOrder(t *testing.T) {
+	matrix := &c8sv1alpha1.MatrixStrategy{
+		Dimensions: map[string][]string{
+			"os":   {"ubuntu", "alpine", "windows"},
+			"go":   {"1.22", "1.21"},
+			"arch": {"arm64", "amd64"},
+		},
+		Exclude: []map[string]string{{"arch": "amd64", "os": "alpine"}},
+	}
+	combinations, err := scheduler.ExpandMatrix(matrix)
+	require.NoError(t, err)
+
+	for _, dimensions := range [][]string{{"os", "go", "arch"}, {"arch", "go", "os"}, {"go", "os", "arch"}} {
+		steps := []c8sv1alpha1.PipelineStep{{Name: "test", Image: "golang", Commands: []string{"go test"}, MatrixDimensions: dimensions}}
+		expanded, values, err := scheduler.ExpandStepMatrix(steps, matrix)
+		require.NoError(t, err)
+
+		var got []map[string]string
+		for _, step := range expanded {
+			got = append(got, values[step.Name])
+		}
+		assert.Equal(t, combinations, got, "dimensions %v", dimensions)
+	}
+}
+
+// TestExpandStepMatrixErrors verifies steps listing unknown dimensions are rejected
+func TestExpandStepMatrixErrors(t *testing.T) {
+	steps := []c8sv1alpha1.PipelineStep{{Name: "test", Image: "golang", Commands: []string{"go test"}, MatrixDimensions: []string{"arch"}}}
+
+	_, _, err := scheduler.ExpandStepMatrix(steps, nil)
+	assert.ErrorContains(t, err, "has no matrix")
+
+	_, _, err = scheduler.ExpandStepMatrix(steps, &c8sv1alpha1.MatrixStrategy{Dimensions: map[string][]string{"os": {"ubuntu"}}})
+	assert.ErrorContains(t, err, "matrix dimension arch not found")
+}
+
+// TestBuildScheduleStepMatrix verifies the schedule of a step matrix runs
+// the expanded steps in one run and records their matrix values
+func TestBuildScheduleStepMatrix(t *testing.T) {
+	config := stepMatrixConfig()
+
+	schedule, err := scheduler.BuildSchedule(config)
+	require.NoError(t, err)
+	assert.Equal(t, 7, schedule.TotalSteps())
+	require.Equal(t, 4, schedule.LayerCount())
+	assert.ElementsMatch(t, []string{"test-ubuntu-1-21", "test-ubuntu-1-22", "test-alpine-1-22"}, schedule.Layers[1].StepNames)
+	assert.Equal(t, map[string]string{"os": "ubuntu", "go": "1.21"}, schedule.MatrixValues["test-ubuntu-1-21"])
+
+	ready := schedule.GetReadySteps(map[string]bool{"lint": true, "test-alpine-1-22": true})
+	var readyNames []string
+	for _, step := range ready {
+		readyNames = append(readyNames, step.Name)
+	}
+	assert.ElementsMatch(t, []string{"test-ubuntu-1-21", "test-ubuntu-1-22", "package-alpine"}, readyNames)
+
+	run := &c8sv1alpha1.PipelineRun{Status: c8sv1alpha1.PipelineRunStatus{Phase: c8sv1alpha1.PipelineRunPhasePending}}
+	assert.False(t, controller.ShouldCreateMatrixRuns(run, config))
+}
+
+// TestInjectMatrixValues verifies matrix values become env vars of the step
+// container and labels of the Job
+func TestInjectMatrixValues(t *testing.T) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{types.LabelManaged: types.LabelManagedValue}},
+		Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: types.ContainerNameStep}},
+		}}},
+	}
+
+	controller.InjectMatrixValues(job, map[string]string{"os": "ubuntu", "go-version": "1.22"})
+
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "MATRIX_GO_VERSION", Value: "1.22"},
+		{Name: "MATRIX_OS", Value: "ubuntu"},
+	}, job.Spec.Template.Spec.Containers[0].Env)
+	assert.Equal(t, "ubuntu", job.Labels["c8s.dev/matrix-os"])
+	assert.Equal(t, "1-22", job.Labels["c8s.dev/matrix-go-version"])
+	assert.Equal(t, types.LabelManagedValue, job.Labels[types.LabelManaged])
+}
+
+// TestStepMatrixDimensionsValidation verifies matrixDimensions must name
+// dimensions of the pipeline matrix
+func TestStepMatrixDimensionsValidation(t *testing.T) {
+	spec, err := parser.Parse([]byte(`version: v1alpha1
+name: platforms
+matrix:
+  dimensions:
+    os: [ubuntu, alpine]
+steps:
+  - name: test
+    image: golang:1.22
+    matrixDimensions: [os]
+    commands: [go test ./...]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"os"}, spec.Steps[0].MatrixDimensions)
+
+	_, err = parser.Parse([]byte(`version: v1alpha1
+name: platforms
+steps:
+  - name: test
+    image: golang:1.22
+    matrixDimensions: [os]
+    commands: [go test ./...]
+`))
+	assert.ErrorContains(t, err, "matrixDimensions requires a matrix")
+
+	config := stepMatrixConfig()
+	config.Spec.Steps[2].MatrixDimensions = []string{"arch"}
+	err = parser.Validate(config)
+	assert.ErrorContains(t, err, "undefined dimension: arch")
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/org/c8s/pkg/controller"
+)
+
+// jobPod returns a Pod of the Job "run-1-build" started at start
+func jobPod(name string, start time.Time) *corev1.Pod {
+	started := metav1.NewTime(start)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"job-name": "run-1-build"},
+		},
+		Status: corev1.PodStatus{StartTime: &started},
+	}
+}
+
+// TestGetJobPod verifies the most recently started Pod of a Job is selected
+// and cached for the duration of a reconcile
+func TestGetJobPod(t *testing.T) {
+	now := time.Now()
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "run-1-build", Namespace: "default"}}
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		jobPod("run-1-build-first", now.Add(-2*time.Minute)),
+		jobPod("run-1-build-retry", now.Add(-time.Minute)),
+	).Build()
+
+	ctx := controller.WithJobPodCache(context.Background())
+	pod, err := controller.GetJobPod(ctx, c, job)
+	require.NoError(t, err)
+	require.NotNil(t, pod)
+	assert.Equal(t, "run-1-build-retry", pod.Name)
+
+	// A newer attempt is only seen by the next reconcile
+	require.NoError(t, c.Create(ctx, jobPod("run-1-build-third", now)))
+	pod, err = controller.GetJobPod(ctx, c, job)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1-build-retry", pod.Name)
+
+	pod, err = controller.GetJobPod(controller.WithJobPodCache(context.Background()), c, job)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1-build-third", pod.Name)
+}
+
+// TestGetJobPodNone verifies a Job without Pods returns nil
+func TestGetJobPodNone(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).Build()
+
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "run-1-build", Namespace: "default"}}
+	pod, err := controller.GetJobPod(context.Background(), c, job)
+	require.NoError(t, err)
+	assert.Nil(t, pod)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/parser"
+	"github.com/org/c8s/pkg/scheduler"
+)
+
+// stepNames returns the names of steps in order
+func stepNames(steps []*c8sv1alpha1.PipelineStep) []string {
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+// TestStepWeight verifies steps without a weight default to 1
+func TestStepWeight(t *testing.T) {
+	assert.Equal(t, 1, scheduler.StepWeight(&c8sv1alpha1.PipelineStep{Name: "build"}))
+	assert.Equal(t, 7, scheduler.StepWeight(&c8sv1alpha1.PipelineStep{Name: "build", Weight: 7}))
+}
+
+// TestGetReadyStepsWithWeightSort verifies ready steps are ordered by
+// descending weight, then by name for equal weights, so that a
+// maxParallel limit below the number of ready steps picks the heaviest
+func TestGetReadyStepsWithWeightSort(t *testing.T) {
+	config := &c8sv1alpha1.PipelineConfig{
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			MaxParallel: 2,
+			Steps: []c8sv1alpha1.PipelineStep{
+				{Name: "lint", Image: "golangci/golangci-lint:v1.61"},
+				{Name: "unit", Image: "golang:1.21", Weight: 5},
+				{Name: "docs", Image: "alpine"},
+				{Name: "e2e", Image: "golang:1.21", Weight: 10},
+				{Name: "vet", Image: "golang:1.21", Weight: 1},
+				{Name: "build", Image: "golang:1.21", DependsOn: []string{"unit"}, Weight: 100},
+			},
+		},
+	}
+
+	schedule, err := scheduler.BuildSchedule(config)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"lint", "unit", "docs", "e2e", "vet"}, stepNames(schedule.GetReadySteps(map[string]bool{})))
+
+	ready := schedule.GetReadySteps(map[string]bool{}, scheduler.WithWeightSort())
+	assert.Equal(t, []string{"e2e", "unit", "docs", "lint", "vet"}, stepNames(ready))
+	require.Less(t, config.Spec.MaxParallel, len(ready))
+	assert.Equal(t, []string{"e2e", "unit"}, stepNames(ready[:config.Spec.MaxParallel]))
+
+	// A heavier step becoming ready in a later layer goes first
+	ready = schedule.GetReadySteps(map[string]bool{"unit": true, "e2e": true}, scheduler.WithWeightSort())
+	assert.Equal(t, []string{"build", "docs", "lint", "vet"}, stepNames(ready))
+}
+
+// TestParseStepWeight verifies weight and maxParallel are parsed and
+// negative values are rejected
+func TestParseStepWeight(t *testing.T) {
+	spec, err := parser.ParseBytes([]byte(`version: v1alpha1
+name: app
+maxParallel: 2
+steps:
+  - name: test
+    image: golang:1.25
+    commands: ["go test ./..."]
+    weight: 10
+  - name: lint
+    image: golangci/golangci-lint:v1.61
+    commands: ["golangci-lint run"]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, spec.MaxParallel)
+	assert.Equal(t, 10, spec.Steps[0].Weight)
+	assert.Equal(t, 0, spec.Steps[1].Weight)
+
+	config := &c8sv1alpha1.PipelineConfig{Spec: *spec}
+	config.Spec.Repository = "https://github.com/org/repo.git"
+	require.NoError(t, parser.Validate(config))
+
+	// A weight of 0 is the default; only negative weights are invalid
+	config.Spec.Steps[0].Weight = 0
+	require.NoError(t, parser.Validate(config))
+
+	config.Spec.MaxParallel = -1
+	config.Spec.Steps[0].Weight = -1
+	err = parser.Validate(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.maxParallel")
+	assert.Contains(t, err.Error(), "invalid weight -1: must not be negative")
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/samples"
+)
+
+// testBaseline returns a baseline of a passing and a failing pipeline
+func testBaseline() *samples.Baseline {
+	return &samples.Baseline{
+		Namespace: "default",
+		Pipelines: []samples.BaselinePipeline{
+			{
+				Name:   "simple-build",
+				Status: "Success",
+				Steps: []samples.BaselineStep{
+					{Name: "build", Phase: "Succeeded", Duration: 10 * time.Second, MemoryBytes: 100 << 20},
+					{Name: "test", Phase: "Succeeded", Duration: 20 * time.Second},
+				},
+			},
+			{Name: "failing", Status: "Failed"},
+		},
+	}
+}
+
+// TestCompareBaselines_NoRegression verifies small changes are not reported
+func TestCompareBaselines_NoRegression(t *testing.T) {
+	current := testBaseline()
+	current.Pipelines[0].Steps[0].Duration = 11 * time.Second
+	current.Pipelines[0].Steps[0].MemoryBytes = 120 << 20
+
+	comparison := samples.CompareBaselines(testBaseline(), current, samples.CompareOptions{})
+	assert.True(t, comparison.Passed())
+	assert.Equal(t, 1, comparison.Pipelines)
+	assert.Equal(t, 2, comparison.Steps)
+}
+
+// TestCompareBaselines_Regressions verifies slower steps, steps using more
+// memory and failing steps are reported
+func TestCompareBaselines_Regressions(t *testing.T) {
+	current := testBaseline()
+	current.Pipelines[0].Status = "Failed"
+	current.Pipelines[0].Steps[0].Duration = 13 * time.Second
+	current.Pipelines[0].Steps[0].MemoryBytes = 200 << 20
+	current.Pipelines[0].Steps[1].Phase = "Failed"
+
+	comparison := samples.CompareBaselines(testBaseline(), current, samples.CompareOptions{})
+	require.False(t, comparison.Passed())
+
+	var kinds []string
+	for _, regression := range comparison.Regressions {
+		kinds = append(kinds, regression.Step+":"+regression.Kind)
+	}
+	assert.Equal(t, []string{
+		":" + samples.RegressionFailure,
+		"build:" + samples.RegressionDuration,
+		"build:" + samples.RegressionMemory,
+		"test:" + samples.RegressionFailure,
+	}, kinds)
+	assert.Equal(t, "took 13s, was 10s (+30%)", comparison.Regressions[1].Message)
+}
+
+// TestCompareBaselines_Thresholds verifies custom thresholds and the minimum
+// duration increase
+func TestCompareBaselines_Thresholds(t *testing.T) {
+	baseline := testBaseline()
+	baseline.Pipelines[0].Steps[0].Duration = 2 * time.Second
+
+	current := testBaseline()
+	current.Pipelines[0].Steps[0].Duration = 2*time.Second + 500*time.Millisecond
+	current.Pipelines[0].Steps[1].Duration = 28 * time.Second
+
+	// 25% slower, but by less than a second
+	comparison := samples.CompareBaselines(baseline, current, samples.CompareOptions{DurationThreshold: 0.5})
+	assert.True(t, comparison.Passed())
+
+	comparison = samples.CompareBaselines(baseline, current, samples.CompareOptions{})
+	require.Len(t, comparison.Regressions, 1)
+	assert.Equal(t, "test", comparison.Regressions[0].Step)
+}
+
+// TestCompareBaselines_Missing verifies pipelines and steps of the baseline
+// that did not run are reported
+func TestCompareBaselines_Missing(t *testing.T) {
+	current := testBaseline()
+	current.Pipelines[0].Steps = current.Pipelines[0].Steps[:1]
+
+	comparison := samples.CompareBaselines(testBaseline(), current, samples.CompareOptions{})
+	require.Len(t, comparison.Regressions, 1)
+	assert.Equal(t, samples.RegressionMissing, comparison.Regressions[0].Kind)
+	assert.Equal(t, "test", comparison.Regressions[0].Step)
+
+	comparison = samples.CompareBaselines(testBaseline(), &samples.Baseline{}, samples.CompareOptions{})
+	require.Len(t, comparison.Regressions, 1)
+	assert.Equal(t, "simple-build", comparison.Regressions[0].Pipeline)
+}
+
+// TestBaselineSteps verifies step durations and peak usage are taken from a
+// finished run
+func TestBaselineSteps(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(42 * time.Second))
+	run := &c8sv1alpha1.PipelineRun{
+		Status: c8sv1alpha1.PipelineRunStatus{
+			Steps: []c8sv1alpha1.StepStatus{
+				{Name: "build/os=linux", Phase: c8sv1alpha1.StepPhaseSucceeded, StartTime: &start, CompletionTime: &end},
+				{Name: "deploy", Phase: c8sv1alpha1.StepPhaseSkipped},
+			},
+		},
+	}
+
+	steps := samples.BaselineSteps(run, map[string]samples.StepUsage{
+		"build.os=linux": {MemoryBytes: 64 << 20, CPUMillis: 250},
+	})
+	require.Len(t, steps, 2)
+	assert.Equal(t, samples.BaselineStep{
+		Name: "build/os=linux", Phase: "Succeeded", Duration: 42 * time.Second, MemoryBytes: 64 << 20, CPUMillis: 250,
+	}, steps[0])
+	assert.Equal(t, samples.BaselineStep{Name: "deploy", Phase: "Skipped"}, steps[1])
+}
+
+// TestSaveLoadBaseline verifies a saved baseline keeps only what it was
+// given and reads back unchanged
+func TestSaveLoadBaseline(t *testing.T) {
+	baseline := testBaseline().Passing()
+	require.Len(t, baseline.Pipelines, 1)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, samples.SaveBaseline(path, baseline))
+
+	loaded, err := samples.LoadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, baseline, loaded)
+
+	_, err = samples.LoadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/localenv/samples"
+	"github.com/org/c8s/pkg/types"
+)
+
+// newCleanTestClient returns a fake client with an old and a recent test run,
+// a run created by other means, and a Job for each
+func newCleanTestClient(t *testing.T) client.Client {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+
+	testLabels := map[string]string{types.LabelCreatedBy: types.CreatedByTest}
+	run := func(name string, labels map[string]string, completed time.Duration) *c8sv1alpha1.PipelineRun {
+		r := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+		if completed > 0 {
+			r.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-completed)}
+		}
+		return r
+	}
+	job := func(runName string, labels map[string]string) *batchv1.Job {
+		jobLabels := map[string]string{types.LabelPipelineRun: runName}
+		for k, v := range labels {
+			jobLabels[k] = v
+		}
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: runName + "-build", Namespace: "default", Labels: jobLabels}}
+	}
+
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(
+		run("old-run", testLabels, 48*time.Hour), job("old-run", testLabels),
+		run("new-run", testLabels, 0), job("new-run", testLabels),
+		run("user-run", nil, 48*time.Hour), job("user-run", nil),
+	).Build()
+}
+
+// TestCleanTestRuns verifies only test-created runs and Jobs are deleted
+func TestCleanTestRuns(t *testing.T) {
+	ctx := context.Background()
+	c := newCleanTestClient(t)
+
+	result, err := samples.CleanTestRuns(ctx, c, samples.CleanOptions{Namespace: "default"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"old-run", "new-run"}, result.PipelineRuns)
+	assert.ElementsMatch(t, []string{"old-run-build", "new-run-build"}, result.Jobs)
+
+	var runs c8sv1alpha1.PipelineRunList
+	require.NoError(t, c.List(ctx, &runs))
+	require.Len(t, runs.Items, 1)
+	assert.Equal(t, "user-run", runs.Items[0].Name)
+
+	var jobs batchv1.JobList
+	require.NoError(t, c.List(ctx, &jobs))
+	require.Len(t, jobs.Items, 1)
+	assert.Equal(t, "user-run-build", jobs.Items[0].Name)
+}
+
+// TestCleanTestRunsOlderThan verifies only runs completed before the cutoff are deleted
+func TestCleanTestRunsOlderThan(t *testing.T) {
+	ctx := context.Background()
+	c := newCleanTestClient(t)
+
+	result, err := samples.CleanTestRuns(ctx, c, samples.CleanOptions{Namespace: "default", OlderThan: 24 * time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old-run"}, result.PipelineRuns)
+	assert.Equal(t, []string{"old-run-build"}, result.Jobs)
+
+	var runs c8sv1alpha1.PipelineRunList
+	require.NoError(t, c.List(ctx, &runs))
+	assert.Len(t, runs.Items, 2)
+
+	var jobs batchv1.JobList
+	require.NoError(t, c.List(ctx, &jobs))
+	assert.Len(t, jobs.Items, 2)
+}
+
+// TestCleanTestRunsDryRun verifies a dry run deletes nothing
+func TestCleanTestRunsDryRun(t *testing.T) {
+	ctx := context.Background()
+	c := newCleanTestClient(t)
+
+	result, err := samples.CleanTestRuns(ctx, c, samples.CleanOptions{Namespace: "default", DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, result.PipelineRuns, 2)
+
+	var runs c8sv1alpha1.PipelineRunList
+	require.NoError(t, c.List(ctx, &runs))
+	assert.Len(t, runs.Items, 3)
+}
+
+// TestCreateJobForStepCreatedByLabel verifies Jobs inherit the created-by label of their run
+func TestCreateJobForStepCreatedByLabel(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:      "run-1",
+		Namespace: "default",
+		Labels:    map[string]string{types.LabelCreatedBy: types.CreatedByTest},
+	}}
+	step := &c8sv1alpha1.PipelineStep{Name: "build", Image: "golang:1.25", Commands: []string{"go build ./..."}}
+	jm := controller.NewJobManager("https://github.com/org/repo.git")
+
+	job, err := jm.CreateJobForStep(step, run, &c8sv1alpha1.PipelineConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, types.CreatedByTest, job.Labels[types.LabelCreatedBy])
+
+	run.Labels = nil
+	job, err = jm.CreateJobForStep(step, run, &c8sv1alpha1.PipelineConfig{})
+	require.NoError(t, err)
+	assert.NotContains(t, job.Labels, types.LabelCreatedBy)
+}
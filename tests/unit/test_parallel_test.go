@@ -0,0 +1,145 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/samples"
+)
+
+const buildSuiteYAML = `pipelines:
+  - name: build
+    spec:
+      repository: https://github.com/org/app.git
+      steps:
+        - name: compile
+          image: golang:1.25
+          commands: ["go build ./..."]
+    expected:
+      steps:
+        - name: compile
+          phase: Succeeded
+  - name: broken
+    spec:
+      repository: https://github.com/org/app.git
+      steps:
+        - name: compile
+          image: golang:1.25
+          commands: ["exit 1"]
+    expected:
+      phase: Failed
+`
+
+// writeSuiteFile writes a suite definition to a temporary file
+func writeSuiteFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// TestLoadTestSuite verifies suite files get defaults and are checked
+func TestLoadTestSuite(t *testing.T) {
+	suite, err := samples.LoadTestSuite(writeSuiteFile(t, "build.yaml", buildSuiteYAML))
+	require.NoError(t, err)
+	assert.Equal(t, "build", suite.Name)
+	require.Len(t, suite.Pipelines, 2)
+	assert.Equal(t, "main", suite.Pipelines[0].Branch)
+	assert.Len(t, suite.Pipelines[0].Commit, 40)
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseSucceeded, suite.Pipelines[0].Expected.Phase)
+	assert.Equal(t, c8sv1alpha1.PipelineRunPhaseFailed, suite.Pipelines[1].Expected.Phase)
+
+	_, err = samples.LoadTestSuite(writeSuiteFile(t, "empty.yaml", "name: empty\npipelines: []\n"))
+	assert.ErrorContains(t, err, "no pipelines")
+
+	_, err = samples.LoadTestSuite(writeSuiteFile(t, "dup.yaml", buildSuiteYAML+strings.Replace(buildSuiteYAML[len("pipelines:\n"):], "broken", "build", 1)))
+	assert.ErrorContains(t, err, "duplicate pipeline build")
+}
+
+// TestSuiteNamespace verifies suite namespaces are DNS-1123 labels ending
+// with the start time
+func TestSuiteNamespace(t *testing.T) {
+	start := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	assert.Equal(t, "api-tests-20250304-050607", samples.SuiteNamespace("API_Tests", start))
+
+	long := samples.SuiteNamespace(strings.Repeat("suite-", 20), start)
+	assert.LessOrEqual(t, len(long), 63)
+	assert.True(t, strings.HasSuffix(long, "-20250304-050607"))
+}
+
+// TestRunTestSuites verifies suites run in their own namespaces, their runs
+// are compared with the expected outcomes and the namespaces are deleted
+func TestRunTestSuites(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	require.NoError(t, corev1.AddToScheme(s))
+
+	// Runs finish as soon as they are created: "broken" pipelines fail
+	c := fake.NewClientBuilder().WithScheme(s).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if run, ok := obj.(*c8sv1alpha1.PipelineRun); ok {
+				run.Status.Phase = c8sv1alpha1.PipelineRunPhaseSucceeded
+				stepPhase := c8sv1alpha1.StepPhaseSucceeded
+				if run.Spec.PipelineConfigRef == "broken" {
+					run.Status.Phase = c8sv1alpha1.PipelineRunPhaseFailed
+					stepPhase = c8sv1alpha1.StepPhaseFailed
+				}
+				run.Status.Steps = []c8sv1alpha1.StepStatus{{Name: "compile", Phase: stepPhase}}
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	build, err := samples.LoadTestSuite(writeSuiteFile(t, "build.yaml", buildSuiteYAML))
+	require.NoError(t, err)
+	strict, err := samples.LoadTestSuite(writeSuiteFile(t, "strict.yaml", strings.Replace(buildSuiteYAML, "phase: Failed", "phase: Succeeded", 1)))
+	require.NoError(t, err)
+
+	summary := samples.RunTestSuites(context.Background(), c, []*samples.TestSuite{build, strict}, samples.SuiteOptions{Timeout: 10 * time.Second})
+	require.Len(t, summary.Suites, 2)
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 3, summary.Passed)
+	assert.Equal(t, 1, summary.Failed)
+	assert.False(t, summary.Succeeded())
+
+	assert.Equal(t, "build", summary.Suites[0].Suite)
+	assert.Empty(t, summary.Suites[0].Error)
+	assert.Equal(t, 2, summary.Suites[0].Passed)
+	assert.Equal(t, 1, summary.Suites[1].Failed)
+	assert.Equal(t, []string{"phase Failed, expected Succeeded"}, summary.Suites[1].Pipelines[1].Differences)
+
+	for _, suite := range summary.Suites {
+		err := c.Get(context.Background(), client.ObjectKey{Name: suite.Namespace}, &corev1.Namespace{})
+		assert.True(t, apierrors.IsNotFound(err), "namespace %s not deleted", suite.Namespace)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/samples"
+	"github.com/org/c8s/pkg/types"
+)
+
+const replayCommit = "0123456789abcdef0123456789abcdef01234567"
+
+const replaySessionJSON = `{
+  "name": "push-main",
+  "pipelineConfig": {
+    "name": "app",
+    "spec": {
+      "repository": "https://github.com/org/app.git",
+      "steps": [{"name": "build", "image": "golang:1.25", "commands": ["go build ./..."]}]
+    }
+  },
+  "events": [
+    {"provider": "github", "push": {"repository": "org/app", "branch": "main", "commit": "` + replayCommit + `"}}
+  ],
+  "expected": {
+    "runs": [
+      {"commit": "01234567", "branch": "main", "phase": "Succeeded",
+       "steps": [{"name": "build", "phase": "Succeeded"}]}
+    ]
+  }
+}`
+
+// writeReplaySession writes a session file and returns its path
+func writeReplaySession(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// replayRun returns a run of the app config created by the webhook for a commit
+func replayRun(name, commit string, phase c8sv1alpha1.PipelineRunPhase, steps ...c8sv1alpha1.StepStatus) *c8sv1alpha1.PipelineRun {
+	return &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.Now(),
+			Labels:            map[string]string{types.LabelPipelineConfig: "app", types.LabelCommit: commit[:8]},
+		},
+		Spec:   c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "app", Commit: commit, Branch: "main"},
+		Status: c8sv1alpha1.PipelineRunStatus{Phase: phase, Steps: steps},
+	}
+}
+
+// TestLoadReplaySession verifies session files are parsed and checked
+func TestLoadReplaySession(t *testing.T) {
+	session, err := samples.LoadReplaySession(writeReplaySession(t, replaySessionJSON))
+	require.NoError(t, err)
+	assert.Equal(t, "default", session.Namespace)
+	assert.Equal(t, "app", session.PipelineConfig.Name)
+	require.Len(t, session.Events, 1)
+	assert.Equal(t, replayCommit, session.Events[0].Push.Commit)
+
+	_, err = samples.LoadReplaySession(writeReplaySession(t, `{"pipelineConfig": {"name": "app"}, "events": [{"provider": "github"}]}`))
+	assert.ErrorContains(t, err, "events[0] needs a payload or a push")
+}
+
+// TestReplayEventRequest verifies recorded deliveries are sent as-is
+func TestReplayEventRequest(t *testing.T) {
+	event := samples.ReplayEvent{
+		Provider: "gitlab",
+		Headers:  map[string]string{"X-Gitlab-Event": "Push Hook"},
+		Payload:  []byte(`{"ref":"refs/heads/main"}`),
+	}
+
+	req, err := event.Request(context.Background(), "http://localhost:8080/")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/webhooks/gitlab", req.URL.String())
+	assert.Equal(t, "Push Hook", req.Header.Get("X-Gitlab-Event"))
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ref":"refs/heads/main"}`, string(body))
+}
+
+// TestCompareReplayRuns verifies missing, different and unexpected runs are reported
+func TestCompareReplayRuns(t *testing.T) {
+	expected := []samples.ExpectedRun{
+		{Commit: "aaaaaaaa", Phase: c8sv1alpha1.PipelineRunPhaseSucceeded, Steps: []samples.ExpectedStep{
+			{Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded},
+			{Name: "test", Phase: c8sv1alpha1.StepPhaseSucceeded},
+		}},
+		{Commit: "bbbbbbbb", Phase: c8sv1alpha1.PipelineRunPhaseSucceeded},
+	}
+	runs := []c8sv1alpha1.PipelineRun{
+		*replayRun("app-aaaaaaaa", "aaaaaaaa11", c8sv1alpha1.PipelineRunPhaseFailed,
+			c8sv1alpha1.StepStatus{Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded},
+			c8sv1alpha1.StepStatus{Name: "test", Phase: c8sv1alpha1.StepPhaseFailed},
+			c8sv1alpha1.StepStatus{Name: "lint", Phase: c8sv1alpha1.StepPhaseSucceeded}),
+		*replayRun("app-cccccccc", "cccccccc11", c8sv1alpha1.PipelineRunPhaseSucceeded),
+	}
+
+	result := samples.CompareReplayRuns(expected, runs)
+
+	assert.False(t, result.Passed)
+	require.Len(t, result.Runs, 2)
+	assert.Equal(t, "app-aaaaaaaa", result.Runs[0].RunName)
+	assert.Equal(t, []string{
+		"phase Failed, expected Succeeded",
+		"step test phase Failed, expected Succeeded",
+		"unexpected step lint",
+	}, result.Runs[0].Differences)
+	assert.Equal(t, []string{"no PipelineRun was created"}, result.Runs[1].Differences)
+	assert.Equal(t, []string{"app-cccccccc"}, result.Unexpected)
+}
+
+// TestReplay verifies a replay applies the PipelineConfig, replaces the run
+// left by an earlier replay and matches the run created by the webhook
+func TestReplay(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	stale := replayRun("app-01234567", replayCommit, c8sv1alpha1.PipelineRunPhaseFailed)
+	stale.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(stale).Build()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/webhooks/github", r.URL.Path)
+		run := replayRun("app-01234567", replayCommit, c8sv1alpha1.PipelineRunPhaseSucceeded,
+			c8sv1alpha1.StepStatus{Name: "build", Phase: c8sv1alpha1.StepPhaseSucceeded})
+		if err := c.Create(r.Context(), run); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	session, err := samples.LoadReplaySession(writeReplaySession(t, replaySessionJSON))
+	require.NoError(t, err)
+
+	result, err := samples.Replay(context.Background(), c, session, samples.ReplayOptions{
+		WebhookURL: server.URL,
+		Timeout:    10 * time.Second,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Passed, "%+v", result)
+	assert.Equal(t, "push-main", result.Session)
+
+	config := &c8sv1alpha1.PipelineConfig{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app"}, config))
+	assert.Equal(t, "https://github.com/org/app.git", config.Spec.Repository)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/samples"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// TestGetTestSamples_AllValid verifies every embedded sample is a valid PipelineConfig named after its file
+func TestGetTestSamples_AllValid(t *testing.T) {
+	assert.Equal(t, []string{"failing", "matrix", "multi-step", "simple-build"}, samples.TestSampleNames())
+
+	all, err := samples.GetTestSamples(nil)
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+
+	for _, sample := range all {
+		var config c8sv1alpha1.PipelineConfig
+		require.NoError(t, utilyaml.UnmarshalStrict(sample.Manifest, &config), sample.Name)
+		assert.Equal(t, "PipelineConfig", config.Kind, sample.Name)
+		assert.Equal(t, sample.Name, config.Name, sample.Name)
+		assert.Empty(t, config.Namespace, "%s should take the namespace from the command", sample.Name)
+		assert.NoError(t, parser.Validate(&config), sample.Name)
+	}
+}
+
+// TestGetTestSamples_Selection verifies a subset can be selected and unknown names are rejected
+func TestGetTestSamples_Selection(t *testing.T) {
+	selected, err := samples.GetTestSamples([]string{"simple-build", " matrix"})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "simple-build", selected[0].Name)
+	assert.Equal(t, "matrix", selected[1].Name)
+
+	_, err = samples.GetTestSamples([]string{"nonexistent"})
+	assert.ErrorContains(t, err, "unknown sample")
+}
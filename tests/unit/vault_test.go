@@ -0,0 +1,183 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/types"
+	"github.com/org/c8s/pkg/vault"
+)
+
+// TestVaultReadSecretKVv2 verifies values are read from KV v2 responses with the configured token
+func TestVaultReadSecretKVv2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/ci":
+			w.Write([]byte(`{"data": {"data": {"password": "hunter2"}, "metadata": {"version": 1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := vault.NewClient(&vault.Config{Address: server.URL, Token: "test-token"})
+	require.NoError(t, err)
+
+	value, err := client.ReadSecret(context.Background(), "secret/data/ci", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	_, err = client.ReadSecret(context.Background(), "secret/data/ci", "missing")
+	assert.True(t, errors.Is(err, vault.ErrKeyNotFound))
+
+	_, err = client.ReadSecret(context.Background(), "secret/data/other", "password")
+	assert.True(t, errors.Is(err, vault.ErrSecretNotFound))
+}
+
+// TestVaultConfigValidate verifies required configuration
+func TestVaultConfigValidate(t *testing.T) {
+	assert.ErrorIs(t, (&vault.Config{}).Validate(), vault.ErrMissingAddress)
+	assert.ErrorIs(t, (&vault.Config{Address: "http://vault:8200"}).Validate(), vault.ErrMissingCredentials)
+	assert.NoError(t, (&vault.Config{Address: "http://vault:8200", Role: "c8s"}).Validate())
+}
+
+// vaultTestReconciler returns a reconciler for a run with one step reading a
+// Vault secret, intercepting the fake client's Creates with create if set
+func vaultTestReconciler(t *testing.T, create func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error) (*controller.PipelineRunReconciler, client.Client, ctrl.Request) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"data": {"token": "hunter2"}, "metadata": {"version": 1}}}`))
+	}))
+	t.Cleanup(server.Close)
+	vaultClient, err := vault.NewClient(&vault.Config{Address: server.URL, Token: "test-token"})
+	require.NoError(t, err)
+
+	config := &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/example-org/example-repo",
+			Steps: []c8sv1alpha1.PipelineStep{{
+				Name:         "deploy",
+				Image:        "alpine:3.20",
+				Commands:     []string{"./deploy.sh"},
+				VaultSecrets: []c8sv1alpha1.VaultSecretRef{{Path: "secret/data/ci", Key: "token"}},
+			}},
+		},
+	}
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default", Finalizers: []string{types.FinalizerPipelineRun}},
+		Spec:       c8sv1alpha1.PipelineRunSpec{PipelineConfigRef: "config", Commit: "abc1234"},
+	}
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+	builder := fake.NewClientBuilder().WithScheme(s).WithObjects(config, run).WithStatusSubresource(run)
+	if create != nil {
+		builder.WithInterceptorFuncs(interceptor.Funcs{Create: create})
+	}
+	c := builder.Build()
+	reconciler := &controller.PipelineRunReconciler{Client: c, Scheme: s, VaultClient: vaultClient}
+	return reconciler, c, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(run)}
+}
+
+// reconcileTwice runs the first pass, which initializes the run's phase, and
+// the pass creating its Jobs
+func reconcileTwice(t *testing.T, reconciler *controller.PipelineRunReconciler, req ctrl.Request) {
+	t.Helper()
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+}
+
+// TestReconcileVaultSecretOwnedByJob verifies the Vault Secret is created
+// before its Job and then handed to it
+func TestReconcileVaultSecretOwnedByJob(t *testing.T) {
+	var created []string
+	reconciler, c, req := vaultTestReconciler(t, func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+		switch obj.(type) {
+		case *corev1.Secret:
+			created = append(created, "Secret")
+		case *batchv1.Job:
+			created = append(created, "Job")
+		}
+		return c.Create(ctx, obj, opts...)
+	})
+	reconcileTwice(t, reconciler, req)
+	assert.Equal(t, []string{"Secret", "Job"}, created)
+
+	jobs := &batchv1.JobList{}
+	require.NoError(t, c.List(context.Background(), jobs))
+	require.Len(t, jobs.Items, 1)
+	job := jobs.Items[0]
+
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(),
+		client.ObjectKey{Namespace: "default", Name: controller.GetVaultSecretName(job.Name)}, secret))
+	assert.Equal(t, []byte("hunter2"), secret.Data["token"])
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, "Job", secret.OwnerReferences[0].Kind)
+	assert.Equal(t, job.Name, secret.OwnerReferences[0].Name)
+}
+
+// TestReconcileVaultSecretCreateFails verifies no Job is created for a step
+// whose Vault Secret couldn't be created, and the failure is reported
+func TestReconcileVaultSecretCreateFails(t *testing.T) {
+	reconciler, c, req := vaultTestReconciler(t, func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+		if _, ok := obj.(*corev1.Secret); ok {
+			return errors.New("secrets is forbidden")
+		}
+		return c.Create(ctx, obj, opts...)
+	})
+	reconcileTwice(t, reconciler, req)
+
+	jobs := &batchv1.JobList{}
+	require.NoError(t, c.List(context.Background(), jobs))
+	assert.Empty(t, jobs.Items)
+
+	run := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, run))
+	condition := meta.FindStatusCondition(run.Status.Conditions, types.ConditionTypeJobsCreated)
+	require.NotNil(t, condition)
+	assert.Equal(t, types.ReasonJobCreationFailed, condition.Reason)
+	assert.Contains(t, condition.Message, "secrets is forbidden")
+}
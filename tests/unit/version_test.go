@@ -0,0 +1,119 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/org/c8s/pkg/localenv/deploy"
+	"github.com/org/c8s/pkg/version"
+)
+
+// TestParseVersion verifies release tags and git describe output are parsed
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  version.Semver
+	}{
+		{input: "v0.3.1", want: version.Semver{Major: 0, Minor: 3, Patch: 1}},
+		{input: "1.2", want: version.Semver{Major: 1, Minor: 2}},
+		{input: "v0.3.1-4-gabc1234-dirty", want: version.Semver{Major: 0, Minor: 3, Patch: 1}},
+	}
+	for _, tt := range tests {
+		got, err := version.Parse(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+
+	for _, input := range []string{"dev", "latest", "v1", "v1.x.0"} {
+		_, err := version.Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+// TestVersionCompatible verifies only major/minor versions are compared
+func TestVersionCompatible(t *testing.T) {
+	ok, err := version.Compatible("v0.3.1", "v0.3.7")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = version.Compatible("v0.3.1", "v0.4.0")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = version.Compatible("dev", "v0.4.0")
+	assert.Error(t, err)
+
+	assert.True(t, version.Semver{Major: 0, Minor: 3, Patch: 1}.Less(version.Semver{Major: 0, Minor: 10}))
+}
+
+// TestLatestRelease verifies the latest release is read from the GitHub API response
+func TestLatestRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(`{"tag_name":"v0.5.0","html_url":"https://github.com/org/c8s/releases/tag/v0.5.0"}`))
+	}))
+	defer server.Close()
+
+	release, err := version.LatestRelease(context.Background(), server.Client(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.5.0", release.TagName)
+	assert.Equal(t, "https://github.com/org/c8s/releases/tag/v0.5.0", release.HTMLURL)
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	_, err = version.LatestRelease(context.Background(), notFound.Client(), notFound.URL)
+	assert.Error(t, err)
+}
+
+// TestImageTag verifies tags are extracted from image references
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, "v0.3.1", deploy.ImageTag("ghcr.io/org/c8s-controller:v0.3.1"))
+	assert.Equal(t, "v0.3.1", deploy.ImageTag("localhost:5000/c8s-controller:v0.3.1"))
+	assert.Equal(t, "latest", deploy.ImageTag("localhost:5000/c8s-controller"))
+	assert.Equal(t, "v0.3.1", deploy.ImageTag("c8s-controller:v0.3.1@sha256:abcd"))
+	assert.Equal(t, "", deploy.ImageTag("c8s-controller@sha256:abcd"))
+}
+
+// TestServedAPIVersions verifies the served versions of an API group are discovered
+func TestServedAPIVersions(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: "c8s.dev/v1alpha1"},
+		{GroupVersion: "apps/v1"},
+	}
+
+	versions, err := deploy.ServedAPIVersions(client, "c8s.dev")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1alpha1"}, versions)
+
+	versions, err = deploy.ServedAPIVersions(client, "example.com")
+	require.NoError(t, err)
+	assert.Nil(t, versions)
+
+	assert.True(t, version.IsSupportedAPIVersion("v1alpha1"))
+	assert.False(t, version.IsSupportedAPIVersion("v1beta1"))
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// volumeTestConfig returns a PipelineConfig declaring a cache claim and a settings ConfigMap
+func volumeTestConfig(accessMode string, steps ...c8sv1alpha1.PipelineStep) *c8sv1alpha1.PipelineConfig {
+	return &c8sv1alpha1.PipelineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-config", Namespace: "default"},
+		Spec: c8sv1alpha1.PipelineConfigSpec{
+			Repository: "https://github.com/org/repo",
+			Steps:      steps,
+			Volumes: []c8sv1alpha1.VolumeSpec{
+				{Name: "cache", PersistentVolumeClaim: &c8sv1alpha1.PVCSpec{ClaimName: "go-cache", AccessMode: accessMode}},
+				{Name: "settings", ConfigMap: &c8sv1alpha1.ConfigMapVolumeSpec{Name: "build-settings"}},
+			},
+		},
+	}
+}
+
+// volumeTestStep returns a step mounting the given volumes under /mnt
+func volumeTestStep(name string, volumes ...string) c8sv1alpha1.PipelineStep {
+	step := c8sv1alpha1.PipelineStep{Name: name, Image: "golang:1.25", Commands: []string{"go build ./..."}}
+	for _, volume := range volumes {
+		step.VolumeMounts = append(step.VolumeMounts, c8sv1alpha1.VolumeMountSpec{Name: volume, MountPath: "/mnt/" + volume})
+	}
+	return step
+}
+
+// TestVolumeValidation verifies volume declarations and step mount references are validated
+func TestVolumeValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *c8sv1alpha1.PipelineConfig
+		errorMsg string
+	}{
+		{
+			name:   "valid mounts",
+			config: volumeTestConfig("ReadWriteOnce", volumeTestStep("build", "cache", "settings"), volumeTestStep("test", "settings")),
+		},
+		{
+			name:     "undeclared volume",
+			config:   volumeTestConfig("", volumeTestStep("build", "missing")),
+			errorMsg: "references undeclared volume: missing",
+		},
+		{
+			name:     "shared claim must be read-only",
+			config:   volumeTestConfig("ReadWriteOnce", volumeTestStep("build", "cache"), volumeTestStep("test", "cache")),
+			errorMsg: "must be ReadOnlyMany when mounted by multiple steps",
+		},
+		{
+			name:   "shared read-only claim",
+			config: volumeTestConfig("ReadOnlyMany", volumeTestStep("build", "cache"), volumeTestStep("test", "cache")),
+		},
+		{
+			name: "reserved volume name",
+			config: func() *c8sv1alpha1.PipelineConfig {
+				config := volumeTestConfig("", volumeTestStep("build"))
+				config.Spec.Volumes[1].Name = "workspace"
+				return config
+			}(),
+			errorMsg: "volume name workspace is reserved",
+		},
+		{
+			name: "relative mount path",
+			config: func() *c8sv1alpha1.PipelineConfig {
+				step := volumeTestStep("build", "settings")
+				step.VolumeMounts[0].MountPath = "settings"
+				return volumeTestConfig("", step)
+			}(),
+			errorMsg: "must be an absolute path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parser.Validate(tt.config)
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCreateJobForStepVolumes verifies only the volumes a step mounts are added to its Job
+func TestCreateJobForStepVolumes(t *testing.T) {
+	step := volumeTestStep("build", "cache")
+	config := volumeTestConfig("ReadOnlyMany", step)
+	run := &c8sv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"},
+		Spec:       c8sv1alpha1.PipelineRunSpec{Commit: "abc1234", Branch: "main"},
+	}
+
+	job, err := controller.NewJobManager(config.Spec.Repository).CreateJobForStep(&step, run, config)
+	require.NoError(t, err)
+
+	volumes := job.Spec.Template.Spec.Volumes
+	require.Len(t, volumes, 2)
+	assert.Equal(t, "cache", volumes[1].Name)
+	require.NotNil(t, volumes[1].PersistentVolumeClaim)
+	assert.Equal(t, "go-cache", volumes[1].PersistentVolumeClaim.ClaimName)
+	assert.True(t, volumes[1].PersistentVolumeClaim.ReadOnly)
+
+	mounts := job.Spec.Template.Spec.Containers[0].VolumeMounts
+	require.Len(t, mounts, 2)
+	assert.Equal(t, "cache", mounts[1].Name)
+	assert.Equal(t, "/mnt/cache", mounts[1].MountPath)
+
+	undeclared := volumeTestStep("lint", "missing")
+	_, err = controller.NewJobManager(config.Spec.Repository).CreateJobForStep(&undeclared, run, config)
+	assert.ErrorContains(t, err, "undeclared volume missing")
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/c8s/pkg/webhook"
+)
+
+// TestEventStore_Wraparound verifies the oldest events are evicted when the store is full
+func TestEventStore_Wraparound(t *testing.T) {
+	store := webhook.NewEventStore(3)
+	for i := 1; i <= 5; i++ {
+		store.Record(webhook.ProviderGitHub, "push", []byte(fmt.Sprintf(`{"n":%d}`, i)), webhook.OutcomeAccepted, fmt.Sprintf("run-%d", i))
+	}
+
+	events := store.List(webhook.EventFilter{})
+	require.Len(t, events, 3)
+	assert.Equal(t, []int64{5, 4, 3}, []int64{events[0].ID, events[1].ID, events[2].ID})
+	assert.Empty(t, events[0].Payload)
+
+	_, ok := store.Get(2)
+	assert.False(t, ok)
+
+	event, ok := store.Get(4)
+	require.True(t, ok)
+	assert.Equal(t, `{"n":4}`, event.Payload)
+	assert.Equal(t, "run-4", event.RunName)
+}
+
+// TestEventStore_Filter verifies events are filtered by provider and outcome and limited
+func TestEventStore_Filter(t *testing.T) {
+	store := webhook.NewEventStore(0)
+	store.Record(webhook.ProviderGitHub, "push", nil, webhook.OutcomeAccepted, "run-1")
+	store.Record(webhook.ProviderGitLab, "Push Hook", nil, webhook.OutcomeRejected, "")
+	store.Record(webhook.ProviderGitHub, "issues", nil, webhook.OutcomeIgnored, "")
+	store.Record(webhook.ProviderGitHub, "push", nil, webhook.OutcomeAccepted, "run-2")
+
+	assert.Len(t, store.List(webhook.EventFilter{Provider: webhook.ProviderGitHub}), 3)
+	assert.Len(t, store.List(webhook.EventFilter{Outcome: webhook.OutcomeAccepted}), 2)
+
+	events := store.List(webhook.EventFilter{Provider: webhook.ProviderGitHub, Outcome: webhook.OutcomeAccepted, Limit: 1})
+	require.Len(t, events, 1)
+	assert.Equal(t, "run-2", events[0].RunName)
+}
+
+// TestEventStore_NilRecord verifies recording into a nil store is a no-op
+func TestEventStore_NilRecord(t *testing.T) {
+	var store *webhook.EventStore
+	assert.NotPanics(t, func() {
+		store.Record(webhook.ProviderGitHub, "push", nil, webhook.OutcomeAccepted, "")
+	})
+}
+
+// TestEventStore_HTTP verifies the list and detail endpoints
+func TestEventStore_HTTP(t *testing.T) {
+	store := webhook.NewEventStore(10)
+	store.Record(webhook.ProviderGitHub, "push", []byte(`{"ref":"refs/heads/main"}`), webhook.OutcomeAccepted, "run-1")
+	store.Record(webhook.ProviderGitLab, "Push Hook", nil, webhook.OutcomeRejected, "")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhooks/events", store.HandleEvents)
+	mux.HandleFunc("/webhooks/events/{id}", store.HandleEvent)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks/events?provider=github&outcome=accepted&limit=20", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var events []webhook.RecordedEvent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(1), events[0].ID)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks/events/1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var event webhook.RecordedEvent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &event))
+	assert.Equal(t, `{"ref":"refs/heads/main"}`, event.Payload)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks/events/99", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks/events?limit=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// TestEventStore_RecordedByHandler verifies provider handlers record their outcome
+func TestEventStore_RecordedByHandler(t *testing.T) {
+	k8sClient := webhookTestClient(t, "https://github.com/example-org/example-repo.git", "s3cr3t")
+	store := webhook.NewEventStore(10)
+	handler := webhook.NewGitHubHandler(k8sClient)
+	handler.SetEventStore(store)
+
+	req, err := webhook.NewTestPushRequest(context.Background(), "http://localhost/webhooks/github", webhook.ProviderGitHub,
+		webhook.TestPushEvent{Repository: "example-org/example-repo", Branch: "main", Commit: testWebhookCommit},
+		"s3cr3t")
+	require.NoError(t, err)
+	handler.Handle(httptest.NewRecorder(), req)
+
+	req, err = webhook.NewTestPushRequest(context.Background(), "http://localhost/webhooks/github", webhook.ProviderGitHub,
+		webhook.TestPushEvent{Repository: "example-org/example-repo", Branch: "main", Commit: testWebhookCommit},
+		"wrong")
+	require.NoError(t, err)
+	handler.Handle(httptest.NewRecorder(), req)
+
+	events := store.List(webhook.EventFilter{})
+	require.Len(t, events, 2)
+	assert.Equal(t, webhook.OutcomeRejected, events[0].Outcome)
+	assert.Equal(t, webhook.OutcomeAccepted, events[1].Outcome)
+	assert.Equal(t, "example-01234567", events[1].RunName)
+	assert.Equal(t, "push", events[1].EventType)
+
+	event, ok := store.Get(events[1].ID)
+	require.True(t, ok)
+	assert.Contains(t, event.Payload, testWebhookCommit)
+}
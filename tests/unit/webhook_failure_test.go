@@ -0,0 +1,158 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/localenv/samples"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+// failureWebhookServer starts a webhook service serving the GitHub handler,
+// health and metrics like cmd/webhook, and returns options targeting it
+func failureWebhookServer(t *testing.T, c client.Client, handler http.HandlerFunc, readTimeout time.Duration) samples.WebhookFailureOptions {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhooks/github", handler)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/metrics", promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{}))
+
+	server := httptest.NewUnstartedServer(mux)
+	server.Config.ReadTimeout = readTimeout
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return samples.WebhookFailureOptions{
+		WebhookURL: server.URL,
+		Provider:   webhook.ProviderGitHub,
+		Repository: "example-org/example-repo",
+		Branch:     "main",
+		Secret:     "s3cr3t",
+		Namespace:  "default",
+		Count:      3,
+		Timeout:    5 * time.Second,
+	}
+}
+
+// TestSimulateWebhookFailures verifies the real handler rejects every kind of
+// invalid event and still accepts a valid one
+func TestSimulateWebhookFailures(t *testing.T) {
+	tests := []struct {
+		errorType   string
+		readTimeout time.Duration
+	}{
+		{samples.FailureSignatureMismatch, 0},
+		{samples.FailureMissingField, 0},
+		{samples.FailureMalformedJSON, 0},
+		{samples.FailureTimeout, 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.errorType, func(t *testing.T) {
+			c := webhookTestClient(t, "https://github.com/example-org/example-repo.git", "s3cr3t")
+			opts := failureWebhookServer(t, c, webhook.NewGitHubHandler(c).Handle, tt.readTimeout)
+			opts.ErrorType = tt.errorType
+
+			result, err := samples.SimulateWebhookFailures(t.Context(), c, opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, 3, result.Sent)
+			require.Len(t, result.Checks, 5)
+			for _, check := range result.Checks {
+				assert.True(t, check.Passed, "%s: %s", check.Name, check.Message)
+			}
+			assert.True(t, result.Passed)
+
+			var runs c8sv1alpha1.PipelineRunList
+			require.NoError(t, c.List(t.Context(), &runs))
+			assert.Len(t, runs.Items, 1, "only the valid event creates a run")
+		})
+	}
+}
+
+// TestSimulateWebhookFailures_AcceptedEvents verifies a service accepting
+// invalid events fails the checks
+func TestSimulateWebhookFailures_AcceptedEvents(t *testing.T) {
+	c := webhookTestClient(t, "https://github.com/example-org/example-repo.git", "s3cr3t")
+	opts := failureWebhookServer(t, c, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}, 0)
+	opts.ErrorType = samples.FailureSignatureMismatch
+	opts.Timeout = time.Second
+
+	result, err := samples.SimulateWebhookFailures(t.Context(), c, opts)
+	require.NoError(t, err)
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, map[string]int{"202": 3}, result.Responses)
+	passed := map[string]bool{}
+	for _, check := range result.Checks {
+		passed[check.Name] = check.Passed
+	}
+	assert.False(t, passed[samples.CheckStatusCodes])
+	assert.False(t, passed[samples.CheckErrorMetrics])
+	assert.False(t, passed[samples.CheckValidEvent])
+	assert.True(t, passed[samples.CheckNoPipelineRuns])
+	assert.True(t, passed[samples.CheckHealthy])
+}
+
+// TestFailureEventRequest_MissingField verifies the pushed commit SHA is
+// removed from the payload of each provider
+func TestFailureEventRequest_MissingField(t *testing.T) {
+	for _, provider := range []string{webhook.ProviderGitHub, webhook.ProviderGitLab, webhook.ProviderBitbucket} {
+		t.Run(provider, func(t *testing.T) {
+			req, err := samples.FailureEventRequest(t.Context(), samples.WebhookFailureOptions{
+				WebhookURL: "http://localhost:8080",
+				Provider:   provider,
+				Repository: "example-org/example-repo",
+				Branch:     "main",
+				ErrorType:  samples.FailureMissingField,
+			}, testWebhookCommit)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.True(t, json.Valid(body))
+			assert.NotContains(t, string(body), `"after"`)
+			assert.NotContains(t, string(body), `"hash"`)
+			assert.Equal(t, int64(len(body)), req.ContentLength)
+		})
+	}
+}
+
+// TestExpectedFailureStatus verifies unknown error types are rejected
+func TestExpectedFailureStatus(t *testing.T) {
+	status, err := samples.ExpectedFailureStatus(samples.FailureSignatureMismatch)
+	require.NoError(t, err)
+	assert.Equal(t, []int{http.StatusUnauthorized}, status)
+
+	_, err = samples.ExpectedFailureStatus("crash")
+	assert.Error(t, err)
+}
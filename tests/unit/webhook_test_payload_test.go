@@ -0,0 +1,111 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+const testWebhookCommit = "0123456789abcdef0123456789abcdef01234567"
+
+// webhookTestClient returns a fake client with a RepositoryConnection for repoURL signed with secret
+func webhookTestClient(t *testing.T, repoURL, secret string) client.Client {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, c8sv1alpha1.AddToScheme(s))
+
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&c8sv1alpha1.RepositoryConnection{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: c8sv1alpha1.RepositoryConnectionSpec{
+				Repository:        repoURL,
+				WebhookSecretRef:  "example-webhook",
+				PipelineConfigRef: "simple-build",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-webhook", Namespace: "default"},
+			Data:       map[string][]byte{"webhook-secret": []byte(secret)},
+		},
+	).Build()
+}
+
+// TestNewTestPushRequest_AcceptedByHandlers verifies synthetic payloads are accepted by the real provider handlers
+func TestNewTestPushRequest_AcceptedByHandlers(t *testing.T) {
+	for _, provider := range []string{webhook.ProviderGitHub, webhook.ProviderGitLab, webhook.ProviderBitbucket} {
+		t.Run(provider, func(t *testing.T) {
+			k8sClient := webhookTestClient(t, webhook.DefaultTestRepositoryURL(provider, "example-org/example-repo"), "s3cr3t")
+			handlers := map[string]http.HandlerFunc{
+				webhook.ProviderGitHub:    webhook.NewGitHubHandler(k8sClient).Handle,
+				webhook.ProviderGitLab:    webhook.NewGitLabHandler(k8sClient).Handle,
+				webhook.ProviderBitbucket: webhook.NewBitbucketHandler(k8sClient).Handle,
+			}
+
+			req, err := webhook.NewTestPushRequest(context.Background(), "http://localhost/webhooks/"+provider, provider,
+				webhook.TestPushEvent{Repository: "example-org/example-repo", Branch: "main", Commit: testWebhookCommit, Author: "dev"},
+				"s3cr3t")
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			handlers[provider](rec, req)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			run := &c8sv1alpha1.PipelineRun{}
+			require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "example-01234567"}, run))
+			assert.Equal(t, testWebhookCommit, run.Spec.Commit)
+			assert.Equal(t, "main", run.Spec.Branch)
+		})
+	}
+}
+
+// TestNewTestPushRequest_WrongSecret verifies a payload signed with another secret is rejected
+func TestNewTestPushRequest_WrongSecret(t *testing.T) {
+	k8sClient := webhookTestClient(t, "https://github.com/example-org/example-repo.git", "s3cr3t")
+
+	req, err := webhook.NewTestPushRequest(context.Background(), "http://localhost/webhooks/github", webhook.ProviderGitHub,
+		webhook.TestPushEvent{Repository: "example-org/example-repo", Branch: "main", Commit: testWebhookCommit},
+		"wrong")
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	webhook.NewGitHubHandler(k8sClient).Handle(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// TestBuildTestPushPayload_Invalid verifies unknown providers and short commits are rejected
+func TestBuildTestPushPayload_Invalid(t *testing.T) {
+	_, err := webhook.BuildTestPushPayload("gitea", webhook.TestPushEvent{Commit: testWebhookCommit})
+	assert.ErrorContains(t, err, "unsupported provider")
+
+	_, err = webhook.BuildTestPushPayload(webhook.ProviderGitHub, webhook.TestPushEvent{Commit: "abc"})
+	assert.ErrorContains(t, err, "at least 8 characters")
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/webhook"
+)
+
+// withSecret returns req with secret as its webhook secret, and its body
+func withSecret(t *testing.T, req *http.Request, secret string) (*http.Request, []byte) {
+	t.Helper()
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	return req.WithContext(webhook.WithSecretLookup(req.Context(), func() ([]byte, error) {
+		return []byte(secret), nil
+	})), body
+}
+
+// TestValidators_ValidateSignature verifies each provider validator accepts requests
+// signed with the webhook secret and rejects other secrets
+func TestValidators_ValidateSignature(t *testing.T) {
+	for _, provider := range []string{webhook.ProviderGitHub, webhook.ProviderGitLab, webhook.ProviderBitbucket} {
+		t.Run(provider, func(t *testing.T) {
+			validator, ok := webhook.LookupValidator(provider)
+			require.True(t, ok)
+
+			newRequest := func() *http.Request {
+				req, err := webhook.NewTestPushRequest(context.Background(), "http://localhost/webhooks/"+provider, provider,
+					webhook.TestPushEvent{Repository: "example-org/example-repo", Branch: "main", Commit: testWebhookCommit},
+					"s3cr3t")
+				require.NoError(t, err)
+				return req
+			}
+
+			req, body := withSecret(t, newRequest(), "s3cr3t")
+			assert.NoError(t, validator.ValidateSignature(req, body))
+
+			req, body = withSecret(t, newRequest(), "other")
+			assert.Error(t, validator.ValidateSignature(req, body))
+
+			// A signed request without a matched RepositoryConnection has no secret
+			req = newRequest()
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Error(t, validator.ValidateSignature(req, body))
+		})
+	}
+}
+
+// TestValidators_ParseEvent verifies each provider payload is normalized to the same event
+func TestValidators_ParseEvent(t *testing.T) {
+	for _, provider := range []string{webhook.ProviderGitHub, webhook.ProviderGitLab, webhook.ProviderBitbucket} {
+		t.Run(provider, func(t *testing.T) {
+			validator, ok := webhook.LookupValidator(provider)
+			require.True(t, ok)
+
+			payload, err := webhook.BuildTestPushPayload(provider,
+				webhook.TestPushEvent{Repository: "example-org/example-repo", Branch: "main", Commit: testWebhookCommit, Author: "dev"})
+			require.NoError(t, err)
+
+			event, err := validator.ParseEvent(payload)
+			require.NoError(t, err)
+			assert.Equal(t, "example-org/example-repo", event.Repository)
+			assert.Equal(t, webhook.DefaultTestRepositoryURL(provider, "example-org/example-repo"), event.RepositoryURL)
+			assert.Equal(t, testWebhookCommit, event.Commit)
+			assert.Equal(t, "main", event.Branch)
+			assert.Equal(t, "dev", event.Author)
+
+			_, err = validator.ParseEvent([]byte("{"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestBitbucketValidator_NoChanges verifies a push without changes is ignored rather than rejected
+func TestBitbucketValidator_NoChanges(t *testing.T) {
+	_, err := (&webhook.BitbucketValidator{}).ParseEvent([]byte(`{"push":{"changes":[]}}`))
+	assert.ErrorIs(t, err, webhook.ErrEventIgnored)
+}
+
+// giteaValidator is a custom validator for Gitea push webhooks authenticated with a token
+type giteaValidator struct{}
+
+func (v *giteaValidator) ValidateSignature(r *http.Request, body []byte) error {
+	secret, err := webhook.WebhookSecret(r)
+	if err != nil {
+		return err
+	}
+	if r.Header.Get("Authorization") != "token "+string(secret) {
+		return fmt.Errorf("token mismatch")
+	}
+	return nil
+}
+
+func (v *giteaValidator) ParseEvent(body []byte) (*webhook.WebhookEvent, error) {
+	var push struct {
+		Ref        string `json:"ref"`
+		After      string `json:"after"`
+		Repository struct {
+			FullName string `json:"full_name"`
+			CloneURL string `json:"clone_url"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &push); err != nil {
+		return nil, err
+	}
+	return &webhook.WebhookEvent{
+		Repository:    push.Repository.FullName,
+		RepositoryURL: push.Repository.CloneURL,
+		Commit:        push.After,
+		Branch:        push.Ref[len("refs/heads/"):],
+	}, nil
+}
+
+// TestRegisterValidator_CustomProvider verifies a registered validator serves a new provider
+// through the shared handler
+func TestRegisterValidator_CustomProvider(t *testing.T) {
+	webhook.RegisterValidator("gitea", &giteaValidator{})
+	assert.Contains(t, webhook.RegisteredProviders(), "gitea")
+
+	validator, ok := webhook.LookupValidator("gitea")
+	require.True(t, ok)
+
+	repoURL := "https://gitea.example.com/example-org/example-repo.git"
+	k8sClient := webhookTestClient(t, repoURL, "s3cr3t")
+	handler := webhook.NewProviderHandler(k8sClient, "gitea", validator)
+
+	payload := fmt.Sprintf(`{"ref":"refs/heads/main","after":%q,"repository":{"full_name":"example-org/example-repo","clone_url":%q}}`,
+		testWebhookCommit, repoURL)
+	newRequest := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/gitea", bytes.NewBufferString(payload))
+		req.Header.Set("Authorization", "token "+token)
+		return req
+	}
+
+	rec := httptest.NewRecorder()
+	handler.Handle(rec, newRequest("wrong"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.Handle(rec, newRequest("s3cr3t"))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	run := &c8sv1alpha1.PipelineRun{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "example-01234567"}, run))
+	assert.Equal(t, "main", run.Spec.Branch)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 C8S Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	c8sv1alpha1 "github.com/org/c8s/pkg/apis/v1alpha1"
+	"github.com/org/c8s/pkg/controller"
+	"github.com/org/c8s/pkg/parser"
+)
+
+// TestStepWorkingDir verifies relative working directories are resolved
+// against the workspace and absolute ones are used as-is
+func TestStepWorkingDir(t *testing.T) {
+	tests := []struct {
+		workingDir string
+		expected   string
+	}{
+		{"", "/workspace"},
+		{"services/api", "/workspace/services/api"},
+		{"./web/", "/workspace/web"},
+		{"/src/app", "/src/app"},
+	}
+
+	for _, tt := range tests {
+		step := &c8sv1alpha1.PipelineStep{WorkingDir: tt.workingDir}
+		assert.Equal(t, tt.expected, controller.StepWorkingDir(step), tt.workingDir)
+	}
+}
+
+// TestCreateJobForStepWorkingDir verifies the step container runs in the
+// step's working directory
+func TestCreateJobForStepWorkingDir(t *testing.T) {
+	run := &c8sv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run-1", Namespace: "default"}}
+	step := &c8sv1alpha1.PipelineStep{
+		Name:       "test-api",
+		Image:      "golang:1.25",
+		Commands:   []string{"go test ./..."},
+		WorkingDir: "services/api",
+	}
+
+	job, err := controller.NewJobManager("https://github.com/org/repo.git").CreateJobForStep(step, run, &c8sv1alpha1.PipelineConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "/workspace/services/api", job.Spec.Template.Spec.Containers[0].WorkingDir)
+}
+
+// TestValidateWorkingDir verifies working directories leaving the workspace
+// through '..' are rejected
+func TestValidateWorkingDir(t *testing.T) {
+	spec, err := parser.ParseBytes([]byte(`version: v1alpha1
+name: mono
+steps:
+  - name: test-api
+    image: golang:1.25
+    commands: ["go test ./..."]
+    workingDir: services/api
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "services/api", spec.Steps[0].WorkingDir)
+
+	config := &c8sv1alpha1.PipelineConfig{Spec: *spec}
+	config.Spec.Repository = "https://github.com/org/repo.git"
+
+	for _, valid := range []string{"services/api", "services/../web", "/src", "/workspace/a/.."} {
+		config.Spec.Steps[0].WorkingDir = valid
+		assert.NoError(t, parser.Validate(config), valid)
+	}
+	for _, invalid := range []string{"..", "services/../../etc", "/workspace/../etc"} {
+		config.Spec.Steps[0].WorkingDir = invalid
+		err := parser.Validate(config)
+		require.Error(t, err, invalid)
+		assert.Contains(t, err.Error(), "spec.steps[0].workingDir")
+	}
+}